@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var bookCmd = &cobra.Command{
+	Use:   "book",
+	Short: "Photo book operations",
+	Long:  `Commands for working with the photo books stored in PostgreSQL.`,
+}
+
+func init() {
+	rootCmd.AddCommand(bookCmd)
+}
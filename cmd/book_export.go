@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/kozaktomas/photo-sorter/internal/config"
+	"github.com/kozaktomas/photo-sorter/internal/database/postgres"
+	"github.com/kozaktomas/photo-sorter/internal/latex"
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+	"github.com/schollz/progressbar/v3"
+	"github.com/spf13/cobra"
+)
+
+var bookExportCmd = &cobra.Command{
+	Use:   "export <book-id>",
+	Short: "Export a photo book as PDF",
+	Long: `Export a photo book as a print-ready PDF via lualatex.
+
+With --proof the book is exported as a review copy for relatives: photos are
+downloaded at low resolution (fit_720) so the file stays small enough to
+email, every page carries a diagonal watermark and its page number and page
+ID, and a ruled comments page follows each section. Each comments page has a
+blank back page and neither advances the page numbering, so notes refer to
+the printed page numbers and every book page keeps its printed side.
+
+Examples:
+  # Export the print PDF
+  photo-sorter book export 9797de58-a0ec-4330-8173-b7ce5b198f33
+
+  # Export a proof with a custom watermark
+  photo-sorter book export 9797de58-a0ec-4330-8173-b7ce5b198f33 --proof --watermark "KONCEPT"
+
+  # Export with original photos to a specific file
+  photo-sorter book export 9797de58-a0ec-4330-8173-b7ce5b198f33 --quality original -o book.pdf`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBookExport,
+}
+
+func init() {
+	bookCmd.AddCommand(bookExportCmd)
+
+	bookExportCmd.Flags().StringP("output", "o", "", "Output file (default: <book title>.pdf)")
+	bookExportCmd.Flags().String("quality", "", "Photo quality: low, medium or original (default medium)")
+	bookExportCmd.Flags().Bool("proof", false, "Export a low-resolution watermarked proof for review")
+	bookExportCmd.Flags().String("watermark", "", "Proof watermark text (default \""+latex.DefaultProofWatermark+"\")")
+	bookExportCmd.Flags().Bool("debug", false, "Draw the layout debug overlay")
+}
+
+// bookExportFilename builds the default output file name from a book title.
+func bookExportFilename(title string, proof bool) string {
+	if title == "" {
+		title = "book"
+	}
+	title = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r < ' ' {
+			return '_'
+		}
+		return r
+	}, title)
+	if proof {
+		title += "-proof"
+	}
+	return title + ".pdf"
+}
+
+// bookExportProgress returns a progress callback that prints export phases
+// and shows a progress bar while photos are downloaded.
+func bookExportProgress() func(latex.ProgressInfo) {
+	var bar *progressbar.ProgressBar
+	phase := ""
+	return func(info latex.ProgressInfo) {
+		if info.Phase != phase {
+			phase = info.Phase
+			if bar != nil {
+				_ = bar.Finish()
+				bar = nil
+				fmt.Println()
+			}
+			if phase == "downloading_photos" && info.Total > 0 {
+				bar = progressbar.NewOptions(info.Total,
+					progressbar.OptionSetDescription("Downloading photos"),
+					progressbar.OptionShowCount(),
+					progressbar.OptionFullWidth(),
+				)
+			} else {
+				fmt.Printf("%s...\n", strings.ReplaceAll(phase, "_", " "))
+			}
+		}
+		if bar != nil {
+			_ = bar.Set(info.Current)
+		}
+	}
+}
+
+func runBookExport(cmd *cobra.Command, args []string) error {
+	bookID := args[0]
+	output := mustGetString(cmd, "output")
+	proof := mustGetBool(cmd, "proof")
+	watermark := mustGetString(cmd, "watermark")
+
+	quality, err := latex.ValidatePhotoQuality(mustGetString(cmd, "quality"))
+	if err != nil {
+		return fmt.Errorf("invalid --quality: %w", err)
+	}
+	if err := latex.ValidateProofWatermark(watermark); err != nil {
+		return fmt.Errorf("invalid --watermark: %w", err)
+	}
+	if _, err := exec.LookPath("lualatex"); err != nil {
+		return errors.New("lualatex is not installed")
+	}
+
+	ctx := context.Background()
+	cfg := config.Load()
+	if cfg.Database.URL == "" {
+		return errors.New("DATABASE_URL environment variable is required")
+	}
+	if err := postgres.Initialize(&cfg.Database); err != nil {
+		return fmt.Errorf("failed to initialize PostgreSQL: %w", err)
+	}
+	bookRepo := postgres.NewBookRepository(postgres.GetGlobalPool())
+
+	book, err := bookRepo.GetBook(ctx, bookID)
+	if err != nil || book == nil {
+		return fmt.Errorf("book not found: %s", bookID)
+	}
+	if output == "" {
+		output = bookExportFilename(book.Title, proof)
+	}
+
+	pp, err := photoprism.NewPhotoPrismWithCapture(
+		cfg.PhotoPrism.URL, cfg.PhotoPrism.Username, cfg.PhotoPrism.GetPassword(), captureDir,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to connect to PhotoPrism: %w", err)
+	}
+	defer pp.Logout()
+
+	pdfData, report, err := latex.GeneratePDFWithCallbacks(ctx, pp, bookRepo, bookID, latex.ExportOptions{
+		Debug:          mustGetBool(cmd, "debug"),
+		OnProgress:     bookExportProgress(),
+		PhotoQuality:   quality,
+		Proof:          proof,
+		ProofWatermark: watermark,
+	})
+	if err != nil {
+		return fmt.Errorf("PDF generation failed: %w", err)
+	}
+	return writeBookExport(output, pdfData, report)
+}
+
+// writeBookExport writes the PDF and prints a summary with the export warnings.
+func writeBookExport(output string, pdfData []byte, report *latex.ExportReport) error {
+	if err := os.WriteFile(output, pdfData, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	fmt.Printf("\nExported %d pages to %s (%.1f MB)\n", report.PageCount, output, float64(len(pdfData))/(1024*1024))
+	for _, w := range report.Warnings {
+		fmt.Printf("  Warning: %s\n", w)
+	}
+	return nil
+}
@@ -8,9 +8,11 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/kozaktomas/photo-sorter/internal/changes"
 	"github.com/kozaktomas/photo-sorter/internal/config"
 	"github.com/kozaktomas/photo-sorter/internal/constants"
 	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/database/mariadb"
 	"github.com/kozaktomas/photo-sorter/internal/database/postgres"
 	"github.com/kozaktomas/photo-sorter/internal/facematch"
 	"github.com/kozaktomas/photo-sorter/internal/photoprism"
@@ -27,6 +29,12 @@ This command refreshes the cached marker assignments (marker UID, subject UID,
 subject name) for all faces in the database. Use this when faces have been
 assigned or unassigned directly in PhotoPrism's native UI.
 
+With --incremental only photos changed since the previous incremental sync
+are synced. Changes are read from PhotoPrism's database when
+PHOTOPRISM_DATABASE_URL is set (new, edited and archived photos and marker
+changes), otherwise from the API (new and edited photos only). The first
+incremental sync looks at all photos.
+
 Examples:
   # Run sync with default concurrency (20 workers)
   photo-sorter cache sync
@@ -34,6 +42,9 @@ Examples:
   # Limit concurrency
   photo-sorter cache sync --concurrency 5
 
+  # Only sync photos changed since the last incremental sync
+  photo-sorter cache sync --incremental
+
   # JSON output for scripting
   photo-sorter cache sync --json`,
 	RunE: runCacheSync,
@@ -44,6 +55,7 @@ func init() {
 
 	cacheSyncCmd.Flags().Int("concurrency", constants.WorkerPoolSize, "Number of parallel workers")
 	cacheSyncCmd.Flags().Bool("json", false, "Output as JSON instead of progress bar")
+	cacheSyncCmd.Flags().Bool("incremental", false, "Only sync photos changed since the last incremental sync")
 }
 
 // SyncCacheResult represents the result of a cache sync operation.
@@ -53,6 +65,7 @@ type SyncCacheResult struct {
 	FacesUpdated  int    `json:"faces_updated"`
 	PhotosDeleted int    `json:"photos_deleted"`
 	Errors        int    `json:"errors"`
+	Incremental   bool   `json:"incremental,omitempty"`
 	DurationMs    int64  `json:"duration_ms"`
 	DurationHuman string `json:"duration_human,omitempty"`
 }
@@ -81,6 +94,8 @@ func initSyncDeps(ctx context.Context, cfg *config.Config, jsonOutput bool) (*sy
 		func() database.FaceWriter { return faceRepo },
 	)
 	database.RegisterEmbeddingWriter(func() database.EmbeddingWriter { return embeddingRepo })
+	syncCursorRepo := postgres.NewSyncCursorRepository(pool)
+	database.RegisterSyncCursorStore(func() database.SyncCursorStore { return syncCursorRepo })
 
 	if !jsonOutput {
 		fmt.Println("Connecting to PhotoPrism...")
@@ -219,9 +234,88 @@ func outputSyncResult(result SyncCacheResult, jsonOutput bool) error {
 	return nil
 }
 
+// detectSyncChanges returns the photos changed since the last incremental
+// sync, reading PhotoPrism's database if PHOTOPRISM_DATABASE_URL is set.
+func detectSyncChanges(
+	ctx context.Context, cfg *config.Config, pp *photoprism.PhotoPrism, jsonOutput bool,
+) (*changes.Set, error) {
+	store, err := database.GetSyncCursorStore(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync cursor store: %w", err)
+	}
+	since, err := changes.Load(ctx, store, changes.CursorCacheSync)
+	if err != nil {
+		return nil, fmt.Errorf("incremental sync: %w", err)
+	}
+
+	source := changes.NewAPISource(pp, constants.DefaultPageSize)
+	if cfg.PhotoPrism.DatabaseURL != "" {
+		mariaPool, err := mariadb.NewPool(cfg.PhotoPrism.DatabaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to PhotoPrism database: %w", err)
+		}
+		defer mariaPool.Close()
+		source = changes.NewMariaDBSource(mariaPool)
+	}
+
+	if !jsonOutput {
+		if since.IsZero() {
+			fmt.Printf("No previous incremental sync, detecting changes of all photos via %s...\n", source.Name())
+		} else {
+			fmt.Printf("Detecting changes since %s via %s...\n",
+				since.PhotosAt.Local().Format("2006-01-02 15:04:05"), source.Name())
+		}
+	}
+	set, err := source.Changes(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect changes: %w", err)
+	}
+	return set, nil
+}
+
+// collectSyncTargets returns the cached photo UIDs to sync. In incremental
+// mode only photos changed since the last incremental sync are returned,
+// together with the detected changes.
+func collectSyncTargets(
+	ctx context.Context, cfg *config.Config, deps *syncDeps, incremental, jsonOutput bool,
+) ([]string, *changes.Set, error) {
+	if !jsonOutput {
+		fmt.Println("Fetching photo UIDs from database...")
+	}
+	photoUIDs, err := collectSyncPhotoUIDs(ctx, deps.faceW, deps.embW)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !incremental {
+		return photoUIDs, nil, nil
+	}
+	set, err := detectSyncChanges(ctx, cfg, deps.pp, jsonOutput)
+	if err != nil {
+		return nil, nil, err
+	}
+	return set.Among(photoUIDs), set, nil
+}
+
+// saveSyncCursor stores the cursor of a successful incremental sync. It does
+// nothing for a full sync (nil set).
+func saveSyncCursor(ctx context.Context, set *changes.Set) error {
+	if set == nil {
+		return nil
+	}
+	store, err := database.GetSyncCursorStore(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get sync cursor store: %w", err)
+	}
+	if err := changes.Save(ctx, store, changes.CursorCacheSync, set.Next); err != nil {
+		return fmt.Errorf("incremental sync: %w", err)
+	}
+	return nil
+}
+
 func runCacheSync(cmd *cobra.Command, args []string) error {
 	concurrency := mustGetInt(cmd, "concurrency")
 	jsonOutput := mustGetBool(cmd, "json")
+	incremental := mustGetBool(cmd, "incremental")
 
 	ctx := context.Background()
 	cfg := config.Load()
@@ -233,16 +327,18 @@ func runCacheSync(cmd *cobra.Command, args []string) error {
 	}
 	defer deps.pp.Logout()
 
-	if !jsonOutput {
-		fmt.Println("Fetching photo UIDs from database...")
-	}
-	photoUIDs, err := collectSyncPhotoUIDs(ctx, deps.faceW, deps.embW)
+	photoUIDs, set, err := collectSyncTargets(ctx, cfg, deps, incremental, jsonOutput)
 	if err != nil {
 		return err
 	}
 
 	if len(photoUIDs) == 0 {
-		return outputSyncResult(SyncCacheResult{Success: true, DurationMs: time.Since(startTime).Milliseconds()}, jsonOutput)
+		if err := saveSyncCursor(ctx, set); err != nil {
+			return err
+		}
+		return outputSyncResult(SyncCacheResult{
+			Success: true, Incremental: incremental, DurationMs: time.Since(startTime).Milliseconds(),
+		}, jsonOutput)
 	}
 
 	if !jsonOutput {
@@ -257,12 +353,20 @@ func runCacheSync(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
+	// Photos that failed are retried by the next incremental sync.
+	if errorCount == 0 {
+		if err := saveSyncCursor(ctx, set); err != nil {
+			return err
+		}
+	}
+
 	return outputSyncResult(SyncCacheResult{
 		Success:       true,
 		PhotosScanned: len(photoUIDs),
 		FacesUpdated:  int(facesUpdated),
 		PhotosDeleted: int(photosDeleted),
 		Errors:        int(errorCount),
+		Incremental:   incremental,
 		DurationMs:    time.Since(startTime).Milliseconds(),
 		DurationHuman: formatDuration(time.Since(startTime)),
 	}, jsonOutput)
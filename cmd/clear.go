@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -31,25 +32,13 @@ func init() {
 	clearCmd.Flags().Bool("yes", false, "Skip confirmation prompt")
 }
 
-// fetchAllAlbumPhotos retrieves all photos from an album using pagination.
-func fetchAllAlbumPhotos(pp *photoprism.PhotoPrism, albumUID string, pageSize int) ([]photoprism.Photo, error) {
-	var allPhotos []photoprism.Photo
-	offset := 0
-	for {
-		photos, err := pp.GetAlbumPhotos(albumUID, pageSize, offset)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get photos: %w", err)
-		}
-		if len(photos) == 0 {
-			break
-		}
-		allPhotos = append(allPhotos, photos...)
-		if len(photos) < pageSize {
-			break
-		}
-		offset += len(photos)
+// fetchAlbumPhotoUIDs retrieves the UIDs of all photos in an album, page by page.
+func fetchAlbumPhotoUIDs(ctx context.Context, pp *photoprism.PhotoPrism, albumUID string) ([]string, error) {
+	uids, err := photoprism.CollectPhotoUIDs(pp.IterateAlbumPhotos(ctx, albumUID, photoprism.IterateOptions{}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get photos: %w", err)
 	}
-	return allPhotos, nil
+	return uids, nil
 }
 
 func confirmAction(prompt string) bool {
@@ -82,28 +71,26 @@ func runClear(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Album: %s\n", album.Title)
 
 	fmt.Println("Fetching photos...")
-	allPhotos, err := fetchAllAlbumPhotos(pp, albumUID, 100)
+	ctx, cancel := setupCancellableContext()
+	defer cancel()
+	pp = pp.WithContext(ctx)
+	photoUIDs, err := fetchAlbumPhotoUIDs(ctx, pp, albumUID)
 	if err != nil {
 		return err
 	}
 
-	if len(allPhotos) == 0 {
+	if len(photoUIDs) == 0 {
 		fmt.Println("Album is already empty.")
 		return nil
 	}
 
-	fmt.Printf("Photos: %d\n", len(allPhotos))
+	fmt.Printf("Photos: %d\n", len(photoUIDs))
 
-	if !skipConfirm && !confirmAction(fmt.Sprintf("\nRemove all %d photo(s) from this album? [y/N]: ", len(allPhotos))) {
+	if !skipConfirm && !confirmAction(fmt.Sprintf("\nRemove all %d photo(s) from this album? [y/N]: ", len(photoUIDs))) {
 		fmt.Println("Cancelled.")
 		return nil
 	}
 
-	photoUIDs := make([]string, len(allPhotos))
-	for i := range allPhotos {
-		photoUIDs[i] = allPhotos[i].UID
-	}
-
 	fmt.Printf("Removing %d photo(s) from album...\n", len(photoUIDs))
 	if err := pp.RemovePhotosFromAlbum(albumUID, photoUIDs); err != nil {
 		return fmt.Errorf("failed to remove photos: %w", err)
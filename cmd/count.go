@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/kozaktomas/photo-sorter/internal/config"
@@ -40,21 +41,11 @@ func runCount(cmd *cobra.Command, args []string) error {
 
 	// Count photos by fetching them with pagination.
 	totalPhotos := 0
-	batchSize := 1000
-	offset := 0
-
-	for {
-		photos, err := pp.GetAlbumPhotos(albumUID, batchSize, offset)
+	for photos, err := range pp.IterateAlbumPhotos(context.Background(), albumUID, photoprism.IterateOptions{}) {
 		if err != nil {
 			return fmt.Errorf("failed to get album photos: %w", err)
 		}
-
 		totalPhotos += len(photos)
-
-		if len(photos) < batchSize {
-			break
-		}
-		offset += batchSize
 	}
 
 	fmt.Printf("Album: %s\n", album.Title)
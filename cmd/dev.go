@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var devCmd = &cobra.Command{
+	Use:   "dev",
+	Short: "Development tools",
+	Long:  `Tools for developing and testing photo-sorter without a live PhotoPrism instance.`,
+}
+
+func init() {
+	rootCmd.AddCommand(devCmd)
+}
+
+// serveDevServer serves handler on addr until interrupted, printing the
+// environment variable that points the other commands at it.
+func serveDevServer(name, addr string, handler http.Handler, envVar string) error {
+	httpServer := &http.Server{
+		Addr:         addr,
+		Handler:      handler,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 5 * time.Minute,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	ctx, cancel := setupCancellableContext()
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer shutdownCancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			fmt.Printf("Error during HTTP shutdown: %v\n", err)
+		}
+	}()
+
+	fmt.Printf("%s listening on %s\n", name, addr)
+	fmt.Printf("Set %s=%s to use it\n", envVar, fakeServerURL(addr))
+	fmt.Println("Press Ctrl+C to stop")
+
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("starting server: %w", err)
+	}
+	return nil
+}
+
+// fakeServerURL returns the URL clients should use for a listen address.
+func fakeServerURL(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "http://" + addr
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"github.com/kozaktomas/photo-sorter/internal/fingerprint/fake"
+	"github.com/spf13/cobra"
+)
+
+var devFakeEmbeddingsCmd = &cobra.Command{
+	Use:   "fake-embeddings",
+	Short: "Run a deterministic embedding server stand-in",
+	Long: `Run a stand-in for the embedding server used for image, text and face embeddings.
+
+No model is loaded. Image embeddings are a fixed random projection of a
+downscaled copy of the image (similar images get similar vectors), text
+embeddings are derived from the words of the text, and faces are synthetic
+boxes placed from a hash of the image. The same input always produces the
+same output, so processing, era computation and similarity search can be
+exercised offline.
+
+Point the other commands at it with EMBEDDING_URL, e.g.:
+  photo-sorter dev fake-embeddings
+  EMBEDDING_URL=http://localhost:8000 photo-sorter serve`,
+	RunE: runDevFakeEmbeddings,
+}
+
+func init() {
+	devCmd.AddCommand(devFakeEmbeddingsCmd)
+
+	devFakeEmbeddingsCmd.Flags().String("addr", ":8000", "Address to listen on")
+	devFakeEmbeddingsCmd.Flags().Int("image-dim", fake.DefaultImageDim, "Dimension of image and text embeddings")
+	devFakeEmbeddingsCmd.Flags().Int("face-dim", fake.DefaultFaceDim, "Dimension of face embeddings")
+	devFakeEmbeddingsCmd.Flags().Int(
+		"max-faces", fake.DefaultMaxFaces, "Maximum synthetic faces per image (0 disables faces)",
+	)
+}
+
+func runDevFakeEmbeddings(cmd *cobra.Command, args []string) error {
+	maxFaces := mustGetInt(cmd, "max-faces")
+	if maxFaces == 0 {
+		maxFaces = -1 // Options treats 0 as "use the default"
+	}
+	server := fake.New(fake.Options{
+		ImageDim: mustGetInt(cmd, "image-dim"),
+		FaceDim:  mustGetInt(cmd, "face-dim"),
+		MaxFaces: maxFaces,
+	})
+	return serveDevServer("Fake embedding server", mustGetString(cmd, "addr"), server.Handler(), "EMBEDDING_URL")
+}
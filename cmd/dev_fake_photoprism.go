@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kozaktomas/photo-sorter/internal/photoprism/fake"
+	"github.com/spf13/cobra"
+)
+
+var devFakePhotoPrismCmd = &cobra.Command{
+	Use:   "fake-photoprism",
+	Short: "Run an in-memory PhotoPrism stand-in",
+	Long: `Run an in-memory stand-in for the PhotoPrism API.
+
+The server is seeded from --dir: images become photos (images in a
+subdirectory are also added to an album named after it) and JSON responses
+recorded with --capture are replayed as albums, photos, labels and subjects.
+Changes made through the API (labels, markers, albums, uploads) are kept in
+memory until the server stops.
+
+Point the other commands at it with PHOTOPRISM_URL, e.g.:
+  photo-sorter dev fake-photoprism --dir ./fixtures
+  PHOTOPRISM_URL=http://localhost:2342 photo-sorter serve`,
+	RunE: runDevFakePhotoPrism,
+}
+
+func init() {
+	devCmd.AddCommand(devFakePhotoPrismCmd)
+
+	devFakePhotoPrismCmd.Flags().String("dir", "", "Directory with images and captured JSON responses")
+	devFakePhotoPrismCmd.Flags().String("addr", ":2342", "Address to listen on")
+	devFakePhotoPrismCmd.Flags().String("username", "", "Accepted username (empty accepts any credentials)")
+	devFakePhotoPrismCmd.Flags().String("password", "", "Accepted password")
+}
+
+func runDevFakePhotoPrism(cmd *cobra.Command, args []string) error {
+	dir := mustGetString(cmd, "dir")
+	addr := mustGetString(cmd, "addr")
+
+	server := fake.New(fake.Options{
+		Username: mustGetString(cmd, "username"),
+		Password: mustGetString(cmd, "password"),
+	})
+	if dir != "" {
+		if err := server.LoadDir(dir); err != nil {
+			return fmt.Errorf("loading fixtures: %w", err)
+		}
+	}
+
+	return serveDevServer("Fake PhotoPrism", addr, server.Handler(), "PHOTOPRISM_URL")
+}
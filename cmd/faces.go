@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var facesCmd = &cobra.Command{
+	Use:   "faces",
+	Short: "Face operations across the library",
+	Long:  `Commands for working with the detected faces stored in PostgreSQL.`,
+}
+
+func init() {
+	rootCmd.AddCommand(facesCmd)
+}
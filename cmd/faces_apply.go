@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kozaktomas/photo-sorter/internal/config"
+	"github.com/kozaktomas/photo-sorter/internal/database/postgres"
+	"github.com/kozaktomas/photo-sorter/internal/facecluster"
+	"github.com/kozaktomas/photo-sorter/internal/facematch"
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+	"github.com/spf13/cobra"
+)
+
+var facesApplyCmd = &cobra.Command{
+	Use:   "apply <person-name>",
+	Short: "Assign a person to many faces at once",
+	Long: `Assign a person to a list of faces, e.g. all faces of a cluster found by
+"faces cluster".
+
+Faces are given as --face photoUID:faceIndex (repeatable) and/or as --cluster N,
+the number of a cluster printed by "faces cluster". Clusters are recomputed, so
+pass the same --threshold and --min-size as to "faces cluster".
+
+For every face, a marker is created in PhotoPrism where the face has none and
+the person is assigned to the existing marker otherwise. Faces are applied in
+parallel; failures are reported per face and do not stop the others. Faces
+already assigned to a different person are left untouched.
+
+Examples:
+  # Name the largest cluster
+  photo-sorter faces apply "Jan Novák" --cluster 1
+
+  # Name individual faces
+  photo-sorter faces apply "Jan Novák" --face pq8abc123:0 --face pq8def456:2
+
+  # Preview which faces would be named
+  photo-sorter faces apply "Jan Novák" --cluster 3 --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFacesApply,
+}
+
+func init() {
+	facesCmd.AddCommand(facesApplyCmd)
+
+	facesApplyCmd.Flags().StringSlice("face", nil, "Face as photoUID:faceIndex (repeatable)")
+	facesApplyCmd.Flags().Int("cluster", 0, "Number of a cluster printed by \"faces cluster\"")
+	facesApplyCmd.Flags().Float64("threshold", facecluster.DefaultThreshold,
+		"Clustering threshold, as passed to \"faces cluster\"")
+	facesApplyCmd.Flags().Int("min-size", facecluster.DefaultMinSize,
+		"Minimum cluster size, as passed to \"faces cluster\"")
+	facesApplyCmd.Flags().Int("concurrency", facematch.DefaultApplyConcurrency, "Number of faces applied in parallel")
+	facesApplyCmd.Flags().Bool("dry-run", false, "Show the faces without applying changes")
+	facesApplyCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+// FacesApplyOutput represents the JSON output structure.
+type FacesApplyOutput struct {
+	Person  string                  `json:"person"`
+	DryRun  bool                    `json:"dry_run"`
+	Faces   []facematch.FaceRef     `json:"faces"`
+	Results []facematch.ApplyResult `json:"results,omitempty"`
+	Summary *facematch.ApplySummary `json:"summary,omitempty"`
+}
+
+// parseFaceRefs parses faces given as photoUID:faceIndex.
+func parseFaceRefs(values []string) ([]facematch.FaceRef, error) {
+	refs := make([]facematch.FaceRef, 0, len(values))
+	for _, v := range values {
+		photoUID, index, ok := strings.Cut(v, ":")
+		if !ok || photoUID == "" {
+			return nil, fmt.Errorf("invalid face %q, expected photoUID:faceIndex", v)
+		}
+		faceIndex, err := strconv.Atoi(index)
+		if err != nil || faceIndex < 0 {
+			return nil, fmt.Errorf("invalid face index in %q", v)
+		}
+		refs = append(refs, facematch.FaceRef{PhotoUID: photoUID, FaceIndex: faceIndex})
+	}
+	return refs, nil
+}
+
+// clusterFaceRefs recomputes the clusters of unassigned faces and returns the
+// faces of the cluster with the given 1-based number.
+func clusterFaceRefs(
+	ctx context.Context, faceRepo *postgres.FaceRepository, number int, opts facecluster.Options,
+) ([]facematch.FaceRef, error) {
+	unassigned, err := faceRepo.GetUnassignedFaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unassigned faces: %w", err)
+	}
+	result, err := facecluster.Run(ctx, facecluster.FilterFaces(unassigned), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cluster faces: %w", err)
+	}
+	if number > len(result.Clusters) {
+		return nil, fmt.Errorf("cluster %d not found, there are %d clusters", number, len(result.Clusters))
+	}
+	faces := result.Clusters[number-1].Faces
+	refs := make([]facematch.FaceRef, len(faces))
+	for i := range faces {
+		refs[i] = facematch.FaceRef{PhotoUID: faces[i].PhotoUID, FaceIndex: faces[i].FaceIndex}
+	}
+	return refs, nil
+}
+
+// collectFaceRefs returns the faces selected by the --face and --cluster flags.
+func collectFaceRefs(
+	ctx context.Context, cmd *cobra.Command, faceRepo *postgres.FaceRepository,
+) ([]facematch.FaceRef, error) {
+	refs, err := parseFaceRefs(mustGetStringSlice(cmd, "face"))
+	if err != nil {
+		return nil, err
+	}
+	cluster := mustGetInt(cmd, "cluster")
+	if cluster < 0 {
+		return nil, errors.New("--cluster must be positive")
+	}
+	if cluster > 0 {
+		opts := facecluster.Options{
+			Threshold: mustGetFloat64(cmd, "threshold"),
+			MinSize:   mustGetInt(cmd, "min-size"),
+		}.WithDefaults()
+		clusterRefs, err := clusterFaceRefs(ctx, faceRepo, cluster, opts)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, clusterRefs...)
+	}
+	if len(refs) == 0 {
+		return nil, errors.New("no faces given, use --face or --cluster")
+	}
+	return refs, nil
+}
+
+// printFacesApplyResults prints the per-face results and the summary.
+func printFacesApplyResults(results []facematch.ApplyResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PHOTO\tFACE\tACTION\tRESULT")
+	fmt.Fprintln(w, "-----\t----\t------\t------")
+	for _, r := range results {
+		status := "ok"
+		switch {
+		case !r.Success:
+			status = "failed: " + r.Error
+		case r.CacheError != "":
+			status = "ok (cache not updated: " + r.CacheError + ")"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", r.PhotoUID, r.FaceIndex, r.Action, status)
+	}
+	w.Flush()
+
+	s := facematch.Summarize(results)
+	fmt.Printf("\nCreated markers: %d, assigned: %d, already assigned: %d, failed: %d\n",
+		s.CreatedMarkers, s.AssignedPeople, s.AlreadyDone, s.Failed)
+}
+
+func runFacesApply(cmd *cobra.Command, args []string) error {
+	personName := strings.TrimSpace(args[0])
+	if personName == "" {
+		return errors.New("person name must not be empty")
+	}
+	concurrency := mustGetInt(cmd, "concurrency")
+	dryRun := mustGetBool(cmd, "dry-run")
+	jsonOutput := mustGetBool(cmd, "json")
+
+	ctx := context.Background()
+	cfg := config.Load()
+	if cfg.Database.URL == "" {
+		return errors.New("DATABASE_URL environment variable is required")
+	}
+	if err := postgres.Initialize(&cfg.Database); err != nil {
+		return fmt.Errorf("failed to initialize PostgreSQL: %w", err)
+	}
+	faceRepo := postgres.NewFaceRepository(postgres.GetGlobalPool())
+
+	refs, err := collectFaceRefs(ctx, cmd, faceRepo)
+	if err != nil {
+		return err
+	}
+	out := FacesApplyOutput{Person: personName, DryRun: dryRun, Faces: refs}
+
+	if dryRun {
+		if jsonOutput {
+			return outputJSON(out)
+		}
+		fmt.Printf("Would assign %s to %d faces:\n", personName, len(refs))
+		for _, ref := range refs {
+			fmt.Printf("  %s:%d\n", ref.PhotoUID, ref.FaceIndex)
+		}
+		return nil
+	}
+
+	if !jsonOutput {
+		fmt.Println("Connecting to PhotoPrism...")
+	}
+	pp, err := photoprism.NewPhotoPrismWithCapture(
+		cfg.PhotoPrism.URL, cfg.PhotoPrism.Username, cfg.PhotoPrism.GetPassword(), captureDir,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to connect to PhotoPrism: %w", err)
+	}
+	defer pp.Logout()
+
+	if !jsonOutput {
+		fmt.Printf("Assigning %s to %d faces...\n\n", personName, len(refs))
+	}
+	out.Results = facematch.ApplyPerson(ctx, pp, faceRepo, refs, personName, concurrency)
+	if jsonOutput {
+		summary := facematch.Summarize(out.Results)
+		out.Summary = &summary
+		return outputJSON(out)
+	}
+	printFacesApplyResults(out.Results)
+	return nil
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kozaktomas/photo-sorter/internal/config"
+	"github.com/kozaktomas/photo-sorter/internal/database/postgres"
+	"github.com/kozaktomas/photo-sorter/internal/facecluster"
+	"github.com/spf13/cobra"
+)
+
+var facesClusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Group unassigned faces into clusters of likely the same person",
+	Long: `Group faces that are not assigned to a person into clusters of faces that
+likely show the same person, to discover people who have not been named yet.
+
+Faces are clustered with DBSCAN over their embeddings: faces within --threshold
+cosine distance of each other are neighbours, and a cluster grows from every
+face with at least --min-size neighbours. Faces too small for reliable matching
+are ignored. The faces of each cluster are listed closest to the cluster centre
+first, so the first faces are the most representative.
+
+Examples:
+  # List the clusters of unassigned faces, largest first
+  photo-sorter faces cluster
+
+  # Stricter clustering, only clusters with at least 5 faces
+  photo-sorter faces cluster --threshold 0.35 --min-size 5
+
+  # Show the 10 largest clusters with 8 faces each
+  photo-sorter faces cluster --limit 10 --show 8
+
+  # Output as JSON
+  photo-sorter faces cluster --json`,
+	Args: cobra.NoArgs,
+	RunE: runFacesCluster,
+}
+
+func init() {
+	facesCmd.AddCommand(facesClusterCmd)
+
+	facesClusterCmd.Flags().Float64("threshold", facecluster.DefaultThreshold,
+		"Maximum cosine distance between neighbouring faces (lower = stricter)")
+	facesClusterCmd.Flags().Int("min-size", facecluster.DefaultMinSize, "Minimum number of faces per cluster")
+	facesClusterCmd.Flags().Int("limit", 20, "Limit number of clusters (0 = all)")
+	facesClusterCmd.Flags().Int("show", 5, "Number of representative faces printed per cluster")
+	facesClusterCmd.Flags().Bool("json", false, "Output as JSON (all faces of every cluster)")
+}
+
+// ClusterFaceOutput represents a face of a cluster in the JSON output.
+type ClusterFaceOutput struct {
+	PhotoUID  string    `json:"photo_uid"`
+	FaceIndex int       `json:"face_index"`
+	Distance  float64   `json:"distance"` // cosine distance to the cluster centroid
+	BBox      []float64 `json:"bbox"`
+	FileUID   string    `json:"file_uid,omitempty"`
+	MarkerUID string    `json:"marker_uid,omitempty"`
+}
+
+// ClusterOutputItem represents a single cluster in the JSON output.
+type ClusterOutputItem struct {
+	ID    int                 `json:"id"`
+	Size  int                 `json:"size"`
+	Faces []ClusterFaceOutput `json:"faces"`
+}
+
+// ClusterOutput represents the JSON output structure.
+type ClusterOutput struct {
+	TotalFaces     int                 `json:"total_faces"`
+	ClusteredFaces int                 `json:"clustered_faces"`
+	NoiseFaces     int                 `json:"noise_faces"`
+	Clusters       []ClusterOutputItem `json:"clusters"`
+}
+
+// buildClusterOutput converts the clustering result to the JSON output.
+func buildClusterOutput(total int, result *facecluster.Result, limit int) ClusterOutput {
+	clusters := result.Clusters
+	if limit > 0 && len(clusters) > limit {
+		clusters = clusters[:limit]
+	}
+	out := ClusterOutput{
+		TotalFaces:     total,
+		ClusteredFaces: total - result.Noise,
+		NoiseFaces:     result.Noise,
+		Clusters:       make([]ClusterOutputItem, 0, len(clusters)),
+	}
+	for i := range clusters {
+		c := &clusters[i]
+		item := ClusterOutputItem{ID: i + 1, Size: len(c.Faces), Faces: make([]ClusterFaceOutput, len(c.Faces))}
+		for j := range c.Faces {
+			f := &c.Faces[j]
+			item.Faces[j] = ClusterFaceOutput{
+				PhotoUID: f.PhotoUID, FaceIndex: f.FaceIndex, Distance: c.Distances[j],
+				BBox: f.BBox, FileUID: f.FileUID, MarkerUID: f.MarkerUID,
+			}
+		}
+		out.Clusters = append(out.Clusters, item)
+	}
+	return out
+}
+
+// printClusterTable prints the clusters with their representative faces.
+func printClusterTable(out ClusterOutput, show int, cfg *config.Config) {
+	fmt.Printf("Clustered %d of %d unassigned faces into %d clusters (%d faces in no cluster)\n\n",
+		out.ClusteredFaces, out.TotalFaces, len(out.Clusters), out.NoiseFaces)
+	if len(out.Clusters) == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CLUSTER\tFACES\tREPRESENTATIVE PHOTOS")
+	fmt.Fprintln(w, "-------\t-----\t---------------------")
+	for _, c := range out.Clusters {
+		refs := make([]string, 0, show)
+		for _, f := range c.Faces[:min(show, len(c.Faces))] {
+			ref := f.PhotoUID
+			if url := cfg.PhotoPrism.PhotoURL(f.PhotoUID); url != "" {
+				ref = url
+			}
+			refs = append(refs, ref)
+		}
+		fmt.Fprintf(w, "%d\t%d\t%s\n", c.ID, c.Size, strings.Join(refs, " "))
+	}
+	w.Flush()
+}
+
+func runFacesCluster(cmd *cobra.Command, args []string) error {
+	opts := facecluster.Options{
+		Threshold: mustGetFloat64(cmd, "threshold"),
+		MinSize:   mustGetInt(cmd, "min-size"),
+	}.WithDefaults()
+	limit := mustGetInt(cmd, "limit")
+	show := mustGetInt(cmd, "show")
+	jsonOutput := mustGetBool(cmd, "json")
+
+	ctx := context.Background()
+	cfg := config.Load()
+	if cfg.Database.URL == "" {
+		return errors.New("DATABASE_URL environment variable is required")
+	}
+	if err := postgres.Initialize(&cfg.Database); err != nil {
+		return fmt.Errorf("failed to initialize PostgreSQL: %w", err)
+	}
+	faceRepo := postgres.NewFaceRepository(postgres.GetGlobalPool())
+
+	unassigned, err := faceRepo.GetUnassignedFaces(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get unassigned faces: %w", err)
+	}
+	faces := facecluster.FilterFaces(unassigned)
+	if !jsonOutput {
+		fmt.Printf("Clustering %d unassigned faces (threshold: %.2f, min size: %d)...\n",
+			len(faces), opts.Threshold, opts.MinSize)
+	}
+
+	result, err := facecluster.Run(ctx, faces, opts)
+	if err != nil {
+		return fmt.Errorf("failed to cluster faces: %w", err)
+	}
+
+	out := buildClusterOutput(len(faces), result, limit)
+	if jsonOutput {
+		return outputJSON(out)
+	}
+	printClusterTable(out, show, cfg)
+	return nil
+}
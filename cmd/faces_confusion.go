@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/kozaktomas/photo-sorter/internal/config"
+	"github.com/kozaktomas/photo-sorter/internal/database/postgres"
+	"github.com/kozaktomas/photo-sorter/internal/faceconfusion"
+	"github.com/spf13/cobra"
+)
+
+var facesConfusionCmd = &cobra.Command{
+	Use:   "confusion",
+	Short: "Report pairs of people the face model confuses",
+	Long: `Report pairs of people whose faces the face model confuses, such as siblings
+or a parent and child, to find and fix mis-assigned faces systematically.
+
+Every person gets a centroid, the mean embedding of their assigned faces. A face
+is confused when it is closer to the centroid of another person than to the
+centroid of its own person (computed without the face itself). Pairs of people
+are listed with the most confused faces first, each with example photos; the
+examples are the faces most clearly closer to the other person.
+
+Examples:
+  # List the most confused pairs of people
+  photo-sorter faces confusion
+
+  # Only faces clearly closer to the other person
+  photo-sorter faces confusion --margin 0.05
+
+  # Output as JSON
+  photo-sorter faces confusion --json`,
+	Args: cobra.NoArgs,
+	RunE: runFacesConfusion,
+}
+
+func init() {
+	facesCmd.AddCommand(facesConfusionCmd)
+
+	facesConfusionCmd.Flags().Int("min-faces", faceconfusion.DefaultMinFaces, "Minimum number of faces per person")
+	facesConfusionCmd.Flags().Float64("margin", 0,
+		"How much closer the other person's centroid must be (cosine distance)")
+	facesConfusionCmd.Flags().Int("limit", 20, "Limit number of pairs (0 = all)")
+	facesConfusionCmd.Flags().Int("examples", 3, "Number of example photos per pair")
+	facesConfusionCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+// ConfusionExampleOutput represents an example face in the JSON output.
+type ConfusionExampleOutput struct {
+	PhotoUID      string  `json:"photo_uid"`
+	FaceIndex     int     `json:"face_index"`
+	AssignedTo    string  `json:"assigned_to"`
+	ConfusedWith  string  `json:"confused_with"`
+	OwnDistance   float64 `json:"own_distance"`
+	OtherDistance float64 `json:"other_distance"`
+	MarkerUID     string  `json:"marker_uid,omitempty"`
+}
+
+// ConfusionPairOutput represents a confused pair of people in the JSON output.
+type ConfusionPairOutput struct {
+	PersonA          string                   `json:"person_a"`
+	PersonB          string                   `json:"person_b"`
+	AConfusedAsB     int                      `json:"a_confused_as_b"`
+	BConfusedAsA     int                      `json:"b_confused_as_a"`
+	CentroidDistance float64                  `json:"centroid_distance"`
+	Examples         []ConfusionExampleOutput `json:"examples"`
+}
+
+// ConfusionOutput represents the JSON output structure.
+type ConfusionOutput struct {
+	People        int                   `json:"people"`
+	Faces         int                   `json:"faces"`
+	ConfusedFaces int                   `json:"confused_faces"`
+	Pairs         []ConfusionPairOutput `json:"pairs"`
+}
+
+// buildConfusionOutput converts the confusion report to the JSON output.
+func buildConfusionOutput(report *faceconfusion.Report, limit int) ConfusionOutput {
+	pairs := report.Pairs
+	if limit > 0 && len(pairs) > limit {
+		pairs = pairs[:limit]
+	}
+	out := ConfusionOutput{
+		People:        report.People,
+		Faces:         report.Faces,
+		ConfusedFaces: report.ConfusedFaces,
+		Pairs:         make([]ConfusionPairOutput, 0, len(pairs)),
+	}
+	for i := range pairs {
+		p := &pairs[i]
+		item := ConfusionPairOutput{
+			PersonA: p.PersonA, PersonB: p.PersonB,
+			AConfusedAsB: p.AConfusedAsB, BConfusedAsA: p.BConfusedAsA,
+			CentroidDistance: p.CentroidDistance,
+			Examples:         make([]ConfusionExampleOutput, len(p.Examples)),
+		}
+		for j := range p.Examples {
+			ex := &p.Examples[j]
+			item.Examples[j] = ConfusionExampleOutput{
+				PhotoUID: ex.Face.PhotoUID, FaceIndex: ex.Face.FaceIndex,
+				AssignedTo: ex.AssignedTo, ConfusedWith: ex.ConfusedWith,
+				OwnDistance: ex.OwnDistance, OtherDistance: ex.OtherDistance,
+				MarkerUID: ex.Face.MarkerUID,
+			}
+		}
+		out.Pairs = append(out.Pairs, item)
+	}
+	return out
+}
+
+// printConfusionTable prints the confused pairs with their example photos.
+func printConfusionTable(out ConfusionOutput, cfg *config.Config) {
+	fmt.Printf("%d of %d faces of %d people are closer to another person\n\n",
+		out.ConfusedFaces, out.Faces, out.People)
+	if len(out.Pairs) == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PERSON A\tPERSON B\tA AS B\tB AS A\tCENTROID DIST")
+	fmt.Fprintln(w, "--------\t--------\t------\t------\t-------------")
+	for _, p := range out.Pairs {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%.3f\n",
+			p.PersonA, p.PersonB, p.AConfusedAsB, p.BConfusedAsA, p.CentroidDistance)
+	}
+	w.Flush()
+
+	fmt.Println("\nExamples:")
+	for _, p := range out.Pairs {
+		fmt.Printf("\n%s / %s\n", p.PersonA, p.PersonB)
+		for _, ex := range p.Examples {
+			ref := ex.PhotoUID
+			if url := cfg.PhotoPrism.PhotoURL(ex.PhotoUID); url != "" {
+				ref = url
+			}
+			fmt.Printf("  %s face %d: assigned to %s (%.3f), closer to %s (%.3f)\n",
+				ref, ex.FaceIndex, ex.AssignedTo, ex.OwnDistance, ex.ConfusedWith, ex.OtherDistance)
+		}
+	}
+}
+
+func runFacesConfusion(cmd *cobra.Command, args []string) error {
+	opts := faceconfusion.Options{
+		MinFaces: mustGetInt(cmd, "min-faces"),
+		Margin:   mustGetFloat64(cmd, "margin"),
+		Examples: mustGetInt(cmd, "examples"),
+	}
+	limit := mustGetInt(cmd, "limit")
+	jsonOutput := mustGetBool(cmd, "json")
+
+	ctx := context.Background()
+	cfg := config.Load()
+	if cfg.Database.URL == "" {
+		return errors.New("DATABASE_URL environment variable is required")
+	}
+	if err := postgres.Initialize(&cfg.Database); err != nil {
+		return fmt.Errorf("failed to initialize PostgreSQL: %w", err)
+	}
+	faceRepo := postgres.NewFaceRepository(postgres.GetGlobalPool())
+
+	faces, err := faceRepo.GetAssignedFaces(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get assigned faces: %w", err)
+	}
+	if !jsonOutput {
+		fmt.Printf("Analyzing %d assigned faces...\n", len(faces))
+	}
+
+	report, err := faceconfusion.Analyze(ctx, faces, opts)
+	if err != nil {
+		return fmt.Errorf("failed to compute confusion report: %w", err)
+	}
+
+	out := buildConfusionOutput(report, limit)
+	if jsonOutput {
+		return outputJSON(out)
+	}
+	printConfusionTable(out, cfg)
+	return nil
+}
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kozaktomas/photo-sorter/internal/config"
+	"github.com/kozaktomas/photo-sorter/internal/constants"
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/database/postgres"
+	"github.com/kozaktomas/photo-sorter/internal/facequality"
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+	"github.com/schollz/progressbar/v3"
+	"github.com/spf13/cobra"
+)
+
+var facesQualityCmd = &cobra.Command{
+	Use:   "quality",
+	Short: "Compute quality scores of stored faces",
+	Long: `Compute the quality score of faces stored before quality scoring existed.
+
+The quality score (0-1) combines the sharpness of the face crop, the face size,
+the detection score and, when the embedding server sends facial landmarks, how
+frontal the face is. New faces are scored when they are processed; this command
+downloads the photos of faces without a score and scores them. Landmarks are not
+stored, so backfilled scores leave out the pose.
+
+Scores weight person centroids (outliers, age-aware matching) and can be used
+to skip low quality faces with --min-quality in "photo match" and min_quality
+in the match API. The server keeps faces in its in-memory index: when
+HNSW_INDEX_PATH is set, this command removes the cached index so a stopped
+server rebuilds it with the new scores on its next start. A running server
+picks them up after "Rebuild Index" on the Process page.
+
+Examples:
+  # Score all faces without a score
+  photo-sorter faces quality
+
+  # Recompute the scores of all faces
+  photo-sorter faces quality --all
+
+  # Output as JSON
+  photo-sorter faces quality --json`,
+	Args: cobra.NoArgs,
+	RunE: runFacesQuality,
+}
+
+func init() {
+	facesCmd.AddCommand(facesQualityCmd)
+
+	facesQualityCmd.Flags().Bool("all", false, "Recompute the scores of all faces, not only missing ones")
+	facesQualityCmd.Flags().Int("concurrency", constants.WorkerPoolSize, "Number of photos scored in parallel")
+	facesQualityCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+// QualityBucket counts the scored faces within a range of quality.
+type QualityBucket struct {
+	Range string `json:"range"`
+	Faces int    `json:"faces"`
+}
+
+// FacesQualityOutput represents the JSON output structure.
+type FacesQualityOutput struct {
+	Photos       int             `json:"photos"`
+	Faces        int             `json:"faces"`
+	Errors       int             `json:"errors"`
+	Distribution []QualityBucket `json:"distribution"`
+}
+
+// qualityBuckets is the number of equal-width buckets of the distribution.
+const qualityBuckets = 5
+
+// facesToScore groups the faces that need a score by photo.
+func facesToScore(faces []database.StoredFace, all bool) map[string][]database.StoredFace {
+	byPhoto := make(map[string][]database.StoredFace)
+	for i := range faces {
+		if all || faces[i].Quality <= 0 {
+			byPhoto[faces[i].PhotoUID] = append(byPhoto[faces[i].PhotoUID], faces[i])
+		}
+	}
+	return byPhoto
+}
+
+// scorePhotoFaces downloads a photo, scores its faces and stores the scores.
+func scorePhotoFaces(
+	ctx context.Context, pp *photoprism.PhotoPrism, faceRepo database.FaceWriter,
+	photoUID string, faces []database.StoredFace,
+) ([]float64, error) {
+	imageData, _, err := pp.GetPhotoDownload(photoUID)
+	if err != nil {
+		return nil, fmt.Errorf("download photo %s: %w", photoUID, err)
+	}
+	img := facequality.DecodeImage(imageData)
+
+	scores := make([]float64, 0, len(faces))
+	for i := range faces {
+		f := &faces[i]
+		q := facequality.Score(img, facequality.Face{BBox: f.BBox, DetScore: f.DetScore})
+		if q <= 0 {
+			continue
+		}
+		if err := faceRepo.UpdateFaceQuality(ctx, photoUID, f.FaceIndex, q); err != nil {
+			return scores, fmt.Errorf("update face %s/%d: %w", photoUID, f.FaceIndex, err)
+		}
+		scores = append(scores, q)
+	}
+	return scores, nil
+}
+
+// scoreAllPhotos scores the faces of all photos concurrently and returns the
+// distribution of the scores and the number of photos that failed.
+func scoreAllPhotos(
+	ctx context.Context, pp *photoprism.PhotoPrism, faceRepo database.FaceWriter,
+	byPhoto map[string][]database.StoredFace, concurrency int, bar *progressbar.ProgressBar,
+) ([qualityBuckets]int, int64) {
+	var mu sync.Mutex
+	var buckets [qualityBuckets]int
+	var errorCount int64
+	sem := make(chan struct{}, max(concurrency, 1))
+	var wg sync.WaitGroup
+
+	for photoUID, faces := range byPhoto {
+		wg.Add(1)
+		go func(uid string, faces []database.StoredFace) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			scores, err := scorePhotoFaces(ctx, pp, faceRepo, uid, faces)
+			if err != nil {
+				atomic.AddInt64(&errorCount, 1)
+			}
+			mu.Lock()
+			for _, q := range scores {
+				buckets[min(int(q*qualityBuckets), qualityBuckets-1)]++
+			}
+			mu.Unlock()
+
+			if bar != nil {
+				bar.Add(1)
+			}
+		}(photoUID, faces)
+	}
+
+	wg.Wait()
+	return buckets, errorCount
+}
+
+// newQualityProgressBar creates a progress bar for face scoring, or nil if JSON output.
+func newQualityProgressBar(count int, jsonOutput bool) *progressbar.ProgressBar {
+	if jsonOutput {
+		return nil
+	}
+	return progressbar.NewOptions(count,
+		progressbar.OptionSetDescription("Scoring faces"),
+		progressbar.OptionShowCount(),
+		progressbar.OptionSetItsString("photos"),
+		progressbar.OptionShowElapsedTimeOnFinish(),
+		progressbar.OptionFullWidth(),
+	)
+}
+
+// buildQualityOutput converts the score distribution to the JSON output.
+func buildQualityOutput(photos int, buckets [qualityBuckets]int, errorCount int64) FacesQualityOutput {
+	out := FacesQualityOutput{Photos: photos, Errors: int(errorCount)}
+	for i, n := range buckets {
+		out.Faces += n
+		out.Distribution = append(out.Distribution, QualityBucket{
+			Range: fmt.Sprintf("%.1f-%.1f", float64(i)/qualityBuckets, float64(i+1)/qualityBuckets),
+			Faces: n,
+		})
+	}
+	return out
+}
+
+// printQualityOutput prints the number of scored faces and their distribution.
+func printQualityOutput(out FacesQualityOutput) {
+	fmt.Printf("\nScored %d faces in %d photos", out.Faces, out.Photos)
+	if out.Errors > 0 {
+		fmt.Printf(" (%d photos failed)", out.Errors)
+	}
+	fmt.Println()
+	fmt.Println("\nQuality distribution:")
+	for _, b := range out.Distribution {
+		fmt.Printf("  %s  %d\n", b.Range, b.Faces)
+	}
+}
+
+func runFacesQuality(cmd *cobra.Command, args []string) error {
+	all := mustGetBool(cmd, "all")
+	concurrency := mustGetInt(cmd, "concurrency")
+	jsonOutput := mustGetBool(cmd, "json")
+
+	ctx := context.Background()
+	cfg := config.Load()
+	if cfg.Database.URL == "" {
+		return errors.New("DATABASE_URL environment variable is required")
+	}
+	if err := postgres.Initialize(&cfg.Database); err != nil {
+		return fmt.Errorf("failed to initialize PostgreSQL: %w", err)
+	}
+	faceRepo := postgres.NewFaceRepository(postgres.GetGlobalPool())
+
+	faces, err := faceRepo.GetAllFaces(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get faces: %w", err)
+	}
+	byPhoto := facesToScore(faces, all)
+	if len(byPhoto) == 0 {
+		if jsonOutput {
+			return outputJSON(buildQualityOutput(0, [qualityBuckets]int{}, 0))
+		}
+		fmt.Println("All faces already have a quality score")
+		return nil
+	}
+
+	pp, err := photoprism.NewPhotoPrismWithCapture(
+		cfg.PhotoPrism.URL, cfg.PhotoPrism.Username, cfg.PhotoPrism.GetPassword(), captureDir,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to connect to PhotoPrism: %w", err)
+	}
+	defer pp.Logout()
+
+	if !jsonOutput {
+		fmt.Printf("Scoring faces of %d photos...\n", len(byPhoto))
+	}
+	bar := newQualityProgressBar(len(byPhoto), jsonOutput)
+	buckets, errorCount := scoreAllPhotos(ctx, pp, faceRepo, byPhoto, concurrency, bar)
+
+	out := buildQualityOutput(len(byPhoto), buckets, errorCount)
+	if out.Faces > 0 {
+		if err := invalidateFaceIndex(cfg.Database.HNSWIndexPath, jsonOutput); err != nil {
+			return err
+		}
+	}
+	if jsonOutput {
+		return outputJSON(out)
+	}
+	printQualityOutput(out)
+	return nil
+}
+
+// invalidateFaceIndex removes the cached face index, whose face metadata
+// holds the old scores, so it is rebuilt from the database.
+func invalidateFaceIndex(indexPath string, quiet bool) error {
+	if indexPath == "" {
+		return nil
+	}
+	if err := database.RemoveFaceIndexFiles(indexPath); err != nil {
+		return fmt.Errorf("failed to invalidate face index: %w", err)
+	}
+	if !quiet {
+		fmt.Printf("Removed the cached face index %s; it is rebuilt with the new scores\n", indexPath)
+	}
+	return nil
+}
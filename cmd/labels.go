@@ -33,9 +33,28 @@ Example:
 	RunE: runLabelsDelete,
 }
 
+var labelsMergeCmd = &cobra.Command{
+	Use:   "merge <from-uid>... --into <uid>",
+	Short: "Merge labels into another label",
+	Long: `Move every photo from one or more labels to a target label and delete
+the merged labels.
+
+Each photo gets the target label with the highest uncertainty of the merged
+labels it carried; the merged labels are then removed from the photo. The
+merged labels are only deleted when every photo was rewritten, so a failed
+merge can simply be re-run.
+
+Examples:
+  photo-sorter labels merge lqb0y3b13vqo0gjx --into lqb0y3b13vqo0gjy --dry-run
+  photo-sorter labels merge lqb0y3b13vqo0gjx lqb0y3b13vqo0gjz --into lqb0y3b13vqo0gjy`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runLabelsMerge,
+}
+
 func init() {
 	rootCmd.AddCommand(labelsCmd)
 	labelsCmd.AddCommand(labelsDeleteCmd)
+	labelsCmd.AddCommand(labelsMergeCmd)
 
 	// List flags.
 	labelsCmd.Flags().Int("count", 1000, "Maximum number of labels to retrieve")
@@ -45,6 +64,12 @@ func init() {
 
 	// Delete flags.
 	labelsDeleteCmd.Flags().Bool("yes", false, "Skip confirmation prompt")
+
+	// Merge flags.
+	labelsMergeCmd.Flags().String("into", "", "UID of the label to merge into (required)")
+	labelsMergeCmd.Flags().Bool("dry-run", false, "Only list the photos that would be relabeled")
+	labelsMergeCmd.Flags().Bool("yes", false, "Skip confirmation prompt")
+	_ = labelsMergeCmd.MarkFlagRequired("into")
 }
 
 func runLabelsList(cmd *cobra.Command, args []string) error {
@@ -160,6 +185,66 @@ func runLabelsDelete(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runLabelsMerge(cmd *cobra.Command, args []string) error {
+	cfg := config.Load()
+	into := mustGetString(cmd, "into")
+	dryRun := mustGetBool(cmd, "dry-run")
+	skipConfirm := mustGetBool(cmd, "yes")
+
+	pp, err := photoprism.NewPhotoPrismWithCapture(
+		cfg.PhotoPrism.URL, cfg.PhotoPrism.Username, cfg.PhotoPrism.GetPassword(), captureDir,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to connect to PhotoPrism: %w", err)
+	}
+	defer pp.Logout()
+
+	// A dry run first, to show what will happen and ask for confirmation.
+	preview, err := pp.MergeLabels(args, into, photoprism.MergeLabelsOptions{DryRun: true})
+	if err != nil {
+		return fmt.Errorf("merge failed: %w", err)
+	}
+	printLabelsMergePlan(preview)
+	if dryRun {
+		fmt.Println("\nDry run: no changes applied.")
+		return nil
+	}
+	if !skipConfirm && !confirmAction(fmt.Sprintf("\nMerge %d label(s) into %s? [y/N]: ",
+		len(preview.From), preview.Into.Name)) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	result, err := pp.MergeLabels(args, into, photoprism.MergeLabelsOptions{
+		OnProgress: func(done, total int, photoUID string) {
+			fmt.Printf("[%d/%d] %s\n", done, total, photoUID)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("merge failed: %w", err)
+	}
+
+	fmt.Printf("\nRelabeled %d photo(s).\n", result.UpdatedCount)
+	if len(result.Errors) > 0 {
+		fmt.Printf("\nErrors: %d\n", len(result.Errors))
+		for _, e := range result.Errors {
+			fmt.Printf("  - %s\n", e)
+		}
+		return fmt.Errorf("%d photos could not be relabeled, labels were not deleted", len(result.Errors))
+	}
+	fmt.Printf("Deleted %d label(s).\n", len(result.From))
+	return nil
+}
+
+func printLabelsMergePlan(plan *photoprism.MergeLabelsResult) {
+	fmt.Println("Labels to merge:")
+	for _, l := range plan.From {
+		fmt.Printf("  - %s (%s, %d photos)\n", l.Name, l.UID, l.PhotoCount)
+	}
+	fmt.Printf("Into: %s (%s)\n", plan.Into.Name, plan.Into.UID)
+	fmt.Printf("Photos to relabel: %d\n", len(plan.PhotoUIDs))
+}
+
 func confirmLabelsDelete(count int) bool {
 	fmt.Printf("\nDelete %d label(s)? [y/N]: ", count)
 	reader := bufio.NewReader(os.Stdin)
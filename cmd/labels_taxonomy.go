@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kozaktomas/photo-sorter/internal/config"
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/database/postgres"
+	"github.com/kozaktomas/photo-sorter/internal/taxonomy"
+	"github.com/spf13/cobra"
+)
+
+var labelsTaxonomyCmd = &cobra.Command{
+	Use:   "taxonomy",
+	Short: "List and manage the label taxonomy",
+	Long: `List the label taxonomy: canonical label names with their aliases
+(synonyms, Czech/English variants) and parent labels.
+
+"photo-sorter sort" applies the taxonomy before writing labels to
+PhotoPrism: aliases are replaced by the canonical name, duplicates are
+merged and parent labels are added (e.g. "Pes" becomes "Dog" plus
+"Animal"). Requires DATABASE_URL.`,
+	Args: cobra.NoArgs,
+	RunE: runLabelsTaxonomyList,
+}
+
+var labelsTaxonomySetCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Add or replace a taxonomy term",
+	Long: `Add a canonical label or replace an existing one.
+
+Examples:
+  photo-sorter labels taxonomy set Animal --alias Animals --alias Zvíře
+  photo-sorter labels taxonomy set Dog --parent Animal --alias Dogs --alias Pes --alias Psi`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLabelsTaxonomySet,
+}
+
+var labelsTaxonomyRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a taxonomy term",
+	Long:  `Remove a canonical label. Its children keep their names but lose their parent.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runLabelsTaxonomyRemove,
+}
+
+var labelsTaxonomyImportCmd = &cobra.Command{
+	Use:   "import <file.yaml>",
+	Short: "Import taxonomy terms from a YAML file",
+	Long: `Add or replace the terms listed in a YAML file. Terms not in the file
+are kept.
+
+File format:
+  labels:
+    - name: Animal
+      aliases: [Animals, Zvíře]
+    - name: Dog
+      parent: Animal
+      aliases: [Dogs, Pes, Psi]
+      description: Domestic dogs of any breed`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLabelsTaxonomyImport,
+}
+
+var labelsTaxonomyExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the taxonomy as YAML",
+	Long:  `Print the taxonomy in the format accepted by "labels taxonomy import".`,
+	Args:  cobra.NoArgs,
+	RunE:  runLabelsTaxonomyExport,
+}
+
+func init() {
+	labelsCmd.AddCommand(labelsTaxonomyCmd)
+	labelsTaxonomyCmd.AddCommand(labelsTaxonomySetCmd)
+	labelsTaxonomyCmd.AddCommand(labelsTaxonomyRemoveCmd)
+	labelsTaxonomyCmd.AddCommand(labelsTaxonomyImportCmd)
+	labelsTaxonomyCmd.AddCommand(labelsTaxonomyExportCmd)
+
+	labelsTaxonomySetCmd.Flags().String("parent", "", "Canonical name of the parent label")
+	labelsTaxonomySetCmd.Flags().StringSlice("alias", nil, "Alias of the label (repeatable)")
+	labelsTaxonomySetCmd.Flags().String("description", "", "Description of the label")
+}
+
+// openTaxonomyStore connects to PostgreSQL and returns the taxonomy store.
+func openTaxonomyStore() (*postgres.LabelTaxonomyRepository, error) {
+	cfg := config.Load()
+	if cfg.Database.URL == "" {
+		return nil, errors.New("DATABASE_URL environment variable is required")
+	}
+	if err := postgres.Initialize(&cfg.Database); err != nil {
+		return nil, fmt.Errorf("failed to initialize PostgreSQL: %w", err)
+	}
+	return postgres.NewLabelTaxonomyRepository(postgres.GetGlobalPool()), nil
+}
+
+func runLabelsTaxonomyList(cmd *cobra.Command, args []string) error {
+	store, err := openTaxonomyStore()
+	if err != nil {
+		return err
+	}
+	terms, err := store.ListLabelTerms(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to list label taxonomy: %w", err)
+	}
+	if len(terms) == 0 {
+		fmt.Println("Label taxonomy is empty.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPARENT\tALIASES")
+	fmt.Fprintln(w, "----\t------\t-------")
+	for _, term := range terms {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", term.Name, term.Parent, strings.Join(term.Aliases, ", "))
+	}
+	w.Flush()
+
+	fmt.Printf("\nTotal: %d terms\n", len(terms))
+	return nil
+}
+
+func runLabelsTaxonomySet(cmd *cobra.Command, args []string) error {
+	store, err := openTaxonomyStore()
+	if err != nil {
+		return err
+	}
+	term := database.LabelTerm{
+		Name:        args[0],
+		Parent:      mustGetString(cmd, "parent"),
+		Aliases:     mustGetStringSlice(cmd, "alias"),
+		Description: mustGetString(cmd, "description"),
+	}
+	if err := taxonomy.SaveTerms(context.Background(), store, term); err != nil {
+		return fmt.Errorf("failed to save %s: %w", args[0], err)
+	}
+	fmt.Printf("Saved %s.\n", strings.TrimSpace(args[0]))
+	return nil
+}
+
+func runLabelsTaxonomyRemove(cmd *cobra.Command, args []string) error {
+	store, err := openTaxonomyStore()
+	if err != nil {
+		return err
+	}
+	deleted, err := store.DeleteLabelTerm(context.Background(), args[0])
+	if err != nil {
+		return fmt.Errorf("failed to remove %s: %w", args[0], err)
+	}
+	if !deleted {
+		return fmt.Errorf("label %s is not in the taxonomy", args[0])
+	}
+	fmt.Printf("Removed %s.\n", args[0])
+	return nil
+}
+
+func runLabelsTaxonomyImport(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", args[0], err)
+	}
+	terms, err := taxonomy.ParseYAML(data)
+	if err != nil {
+		return fmt.Errorf("invalid taxonomy file: %w", err)
+	}
+	store, err := openTaxonomyStore()
+	if err != nil {
+		return err
+	}
+	if err := taxonomy.SaveTerms(context.Background(), store, terms...); err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+	fmt.Printf("Imported %d terms.\n", len(terms))
+	return nil
+}
+
+func runLabelsTaxonomyExport(cmd *cobra.Command, args []string) error {
+	store, err := openTaxonomyStore()
+	if err != nil {
+		return err
+	}
+	terms, err := store.ListLabelTerms(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to list label taxonomy: %w", err)
+	}
+	data, err := taxonomy.MarshalYAML(terms)
+	if err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+	if _, err := os.Stdout.Write(data); err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+	return nil
+}
@@ -48,17 +48,20 @@ func runMove(cmd *cobra.Command, args []string) error {
 
 	// Get all photos from source album.
 	fmt.Println("Fetching photos from source album...")
-	allPhotos, err := fetchAllAlbumPhotos(pp, sourceAlbumUID, 100)
+	ctx, cancel := setupCancellableContext()
+	defer cancel()
+	pp = pp.WithContext(ctx)
+	photoUIDs, err := fetchAlbumPhotoUIDs(ctx, pp, sourceAlbumUID)
 	if err != nil {
 		return err
 	}
 
-	if len(allPhotos) == 0 {
+	if len(photoUIDs) == 0 {
 		fmt.Println("No photos found in source album.")
 		return nil
 	}
 
-	fmt.Printf("Found %d photo(s) to move\n", len(allPhotos))
+	fmt.Printf("Found %d photo(s) to move\n", len(photoUIDs))
 
 	// Create new album.
 	fmt.Printf("Creating new album: %s\n", newAlbumName)
@@ -68,12 +71,6 @@ func runMove(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Printf("Created album: %s (UID: %s)\n", newAlbum.Title, newAlbum.UID)
 
-	// Collect photo UIDs.
-	photoUIDs := make([]string, len(allPhotos))
-	for i := range allPhotos {
-		photoUIDs[i] = allPhotos[i].UID
-	}
-
 	// Add photos to new album.
 	fmt.Println("Adding photos to new album...")
 	if err := pp.AddPhotosToAlbum(newAlbum.UID, photoUIDs); err != nil {
@@ -86,7 +83,7 @@ func runMove(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to remove photos from source album: %w", err)
 	}
 
-	fmt.Printf("\nDone! Moved %d photo(s) from '%s' to '%s'\n", len(allPhotos), sourceAlbum.Title, newAlbum.Title)
+	fmt.Printf("\nDone! Moved %d photo(s) from '%s' to '%s'\n", len(photoUIDs), sourceAlbum.Title, newAlbum.Title)
 	fmt.Printf("New album UID: %s\n", newAlbum.UID)
 
 	return nil
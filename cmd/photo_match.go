@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	_ "image/png"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"text/tabwriter"
@@ -20,6 +22,7 @@ import (
 	"github.com/kozaktomas/photo-sorter/internal/database"
 	"github.com/kozaktomas/photo-sorter/internal/database/postgres"
 	"github.com/kozaktomas/photo-sorter/internal/facematch"
+	"github.com/kozaktomas/photo-sorter/internal/facequality"
 	"github.com/kozaktomas/photo-sorter/internal/photoprism"
 	"github.com/spf13/cobra"
 	"golang.org/x/image/draw"
@@ -39,6 +42,18 @@ This command:
 Use --apply to create markers and assign the person in PhotoPrism.
 Use --dry-run with --apply to preview changes without applying them.
 
+Use --age-aware for people whose photos span decades. The source faces are then
+bucketed into periods of --period-years by photo year, each period gets its own
+centroid, and every candidate is ranked by its distance to the centroid of the
+period nearest to its photo's year (the centroid of all source faces when the
+year is unknown). A candidate close to a period centroid is kept even when only
+a few source faces (e.g. from childhood) find it, and one far from it is
+dropped. Age-aware matching is not available in the web UI.
+
+Every face has a quality score (0-1) from its sharpness, size, detection score
+and pose; period centroids weight faces by it. Use --min-quality to leave out
+blurry, tiny or profile faces, both as source faces and as candidates.
+
 Examples:
   # Find all photos containing john-doe
   photo-sorter photo match john-doe
@@ -56,7 +71,13 @@ Examples:
   photo-sorter photo match john-doe --apply --dry-run
 
   # Actually apply changes to PhotoPrism
-  photo-sorter photo match john-doe --apply`,
+  photo-sorter photo match john-doe --apply
+
+  # Match childhood photos using per-decade centroids
+  photo-sorter photo match john-doe --age-aware
+
+  # Ignore low quality faces
+  photo-sorter photo match john-doe --min-quality 0.4`,
 	Args: cobra.ExactArgs(1),
 	RunE: runPhotoMatch,
 }
@@ -70,6 +91,12 @@ func init() {
 	photoMatchCmd.Flags().Bool("apply", false, "Apply changes to PhotoPrism (create markers and assign person)")
 	photoMatchCmd.Flags().Bool("dry-run", false, "Preview changes without applying them (use with --apply)")
 	photoMatchCmd.Flags().Bool("save-matches", false, "Save matched photos with face boxes to test/ folder")
+	photoMatchCmd.Flags().Bool("age-aware", false,
+		"Rank candidates by the centroid of the period nearest to their photo year")
+	photoMatchCmd.Flags().Int("period-years", facematch.DefaultPeriodYears,
+		"Length of a period in years (with --age-aware)")
+	photoMatchCmd.Flags().Float64("min-quality", 0,
+		"Minimum face quality (0-1) of source faces and candidates (0 = all)")
 }
 
 // MatchResult represents a photo that matches the person search.
@@ -84,6 +111,9 @@ type MatchResult struct {
 	MarkerUID  string                `json:"marker_uid,omitempty"`  // Existing marker UID if found
 	MarkerName string                `json:"marker_name,omitempty"` // Existing marker name if assigned
 	IoU        float64               `json:"iou,omitempty"`         // IoU with matched marker
+	Period     string                `json:"period,omitempty"`      // Period centroid used (with --age-aware)
+	PeriodDist float64               `json:"period_dist,omitempty"` // Distance to the period centroid
+	Quality    float64               `json:"quality,omitempty"`     // Quality score of the matched face
 	Applied    bool                  `json:"applied,omitempty"`     // Whether the change was applied
 	ApplyError string                `json:"apply_error,omitempty"` // Error message if apply failed
 }
@@ -93,10 +123,17 @@ type MatchOutput struct {
 	Person       string        `json:"person"`
 	SourcePhotos int           `json:"source_photos"`
 	SourceFaces  int           `json:"source_faces"`
+	Periods      []MatchPeriod `json:"periods,omitempty"`
 	Matches      []MatchResult `json:"matches"`
 	Summary      MatchSummary  `json:"summary"`
 }
 
+// MatchPeriod describes a period centroid of the source faces.
+type MatchPeriod struct {
+	Period string `json:"period"`
+	Faces  int    `json:"faces"`
+}
+
 // MatchSummary provides counts by action type.
 type MatchSummary struct {
 	CreateMarker int `json:"create_marker"`
@@ -336,6 +373,9 @@ type matchCmdFlags struct {
 	apply       bool
 	dryRun      bool
 	saveMatches bool
+	ageAware    bool
+	periodYears int
+	minQuality  float64
 }
 
 // matchDeps holds initialized dependencies for the photo match command.
@@ -392,6 +432,8 @@ type sourceData struct {
 	PhotoUID  string
 	Embedding []float32
 	BBox      []float64
+	Year      int     // 0 = unknown
+	Quality   float64 // face quality score
 }
 
 // extractPhotoDimensions extracts width and height from photo details Files[0].
@@ -508,7 +550,10 @@ func resolveSourceFaceForPhoto(
 		return nil
 	}
 
-	return &sourceData{PhotoUID: photo.UID, Embedding: bestFace.Embedding, BBox: bestFace.BBox}
+	return &sourceData{
+		PhotoUID: photo.UID, Embedding: bestFace.Embedding, BBox: bestFace.BBox,
+		Year: facematch.PhotoYear(photo.Year, photo.TakenAt), Quality: facequality.ForFace(bestFace),
+	}
 }
 
 // collectSourceFaces gathers face embeddings for source photos that match the person.
@@ -548,7 +593,10 @@ type matchCandidate struct {
 	Distance   float64
 	FaceIndex  int
 	BBox       []float64
+	Embedding  []float32
+	Quality    float64
 	MatchCount int
+	PeriodHit  bool // found by a period centroid search (--age-aware)
 }
 
 // matchSearchResult holds the result of a single similarity search.
@@ -589,12 +637,27 @@ func runParallelFaceSearches(
 	return resultsChan
 }
 
-// accumulateMatchCandidates processes search results into a match map, excluding source photos.
+// candidateFilter decides which similar faces can become match candidates.
+type candidateFilter struct {
+	sourcePhotos map[string]bool // photos already tagged with the person
+	minQuality   float64         // --min-quality (0 = all)
+}
+
+// skip reports whether a similar face is on a source photo or of too low quality.
+func (f *candidateFilter) skip(face *database.StoredFace) bool {
+	if f.sourcePhotos[face.PhotoUID] {
+		return true
+	}
+	return f.minQuality > 0 && facequality.ForFace(face) < f.minQuality
+}
+
+// accumulateMatchCandidates processes search results into a match map, excluding source photos
+// and faces rejected by the filter.
 //
 //nolint:gocognit // Candidate accumulation from parallel search results.
 func accumulateMatchCandidates(
 	resultsChan chan matchSearchResult,
-	sourcePhotoSet map[string]bool,
+	filter *candidateFilter,
 ) (map[string]*matchCandidate, error) {
 	matchMap := make(map[string]*matchCandidate)
 	var searchErr error
@@ -604,8 +667,9 @@ func accumulateMatchCandidates(
 			searchErr = result.err
 			continue
 		}
-		for i, face := range result.faces {
-			if sourcePhotoSet[face.PhotoUID] {
+		for i := range result.faces {
+			face := &result.faces[i]
+			if filter.skip(face) {
 				continue
 			}
 			if existing, ok := matchMap[face.PhotoUID]; ok {
@@ -614,6 +678,8 @@ func accumulateMatchCandidates(
 					existing.Distance = result.distances[i]
 					existing.FaceIndex = face.FaceIndex
 					existing.BBox = face.BBox
+					existing.Embedding = face.Embedding
+					existing.Quality = facequality.ForFace(face)
 				}
 			} else {
 				matchMap[face.PhotoUID] = &matchCandidate{
@@ -621,6 +687,8 @@ func accumulateMatchCandidates(
 					Distance:   result.distances[i],
 					FaceIndex:  face.FaceIndex,
 					BBox:       face.BBox,
+					Embedding:  face.Embedding,
+					Quality:    facequality.ForFace(face),
 					MatchCount: 1,
 				}
 			}
@@ -633,10 +701,50 @@ func accumulateMatchCandidates(
 	return matchMap, nil
 }
 
+// addPeriodCandidates searches for faces similar to the period centroids and
+// marks them as period hits, adding candidates not found by any source face.
+func addPeriodCandidates(
+	ctx context.Context,
+	faceReader database.FaceReader,
+	periods facematch.PeriodCentroids,
+	matchMap map[string]*matchCandidate,
+	filter *candidateFilter,
+	searchLimit int,
+	threshold float64,
+) {
+	centroids := make([][]float32, len(periods))
+	for i := range periods {
+		centroids[i] = periods[i].Centroid
+	}
+
+	for result := range runParallelFaceSearches(ctx, faceReader, centroids, searchLimit, threshold) {
+		for i := range result.faces {
+			face := &result.faces[i]
+			if filter.skip(face) {
+				continue
+			}
+			if existing, ok := matchMap[face.PhotoUID]; ok {
+				existing.PeriodHit = true
+				continue
+			}
+			matchMap[face.PhotoUID] = &matchCandidate{
+				PhotoUID:  face.PhotoUID,
+				Distance:  result.distances[i],
+				FaceIndex: face.FaceIndex,
+				BBox:      face.BBox,
+				Embedding: face.Embedding,
+				Quality:   facequality.ForFace(face),
+				PeriodHit: true,
+			}
+		}
+	}
+}
+
 // filterAndSortMatchCandidates filters by min match count, sorts by distance, and applies limit.
+// Period hits are kept with fewer matches.
 func filterAndSortMatchCandidates(matchMap map[string]*matchCandidate, minMatchCount, limit int) []matchCandidate {
 	for photoUID, candidate := range matchMap {
-		if candidate.MatchCount < minMatchCount {
+		if candidate.MatchCount < minMatchCount && !candidate.PeriodHit {
 			delete(matchMap, photoUID)
 		}
 	}
@@ -664,28 +772,31 @@ func filterAndSortMatchCandidates(matchMap map[string]*matchCandidate, minMatchC
 func searchSimilarFaces(
 	ctx context.Context,
 	faceReader database.FaceReader,
-	sourceEmbeddings [][]float32,
-	sourcePhotoUIDs []string,
-	threshold float64,
-	limit int,
+	src *sourceResult,
+	flags *matchCmdFlags,
 	minMatchCount int,
 ) ([]matchCandidate, error) {
-	sourcePhotoSet := make(map[string]bool)
-	for _, uid := range sourcePhotoUIDs {
-		sourcePhotoSet[uid] = true
+	filter := &candidateFilter{sourcePhotos: make(map[string]bool), minQuality: flags.minQuality}
+	for _, uid := range src.photoUIDs {
+		filter.sourcePhotos[uid] = true
 	}
+	threshold, limit := flags.threshold, flags.limit
 
 	searchLimit := constants.DefaultSearchLimit
 	if limit > 0 && limit < searchLimit {
 		searchLimit = limit * 10
 	}
 
-	resultsChan := runParallelFaceSearches(ctx, faceReader, sourceEmbeddings, searchLimit, threshold)
-	matchMap, err := accumulateMatchCandidates(resultsChan, sourcePhotoSet)
+	resultsChan := runParallelFaceSearches(ctx, faceReader, src.embeddings, searchLimit, threshold)
+	matchMap, err := accumulateMatchCandidates(resultsChan, filter)
 	if err != nil {
 		return nil, err
 	}
+	addPeriodCandidates(ctx, faceReader, src.periods, matchMap, filter, searchLimit, threshold)
 
+	if flags.ageAware {
+		limit = 0 // applied once the candidates are ranked by their period centroid
+	}
 	return filterAndSortMatchCandidates(matchMap, minMatchCount, limit), nil
 }
 
@@ -718,13 +829,60 @@ func isFaceTooSmall(bbox []float64, width int) bool {
 	return faceWidth < database.MinFaceWidthPx || faceWidthRel < database.MinFaceWidthRel
 }
 
+// globalPeriod is the period reported for candidates ranked by the centroid
+// of all source faces.
+const globalPeriod = "all"
+
+// ageMatcher ranks candidates by the per-period centroids of the source faces
+// (--age-aware).
+type ageMatcher struct {
+	periods   facematch.PeriodCentroids
+	centroid  []float32 // centroid of all source faces, nil without --age-aware
+	threshold float64
+	limit     int
+}
+
+// score records the centroid of the period nearest to the candidate's photo
+// year, or the centroid of all source faces when the year is unknown, and the
+// candidate's distance to it. It reports false when that centroid is not
+// within the threshold.
+func (a *ageMatcher) score(result *MatchResult, c *matchCandidate, details map[string]any) bool {
+	if a == nil || a.centroid == nil {
+		return true
+	}
+	year := facematch.PhotoYear(detailsInt(details, "Year"), detailsString(details, "TakenAt"))
+	centroid, dist := a.periods.Rank(c.Embedding, year, a.centroid)
+	result.Period = globalPeriod
+	if centroid != nil {
+		result.Period = centroid.Period.String()
+	}
+	result.PeriodDist = dist
+	return dist <= a.threshold
+}
+
+// rank sorts the matches by the distance to their period centroid and applies
+// the limit.
+func (a *ageMatcher) rank(matches []MatchResult) []MatchResult {
+	if a == nil || a.centroid == nil {
+		return matches
+	}
+	slices.SortStableFunc(matches, func(x, y MatchResult) int { return cmp.Compare(x.PeriodDist, y.PeriodDist) })
+	if a.limit > 0 && len(matches) > a.limit {
+		matches = matches[:a.limit]
+	}
+	return matches
+}
+
 // determineCandidateAction fetches markers for a candidate and determines the action.
-func determineCandidateAction(pp *photoprism.PhotoPrism, c matchCandidate, jsonOutput bool) MatchResult {
+func determineCandidateAction(
+	pp *photoprism.PhotoPrism, c matchCandidate, ages *ageMatcher, jsonOutput bool,
+) MatchResult {
 	result := MatchResult{
 		PhotoUID:  c.PhotoUID,
 		Distance:  c.Distance,
 		FaceIndex: c.FaceIndex,
 		BBox:      c.BBox,
+		Quality:   c.Quality,
 		Action:    facematch.ActionCreateMarker,
 	}
 
@@ -735,6 +893,10 @@ func determineCandidateAction(pp *photoprism.PhotoPrism, c matchCandidate, jsonO
 	}
 
 	result.FileUID = extractFileUID(details)
+	if !ages.score(&result, &c, details) {
+		result.Action = ""
+		return result
+	}
 	width, height := extractPhotoDimensions(details)
 	if width == 0 || height == 0 || len(c.BBox) != 4 {
 		return result
@@ -763,18 +925,22 @@ func determineCandidateAction(pp *photoprism.PhotoPrism, c matchCandidate, jsonO
 func buildMatchResults(
 	pp *photoprism.PhotoPrism,
 	candidates []matchCandidate,
+	ages *ageMatcher,
 	jsonOutput bool,
 ) ([]MatchResult, MatchSummary) {
 	matches := make([]MatchResult, 0, len(candidates))
-	summary := MatchSummary{}
-
 	for _, c := range candidates {
-		result := determineCandidateAction(pp, c, jsonOutput)
+		result := determineCandidateAction(pp, c, ages, jsonOutput)
 		if result.Action == "" {
-			continue // Skipped (face too small)
+			continue // Skipped (face too small or too far from its period)
 		}
+		matches = append(matches, result)
+	}
+	matches = ages.rank(matches)
 
-		switch result.Action {
+	summary := MatchSummary{}
+	for i := range matches {
+		switch matches[i].Action {
 		case facematch.ActionCreateMarker:
 			summary.CreateMarker++
 		case facematch.ActionAssignPerson:
@@ -784,7 +950,6 @@ func buildMatchResults(
 		case facematch.ActionUnassignPerson:
 			// Not applicable.
 		}
-		matches = append(matches, result)
 	}
 
 	return matches, summary
@@ -965,8 +1130,8 @@ func printMatchTable(matches []MatchResult, summary MatchSummary, personName str
 	fmt.Printf("Found %d photos matching %s:\n\n", len(matches), personName)
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "PHOTO\tDISTANCE\tACTION\tMARKER UID\tMARKER NAME\tIoU")
-	fmt.Fprintln(w, "-----\t--------\t------\t----------\t-----------\t---")
+	fmt.Fprintln(w, "PHOTO\tDISTANCE\tQUALITY\tACTION\tMARKER UID\tMARKER NAME\tIoU\tPERIOD")
+	fmt.Fprintln(w, "-----\t--------\t-------\t------\t----------\t-----------\t---\t------")
 
 	for i := range matches {
 		m := &matches[i]
@@ -986,8 +1151,12 @@ func printMatchTable(matches []MatchResult, summary MatchSummary, personName str
 		if m.IoU > 0 {
 			iouStr = fmt.Sprintf("%.2f", m.IoU)
 		}
-		fmt.Fprintf(w, "%s\t%.4f\t%s\t%s\t%s\t%s\n",
-			photoRef, m.Distance, m.Action, markerUID, markerName, iouStr)
+		period := "-"
+		if m.Period != "" {
+			period = fmt.Sprintf("%s (%.4f)", m.Period, m.PeriodDist)
+		}
+		fmt.Fprintf(w, "%s\t%.4f\t%.2f\t%s\t%s\t%s\t%s\t%s\n",
+			photoRef, m.Distance, m.Quality, m.Action, markerUID, markerName, iouStr, period)
 	}
 
 	w.Flush()
@@ -999,22 +1168,16 @@ func printMatchTable(matches []MatchResult, summary MatchSummary, personName str
 }
 
 // fetchAllPersonPhotos fetches all photos for a person query from PhotoPrism.
-func fetchAllPersonPhotos(pp *photoprism.PhotoPrism, personName string) ([]photoprism.Photo, error) {
-	query := "person:" + personName
+func fetchAllPersonPhotos(
+	ctx context.Context, pp *photoprism.PhotoPrism, personName string,
+) ([]photoprism.Photo, error) {
 	var sourcePhotos []photoprism.Photo
-	pageSize := constants.DefaultPageSize
-	offset := 0
-
-	for {
-		photos, err := pp.GetPhotosWithQuery(pageSize, offset, query)
+	opts := photoprism.IterateOptions{PageSize: constants.DefaultPageSize}
+	for photos, err := range pp.IteratePhotos(ctx, "person:"+personName, opts) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to get photos: %w", err)
 		}
-		if len(photos) == 0 {
-			break
-		}
 		sourcePhotos = append(sourcePhotos, photos...)
-		offset += len(photos)
 	}
 	return sourcePhotos, nil
 }
@@ -1042,14 +1205,12 @@ func extractSourceEmbeddingsAndUIDs(sourceFaces []sourceData) ([][]float32, []st
 func processMatchResults(
 	deps *matchDeps,
 	flags *matchCmdFlags,
+	src *sourceResult,
 	matches []MatchResult,
 	summary MatchSummary,
-	actualPersonName string,
-	sourcePhotos int,
-	sourceEmbeddingCount int,
 ) error {
 	if flags.apply {
-		nameToApply := actualPersonName
+		nameToApply := src.actualPersonName
 		if nameToApply == "" {
 			nameToApply = flags.personName
 		}
@@ -1061,9 +1222,13 @@ func processMatchResults(
 	}
 
 	if flags.jsonOutput {
+		periods := make([]MatchPeriod, len(src.periods))
+		for i := range src.periods {
+			periods[i] = MatchPeriod{Period: src.periods[i].Period.String(), Faces: src.periods[i].Faces}
+		}
 		return outputJSON(MatchOutput{
-			Person: flags.personName, SourcePhotos: sourcePhotos, SourceFaces: sourceEmbeddingCount,
-			Matches: matches, Summary: summary,
+			Person: flags.personName, SourcePhotos: len(src.photos), SourceFaces: len(src.embeddings),
+			Periods: periods, Matches: matches, Summary: summary,
 		})
 	}
 
@@ -1075,14 +1240,45 @@ type sourceResult struct {
 	photos           []photoprism.Photo
 	embeddings       [][]float32
 	photoUIDs        []string
+	periods          facematch.PeriodCentroids // per-period centroids (--age-aware)
+	centroid         []float32                 // centroid of all source faces (--age-aware)
 	actualPersonName string
 }
 
+// minPeriodFaces is the minimum number of source faces of a period to build
+// its centroid for age-aware matching.
+const minPeriodFaces = 2
+
+// filterSourceFacesByQuality drops source faces below minQuality and
+// returns the kept faces and the number dropped.
+func filterSourceFacesByQuality(sourceFaces []sourceData, minQuality float64) ([]sourceData, int) {
+	if minQuality <= 0 {
+		return sourceFaces, 0
+	}
+	kept := sourceFaces[:0]
+	for _, sf := range sourceFaces {
+		if sf.Quality >= minQuality {
+			kept = append(kept, sf)
+		}
+	}
+	return kept, len(sourceFaces) - len(kept)
+}
+
+// buildSourcePeriods builds the per-period centroids of the source faces and
+// the centroid of all of them, weighted by face quality.
+func buildSourcePeriods(sourceFaces []sourceData, periodYears int) (facematch.PeriodCentroids, []float32) {
+	dated := make([]facematch.DatedFace, len(sourceFaces))
+	for i, sf := range sourceFaces {
+		dated[i] = facematch.DatedFace{Embedding: sf.Embedding, Year: sf.Year, Weight: sf.Quality}
+	}
+	return facematch.BuildPeriodCentroids(dated, periodYears, minPeriodFaces), facematch.BuildCentroid(dated)
+}
+
 func collectSourceData(
 	ctx context.Context, deps *matchDeps, flags *matchCmdFlags,
 ) (*sourceResult, error) {
 	warnf(!flags.jsonOutput, "Searching for photos with query: person:%s\n", flags.personName)
-	sourcePhotos, err := fetchAllPersonPhotos(deps.pp, flags.personName)
+	sourcePhotos, err := fetchAllPersonPhotos(ctx, deps.pp, flags.personName)
 	if err != nil {
 		return nil, err
 	}
@@ -1095,15 +1291,29 @@ func collectSourceData(
 	sourceFaces, actualPersonName := collectSourceFaces(
 		ctx, deps.pp, deps.faceReader, sourcePhotos, flags.personName, flags.jsonOutput,
 	)
+	sourceFaces, lowQuality := filterSourceFacesByQuality(sourceFaces, flags.minQuality)
+	if lowQuality > 0 && !flags.jsonOutput {
+		fmt.Printf("Skipped %d source faces below quality %.2f\n", lowQuality, flags.minQuality)
+	}
 	embeddings, photoUIDs := extractSourceEmbeddingsAndUIDs(sourceFaces)
 
+	var periods facematch.PeriodCentroids
+	var centroid []float32
+	if flags.ageAware {
+		periods, centroid = buildSourcePeriods(sourceFaces, flags.periodYears)
+		if !flags.jsonOutput {
+			fmt.Printf("Built %d period centroids of %d years\n", len(periods), flags.periodYears)
+		}
+	}
+
 	return &sourceResult{
-		photos: sourcePhotos, embeddings: embeddings,
-		photoUIDs: photoUIDs, actualPersonName: actualPersonName,
+		photos: sourcePhotos, embeddings: embeddings, photoUIDs: photoUIDs,
+		periods: periods, centroid: centroid, actualPersonName: actualPersonName,
 	}, nil
 }
 
-func runPhotoMatch(cmd *cobra.Command, args []string) error {
+// parseMatchFlags parses and validates the flags of the photo match command.
+func parseMatchFlags(cmd *cobra.Command, args []string) (*matchCmdFlags, error) {
 	flags := &matchCmdFlags{
 		personName:  args[0],
 		threshold:   mustGetFloat64(cmd, "threshold"),
@@ -1112,6 +1322,20 @@ func runPhotoMatch(cmd *cobra.Command, args []string) error {
 		apply:       mustGetBool(cmd, "apply"),
 		dryRun:      mustGetBool(cmd, "dry-run"),
 		saveMatches: mustGetBool(cmd, "save-matches"),
+		ageAware:    mustGetBool(cmd, "age-aware"),
+		periodYears: mustGetInt(cmd, "period-years"),
+		minQuality:  mustGetFloat64(cmd, "min-quality"),
+	}
+	if flags.minQuality < 0 || flags.minQuality > 1 {
+		return nil, errors.New("--min-quality must be between 0 and 1")
+	}
+	return flags, nil
+}
+
+func runPhotoMatch(cmd *cobra.Command, args []string) error {
+	flags, err := parseMatchFlags(cmd, args)
+	if err != nil {
+		return err
 	}
 
 	ctx := context.Background()
@@ -1147,16 +1371,14 @@ func runPhotoMatch(cmd *cobra.Command, args []string) error {
 		"Found %d face embeddings from source photos\nSearching for similar faces (threshold: %.2f, min matches: %d/%d)...\n",
 		len(src.embeddings), flags.threshold, minMatchCount, len(src.embeddings))
 
-	candidates, err := searchSimilarFaces(
-		ctx, deps.faceReader, src.embeddings, src.photoUIDs,
-		flags.threshold, flags.limit, minMatchCount,
-	)
+	candidates, err := searchSimilarFaces(ctx, deps.faceReader, src, flags, minMatchCount)
 	if err != nil {
 		return err
 	}
 
 	warnf(!flags.jsonOutput && len(candidates) > 0, "Fetching marker info for %d matches...\n\n", len(candidates))
-	matches, summary := buildMatchResults(deps.pp, candidates, flags.jsonOutput)
+	ages := &ageMatcher{periods: src.periods, centroid: src.centroid, threshold: flags.threshold, limit: flags.limit}
+	matches, summary := buildMatchResults(deps.pp, candidates, ages, flags.jsonOutput)
 
-	return processMatchResults(deps, flags, matches, summary, src.actualPersonName, len(src.photos), len(src.embeddings))
+	return processMatchResults(deps, flags, src, matches, summary)
 }
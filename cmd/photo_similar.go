@@ -175,22 +175,14 @@ func fetchPhotosForLabels(pp *photoprism.PhotoPrism, labels []string, jsonOutput
 			fmt.Printf("Fetching photos with label '%s'...\n", label)
 		}
 
-		query := "label:" + label
-		offset := 0
-		pageSize := 100
-
-		for {
-			photos, err := pp.GetPhotosWithQuery(pageSize, offset, query)
-			if err != nil {
-				return nil, fmt.Errorf("failed to get photos for label '%s': %w", label, err)
-			}
-			for _, photo := range photos {
-				sourcePhotoUIDs[photo.UID] = true
-			}
-			if len(photos) < pageSize {
-				break
-			}
-			offset += pageSize
+		uids, err := photoprism.CollectPhotoUIDs(
+			pp.IteratePhotos(context.Background(), "label:"+label, photoprism.IterateOptions{}),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get photos for label '%s': %w", label, err)
+		}
+		for _, uid := range uids {
+			sourcePhotoUIDs[uid] = true
 		}
 	}
 	return sourcePhotoUIDs, nil
@@ -16,6 +16,7 @@ import (
 	"github.com/kozaktomas/photo-sorter/internal/database/postgres"
 	mcpserver "github.com/kozaktomas/photo-sorter/internal/mcp"
 	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+	"github.com/kozaktomas/photo-sorter/internal/scheduler"
 	"github.com/kozaktomas/photo-sorter/internal/web"
 	"github.com/kozaktomas/photo-sorter/internal/web/handlers"
 	"github.com/spf13/cobra"
@@ -98,6 +99,22 @@ func registerServeBackends(
 	tcRepo := postgres.NewTextCheckRepository(pool)
 	database.RegisterTextCheckStore(func() database.TextCheckStore { return tcRepo })
 
+	jobRepo := postgres.NewJobRepository(pool)
+	database.RegisterJobStore(func() database.JobStore { return jobRepo })
+	fmt.Printf("Job persistence enabled (PostgreSQL)\n")
+
+	sortChangeRepo := postgres.NewSortChangeRepository(pool)
+	database.RegisterSortUndoStore(func() database.SortUndoStore { return sortChangeRepo })
+
+	analysisCacheRepo := postgres.NewAnalysisCacheRepository(pool)
+	database.RegisterAnalysisCache(func() database.AnalysisCache { return analysisCacheRepo })
+
+	taxonomyRepo := postgres.NewLabelTaxonomyRepository(pool)
+	database.RegisterLabelTaxonomyStore(func() database.LabelTaxonomyStore { return taxonomyRepo })
+
+	syncCursorRepo := postgres.NewSyncCursorRepository(pool)
+	database.RegisterSyncCursorStore(func() database.SyncCursorStore { return syncCursorRepo })
+
 	sessionRepo := postgres.NewSessionRepository(pool)
 	fmt.Printf("Session persistence enabled (PostgreSQL)\n")
 	return sessionRepo
@@ -166,6 +183,11 @@ func runServe(cmd *cobra.Command, args []string) error {
 	if cfg.Database.URL == "" {
 		return errors.New("DATABASE_URL environment variable is required")
 	}
+	if spec := cfg.Scheduler.Schedule; spec != "" {
+		if _, err := scheduler.Parse(spec); err != nil {
+			return fmt.Errorf("invalid SYNC_SCHEDULE: %w", err)
+		}
+	}
 
 	fmt.Printf("Connecting to PostgreSQL database...\n")
 	if err := postgres.Initialize(&cfg.Database); err != nil {
@@ -204,6 +226,12 @@ func runServe(cmd *cobra.Command, args []string) error {
 
 	server := web.NewServer(cfg, port, host, sessionSecret, sessionRepo, mcpHandler)
 
+	if resumed, err := server.ResumeJobs(ctx); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	} else if resumed > 0 {
+		fmt.Printf("Resumed %d interrupted job(s)\n", resumed)
+	}
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
@@ -245,9 +273,13 @@ func buildMCPHandler(ctx context.Context, cfg *config.Config, apiToken string) (
 	if err != nil {
 		return nil, fmt.Errorf("MCP: %w", err)
 	}
+	taxonomyStore, err := database.GetLabelTaxonomyStore(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("MCP: %w", err)
+	}
 
 	mcpSrv := mcpserver.NewServer(
-		Version, bookWriter, tvStore, tcStore, embReader,
+		Version, bookWriter, tvStore, tcStore, embReader, taxonomyStore,
 		pp, cfg, apiToken, "/mcp",
 	)
 	return mcpserver.BearerAuthMiddleware(apiToken)(mcpSrv.Handler()), nil
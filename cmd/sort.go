@@ -9,10 +9,13 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/google/uuid"
 	"github.com/kozaktomas/photo-sorter/internal/ai"
 	"github.com/kozaktomas/photo-sorter/internal/config"
+	"github.com/kozaktomas/photo-sorter/internal/database/postgres"
 	"github.com/kozaktomas/photo-sorter/internal/photoprism"
 	"github.com/kozaktomas/photo-sorter/internal/sorter"
+	"github.com/kozaktomas/photo-sorter/internal/taxonomy"
 	"github.com/spf13/cobra"
 )
 
@@ -21,7 +24,13 @@ var sortCmd = &cobra.Command{
 	Short: "Sort photos using AI",
 	Long: `Sort photos in a PhotoPrism album using AI.
 The command analyzes photos and applies labels and estimated dates
-based on visual content analysis.`,
+based on visual content analysis.
+
+When DATABASE_URL is set, the previous state of every changed photo is
+recorded in an undo log and the run can be reverted with
+"photo-sorter sort revert <run-id>". AI analyses are also cached in the
+database, so unchanged photos are not analyzed (and paid for) again;
+use --no-cache to always call the AI provider.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSort,
 }
@@ -33,9 +42,14 @@ func init() {
 	sortCmd.Flags().Int("limit", 0, "Limit number of photos to process (0 = no limit)")
 	sortCmd.Flags().Bool("individual-dates", false, "Estimate date per photo instead of album-wide")
 	sortCmd.Flags().Bool("batch", false, "Use batch API for 50% cost savings (slower, may take minutes)")
-	sortCmd.Flags().String("provider", "openai", "AI provider to use: openai, gemini, ollama, llamacpp")
+	sortCmd.Flags().String("provider", "openai", "AI provider to use: openai, gemini, ollama, llamacpp, openai-compatible")
 	sortCmd.Flags().Bool("force-date", false, "Overwrite existing dates with AI estimates")
+	sortCmd.Flags().StringSlice("consensus", nil,
+		"Combine the labels of several providers, e.g. openai,gemini,ollama (overrides --provider)")
 	sortCmd.Flags().Int("concurrency", 5, "Number of parallel requests in standard mode")
+	sortCmd.Flags().String("resume-batch", "", "Wait for a previously submitted batch instead of creating a new one")
+	sortCmd.Flags().String("cancel-batch", "", "Cancel a previously submitted batch and exit")
+	sortCmd.Flags().Bool("no-cache", false, "Do not use the analysis cache (requires DATABASE_URL)")
 }
 
 // createAIProvider creates the AI provider based on the provider name and config.
@@ -57,9 +71,78 @@ func createAIProvider(providerName string, cfg *config.Config) (ai.Provider, err
 			return nil, fmt.Errorf("creating llama.cpp provider: %w", err)
 		}
 		return p, nil
+	case "openai-compatible":
+		pricing := cfg.GetOpenAICompatiblePricing()
+		p, err := ai.NewOpenAICompatibleProvider(
+			cfg.OpenAICompatible.URL, cfg.OpenAICompatible.GetAPIKey(), cfg.OpenAICompatible.Model,
+			ai.RequestPricing{Input: pricing.Input, Output: pricing.Output},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("creating OpenAI-compatible provider: %w", err)
+		}
+		return p, nil
 	default:
-		return nil, fmt.Errorf("unknown provider: %s (supported: openai, gemini, ollama, llamacpp)", providerName)
+		return nil, fmt.Errorf(
+			"unknown provider: %s (supported: openai, gemini, ollama, llamacpp, openai-compatible)", providerName,
+		)
+	}
+}
+
+// createSortProvider creates the provider selected by --provider, or a
+// consensus of the providers listed in --consensus.
+func createSortProvider(flags sortFlags, cfg *config.Config) (ai.Provider, error) {
+	if len(flags.consensus) == 0 {
+		return createAIProvider(flags.providerName, cfg)
+	}
+	if flags.batchMode {
+		return nil, errors.New("--consensus cannot be combined with --batch")
+	}
+	var providers []ai.Provider
+	seen := make(map[string]bool)
+	for _, name := range flags.consensus {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		p, err := createAIProvider(name, cfg)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, p)
+	}
+	consensus, err := ai.NewConsensusProvider(providers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --consensus: %w", err)
+	}
+	return consensus, nil
+}
+
+// enableBatchMode switches the provider to batch mode if requested and turns
+// on the emulated batch queue for providers without a native batch API
+// (Ollama, llama.cpp).
+func enableBatchMode(provider ai.Provider, cfg *config.Config, flags sortFlags) error {
+	if !flags.batchMode {
+		return nil
+	}
+	provider.SetBatchMode(true)
+	lb, ok := provider.(ai.LocalBatcher)
+	if !ok {
+		return nil
+	}
+	if err := lb.EnableLocalBatch(cfg.LocalBatch.Dir, flags.concurrency); err != nil {
+		return fmt.Errorf("failed to enable local batch queue: %w", err)
+	}
+	return nil
+}
+
+// cancelSortBatch cancels a batch submitted by an earlier run.
+func cancelSortBatch(provider ai.Provider, batchID string) error {
+	if err := provider.CancelBatch(context.Background(), batchID); err != nil {
+		return fmt.Errorf("failed to cancel batch: %w", err)
 	}
+	fmt.Printf("Batch %s cancelled\n", batchID)
+	return nil
 }
 
 func createOpenAIProvider(cfg *config.Config) (ai.Provider, error) {
@@ -92,6 +175,9 @@ func createGeminiProvider(cfg *config.Config) (ai.Provider, error) {
 func printSortResults(result *sorter.SortResult, aiProvider ai.Provider, cfg *config.Config, individualDates bool) {
 	fmt.Printf("\nProcessed: %d photos\n", result.ProcessedCount)
 	fmt.Printf("Sorted: %d photos\n", result.SortedCount)
+	if result.CacheHits > 0 {
+		fmt.Printf("Cache hits: %d photos\n", result.CacheHits)
+	}
 
 	usage := aiProvider.GetUsage()
 	if usage.InputTokens > 0 || usage.OutputTokens > 0 {
@@ -142,6 +228,10 @@ func printSortSuggestions(suggestions []ai.SortSuggestion, cfg *config.Config, i
 			fmt.Printf("    Estimated date: %s\n", s.EstimatedDate)
 		}
 		fmt.Printf("    Description: %s\n", s.Description)
+		if s.Consensus != nil && len(s.Consensus.Disputed) > 0 {
+			fmt.Printf("    Disputed labels (%.0f%% agreement): %s\n",
+				s.Consensus.Agreement*100, strings.Join(s.Consensus.Disputed, ", "))
+		}
 	}
 }
 
@@ -165,10 +255,14 @@ type sortFlags struct {
 	providerName    string
 	forceDate       bool
 	concurrency     int
+	noCache         bool
+	resumeBatch     string
+	cancelBatch     string
+	consensus       []string
 }
 
 func parseSortFlags(cmd *cobra.Command) sortFlags {
-	return sortFlags{
+	flags := sortFlags{
 		dryRun:          mustGetBool(cmd, "dry-run"),
 		limit:           mustGetInt(cmd, "limit"),
 		individualDates: mustGetBool(cmd, "individual-dates"),
@@ -176,7 +270,15 @@ func parseSortFlags(cmd *cobra.Command) sortFlags {
 		providerName:    mustGetString(cmd, "provider"),
 		forceDate:       mustGetBool(cmd, "force-date"),
 		concurrency:     mustGetInt(cmd, "concurrency"),
+		noCache:         mustGetBool(cmd, "no-cache"),
+		resumeBatch:     mustGetString(cmd, "resume-batch"),
+		cancelBatch:     mustGetString(cmd, "cancel-batch"),
+		consensus:       mustGetStringSlice(cmd, "consensus"),
+	}
+	if flags.resumeBatch != "" || flags.cancelBatch != "" {
+		flags.batchMode = true
 	}
+	return flags
 }
 
 func runSort(cmd *cobra.Command, args []string) error {
@@ -184,12 +286,15 @@ func runSort(cmd *cobra.Command, args []string) error {
 	cfg := config.Load()
 	flags := parseSortFlags(cmd)
 
-	aiProvider, err := createAIProvider(flags.providerName, cfg)
+	aiProvider, err := createSortProvider(flags, cfg)
 	if err != nil {
 		return err
 	}
-	if flags.batchMode {
-		aiProvider.SetBatchMode(true)
+	if err := enableBatchMode(aiProvider, cfg, flags); err != nil {
+		return err
+	}
+	if flags.cancelBatch != "" {
+		return cancelSortBatch(aiProvider, flags.cancelBatch)
 	}
 
 	ctx, cancel := setupCancellableContext()
@@ -203,6 +308,7 @@ func runSort(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to connect to PhotoPrism: %w", err)
 	}
 	defer pp.Logout()
+	pp = pp.WithContext(ctx)
 
 	album, err := pp.GetAlbum(albumUID)
 	if err != nil {
@@ -211,23 +317,65 @@ func runSort(cmd *cobra.Command, args []string) error {
 
 	printSortHeader(album, aiProvider, flags)
 
+	opts := sorter.SortOptions{
+		DryRun:            flags.dryRun,
+		Limit:             flags.limit,
+		IndividualDates:   flags.individualDates,
+		BatchMode:         flags.batchMode,
+		ForceDate:         flags.forceDate,
+		Concurrency:       flags.concurrency,
+		ResumeBatchID:     flags.resumeBatch,
+		KeepBatchOnCancel: true,
+	}
+	runID := setupSortDatabase(ctx, cfg, flags, &opts)
+
 	s := sorter.New(pp, aiProvider)
-	result, err := s.Sort(ctx, albumUID, album.Title, album.Description, sorter.SortOptions{
-		DryRun:          flags.dryRun,
-		Limit:           flags.limit,
-		IndividualDates: flags.individualDates,
-		BatchMode:       flags.batchMode,
-		ForceDate:       flags.forceDate,
-		Concurrency:     flags.concurrency,
-	})
+	result, err := s.Sort(ctx, albumUID, album.Title, album.Description, opts)
 	if err != nil {
 		return fmt.Errorf("sorting failed: %w", err)
 	}
 
 	printSortResults(result, aiProvider, cfg, flags.individualDates)
+	if runID != "" && result.SortedCount > 0 {
+		fmt.Printf("\nRun ID: %s\n", runID)
+		fmt.Printf("Undo with: photo-sorter sort revert %s\n", runID)
+	}
 	return nil
 }
 
+// setupSortDatabase connects to PostgreSQL and wires the analysis cache and,
+// for real runs, the undo log into opts. Returns the run ID, or "" when no
+// undo log is recorded. Without a database the sort runs uncached and
+// without an undo log.
+func setupSortDatabase(ctx context.Context, cfg *config.Config, flags sortFlags, opts *sorter.SortOptions) string {
+	if cfg.Database.URL == "" {
+		if !flags.dryRun {
+			fmt.Println("Warning: DATABASE_URL not set, changes will not be revertible")
+		}
+		return ""
+	}
+	if err := postgres.Initialize(&cfg.Database); err != nil {
+		fmt.Printf("Warning: failed to initialize PostgreSQL, analysis cache, label taxonomy and undo log disabled: %v\n", err)
+		return ""
+	}
+	pool := postgres.GetGlobalPool()
+	if !flags.noCache {
+		opts.Cache = postgres.NewAnalysisCacheRepository(pool)
+	}
+	tax, err := taxonomy.Load(ctx, postgres.NewLabelTaxonomyRepository(pool))
+	if err != nil {
+		fmt.Printf("Warning: label taxonomy not applied: %v\n", err)
+	} else {
+		opts.Taxonomy = tax
+	}
+	if flags.dryRun {
+		return ""
+	}
+	runID := uuid.New().String()
+	opts.OnBeforeApply = sorter.UndoRecorder(ctx, postgres.NewSortChangeRepository(pool), runID)
+	return runID
+}
+
 func printSortHeader(album *photoprism.Album, provider ai.Provider, flags sortFlags) {
 	fmt.Printf("Sorting album: %s\n", album.Title)
 	if album.Description != "" {
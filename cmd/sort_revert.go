@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kozaktomas/photo-sorter/internal/config"
+	"github.com/kozaktomas/photo-sorter/internal/database/postgres"
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+	"github.com/kozaktomas/photo-sorter/internal/sorter"
+	"github.com/spf13/cobra"
+)
+
+var sortRevertCmd = &cobra.Command{
+	Use:   "revert <run-id>",
+	Short: "Revert the changes of a previous sort run",
+	Long: `Restores every photo changed by a sort run to the state it had before
+the run: title, description, notes, date and labels.
+
+The run ID is printed at the end of "photo-sorter sort" and is the job ID
+for sort runs started from the web UI. Photos are reverted one by one;
+photos that were already reverted are skipped, so a partially failed
+revert can be re-run. Requires DATABASE_URL.
+
+Examples:
+  photo-sorter sort revert 3f2b9c1e-8d4a-4c2b-9e5f-1a2b3c4d5e6f`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSortRevert,
+}
+
+func init() {
+	sortCmd.AddCommand(sortRevertCmd)
+}
+
+func runSortRevert(cmd *cobra.Command, args []string) error {
+	runID := args[0]
+	cfg := config.Load()
+
+	if cfg.Database.URL == "" {
+		return errors.New("DATABASE_URL environment variable is required")
+	}
+	if err := postgres.Initialize(&cfg.Database); err != nil {
+		return fmt.Errorf("failed to initialize PostgreSQL: %w", err)
+	}
+
+	ctx, cancel := setupCancellableContext()
+	defer cancel()
+
+	pp, err := photoprism.NewPhotoPrismWithCapture(
+		cfg.PhotoPrism.URL, cfg.PhotoPrism.Username,
+		cfg.PhotoPrism.GetPassword(), captureDir,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to connect to PhotoPrism: %w", err)
+	}
+	defer pp.Logout()
+	pp = pp.WithContext(ctx)
+
+	fmt.Printf("Reverting sort run: %s\n", runID)
+	store := postgres.NewSortChangeRepository(postgres.GetGlobalPool())
+	result, err := sorter.RevertRun(ctx, pp, store, runID)
+	if err != nil {
+		return fmt.Errorf("revert failed: %w", err)
+	}
+
+	fmt.Printf("\nReverted: %d photos\n", result.RevertedCount)
+	if result.AlreadyCount > 0 {
+		fmt.Printf("Already reverted: %d photos\n", result.AlreadyCount)
+	}
+	if len(result.Errors) > 0 {
+		fmt.Printf("\nErrors: %d\n", len(result.Errors))
+		for _, err := range result.Errors {
+			fmt.Printf("  - %v\n", err)
+		}
+		return fmt.Errorf("%d photos could not be reverted", len(result.Errors))
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -9,7 +10,6 @@ import (
 	"sync"
 
 	"github.com/kozaktomas/photo-sorter/internal/config"
-	"github.com/kozaktomas/photo-sorter/internal/constants"
 	"github.com/kozaktomas/photo-sorter/internal/photoprism"
 	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/cobra"
@@ -199,13 +199,13 @@ func processUploads(pp *photoprism.PhotoPrism, uploadTokens []string, albumUID s
 
 // getAlbumPhotoUIDs fetches all photo UIDs in an album as a set.
 func getAlbumPhotoUIDs(pp *photoprism.PhotoPrism, albumUID string) (map[string]struct{}, error) {
-	photos, err := fetchAllAlbumPhotos(pp, albumUID, constants.DefaultPageSize)
+	photoUIDs, err := fetchAlbumPhotoUIDs(context.Background(), pp, albumUID)
 	if err != nil {
 		return nil, err
 	}
-	uids := make(map[string]struct{}, len(photos))
-	for _, p := range photos {
-		uids[p.UID] = struct{}{}
+	uids := make(map[string]struct{}, len(photoUIDs))
+	for _, uid := range photoUIDs {
+		uids[uid] = struct{}{}
 	}
 	return uids, nil
 }
@@ -0,0 +1,256 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Consensus records how the providers of a ConsensusProvider voted on a photo.
+type Consensus struct {
+	Votes    []ProviderVote `json:"votes"`
+	Disputed []string       `json:"disputed,omitempty"` // labels not suggested by every responding provider
+	// Agreement is the share of all suggested labels that every responding
+	// provider suggested (1 = full agreement).
+	Agreement float64 `json:"agreement"`
+}
+
+// ProviderVote is the analysis of a single provider within a consensus.
+type ProviderVote struct {
+	Provider    string                `json:"provider"`
+	Labels      []LabelWithConfidence `json:"labels,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Error       string                `json:"error,omitempty"`
+}
+
+// ConsensusProvider implements Provider by asking several providers for each
+// photo analysis and merging their answers: a label is kept when a majority
+// of the responding providers suggest it, with its confidence averaged over
+// all of them (a provider not suggesting the label counts as 0). The
+// description of the provider agreeing most with the merged labels is used.
+// Album dates are estimated by the first provider. Batch mode is not supported.
+type ConsensusProvider struct {
+	providers []Provider
+	usage     Usage
+}
+
+// NewConsensusProvider creates a consensus over at least two providers.
+func NewConsensusProvider(providers []Provider) (*ConsensusProvider, error) {
+	if len(providers) < 2 {
+		return nil, errors.New("consensus needs at least two providers")
+	}
+	return &ConsensusProvider{providers: providers}, nil
+}
+
+// Name returns the names of all providers.
+func (p *ConsensusProvider) Name() string {
+	names := make([]string, len(p.providers))
+	for i, provider := range p.providers {
+		names[i] = provider.Name()
+	}
+	return "consensus(" + strings.Join(names, ",") + ")"
+}
+
+// SetBatchMode is a no-op; consensus has no batch mode.
+func (p *ConsensusProvider) SetBatchMode(enabled bool) {
+	// No batch mode - no-op.
+}
+
+// GetUsage returns the usage summed over all providers.
+func (p *ConsensusProvider) GetUsage() *Usage {
+	p.usage = Usage{}
+	for _, provider := range p.providers {
+		u := provider.GetUsage()
+		p.usage.InputTokens += u.InputTokens
+		p.usage.OutputTokens += u.OutputTokens
+		p.usage.TotalCost += u.TotalCost
+	}
+	return &p.usage
+}
+
+// ResetUsage zeroes out the usage counters of all providers.
+func (p *ConsensusProvider) ResetUsage() {
+	for _, provider := range p.providers {
+		provider.ResetUsage()
+	}
+	p.usage = Usage{}
+}
+
+// AnalyzePhoto asks all providers in parallel and merges their analyses. It
+// fails only if every provider fails.
+func (p *ConsensusProvider) AnalyzePhoto(
+	ctx context.Context,
+	imageData []byte,
+	metadata *PhotoMetadata,
+	availableLabels []string,
+	estimateDate bool,
+) (*PhotoAnalysis, error) {
+	analyses := make([]*PhotoAnalysis, len(p.providers))
+	errs := make([]error, len(p.providers))
+	var wg sync.WaitGroup
+	for i, provider := range p.providers {
+		wg.Go(func() {
+			analyses[i], errs[i] = provider.AnalyzePhoto(ctx, imageData, metadata, availableLabels, estimateDate)
+		})
+	}
+	wg.Wait()
+
+	votes := make([]ProviderVote, len(p.providers))
+	responded := 0
+	for i, provider := range p.providers {
+		votes[i].Provider = provider.Name()
+		if errs[i] != nil {
+			votes[i].Error = errs[i].Error()
+			continue
+		}
+		votes[i].Labels = analyses[i].Labels
+		votes[i].Description = analyses[i].Description
+		responded++
+	}
+	if responded == 0 {
+		return nil, fmt.Errorf("all providers failed: %w", errors.Join(errs...))
+	}
+	return mergeAnalyses(analyses, votes), nil
+}
+
+// labelTally accumulates the votes for one label across providers.
+type labelTally struct {
+	name          string
+	votes         int
+	confidenceSum float64
+}
+
+// mergeAnalyses combines the analyses of the responding providers (nil
+// entries are providers that failed) into one consensus analysis.
+func mergeAnalyses(analyses []*PhotoAnalysis, votes []ProviderVote) *PhotoAnalysis {
+	var order []string
+	tallies := make(map[string]*labelTally)
+	responded := 0
+	for _, a := range analyses {
+		if a == nil {
+			continue
+		}
+		responded++
+		seen := make(map[string]bool)
+		for _, l := range a.Labels {
+			key := strings.ToLower(strings.TrimSpace(l.Name))
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			t, ok := tallies[key]
+			if !ok {
+				t = &labelTally{name: l.Name}
+				tallies[key] = t
+				order = append(order, key)
+			}
+			t.votes++
+			t.confidenceSum += l.Confidence
+		}
+	}
+
+	merged := &PhotoAnalysis{Consensus: &Consensus{Votes: votes, Agreement: 1}}
+	consensusConfidence := make(map[string]float64)
+	unanimous := 0
+	for _, key := range order {
+		t := tallies[key]
+		if t.votes == responded {
+			unanimous++
+		} else {
+			merged.Consensus.Disputed = append(merged.Consensus.Disputed, t.name)
+		}
+		if t.votes*2 <= responded {
+			continue
+		}
+		confidence := t.confidenceSum / float64(responded)
+		consensusConfidence[key] = confidence
+		merged.Labels = append(merged.Labels, LabelWithConfidence{Name: t.name, Confidence: confidence})
+	}
+	if len(order) > 0 {
+		merged.Consensus.Agreement = float64(unanimous) / float64(len(order))
+	}
+
+	best := bestAnalysis(analyses, consensusConfidence)
+	merged.Description = best.Description
+	merged.EstimatedDate = majorityDate(analyses, best.EstimatedDate)
+	return merged
+}
+
+// bestAnalysis returns the analysis whose labels agree most with the merged
+// labels; ties go to the earlier provider.
+func bestAnalysis(analyses []*PhotoAnalysis, consensusConfidence map[string]float64) *PhotoAnalysis {
+	var best *PhotoAnalysis
+	bestScore := -1.0
+	for _, a := range analyses {
+		if a == nil {
+			continue
+		}
+		score := 0.0
+		for _, l := range a.Labels {
+			score += consensusConfidence[strings.ToLower(strings.TrimSpace(l.Name))]
+		}
+		if score > bestScore {
+			best, bestScore = a, score
+		}
+	}
+	return best
+}
+
+// majorityDate returns the estimated date suggested by most providers, or
+// fallback when no date has more votes than the others.
+func majorityDate(analyses []*PhotoAnalysis, fallback string) string {
+	counts := make(map[string]int)
+	best, bestCount, tie := "", 0, false
+	for _, a := range analyses {
+		if a == nil || a.EstimatedDate == "" {
+			continue
+		}
+		counts[a.EstimatedDate]++
+		switch c := counts[a.EstimatedDate]; {
+		case c > bestCount:
+			best, bestCount, tie = a.EstimatedDate, c, false
+		case c == bestCount && a.EstimatedDate != best:
+			tie = true
+		}
+	}
+	if best == "" || tie {
+		return fallback
+	}
+	return best
+}
+
+// EstimateAlbumDate estimates the album date with the first provider.
+func (p *ConsensusProvider) EstimateAlbumDate(
+	ctx context.Context,
+	albumTitle string,
+	albumDescription string,
+	photoDescriptions []string,
+) (*AlbumDateEstimate, error) {
+	estimate, err := p.providers[0].EstimateAlbumDate(ctx, albumTitle, albumDescription, photoDescriptions)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", p.providers[0].Name(), err)
+	}
+	return estimate, nil
+}
+
+// CreatePhotoBatch is not supported in consensus mode and returns an error.
+func (p *ConsensusProvider) CreatePhotoBatch(ctx context.Context, requests []BatchPhotoRequest) (string, error) {
+	return "", errors.New("consensus mode does not support batch operations")
+}
+
+// GetBatchStatus is not supported in consensus mode and returns an error.
+func (p *ConsensusProvider) GetBatchStatus(ctx context.Context, batchID string) (*BatchStatus, error) {
+	return nil, errors.New("consensus mode does not support batch operations")
+}
+
+// GetBatchResults is not supported in consensus mode and returns an error.
+func (p *ConsensusProvider) GetBatchResults(ctx context.Context, batchID string) ([]BatchPhotoResult, error) {
+	return nil, errors.New("consensus mode does not support batch operations")
+}
+
+// CancelBatch is not supported in consensus mode and returns an error.
+func (p *ConsensusProvider) CancelBatch(ctx context.Context, batchID string) error {
+	return errors.New("consensus mode does not support batch operations")
+}
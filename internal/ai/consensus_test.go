@@ -0,0 +1,146 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// stubProvider answers AnalyzePhoto with a fixed analysis or error.
+type stubProvider struct {
+	OllamaProvider // satisfies the rest of Provider
+
+	name     string
+	analysis *PhotoAnalysis
+	err      error
+}
+
+func (p *stubProvider) Name() string { return p.name }
+
+func (p *stubProvider) AnalyzePhoto(
+	_ context.Context, _ []byte, _ *PhotoMetadata, _ []string, _ bool,
+) (*PhotoAnalysis, error) {
+	return p.analysis, p.err
+}
+
+func labels(pairs ...any) []LabelWithConfidence {
+	var result []LabelWithConfidence
+	for i := 0; i < len(pairs); i += 2 {
+		result = append(result, LabelWithConfidence{Name: pairs[i].(string), Confidence: pairs[i+1].(float64)})
+	}
+	return result
+}
+
+func TestNewConsensusProvider_NeedsTwoProviders(t *testing.T) {
+	if _, err := NewConsensusProvider([]Provider{&stubProvider{name: "a"}}); err == nil {
+		t.Error("expected error for a single provider")
+	}
+}
+
+func TestConsensusProvider_AnalyzePhoto(t *testing.T) {
+	p, err := NewConsensusProvider([]Provider{
+		&stubProvider{name: "a", analysis: &PhotoAnalysis{
+			Labels: labels("beach", 0.9, "sea", 0.9), Description: "A beach", EstimatedDate: "2020-07-01",
+		}},
+		&stubProvider{name: "b", analysis: &PhotoAnalysis{
+			Labels: labels("Beach", 0.9, "sea", 0.6, "dog", 0.9), Description: "A dog on a beach", EstimatedDate: "2020-07-01",
+		}},
+		&stubProvider{name: "c", analysis: &PhotoAnalysis{
+			Labels: labels("beach", 0.6, "mountain", 0.9), Description: "Hills", EstimatedDate: "1999-01-01",
+		}},
+	})
+	if err != nil {
+		t.Fatalf("NewConsensusProvider: %v", err)
+	}
+	if p.Name() != "consensus(a,b,c)" {
+		t.Errorf("unexpected name %q", p.Name())
+	}
+
+	analysis, err := p.AnalyzePhoto(context.Background(), nil, &PhotoMetadata{}, nil, true)
+	if err != nil {
+		t.Fatalf("AnalyzePhoto: %v", err)
+	}
+
+	// beach: 3 votes, (0.9+0.9+0.6)/3; sea: 2 votes, (0.9+0.6)/3; dog and mountain: 1 vote, dropped.
+	if len(analysis.Labels) != 2 {
+		t.Fatalf("expected 2 merged labels, got %+v", analysis.Labels)
+	}
+	if analysis.Labels[0].Name != "beach" || math.Abs(analysis.Labels[0].Confidence-0.8) > 1e-9 {
+		t.Errorf("unexpected beach label: %+v", analysis.Labels[0])
+	}
+	if analysis.Labels[1].Name != "sea" || math.Abs(analysis.Labels[1].Confidence-0.5) > 1e-9 {
+		t.Errorf("unexpected sea label: %+v", analysis.Labels[1])
+	}
+
+	// Provider a agrees most with the merged labels (beach + sea, nothing else scored).
+	if analysis.Description != "A beach" {
+		t.Errorf("expected description of the most agreeing provider, got %q", analysis.Description)
+	}
+	if analysis.EstimatedDate != "2020-07-01" {
+		t.Errorf("expected majority date, got %q", analysis.EstimatedDate)
+	}
+
+	c := analysis.Consensus
+	if c == nil || len(c.Votes) != 3 {
+		t.Fatalf("expected 3 votes, got %+v", c)
+	}
+	if strings.Join(c.Disputed, ",") != "sea,dog,mountain" {
+		t.Errorf("unexpected disputed labels: %v", c.Disputed)
+	}
+	if math.Abs(c.Agreement-0.25) > 1e-9 {
+		t.Errorf("expected agreement 0.25, got %f", c.Agreement)
+	}
+}
+
+func TestConsensusProvider_PartialFailure(t *testing.T) {
+	p, _ := NewConsensusProvider([]Provider{
+		&stubProvider{name: "a", err: errors.New("timeout")},
+		&stubProvider{name: "b", analysis: &PhotoAnalysis{Labels: labels("cat", 0.9), Description: "A cat"}},
+	})
+
+	analysis, err := p.AnalyzePhoto(context.Background(), nil, &PhotoMetadata{}, nil, false)
+	if err != nil {
+		t.Fatalf("AnalyzePhoto: %v", err)
+	}
+	if len(analysis.Labels) != 1 || analysis.Labels[0].Confidence != 0.9 {
+		t.Errorf("expected the responding provider's labels, got %+v", analysis.Labels)
+	}
+	if analysis.Consensus.Votes[0].Error != "timeout" {
+		t.Errorf("expected failed vote to record its error, got %+v", analysis.Consensus.Votes[0])
+	}
+	if analysis.Consensus.Agreement != 1 {
+		t.Errorf("expected full agreement among responding providers, got %f", analysis.Consensus.Agreement)
+	}
+}
+
+func TestConsensusProvider_AllFail(t *testing.T) {
+	p, _ := NewConsensusProvider([]Provider{
+		&stubProvider{name: "a", err: errors.New("down")},
+		&stubProvider{name: "b", err: errors.New("down too")},
+	})
+	if _, err := p.AnalyzePhoto(context.Background(), nil, &PhotoMetadata{}, nil, false); err == nil {
+		t.Error("expected error when every provider fails")
+	}
+}
+
+func TestMajorityDate(t *testing.T) {
+	a := func(date string) *PhotoAnalysis { return &PhotoAnalysis{EstimatedDate: date} }
+	tests := []struct {
+		name     string
+		analyses []*PhotoAnalysis
+		want     string
+	}{
+		{"majority", []*PhotoAnalysis{a("2001-01-01"), a("2002-02-02"), a("2002-02-02")}, "2002-02-02"},
+		{"tie uses fallback", []*PhotoAnalysis{a("2001-01-01"), a("2002-02-02")}, "fallback"},
+		{"no dates", []*PhotoAnalysis{a(""), nil}, "fallback"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := majorityDate(tt.analyses, "fallback"); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package ai
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strings"
 )
@@ -24,3 +26,15 @@ func buildAlbumDateContent(albumTitle, albumDescription string, photoDescription
 	}
 	return b.String()
 }
+
+// PhotoAnalysisPromptVersion identifies the photo analysis prompt template.
+// It changes whenever the embedded prompt text changes, so analyses cached
+// under an older prompt are not reused.
+func PhotoAnalysisPromptVersion(estimateDate bool) string {
+	prompt := photoAnalysisPrompt
+	if estimateDate {
+		prompt = photoAnalysisWithDatePrompt
+	}
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:8])
+}
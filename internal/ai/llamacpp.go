@@ -24,6 +24,7 @@ type LlamaCppProvider struct {
 	model     string
 	client    *http.Client
 	usage     Usage
+	batch     *LocalBatchQueue // emulated batch API, set by EnableLocalBatch
 }
 
 // NewLlamaCppProvider creates a new llama.cpp provider with the given config.
@@ -56,9 +57,10 @@ func (p *LlamaCppProvider) Name() string {
 	return p.model
 }
 
-// SetBatchMode is a no-op; llama.cpp does not support batch mode.
+// SetBatchMode is a no-op; local models cost the same in batch mode.
+// Batches are emulated by EnableLocalBatch.
 func (p *LlamaCppProvider) SetBatchMode(enabled bool) {
-	// llama.cpp doesn't support batch mode - no-op.
+	// No batch pricing - no-op.
 }
 
 // GetUsage returns the accumulated API token usage.
@@ -296,22 +298,52 @@ func (p *LlamaCppProvider) sendRequest(ctx context.Context, messages []llamaCppM
 	return &llamaResp, nil
 }
 
-// CreatePhotoBatch is not supported by llama.cpp and returns an error.
+// EnableLocalBatch makes the batch methods work through a LocalBatchQueue
+// stored in dir, analyzing at most concurrency photos at a time.
+func (p *LlamaCppProvider) EnableLocalBatch(dir string, concurrency int) error {
+	q, err := NewLocalBatchQueue(dir, concurrency, p.AnalyzePhoto)
+	if err != nil {
+		return err
+	}
+	p.batch = q
+	return nil
+}
+
+// StopLocalBatch stops working on a local batch without cancelling it.
+func (p *LlamaCppProvider) StopLocalBatch(batchID string) {
+	if p.batch != nil {
+		p.batch.Stop(batchID)
+	}
+}
+
+// CreatePhotoBatch queues photos in the local batch queue and returns the batch ID.
 func (p *LlamaCppProvider) CreatePhotoBatch(ctx context.Context, requests []BatchPhotoRequest) (string, error) {
-	return "", errors.New("llama.cpp does not support batch operations")
+	if p.batch == nil {
+		return "", errors.New("llama.cpp batch mode is not enabled")
+	}
+	return p.batch.Create(requests)
 }
 
-// GetBatchStatus is not supported by llama.cpp and returns an error.
+// GetBatchStatus returns the progress of a local batch.
 func (p *LlamaCppProvider) GetBatchStatus(ctx context.Context, batchID string) (*BatchStatus, error) {
-	return nil, errors.New("llama.cpp does not support batch operations")
+	if p.batch == nil {
+		return nil, errors.New("llama.cpp batch mode is not enabled")
+	}
+	return p.batch.Status(batchID)
 }
 
-// GetBatchResults is not supported by llama.cpp and returns an error.
+// GetBatchResults returns the results of a completed local batch.
 func (p *LlamaCppProvider) GetBatchResults(ctx context.Context, batchID string) ([]BatchPhotoResult, error) {
-	return nil, errors.New("llama.cpp does not support batch operations")
+	if p.batch == nil {
+		return nil, errors.New("llama.cpp batch mode is not enabled")
+	}
+	return p.batch.Results(batchID)
 }
 
-// CancelBatch is not supported by llama.cpp and returns an error.
+// CancelBatch stops a local batch.
 func (p *LlamaCppProvider) CancelBatch(ctx context.Context, batchID string) error {
-	return errors.New("llama.cpp does not support batch operations")
+	if p.batch == nil {
+		return errors.New("llama.cpp batch mode is not enabled")
+	}
+	return p.batch.Cancel(batchID)
 }
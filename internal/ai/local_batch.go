@@ -0,0 +1,408 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	localBatchInProgress = "in_progress"
+	localBatchCompleted  = "completed"
+	localBatchCancelled  = "cancelled"
+
+	// localBatchMaxAge is how long an unfinished or cancelled batch is kept
+	// on disk before NewLocalBatchQueue removes it.
+	localBatchMaxAge = 7 * 24 * time.Hour
+)
+
+// AnalyzeFunc analyzes a single photo; it matches Provider.AnalyzePhoto.
+type AnalyzeFunc func(
+	ctx context.Context, imageData []byte, metadata *PhotoMetadata,
+	availableLabels []string, estimateDate bool,
+) (*PhotoAnalysis, error)
+
+// LocalBatcher is implemented by providers without a native batch API that
+// can emulate one with a LocalBatchQueue.
+type LocalBatcher interface {
+	EnableLocalBatch(dir string, concurrency int) error
+	// StopLocalBatch stops working on a batch without cancelling it, so it
+	// can be resumed later.
+	StopLocalBatch(batchID string)
+}
+
+// DefaultLocalBatchDir returns the directory used for local batch queues when
+// none is configured.
+func DefaultLocalBatchDir() string {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "photo-sorter", "batches")
+}
+
+// LocalBatchQueue emulates a batch API for providers that analyze one photo
+// per request (Ollama, llama.cpp). Submitted requests are written to disk and
+// worked through in the background with bounded concurrency, independent of
+// the caller's context. Results are written to disk as they complete, so a
+// batch survives the client going away: querying the batch ID later, even
+// from a new process, resumes any unfinished work and returns its results.
+// A batch is removed from disk once its results are read; a cancelled batch
+// keeps only its manifest. Batches older than localBatchMaxAge are removed
+// when a queue is created.
+//
+// Layout: <dir>/<batch-id>/manifest.json, requests/<n>.json, results/<n>.json.
+type LocalBatchQueue struct {
+	dir         string
+	concurrency int
+	analyze     AnalyzeFunc
+
+	mu      sync.Mutex
+	running map[string]*localBatchRun
+}
+
+// localBatchRun tracks the workers of a batch.
+type localBatchRun struct {
+	cancel context.CancelFunc
+	done   chan struct{} // closed when the workers have exited
+}
+
+// localBatchManifest describes a submitted batch.
+type localBatchManifest struct {
+	ID        string    `json:"id"`
+	Total     int       `json:"total"`
+	Cancelled bool      `json:"cancelled"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewLocalBatchQueue creates a queue storing batches under dir and running at
+// most concurrency analyses at a time per batch. Batches older than
+// localBatchMaxAge are removed.
+func NewLocalBatchQueue(dir string, concurrency int, analyze AnalyzeFunc) (*LocalBatchQueue, error) {
+	if dir == "" {
+		dir = DefaultLocalBatchDir()
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create batch directory: %w", err)
+	}
+	q := &LocalBatchQueue{
+		dir:         dir,
+		concurrency: concurrency,
+		analyze:     analyze,
+		running:     make(map[string]*localBatchRun),
+	}
+	q.removeExpired(time.Now().Add(-localBatchMaxAge))
+	return q, nil
+}
+
+// removeExpired removes the batches created before cutoff. Directories
+// without a readable manifest are judged by their modification time.
+func (q *LocalBatchQueue) removeExpired(cutoff time.Time) {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		created := time.Time{}
+		if manifest, err := q.loadManifest(e.Name()); err == nil {
+			created = manifest.CreatedAt
+		} else if info, err := e.Info(); err == nil {
+			created = info.ModTime()
+		}
+		if created.Before(cutoff) {
+			// A batch that cannot be removed is retried next time.
+			_ = os.RemoveAll(filepath.Join(q.dir, e.Name()))
+		}
+	}
+}
+
+// Create persists the requests as a new batch, starts working on it in the
+// background and returns its ID.
+func (q *LocalBatchQueue) Create(requests []BatchPhotoRequest) (string, error) {
+	if len(requests) == 0 {
+		return "", errors.New("no requests to process")
+	}
+	id := "local-" + uuid.New().String()
+	batchDir := filepath.Join(q.dir, id)
+	for _, sub := range []string{"requests", "results"} {
+		if err := os.MkdirAll(filepath.Join(batchDir, sub), 0o750); err != nil {
+			return "", fmt.Errorf("failed to create batch directory: %w", err)
+		}
+	}
+	for i := range requests {
+		if err := writeJSONFile(q.requestPath(id, i), &requests[i]); err != nil {
+			return "", fmt.Errorf("failed to store request %d: %w", i, err)
+		}
+	}
+	manifest := localBatchManifest{ID: id, Total: len(requests), CreatedAt: time.Now()}
+	if err := writeJSONFile(q.manifestPath(id), &manifest); err != nil {
+		return "", fmt.Errorf("failed to store batch manifest: %w", err)
+	}
+	q.ensureRunning(&manifest)
+	return id, nil
+}
+
+// Status reports the progress of a batch. An unfinished batch that is not
+// being worked on (e.g. after a restart) is resumed.
+func (q *LocalBatchQueue) Status(batchID string) (*BatchStatus, error) {
+	manifest, err := q.loadManifest(batchID)
+	if err != nil {
+		return nil, err
+	}
+	results, err := q.loadResults(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &BatchStatus{ID: batchID, TotalRequests: manifest.Total}
+	for _, r := range results {
+		switch {
+		case r == nil:
+		case r.Error != "":
+			status.FailedCount++
+		default:
+			status.CompletedCount++
+		}
+	}
+	switch {
+	case manifest.Cancelled:
+		status.Status = localBatchCancelled
+	case status.CompletedCount+status.FailedCount == manifest.Total:
+		status.Status = localBatchCompleted
+	default:
+		status.Status = localBatchInProgress
+		q.ensureRunning(manifest)
+	}
+	return status, nil
+}
+
+// Results returns the results of a completed batch in request order and
+// removes the batch from disk.
+func (q *LocalBatchQueue) Results(batchID string) ([]BatchPhotoResult, error) {
+	status, err := q.Status(batchID)
+	if err != nil {
+		return nil, err
+	}
+	if status.Status != localBatchCompleted {
+		return nil, fmt.Errorf("batch is not completed, status: %s", status.Status)
+	}
+	manifest, err := q.loadManifest(batchID)
+	if err != nil {
+		return nil, err
+	}
+	results, err := q.loadResults(manifest)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]BatchPhotoResult, len(results))
+	for i, r := range results {
+		out[i] = *r
+	}
+	q.stopAndWait(batchID)
+	if err := os.RemoveAll(filepath.Join(q.dir, batchID)); err != nil {
+		return nil, fmt.Errorf("failed to remove batch: %w", err)
+	}
+	return out, nil
+}
+
+// Cancel stops a batch and removes its requests and results from disk. The
+// manifest is kept so the batch still reports its cancelled status.
+func (q *LocalBatchQueue) Cancel(batchID string) error {
+	manifest, err := q.loadManifest(batchID)
+	if err != nil {
+		return err
+	}
+	manifest.Cancelled = true
+	if err := writeJSONFile(q.manifestPath(batchID), manifest); err != nil {
+		return fmt.Errorf("failed to store batch manifest: %w", err)
+	}
+	q.stopAndWait(batchID)
+	for _, sub := range []string{"requests", "results"} {
+		if err := os.RemoveAll(filepath.Join(q.dir, batchID, sub)); err != nil {
+			return fmt.Errorf("failed to remove batch %s: %w", sub, err)
+		}
+	}
+	return nil
+}
+
+// stopAndWait stops the workers of a batch and waits until they have exited,
+// so nothing writes to the batch directory afterwards.
+func (q *LocalBatchQueue) stopAndWait(batchID string) {
+	q.mu.Lock()
+	run, ok := q.running[batchID]
+	q.mu.Unlock()
+	if !ok {
+		return
+	}
+	run.cancel()
+	<-run.done
+}
+
+// Stop stops the workers of a batch without cancelling it; unfinished
+// requests are picked up the next time its status is queried.
+func (q *LocalBatchQueue) Stop(batchID string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if run, ok := q.running[batchID]; ok {
+		run.cancel()
+	}
+}
+
+// Close stops all background work without cancelling the batches; they are
+// resumed the next time their status is queried.
+func (q *LocalBatchQueue) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, run := range q.running {
+		run.cancel()
+	}
+}
+
+// ensureRunning starts workers for a batch unless they are already running.
+func (q *LocalBatchQueue) ensureRunning(manifest *localBatchManifest) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.running[manifest.ID]; ok {
+		return
+	}
+	// The batch must outlive the request that created or polled it.
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &localBatchRun{cancel: cancel, done: make(chan struct{})}
+	q.running[manifest.ID] = run
+	go q.work(ctx, manifest, run)
+}
+
+// work processes every request of a batch that has no result yet.
+func (q *LocalBatchQueue) work(ctx context.Context, manifest *localBatchManifest, run *localBatchRun) {
+	defer close(run.done)
+	pending := make(chan int, manifest.Total)
+	for i := range manifest.Total {
+		if _, err := os.Stat(q.resultPath(manifest.ID, i)); errors.Is(err, os.ErrNotExist) {
+			pending <- i
+		}
+	}
+	close(pending)
+
+	var wg sync.WaitGroup
+	for range q.concurrency {
+		wg.Go(func() {
+			for i := range pending {
+				if ctx.Err() != nil {
+					return
+				}
+				q.processRequest(ctx, manifest.ID, i)
+			}
+		})
+	}
+	wg.Wait()
+
+	q.mu.Lock()
+	delete(q.running, manifest.ID)
+	q.mu.Unlock()
+}
+
+// processRequest analyzes one request and stores its result. Nothing is
+// stored if the batch was stopped meanwhile, so the request is retried on
+// resume.
+func (q *LocalBatchQueue) processRequest(ctx context.Context, batchID string, i int) {
+	var req BatchPhotoRequest
+	result := BatchPhotoResult{}
+	if err := readJSONFile(q.requestPath(batchID, i), &req); err != nil {
+		result.Error = fmt.Sprintf("failed to load request: %v", err)
+	} else {
+		result.PhotoUID = req.PhotoUID
+		analysis, err := q.analyze(ctx, req.ImageData, req.Metadata, req.AvailableLabels, req.EstimateDate)
+		if err != nil {
+			result.Error = err.Error()
+		}
+		result.Analysis = analysis
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	// A failed write only means the request is analyzed again on resume.
+	_ = writeJSONFile(q.resultPath(batchID, i), &result)
+}
+
+func (q *LocalBatchQueue) loadManifest(batchID string) (*localBatchManifest, error) {
+	if batchID == "" || filepath.Base(batchID) != batchID {
+		return nil, fmt.Errorf("invalid batch ID %q", batchID)
+	}
+	var manifest localBatchManifest
+	if err := readJSONFile(q.manifestPath(batchID), &manifest); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("batch %s not found", batchID)
+		}
+		return nil, fmt.Errorf("failed to load batch manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// loadResults returns the stored results indexed by request; missing ones are nil.
+func (q *LocalBatchQueue) loadResults(manifest *localBatchManifest) ([]*BatchPhotoResult, error) {
+	results := make([]*BatchPhotoResult, manifest.Total)
+	for i := range manifest.Total {
+		var r BatchPhotoResult
+		err := readJSONFile(q.resultPath(manifest.ID, i), &r)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load result %d: %w", i, err)
+		}
+		results[i] = &r
+	}
+	return results, nil
+}
+
+func (q *LocalBatchQueue) manifestPath(batchID string) string {
+	return filepath.Join(q.dir, batchID, "manifest.json")
+}
+
+func (q *LocalBatchQueue) requestPath(batchID string, i int) string {
+	return filepath.Join(q.dir, batchID, "requests", fmt.Sprintf("%06d.json", i))
+}
+
+func (q *LocalBatchQueue) resultPath(batchID string, i int) string {
+	return filepath.Join(q.dir, batchID, "results", fmt.Sprintf("%06d.json", i))
+}
+
+// writeJSONFile writes v atomically so readers never see a partial file.
+func writeJSONFile(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", filepath.Base(path), err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+func readJSONFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image/color"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeAnalyze describes each photo by its image bytes and fails for "bad".
+func fakeAnalyze(
+	_ context.Context, imageData []byte, _ *PhotoMetadata, _ []string, _ bool,
+) (*PhotoAnalysis, error) {
+	if string(imageData) == "bad" {
+		return nil, errors.New("analysis failed")
+	}
+	return &PhotoAnalysis{Description: "photo " + string(imageData)}, nil
+}
+
+func waitForBatch(t *testing.T, q *LocalBatchQueue, batchID string) *BatchStatus {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		status, err := q.Status(batchID)
+		if err != nil {
+			t.Fatalf("Status: %v", err)
+		}
+		if status.Status != localBatchInProgress {
+			return status
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("batch did not finish in time")
+	return nil
+}
+
+func batchRequests(images ...string) []BatchPhotoRequest {
+	requests := make([]BatchPhotoRequest, len(images))
+	for i, img := range images {
+		requests[i] = BatchPhotoRequest{PhotoUID: fmt.Sprintf("p%d", i), ImageData: []byte(img)}
+	}
+	return requests
+}
+
+func TestLocalBatchQueue_ProcessesBatch(t *testing.T) {
+	dir := t.TempDir()
+	q, err := NewLocalBatchQueue(dir, 2, fakeAnalyze)
+	if err != nil {
+		t.Fatalf("NewLocalBatchQueue: %v", err)
+	}
+	defer q.Close()
+
+	batchID, err := q.Create(batchRequests("a", "bad", "c"))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	status := waitForBatch(t, q, batchID)
+	if status.Status != localBatchCompleted || status.TotalRequests != 3 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+	if status.CompletedCount != 2 || status.FailedCount != 1 {
+		t.Errorf("expected 2 completed and 1 failed, got %+v", status)
+	}
+
+	results, err := q.Results(batchID)
+	if err != nil {
+		t.Fatalf("Results: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].PhotoUID != "p0" || results[0].Analysis == nil || results[0].Analysis.Description != "photo a" {
+		t.Errorf("unexpected first result: %+v", results[0])
+	}
+	if results[1].Error == "" || results[1].Analysis != nil {
+		t.Errorf("expected second result to fail, got %+v", results[1])
+	}
+	if results[2].PhotoUID != "p2" {
+		t.Errorf("expected results in request order, got %s last", results[2].PhotoUID)
+	}
+	if _, err := os.Stat(filepath.Join(dir, batchID)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected batch directory to be removed after reading results, got %v", err)
+	}
+}
+
+func TestLocalBatchQueue_ResumesAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	release := make(chan struct{})
+	defer close(release)
+
+	// The first queue never finishes a request, like a process that is killed.
+	stuck, err := NewLocalBatchQueue(dir, 1, func(
+		ctx context.Context, _ []byte, _ *PhotoMetadata, _ []string, _ bool,
+	) (*PhotoAnalysis, error) {
+		select {
+		case <-ctx.Done():
+		case <-release:
+		}
+		return nil, errors.New("interrupted")
+	})
+	if err != nil {
+		t.Fatalf("NewLocalBatchQueue: %v", err)
+	}
+	batchID, err := stuck.Create(batchRequests("a", "b"))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	stuck.Close()
+
+	var calls atomic.Int32
+	q, err := NewLocalBatchQueue(dir, 1, func(
+		ctx context.Context, imageData []byte, metadata *PhotoMetadata, labels []string, estimateDate bool,
+	) (*PhotoAnalysis, error) {
+		calls.Add(1)
+		return fakeAnalyze(ctx, imageData, metadata, labels, estimateDate)
+	})
+	if err != nil {
+		t.Fatalf("NewLocalBatchQueue: %v", err)
+	}
+	defer q.Close()
+
+	if status := waitForBatch(t, q, batchID); status.Status != localBatchCompleted {
+		t.Fatalf("expected resumed batch to complete, got %+v", status)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected both requests to be analyzed after resume, got %d calls", calls.Load())
+	}
+}
+
+func TestLocalBatchQueue_Cancel(t *testing.T) {
+	dir := t.TempDir()
+	block := make(chan struct{})
+	defer close(block)
+	q, err := NewLocalBatchQueue(dir, 1, func(
+		ctx context.Context, _ []byte, _ *PhotoMetadata, _ []string, _ bool,
+	) (*PhotoAnalysis, error) {
+		select {
+		case <-ctx.Done():
+		case <-block:
+		}
+		return &PhotoAnalysis{}, nil
+	})
+	if err != nil {
+		t.Fatalf("NewLocalBatchQueue: %v", err)
+	}
+	defer q.Close()
+
+	batchID, err := q.Create(batchRequests("a", "b"))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := q.Cancel(batchID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+
+	status, err := q.Status(batchID)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.Status != localBatchCancelled {
+		t.Errorf("expected cancelled, got %s", status.Status)
+	}
+	if _, err := q.Results(batchID); err == nil {
+		t.Error("expected results of a cancelled batch to be unavailable")
+	}
+	if _, err := os.Stat(filepath.Join(dir, batchID, "requests")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected requests of a cancelled batch to be removed, got %v", err)
+	}
+}
+
+func TestLocalBatchQueue_RemovesExpiredBatches(t *testing.T) {
+	dir := t.TempDir()
+	block := make(chan struct{})
+	defer close(block)
+	q, err := NewLocalBatchQueue(dir, 1, func(
+		ctx context.Context, _ []byte, _ *PhotoMetadata, _ []string, _ bool,
+	) (*PhotoAnalysis, error) {
+		select {
+		case <-ctx.Done():
+		case <-block:
+		}
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("NewLocalBatchQueue: %v", err)
+	}
+	oldID, err := q.Create(batchRequests("a"))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	newID, err := q.Create(batchRequests("b"))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	q.Close()
+
+	// Backdate the first batch past the maximum age.
+	manifest, err := q.loadManifest(oldID)
+	if err != nil {
+		t.Fatalf("loadManifest: %v", err)
+	}
+	manifest.CreatedAt = time.Now().Add(-localBatchMaxAge - time.Hour)
+	if err := writeJSONFile(q.manifestPath(oldID), manifest); err != nil {
+		t.Fatalf("writeJSONFile: %v", err)
+	}
+
+	if _, err := NewLocalBatchQueue(dir, 1, fakeAnalyze); err != nil {
+		t.Fatalf("NewLocalBatchQueue: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, oldID)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected expired batch to be removed, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, newID)); err != nil {
+		t.Errorf("expected recent batch to be kept, got %v", err)
+	}
+}
+
+func TestLocalBatchQueue_StopKeepsBatchResumable(t *testing.T) {
+	started := make(chan struct{})
+	var calls atomic.Int32
+	q, err := NewLocalBatchQueue(t.TempDir(), 1, func(
+		ctx context.Context, imageData []byte, metadata *PhotoMetadata, labels []string, estimateDate bool,
+	) (*PhotoAnalysis, error) {
+		// The first analysis hangs until the batch is stopped.
+		if calls.Add(1) == 1 {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return fakeAnalyze(ctx, imageData, metadata, labels, estimateDate)
+	})
+	if err != nil {
+		t.Fatalf("NewLocalBatchQueue: %v", err)
+	}
+	defer q.Close()
+
+	batchID, err := q.Create(batchRequests("a", "b"))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	<-started
+	q.Stop(batchID)
+
+	status := waitForBatch(t, q, batchID)
+	if status.Status != localBatchCompleted || status.CompletedCount != 2 {
+		t.Fatalf("expected stopped batch to resume and complete, got %+v", status)
+	}
+}
+
+func TestLocalBatchQueue_UnknownBatch(t *testing.T) {
+	q, err := NewLocalBatchQueue(t.TempDir(), 1, fakeAnalyze)
+	if err != nil {
+		t.Fatalf("NewLocalBatchQueue: %v", err)
+	}
+	for _, id := range []string{"local-missing", "../escape", ""} {
+		if _, err := q.Status(id); err == nil {
+			t.Errorf("expected error for batch ID %q", id)
+		}
+	}
+}
+
+func TestOllamaProvider_LocalBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"message":{"role":"assistant","content":"{\"labels\":[],\"description\":\"local\"}"},`+
+			`"done":true,"prompt_eval_count":10,"eval_count":5}`)
+	}))
+	defer server.Close()
+
+	p, err := NewOllamaProvider(server.URL, "llava")
+	if err != nil {
+		t.Fatalf("NewOllamaProvider: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := p.CreatePhotoBatch(ctx, nil); err == nil {
+		t.Error("expected error before local batch mode is enabled")
+	}
+
+	if err := p.EnableLocalBatch(t.TempDir(), 1); err != nil {
+		t.Fatalf("EnableLocalBatch: %v", err)
+	}
+	defer p.batch.Close()
+
+	img := encodeJPEG(createTestImage(10, 10, color.White))
+	batchID, err := p.CreatePhotoBatch(ctx, []BatchPhotoRequest{{PhotoUID: "p1", ImageData: img}})
+	if err != nil {
+		t.Fatalf("CreatePhotoBatch: %v", err)
+	}
+	waitForBatch(t, p.batch, batchID)
+
+	results, err := p.GetBatchResults(ctx, batchID)
+	if err != nil {
+		t.Fatalf("GetBatchResults: %v", err)
+	}
+	if len(results) != 1 || results[0].Analysis == nil || results[0].Analysis.Description != "local" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
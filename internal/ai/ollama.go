@@ -24,6 +24,7 @@ type OllamaProvider struct {
 	model     string
 	client    *http.Client
 	usage     Usage
+	batch     *LocalBatchQueue // emulated batch API, set by EnableLocalBatch
 }
 
 // NewOllamaProvider creates a new Ollama provider with the given config.
@@ -56,9 +57,10 @@ func (p *OllamaProvider) Name() string {
 	return p.model
 }
 
-// SetBatchMode is a no-op; Ollama does not support batch mode.
+// SetBatchMode is a no-op; local models cost the same in batch mode.
+// Batches are emulated by EnableLocalBatch.
 func (p *OllamaProvider) SetBatchMode(enabled bool) {
-	// Ollama doesn't support batch mode - no-op.
+	// No batch pricing - no-op.
 }
 
 // GetUsage returns the accumulated API token usage.
@@ -293,22 +295,52 @@ func extractJSON(content string) string {
 	return content[start:]
 }
 
-// CreatePhotoBatch is not supported by Ollama and returns an error.
+// EnableLocalBatch makes the batch methods work through a LocalBatchQueue
+// stored in dir, analyzing at most concurrency photos at a time.
+func (p *OllamaProvider) EnableLocalBatch(dir string, concurrency int) error {
+	q, err := NewLocalBatchQueue(dir, concurrency, p.AnalyzePhoto)
+	if err != nil {
+		return err
+	}
+	p.batch = q
+	return nil
+}
+
+// StopLocalBatch stops working on a local batch without cancelling it.
+func (p *OllamaProvider) StopLocalBatch(batchID string) {
+	if p.batch != nil {
+		p.batch.Stop(batchID)
+	}
+}
+
+// CreatePhotoBatch queues photos in the local batch queue and returns the batch ID.
 func (p *OllamaProvider) CreatePhotoBatch(ctx context.Context, requests []BatchPhotoRequest) (string, error) {
-	return "", errors.New("ollama does not support batch operations")
+	if p.batch == nil {
+		return "", errors.New("ollama batch mode is not enabled")
+	}
+	return p.batch.Create(requests)
 }
 
-// GetBatchStatus is not supported by Ollama and returns an error.
+// GetBatchStatus returns the progress of a local batch.
 func (p *OllamaProvider) GetBatchStatus(ctx context.Context, batchID string) (*BatchStatus, error) {
-	return nil, errors.New("ollama does not support batch operations")
+	if p.batch == nil {
+		return nil, errors.New("ollama batch mode is not enabled")
+	}
+	return p.batch.Status(batchID)
 }
 
-// GetBatchResults is not supported by Ollama and returns an error.
+// GetBatchResults returns the results of a completed local batch.
 func (p *OllamaProvider) GetBatchResults(ctx context.Context, batchID string) ([]BatchPhotoResult, error) {
-	return nil, errors.New("ollama does not support batch operations")
+	if p.batch == nil {
+		return nil, errors.New("ollama batch mode is not enabled")
+	}
+	return p.batch.Results(batchID)
 }
 
-// CancelBatch is not supported by Ollama and returns an error.
+// CancelBatch stops a local batch.
 func (p *OllamaProvider) CancelBatch(ctx context.Context, batchID string) error {
-	return errors.New("ollama does not support batch operations")
+	if p.batch == nil {
+		return errors.New("ollama batch mode is not enabled")
+	}
+	return p.batch.Cancel(batchID)
 }
@@ -0,0 +1,291 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// OpenAICompatibleProvider implements Provider for any server exposing the
+// OpenAI chat completions API (vLLM, LM Studio, LocalAI, ...). The base URL
+// is expected to include the API version prefix, e.g. http://host:8000/v1.
+type OpenAICompatibleProvider struct {
+	parsedURL   *url.URL
+	apiKey      string
+	model       string
+	client      *http.Client
+	usage       Usage
+	inputPrice  float64 // per 1M tokens
+	outputPrice float64 // per 1M tokens
+}
+
+// NewOpenAICompatibleProvider creates a provider for an OpenAI-compatible
+// endpoint. apiKey may be empty for servers that do not require auth.
+func NewOpenAICompatibleProvider(
+	baseURL, apiKey, model string, pricing RequestPricing,
+) (*OpenAICompatibleProvider, error) {
+	if baseURL == "" {
+		return nil, errors.New("OpenAI-compatible base URL is required")
+	}
+	if model == "" {
+		return nil, errors.New("OpenAI-compatible model is required")
+	}
+	parsed, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OpenAI-compatible URL: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("invalid OpenAI-compatible URL scheme %q: must be http or https", parsed.Scheme)
+	}
+	if parsed.Host == "" {
+		return nil, errors.New("invalid OpenAI-compatible URL: missing host")
+	}
+	return &OpenAICompatibleProvider{
+		parsedURL:   parsed,
+		apiKey:      apiKey,
+		model:       model,
+		client:      &http.Client{},
+		inputPrice:  pricing.Input,
+		outputPrice: pricing.Output,
+	}, nil
+}
+
+// Name returns the provider name.
+func (p *OpenAICompatibleProvider) Name() string {
+	return p.model
+}
+
+// SetBatchMode is a no-op; OpenAI-compatible servers have no batch API.
+func (p *OpenAICompatibleProvider) SetBatchMode(enabled bool) {
+	// No batch API - no-op.
+}
+
+// GetUsage returns the accumulated API token usage.
+func (p *OpenAICompatibleProvider) GetUsage() *Usage {
+	return &p.usage
+}
+
+// ResetUsage zeroes out the accumulated token usage counters.
+func (p *OpenAICompatibleProvider) ResetUsage() {
+	p.usage = Usage{}
+}
+
+func (p *OpenAICompatibleProvider) trackUsage(inputTokens, outputTokens int) {
+	p.usage.InputTokens += inputTokens
+	p.usage.OutputTokens += outputTokens
+	p.usage.TotalCost += float64(inputTokens) / 1_000_000 * p.inputPrice
+	p.usage.TotalCost += float64(outputTokens) / 1_000_000 * p.outputPrice
+}
+
+// compatRequest represents an OpenAI chat completions request.
+type compatRequest struct {
+	Model       string          `json:"model"`
+	Messages    []compatMessage `json:"messages"`
+	MaxTokens   int             `json:"max_tokens,omitempty"`
+	Temperature float64         `json:"temperature,omitempty"`
+	Stream      bool            `json:"stream"`
+}
+
+type compatMessage struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"` // string or []compatContentPart
+}
+
+type compatContentPart struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	ImageURL *compatImageURL `json:"image_url,omitempty"`
+}
+
+type compatImageURL struct {
+	URL string `json:"url"`
+}
+
+// compatResponse represents an OpenAI chat completions response.
+type compatResponse struct {
+	Choices []struct {
+		Message struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// AnalyzePhoto sends a photo to the endpoint for AI analysis and returns labels and description.
+func (p *OpenAICompatibleProvider) AnalyzePhoto(
+	ctx context.Context,
+	imageData []byte,
+	metadata *PhotoMetadata,
+	availableLabels []string,
+	estimateDate bool,
+) (*PhotoAnalysis, error) {
+	const maxRetries = 5
+
+	resizedData, err := ResizeImage(imageData, 800)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resize image: %w", err)
+	}
+
+	imageURL := "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(resizedData)
+	messages := []compatMessage{
+		{Role: "system", Content: buildPhotoAnalysisPrompt(availableLabels, estimateDate)},
+		{
+			Role: "user",
+			Content: []compatContentPart{
+				{Type: "text", Text: buildUserMessageWithMetadata(metadata)},
+				{Type: "image_url", ImageURL: &compatImageURL{URL: imageURL}},
+			},
+		},
+	}
+
+	var lastError error
+	var lastResponse string
+
+	for range maxRetries {
+		content, err := p.complete(ctx, messages)
+		if err != nil {
+			return nil, err
+		}
+		lastResponse = content
+
+		var analysis PhotoAnalysis
+		if err := json.Unmarshal([]byte(extractJSON(content)), &analysis); err != nil {
+			lastError = err
+			messages = append(messages,
+				compatMessage{Role: "assistant", Content: content},
+				compatMessage{
+					Role: "user",
+					Content: fmt.Sprintf(
+						"JSON parse error: %v. Please fix the JSON and try again."+
+							" Output ONLY valid JSON, no other text.", err,
+					),
+				},
+			)
+			continue
+		}
+
+		return &analysis, nil
+	}
+
+	return nil, fmt.Errorf(
+		"failed to parse analysis JSON after %d attempts: %w (last response: %s)",
+		maxRetries, lastError, lastResponse,
+	)
+}
+
+// EstimateAlbumDate estimates the date for a set of photos based on their descriptions.
+func (p *OpenAICompatibleProvider) EstimateAlbumDate(
+	ctx context.Context,
+	albumTitle string,
+	albumDescription string,
+	photoDescriptions []string,
+) (*AlbumDateEstimate, error) {
+	messages := []compatMessage{
+		{Role: "system", Content: buildAlbumDatePrompt()},
+		{Role: "user", Content: buildAlbumDateContent(albumTitle, albumDescription, photoDescriptions)},
+	}
+
+	content, err := p.complete(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+
+	var estimate AlbumDateEstimate
+	if err := json.Unmarshal([]byte(extractJSON(content)), &estimate); err != nil {
+		return nil, fmt.Errorf("failed to parse album date JSON: %w (response: %s)", err, content)
+	}
+
+	return &estimate, nil
+}
+
+// complete sends a chat completion request, tracks its token usage and
+// returns the content of the first choice.
+func (p *OpenAICompatibleProvider) complete(ctx context.Context, messages []compatMessage) (string, error) {
+	resp, err := p.sendRequest(ctx, messages)
+	if err != nil {
+		return "", fmt.Errorf("OpenAI-compatible API error: %w", err)
+	}
+
+	p.trackUsage(resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+
+	if len(resp.Choices) == 0 {
+		return "", errors.New("no response from OpenAI-compatible endpoint")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+func (p *OpenAICompatibleProvider) sendRequest(ctx context.Context, messages []compatMessage) (*compatResponse, error) {
+	jsonBody, err := json.Marshal(compatRequest{
+		Model:       p.model,
+		Messages:    messages,
+		MaxTokens:   500,
+		Temperature: 0.1,
+		Stream:      false,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	reqURL := p.parsedURL.JoinPath("chat/completions")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL.String(), bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var compatResp compatResponse
+	if err := json.Unmarshal(body, &compatResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &compatResp, nil
+}
+
+// CreatePhotoBatch is not supported by OpenAI-compatible endpoints and returns an error.
+func (p *OpenAICompatibleProvider) CreatePhotoBatch(ctx context.Context, requests []BatchPhotoRequest) (string, error) {
+	return "", errors.New("OpenAI-compatible provider does not support batch operations")
+}
+
+// GetBatchStatus is not supported by OpenAI-compatible endpoints and returns an error.
+func (p *OpenAICompatibleProvider) GetBatchStatus(ctx context.Context, batchID string) (*BatchStatus, error) {
+	return nil, errors.New("OpenAI-compatible provider does not support batch operations")
+}
+
+// GetBatchResults is not supported by OpenAI-compatible endpoints and returns an error.
+func (p *OpenAICompatibleProvider) GetBatchResults(ctx context.Context, batchID string) ([]BatchPhotoResult, error) {
+	return nil, errors.New("OpenAI-compatible provider does not support batch operations")
+}
+
+// CancelBatch is not supported by OpenAI-compatible endpoints and returns an error.
+func (p *OpenAICompatibleProvider) CancelBatch(ctx context.Context, batchID string) error {
+	return errors.New("OpenAI-compatible provider does not support batch operations")
+}
@@ -0,0 +1,212 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeChatServer is a minimal OpenAI-compatible chat completions server that
+// answers with canned contents in order and records the requests it received.
+type fakeChatServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	replies  []string
+	requests []compatRequest
+	authHdrs []string
+}
+
+func newFakeChatServer(t *testing.T, replies ...string) *fakeChatServer {
+	t.Helper()
+	f := &fakeChatServer{replies: replies}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		var req compatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		f.mu.Lock()
+		f.requests = append(f.requests, req)
+		f.authHdrs = append(f.authHdrs, r.Header.Get("Authorization"))
+		if len(f.replies) == 0 {
+			f.mu.Unlock()
+			http.Error(w, "no more replies", http.StatusInternalServerError)
+			return
+		}
+		reply := f.replies[0]
+		f.replies = f.replies[1:]
+		f.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"choices":[{"message":{"role":"assistant","content":%q}}],`+
+			`"usage":{"prompt_tokens":1000,"completion_tokens":200}}`, reply)
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func newTestCompatProvider(t *testing.T, server *fakeChatServer, apiKey string) *OpenAICompatibleProvider {
+	t.Helper()
+	p, err := NewOpenAICompatibleProvider(server.URL+"/v1/", apiKey, "qwen2-vl",
+		RequestPricing{Input: 1.0, Output: 2.0})
+	if err != nil {
+		t.Fatalf("NewOpenAICompatibleProvider: %v", err)
+	}
+	return p
+}
+
+func TestNewOpenAICompatibleProvider_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		model   string
+	}{
+		{"missing URL", "", "model"},
+		{"missing model", "http://localhost:8000/v1", ""},
+		{"bad scheme", "ftp://localhost/v1", "model"},
+		{"missing host", "http:///v1", "model"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewOpenAICompatibleProvider(tt.baseURL, "", tt.model, RequestPricing{}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestOpenAICompatibleProvider_AnalyzePhoto(t *testing.T) {
+	server := newFakeChatServer(t,
+		"```json\n"+`{"labels":[{"name":"beach","confidence":0.95}],"description":"A sunny beach"}`+"\n```",
+	)
+	p := newTestCompatProvider(t, server, "secret")
+
+	img := encodeJPEG(createTestImage(100, 80, color.RGBA{R: 200, G: 180, B: 90, A: 255}))
+	analysis, err := p.AnalyzePhoto(context.Background(), img,
+		&PhotoMetadata{OriginalName: "IMG_0001.jpg"}, []string{"beach", "mountain"}, false)
+	if err != nil {
+		t.Fatalf("AnalyzePhoto: %v", err)
+	}
+
+	if len(analysis.Labels) != 1 || analysis.Labels[0].Name != "beach" {
+		t.Errorf("unexpected labels: %+v", analysis.Labels)
+	}
+	if analysis.Description != "A sunny beach" {
+		t.Errorf("unexpected description: %q", analysis.Description)
+	}
+
+	if len(server.requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(server.requests))
+	}
+	req := server.requests[0]
+	if req.Model != "qwen2-vl" {
+		t.Errorf("expected model qwen2-vl, got %s", req.Model)
+	}
+	if server.authHdrs[0] != "Bearer secret" {
+		t.Errorf("expected bearer auth header, got %q", server.authHdrs[0])
+	}
+	parts, ok := req.Messages[1].Content.([]any)
+	if !ok || len(parts) != 2 {
+		t.Fatalf("expected text and image parts, got %#v", req.Messages[1].Content)
+	}
+	imagePart, _ := parts[1].(map[string]any)
+	imageURL, _ := imagePart["image_url"].(map[string]any)
+	if url, _ := imageURL["url"].(string); !strings.HasPrefix(url, "data:image/jpeg;base64,") {
+		t.Errorf("expected inline JPEG data URL, got %.40q", url)
+	}
+
+	usage := p.GetUsage()
+	if usage.InputTokens != 1000 || usage.OutputTokens != 200 {
+		t.Errorf("unexpected token usage: %+v", usage)
+	}
+	// 1000 * $1/1M + 200 * $2/1M.
+	if math.Abs(usage.TotalCost-0.0014) > 1e-9 {
+		t.Errorf("expected total cost 0.0014, got %f", usage.TotalCost)
+	}
+}
+
+func TestOpenAICompatibleProvider_AnalyzePhotoRetriesInvalidJSON(t *testing.T) {
+	server := newFakeChatServer(t,
+		"not json at all",
+		`{"labels":[],"description":"Second try"}`,
+	)
+	p := newTestCompatProvider(t, server, "")
+
+	img := encodeJPEG(createTestImage(10, 10, color.White))
+	analysis, err := p.AnalyzePhoto(context.Background(), img, &PhotoMetadata{}, nil, false)
+	if err != nil {
+		t.Fatalf("AnalyzePhoto: %v", err)
+	}
+	if analysis.Description != "Second try" {
+		t.Errorf("unexpected description: %q", analysis.Description)
+	}
+	if len(server.requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(server.requests))
+	}
+	if n := len(server.requests[1].Messages); n != 4 {
+		t.Errorf("expected retry to include the failed answer and a fix-up message, got %d messages", n)
+	}
+	if server.authHdrs[0] != "" {
+		t.Errorf("expected no auth header without API key, got %q", server.authHdrs[0])
+	}
+	if p.GetUsage().InputTokens != 2000 {
+		t.Errorf("expected usage of both attempts to be tracked, got %d", p.GetUsage().InputTokens)
+	}
+}
+
+func TestOpenAICompatibleProvider_EstimateAlbumDate(t *testing.T) {
+	server := newFakeChatServer(t,
+		`{"estimated_date":"1998-07-15","confidence":0.7,"reasoning":"Film grain and clothing"}`,
+	)
+	p := newTestCompatProvider(t, server, "")
+
+	estimate, err := p.EstimateAlbumDate(context.Background(), "Summer", "", []string{"A beach", "A boat"})
+	if err != nil {
+		t.Fatalf("EstimateAlbumDate: %v", err)
+	}
+	if estimate.EstimatedDate != "1998-07-15" || estimate.Confidence != 0.7 {
+		t.Errorf("unexpected estimate: %+v", estimate)
+	}
+
+	content, _ := server.requests[0].Messages[1].Content.(string)
+	if !strings.Contains(content, "Album title: Summer") || !strings.Contains(content, "2. A boat") {
+		t.Errorf("unexpected user content: %q", content)
+	}
+
+	p.ResetUsage()
+	if p.GetUsage().InputTokens != 0 || p.GetUsage().TotalCost != 0 {
+		t.Errorf("expected usage to be reset, got %+v", p.GetUsage())
+	}
+}
+
+func TestOpenAICompatibleProvider_APIError(t *testing.T) {
+	server := newFakeChatServer(t) // no replies: every request fails with 500
+	p := newTestCompatProvider(t, server, "")
+
+	_, err := p.EstimateAlbumDate(context.Background(), "Album", "", []string{"x"})
+	if err == nil || !strings.Contains(err.Error(), "status 500") {
+		t.Errorf("expected API error with status, got %v", err)
+	}
+}
+
+func TestOpenAICompatibleProvider_BatchUnsupported(t *testing.T) {
+	server := newFakeChatServer(t)
+	p := newTestCompatProvider(t, server, "")
+
+	if _, err := p.CreatePhotoBatch(context.Background(), nil); err == nil {
+		t.Error("expected batch creation to be unsupported")
+	}
+}
@@ -86,6 +86,8 @@ type PhotoAnalysis struct {
 	Description string `json:"description"`
 	// EstimatedDate in YYYY-MM-DD format (only set when individual dating is enabled).
 	EstimatedDate string `json:"estimated_date,omitempty"`
+	// Consensus holds the individual provider votes (only set by ConsensusProvider).
+	Consensus *Consensus `json:"consensus,omitempty"`
 }
 
 // LabelWithConfidence represents a label with its confidence score.
@@ -110,4 +112,5 @@ type SortSuggestion struct {
 	Labels        []LabelWithConfidence
 	Description   string
 	EstimatedDate string
+	Consensus     *Consensus `json:",omitempty"` // per-provider votes in consensus mode
 }
@@ -0,0 +1,80 @@
+package changes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+)
+
+// apiSource detects changes through PhotoPrism's search API.
+type apiSource struct {
+	pp       *photoprism.PhotoPrism
+	pageSize int
+}
+
+// NewAPISource returns a source that pages through PhotoPrism's search,
+// newest first, until it reaches photos older than the cursor. It detects
+// new and edited photos, but not marker changes or archived photos.
+// pageSize 0 uses photoprism.DefaultIteratePageSize.
+func NewAPISource(pp *photoprism.PhotoPrism, pageSize int) Source {
+	return &apiSource{pp: pp, pageSize: pageSize}
+}
+
+func (s *apiSource) Name() string { return "api" }
+
+func (s *apiSource) Changes(ctx context.Context, since Cursor) (*Set, error) {
+	set := &Set{Next: since}
+	seen := make(map[string]bool)
+	if err := s.scan(ctx, "added", since.PhotosAt, photoCreatedAt, set, seen); err != nil {
+		return nil, err
+	}
+	// With a zero cursor the first pass already returned every photo.
+	if !since.PhotosAt.IsZero() {
+		if err := s.scan(ctx, "edited", since.PhotosAt, photoEditedAt, set, seen); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// scan pages through photos sorted by a timestamp, newest first, and adds
+// them to the set until it reaches a photo older than since.
+func (s *apiSource) scan(
+	ctx context.Context, order string, since time.Time, stamp func(*photoprism.Photo) time.Time,
+	set *Set, seen map[string]bool,
+) error {
+	opts := photoprism.IterateOptions{PageSize: s.pageSize, Order: order}
+	for page, err := range s.pp.IteratePhotos(ctx, "", opts) {
+		if err != nil {
+			return fmt.Errorf("listing photos by %s: %w", order, err)
+		}
+		for i := range page {
+			t := stamp(&page[i])
+			if !since.IsZero() && (t.IsZero() || t.Before(since)) {
+				return nil
+			}
+			set.Next.PhotosAt = later(set.Next.PhotosAt, t)
+			if !seen[page[i].UID] {
+				seen[page[i].UID] = true
+				set.Changed = append(set.Changed, page[i].UID)
+			}
+		}
+	}
+	return nil
+}
+
+func photoCreatedAt(p *photoprism.Photo) time.Time { return parseTime(p.CreatedAt) }
+
+func photoEditedAt(p *photoprism.Photo) time.Time { return parseTime(p.EditedAt) }
+
+// parseTime parses a PhotoPrism timestamp, returning the zero time if it is
+// empty or malformed.
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,114 @@
+package changes
+
+import (
+	"context"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+	"github.com/kozaktomas/photo-sorter/internal/photoprism/fake"
+)
+
+func startFake(t *testing.T, s *fake.Server) *photoprism.PhotoPrism {
+	t.Helper()
+	server := httptest.NewServer(s.Handler())
+	t.Cleanup(server.Close)
+	pp, err := photoprism.NewPhotoPrism(server.URL, "admin", "secret")
+	if err != nil {
+		t.Fatalf("NewPhotoPrism failed: %v", err)
+	}
+	return pp
+}
+
+func TestAPISource(t *testing.T) {
+	s := fake.New(fake.Options{})
+	old := s.AddPhoto(photoprism.Photo{CreatedAt: "2024-01-01T10:00:00Z"}, nil)
+	edited := s.AddPhoto(photoprism.Photo{CreatedAt: "2024-01-02T10:00:00Z", EditedAt: "2024-03-01T08:00:00Z"}, nil)
+	recent := s.AddPhoto(photoprism.Photo{CreatedAt: "2024-02-01T10:00:00Z"}, nil)
+	newest := s.AddPhoto(photoprism.Photo{CreatedAt: "2024-02-05T10:00:00Z"}, nil)
+	source := NewAPISource(startFake(t, s), 2)
+	ctx := context.Background()
+
+	all, err := source.Changes(ctx, Cursor{})
+	if err != nil {
+		t.Fatalf("Changes failed: %v", err)
+	}
+	if len(all.Changed) != 4 || len(all.Archived) != 0 {
+		t.Errorf("expected all 4 photos for a zero cursor, got %v", all.Changed)
+	}
+	if want := time.Date(2024, time.February, 5, 10, 0, 0, 0, time.UTC); !all.Next.PhotosAt.Equal(want) {
+		t.Errorf("expected next cursor %v, got %v", want, all.Next.PhotosAt)
+	}
+
+	since := Cursor{PhotosAt: time.Date(2024, time.February, 1, 10, 0, 0, 0, time.UTC)}
+	set, err := source.Changes(ctx, since)
+	if err != nil {
+		t.Fatalf("Changes failed: %v", err)
+	}
+	got := slices.Sorted(slices.Values(set.Changed))
+	want := slices.Sorted(slices.Values([]string{newest, recent, edited}))
+	if !slices.Equal(got, want) {
+		t.Errorf("expected photos %v, got %v (old photo %s)", want, got, old)
+	}
+	if wantNext := time.Date(2024, time.March, 1, 8, 0, 0, 0, time.UTC); !set.Next.PhotosAt.Equal(wantNext) {
+		t.Errorf("expected next cursor %v, got %v", wantNext, set.Next.PhotosAt)
+	}
+
+	none, err := source.Changes(ctx, Cursor{PhotosAt: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("Changes failed: %v", err)
+	}
+	if len(none.Changed) != 0 {
+		t.Errorf("expected no changes after the newest photo, got %v", none.Changed)
+	}
+}
+
+func TestSet_Among(t *testing.T) {
+	set := &Set{Changed: []string{"p1", "p2"}, Archived: []string{"p3"}}
+	got := set.Among([]string{"p2", "p3", "p4"})
+	if !slices.Equal(got, []string{"p2", "p3"}) {
+		t.Errorf("expected [p2 p3], got %v", got)
+	}
+}
+
+// memoryCursorStore is an in-memory database.SyncCursorStore.
+type memoryCursorStore map[string]database.SyncCursor
+
+//nolint:nilnil // nil means no cursor, like the PostgreSQL store
+func (m memoryCursorStore) GetSyncCursor(_ context.Context, name string) (*database.SyncCursor, error) {
+	c, ok := m[name]
+	if !ok {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+func (m memoryCursorStore) SaveSyncCursor(_ context.Context, c *database.SyncCursor) error {
+	m[c.Name] = *c
+	return nil
+}
+
+func TestLoadSave(t *testing.T) {
+	store := memoryCursorStore{}
+	ctx := context.Background()
+
+	cursor, err := Load(ctx, store, CursorProcess)
+	if err != nil || !cursor.IsZero() {
+		t.Fatalf("expected zero cursor, got %+v (err %v)", cursor, err)
+	}
+
+	saved := Cursor{PhotosAt: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)}
+	if err := Save(ctx, store, CursorProcess, saved); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	cursor, err = Load(ctx, store, CursorProcess)
+	if err != nil || cursor != saved {
+		t.Errorf("expected %+v, got %+v (err %v)", saved, cursor, err)
+	}
+	if other, _ := Load(ctx, store, CursorCacheSync); !other.IsZero() {
+		t.Errorf("expected cursors to be independent, got %+v", other)
+	}
+}
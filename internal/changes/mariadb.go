@@ -0,0 +1,53 @@
+package changes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kozaktomas/photo-sorter/internal/database/mariadb"
+)
+
+// mariaDBSource detects changes by querying PhotoPrism's MariaDB database.
+type mariaDBSource struct {
+	pool *mariadb.Pool
+}
+
+// NewMariaDBSource returns a source that reads photo and marker timestamps
+// from PhotoPrism's database (PHOTOPRISM_DATABASE_URL). Besides new and
+// edited photos it detects marker changes and archived photos.
+func NewMariaDBSource(pool *mariadb.Pool) Source {
+	return &mariaDBSource{pool: pool}
+}
+
+func (s *mariaDBSource) Name() string { return "mariadb" }
+
+func (s *mariaDBSource) Changes(ctx context.Context, since Cursor) (*Set, error) {
+	photos, err := s.pool.ChangedPhotos(ctx, since.PhotosAt)
+	if err != nil {
+		return nil, fmt.Errorf("detecting changed photos: %w", err)
+	}
+	markers, err := s.pool.ChangedMarkers(ctx, since.MarkersAt)
+	if err != nil {
+		return nil, fmt.Errorf("detecting changed markers: %w", err)
+	}
+
+	set := &Set{Next: since}
+	seen := make(map[string]bool, len(photos))
+	for _, p := range photos {
+		set.Next.PhotosAt = later(set.Next.PhotosAt, p.ChangedAt)
+		seen[p.PhotoUID] = true
+		if p.Archived {
+			set.Archived = append(set.Archived, p.PhotoUID)
+		} else {
+			set.Changed = append(set.Changed, p.PhotoUID)
+		}
+	}
+	for _, m := range markers {
+		set.Next.MarkersAt = later(set.Next.MarkersAt, m.ChangedAt)
+		if !seen[m.PhotoUID] {
+			seen[m.PhotoUID] = true
+			set.Changed = append(set.Changed, m.PhotoUID)
+		}
+	}
+	return set, nil
+}
@@ -13,14 +13,17 @@ var pricesYAML []byte
 
 // Config holds all application configuration loaded from environment variables.
 type Config struct {
-	PhotoPrism PhotoPrismConfig
-	OpenAI     OpenAIConfig
-	Gemini     GeminiConfig
-	Ollama     OllamaConfig
-	LlamaCpp   LlamaCppConfig
-	Embedding  EmbeddingConfig
-	Database   DatabaseConfig
-	Prices     PricesConfig
+	PhotoPrism       PhotoPrismConfig
+	OpenAI           OpenAIConfig
+	Gemini           GeminiConfig
+	Ollama           OllamaConfig
+	LlamaCpp         LlamaCppConfig
+	OpenAICompatible OpenAICompatibleConfig
+	LocalBatch       LocalBatchConfig
+	Embedding        EmbeddingConfig
+	Database         DatabaseConfig
+	Scheduler        SchedulerConfig
+	Prices           PricesConfig
 }
 
 // PhotoPrismConfig holds PhotoPrism API connection settings.
@@ -75,6 +78,30 @@ type LlamaCppConfig struct {
 	Model string // defaults to llava
 }
 
+// LocalBatchConfig holds settings of the emulated batch queue used by the
+// Ollama and llama.cpp providers in batch mode.
+type LocalBatchConfig struct {
+	Dir string // defaults to <user cache dir>/photo-sorter/batches
+}
+
+// OpenAICompatibleConfig holds settings for any OpenAI-compatible chat
+// completions endpoint (vLLM, LM Studio, LocalAI, ...).
+type OpenAICompatibleConfig struct {
+	URL         string  // base URL including the version prefix, e.g. http://localhost:8000/v1
+	Model       string  // model name sent with each request
+	apiKey      string  // optional bearer token
+	InputPrice  float64 // USD per 1M input tokens; falls back to prices.yaml by model name
+	OutputPrice float64 // USD per 1M output tokens; falls back to prices.yaml by model name
+}
+
+// NewOpenAICompatibleConfig creates an OpenAICompatibleConfig with the given API key.
+func NewOpenAICompatibleConfig(url, model, apiKey string) OpenAICompatibleConfig {
+	return OpenAICompatibleConfig{URL: url, Model: model, apiKey: apiKey}
+}
+
+// GetAPIKey returns the OpenAI-compatible endpoint API key.
+func (c *OpenAICompatibleConfig) GetAPIKey() string { return c.apiKey }
+
 // EmbeddingConfig holds embeddings service connection settings.
 type EmbeddingConfig struct {
 	URL string // defaults to http://localhost:8000
@@ -90,6 +117,11 @@ type DatabaseConfig struct {
 	HNSWEmbeddingIndexPath string // Path to persist embedding HNSW index (optional, if empty index is rebuilt on startup)
 }
 
+// SchedulerConfig holds settings of the background sync run by the web server.
+type SchedulerConfig struct {
+	Schedule string // cron expression, descriptor or "@every <duration>"; empty disables the scheduler
+}
+
 // PricesConfig holds model pricing data loaded from prices.yaml.
 type PricesConfig struct {
 	Models map[string]ModelPricing `yaml:"models"`
@@ -120,6 +152,19 @@ func envInt(key string, defaultVal int) int {
 	return defaultVal
 }
 
+// envFloat reads an environment variable and parses it as a non-negative float.
+// Returns the default value if the env var is unset, empty, or invalid.
+func envFloat(key string, defaultVal float64) float64 {
+	s := os.Getenv(key)
+	if s == "" {
+		return defaultVal
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && f >= 0 {
+		return f
+	}
+	return defaultVal
+}
+
 // Load reads all configuration from environment variables and returns a Config.
 func Load() *Config {
 	var prices PricesConfig
@@ -151,6 +196,16 @@ func Load() *Config {
 			URL:   os.Getenv("LLAMACPP_URL"),
 			Model: os.Getenv("LLAMACPP_MODEL"),
 		},
+		OpenAICompatible: OpenAICompatibleConfig{
+			URL:         os.Getenv("OPENAI_COMPATIBLE_URL"),
+			Model:       os.Getenv("OPENAI_COMPATIBLE_MODEL"),
+			apiKey:      os.Getenv("OPENAI_COMPATIBLE_API_KEY"),
+			InputPrice:  envFloat("OPENAI_COMPATIBLE_INPUT_PRICE", 0),
+			OutputPrice: envFloat("OPENAI_COMPATIBLE_OUTPUT_PRICE", 0),
+		},
+		LocalBatch: LocalBatchConfig{
+			Dir: os.Getenv("LOCAL_BATCH_DIR"),
+		},
 		Embedding: EmbeddingConfig{
 			URL: os.Getenv("EMBEDDING_URL"),
 			Dim: envInt("EMBEDDING_DIM", 768),
@@ -162,6 +217,9 @@ func Load() *Config {
 			HNSWIndexPath:          os.Getenv("HNSW_INDEX_PATH"),
 			HNSWEmbeddingIndexPath: os.Getenv("HNSW_EMBEDDING_INDEX_PATH"),
 		},
+		Scheduler: SchedulerConfig{
+			Schedule: os.Getenv("SYNC_SCHEDULE"),
+		},
 		Prices: prices,
 	}
 }
@@ -174,3 +232,15 @@ func (c *Config) GetModelPricing(modelName string) ModelPricing {
 	// Return zero pricing if model not found.
 	return ModelPricing{}
 }
+
+// GetOpenAICompatiblePricing returns the per-1M-token pricing of the
+// OpenAI-compatible provider. Explicit OPENAI_COMPATIBLE_*_PRICE settings win;
+// otherwise the model's standard pricing from prices.yaml is used (zero for
+// unknown, typically self-hosted, models).
+func (c *Config) GetOpenAICompatiblePricing() RequestPricing {
+	compat := c.OpenAICompatible
+	if compat.InputPrice > 0 || compat.OutputPrice > 0 {
+		return RequestPricing{Input: compat.InputPrice, Output: compat.OutputPrice}
+	}
+	return c.GetModelPricing(compat.Model).Standard
+}
@@ -284,6 +284,55 @@ func TestLoad_OllamaConfig(t *testing.T) {
 	}
 }
 
+func TestLoad_OpenAICompatibleConfig(t *testing.T) {
+	t.Setenv("OPENAI_COMPATIBLE_URL", "http://vllm:8000/v1")
+	t.Setenv("OPENAI_COMPATIBLE_MODEL", "qwen2-vl")
+	t.Setenv("OPENAI_COMPATIBLE_API_KEY", "local-key")
+	t.Setenv("OPENAI_COMPATIBLE_INPUT_PRICE", "0.10")
+	t.Setenv("OPENAI_COMPATIBLE_OUTPUT_PRICE", "invalid")
+
+	cfg := Load()
+
+	if cfg.OpenAICompatible.URL != "http://vllm:8000/v1" {
+		t.Errorf("expected URL 'http://vllm:8000/v1', got '%s'", cfg.OpenAICompatible.URL)
+	}
+	if cfg.OpenAICompatible.Model != "qwen2-vl" {
+		t.Errorf("expected model 'qwen2-vl', got '%s'", cfg.OpenAICompatible.Model)
+	}
+	if cfg.OpenAICompatible.GetAPIKey() != "local-key" {
+		t.Errorf("expected API key 'local-key', got '%s'", cfg.OpenAICompatible.GetAPIKey())
+	}
+	if cfg.OpenAICompatible.InputPrice != 0.10 {
+		t.Errorf("expected input price 0.10, got %f", cfg.OpenAICompatible.InputPrice)
+	}
+	if cfg.OpenAICompatible.OutputPrice != 0 {
+		t.Errorf("expected invalid output price to fall back to 0, got %f", cfg.OpenAICompatible.OutputPrice)
+	}
+}
+
+func TestGetOpenAICompatiblePricing(t *testing.T) {
+	cfg := Load()
+
+	cfg.OpenAICompatible = NewOpenAICompatibleConfig("http://x/v1", "gpt-4.1-mini", "")
+	pricing := cfg.GetOpenAICompatiblePricing()
+	if pricing.Input != 0.40 || pricing.Output != 1.60 {
+		t.Errorf("expected prices.yaml fallback for known model, got %+v", pricing)
+	}
+
+	cfg.OpenAICompatible.InputPrice = 1
+	cfg.OpenAICompatible.OutputPrice = 2
+	pricing = cfg.GetOpenAICompatiblePricing()
+	if pricing.Input != 1 || pricing.Output != 2 {
+		t.Errorf("expected explicit prices to win, got %+v", pricing)
+	}
+
+	cfg.OpenAICompatible = NewOpenAICompatibleConfig("http://x/v1", "my-local-model", "")
+	pricing = cfg.GetOpenAICompatiblePricing()
+	if pricing.Input != 0 || pricing.Output != 0 {
+		t.Errorf("expected zero pricing for unknown model, got %+v", pricing)
+	}
+}
+
 func TestLoad_EmbeddingConfig(t *testing.T) {
 	t.Setenv("EMBEDDING_URL", "http://localhost:8000")
 	t.Setenv("EMBEDDING_DIM", "1024")
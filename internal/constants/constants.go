@@ -45,10 +45,11 @@ const (
 
 // AI provider constants.
 const (
-	ProviderOpenAI   = "openai"
-	ProviderGemini   = "gemini"
-	ProviderOllama   = "ollama"
-	ProviderLlamaCpp = "llamacpp"
+	ProviderOpenAI           = "openai"
+	ProviderGemini           = "gemini"
+	ProviderOllama           = "ollama"
+	ProviderLlamaCpp         = "llamacpp"
+	ProviderOpenAICompatible = "openai-compatible"
 )
 
 // Label constants.
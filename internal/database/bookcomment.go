@@ -0,0 +1,132 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Targets of a book review comment.
+const (
+	CommentTargetBook         = "book"
+	CommentTargetPage         = "page"
+	CommentTargetSlot         = "slot"
+	CommentTargetSectionPhoto = "section_photo"
+)
+
+// MaxBookCommentLength caps the length of a comment body in characters.
+const MaxBookCommentLength = 4000
+
+// BookComment is a review comment left on a book, one of its pages, a slot of
+// a page, or a photo in a section's prepick pool. Which target fields are set
+// depends on TargetType: PageID for pages, PageID and SlotIndex for slots,
+// SectionID and PhotoUID for section photos.
+type BookComment struct {
+	ID         string
+	BookID     string
+	TargetType string
+	PageID     string
+	SlotIndex  int
+	SectionID  string
+	PhotoUID   string
+	Body       string
+	AuthorUID  string     // PhotoPrism user UID of the author; empty when unknown
+	ResolvedAt *time.Time // nil = unresolved
+	ResolvedBy string     // PhotoPrism user UID of the resolver
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// IsResolved reports whether the comment has been resolved.
+func (c *BookComment) IsResolved() bool {
+	return c.ResolvedAt != nil
+}
+
+// commentTarget describes which target fields a comment target type sets.
+type commentTarget struct {
+	page, sectionPhoto bool
+	fields             string
+}
+
+var commentTargets = map[string]commentTarget{
+	CommentTargetBook:         {fields: "no page or section photo"},
+	CommentTargetPage:         {page: true, fields: "page_id"},
+	CommentTargetSlot:         {page: true, fields: "page_id and slot_index"},
+	CommentTargetSectionPhoto: {sectionPhoto: true, fields: "section_id and photo_uid"},
+}
+
+// Validate checks the comment body and that exactly the target fields of the
+// target type are set. It does not check that the target exists; see
+// CheckBookCommentTarget.
+func (c *BookComment) Validate() error {
+	body := strings.TrimSpace(c.Body)
+	if body == "" {
+		return errors.New("body is required")
+	}
+	if n := utf8.RuneCountInString(body); n > MaxBookCommentLength {
+		return fmt.Errorf("body is too long: %d characters (max %d)", n, MaxBookCommentLength)
+	}
+	target, ok := commentTargets[c.TargetType]
+	if !ok {
+		return fmt.Errorf("invalid target_type %q", c.TargetType)
+	}
+	if (c.PageID != "") != target.page ||
+		(c.SectionID != "") != target.sectionPhoto || (c.PhotoUID != "") != target.sectionPhoto {
+		return fmt.Errorf("%s comments take %s", c.TargetType, target.fields)
+	}
+	if c.SlotIndex < 0 {
+		return errors.New("slot_index must not be negative")
+	}
+	return nil
+}
+
+// CheckBookCommentTarget verifies that the target of a validated comment
+// exists and belongs to the comment's book: the page (and for slots, the slot
+// index within the page format), or the photo in the section's pool. It
+// returns an error wrapping ErrCommentTargetNotFound otherwise.
+func CheckBookCommentTarget(ctx context.Context, br BookReader, c *BookComment) error {
+	switch c.TargetType {
+	case CommentTargetPage, CommentTargetSlot:
+		return checkCommentPage(ctx, br, c)
+	case CommentTargetSectionPhoto:
+		return checkCommentSectionPhoto(ctx, br, c)
+	}
+	return nil
+}
+
+func checkCommentPage(ctx context.Context, br BookReader, c *BookComment) error {
+	page, err := br.GetPage(ctx, c.PageID)
+	if err != nil {
+		return fmt.Errorf("get page: %w", err)
+	}
+	if page == nil || page.BookID != c.BookID {
+		return fmt.Errorf("%w: page %s is not in the book", ErrCommentTargetNotFound, c.PageID)
+	}
+	if c.TargetType == CommentTargetSlot && c.SlotIndex >= page.SlotCount() {
+		return fmt.Errorf("%w: page has no slot %d", ErrCommentTargetNotFound, c.SlotIndex)
+	}
+	return nil
+}
+
+func checkCommentSectionPhoto(ctx context.Context, br BookReader, c *BookComment) error {
+	section, err := br.GetSection(ctx, c.SectionID)
+	if err != nil {
+		return fmt.Errorf("get section: %w", err)
+	}
+	if section == nil || section.BookID != c.BookID {
+		return fmt.Errorf("%w: section %s is not in the book", ErrCommentTargetNotFound, c.SectionID)
+	}
+	photos, err := br.GetSectionPhotos(ctx, c.SectionID)
+	if err != nil {
+		return fmt.Errorf("get section photos: %w", err)
+	}
+	for _, p := range photos {
+		if p.PhotoUID == c.PhotoUID {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: photo %s is not in the section", ErrCommentTargetNotFound, c.PhotoUID)
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBookCommentValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		comment BookComment
+		want    string // empty = valid
+	}{
+		{"book", BookComment{TargetType: CommentTargetBook, Body: "Pěkné"}, ""},
+		{"page", BookComment{TargetType: CommentTargetPage, PageID: "p1", Body: "x"}, ""},
+		{"slot", BookComment{TargetType: CommentTargetSlot, PageID: "p1", SlotIndex: 2, Body: "x"}, ""},
+		{"section photo", BookComment{
+			TargetType: CommentTargetSectionPhoto, SectionID: "s1", PhotoUID: "ph1", Body: "x",
+		}, ""},
+		{"blank body", BookComment{TargetType: CommentTargetBook, Body: " \n "}, "body is required"},
+		{"long body", BookComment{
+			TargetType: CommentTargetBook, Body: strings.Repeat("é", MaxBookCommentLength+1),
+		}, "body is too long"},
+		{"unknown target", BookComment{TargetType: "chapter", Body: "x"}, "invalid target_type"},
+		{"book with page", BookComment{TargetType: CommentTargetBook, PageID: "p1", Body: "x"},
+			"book comments take no page or section photo"},
+		{"slot without page", BookComment{TargetType: CommentTargetSlot, Body: "x"},
+			"slot comments take page_id and slot_index"},
+		{"section photo without photo", BookComment{
+			TargetType: CommentTargetSectionPhoto, SectionID: "s1", Body: "x",
+		}, "section_photo comments take section_id and photo_uid"},
+		{"negative slot", BookComment{TargetType: CommentTargetSlot, PageID: "p1", SlotIndex: -1, Body: "x"},
+			"slot_index must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.comment.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
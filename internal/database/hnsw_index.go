@@ -21,7 +21,15 @@ type HNSWIndexMetadata struct {
 	Version   int       `json:"version"` // For future compatibility
 }
 
-const hnswMetadataVersion = 1
+// hnswMetadataVersion is bumped when the cached face metadata changes, so
+// older caches are rebuilt. Version 2 added face quality scores.
+const hnswMetadataVersion = 2
+
+// IsCurrentVersion reports whether the cached index was written with the
+// current metadata version.
+func (m HNSWIndexMetadata) IsCurrentVersion() bool {
+	return m.Version == hnswMetadataVersion
+}
 
 // HNSWIndex wraps the HNSW graph for face embedding search.
 type HNSWIndex struct {
@@ -151,6 +159,19 @@ func (h *HNSWIndex) UpdateFaceMetadata(id int64, markerUID, subjectUID, subjectN
 	return true
 }
 
+// UpdateFaceQuality updates the quality score of a face in the idToFace map by database ID.
+// Returns true if the face was found and updated, false if not found.
+func (h *HNSWIndex) UpdateFaceQuality(id int64, quality float64) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	face, ok := h.idToFace[id]
+	if !ok {
+		return false
+	}
+	face.Quality = quality
+	return true
+}
+
 // Delete removes a face from the index (marks as deleted).
 func (h *HNSWIndex) Delete(id int64) {
 	h.mu.Lock()
@@ -298,6 +319,17 @@ func LoadHNSWMetadata(path string) (HNSWIndexMetadata, error) {
 	return metadata, nil
 }
 
+// RemoveFaceIndexFiles removes a cached face index (graph, .meta and .faces
+// files), so it is rebuilt from the database the next time it is loaded.
+func RemoveFaceIndexFiles(path string) error {
+	for _, p := range []string{path, path + ".meta", path + ".faces"} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove face index file: %w", err)
+		}
+	}
+	return nil
+}
+
 // SaveFaceMetadata saves face metadata to a .faces file for fast loading at startup.
 func SaveFaceMetadata(path string, faces []StoredFace) error {
 	facesPath := path + ".faces"
@@ -394,10 +426,7 @@ func (h *HNSWIndex) SaveWithFaceMetadata(path string, metadata HNSWIndexMetadata
 
 	if h.graph == nil && h.savedGraph == nil {
 		fmt.Printf("Face index save: no graph loaded, removing files\n")
-		_ = os.Remove(path)
-		_ = os.Remove(path + ".meta")
-		_ = os.Remove(path + ".faces")
-		return nil
+		return RemoveFaceIndexFiles(path)
 	}
 
 	if err := h.exportFaceGraph(path); err != nil {
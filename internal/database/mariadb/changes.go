@@ -0,0 +1,79 @@
+package mariadb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PhotoChange is a photo created, updated or archived in PhotoPrism.
+type PhotoChange struct {
+	PhotoUID  string
+	ChangedAt time.Time // newest of updated_at and deleted_at
+	Archived  bool
+}
+
+// ChangedPhotos returns the photos updated or archived at or after since.
+// Creating a photo sets updated_at too, so new photos are included.
+func (p *Pool) ChangedPhotos(ctx context.Context, since time.Time) ([]PhotoChange, error) {
+	query := `
+		SELECT photo_uid, GREATEST(updated_at, COALESCE(deleted_at, updated_at)), deleted_at IS NOT NULL
+		FROM photos
+		WHERE updated_at >= ? OR deleted_at >= ?
+		ORDER BY updated_at
+	`
+	rows, err := p.db.QueryContext(ctx, query, since, since)
+	if err != nil {
+		return nil, fmt.Errorf("query changed photos: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []PhotoChange
+	for rows.Next() {
+		var c PhotoChange
+		if err := rows.Scan(&c.PhotoUID, &c.ChangedAt, &c.Archived); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return changes, nil
+}
+
+// MarkerChange is a photo whose face markers changed.
+type MarkerChange struct {
+	PhotoUID  string
+	ChangedAt time.Time // newest marker updated_at of the photo
+}
+
+// ChangedMarkers returns the photos with face markers updated at or after
+// since, e.g. after a face was assigned or unassigned in PhotoPrism.
+func (p *Pool) ChangedMarkers(ctx context.Context, since time.Time) ([]MarkerChange, error) {
+	query := `
+		SELECT f.photo_uid, MAX(m.updated_at)
+		FROM markers m
+		JOIN files f ON f.file_uid = m.file_uid
+		WHERE m.marker_type = 'face' AND m.updated_at >= ?
+		GROUP BY f.photo_uid
+	`
+	rows, err := p.db.QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("query changed markers: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []MarkerChange
+	for rows.Next() {
+		var c MarkerChange
+		if err := rows.Scan(&c.PhotoUID, &c.ChangedAt); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return changes, nil
+}
@@ -7,7 +7,7 @@ import (
 	"fmt"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 )
 
 // Pool manages a MariaDB connection pool.
@@ -15,16 +15,25 @@ type Pool struct {
 	db *sql.DB
 }
 
-// NewPool creates a new MariaDB connection pool.
+// NewPool creates a new MariaDB connection pool. DATETIME columns are always
+// parsed as UTC times, which is how PhotoPrism stores them.
 func NewPool(dsn string) (*Pool, error) {
 	if dsn == "" {
 		return nil, errors.New("MariaDB DSN is required")
 	}
 
-	db, err := sql.Open("mysql", dsn)
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MariaDB DSN: %w", err)
+	}
+	cfg.ParseTime = true
+	cfg.Loc = time.UTC
+
+	connector, err := mysql.NewConnector(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open MariaDB: %w", err)
 	}
+	db := sql.OpenDB(connector)
 
 	db.SetMaxOpenConns(5)
 	db.SetMaxIdleConns(2)
@@ -2,10 +2,14 @@
 package mock
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/kozaktomas/photo-sorter/internal/database"
 	"github.com/kozaktomas/photo-sorter/internal/facematch"
@@ -399,6 +403,46 @@ func (m *MockFaceReader) GetFacesWithMarkerUID(ctx context.Context) ([]database.
 	return result, nil
 }
 
+// GetUnassignedFaces returns all faces with an embedding and no subject name,
+// ordered by photo UID and face index.
+func (m *MockFaceReader) GetUnassignedFaces(ctx context.Context) ([]database.StoredFace, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []database.StoredFace
+	for _, faces := range m.faces {
+		for _, face := range faces {
+			if len(face.Embedding) > 0 && face.SubjectName == "" {
+				result = append(result, face)
+			}
+		}
+	}
+	slices.SortFunc(result, func(a, b database.StoredFace) int {
+		return cmp.Or(strings.Compare(a.PhotoUID, b.PhotoUID), cmp.Compare(a.FaceIndex, b.FaceIndex))
+	})
+	return result, nil
+}
+
+// GetAssignedFaces returns all faces with an embedding and a subject name,
+// ordered by photo UID and face index.
+func (m *MockFaceReader) GetAssignedFaces(ctx context.Context) ([]database.StoredFace, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []database.StoredFace
+	for _, faces := range m.faces {
+		for _, face := range faces {
+			if len(face.Embedding) > 0 && face.SubjectName != "" {
+				result = append(result, face)
+			}
+		}
+	}
+	slices.SortFunc(result, func(a, b database.StoredFace) int {
+		return cmp.Or(strings.Compare(a.PhotoUID, b.PhotoUID), cmp.Compare(a.FaceIndex, b.FaceIndex))
+	})
+	return result, nil
+}
+
 // MockFaceWriter is a mock implementation of database.FaceWriter.
 type MockFaceWriter struct { //nolint:revive // Mock prefix is conventional for test doubles.
 	*MockFaceReader
@@ -408,6 +452,7 @@ type MockFaceWriter struct { //nolint:revive // Mock prefix is conventional for
 	MarkProcessedCalls   []MarkProcessedCall
 	UpdateMarkerCalls    []UpdateMarkerCall
 	UpdatePhotoInfoCalls []UpdatePhotoInfoCall
+	UpdateQualityCalls   []UpdateQualityCall
 	DeleteFacesCalls     []string
 
 	// Error injection.
@@ -415,6 +460,7 @@ type MockFaceWriter struct { //nolint:revive // Mock prefix is conventional for
 	MarkProcessedError   error
 	UpdateMarkerError    error
 	UpdatePhotoInfoError error
+	UpdateQualityError   error
 	DeleteFacesError     error
 }
 
@@ -448,6 +494,13 @@ type UpdatePhotoInfoCall struct {
 	FileUID     string
 }
 
+// UpdateQualityCall tracks an UpdateFaceQuality call.
+type UpdateQualityCall struct {
+	PhotoUID  string
+	FaceIndex int
+	Quality   float64
+}
+
 // NewMockFaceWriter creates a new mock face writer.
 func NewMockFaceWriter() *MockFaceWriter {
 	return &MockFaceWriter{
@@ -482,6 +535,8 @@ func (m *MockFaceWriter) UpdateFaceMarker(
 	if m.UpdateMarkerError != nil {
 		return m.UpdateMarkerError
 	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.UpdateMarkerCalls = append(m.UpdateMarkerCalls, UpdateMarkerCall{
 		PhotoUID:    photoUID,
 		FaceIndex:   faceIndex,
@@ -509,6 +564,24 @@ func (m *MockFaceWriter) UpdateFacePhotoInfo(
 	return nil
 }
 
+// UpdateFaceQuality updates the quality score of a face.
+func (m *MockFaceWriter) UpdateFaceQuality(ctx context.Context, photoUID string, faceIndex int, quality float64) error {
+	if m.UpdateQualityError != nil {
+		return m.UpdateQualityError
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.UpdateQualityCalls = append(m.UpdateQualityCalls, UpdateQualityCall{
+		PhotoUID: photoUID, FaceIndex: faceIndex, Quality: quality,
+	})
+	for i := range m.faces[photoUID] {
+		if m.faces[photoUID][i].FaceIndex == faceIndex {
+			m.faces[photoUID][i].Quality = quality
+		}
+	}
+	return nil
+}
+
 // DeleteFacesByPhoto removes all faces for a photo.
 func (m *MockFaceWriter) DeleteFacesByPhoto(ctx context.Context, photoUID string) ([]int64, error) {
 	if m.DeleteFacesError != nil {
@@ -565,10 +638,13 @@ type MockBookWriter struct { //nolint:revive // Mock prefix is conventional for
 	pages         map[string]*database.BookPage
 	pageSlots     map[string][]database.PageSlot            // keyed by pageID
 	memberships   map[string][]database.PhotoBookMembership // keyed by photoUID
+	pageLayouts   map[string]*database.PageLayout
+	bookComments  map[string]*database.BookComment
 
 	bookCounter    int
 	sectionCounter int
 	pageCounter    int
+	commentCounter int
 
 	// Error injection.
 	ListBooksError               error
@@ -599,6 +675,9 @@ type MockBookWriter struct { //nolint:revive // Mock prefix is conventional for
 	SwapSlotsError               error
 	UpdateSlotCropError          error
 	GetPhotoBookMembershipsError error
+	ListPageLayoutsError         error
+	ListBookCommentsError        error
+	CreateBookCommentError       error
 }
 
 // NewMockBookWriter creates a new mock book writer.
@@ -610,6 +689,8 @@ func NewMockBookWriter() *MockBookWriter {
 		pages:         make(map[string]*database.BookPage),
 		pageSlots:     make(map[string][]database.PageSlot),
 		memberships:   make(map[string][]database.PhotoBookMembership),
+		pageLayouts:   make(map[string]*database.PageLayout),
+		bookComments:  make(map[string]*database.BookComment),
 	}
 }
 
@@ -634,6 +715,13 @@ func (m *MockBookWriter) AddPage(page database.BookPage) {
 	m.pages[page.ID] = &page
 }
 
+// AddPageLayout adds a page layout to the mock store.
+func (m *MockBookWriter) AddPageLayout(layout database.PageLayout) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pageLayouts[layout.ID] = &layout
+}
+
 // SetPageSlots sets slots for a page.
 func (m *MockBookWriter) SetPageSlots(pageID string, slots []database.PageSlot) {
 	m.mu.Lock()
@@ -949,6 +1037,7 @@ func (m *MockBookWriter) GetPages(ctx context.Context, bookID string) ([]databas
 		if p.BookID == bookID {
 			page := *p
 			page.Slots = m.pageSlots[page.ID]
+			page.Layout = m.pageLayouts[page.Format]
 			result = append(result, page)
 		}
 	}
@@ -968,6 +1057,7 @@ func (m *MockBookWriter) GetPage(ctx context.Context, pageID string) (*database.
 	}
 	page := *p
 	page.Slots = m.pageSlots[page.ID]
+	page.Layout = m.pageLayouts[page.Format]
 	return &page, nil
 }
 
@@ -1382,9 +1472,439 @@ func (m *MockBookWriter) GetPhotoBookMemberships(
 	return m.memberships[photoUID], nil
 }
 
+// MockJobStore is a mock implementation of database.JobStore.
+type MockJobStore struct { //nolint:revive // Mock prefix is conventional for test doubles.
+	mu   sync.RWMutex
+	jobs map[string]*database.StoredJob
+
+	// Error injection.
+	SaveJobError  error
+	GetJobError   error
+	ListJobsError error
+}
+
+// NewMockJobStore creates a new mock job store.
+func NewMockJobStore() *MockJobStore {
+	return &MockJobStore{
+		jobs: make(map[string]*database.StoredJob),
+	}
+}
+
+// SaveJob upserts a job snapshot.
+func (m *MockJobStore) SaveJob(ctx context.Context, job *database.StoredJob) error {
+	if m.SaveJobError != nil {
+		return m.SaveJobError
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *job
+	m.jobs[job.ID] = &stored
+	return nil
+}
+
+// GetJob retrieves a job by ID.
+func (m *MockJobStore) GetJob(ctx context.Context, id string) (*database.StoredJob, error) {
+	if m.GetJobError != nil {
+		return nil, m.GetJobError
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, nil
+	}
+	result := *job
+	return &result, nil
+}
+
+// ListJobs returns jobs matching the filter, newest first.
+func (m *MockJobStore) ListJobs(ctx context.Context, filter database.JobFilter) ([]database.StoredJob, error) {
+	if m.ListJobsError != nil {
+		return nil, m.ListJobsError
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var result []database.StoredJob
+	for _, job := range m.jobs {
+		if len(filter.Types) > 0 && !slices.Contains(filter.Types, job.Type) {
+			continue
+		}
+		if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, job.Status) {
+			continue
+		}
+		if !filter.StartedAfter.IsZero() && job.StartedAt.Before(filter.StartedAfter) {
+			continue
+		}
+		if !filter.StartedBefore.IsZero() && !job.StartedAt.Before(filter.StartedBefore) {
+			continue
+		}
+		result = append(result, *job)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].StartedAt.After(result[j].StartedAt)
+	})
+	if filter.Offset > 0 {
+		result = result[min(filter.Offset, len(result)):]
+	}
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
+// MockSortUndoStore is a mock implementation of database.SortUndoStore.
+type MockSortUndoStore struct { //nolint:revive // Mock prefix is conventional for test doubles.
+	mu      sync.RWMutex
+	changes []database.SortChange
+	nextID  int64
+
+	// Error injection.
+	SaveSortChangeError         error
+	ListSortChangesError        error
+	MarkSortChangeRevertedError error
+}
+
+// NewMockSortUndoStore creates a new mock sort undo store.
+func NewMockSortUndoStore() *MockSortUndoStore {
+	return &MockSortUndoStore{nextID: 1}
+}
+
+// SaveSortChange records a photo's prior state, keeping the first per (run, photo).
+func (m *MockSortUndoStore) SaveSortChange(ctx context.Context, change *database.SortChange) error {
+	if m.SaveSortChangeError != nil {
+		return m.SaveSortChangeError
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, c := range m.changes {
+		if c.RunID == change.RunID && c.PhotoUID == change.PhotoUID {
+			return nil
+		}
+	}
+	stored := *change
+	stored.ID = m.nextID
+	stored.CreatedAt = time.Now()
+	m.nextID++
+	m.changes = append(m.changes, stored)
+	return nil
+}
+
+// ListSortChanges returns all changes recorded for a run, oldest first.
+func (m *MockSortUndoStore) ListSortChanges(ctx context.Context, runID string) ([]database.SortChange, error) {
+	if m.ListSortChangesError != nil {
+		return nil, m.ListSortChangesError
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var result []database.SortChange
+	for _, c := range m.changes {
+		if c.RunID == runID {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+// MarkSortChangeReverted stamps a change as reverted.
+func (m *MockSortUndoStore) MarkSortChangeReverted(ctx context.Context, id int64) error {
+	if m.MarkSortChangeRevertedError != nil {
+		return m.MarkSortChangeRevertedError
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.changes {
+		if m.changes[i].ID == id {
+			now := time.Now()
+			m.changes[i].RevertedAt = &now
+		}
+	}
+	return nil
+}
+
+// MockAnalysisCache is a mock implementation of database.AnalysisCache.
+type MockAnalysisCache struct { //nolint:revive // Mock prefix is conventional for test doubles.
+	mu      sync.RWMutex
+	entries map[string]database.CachedAnalysis
+
+	// Error injection.
+	GetCachedAnalysisError  error
+	SaveCachedAnalysisError error
+}
+
+// NewMockAnalysisCache creates a new mock analysis cache.
+func NewMockAnalysisCache() *MockAnalysisCache {
+	return &MockAnalysisCache{entries: make(map[string]database.CachedAnalysis)}
+}
+
+// GetCachedAnalysis returns the analysis stored under key, or nil if none.
+func (m *MockAnalysisCache) GetCachedAnalysis(ctx context.Context, key string) (*database.CachedAnalysis, error) {
+	if m.GetCachedAnalysisError != nil {
+		return nil, m.GetCachedAnalysisError
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+// SaveCachedAnalysis stores (or replaces) an analysis.
+func (m *MockAnalysisCache) SaveCachedAnalysis(ctx context.Context, entry *database.CachedAnalysis) error {
+	if m.SaveCachedAnalysisError != nil {
+		return m.SaveCachedAnalysisError
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *entry
+	stored.CreatedAt = time.Now()
+	m.entries[entry.Key] = stored
+	return nil
+}
+
+// Len returns the number of cached analyses.
+func (m *MockAnalysisCache) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.entries)
+}
+
+// MockLabelTaxonomyStore is a mock implementation of database.LabelTaxonomyStore.
+type MockLabelTaxonomyStore struct { //nolint:revive // Mock prefix is conventional for test doubles.
+	mu    sync.RWMutex
+	terms map[string]database.LabelTerm
+
+	// Error injection.
+	ListLabelTermsError  error
+	SaveLabelTermsError  error
+	DeleteLabelTermError error
+}
+
+// NewMockLabelTaxonomyStore creates a new mock label taxonomy store.
+func NewMockLabelTaxonomyStore() *MockLabelTaxonomyStore {
+	return &MockLabelTaxonomyStore{terms: make(map[string]database.LabelTerm)}
+}
+
+// ListLabelTerms returns all terms ordered by name.
+func (m *MockLabelTaxonomyStore) ListLabelTerms(ctx context.Context) ([]database.LabelTerm, error) {
+	if m.ListLabelTermsError != nil {
+		return nil, m.ListLabelTermsError
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make([]database.LabelTerm, 0, len(m.terms))
+	for _, t := range m.terms {
+		result = append(result, t)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// SaveLabelTerms inserts or replaces terms.
+func (m *MockLabelTaxonomyStore) SaveLabelTerms(ctx context.Context, terms []database.LabelTerm) error {
+	if m.SaveLabelTermsError != nil {
+		return m.SaveLabelTermsError
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for _, t := range terms {
+		if existing, ok := m.terms[t.Name]; ok {
+			t.CreatedAt = existing.CreatedAt
+		} else {
+			t.CreatedAt = now
+		}
+		t.UpdatedAt = now
+		m.terms[t.Name] = t
+	}
+	return nil
+}
+
+// DeleteLabelTerm removes a term; its children lose their parent.
+func (m *MockLabelTaxonomyStore) DeleteLabelTerm(ctx context.Context, name string) (bool, error) {
+	if m.DeleteLabelTermError != nil {
+		return false, m.DeleteLabelTermError
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.terms[name]; !ok {
+		return false, nil
+	}
+	delete(m.terms, name)
+	for key, t := range m.terms {
+		if t.Parent == name {
+			t.Parent = ""
+			m.terms[key] = t
+		}
+	}
+	return true, nil
+}
+
 // Verify interface compliance.
 var _ database.EmbeddingReader = (*MockEmbeddingReader)(nil)
 var _ database.EmbeddingWriter = (*MockEmbeddingWriter)(nil)
 var _ database.FaceReader = (*MockFaceReader)(nil)
 var _ database.FaceWriter = (*MockFaceWriter)(nil)
 var _ database.BookWriter = (*MockBookWriter)(nil)
+var _ database.JobStore = (*MockJobStore)(nil)
+var _ database.SortUndoStore = (*MockSortUndoStore)(nil)
+var _ database.AnalysisCache = (*MockAnalysisCache)(nil)
+var _ database.LabelTaxonomyStore = (*MockLabelTaxonomyStore)(nil)
+
+// ListPageLayouts returns all page layouts from the mock store ordered by name.
+func (m *MockBookWriter) ListPageLayouts(_ context.Context) ([]database.PageLayout, error) {
+	if m.ListPageLayoutsError != nil {
+		return nil, m.ListPageLayoutsError
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make([]database.PageLayout, 0, len(m.pageLayouts))
+	for _, l := range m.pageLayouts {
+		result = append(result, *l)
+	}
+	slices.SortFunc(result, func(a, b database.PageLayout) int { return cmp.Compare(a.Name, b.Name) })
+	return result, nil
+}
+
+// GetPageLayout returns a page layout by ID, or nil if it does not exist.
+func (m *MockBookWriter) GetPageLayout(_ context.Context, id string) (*database.PageLayout, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	l, ok := m.pageLayouts[id]
+	if !ok {
+		return nil, nil
+	}
+	layout := *l
+	return &layout, nil
+}
+
+// CreatePageLayout adds a page layout, failing when the ID is taken.
+func (m *MockBookWriter) CreatePageLayout(_ context.Context, layout *database.PageLayout) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.pageLayouts[layout.ID]; ok {
+		return database.ErrPageLayoutExists
+	}
+	stored := *layout
+	m.pageLayouts[layout.ID] = &stored
+	return nil
+}
+
+// UpdatePageLayout replaces a page layout, refusing to change the slot count
+// of a layout that pages use.
+func (m *MockBookWriter) UpdatePageLayout(_ context.Context, layout *database.PageLayout) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	old, ok := m.pageLayouts[layout.ID]
+	if !ok {
+		return database.ErrPageLayoutNotFound
+	}
+	if old.SlotCount() != layout.SlotCount() && m.pageLayoutInUse(layout.ID) {
+		return database.ErrPageLayoutInUse
+	}
+	stored := *layout
+	m.pageLayouts[layout.ID] = &stored
+	return nil
+}
+
+// DeletePageLayout removes a page layout that no page uses.
+func (m *MockBookWriter) DeletePageLayout(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.pageLayoutInUse(id) {
+		return database.ErrPageLayoutInUse
+	}
+	delete(m.pageLayouts, id)
+	return nil
+}
+
+// pageLayoutInUse reports whether any page uses the layout. The caller must hold the lock.
+func (m *MockBookWriter) pageLayoutInUse(id string) bool {
+	for _, p := range m.pages {
+		if p.Format == id {
+			return true
+		}
+	}
+	return false
+}
+
+// AddBookComment adds a comment to the mock store.
+func (m *MockBookWriter) AddBookComment(comment database.BookComment) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bookComments[comment.ID] = &comment
+}
+
+// ListBookComments returns the comments of a book ordered by creation time.
+func (m *MockBookWriter) ListBookComments(
+	_ context.Context, bookID string, unresolvedOnly bool,
+) ([]database.BookComment, error) {
+	if m.ListBookCommentsError != nil {
+		return nil, m.ListBookCommentsError
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var result []database.BookComment
+	for _, c := range m.bookComments {
+		if c.BookID == bookID && (!unresolvedOnly || !c.IsResolved()) {
+			result = append(result, *c)
+		}
+	}
+	slices.SortFunc(result, func(a, b database.BookComment) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return result, nil
+}
+
+// GetBookComment returns a comment by ID, or nil if it does not exist.
+func (m *MockBookWriter) GetBookComment(_ context.Context, id string) (*database.BookComment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	c, ok := m.bookComments[id]
+	if !ok {
+		return nil, nil
+	}
+	comment := *c
+	return &comment, nil
+}
+
+// CreateBookComment adds a comment to the mock store and assigns it a generated ID.
+func (m *MockBookWriter) CreateBookComment(_ context.Context, comment *database.BookComment) error {
+	if m.CreateBookCommentError != nil {
+		return m.CreateBookCommentError
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.commentCounter++
+	comment.ID = fmt.Sprintf("comment-%d", m.commentCounter)
+	comment.CreatedAt = time.Now()
+	comment.UpdatedAt = comment.CreatedAt
+	stored := *comment
+	m.bookComments[comment.ID] = &stored
+	return nil
+}
+
+// SetBookCommentResolved resolves or reopens a comment in the mock store.
+func (m *MockBookWriter) SetBookCommentResolved(_ context.Context, id string, resolved bool, resolvedBy string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.bookComments[id]
+	if !ok {
+		return database.ErrBookCommentNotFound
+	}
+	c.ResolvedAt, c.ResolvedBy = nil, ""
+	if resolved {
+		now := time.Now()
+		c.ResolvedAt, c.ResolvedBy = &now, resolvedBy
+	}
+	return nil
+}
+
+// DeleteBookComment removes a comment from the mock store.
+func (m *MockBookWriter) DeleteBookComment(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.bookComments, id)
+	return nil
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Page layout grid limits. Layout cells are placed on the 12 columns of the
+// page canvas and on up to MaxPageLayoutRows equal rows.
+const (
+	PageLayoutGridColumns = 12
+	MaxPageLayoutRows     = 6
+	MaxPageLayoutSlots    = 12
+)
+
+// pageLayoutIDRe matches layout IDs. Pages store the ID in their format
+// column, which holds up to 20 characters.
+var pageLayoutIDRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_]{0,19}$`)
+
+// PageLayoutCell places one slot of a page layout on the grid. Columns and
+// rows are 0-indexed; spans are at least 1.
+type PageLayoutCell struct {
+	Col     int `json:"col"`
+	ColSpan int `json:"col_span"`
+	Row     int `json:"row"`
+	RowSpan int `json:"row_span"`
+}
+
+// PageLayout is a user-defined page format: slots placed on the 12-column
+// grid of the page canvas, whose height is split into Rows equal rows. Pages
+// use a layout by setting their format to the layout ID; slots are numbered
+// in the order of Cells.
+type PageLayout struct {
+	ID          string
+	Name        string
+	Description string
+	Rows        int
+	Cells       []PageLayoutCell
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// IsBuiltinPageFormat reports whether format is one of the built-in formats.
+func IsBuiltinPageFormat(format string) bool {
+	return PageFormatSlotCount(format) > 0
+}
+
+// Validate checks the layout ID, name and grid: every cell must lie within
+// the grid and no two cells may overlap.
+func (l *PageLayout) Validate() error {
+	if !pageLayoutIDRe.MatchString(l.ID) {
+		return errors.New("id must be 1-20 lowercase letters, digits or underscores")
+	}
+	if IsBuiltinPageFormat(l.ID) {
+		return fmt.Errorf("id %q is a built-in format", l.ID)
+	}
+	if l.Name == "" {
+		return errors.New("name is required")
+	}
+	if l.Rows < 1 || l.Rows > MaxPageLayoutRows {
+		return fmt.Errorf("rows must be between 1 and %d", MaxPageLayoutRows)
+	}
+	if len(l.Cells) == 0 || len(l.Cells) > MaxPageLayoutSlots {
+		return fmt.Errorf("layout must have between 1 and %d cells", MaxPageLayoutSlots)
+	}
+
+	var used [MaxPageLayoutRows][PageLayoutGridColumns]bool
+	for i, c := range l.Cells {
+		if c.ColSpan < 1 || c.RowSpan < 1 || c.Col < 0 || c.Row < 0 ||
+			c.Col+c.ColSpan > PageLayoutGridColumns || c.Row+c.RowSpan > l.Rows {
+			return fmt.Errorf("cell %d is outside the %dx%d grid", i, PageLayoutGridColumns, l.Rows)
+		}
+		for row := c.Row; row < c.Row+c.RowSpan; row++ {
+			for col := c.Col; col < c.Col+c.ColSpan; col++ {
+				if used[row][col] {
+					return fmt.Errorf("cell %d overlaps another cell", i)
+				}
+				used[row][col] = true
+			}
+		}
+	}
+	return nil
+}
+
+// SlotCount returns the number of slots of the layout.
+func (l *PageLayout) SlotCount() int {
+	return len(l.Cells)
+}
+
+// OnLeftEdge reports whether the slot touches the left page margin.
+func (l *PageLayout) OnLeftEdge(slot int) bool {
+	return slot >= 0 && slot < len(l.Cells) && l.Cells[slot].Col == 0
+}
+
+// OnRightEdge reports whether the slot touches the right page margin.
+func (l *PageLayout) OnRightEdge(slot int) bool {
+	if slot < 0 || slot >= len(l.Cells) {
+		return false
+	}
+	c := l.Cells[slot]
+	return c.Col+c.ColSpan == PageLayoutGridColumns
+}
+
+// LeftNeighbors returns the slots directly left of the slot that share at
+// least one grid row with it.
+func (l *PageLayout) LeftNeighbors(slot int) []int {
+	if slot < 0 || slot >= len(l.Cells) {
+		return nil
+	}
+	c := l.Cells[slot]
+	var result []int
+	for i, o := range l.Cells {
+		if o.Col+o.ColSpan == c.Col && rowsOverlap(o, c) {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// RightNeighbors returns the slots directly right of the slot that share at
+// least one grid row with it.
+func (l *PageLayout) RightNeighbors(slot int) []int {
+	if slot < 0 || slot >= len(l.Cells) {
+		return nil
+	}
+	c := l.Cells[slot]
+	var result []int
+	for i, o := range l.Cells {
+		if c.Col+c.ColSpan == o.Col && rowsOverlap(o, c) {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// rowsOverlap reports whether two cells share at least one grid row.
+func rowsOverlap(a, b PageLayoutCell) bool {
+	return a.Row < b.Row+b.RowSpan && b.Row < a.Row+a.RowSpan
+}
+
+// SlotCount returns the number of slots of the page: those of its layout for
+// custom formats, otherwise those of its built-in format.
+func (p *BookPage) SlotCount() int {
+	if p.Layout != nil {
+		return p.Layout.SlotCount()
+	}
+	return PageFormatSlotCount(p.Format)
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// threeUp has a tall slot on the left and two stacked slots on the right.
+var threeUp = PageLayout{
+	ID: "3_up", Name: "3-up", Rows: 2,
+	Cells: []PageLayoutCell{
+		{Col: 0, ColSpan: 6, Row: 0, RowSpan: 2},
+		{Col: 6, ColSpan: 6, Row: 0, RowSpan: 1},
+		{Col: 6, ColSpan: 6, Row: 1, RowSpan: 1},
+	},
+}
+
+func TestPageLayoutValidate(t *testing.T) {
+	valid := threeUp
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate() = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(l *PageLayout)
+		want   string
+	}{
+		{"empty id", func(l *PageLayout) { l.ID = "" }, "id must be"},
+		{"invalid id", func(l *PageLayout) { l.ID = "Three Up" }, "id must be"},
+		{"long id", func(l *PageLayout) { l.ID = strings.Repeat("a", 21) }, "id must be"},
+		{"built-in id", func(l *PageLayout) { l.ID = "4_landscape" }, "built-in format"},
+		{"no name", func(l *PageLayout) { l.Name = "" }, "name is required"},
+		{"no rows", func(l *PageLayout) { l.Rows = 0 }, "rows must be"},
+		{"too many rows", func(l *PageLayout) { l.Rows = MaxPageLayoutRows + 1 }, "rows must be"},
+		{"no cells", func(l *PageLayout) { l.Cells = nil }, "between 1 and"},
+		{"past last column", func(l *PageLayout) { l.Cells[1].ColSpan = 7 }, "outside"},
+		{"past last row", func(l *PageLayout) { l.Cells[2].RowSpan = 2 }, "outside"},
+		{"zero span", func(l *PageLayout) { l.Cells[0].ColSpan = 0 }, "outside"},
+		{"negative column", func(l *PageLayout) { l.Cells[0].Col = -1 }, "outside"},
+		{"overlap", func(l *PageLayout) { l.Cells[1].Col = 5 }, "overlaps"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := threeUp
+			l.Cells = slices.Clone(threeUp.Cells)
+			tt.modify(&l)
+			err := l.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageLayoutEdges(t *testing.T) {
+	l := &threeUp
+	tests := []struct {
+		slot        int
+		left, right bool
+		leftN       []int
+		rightN      []int
+	}{
+		{slot: 0, left: true, rightN: []int{1, 2}},
+		{slot: 1, right: true, leftN: []int{0}},
+		{slot: 2, right: true, leftN: []int{0}},
+		{slot: 3},
+	}
+	for _, tt := range tests {
+		if got := l.OnLeftEdge(tt.slot); got != tt.left {
+			t.Errorf("OnLeftEdge(%d) = %v, want %v", tt.slot, got, tt.left)
+		}
+		if got := l.OnRightEdge(tt.slot); got != tt.right {
+			t.Errorf("OnRightEdge(%d) = %v, want %v", tt.slot, got, tt.right)
+		}
+		if got := l.LeftNeighbors(tt.slot); !slices.Equal(got, tt.leftN) {
+			t.Errorf("LeftNeighbors(%d) = %v, want %v", tt.slot, got, tt.leftN)
+		}
+		if got := l.RightNeighbors(tt.slot); !slices.Equal(got, tt.rightN) {
+			t.Errorf("RightNeighbors(%d) = %v, want %v", tt.slot, got, tt.rightN)
+		}
+	}
+}
+
+func TestBookPageSlotCount(t *testing.T) {
+	if got := (&BookPage{Format: "4_landscape"}).SlotCount(); got != 4 {
+		t.Errorf("built-in SlotCount() = %d, want 4", got)
+	}
+	if got := (&BookPage{Format: "3_up", Layout: &threeUp}).SlotCount(); got != 3 {
+		t.Errorf("layout SlotCount() = %d, want 3", got)
+	}
+	if got := (&BookPage{Format: "3_up"}).SlotCount(); got != 0 {
+		t.Errorf("unresolved layout SlotCount() = %d, want 0", got)
+	}
+}
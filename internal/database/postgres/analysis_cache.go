@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+// AnalysisCacheRepository provides PostgreSQL-backed storage for cached AI photo analyses.
+type AnalysisCacheRepository struct {
+	pool *Pool
+}
+
+// NewAnalysisCacheRepository creates a new analysis cache repository.
+func NewAnalysisCacheRepository(pool *Pool) *AnalysisCacheRepository {
+	return &AnalysisCacheRepository{pool: pool}
+}
+
+// GetCachedAnalysis returns the analysis stored under key, or nil if none.
+func (r *AnalysisCacheRepository) GetCachedAnalysis(
+	ctx context.Context, key string,
+) (*database.CachedAnalysis, error) {
+	var entry database.CachedAnalysis
+	var analysis []byte
+	err := r.pool.QueryRow(ctx, `
+		SELECT cache_key, image_hash, provider, model, prompt_version, labels_hash, analysis, created_at
+		FROM analysis_cache
+		WHERE cache_key = $1`, key,
+	).Scan(
+		&entry.Key, &entry.ImageHash, &entry.Provider, &entry.Model,
+		&entry.PromptVersion, &entry.LabelsHash, &analysis, &entry.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get cached analysis: %w", err)
+	}
+	entry.Analysis = analysis
+	return &entry, nil
+}
+
+// SaveCachedAnalysis stores (or replaces) an analysis.
+func (r *AnalysisCacheRepository) SaveCachedAnalysis(ctx context.Context, entry *database.CachedAnalysis) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO analysis_cache
+		  (cache_key, image_hash, provider, model, prompt_version, labels_hash, analysis)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (cache_key)
+		DO UPDATE SET analysis = EXCLUDED.analysis, created_at = NOW()`,
+		entry.Key, entry.ImageHash, entry.Provider, entry.Model,
+		entry.PromptVersion, entry.LabelsHash, []byte(entry.Analysis),
+	)
+	if err != nil {
+		return fmt.Errorf("save cached analysis: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+func TestAnalysisCacheRepository(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	if pool == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewAnalysisCacheRepository(pool)
+
+	entry, err := repo.GetCachedAnalysis(ctx, "missing")
+	if err != nil {
+		t.Fatalf("GetCachedAnalysis (miss): %v", err)
+	}
+	if entry != nil {
+		t.Fatalf("expected nil on cache miss, got %+v", entry)
+	}
+
+	saved := &database.CachedAnalysis{
+		Key:           "key-1",
+		ImageHash:     "img",
+		Provider:      "openai",
+		Model:         "gpt-4.1-mini",
+		PromptVersion: "v1",
+		LabelsHash:    "labels",
+		Analysis:      json.RawMessage(`{"description":"first"}`),
+	}
+	if err := repo.SaveCachedAnalysis(ctx, saved); err != nil {
+		t.Fatalf("SaveCachedAnalysis: %v", err)
+	}
+	saved.Analysis = json.RawMessage(`{"description":"second"}`)
+	if err := repo.SaveCachedAnalysis(ctx, saved); err != nil {
+		t.Fatalf("SaveCachedAnalysis (replace): %v", err)
+	}
+
+	entry, err = repo.GetCachedAnalysis(ctx, "key-1")
+	if err != nil {
+		t.Fatalf("GetCachedAnalysis: %v", err)
+	}
+	if entry == nil {
+		t.Fatal("expected cached analysis")
+	}
+	var analysis map[string]string
+	if err := json.Unmarshal(entry.Analysis, &analysis); err != nil {
+		t.Fatalf("unmarshal analysis: %v", err)
+	}
+	if analysis["description"] != "second" {
+		t.Errorf("expected replaced analysis, got %v", analysis)
+	}
+	if entry.Provider != "openai" || entry.Model != "gpt-4.1-mini" || entry.ImageHash != "img" {
+		t.Errorf("unexpected entry metadata: %+v", entry)
+	}
+}
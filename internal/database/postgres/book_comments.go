@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+// --- Book comments ---
+
+const bookCommentColumns = `id, book_id, target_type, page_id, slot_index, section_id, photo_uid,
+	body, author_uid, resolved_at, resolved_by, created_at, updated_at`
+
+// scanBookComment scans a book_comments row selected by bookCommentColumns.
+func scanBookComment(scan func(dest ...any) error) (*database.BookComment, error) {
+	var c database.BookComment
+	var pageID, sectionID, photoUID sql.NullString
+	var slotIndex sql.NullInt64
+	var resolvedAt sql.NullTime
+	if err := scan(&c.ID, &c.BookID, &c.TargetType, &pageID, &slotIndex, &sectionID, &photoUID,
+		&c.Body, &c.AuthorUID, &resolvedAt, &c.ResolvedBy, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("scan book comment: %w", err)
+	}
+	c.PageID = pageID.String
+	c.SlotIndex = int(slotIndex.Int64)
+	c.SectionID = sectionID.String
+	c.PhotoUID = photoUID.String
+	if resolvedAt.Valid {
+		c.ResolvedAt = &resolvedAt.Time
+	}
+	return &c, nil
+}
+
+// ListBookComments returns the comments of a book ordered by creation time,
+// optionally only the unresolved ones.
+func (r *BookRepository) ListBookComments(
+	ctx context.Context, bookID string, unresolvedOnly bool,
+) ([]database.BookComment, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+bookCommentColumns+` FROM book_comments
+		 WHERE book_id = $1 AND (NOT $2 OR resolved_at IS NULL)
+		 ORDER BY created_at, id`, bookID, unresolvedOnly)
+	if err != nil {
+		return nil, fmt.Errorf("list book comments: %w", err)
+	}
+	defer rows.Close()
+
+	var comments []database.BookComment
+	for rows.Next() {
+		c, err := scanBookComment(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, *c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate book comments: %w", err)
+	}
+	return comments, nil
+}
+
+// GetBookComment retrieves a book comment by ID, or nil if it does not exist.
+func (r *BookRepository) GetBookComment(ctx context.Context, id string) (*database.BookComment, error) {
+	c, err := scanBookComment(
+		r.pool.QueryRow(ctx, `SELECT `+bookCommentColumns+` FROM book_comments WHERE id = $1`, id).Scan,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get book comment: %w", err)
+	}
+	return c, nil
+}
+
+// CreateBookComment inserts a new comment and populates its ID. Only the
+// target columns of the comment's target type are stored; the others are NULL.
+func (r *BookRepository) CreateBookComment(ctx context.Context, comment *database.BookComment) error {
+	if comment.ID == "" {
+		comment.ID = newID()
+	}
+	now := time.Now()
+	comment.CreatedAt = now
+	comment.UpdatedAt = now
+
+	var slotIndex *int
+	if comment.TargetType == database.CommentTargetSlot {
+		slotIndex = &comment.SlotIndex
+	}
+
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO book_comments (id, book_id, target_type, page_id, slot_index, section_id, photo_uid,
+		                            body, author_uid, created_at, updated_at)
+		 VALUES ($1, $2, $3, NULLIF($4, ''), $5, NULLIF($6, ''), NULLIF($7, ''), $8, $9, $10, $11)`,
+		comment.ID, comment.BookID, comment.TargetType, comment.PageID, slotIndex,
+		comment.SectionID, comment.PhotoUID, comment.Body, comment.AuthorUID, comment.CreatedAt, comment.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("create book comment: %w", err)
+	}
+	return nil
+}
+
+// SetBookCommentResolved marks a comment resolved by resolvedBy, or reopens
+// it. It returns database.ErrBookCommentNotFound when the comment does not
+// exist.
+func (r *BookRepository) SetBookCommentResolved(
+	ctx context.Context, id string, resolved bool, resolvedBy string,
+) error {
+	var resolvedAt *time.Time
+	now := time.Now()
+	if resolved {
+		resolvedAt = &now
+	} else {
+		resolvedBy = ""
+	}
+	res, err := r.pool.Exec(ctx,
+		`UPDATE book_comments SET resolved_at = $1, resolved_by = $2, updated_at = $3 WHERE id = $4`,
+		resolvedAt, resolvedBy, now, id)
+	if err != nil {
+		return fmt.Errorf("set book comment resolved: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return database.ErrBookCommentNotFound
+	}
+	return nil
+}
+
+// DeleteBookComment removes a comment.
+func (r *BookRepository) DeleteBookComment(ctx context.Context, id string) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM book_comments WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("delete book comment: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+//go:build integration
+
+package postgres
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+func TestBookComments(t *testing.T) {
+	f, cleanup := setupMoveFixture(t)
+	if f == nil {
+		return
+	}
+	defer cleanup()
+
+	page := f.makePage(t, f.sectionA, "photo1")
+	comments := []*database.BookComment{
+		{BookID: f.bookID, TargetType: database.CommentTargetBook, Body: "Obálka?", AuthorUID: "u1"},
+		{BookID: f.bookID, TargetType: database.CommentTargetSlot, PageID: page.ID, SlotIndex: 0, Body: "Oříznout"},
+		{BookID: f.bookID, TargetType: database.CommentTargetSectionPhoto,
+			SectionID: f.sectionA, PhotoUID: "photo1", Body: "Kdo to je?"},
+	}
+	for _, c := range comments {
+		if err := f.repo.CreateBookComment(f.ctx, c); err != nil {
+			t.Fatalf("create comment: %v", err)
+		}
+	}
+
+	got, err := f.repo.GetBookComment(f.ctx, comments[1].ID)
+	if err != nil || got == nil {
+		t.Fatalf("get comment: %v, %v", got, err)
+	}
+	if got.PageID != page.ID || got.SlotIndex != 0 || got.SectionID != "" || got.IsResolved() {
+		t.Errorf("unexpected comment: %+v", got)
+	}
+
+	if err := f.repo.SetBookCommentResolved(f.ctx, comments[0].ID, true, "u2"); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	unresolved, err := f.repo.ListBookComments(f.ctx, f.bookID, true)
+	if err != nil || len(unresolved) != 2 {
+		t.Fatalf("unresolved comments = %d, %v; want 2", len(unresolved), err)
+	}
+	all, _ := f.repo.ListBookComments(f.ctx, f.bookID, false)
+	if len(all) != 3 || !all[0].IsResolved() || all[0].ResolvedBy != "u2" {
+		t.Errorf("all comments = %+v", all)
+	}
+
+	if err := f.repo.SetBookCommentResolved(f.ctx, comments[0].ID, false, "u2"); err != nil {
+		t.Fatalf("unresolve: %v", err)
+	}
+	reopened, _ := f.repo.GetBookComment(f.ctx, comments[0].ID)
+	if reopened.IsResolved() || reopened.ResolvedBy != "" {
+		t.Errorf("reopened comment = %+v", reopened)
+	}
+	if err := f.repo.SetBookCommentResolved(f.ctx, "missing", true, ""); !errors.Is(err, database.ErrBookCommentNotFound) {
+		t.Errorf("resolve missing comment: %v, want ErrBookCommentNotFound", err)
+	}
+
+	// Comments go away with their page and section photo.
+	if err := f.repo.DeletePage(f.ctx, page.ID); err != nil {
+		t.Fatalf("delete page: %v", err)
+	}
+	if err := f.repo.RemoveSectionPhotos(f.ctx, f.sectionA, []string{"photo1"}); err != nil {
+		t.Fatalf("remove section photo: %v", err)
+	}
+	remaining, _ := f.repo.ListBookComments(f.ctx, f.bookID, false)
+	if len(remaining) != 1 || remaining[0].ID != comments[0].ID {
+		t.Errorf("remaining comments = %+v, want only the book comment", remaining)
+	}
+
+	if err := f.repo.DeleteBookComment(f.ctx, comments[0].ID); err != nil {
+		t.Fatalf("delete comment: %v", err)
+	}
+	if c, _ := f.repo.GetBookComment(f.ctx, comments[0].ID); c != nil {
+		t.Error("comment still exists after delete")
+	}
+}
@@ -42,7 +42,7 @@ func newID() string {
 // --- Books ---
 
 // CreateBook inserts a new book into the database and populates its ID.
-// Zero-value typography fields are filled with defaults for backward compatibility.
+// Zero-value typography and page setup fields are filled with defaults for backward compatibility.
 func (r *BookRepository) CreateBook(ctx context.Context, book *database.PhotoBook) error {
 	if book.ID == "" {
 		book.ID = newID()
@@ -57,13 +57,15 @@ func (r *BookRepository) CreateBook(ctx context.Context, book *database.PhotoBoo
 		  body_font_size, body_line_height, h1_font_size,
 		  h2_font_size, caption_opacity, caption_font_size,
 		  heading_color_bleed, caption_badge_size, body_text_pad_mm,
+		  page_size, orientation, bleed_mm,
 		  created_at, updated_at)
-		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16)`,
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19)`,
 		book.ID, book.Title, book.Description,
 		book.BodyFont, book.HeadingFont, book.BodyFontSize,
 		book.BodyLineHeight, book.H1FontSize, book.H2FontSize,
 		book.CaptionOpacity, book.CaptionFontSize,
 		book.HeadingColorBleed, book.CaptionBadgeSize, book.BodyTextPadMM,
+		book.PageSize, book.Orientation, book.BleedMM,
 		book.CreatedAt, book.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("create book: %w", err)
@@ -71,7 +73,7 @@ func (r *BookRepository) CreateBook(ctx context.Context, book *database.PhotoBoo
 	return nil
 }
 
-// applyBookTypographyDefaults fills zero-value typography fields with defaults.
+// applyBookTypographyDefaults fills zero-value typography and page setup fields with defaults.
 // This ensures backward compatibility when callers create books without
 // specifying typography settings.
 func applyBookTypographyDefaults(book *database.PhotoBook) {
@@ -81,6 +83,12 @@ func applyBookTypographyDefaults(book *database.PhotoBook) {
 	if book.HeadingFont == "" {
 		book.HeadingFont = "source-sans-3"
 	}
+	if book.PageSize == "" {
+		book.PageSize = "a4"
+	}
+	if book.Orientation == "" {
+		book.Orientation = "landscape"
+	}
 	floatDefaults := []struct {
 		ptr *float64
 		val float64
@@ -94,6 +102,7 @@ func applyBookTypographyDefaults(book *database.PhotoBook) {
 		{&book.HeadingColorBleed, 4.0},
 		{&book.CaptionBadgeSize, 4.0},
 		{&book.BodyTextPadMM, 4.0},
+		{&book.BleedMM, 3.0},
 	}
 	for _, d := range floatDefaults {
 		if *d.ptr == 0 {
@@ -111,6 +120,7 @@ func (r *BookRepository) GetBook(ctx context.Context, id string) (*database.Phot
 		        body_line_height, h1_font_size, h2_font_size,
 		        caption_opacity, caption_font_size,
 		        heading_color_bleed, caption_badge_size, body_text_pad_mm,
+		        page_size, orientation, bleed_mm,
 		        created_at, updated_at
 		 FROM photo_books WHERE id = $1`, id).
 		Scan(&b.ID, &b.Title, &b.Description,
@@ -118,6 +128,7 @@ func (r *BookRepository) GetBook(ctx context.Context, id string) (*database.Phot
 			&b.BodyLineHeight, &b.H1FontSize, &b.H2FontSize,
 			&b.CaptionOpacity, &b.CaptionFontSize,
 			&b.HeadingColorBleed, &b.CaptionBadgeSize, &b.BodyTextPadMM,
+			&b.PageSize, &b.Orientation, &b.BleedMM,
 			&b.CreatedAt, &b.UpdatedAt)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
@@ -136,6 +147,7 @@ func (r *BookRepository) ListBooks(ctx context.Context) ([]database.PhotoBook, e
 		        body_line_height, h1_font_size, h2_font_size,
 		        caption_opacity, caption_font_size,
 		        heading_color_bleed, caption_badge_size, body_text_pad_mm,
+		        page_size, orientation, bleed_mm,
 		        created_at, updated_at
 		 FROM photo_books ORDER BY created_at DESC`)
 	if err != nil {
@@ -149,6 +161,7 @@ func (r *BookRepository) ListBooks(ctx context.Context) ([]database.PhotoBook, e
 			&b.BodyFont, &b.HeadingFont, &b.BodyFontSize, &b.BodyLineHeight,
 			&b.H1FontSize, &b.H2FontSize, &b.CaptionOpacity, &b.CaptionFontSize,
 			&b.HeadingColorBleed, &b.CaptionBadgeSize, &b.BodyTextPadMM,
+			&b.PageSize, &b.Orientation, &b.BleedMM,
 			&b.CreatedAt, &b.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("scan book: %w", err)
 		}
@@ -167,6 +180,7 @@ func (r *BookRepository) ListBooksWithCounts(ctx context.Context) ([]database.Ph
 			pb.body_font, pb.heading_font, pb.body_font_size, pb.body_line_height,
 			pb.h1_font_size, pb.h2_font_size, pb.caption_opacity, pb.caption_font_size,
 			pb.heading_color_bleed, pb.caption_badge_size, pb.body_text_pad_mm,
+			pb.page_size, pb.orientation, pb.bleed_mm,
 			pb.created_at, pb.updated_at,
 			(SELECT COUNT(*) FROM book_sections WHERE book_id = pb.id) as section_count,
 			(SELECT COUNT(*) FROM book_pages WHERE book_id = pb.id) as page_count,
@@ -186,6 +200,7 @@ func (r *BookRepository) ListBooksWithCounts(ctx context.Context) ([]database.Ph
 			&b.BodyFont, &b.HeadingFont, &b.BodyFontSize, &b.BodyLineHeight,
 			&b.H1FontSize, &b.H2FontSize, &b.CaptionOpacity, &b.CaptionFontSize,
 			&b.HeadingColorBleed, &b.CaptionBadgeSize, &b.BodyTextPadMM,
+			&b.PageSize, &b.Orientation, &b.BleedMM,
 			&b.CreatedAt, &b.UpdatedAt,
 			&b.SectionCount, &b.PageCount, &b.PhotoCount); err != nil {
 			return nil, fmt.Errorf("scan book with counts: %w", err)
@@ -198,7 +213,7 @@ func (r *BookRepository) ListBooksWithCounts(ctx context.Context) ([]database.Ph
 	return books, nil
 }
 
-// UpdateBook updates a book's title, description, and typography settings.
+// UpdateBook updates a book's title, description, typography and page setup.
 func (r *BookRepository) UpdateBook(ctx context.Context, book *database.PhotoBook) error {
 	book.UpdatedAt = time.Now()
 	_, err := r.pool.Exec(ctx,
@@ -206,11 +221,13 @@ func (r *BookRepository) UpdateBook(ctx context.Context, book *database.PhotoBoo
 			body_font = $3, heading_font = $4, body_font_size = $5, body_line_height = $6,
 			h1_font_size = $7, h2_font_size = $8, caption_opacity = $9, caption_font_size = $10,
 			heading_color_bleed = $11, caption_badge_size = $12, body_text_pad_mm = $13,
-			updated_at = $14 WHERE id = $15`,
+			page_size = $14, orientation = $15, bleed_mm = $16,
+			updated_at = $17 WHERE id = $18`,
 		book.Title, book.Description,
 		book.BodyFont, book.HeadingFont, book.BodyFontSize, book.BodyLineHeight,
 		book.H1FontSize, book.H2FontSize, book.CaptionOpacity, book.CaptionFontSize,
 		book.HeadingColorBleed, book.CaptionBadgeSize, book.BodyTextPadMM,
+		book.PageSize, book.Orientation, book.BleedMM,
 		book.UpdatedAt, book.ID)
 	if err != nil {
 		return fmt.Errorf("update book: %w", err)
@@ -614,7 +631,7 @@ func (r *BookRepository) CreatePage(ctx context.Context, page *database.BookPage
 	return nil
 }
 
-// GetPage retrieves a page by ID with its slots from the database.
+// GetPage retrieves a page by ID with its slots and custom layout from the database.
 func (r *BookRepository) GetPage(ctx context.Context, pageID string) (*database.BookPage, error) {
 	var p database.BookPage
 	err := r.pool.QueryRow(ctx,
@@ -637,10 +654,14 @@ func (r *BookRepository) GetPage(ctx context.Context, pageID string) (*database.
 		return nil, err
 	}
 	p.Slots = slots
-	return &p, nil
+	pages := []database.BookPage{p}
+	if err := r.attachPageLayouts(ctx, pages); err != nil {
+		return nil, err
+	}
+	return &pages[0], nil
 }
 
-// GetPages retrieves all pages for a book with their slots, ordered by sort order.
+// GetPages retrieves all pages for a book with their slots and custom layouts, ordered by sort order.
 func (r *BookRepository) GetPages(ctx context.Context, bookID string) ([]database.BookPage, error) {
 	rows, err := r.pool.Query(ctx,
 		`SELECT bp.id, bp.book_id, COALESCE(bp.section_id, ''), bp.format, bp.style,
@@ -702,6 +723,9 @@ func (r *BookRepository) GetPages(ctx context.Context, bookID string) ([]databas
 	for i := range pages {
 		pages[i].Slots = slotsByPage[pages[i].ID]
 	}
+	if err := r.attachPageLayouts(ctx, pages); err != nil {
+		return nil, err
+	}
 	return pages, nil
 }
 
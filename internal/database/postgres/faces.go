@@ -44,7 +44,7 @@ func NewFaceRepository(pool *Pool) *FaceRepository {
 func (r *FaceRepository) GetFaces(ctx context.Context, photoUID string) ([]database.StoredFace, error) {
 	query := `
 		SELECT id, photo_uid, face_index, embedding, bbox, det_score, model, dim, created_at,
-		       marker_uid, subject_uid, subject_name, photo_width, photo_height, orientation, file_uid
+		       marker_uid, subject_uid, subject_name, photo_width, photo_height, orientation, file_uid, quality
 		FROM faces
 		WHERE photo_uid = $1
 		ORDER BY face_index
@@ -71,7 +71,7 @@ func (r *FaceRepository) GetFacesBySubjectName(ctx context.Context, subjectName
 	// This matches the Go normalization: lowercase, remove diacritics, replace dashes with spaces.
 	query := `
 		SELECT id, photo_uid, face_index, embedding, bbox, det_score, model, dim, created_at,
-		       marker_uid, subject_uid, subject_name, photo_width, photo_height, orientation, file_uid
+		       marker_uid, subject_uid, subject_name, photo_width, photo_height, orientation, file_uid, quality
 		FROM faces
 		WHERE LOWER(REPLACE(unaccent(subject_name), '-', ' ')) = $1
 		ORDER BY id
@@ -184,7 +184,7 @@ func (r *FaceRepository) GetUniquePhotoUIDs(ctx context.Context) ([]string, erro
 func (r *FaceRepository) GetFacesWithMarkerUID(ctx context.Context) ([]database.StoredFace, error) {
 	query := `
 		SELECT id, photo_uid, face_index, embedding, bbox, det_score, model, dim, created_at,
-		       marker_uid, subject_uid, subject_name, photo_width, photo_height, orientation, file_uid
+		       marker_uid, subject_uid, subject_name, photo_width, photo_height, orientation, file_uid, quality
 		FROM faces
 		WHERE marker_uid IS NOT NULL AND marker_uid != ''
 		ORDER BY id
@@ -199,6 +199,44 @@ func (r *FaceRepository) GetFacesWithMarkerUID(ctx context.Context) ([]database.
 	return scanFaces(rows)
 }
 
+// GetUnassignedFaces returns all faces that are not assigned to a person.
+func (r *FaceRepository) GetUnassignedFaces(ctx context.Context) ([]database.StoredFace, error) {
+	query := `
+		SELECT id, photo_uid, face_index, embedding, bbox, det_score, model, dim, created_at,
+		       marker_uid, subject_uid, subject_name, photo_width, photo_height, orientation, file_uid, quality
+		FROM faces
+		WHERE subject_name IS NULL OR subject_name = ''
+		ORDER BY id
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query unassigned faces: %w", err)
+	}
+	defer rows.Close()
+
+	return scanFaces(rows)
+}
+
+// GetAssignedFaces returns all faces that are assigned to a person.
+func (r *FaceRepository) GetAssignedFaces(ctx context.Context) ([]database.StoredFace, error) {
+	query := `
+		SELECT id, photo_uid, face_index, embedding, bbox, det_score, model, dim, created_at,
+		       marker_uid, subject_uid, subject_name, photo_width, photo_height, orientation, file_uid, quality
+		FROM faces
+		WHERE subject_name IS NOT NULL AND subject_name != ''
+		ORDER BY id
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query assigned faces: %w", err)
+	}
+	defer rows.Close()
+
+	return scanFaces(rows)
+}
+
 // CountProcessed returns the number of photos that have been processed for face detection.
 func (r *FaceRepository) CountProcessed(ctx context.Context) (int, error) {
 	var count int
@@ -270,7 +308,7 @@ func (r *FaceRepository) findSimilarPostgres(
 
 	query := `
 		SELECT id, photo_uid, face_index, embedding, bbox, det_score, model, dim, created_at,
-		       marker_uid, subject_uid, subject_name, photo_width, photo_height, orientation, file_uid
+		       marker_uid, subject_uid, subject_name, photo_width, photo_height, orientation, file_uid, quality
 		FROM faces
 		ORDER BY embedding <=> $1::vector
 		LIMIT $2
@@ -364,7 +402,7 @@ func (r *FaceRepository) findSimilarWithDistancePostgres(
 
 	query := `
 		SELECT id, photo_uid, face_index, embedding, bbox, det_score, model, dim, created_at,
-		       marker_uid, subject_uid, subject_name, photo_width, photo_height, orientation, file_uid,
+		       marker_uid, subject_uid, subject_name, photo_width, photo_height, orientation, file_uid, quality,
 		       embedding <=> $1::vector AS distance
 		FROM faces
 		WHERE embedding <=> $1::vector < $2
@@ -407,6 +445,7 @@ type faceNullableFields struct {
 	photoWidth  sql.NullInt32
 	photoHeight sql.NullInt32
 	orientation sql.NullInt32
+	quality     sql.NullFloat64
 }
 
 // extractNullableFields converts optional face fields to SQL nullable types.
@@ -433,6 +472,9 @@ func extractNullableFields(face *database.StoredFace) faceNullableFields {
 	if face.Orientation > 0 {
 		f.orientation = sql.NullInt32{Int32: safeIntToInt32(face.Orientation), Valid: true}
 	}
+	if face.Quality > 0 {
+		f.quality = sql.NullFloat64{Float64: face.Quality, Valid: true}
+	}
 	return f
 }
 
@@ -501,8 +543,9 @@ func insertFacesReturningIDs(
 		var newID int64
 		err := tx.QueryRowContext(ctx, `
 			INSERT INTO faces (photo_uid, face_index, embedding, bbox, det_score, model, dim,
-			                   marker_uid, subject_uid, subject_name, photo_width, photo_height, orientation, file_uid)
-			VALUES ($1, $2, $3::vector, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			                   marker_uid, subject_uid, subject_name, photo_width, photo_height, orientation, file_uid,
+			                   quality)
+			VALUES ($1, $2, $3::vector, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 			RETURNING id
 		`,
 			photoUID,
@@ -519,6 +562,7 @@ func insertFacesReturningIDs(
 			nf.photoHeight,
 			nf.orientation,
 			nf.fileUID,
+			nf.quality,
 		).Scan(&newID)
 		if err != nil {
 			return nil, fmt.Errorf("insert face %d: %w", face.FaceIndex, err)
@@ -628,6 +672,33 @@ func (r *FaceRepository) UpdateFacePhotoInfo(
 	return nil
 }
 
+// UpdateFaceQuality updates the quality score of a specific face.
+func (r *FaceRepository) UpdateFaceQuality(ctx context.Context, photoUID string, faceIndex int, quality float64) error {
+	query := `
+		UPDATE faces SET quality = $1
+		WHERE photo_uid = $2 AND face_index = $3
+		RETURNING id
+	`
+
+	var faceID int64
+	err := r.pool.QueryRow(ctx, query, quality, photoUID, faceIndex).Scan(&faceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil // Face not found, no-op.
+	}
+	if err != nil {
+		return fmt.Errorf("update face quality: %w", err)
+	}
+
+	// Sync the in-memory HNSW index if enabled.
+	if r.isHNSWEnabled() {
+		r.hnswMu.RLock()
+		r.hnswIndex.UpdateFaceQuality(faceID, quality)
+		r.hnswMu.RUnlock()
+	}
+
+	return nil
+}
+
 // SaveFacesBatch saves faces for multiple photos in a single transaction.
 //
 //nolint:funlen // Batch operation with transaction management.
@@ -656,8 +727,9 @@ func (r *FaceRepository) SaveFacesBatch(ctx context.Context, facesByPhoto map[st
 	// Prepare insert statement.
 	stmt, err := tx.PrepareContext(ctx, `
 		INSERT INTO faces (photo_uid, face_index, embedding, bbox, det_score, model, dim,
-		                   marker_uid, subject_uid, subject_name, photo_width, photo_height, orientation, file_uid)
-		VALUES ($1, $2, $3::vector, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		                   marker_uid, subject_uid, subject_name, photo_width, photo_height, orientation, file_uid,
+		                   quality)
+		VALUES ($1, $2, $3::vector, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`)
 	if err != nil {
 		return fmt.Errorf("prepare statement: %w", err)
@@ -686,6 +758,7 @@ func (r *FaceRepository) SaveFacesBatch(ctx context.Context, facesByPhoto map[st
 				nf.photoHeight,
 				nf.orientation,
 				nf.fileUID,
+				nf.quality,
 			); err != nil {
 				return fmt.Errorf("insert face %s/%d: %w", photoUID, face.FaceIndex, err)
 			}
@@ -735,7 +808,7 @@ func (r *FaceRepository) MarkFacesProcessedBatch(ctx context.Context, records []
 }
 
 // scanFaceRow scans a single row into a StoredFace, with optional extra scan destinations.
-// appended after the standard 16 face columns (e.g., a distance column).
+// appended after the standard 17 face columns (e.g., a distance column).
 func scanFaceRow(scanner interface{ Scan(...any) error }, extraDest ...any) (database.StoredFace, error) {
 	var face database.StoredFace
 	var vec pgvector.Vector
@@ -743,8 +816,9 @@ func scanFaceRow(scanner interface{ Scan(...any) error }, extraDest ...any) (dat
 	var markerUID, subjectUID, subjectName, fileUID sql.NullString
 	var photoWidth, photoHeight, orientation sql.NullInt32
 	var model sql.NullString
+	var quality sql.NullFloat64
 
-	dest := make([]any, 0, 16+len(extraDest))
+	dest := make([]any, 0, 17+len(extraDest))
 	dest = append(dest,
 		&face.ID,
 		&face.PhotoUID,
@@ -762,6 +836,7 @@ func scanFaceRow(scanner interface{ Scan(...any) error }, extraDest ...any) (dat
 		&photoHeight,
 		&orientation,
 		&fileUID,
+		&quality,
 	)
 	dest = append(dest, extraDest...)
 
@@ -795,6 +870,9 @@ func scanFaceRow(scanner interface{ Scan(...any) error }, extraDest ...any) (dat
 	if orientation.Valid {
 		face.Orientation = int(orientation.Int32)
 	}
+	if quality.Valid {
+		face.Quality = quality.Float64
+	}
 
 	return face, nil
 }
@@ -824,7 +902,7 @@ func scanFaceWithDistance(rows *sql.Rows) (database.StoredFace, float64, error)
 func (r *FaceRepository) GetAllFaces(ctx context.Context) ([]database.StoredFace, error) {
 	query := `
 		SELECT id, photo_uid, face_index, embedding, bbox, det_score, model, dim, created_at,
-		       marker_uid, subject_uid, subject_name, photo_width, photo_height, orientation, file_uid
+		       marker_uid, subject_uid, subject_name, photo_width, photo_height, orientation, file_uid, quality
 		FROM faces
 		ORDER BY id
 	`
@@ -846,6 +924,10 @@ func (r *FaceRepository) tryLoadFaceIndex(ctx context.Context, indexPath string,
 		fmt.Printf("Face index: metadata file error: %v (will rebuild)\n", metaErr)
 		return false
 	}
+	if !metadata.IsCurrentVersion() {
+		fmt.Printf("Face index: outdated cache version %d (will rebuild)\n", metadata.Version)
+		return false
+	}
 	if metadata.FaceCount != dbFaceCount || metadata.MaxFaceID != dbMaxFaceID {
 		fmt.Printf("Face index: stale (db: count=%d max_id=%d, cached: count=%d max_id=%d) (will rebuild)\n",
 			dbFaceCount, dbMaxFaceID, metadata.FaceCount, metadata.MaxFaceID)
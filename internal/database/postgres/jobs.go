@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/lib/pq"
+)
+
+// JobRepository provides PostgreSQL-backed async job storage.
+type JobRepository struct {
+	pool *Pool
+}
+
+// NewJobRepository creates a new job repository.
+func NewJobRepository(pool *Pool) *JobRepository {
+	return &JobRepository{pool: pool}
+}
+
+const upsertJobSQL = `
+INSERT INTO jobs
+  (id, job_type, status, options, progress, total, processed,
+   result, error, started_at, completed_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+ON CONFLICT (id)
+DO UPDATE SET
+  status = EXCLUDED.status,
+  options = EXCLUDED.options,
+  progress = EXCLUDED.progress,
+  total = EXCLUDED.total,
+  processed = EXCLUDED.processed,
+  result = EXCLUDED.result,
+  error = EXCLUDED.error,
+  completed_at = EXCLUDED.completed_at,
+  updated_at = NOW()
+RETURNING updated_at`
+
+// SaveJob upserts a job snapshot.
+func (r *JobRepository) SaveJob(ctx context.Context, job *database.StoredJob) error {
+	options := []byte(job.Options)
+	if len(options) == 0 {
+		options = []byte("{}")
+	}
+	var result any
+	if len(job.Result) > 0 {
+		result = []byte(job.Result)
+	}
+	err := r.pool.QueryRow(ctx, upsertJobSQL,
+		job.ID, job.Type, job.Status, options,
+		job.Progress, job.Total, job.Processed,
+		result, job.Error, job.StartedAt, job.CompletedAt,
+	).Scan(&job.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("save job: %w", err)
+	}
+	return nil
+}
+
+const selectJobColumns = `
+SELECT id, job_type, status, options, progress, total, processed,
+       result, error, started_at, completed_at, updated_at
+FROM jobs`
+
+// GetJob retrieves a job by ID, returns nil if not found.
+func (r *JobRepository) GetJob(ctx context.Context, id string) (*database.StoredJob, error) {
+	job, err := scanJob(r.pool.QueryRow(ctx, selectJobColumns+" WHERE id = $1", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get job: %w", err)
+	}
+	return &job, nil
+}
+
+// ListJobs returns jobs matching the filter, newest first.
+func (r *JobRepository) ListJobs(ctx context.Context, filter database.JobFilter) ([]database.StoredJob, error) {
+	query, args := buildJobListQuery(filter)
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []database.StoredJob
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// buildJobListQuery builds the SELECT for ListJobs from the filter.
+func buildJobListQuery(filter database.JobFilter) (string, []any) {
+	var where []string
+	var args []any
+	if len(filter.Types) > 0 {
+		args = append(args, pq.Array(filter.Types))
+		where = append(where, fmt.Sprintf("job_type = ANY($%d)", len(args)))
+	}
+	if len(filter.Statuses) > 0 {
+		args = append(args, pq.Array(filter.Statuses))
+		where = append(where, fmt.Sprintf("status = ANY($%d)", len(args)))
+	}
+	if !filter.StartedAfter.IsZero() {
+		args = append(args, filter.StartedAfter)
+		where = append(where, fmt.Sprintf("started_at >= $%d", len(args)))
+	}
+	if !filter.StartedBefore.IsZero() {
+		args = append(args, filter.StartedBefore)
+		where = append(where, fmt.Sprintf("started_at < $%d", len(args)))
+	}
+
+	query := selectJobColumns
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY started_at DESC"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+	return query, args
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanJob reads a single jobs row into a StoredJob.
+func scanJob(row rowScanner) (database.StoredJob, error) {
+	var job database.StoredJob
+	var options, result []byte
+	if err := row.Scan(
+		&job.ID, &job.Type, &job.Status, &options,
+		&job.Progress, &job.Total, &job.Processed,
+		&result, &job.Error, &job.StartedAt, &job.CompletedAt, &job.UpdatedAt,
+	); err != nil {
+		return job, fmt.Errorf("scan job: %w", err)
+	}
+	job.Options = options
+	if result != nil {
+		job.Result = result
+	}
+	return job, nil
+}
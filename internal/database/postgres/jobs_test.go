@@ -0,0 +1,82 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+func TestJobRepository(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	if pool == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewJobRepository(pool)
+	started := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	t.Run("SaveAndGet", func(t *testing.T) {
+		job := &database.StoredJob{
+			ID: "job-1", Type: database.JobTypeProcess, Status: "running",
+			Options: json.RawMessage(`{"concurrency":5}`), Total: 100, Processed: 40, Progress: 40,
+			StartedAt: started,
+		}
+		if err := repo.SaveJob(ctx, job); err != nil {
+			t.Fatalf("SaveJob: %v", err)
+		}
+		got, err := repo.GetJob(ctx, "job-1")
+		if err != nil {
+			t.Fatalf("GetJob: %v", err)
+		}
+		if got == nil {
+			t.Fatal("expected job, got nil")
+			return
+		}
+		if got.Status != "running" || got.Processed != 40 || got.Result != nil {
+			t.Errorf("unexpected job: %+v", got)
+		}
+	})
+
+	t.Run("UpsertCompletes", func(t *testing.T) {
+		now := time.Now()
+		job := &database.StoredJob{
+			ID: "job-1", Type: database.JobTypeProcess, Status: "completed",
+			Options: json.RawMessage(`{"concurrency":5}`), Total: 100, Processed: 100, Progress: 100,
+			Result: json.RawMessage(`{"embed_success":100}`), StartedAt: started, CompletedAt: &now,
+		}
+		if err := repo.SaveJob(ctx, job); err != nil {
+			t.Fatalf("SaveJob: %v", err)
+		}
+		got, _ := repo.GetJob(ctx, "job-1")
+		if got.Status != "completed" || got.CompletedAt == nil || len(got.Result) == 0 {
+			t.Errorf("unexpected job after upsert: %+v", got)
+		}
+	})
+
+	t.Run("GetMissing", func(t *testing.T) {
+		got, err := repo.GetJob(ctx, "missing")
+		if err != nil || got != nil {
+			t.Errorf("expected nil, nil; got %+v, %v", got, err)
+		}
+	})
+
+	t.Run("ListByStatus", func(t *testing.T) {
+		repo.SaveJob(ctx, &database.StoredJob{
+			ID: "job-2", Type: database.JobTypeSort, Status: "running", StartedAt: time.Now(),
+		})
+		jobs, err := repo.ListJobs(ctx, database.JobFilter{Statuses: []string{"running", "pending"}})
+		if err != nil {
+			t.Fatalf("ListJobs: %v", err)
+		}
+		if len(jobs) != 1 || jobs[0].ID != "job-2" {
+			t.Errorf("expected only job-2, got %+v", jobs)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/lib/pq"
+)
+
+// LabelTaxonomyRepository provides PostgreSQL-backed storage for the label taxonomy.
+type LabelTaxonomyRepository struct {
+	pool *Pool
+}
+
+// NewLabelTaxonomyRepository creates a new label taxonomy repository.
+func NewLabelTaxonomyRepository(pool *Pool) *LabelTaxonomyRepository {
+	return &LabelTaxonomyRepository{pool: pool}
+}
+
+// ListLabelTerms returns all taxonomy terms ordered by name.
+func (r *LabelTaxonomyRepository) ListLabelTerms(ctx context.Context) ([]database.LabelTerm, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT name, parent, aliases, description, created_at, updated_at
+		FROM label_taxonomy
+		ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("list label terms: %w", err)
+	}
+	defer rows.Close()
+
+	var terms []database.LabelTerm
+	for rows.Next() {
+		var t database.LabelTerm
+		var parent sql.NullString
+		if err := rows.Scan(
+			&t.Name, &parent, pq.Array(&t.Aliases), &t.Description, &t.CreatedAt, &t.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan label term: %w", err)
+		}
+		t.Parent = parent.String
+		terms = append(terms, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate label terms: %w", err)
+	}
+	return terms, nil
+}
+
+// SaveLabelTerms inserts or replaces terms in a single transaction.
+func (r *LabelTaxonomyRepository) SaveLabelTerms(ctx context.Context, terms []database.LabelTerm) error {
+	tx, err := r.pool.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, t := range terms {
+		aliases := t.Aliases
+		if aliases == nil {
+			aliases = []string{}
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO label_taxonomy (name, parent, aliases, description)
+			VALUES ($1, NULLIF($2, ''), $3, $4)
+			ON CONFLICT (name) DO UPDATE SET
+				parent = EXCLUDED.parent,
+				aliases = EXCLUDED.aliases,
+				description = EXCLUDED.description,
+				updated_at = NOW()`,
+			t.Name, t.Parent, pq.Array(aliases), t.Description,
+		)
+		if err != nil {
+			return fmt.Errorf("save label term %s: %w", t.Name, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit label terms: %w", err)
+	}
+	return nil
+}
+
+// DeleteLabelTerm removes a term; its children lose their parent. Returns
+// false if the term does not exist.
+func (r *LabelTaxonomyRepository) DeleteLabelTerm(ctx context.Context, name string) (bool, error) {
+	res, err := r.pool.Exec(ctx, "DELETE FROM label_taxonomy WHERE name = $1", name)
+	if err != nil {
+		return false, fmt.Errorf("delete label term: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("delete label term: %w", err)
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,66 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+func TestLabelTaxonomyRepository(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	if pool == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewLabelTaxonomyRepository(pool)
+
+	// The child comes before its parent: the parent constraint is deferred.
+	err := repo.SaveLabelTerms(ctx, []database.LabelTerm{
+		{Name: "Dog", Parent: "Animal", Aliases: []string{"Dogs", "Pes"}},
+		{Name: "Animal", Aliases: []string{"Zvíře"}, Description: "Any animal"},
+	})
+	if err != nil {
+		t.Fatalf("SaveLabelTerms: %v", err)
+	}
+
+	terms, err := repo.ListLabelTerms(ctx)
+	if err != nil {
+		t.Fatalf("ListLabelTerms: %v", err)
+	}
+	if len(terms) != 2 || terms[0].Name != "Animal" || terms[1].Name != "Dog" {
+		t.Fatalf("unexpected terms: %+v", terms)
+	}
+	if terms[1].Parent != "Animal" || len(terms[1].Aliases) != 2 || terms[1].Aliases[1] != "Pes" {
+		t.Errorf("unexpected dog term: %+v", terms[1])
+	}
+	if terms[0].Parent != "" || terms[0].Description != "Any animal" {
+		t.Errorf("unexpected animal term: %+v", terms[0])
+	}
+
+	// Unknown parents are rejected at commit.
+	if err := repo.SaveLabelTerms(ctx, []database.LabelTerm{{Name: "Cat", Parent: "Missing"}}); err == nil {
+		t.Error("expected error for unknown parent")
+	}
+
+	deleted, err := repo.DeleteLabelTerm(ctx, "Animal")
+	if err != nil || !deleted {
+		t.Fatalf("DeleteLabelTerm: %v, deleted=%v", err, deleted)
+	}
+	terms, err = repo.ListLabelTerms(ctx)
+	if err != nil {
+		t.Fatalf("ListLabelTerms: %v", err)
+	}
+	if len(terms) != 1 || terms[0].Parent != "" {
+		t.Errorf("expected orphaned Dog term, got %+v", terms)
+	}
+
+	deleted, err = repo.DeleteLabelTerm(ctx, "Animal")
+	if err != nil || deleted {
+		t.Errorf("expected second delete to report missing term, got %v, %v", deleted, err)
+	}
+}
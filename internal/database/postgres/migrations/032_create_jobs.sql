@@ -0,0 +1,19 @@
+-- jobs: persisted state of async sort/process/upload/book export jobs so
+-- a server restart does not lose progress, results, or the ability to
+-- resume. options/result hold the job-kind-specific JSON payloads.
+CREATE TABLE IF NOT EXISTS jobs (
+    id VARCHAR(36) PRIMARY KEY,
+    job_type VARCHAR(20) NOT NULL CHECK (job_type IN ('sort', 'process', 'upload', 'book_export')),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'running', 'completed', 'failed', 'cancelled')),
+    options JSONB NOT NULL DEFAULT '{}',
+    progress INTEGER NOT NULL DEFAULT 0,
+    total INTEGER NOT NULL DEFAULT 0,
+    processed INTEGER NOT NULL DEFAULT 0,
+    result JSONB,
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status);
+CREATE INDEX IF NOT EXISTS idx_jobs_type_started_at ON jobs(job_type, started_at DESC);
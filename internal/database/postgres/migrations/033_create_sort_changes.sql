@@ -0,0 +1,13 @@
+-- sort_changes: undo log of AI sort runs. Each row holds a photo's title,
+-- description, notes, date and labels as they were before the run touched
+-- it, so the run can be reverted photo by photo.
+CREATE TABLE IF NOT EXISTS sort_changes (
+    id BIGSERIAL PRIMARY KEY,
+    run_id VARCHAR(36) NOT NULL,
+    photo_uid VARCHAR(32) NOT NULL,
+    before JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reverted_at TIMESTAMPTZ,
+    UNIQUE (run_id, photo_uid)
+);
+CREATE INDEX IF NOT EXISTS idx_sort_changes_run_id ON sort_changes(run_id);
@@ -0,0 +1,14 @@
+-- analysis_cache: AI photo analyses keyed by everything that influences the
+-- result (image content hash, provider, model, prompt version, label set),
+-- so re-sorting an unchanged album does not pay for the same analysis again.
+CREATE TABLE IF NOT EXISTS analysis_cache (
+    cache_key VARCHAR(64) PRIMARY KEY,
+    image_hash VARCHAR(64) NOT NULL,
+    provider VARCHAR(100) NOT NULL,
+    model VARCHAR(255) NOT NULL,
+    prompt_version VARCHAR(64) NOT NULL,
+    labels_hash VARCHAR(64) NOT NULL,
+    analysis JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_analysis_cache_image_hash ON analysis_cache(image_hash);
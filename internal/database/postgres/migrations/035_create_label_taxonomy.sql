@@ -0,0 +1,15 @@
+-- label_taxonomy: managed label vocabulary. AI-suggested labels are mapped
+-- onto canonical names (via aliases: synonyms, plurals, Czech/English
+-- translations) and expanded with their parents before being written to
+-- PhotoPrism. The parent constraint is deferred so a whole taxonomy can be
+-- imported in one transaction regardless of order.
+CREATE TABLE IF NOT EXISTS label_taxonomy (
+    name VARCHAR(255) PRIMARY KEY,
+    parent VARCHAR(255) REFERENCES label_taxonomy(name)
+        ON UPDATE CASCADE ON DELETE SET NULL
+        DEFERRABLE INITIALLY DEFERRED,
+    aliases TEXT[] NOT NULL DEFAULT '{}',
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,9 @@
+-- sync_cursors: progress of incremental change detection against PhotoPrism,
+-- one row per consumer (process job, cache sync), so scheduled runs only
+-- look at photos and markers changed since the previous run.
+CREATE TABLE IF NOT EXISTS sync_cursors (
+    name VARCHAR(64) PRIMARY KEY,
+    photos_at TIMESTAMPTZ NOT NULL,
+    markers_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,6 @@
+-- Per-face quality score (0-1) from crop sharpness, face size, detection score
+-- and landmark symmetry. Used to weight person centroids and to filter out
+-- blurry, tiny or profile faces when matching. NULL for faces stored before
+-- scoring existed; "faces quality" backfills them.
+ALTER TABLE faces
+  ADD COLUMN IF NOT EXISTS quality DOUBLE PRECISION;
@@ -0,0 +1,6 @@
+-- Per-book page setup: trimmed page size, orientation and print bleed.
+-- Defaults reproduce the previously hard-coded A4 landscape page with 3mm
+-- bleed, so existing books render unchanged.
+ALTER TABLE photo_books ADD COLUMN IF NOT EXISTS page_size TEXT NOT NULL DEFAULT 'a4';
+ALTER TABLE photo_books ADD COLUMN IF NOT EXISTS orientation TEXT NOT NULL DEFAULT 'landscape';
+ALTER TABLE photo_books ADD COLUMN IF NOT EXISTS bleed_mm REAL NOT NULL DEFAULT 3.0;
@@ -0,0 +1,16 @@
+-- page_layouts: user-defined page formats. Each layout places its slots on
+-- the 12-column grid of the page canvas split into grid_rows equal rows;
+-- cells is a JSON array of {col, col_span, row, row_span}, one per slot.
+-- Pages use a layout by storing its ID in book_pages.format, so the format
+-- check constraint is replaced by validation in the application.
+CREATE TABLE IF NOT EXISTS page_layouts (
+    id VARCHAR(20) PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    grid_rows INTEGER NOT NULL CHECK (grid_rows BETWEEN 1 AND 6),
+    cells JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE book_pages DROP CONSTRAINT IF EXISTS book_pages_format_check;
@@ -0,0 +1,21 @@
+-- book_comments: review comments left on a book, a page, a page slot or a
+-- photo in a section's prepick pool. target_type selects which of the target
+-- columns are set; comments are removed together with their target.
+CREATE TABLE IF NOT EXISTS book_comments (
+    id VARCHAR(36) PRIMARY KEY,
+    book_id VARCHAR(36) NOT NULL REFERENCES photo_books(id) ON DELETE CASCADE,
+    target_type VARCHAR(20) NOT NULL CHECK (target_type IN ('book', 'page', 'slot', 'section_photo')),
+    page_id VARCHAR(36) REFERENCES book_pages(id) ON DELETE CASCADE,
+    slot_index INTEGER,
+    section_id VARCHAR(36),
+    photo_uid VARCHAR(32),
+    body TEXT NOT NULL,
+    author_uid TEXT NOT NULL DEFAULT '',
+    resolved_at TIMESTAMPTZ,
+    resolved_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (section_id, photo_uid) REFERENCES section_photos(section_id, photo_uid) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_book_comments_book ON book_comments(book_id, created_at);
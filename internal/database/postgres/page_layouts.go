@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+// --- Page layouts ---
+
+// scanPageLayout scans a page_layouts row selected by pageLayoutColumns.
+func scanPageLayout(scan func(dest ...any) error) (*database.PageLayout, error) {
+	var l database.PageLayout
+	var cells []byte
+	if err := scan(&l.ID, &l.Name, &l.Description, &l.Rows, &cells, &l.CreatedAt, &l.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("scan page layout: %w", err)
+	}
+	if err := json.Unmarshal(cells, &l.Cells); err != nil {
+		return nil, fmt.Errorf("unmarshal cells of layout %s: %w", l.ID, err)
+	}
+	return &l, nil
+}
+
+const pageLayoutColumns = `id, name, description, grid_rows, cells, created_at, updated_at`
+
+// ListPageLayouts returns all page layouts ordered by name.
+func (r *BookRepository) ListPageLayouts(ctx context.Context) ([]database.PageLayout, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+pageLayoutColumns+` FROM page_layouts ORDER BY name, id`)
+	if err != nil {
+		return nil, fmt.Errorf("list page layouts: %w", err)
+	}
+	defer rows.Close()
+
+	var layouts []database.PageLayout
+	for rows.Next() {
+		l, err := scanPageLayout(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		layouts = append(layouts, *l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate page layouts: %w", err)
+	}
+	return layouts, nil
+}
+
+// GetPageLayout retrieves a page layout by ID, or nil if it does not exist.
+func (r *BookRepository) GetPageLayout(ctx context.Context, id string) (*database.PageLayout, error) {
+	l, err := scanPageLayout(
+		r.pool.QueryRow(ctx, `SELECT `+pageLayoutColumns+` FROM page_layouts WHERE id = $1`, id).Scan,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get page layout: %w", err)
+	}
+	return l, nil
+}
+
+// CreatePageLayout inserts a new page layout. It returns
+// database.ErrPageLayoutExists when a layout with the ID already exists.
+func (r *BookRepository) CreatePageLayout(ctx context.Context, layout *database.PageLayout) error {
+	cells, err := json.Marshal(layout.Cells)
+	if err != nil {
+		return fmt.Errorf("marshal cells: %w", err)
+	}
+	now := time.Now()
+	layout.CreatedAt = now
+	layout.UpdatedAt = now
+
+	_, err = r.pool.Exec(ctx,
+		`INSERT INTO page_layouts (id, name, description, grid_rows, cells, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		layout.ID, layout.Name, layout.Description, layout.Rows, cells, layout.CreatedAt, layout.UpdatedAt)
+	if isUniqueViolation(err, "page_layouts_pkey") {
+		return database.ErrPageLayoutExists
+	}
+	if err != nil {
+		return fmt.Errorf("create page layout: %w", err)
+	}
+	return nil
+}
+
+// UpdatePageLayout updates a page layout's name, description and grid. The
+// number of slots of a layout used by pages cannot change, as their slots
+// would no longer match; such updates return database.ErrPageLayoutInUse.
+// It returns database.ErrPageLayoutNotFound when the layout does not exist.
+func (r *BookRepository) UpdatePageLayout(ctx context.Context, layout *database.PageLayout) error {
+	cells, err := json.Marshal(layout.Cells)
+	if err != nil {
+		return fmt.Errorf("marshal cells: %w", err)
+	}
+	layout.UpdatedAt = time.Now()
+
+	res, err := r.pool.Exec(ctx,
+		`UPDATE page_layouts SET name = $1, description = $2, grid_rows = $3, cells = $4, updated_at = $5
+		 WHERE id = $6
+		   AND (jsonb_array_length(cells) = jsonb_array_length($4::jsonb)
+		        OR NOT EXISTS (SELECT 1 FROM book_pages WHERE format = $6))`,
+		layout.Name, layout.Description, layout.Rows, cells, layout.UpdatedAt, layout.ID)
+	if err != nil {
+		return fmt.Errorf("update page layout: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return r.pageLayoutUpdateError(ctx, layout.ID)
+	}
+	return nil
+}
+
+// DeletePageLayout removes a page layout that no page uses. It returns
+// database.ErrPageLayoutInUse when pages still use it.
+func (r *BookRepository) DeletePageLayout(ctx context.Context, id string) error {
+	res, err := r.pool.Exec(ctx,
+		`DELETE FROM page_layouts
+		 WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM book_pages WHERE format = $1)`, id)
+	if err != nil {
+		return fmt.Errorf("delete page layout: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		if err := r.pageLayoutUpdateError(ctx, id); !errors.Is(err, database.ErrPageLayoutNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// pageLayoutUpdateError explains why an update or delete of a layout matched
+// no row: the layout does not exist, or pages use it.
+func (r *BookRepository) pageLayoutUpdateError(ctx context.Context, id string) error {
+	var exists bool
+	if err := r.pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM page_layouts WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return fmt.Errorf("check page layout: %w", err)
+	}
+	if !exists {
+		return database.ErrPageLayoutNotFound
+	}
+	return database.ErrPageLayoutInUse
+}
+
+// attachPageLayouts populates the layout of pages with a custom format.
+func (r *BookRepository) attachPageLayouts(ctx context.Context, pages []database.BookPage) error {
+	layouts := make(map[string]*database.PageLayout)
+	for i := range pages {
+		format := pages[i].Format
+		if database.IsBuiltinPageFormat(format) {
+			continue
+		}
+		l, ok := layouts[format]
+		if !ok {
+			var err error
+			if l, err = r.GetPageLayout(ctx, format); err != nil {
+				return err
+			}
+			layouts[format] = l
+		}
+		pages[i].Layout = l
+	}
+	return nil
+}
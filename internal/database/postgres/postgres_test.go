@@ -237,6 +237,7 @@ func TestFaceRepository(t *testing.T) {
 				PhotoHeight: 1080,
 				Orientation: 1,
 				FileUID:     "file1",
+				Quality:     0.8,
 			},
 			{
 				PhotoUID:  "photo456",
@@ -267,6 +268,9 @@ func TestFaceRepository(t *testing.T) {
 		if got[0].PhotoWidth != 1920 {
 			t.Errorf("Expected PhotoWidth 1920, got %d", got[0].PhotoWidth)
 		}
+		if got[0].Quality != 0.8 || got[1].Quality != 0 {
+			t.Errorf("Expected qualities 0.8 and 0 (not computed), got %f and %f", got[0].Quality, got[1].Quality)
+		}
 	})
 
 	// Test HasFaces.
@@ -318,6 +322,43 @@ func TestFaceRepository(t *testing.T) {
 		}
 	})
 
+	// Test GetUnassignedFaces.
+	t.Run("GetUnassignedFaces", func(t *testing.T) {
+		faces, err := repo.GetUnassignedFaces(ctx)
+		if err != nil {
+			t.Fatalf("Failed to get unassigned faces: %v", err)
+		}
+		if len(faces) != 1 || faces[0].FaceIndex != 1 {
+			t.Errorf("Expected only face 1 of photo456, got %+v", faces)
+		}
+	})
+
+	// Test GetAssignedFaces.
+	t.Run("GetAssignedFaces", func(t *testing.T) {
+		faces, err := repo.GetAssignedFaces(ctx)
+		if err != nil {
+			t.Fatalf("Failed to get assigned faces: %v", err)
+		}
+		if len(faces) != 1 || faces[0].SubjectName != "John Doe" {
+			t.Errorf("Expected only face 0 of photo456, got %+v", faces)
+		}
+	})
+
+	// Test UpdateFaceQuality.
+	t.Run("UpdateFaceQuality", func(t *testing.T) {
+		if err := repo.UpdateFaceQuality(ctx, "photo456", 1, 0.35); err != nil {
+			t.Fatalf("Failed to update quality: %v", err)
+		}
+		if err := repo.UpdateFaceQuality(ctx, "missing", 0, 0.5); err != nil {
+			t.Errorf("Expected no error for a missing face, got %v", err)
+		}
+
+		faces, _ := repo.GetFaces(ctx, "photo456")
+		if len(faces) != 2 || faces[1].Quality != 0.35 {
+			t.Errorf("Quality update not reflected: %+v", faces)
+		}
+	})
+
 	// Test UpdateFaceMarker.
 	t.Run("UpdateFaceMarker", func(t *testing.T) {
 		err := repo.UpdateFaceMarker(ctx, "photo456", 1, "newMarker", "newSubject", "Jane Doe")
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+// SortChangeRepository provides PostgreSQL-backed storage for the sort undo log.
+type SortChangeRepository struct {
+	pool *Pool
+}
+
+// NewSortChangeRepository creates a new sort change repository.
+func NewSortChangeRepository(pool *Pool) *SortChangeRepository {
+	return &SortChangeRepository{pool: pool}
+}
+
+// SaveSortChange records a photo's prior state. A second change for the same
+// (run, photo) is ignored so the stored snapshot is always the pre-run state.
+func (r *SortChangeRepository) SaveSortChange(ctx context.Context, change *database.SortChange) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO sort_changes (run_id, photo_uid, before)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (run_id, photo_uid) DO NOTHING`,
+		change.RunID, change.PhotoUID, []byte(change.Before),
+	)
+	if err != nil {
+		return fmt.Errorf("save sort change: %w", err)
+	}
+	return nil
+}
+
+// ListSortChanges returns all changes recorded for a run, oldest first.
+func (r *SortChangeRepository) ListSortChanges(ctx context.Context, runID string) ([]database.SortChange, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, run_id, photo_uid, before, created_at, reverted_at
+		FROM sort_changes
+		WHERE run_id = $1
+		ORDER BY id`, runID)
+	if err != nil {
+		return nil, fmt.Errorf("list sort changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []database.SortChange
+	for rows.Next() {
+		var c database.SortChange
+		var before []byte
+		if err := rows.Scan(&c.ID, &c.RunID, &c.PhotoUID, &before, &c.CreatedAt, &c.RevertedAt); err != nil {
+			return nil, fmt.Errorf("scan sort change: %w", err)
+		}
+		c.Before = before
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate sort changes: %w", err)
+	}
+	return changes, nil
+}
+
+// MarkSortChangeReverted stamps a change as reverted.
+func (r *SortChangeRepository) MarkSortChangeReverted(ctx context.Context, id int64) error {
+	_, err := r.pool.Exec(ctx, "UPDATE sort_changes SET reverted_at = NOW() WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("mark sort change reverted: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+func TestSortChangeRepository(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	if pool == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewSortChangeRepository(pool)
+
+	first := &database.SortChange{RunID: "run-1", PhotoUID: "p1", Before: json.RawMessage(`{"title":"before"}`)}
+	if err := repo.SaveSortChange(ctx, first); err != nil {
+		t.Fatalf("SaveSortChange: %v", err)
+	}
+	// A second snapshot of the same photo in the same run is ignored.
+	again := &database.SortChange{RunID: "run-1", PhotoUID: "p1", Before: json.RawMessage(`{"title":"after"}`)}
+	if err := repo.SaveSortChange(ctx, again); err != nil {
+		t.Fatalf("SaveSortChange (duplicate): %v", err)
+	}
+	other := &database.SortChange{RunID: "run-2", PhotoUID: "p1", Before: json.RawMessage(`{}`)}
+	if err := repo.SaveSortChange(ctx, other); err != nil {
+		t.Fatalf("SaveSortChange (other run): %v", err)
+	}
+
+	changes, err := repo.ListSortChanges(ctx, "run-1")
+	if err != nil {
+		t.Fatalf("ListSortChanges: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 change, got %d", len(changes))
+	}
+	var before map[string]string
+	if err := json.Unmarshal(changes[0].Before, &before); err != nil {
+		t.Fatalf("unmarshal before: %v", err)
+	}
+	if before["title"] != "before" {
+		t.Errorf("expected first snapshot to be kept, got %v", before)
+	}
+	if changes[0].RevertedAt != nil {
+		t.Error("expected change not to be reverted yet")
+	}
+
+	if err := repo.MarkSortChangeReverted(ctx, changes[0].ID); err != nil {
+		t.Fatalf("MarkSortChangeReverted: %v", err)
+	}
+	changes, err = repo.ListSortChanges(ctx, "run-1")
+	if err != nil {
+		t.Fatalf("ListSortChanges: %v", err)
+	}
+	if changes[0].RevertedAt == nil {
+		t.Error("expected change to be marked reverted")
+	}
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+// SyncCursorRepository provides PostgreSQL-backed storage for incremental sync cursors.
+type SyncCursorRepository struct {
+	pool *Pool
+}
+
+// NewSyncCursorRepository creates a new sync cursor repository.
+func NewSyncCursorRepository(pool *Pool) *SyncCursorRepository {
+	return &SyncCursorRepository{pool: pool}
+}
+
+// GetSyncCursor returns the cursor stored under name, or nil if none.
+func (r *SyncCursorRepository) GetSyncCursor(ctx context.Context, name string) (*database.SyncCursor, error) {
+	var cursor database.SyncCursor
+	err := r.pool.QueryRow(ctx, `
+		SELECT name, photos_at, markers_at, updated_at
+		FROM sync_cursors
+		WHERE name = $1`, name,
+	).Scan(&cursor.Name, &cursor.PhotosAt, &cursor.MarkersAt, &cursor.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get sync cursor: %w", err)
+	}
+	return &cursor, nil
+}
+
+// SaveSyncCursor stores (or replaces) a cursor.
+func (r *SyncCursorRepository) SaveSyncCursor(ctx context.Context, cursor *database.SyncCursor) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO sync_cursors (name, photos_at, markers_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (name)
+		DO UPDATE SET photos_at = EXCLUDED.photos_at, markers_at = EXCLUDED.markers_at, updated_at = NOW()`,
+		cursor.Name, cursor.PhotosAt, cursor.MarkersAt,
+	)
+	if err != nil {
+		return fmt.Errorf("save sync cursor: %w", err)
+	}
+	return nil
+}
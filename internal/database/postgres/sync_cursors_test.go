@@ -0,0 +1,54 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+func TestSyncCursorRepository(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	if pool == nil {
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewSyncCursorRepository(pool)
+
+	cursor, err := repo.GetSyncCursor(ctx, "process")
+	if err != nil {
+		t.Fatalf("GetSyncCursor (miss): %v", err)
+	}
+	if cursor != nil {
+		t.Fatalf("expected nil for missing cursor, got %+v", cursor)
+	}
+
+	first := time.Date(2024, time.March, 15, 10, 0, 0, 0, time.UTC)
+	saved := &database.SyncCursor{Name: "process", PhotosAt: first, MarkersAt: first}
+	if err := repo.SaveSyncCursor(ctx, saved); err != nil {
+		t.Fatalf("SaveSyncCursor: %v", err)
+	}
+	saved.PhotosAt = first.Add(time.Hour)
+	if err := repo.SaveSyncCursor(ctx, saved); err != nil {
+		t.Fatalf("SaveSyncCursor (replace): %v", err)
+	}
+
+	cursor, err = repo.GetSyncCursor(ctx, "process")
+	if err != nil {
+		t.Fatalf("GetSyncCursor: %v", err)
+	}
+	if cursor == nil {
+		t.Fatal("expected stored cursor")
+	}
+	if !cursor.PhotosAt.Equal(first.Add(time.Hour)) || !cursor.MarkersAt.Equal(first) {
+		t.Errorf("unexpected cursor: %+v", cursor)
+	}
+	if cursor.UpdatedAt.IsZero() {
+		t.Error("expected updated_at to be set")
+	}
+}
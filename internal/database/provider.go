@@ -28,6 +28,11 @@ var (
 	postgresEmbeddingHNSW      HNSWRebuilder // Singleton for embedding HNSW rebuilding
 	postgresTextVersionStore   func() TextVersionStore
 	postgresTextCheckStore     func() TextCheckStore
+	postgresJobStore           func() JobStore
+	postgresSortUndoStore      func() SortUndoStore
+	postgresAnalysisCache      func() AnalysisCache
+	postgresLabelTaxonomyStore func() LabelTaxonomyStore
+	postgresSyncCursorStore    func() SyncCursorStore
 	postgresInitialized        bool
 )
 
@@ -43,6 +48,11 @@ func ResetForTesting() {
 	postgresEmbeddingHNSW = nil
 	postgresTextVersionStore = nil
 	postgresTextCheckStore = nil
+	postgresJobStore = nil
+	postgresSortUndoStore = nil
+	postgresAnalysisCache = nil
+	postgresLabelTaxonomyStore = nil
+	postgresSyncCursorStore = nil
 	postgresInitialized = false
 }
 
@@ -221,3 +231,83 @@ func GetTextCheckStore(ctx context.Context) (TextCheckStore, error) {
 	}
 	return postgresTextCheckStore(), nil
 }
+
+// RegisterJobStore registers the JobStore constructor.
+func RegisterJobStore(store func() JobStore) {
+	postgresJobStore = store
+}
+
+// GetJobStore returns a JobStore from the PostgreSQL backend.
+func GetJobStore(ctx context.Context) (JobStore, error) {
+	if !postgresInitialized {
+		return nil, errors.New("PostgreSQL backend not initialized: DATABASE_URL is required")
+	}
+	if postgresJobStore == nil {
+		return nil, errors.New("PostgreSQL job store not registered")
+	}
+	return postgresJobStore(), nil
+}
+
+// RegisterSortUndoStore registers the SortUndoStore constructor.
+func RegisterSortUndoStore(store func() SortUndoStore) {
+	postgresSortUndoStore = store
+}
+
+// GetSortUndoStore returns a SortUndoStore from the PostgreSQL backend.
+func GetSortUndoStore(ctx context.Context) (SortUndoStore, error) {
+	if !postgresInitialized {
+		return nil, errors.New("PostgreSQL backend not initialized: DATABASE_URL is required")
+	}
+	if postgresSortUndoStore == nil {
+		return nil, errors.New("PostgreSQL sort undo store not registered")
+	}
+	return postgresSortUndoStore(), nil
+}
+
+// RegisterAnalysisCache registers the AnalysisCache constructor.
+func RegisterAnalysisCache(cache func() AnalysisCache) {
+	postgresAnalysisCache = cache
+}
+
+// GetAnalysisCache returns an AnalysisCache from the PostgreSQL backend.
+func GetAnalysisCache(ctx context.Context) (AnalysisCache, error) {
+	if !postgresInitialized {
+		return nil, errors.New("PostgreSQL backend not initialized: DATABASE_URL is required")
+	}
+	if postgresAnalysisCache == nil {
+		return nil, errors.New("PostgreSQL analysis cache not registered")
+	}
+	return postgresAnalysisCache(), nil
+}
+
+// RegisterLabelTaxonomyStore registers the LabelTaxonomyStore constructor.
+func RegisterLabelTaxonomyStore(store func() LabelTaxonomyStore) {
+	postgresLabelTaxonomyStore = store
+}
+
+// GetLabelTaxonomyStore returns a LabelTaxonomyStore from the PostgreSQL backend.
+func GetLabelTaxonomyStore(ctx context.Context) (LabelTaxonomyStore, error) {
+	if !postgresInitialized {
+		return nil, errors.New("PostgreSQL backend not initialized: DATABASE_URL is required")
+	}
+	if postgresLabelTaxonomyStore == nil {
+		return nil, errors.New("PostgreSQL label taxonomy store not registered")
+	}
+	return postgresLabelTaxonomyStore(), nil
+}
+
+// RegisterSyncCursorStore registers the SyncCursorStore constructor.
+func RegisterSyncCursorStore(store func() SyncCursorStore) {
+	postgresSyncCursorStore = store
+}
+
+// GetSyncCursorStore returns a SyncCursorStore from the PostgreSQL backend.
+func GetSyncCursorStore(ctx context.Context) (SyncCursorStore, error) {
+	if !postgresInitialized {
+		return nil, errors.New("PostgreSQL backend not initialized: DATABASE_URL is required")
+	}
+	if postgresSyncCursorStore == nil {
+		return nil, errors.New("PostgreSQL sync cursor store not registered")
+	}
+	return postgresSyncCursorStore(), nil
+}
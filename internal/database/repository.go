@@ -56,6 +56,10 @@ type FaceReader interface {
 	GetUniquePhotoUIDs(ctx context.Context) ([]string, error)
 	// GetFacesWithMarkerUID returns all faces that have a non-empty marker_uid.
 	GetFacesWithMarkerUID(ctx context.Context) ([]StoredFace, error)
+	// GetUnassignedFaces returns all faces that are not assigned to a person.
+	GetUnassignedFaces(ctx context.Context) ([]StoredFace, error)
+	// GetAssignedFaces returns all faces that are assigned to a person.
+	GetAssignedFaces(ctx context.Context) ([]StoredFace, error)
 	// GetPhotoUIDsWithSubjectName returns a set of photo UIDs (from the given list) that.
 	// have at least one face assigned to the given subject name. Used to detect photos.
 	// where a person is already assigned, even if the HNSW cache is stale.
@@ -80,6 +84,10 @@ type FaceWriter interface {
 	// Used during processing or backfill to populate cached PhotoPrism data.
 	UpdateFacePhotoInfo(ctx context.Context, photoUID string, width, height, orientation int, fileUID string) error
 
+	// UpdateFaceQuality updates the quality score of a specific face.
+	// Used to backfill scores of faces stored before quality scoring existed.
+	UpdateFaceQuality(ctx context.Context, photoUID string, faceIndex int, quality float64) error
+
 	// DeleteFacesByPhoto removes all faces and faces_processed records for a photo.
 	// Returns the deleted face IDs for HNSW cleanup.
 	DeleteFacesByPhoto(ctx context.Context, photoUID string) ([]int64, error)
@@ -127,6 +135,13 @@ type BookReader interface {
 	GetPage(ctx context.Context, pageID string) (*BookPage, error)
 	GetPageSlots(ctx context.Context, pageID string) ([]PageSlot, error)
 	GetPhotoBookMemberships(ctx context.Context, photoUID string) ([]PhotoBookMembership, error)
+	ListPageLayouts(ctx context.Context) ([]PageLayout, error)
+	// GetPageLayout returns nil without an error when the layout does not exist.
+	GetPageLayout(ctx context.Context, id string) (*PageLayout, error)
+	// ListBookComments returns the comments of a book, oldest first.
+	ListBookComments(ctx context.Context, bookID string, unresolvedOnly bool) ([]BookComment, error)
+	// GetBookComment returns nil without an error when the comment does not exist.
+	GetBookComment(ctx context.Context, id string) (*BookComment, error)
 }
 
 // BookWriter provides write access to photo book data.
@@ -165,6 +180,17 @@ type BookWriter interface {
 	ClearSlot(ctx context.Context, pageID string, slotIndex int) error
 	SwapSlots(ctx context.Context, pageID string, slotA int, slotB int) error
 	UpdateSlotCrop(ctx context.Context, pageID string, slotIndex int, cropX, cropY, cropScale float64) error
+	CreatePageLayout(ctx context.Context, layout *PageLayout) error
+	// UpdatePageLayout returns ErrPageLayoutInUse when the update changes the
+	// number of slots of a layout that pages use.
+	UpdatePageLayout(ctx context.Context, layout *PageLayout) error
+	// DeletePageLayout returns ErrPageLayoutInUse when pages use the layout.
+	DeletePageLayout(ctx context.Context, id string) error
+	CreateBookComment(ctx context.Context, comment *BookComment) error
+	// SetBookCommentResolved resolves (recording resolvedBy) or reopens a
+	// comment. Returns ErrBookCommentNotFound if the comment does not exist.
+	SetBookCommentResolved(ctx context.Context, id string, resolved bool, resolvedBy string) error
+	DeleteBookComment(ctx context.Context, id string) error
 }
 
 // TextVersionStore provides access to text version history.
@@ -190,3 +216,52 @@ type TextCheckKey struct {
 	SourceID   string
 	Field      string
 }
+
+// JobStore persists async job state so runs survive server restarts.
+type JobStore interface {
+	// SaveJob upserts a job snapshot (by ID).
+	SaveJob(ctx context.Context, job *StoredJob) error
+	// GetJob retrieves a job by ID, returns nil if not found.
+	GetJob(ctx context.Context, id string) (*StoredJob, error)
+	// ListJobs returns jobs matching the filter, newest first.
+	ListJobs(ctx context.Context, filter JobFilter) ([]StoredJob, error)
+}
+
+// SortUndoStore persists the per-photo undo log of AI sort runs.
+type SortUndoStore interface {
+	// SaveSortChange records a photo's prior state. Only the first change per
+	// (run, photo) is kept, so the log always holds the pre-run state.
+	SaveSortChange(ctx context.Context, change *SortChange) error
+	// ListSortChanges returns all changes recorded for a run, oldest first.
+	ListSortChanges(ctx context.Context, runID string) ([]SortChange, error)
+	// MarkSortChangeReverted stamps a change as reverted.
+	MarkSortChangeReverted(ctx context.Context, id int64) error
+}
+
+// AnalysisCache stores AI photo analyses so unchanged photos are not sent to
+// the provider again.
+type AnalysisCache interface {
+	// GetCachedAnalysis returns the analysis stored under key, or nil if none.
+	GetCachedAnalysis(ctx context.Context, key string) (*CachedAnalysis, error)
+	// SaveCachedAnalysis stores (or replaces) an analysis.
+	SaveCachedAnalysis(ctx context.Context, entry *CachedAnalysis) error
+}
+
+// LabelTaxonomyStore persists the managed label vocabulary.
+type LabelTaxonomyStore interface {
+	// ListLabelTerms returns all terms ordered by name.
+	ListLabelTerms(ctx context.Context) ([]LabelTerm, error)
+	// SaveLabelTerms inserts or replaces terms atomically.
+	SaveLabelTerms(ctx context.Context, terms []LabelTerm) error
+	// DeleteLabelTerm removes a term, returning false if it does not exist.
+	DeleteLabelTerm(ctx context.Context, name string) (bool, error)
+}
+
+// SyncCursorStore persists how far incremental change detection against
+// PhotoPrism has progressed, one cursor per consumer.
+type SyncCursorStore interface {
+	// GetSyncCursor returns the cursor stored under name, or nil if none.
+	GetSyncCursor(ctx context.Context, name string) (*SyncCursor, error)
+	// SaveSyncCursor stores (or replaces) a cursor.
+	SaveSyncCursor(ctx context.Context, cursor *SyncCursor) error
+}
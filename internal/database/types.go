@@ -1,6 +1,7 @@
 package database
 
 import (
+	"encoding/json"
 	"errors"
 	"time"
 )
@@ -25,6 +26,23 @@ var ErrPageNotFound = errors.New("page not found")
 // section belongs to a different book than the page being moved.
 var ErrSectionBookMismatch = errors.New("target section belongs to a different book")
 
+// ErrPageLayoutNotFound is returned when a referenced page layout does not exist.
+var ErrPageLayoutNotFound = errors.New("page layout not found")
+
+// ErrPageLayoutExists is returned by CreatePageLayout when the layout ID is taken.
+var ErrPageLayoutExists = errors.New("page layout already exists")
+
+// ErrPageLayoutInUse is returned when deleting a page layout that pages still
+// use, or changing its number of slots.
+var ErrPageLayoutInUse = errors.New("page layout is used by pages")
+
+// ErrBookCommentNotFound is returned when a referenced book comment does not exist.
+var ErrBookCommentNotFound = errors.New("book comment not found")
+
+// ErrCommentTargetNotFound is returned when the page, slot or section photo a
+// comment refers to does not exist in the comment's book.
+var ErrCommentTargetNotFound = errors.New("comment target not found")
+
 // StoredEmbedding represents an embedding stored in the database.
 type StoredEmbedding struct {
 	PhotoUID   string
@@ -46,6 +64,7 @@ type StoredFace struct {
 	Model     string
 	Dim       int
 	CreatedAt time.Time
+	Quality   float64 // Face quality score 0-1 (0 = not computed yet)
 
 	// Cached PhotoPrism data (populated during processing, v3+).
 	MarkerUID   string // Matching PhotoPrism marker UID (empty if no marker matched)
@@ -102,6 +121,9 @@ type PhotoBook struct {
 	HeadingColorBleed float64
 	CaptionBadgeSize  float64
 	BodyTextPadMM     float64
+	PageSize          string  // "a4", "a5", "a3", "square_20", "square_30"
+	Orientation       string  // "landscape" or "portrait"
+	BleedMM           float64 // print bleed on every side of the page
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 }
@@ -159,7 +181,8 @@ type BookPage struct {
 	SplitPosition  *float64 // nullable; 0.2-0.8 column ratio; nil = format default
 	HidePageNumber bool     // suppress folio rendering on this page (numbering continues)
 	SortOrder      int
-	Slots          []PageSlot // populated on read
+	Slots          []PageSlot  // populated on read
+	Layout         *PageLayout // layout of a custom format, populated on read; nil for built-in formats
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
@@ -271,3 +294,88 @@ func PageFormatSlotCount(format string) int {
 		return 0
 	}
 }
+
+// Job type values stored in StoredJob.Type.
+const (
+	JobTypeSort       = "sort"
+	JobTypeProcess    = "process"
+	JobTypeUpload     = "upload"
+	JobTypeBookExport = "book_export"
+)
+
+// StoredJob is a persisted snapshot of an async job (sort, process, upload,
+// or book export). Options and Result hold the job-kind-specific JSON so the
+// job store stays agnostic of the individual job structs.
+type StoredJob struct {
+	ID          string
+	Type        string // one of the JobType* constants
+	Status      string // "pending", "running", "completed", "failed", "cancelled"
+	Options     json.RawMessage
+	Progress    int // 0-100
+	Total       int
+	Processed   int
+	Result      json.RawMessage // nil until the job completes
+	Error       string
+	StartedAt   time.Time
+	CompletedAt *time.Time
+	UpdatedAt   time.Time
+}
+
+// JobFilter narrows the result of JobStore.ListJobs. Zero values mean "any".
+type JobFilter struct {
+	Types         []string
+	Statuses      []string
+	StartedAfter  time.Time // inclusive lower bound on StartedAt
+	StartedBefore time.Time // exclusive upper bound on StartedAt
+	Limit         int
+	Offset        int
+}
+
+// SortChange records the state of a photo before a sort run changed it, so
+// the run can be reverted photo by photo. Before holds a sorter.PhotoSnapshot
+// as JSON.
+type SortChange struct {
+	ID         int64
+	RunID      string
+	PhotoUID   string
+	Before     json.RawMessage
+	CreatedAt  time.Time
+	RevertedAt *time.Time // nil until the change has been reverted
+}
+
+// CachedAnalysis is a stored AI photo analysis. Key is derived from all the
+// inputs that influence the analysis (image content, provider, model, prompt
+// version and label set); the individual parts are kept for inspection and
+// targeted invalidation. Analysis holds an ai.PhotoAnalysis as JSON.
+type CachedAnalysis struct {
+	Key           string
+	ImageHash     string
+	Provider      string
+	Model         string
+	PromptVersion string
+	LabelsHash    string
+	Analysis      json.RawMessage
+	CreatedAt     time.Time
+}
+
+// LabelTerm is an entry of the label taxonomy: a canonical label name with
+// the alternative names (synonyms, plurals, translations) that map onto it
+// and an optional parent term.
+type LabelTerm struct {
+	Name        string
+	Parent      string // canonical name of the parent term, empty for top-level terms
+	Aliases     []string
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// SyncCursor records the newest PhotoPrism change seen by a consumer of
+// incremental change detection (e.g. the process job or the cache sync).
+// Photos and markers changed at or after these times are considered new.
+type SyncCursor struct {
+	Name      string
+	PhotosAt  time.Time // newest photo CreatedAt/UpdatedAt/EditedAt/DeletedAt seen
+	MarkersAt time.Time // newest marker UpdatedAt seen; zero if markers are not tracked
+	UpdatedAt time.Time
+}
@@ -0,0 +1,254 @@
+// Package facecluster groups faces that are not assigned to a person into
+// clusters that likely show the same person, so a whole cluster can be named
+// at once instead of matching faces one known person at a time.
+//
+// Clustering is DBSCAN over the cosine distance between face embeddings. The
+// neighbourhood of each face is looked up in an in-memory HNSW index built
+// from the clustered faces only, so tens of thousands of faces cluster in
+// seconds instead of comparing every pair.
+package facecluster
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math/rand"
+	"slices"
+
+	"github.com/coder/hnsw"
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+// Default clustering parameters.
+const (
+	// DefaultThreshold is the maximum cosine distance between two faces to
+	// count as neighbours. It is stricter than face matching because DBSCAN
+	// chains neighbours, so a loose threshold merges different people.
+	DefaultThreshold = 0.4
+
+	// DefaultMinSize is the minimum number of faces of a cluster.
+	DefaultMinSize = 3
+
+	// DefaultMaxNeighbors is the number of nearest faces looked up per face.
+	DefaultMaxNeighbors = 50
+)
+
+// noise marks a face that does not belong to any cluster.
+const noise = -1
+
+// Options configures clustering. Zero values use the defaults.
+type Options struct {
+	Threshold    float64 // max cosine distance between neighbouring faces
+	MinSize      int     // min faces within Threshold of a face (itself included) for it to grow a cluster
+	MaxNeighbors int     // nearest faces looked up per face; caps the neighbourhood size
+}
+
+// WithDefaults returns the options with zero values replaced by the defaults.
+func (o Options) WithDefaults() Options {
+	if o.Threshold <= 0 {
+		o.Threshold = DefaultThreshold
+	}
+	if o.MinSize <= 0 {
+		o.MinSize = DefaultMinSize
+	}
+	if o.MaxNeighbors <= 0 {
+		o.MaxNeighbors = DefaultMaxNeighbors
+	}
+	o.MaxNeighbors = max(o.MaxNeighbors, o.MinSize)
+	return o
+}
+
+// Cluster is a group of faces that likely show the same person.
+type Cluster struct {
+	Faces     []database.StoredFace // sorted by distance to the centroid, most representative first
+	Distances []float64             // cosine distance of each face to the centroid
+	Centroid  []float32             // mean embedding of the faces
+}
+
+// Result is the outcome of clustering.
+type Result struct {
+	Clusters []Cluster // largest first
+	Noise    int       // faces not in any cluster
+}
+
+// FilterFaces returns the faces worth clustering: faces with an embedding
+// that are not too small to be matched reliably (the same limits as face
+// matching).
+func FilterFaces(faces []database.StoredFace) []database.StoredFace {
+	var result []database.StoredFace
+	for i := range faces {
+		f := &faces[i]
+		if len(f.Embedding) == 0 || len(f.BBox) != 4 {
+			continue
+		}
+		width := f.BBox[2] - f.BBox[0]
+		if width < database.MinFaceWidthPx || (f.PhotoWidth > 0 && width/float64(f.PhotoWidth) < database.MinFaceWidthRel) {
+			continue
+		}
+		result = append(result, *f)
+	}
+	return result
+}
+
+// Run clusters the given faces. Faces without an embedding, or with an
+// embedding of a different dimension than the first face, are counted as
+// noise.
+func Run(ctx context.Context, faces []database.StoredFace, opts Options) (*Result, error) {
+	opts = opts.WithDefaults()
+	graph, indexed := buildGraph(faces)
+
+	neighbors := make([][]int, len(faces))
+	for i, ok := range indexed {
+		if i%1000 == 0 && ctx.Err() != nil {
+			return nil, fmt.Errorf("clustering cancelled: %w", ctx.Err())
+		}
+		if ok {
+			neighbors[i] = neighborhood(graph, faces, i, opts)
+		}
+	}
+
+	labels := dbscan(neighbors, opts.MinSize)
+	return buildResult(faces, labels, opts.MinSize), nil
+}
+
+// buildGraph indexes the faces by their position in the slice and reports
+// which faces were indexed.
+func buildGraph(faces []database.StoredFace) (*hnsw.Graph[int], []bool) {
+	g := hnsw.NewGraph[int]()
+	g.M = database.HNSWMaxNeighbors
+	g.Ml = 1.0 / float64(database.HNSWMaxNeighbors)
+	g.EfSearch = database.HNSWEfSearch
+	g.Distance = hnsw.CosineDistance
+	// A fixed seed keeps the graph, and therefore the clusters, reproducible.
+	g.Rng = rand.New(rand.NewSource(1)) //nolint:gosec // Not used for security.
+
+	indexed := make([]bool, len(faces))
+	dim := 0
+	for i := range faces {
+		emb := faces[i].Embedding
+		if len(emb) == 0 || (dim != 0 && len(emb) != dim) {
+			continue
+		}
+		dim = len(emb)
+		g.Add(hnsw.MakeNode(i, emb))
+		indexed[i] = true
+	}
+	return g, indexed
+}
+
+// neighborhood returns the faces within the threshold of face i, itself
+// included.
+func neighborhood(g *hnsw.Graph[int], faces []database.StoredFace, i int, opts Options) []int {
+	var result []int
+	for _, n := range g.Search(faces[i].Embedding, opts.MaxNeighbors) {
+		if n.Key == i || database.CosineDistance(faces[i].Embedding, n.Value) <= opts.Threshold {
+			result = append(result, n.Key)
+		}
+	}
+	if !slices.Contains(result, i) {
+		result = append(result, i)
+	}
+	return result
+}
+
+// dbscan labels every face with its cluster number or noise. A face with at
+// least minSize neighbours is a core face; clusters grow from core faces to
+// their neighbours, and faces only reachable from a core face join its
+// cluster without growing it further.
+func dbscan(neighbors [][]int, minSize int) []int {
+	labels := make([]int, len(neighbors))
+	for i := range labels {
+		labels[i] = noise
+	}
+	visited := make([]bool, len(neighbors))
+	cluster := 0
+	for i := range neighbors {
+		if visited[i] || len(neighbors[i]) < minSize {
+			continue
+		}
+		queue := []int{i}
+		visited[i] = true
+		for len(queue) > 0 {
+			face := queue[0]
+			queue = queue[1:]
+			labels[face] = cluster
+			if len(neighbors[face]) < minSize {
+				continue
+			}
+			for _, n := range neighbors[face] {
+				if !visited[n] {
+					visited[n] = true
+					queue = append(queue, n)
+				}
+			}
+		}
+		cluster++
+	}
+	return labels
+}
+
+// buildResult groups the labelled faces into clusters of at least minSize
+// faces, sorts each cluster by distance to its centroid and the clusters by
+// size.
+func buildResult(faces []database.StoredFace, labels []int, minSize int) *Result {
+	groups := make(map[int][]database.StoredFace)
+	result := &Result{}
+	for i, label := range labels {
+		if label == noise {
+			result.Noise++
+			continue
+		}
+		groups[label] = append(groups[label], faces[i])
+	}
+
+	for label := range len(groups) {
+		members := groups[label]
+		if len(members) < minSize {
+			result.Noise += len(members)
+			continue
+		}
+		result.Clusters = append(result.Clusters, newCluster(members))
+	}
+	// Stable, so equally sized clusters keep the order of their first face.
+	slices.SortStableFunc(result.Clusters, func(a, b Cluster) int {
+		return cmp.Compare(len(b.Faces), len(a.Faces))
+	})
+	return result
+}
+
+// newCluster computes the centroid of the faces and orders them by their
+// distance to it.
+func newCluster(faces []database.StoredFace) Cluster {
+	centroid := make([]float32, len(faces[0].Embedding))
+	for i := range faces {
+		for j, v := range faces[i].Embedding {
+			centroid[j] += v
+		}
+	}
+	for j := range centroid {
+		centroid[j] /= float32(len(faces))
+	}
+
+	type faceWithDist struct {
+		face database.StoredFace
+		dist float64
+	}
+	ranked := make([]faceWithDist, len(faces))
+	for i := range faces {
+		ranked[i] = faceWithDist{face: faces[i], dist: database.CosineDistance(centroid, faces[i].Embedding)}
+	}
+	slices.SortStableFunc(ranked, func(a, b faceWithDist) int {
+		return cmp.Compare(a.dist, b.dist)
+	})
+
+	c := Cluster{
+		Faces:     make([]database.StoredFace, len(ranked)),
+		Distances: make([]float64, len(ranked)),
+		Centroid:  centroid,
+	}
+	for i, r := range ranked {
+		c.Faces[i] = r.face
+		c.Distances[i] = r.dist
+	}
+	return c
+}
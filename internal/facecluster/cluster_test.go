@@ -0,0 +1,132 @@
+package facecluster
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+// person returns a random unit direction standing for one person's face.
+func person(rng *rand.Rand, dim int) []float32 {
+	v := make([]float32, dim)
+	for i := range v {
+		v[i] = float32(rng.NormFloat64())
+	}
+	return v
+}
+
+// faceOf returns an embedding close to the person's direction.
+func faceOf(rng *rand.Rand, base []float32, noise float64) []float32 {
+	v := make([]float32, len(base))
+	for i := range v {
+		v[i] = base[i] + float32(rng.NormFloat64()*noise)
+	}
+	return v
+}
+
+func TestRun(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	const dim = 64
+	alice, bob := person(rng, dim), person(rng, dim)
+
+	var faces []database.StoredFace
+	add := func(uid string, emb []float32) {
+		faces = append(faces, database.StoredFace{ID: int64(len(faces) + 1), PhotoUID: uid, Embedding: emb})
+	}
+	for i := range 8 {
+		add(fmt.Sprintf("alice%d", i), faceOf(rng, alice, 0.3))
+	}
+	for i := range 4 {
+		add(fmt.Sprintf("bob%d", i), faceOf(rng, bob, 0.3))
+	}
+	for i := range 3 {
+		add(fmt.Sprintf("stranger%d", i), person(rng, dim))
+	}
+	add("no-embedding", nil)
+
+	result, err := Run(context.Background(), faces, Options{})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(result.Clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %d", len(result.Clusters))
+	}
+	if result.Noise != 4 {
+		t.Errorf("expected 4 noise faces, got %d", result.Noise)
+	}
+
+	for i, want := range []struct {
+		prefix string
+		size   int
+	}{{"alice", 8}, {"bob", 4}} {
+		c := result.Clusters[i]
+		if len(c.Faces) != want.size {
+			t.Errorf("cluster %d: expected %d faces, got %d", i, want.size, len(c.Faces))
+		}
+		for j := range c.Faces {
+			if !strings.HasPrefix(c.Faces[j].PhotoUID, want.prefix) {
+				t.Errorf("cluster %d: unexpected face %s", i, c.Faces[j].PhotoUID)
+			}
+			if j > 0 && c.Distances[j] < c.Distances[j-1] {
+				t.Errorf("cluster %d: faces not sorted by distance to the centroid", i)
+			}
+		}
+	}
+}
+
+func TestRun_MinSize(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	base := person(rng, 32)
+	faces := make([]database.StoredFace, 3)
+	for i := range faces {
+		faces[i] = database.StoredFace{ID: int64(i + 1), Embedding: faceOf(rng, base, 0.2)}
+	}
+
+	result, err := Run(context.Background(), faces, Options{MinSize: 4})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(result.Clusters) != 0 || result.Noise != 3 {
+		t.Errorf("expected only noise below min size, got %d clusters and %d noise",
+			len(result.Clusters), result.Noise)
+	}
+}
+
+func TestRun_Empty(t *testing.T) {
+	result, err := Run(context.Background(), nil, Options{})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(result.Clusters) != 0 || result.Noise != 0 {
+		t.Errorf("expected empty result, got %+v", result)
+	}
+}
+
+func TestRun_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	faces := []database.StoredFace{{ID: 1, Embedding: []float32{1, 0}}}
+	if _, err := Run(ctx, faces, Options{}); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
+
+func TestFilterFaces(t *testing.T) {
+	emb := []float32{1, 0}
+	faces := []database.StoredFace{
+		{PhotoUID: "ok", Embedding: emb, BBox: []float64{0, 0, 100, 100}, PhotoWidth: 2000},
+		{PhotoUID: "no-width", Embedding: emb, BBox: []float64{0, 0, 100, 100}},
+		{PhotoUID: "tiny", Embedding: emb, BBox: []float64{0, 0, 20, 20}, PhotoWidth: 2000},
+		{PhotoUID: "tiny-rel", Embedding: emb, BBox: []float64{0, 0, 40, 40}, PhotoWidth: 8000},
+		{PhotoUID: "no-embedding", BBox: []float64{0, 0, 100, 100}},
+		{PhotoUID: "no-bbox", Embedding: emb},
+	}
+	got := FilterFaces(faces)
+	if len(got) != 2 || got[0].PhotoUID != "ok" || got[1].PhotoUID != "no-width" {
+		t.Errorf("unexpected faces: %+v", got)
+	}
+}
@@ -0,0 +1,233 @@
+// Package faceconfusion finds pairs of people the face model confuses, such
+// as siblings or a parent and child.
+//
+// Every person gets a centroid, the mean embedding of their assigned faces.
+// A face is confused when it is closer to the centroid of another person than
+// to the centroid of its own person. The own centroid leaves the face itself
+// out, so a person with few faces is not pulled towards each of them. Pairs
+// of people are ranked by the number of their confused faces, which points
+// at mis-assignments and at people whose faces need a closer look.
+package faceconfusion
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/facematch"
+)
+
+// Default report parameters.
+const (
+	// DefaultMinFaces is the minimum number of faces of a person to be
+	// included; centroids of fewer faces are not reliable.
+	DefaultMinFaces = 3
+
+	// DefaultExamples is the number of example faces kept per pair.
+	DefaultExamples = 5
+)
+
+// Options configures the report. Zero values use the defaults.
+type Options struct {
+	MinFaces int     // min faces with an embedding per person
+	Margin   float64 // how much closer the other centroid must be for a face to count as confused
+	Examples int     // example faces kept per pair, most confused first
+}
+
+// WithDefaults returns the options with zero values replaced by the defaults.
+func (o Options) WithDefaults() Options {
+	if o.MinFaces <= 0 {
+		o.MinFaces = DefaultMinFaces
+	}
+	o.MinFaces = max(o.MinFaces, 2) // the own centroid of a face leaves the face out
+	if o.Examples <= 0 {
+		o.Examples = DefaultExamples
+	}
+	return o
+}
+
+// Example is a face that is closer to another person's centroid than to the
+// centroid of the person it is assigned to.
+type Example struct {
+	Face          database.StoredFace
+	AssignedTo    string  // person the face is assigned to
+	ConfusedWith  string  // person whose centroid is closer
+	OwnDistance   float64 // cosine distance to the own centroid
+	OtherDistance float64 // cosine distance to the other centroid
+}
+
+// Pair is a pair of people with confused faces. PersonA sorts before PersonB.
+type Pair struct {
+	PersonA          string
+	PersonB          string
+	FacesA           int     // faces of PersonA
+	FacesB           int     // faces of PersonB
+	AConfusedAsB     int     // faces of PersonA closer to the centroid of PersonB
+	BConfusedAsA     int     // faces of PersonB closer to the centroid of PersonA
+	CentroidDistance float64 // cosine distance between the centroids
+	Examples         []Example
+}
+
+// Confused returns the number of confused faces of the pair.
+func (p *Pair) Confused() int {
+	return p.AConfusedAsB + p.BConfusedAsA
+}
+
+// Report is the outcome of the analysis.
+type Report struct {
+	People        int // people with at least MinFaces faces
+	Faces         int // faces of these people
+	ConfusedFaces int
+	Pairs         []Pair // most confused faces first
+}
+
+// person holds the faces of a single person and the sum of their embeddings.
+type person struct {
+	name  string
+	faces []*database.StoredFace
+	sum   []float32
+}
+
+// Analyze computes the confusion report for the given assigned faces. Faces
+// without an embedding or a subject name are ignored; people are matched by
+// normalized name, so "jan-novak" and "Jan Novák" are the same person.
+func Analyze(ctx context.Context, faces []database.StoredFace, opts Options) (*Report, error) {
+	opts = opts.WithDefaults()
+	people := groupPeople(faces, opts.MinFaces)
+
+	report := &Report{People: len(people)}
+	pairs := make(map[[2]int]*Pair)
+	for i, p := range people {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("confusion analysis cancelled: %w", err)
+		}
+		report.Faces += len(p.faces)
+		for _, face := range p.faces {
+			example, j, ok := classifyFace(face, i, people, opts.Margin)
+			if !ok {
+				continue
+			}
+			report.ConfusedFaces++
+			addToPair(pairs, people, i, j, example)
+		}
+	}
+
+	report.Pairs = sortPairs(pairs, opts.Examples)
+	return report, nil
+}
+
+// groupPeople groups faces by normalized subject name and drops people with
+// fewer than minFaces faces. People are sorted by name.
+func groupPeople(faces []database.StoredFace, minFaces int) []*person {
+	byName := make(map[string]*person)
+	for i := range faces {
+		f := &faces[i]
+		if len(f.Embedding) == 0 || f.SubjectName == "" {
+			continue
+		}
+		key := facematch.NormalizePersonName(f.SubjectName)
+		p, ok := byName[key]
+		if !ok {
+			p = &person{name: f.SubjectName, sum: make([]float32, len(f.Embedding))}
+			byName[key] = p
+		}
+		if len(f.Embedding) != len(p.sum) {
+			continue
+		}
+		p.faces = append(p.faces, f)
+		for d, v := range f.Embedding {
+			p.sum[d] += v
+		}
+	}
+
+	people := make([]*person, 0, len(byName))
+	for _, p := range byName {
+		if len(p.faces) >= minFaces {
+			people = append(people, p)
+		}
+	}
+	slices.SortFunc(people, func(a, b *person) int { return cmp.Compare(a.name, b.name) })
+	return people
+}
+
+// classifyFace reports whether the face of people[own] is closer to the
+// centroid of another person than to its own centroid by more than margin,
+// and if so which person. Cosine distance ignores the magnitude, so the sums
+// of embeddings serve as centroids without dividing by the face count.
+func classifyFace(
+	face *database.StoredFace, own int, people []*person, margin float64,
+) (Example, int, bool) {
+	ownSum := make([]float32, len(face.Embedding))
+	for d, v := range face.Embedding {
+		ownSum[d] = people[own].sum[d] - v
+	}
+	ownDist := database.CosineDistance(ownSum, face.Embedding)
+
+	closest, closestDist := -1, ownDist-margin
+	for j, other := range people {
+		if j == own {
+			continue
+		}
+		if dist := database.CosineDistance(other.sum, face.Embedding); dist < closestDist {
+			closest, closestDist = j, dist
+		}
+	}
+	if closest < 0 {
+		return Example{}, 0, false
+	}
+	return Example{
+		Face:          *face,
+		AssignedTo:    people[own].name,
+		ConfusedWith:  people[closest].name,
+		OwnDistance:   ownDist,
+		OtherDistance: closestDist,
+	}, closest, true
+}
+
+// addToPair records a confused face of people[i] closer to people[j].
+func addToPair(pairs map[[2]int]*Pair, people []*person, i, j int, example Example) {
+	a, b := min(i, j), max(i, j)
+	pair, ok := pairs[[2]int{a, b}]
+	if !ok {
+		pair = &Pair{
+			PersonA: people[a].name, PersonB: people[b].name,
+			FacesA: len(people[a].faces), FacesB: len(people[b].faces),
+			CentroidDistance: database.CosineDistance(people[a].sum, people[b].sum),
+		}
+		pairs[[2]int{a, b}] = pair
+	}
+	if i == a {
+		pair.AConfusedAsB++
+	} else {
+		pair.BConfusedAsA++
+	}
+	pair.Examples = append(pair.Examples, example)
+}
+
+// sortPairs returns the pairs with the most confused faces first and keeps
+// the most confused examples of each pair.
+func sortPairs(pairs map[[2]int]*Pair, examples int) []Pair {
+	result := make([]Pair, 0, len(pairs))
+	for _, p := range pairs {
+		slices.SortFunc(p.Examples, func(x, y Example) int {
+			return cmp.Or(
+				cmp.Compare(y.OwnDistance-y.OtherDistance, x.OwnDistance-x.OtherDistance),
+				cmp.Compare(x.Face.PhotoUID, y.Face.PhotoUID),
+				cmp.Compare(x.Face.FaceIndex, y.Face.FaceIndex),
+			)
+		})
+		p.Examples = p.Examples[:min(examples, len(p.Examples))]
+		result = append(result, *p)
+	}
+	slices.SortFunc(result, func(x, y Pair) int {
+		return cmp.Or(
+			cmp.Compare(y.Confused(), x.Confused()),
+			cmp.Compare(x.CentroidDistance, y.CentroidDistance),
+			cmp.Compare(x.PersonA, y.PersonA),
+			cmp.Compare(x.PersonB, y.PersonB),
+		)
+	})
+	return result
+}
@@ -0,0 +1,110 @@
+package faceconfusion
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+// direction returns a random direction standing for one person's face.
+func direction(rng *rand.Rand, dim int) []float32 {
+	v := make([]float32, dim)
+	for i := range v {
+		v[i] = float32(rng.NormFloat64())
+	}
+	return v
+}
+
+// faceOf returns an embedding close to the given direction.
+func faceOf(rng *rand.Rand, base []float32, noise float64) []float32 {
+	v := make([]float32, len(base))
+	for i := range v {
+		v[i] = base[i] + float32(rng.NormFloat64()*noise)
+	}
+	return v
+}
+
+func TestAnalyze(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	const dim = 64
+	alice, bob, carol := direction(rng, dim), direction(rng, dim), direction(rng, dim)
+
+	var faces []database.StoredFace
+	add := func(name, uid string, emb []float32) {
+		faces = append(faces, database.StoredFace{PhotoUID: uid, SubjectName: name, Embedding: emb})
+	}
+	for i := range 6 {
+		add("Alice", fmt.Sprintf("alice%d", i), faceOf(rng, alice, 0.3))
+		add("Bob", fmt.Sprintf("bob%d", i), faceOf(rng, bob, 0.3))
+		add("Carol", fmt.Sprintf("carol%d", i), faceOf(rng, carol, 0.3))
+	}
+	// Two faces of Bob wrongly assigned to Alice, one under a slug name.
+	add("Alice", "wrong1", faceOf(rng, bob, 0.3))
+	add("alice", "wrong2", faceOf(rng, bob, 0.3))
+	// Too few faces to be included.
+	add("Dave", "dave0", faceOf(rng, carol, 0.3))
+	add("Eve", "no-embedding", nil)
+
+	report, err := Analyze(context.Background(), faces, Options{})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if report.People != 3 || report.Faces != 20 {
+		t.Errorf("expected 3 people with 20 faces, got %d with %d", report.People, report.Faces)
+	}
+	if report.ConfusedFaces != 2 || len(report.Pairs) != 1 {
+		t.Fatalf("expected 2 confused faces in 1 pair, got %d in %+v", report.ConfusedFaces, report.Pairs)
+	}
+
+	pair := report.Pairs[0]
+	if pair.PersonA != "Alice" || pair.PersonB != "Bob" {
+		t.Errorf("expected pair Alice/Bob, got %s/%s", pair.PersonA, pair.PersonB)
+	}
+	if pair.AConfusedAsB != 2 || pair.BConfusedAsA != 0 || pair.Confused() != 2 {
+		t.Errorf("expected 2 faces of Alice confused as Bob, got %d/%d", pair.AConfusedAsB, pair.BConfusedAsA)
+	}
+	for _, ex := range pair.Examples {
+		if ex.ConfusedWith != "Bob" || ex.OtherDistance >= ex.OwnDistance {
+			t.Errorf("unexpected example %s: %+v", ex.Face.PhotoUID, ex)
+		}
+	}
+}
+
+func TestAnalyze_Margin(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	const dim = 64
+	alice, bob := direction(rng, dim), direction(rng, dim)
+
+	var faces []database.StoredFace
+	for i := range 5 {
+		faces = append(faces,
+			database.StoredFace{PhotoUID: fmt.Sprintf("a%d", i), SubjectName: "Alice", Embedding: faceOf(rng, alice, 0.3)},
+			database.StoredFace{PhotoUID: fmt.Sprintf("b%d", i), SubjectName: "Bob", Embedding: faceOf(rng, bob, 0.3)},
+		)
+	}
+	faces = append(faces, database.StoredFace{PhotoUID: "wrong", SubjectName: "Alice", Embedding: faceOf(rng, bob, 0.3)})
+
+	report, err := Analyze(context.Background(), faces, Options{Margin: 2})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if report.ConfusedFaces != 0 || len(report.Pairs) != 0 {
+		t.Errorf("expected no confused faces with a margin of 2, got %+v", report.Pairs)
+	}
+}
+
+func TestAnalyze_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	faces := []database.StoredFace{
+		{PhotoUID: "a", SubjectName: "Alice", Embedding: []float32{1, 0}},
+		{PhotoUID: "b", SubjectName: "Alice", Embedding: []float32{1, 0.1}},
+	}
+
+	if _, err := Analyze(ctx, faces, Options{MinFaces: 2}); err == nil {
+		t.Error("expected an error after cancellation")
+	}
+}
@@ -0,0 +1,204 @@
+package facematch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/kozaktomas/photo-sorter/internal/constants"
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+)
+
+// DefaultApplyConcurrency is the default number of faces applied in parallel.
+const DefaultApplyConcurrency = 5
+
+// FaceRef identifies a detected face by its photo and face index.
+type FaceRef struct {
+	PhotoUID  string `json:"photo_uid"`
+	FaceIndex int    `json:"face_index"`
+}
+
+// ApplyResult is the outcome of assigning a person to a single face.
+type ApplyResult struct {
+	PhotoUID   string      `json:"photo_uid"`
+	FaceIndex  int         `json:"face_index"`
+	Action     MatchAction `json:"action,omitempty"`
+	MarkerUID  string      `json:"marker_uid,omitempty"`
+	Success    bool        `json:"success"`
+	Error      string      `json:"error,omitempty"`
+	CacheError string      `json:"cache_error,omitempty"` // marker applied, but the faces cache was not updated
+}
+
+// ApplySummary counts the outcomes of a bulk apply.
+type ApplySummary struct {
+	CreatedMarkers int `json:"created_markers"`
+	AssignedPeople int `json:"assigned_people"`
+	AlreadyDone    int `json:"already_done"`
+	Failed         int `json:"failed"`
+}
+
+// MarkerClient creates and updates PhotoPrism markers.
+type MarkerClient interface {
+	CreateMarker(marker photoprism.MarkerCreate) (*photoprism.Marker, error)
+	UpdateMarker(markerUID string, update photoprism.MarkerUpdate) (*photoprism.Marker, error)
+}
+
+// FaceStore reads faces from and updates marker data in the faces cache.
+type FaceStore interface {
+	GetFaces(ctx context.Context, photoUID string) ([]database.StoredFace, error)
+	UpdateFaceMarker(ctx context.Context, photoUID string, faceIndex int, markerUID, subjectUID, subjectName string) error
+}
+
+// Summarize counts the outcomes of the results.
+func Summarize(results []ApplyResult) ApplySummary {
+	var s ApplySummary
+	for i := range results {
+		switch {
+		case !results[i].Success:
+			s.Failed++
+		case results[i].Action == ActionCreateMarker:
+			s.CreatedMarkers++
+		case results[i].Action == ActionAssignPerson:
+			s.AssignedPeople++
+		case results[i].Action == ActionAlreadyDone:
+			s.AlreadyDone++
+		}
+	}
+	return s
+}
+
+// ApplyPerson assigns personName to every referenced face: it creates a face
+// marker in PhotoPrism where the face has none and assigns the person to the
+// existing marker otherwise, then updates the faces cache. Faces are applied
+// in parallel; a failing face is reported in its result and does not stop
+// the others. Faces already assigned to a different person fail rather than
+// being reassigned. Results are in the order of refs, duplicates removed.
+func ApplyPerson(
+	ctx context.Context, client MarkerClient, store FaceStore, refs []FaceRef, personName string, concurrency int,
+) []ApplyResult {
+	refs = uniqueRefs(refs)
+	if concurrency <= 0 {
+		concurrency = DefaultApplyConcurrency
+	}
+
+	results := make([]ApplyResult, len(refs))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(refs)) {
+		wg.Go(func() {
+			for i := range work {
+				results[i] = applyPersonToFace(ctx, client, store, refs[i], personName)
+			}
+		})
+	}
+	for i := range refs {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+	return results
+}
+
+// uniqueRefs returns refs without duplicates, keeping the first occurrence.
+func uniqueRefs(refs []FaceRef) []FaceRef {
+	seen := make(map[FaceRef]bool, len(refs))
+	result := make([]FaceRef, 0, len(refs))
+	for _, ref := range refs {
+		if !seen[ref] {
+			seen[ref] = true
+			result = append(result, ref)
+		}
+	}
+	return result
+}
+
+// applyPersonToFace applies the person to a single face.
+func applyPersonToFace(
+	ctx context.Context, client MarkerClient, store FaceStore, ref FaceRef, personName string,
+) ApplyResult {
+	result := ApplyResult{PhotoUID: ref.PhotoUID, FaceIndex: ref.FaceIndex}
+	if err := ctx.Err(); err != nil {
+		result.Error = fmt.Sprintf("cancelled: %v", err)
+		return result
+	}
+
+	face, err := findFace(ctx, store, ref)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.MarkerUID = face.MarkerUID
+
+	if face.SubjectName != "" {
+		if NormalizePersonName(face.SubjectName) != NormalizePersonName(personName) {
+			result.Error = fmt.Sprintf("face is already assigned to %s", face.SubjectName)
+			return result
+		}
+		result.Action = ActionAlreadyDone
+		result.Success = true
+		return result
+	}
+
+	var marker *photoprism.Marker
+	if face.MarkerUID == "" {
+		result.Action = ActionCreateMarker
+		marker, err = createFaceMarker(client, face, personName)
+	} else {
+		result.Action = ActionAssignPerson
+		marker, err = client.UpdateMarker(face.MarkerUID, photoprism.MarkerUpdate{Name: personName, SubjSrc: "manual"})
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if marker.UID != "" {
+		result.MarkerUID = marker.UID
+	}
+	result.Success = true
+
+	err = store.UpdateFaceMarker(ctx, ref.PhotoUID, ref.FaceIndex, result.MarkerUID, marker.SubjUID, personName)
+	if err != nil {
+		result.CacheError = err.Error()
+	}
+	return result
+}
+
+// findFace returns the cached face with the referenced index.
+func findFace(ctx context.Context, store FaceStore, ref FaceRef) (*database.StoredFace, error) {
+	faces, err := store.GetFaces(ctx, ref.PhotoUID)
+	if err != nil {
+		return nil, fmt.Errorf("loading faces: %w", err)
+	}
+	for i := range faces {
+		if faces[i].FaceIndex == ref.FaceIndex {
+			return &faces[i], nil
+		}
+	}
+	return nil, errors.New("face not found")
+}
+
+// createFaceMarker creates a face marker named personName at the face's
+// bounding box.
+func createFaceMarker(client MarkerClient, face *database.StoredFace, personName string) (*photoprism.Marker, error) {
+	if face.FileUID == "" || face.PhotoWidth <= 0 || face.PhotoHeight <= 0 || len(face.BBox) != 4 {
+		return nil, errors.New("photo file info not cached, run cache sync first")
+	}
+	bboxRel := ConvertPixelBBoxToDisplayRelative(face.BBox, face.PhotoWidth, face.PhotoHeight, face.Orientation)
+	marker, err := client.CreateMarker(photoprism.MarkerCreate{
+		FileUID: face.FileUID,
+		Type:    constants.MarkerTypeFace,
+		X:       bboxRel[0],
+		Y:       bboxRel[1],
+		W:       bboxRel[2],
+		H:       bboxRel[3],
+		Name:    personName,
+		Src:     "manual",
+		SubjSrc: "manual",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating marker: %w", err)
+	}
+	return marker, nil
+}
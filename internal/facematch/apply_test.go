@@ -0,0 +1,138 @@
+package facematch_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/database/mock"
+	"github.com/kozaktomas/photo-sorter/internal/facematch"
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+)
+
+// stubMarkerClient records marker calls and fails for the configured marker UIDs.
+type stubMarkerClient struct {
+	mu      sync.Mutex
+	created []photoprism.MarkerCreate
+	updated []string
+	failUID string
+}
+
+func (c *stubMarkerClient) CreateMarker(m photoprism.MarkerCreate) (*photoprism.Marker, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.created = append(c.created, m)
+	return &photoprism.Marker{UID: "new-marker", SubjUID: "subj-new"}, nil
+}
+
+func (c *stubMarkerClient) UpdateMarker(uid string, _ photoprism.MarkerUpdate) (*photoprism.Marker, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if uid == c.failUID {
+		return nil, errors.New("marker not found")
+	}
+	c.updated = append(c.updated, uid)
+	return &photoprism.Marker{UID: uid, SubjUID: "subj-new"}, nil
+}
+
+func newApplyStore() *mock.MockFaceWriter {
+	store := mock.NewMockFaceWriter()
+	store.AddFaces("p1", []database.StoredFace{
+		{PhotoUID: "p1", FaceIndex: 0, BBox: []float64{100, 100, 200, 200},
+			PhotoWidth: 1000, PhotoHeight: 500, Orientation: 1, FileUID: "f1"},
+		{PhotoUID: "p1", FaceIndex: 1, MarkerUID: "m1"},
+	})
+	store.AddFaces("p2", []database.StoredFace{
+		{PhotoUID: "p2", FaceIndex: 0, MarkerUID: "m2", SubjectName: "Jan Novák", SubjectUID: "s1"},
+		{PhotoUID: "p2", FaceIndex: 1, MarkerUID: "m3", SubjectName: "Someone Else", SubjectUID: "s2"},
+		{PhotoUID: "p2", FaceIndex: 2, MarkerUID: "broken"},
+		{PhotoUID: "p2", FaceIndex: 3}, // no file info cached
+	})
+	return store
+}
+
+func TestApplyPerson(t *testing.T) {
+	store := newApplyStore()
+	client := &stubMarkerClient{failUID: "broken"}
+	refs := []facematch.FaceRef{
+		{PhotoUID: "p1", FaceIndex: 0},
+		{PhotoUID: "p1", FaceIndex: 1},
+		{PhotoUID: "p1", FaceIndex: 1}, // duplicate
+		{PhotoUID: "p2", FaceIndex: 0},
+		{PhotoUID: "p2", FaceIndex: 1},
+		{PhotoUID: "p2", FaceIndex: 2},
+		{PhotoUID: "p2", FaceIndex: 3},
+		{PhotoUID: "p3", FaceIndex: 0},
+	}
+
+	results := facematch.ApplyPerson(context.Background(), client, store, refs, "jan-novak", 3)
+
+	want := []struct {
+		action  facematch.MatchAction
+		success bool
+		marker  string
+	}{
+		{facematch.ActionCreateMarker, true, "new-marker"},
+		{facematch.ActionAssignPerson, true, "m1"},
+		{facematch.ActionAlreadyDone, true, "m2"},
+		{"", false, "m3"},
+		{facematch.ActionAssignPerson, false, "broken"},
+		{facematch.ActionCreateMarker, false, ""},
+		{"", false, ""},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(results))
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.Action != w.action || r.Success != w.success || r.MarkerUID != w.marker {
+			t.Errorf("result %d (%s/%d): got action=%q success=%v marker=%q error=%q, want %q %v %q",
+				i, r.PhotoUID, r.FaceIndex, r.Action, r.Success, r.MarkerUID, r.Error, w.action, w.success, w.marker)
+		}
+		if !r.Success && r.Error == "" {
+			t.Errorf("result %d: expected an error message", i)
+		}
+	}
+
+	if len(client.created) != 1 || client.created[0].FileUID != "f1" || client.created[0].Name != "jan-novak" {
+		t.Errorf("unexpected created markers: %+v", client.created)
+	}
+	if c := client.created[0]; c.X != 0.1 || c.Y != 0.2 || c.W != 0.1 || c.H != 0.2 {
+		t.Errorf("unexpected marker bbox: %+v", c)
+	}
+	if len(store.UpdateMarkerCalls) != 2 {
+		t.Errorf("expected 2 cache updates, got %+v", store.UpdateMarkerCalls)
+	}
+
+	summary := facematch.Summarize(results)
+	if summary != (facematch.ApplySummary{CreatedMarkers: 1, AssignedPeople: 1, AlreadyDone: 1, Failed: 4}) {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
+
+func TestApplyPerson_CacheError(t *testing.T) {
+	store := newApplyStore()
+	store.UpdateMarkerError = errors.New("db down")
+	refs := []facematch.FaceRef{{PhotoUID: "p1", FaceIndex: 1}}
+
+	results := facematch.ApplyPerson(context.Background(), &stubMarkerClient{}, store, refs, "Jan", 0)
+
+	if !results[0].Success || results[0].CacheError == "" {
+		t.Errorf("expected success with cache error, got %+v", results[0])
+	}
+}
+
+func TestApplyPerson_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client := &stubMarkerClient{}
+	refs := []facematch.FaceRef{{PhotoUID: "p1", FaceIndex: 1}}
+
+	results := facematch.ApplyPerson(ctx, client, newApplyStore(), refs, "Jan", 0)
+
+	if results[0].Success || len(client.updated) != 0 {
+		t.Errorf("expected nothing applied after cancellation, got %+v", results[0])
+	}
+}
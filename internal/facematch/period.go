@@ -0,0 +1,188 @@
+package facematch
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+// DefaultPeriodYears is the default length of a period for age-aware matching.
+const DefaultPeriodYears = 10
+
+// DatedFace is a face embedding with the year its photo was taken (0 = unknown)
+// and its weight in the centroid, usually the face quality (0 = weight 1).
+type DatedFace struct {
+	Embedding []float32
+	Year      int
+	Weight    float64
+}
+
+// Period is a range of years, both ends inclusive.
+type Period struct {
+	From int
+	To   int
+}
+
+// String returns the period as "1980-1989".
+func (p Period) String() string {
+	return fmt.Sprintf("%d-%d", p.From, p.To)
+}
+
+// yearDistance returns the number of years between year and the period.
+func (p Period) yearDistance(year int) int {
+	switch {
+	case year < p.From:
+		return p.From - year
+	case year > p.To:
+		return year - p.To
+	default:
+		return 0
+	}
+}
+
+// PeriodCentroid is the mean embedding of a person's faces from one period,
+// so faces from childhood and adulthood are not averaged together.
+type PeriodCentroid struct {
+	Period   Period
+	Centroid []float32
+	Faces    int
+}
+
+// PeriodCentroids are the per-period centroids of a person, oldest period first.
+type PeriodCentroids []PeriodCentroid
+
+// PhotoYear returns the year a photo was taken from PhotoPrism's Year field,
+// falling back to TakenAt when the year is missing. PhotoPrism reports an
+// unknown year as -1, which returns 0 without looking at TakenAt, as TakenAt
+// is then only a guess such as the file time.
+func PhotoYear(year int, takenAt string) int {
+	if year > 0 {
+		return year
+	}
+	if year < 0 || takenAt == "" {
+		return 0
+	}
+	t, err := time.Parse(time.RFC3339, takenAt)
+	if err != nil || t.Year() <= 1 {
+		return 0
+	}
+	return t.Year()
+}
+
+// BuildPeriodCentroids buckets faces into periods of periodYears years by the
+// year of their photo and returns the centroid of every period with at least
+// minFaces faces. Centroids are weighted means, so low quality faces pull
+// them less. Faces with an unknown year are left out.
+func BuildPeriodCentroids(faces []DatedFace, periodYears, minFaces int) PeriodCentroids {
+	if periodYears <= 0 {
+		periodYears = DefaultPeriodYears
+	}
+	sums := make(map[int][]float32)
+	counts := make(map[int]int)
+	weights := make(map[int]float64)
+	for _, f := range faces {
+		if f.Year <= 0 || len(f.Embedding) == 0 {
+			continue
+		}
+		from := f.Year - f.Year%periodYears
+		sum, ok := sums[from]
+		if !ok {
+			sum = make([]float32, len(f.Embedding))
+			sums[from] = sum
+		}
+		if len(f.Embedding) != len(sum) {
+			continue
+		}
+		w := f.Weight
+		if w <= 0 {
+			w = 1
+		}
+		for i, v := range f.Embedding {
+			sum[i] += float32(w) * v
+		}
+		counts[from]++
+		weights[from] += w
+	}
+
+	var centroids PeriodCentroids
+	for from, sum := range sums {
+		n := counts[from]
+		if n < max(minFaces, 1) {
+			continue
+		}
+		for i := range sum {
+			sum[i] /= float32(weights[from])
+		}
+		centroids = append(centroids, PeriodCentroid{
+			Period: Period{From: from, To: from + periodYears - 1}, Centroid: sum, Faces: n,
+		})
+	}
+	slices.SortFunc(centroids, func(a, b PeriodCentroid) int { return cmp.Compare(a.Period.From, b.Period.From) })
+	return centroids
+}
+
+// Nearest returns the centroid whose period is nearest to the year, preferring
+// the period with more faces when two are equally near. It returns nil when
+// there are no centroids or the year is unknown.
+func (pc PeriodCentroids) Nearest(year int) *PeriodCentroid {
+	if year <= 0 {
+		return nil
+	}
+	var best *PeriodCentroid
+	for i := range pc {
+		c := &pc[i]
+		if best == nil {
+			best = c
+			continue
+		}
+		d, bestD := c.Period.yearDistance(year), best.Period.yearDistance(year)
+		if d < bestD || (d == bestD && c.Faces > best.Faces) {
+			best = c
+		}
+	}
+	return best
+}
+
+// Rank returns the centroid a candidate face from a photo taken in year is
+// ranked by and the cosine distance of the embedding to it: the centroid of
+// the nearest period. When the year is unknown or there are no centroids, it
+// returns nil and the distance to global, the centroid of all faces.
+func (pc PeriodCentroids) Rank(embedding []float32, year int, global []float32) (*PeriodCentroid, float64) {
+	if c := pc.Nearest(year); c != nil {
+		return c, database.CosineDistance(c.Centroid, embedding)
+	}
+	return nil, database.CosineDistance(global, embedding)
+}
+
+// BuildCentroid returns the weighted mean of all face embeddings, whatever
+// their year. It returns nil when there are no faces.
+func BuildCentroid(faces []DatedFace) []float32 {
+	var sum []float32
+	weights := 0.0
+	for _, f := range faces {
+		if len(f.Embedding) == 0 {
+			continue
+		}
+		if sum == nil {
+			sum = make([]float32, len(f.Embedding))
+		}
+		if len(f.Embedding) != len(sum) {
+			continue
+		}
+		w := f.Weight
+		if w <= 0 {
+			w = 1
+		}
+		for i, v := range f.Embedding {
+			sum[i] += float32(w) * v
+		}
+		weights += w
+	}
+	for i := range sum {
+		sum[i] /= float32(weights)
+	}
+	return sum
+}
@@ -0,0 +1,123 @@
+package facematch
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPhotoYear(t *testing.T) {
+	tests := []struct {
+		name    string
+		year    int
+		takenAt string
+		want    int
+	}{
+		{"year set", 1987, "2020-01-01T00:00:00Z", 1987},
+		{"unknown year", -1, "2020-01-01T00:00:00Z", 0},
+		{"missing year uses taken at", 0, "1995-06-15T10:00:00Z", 1995},
+		{"missing year and taken at", 0, "", 0},
+		{"invalid taken at", 0, "yesterday", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PhotoYear(tt.year, tt.takenAt); got != tt.want {
+				t.Errorf("PhotoYear(%d, %q) = %d, want %d", tt.year, tt.takenAt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildPeriodCentroids(t *testing.T) {
+	faces := []DatedFace{
+		{Embedding: []float32{1, 0}, Year: 1983},
+		{Embedding: []float32{0.8, 0.2}, Year: 1989},
+		{Embedding: []float32{0, 1}, Year: 2015},
+		{Embedding: []float32{0, 1}, Year: 2019},
+		{Embedding: []float32{1, 1}, Year: 2001}, // single face, below minFaces
+		{Embedding: []float32{1, 1}, Year: 0},    // unknown year
+	}
+
+	centroids := BuildPeriodCentroids(faces, 10, 2)
+
+	if len(centroids) != 2 {
+		t.Fatalf("expected 2 periods, got %+v", centroids)
+	}
+	if centroids[0].Period.String() != "1980-1989" || centroids[0].Faces != 2 {
+		t.Errorf("unexpected first period: %+v", centroids[0])
+	}
+	if c := centroids[0].Centroid; c[0] != 0.9 || c[1] != 0.1 {
+		t.Errorf("unexpected centroid: %v", c)
+	}
+	if centroids[1].Period.String() != "2010-2019" {
+		t.Errorf("unexpected second period: %+v", centroids[1])
+	}
+}
+
+func TestBuildPeriodCentroids_Weighted(t *testing.T) {
+	faces := []DatedFace{
+		{Embedding: []float32{1, 0}, Year: 1983, Weight: 0.75},
+		{Embedding: []float32{0, 1}, Year: 1985, Weight: 0.25},
+	}
+
+	centroids := BuildPeriodCentroids(faces, 10, 2)
+
+	if len(centroids) != 1 || centroids[0].Faces != 2 {
+		t.Fatalf("expected 1 period of 2 faces, got %+v", centroids)
+	}
+	if c := centroids[0].Centroid; c[0] != 0.75 || c[1] != 0.25 {
+		t.Errorf("unexpected weighted centroid: %v", c)
+	}
+}
+
+func TestPeriodCentroids_Rank(t *testing.T) {
+	centroids := PeriodCentroids{
+		{Period: Period{From: 1980, To: 1989}, Centroid: []float32{1, 0}, Faces: 5},
+		{Period: Period{From: 2010, To: 2019}, Centroid: []float32{0, 1}, Faces: 20},
+	}
+	global := []float32{1, 1}
+
+	tests := []struct {
+		name       string
+		year       int
+		embedding  []float32
+		wantPeriod string
+		wantDist   float64
+	}{
+		{"inside period", 1985, []float32{0, 1}, "1980-1989", 1},
+		{"nearest period", 1993, []float32{0, 1}, "1980-1989", 1},
+		{"later period", 2024, []float32{0, 1}, "2010-2019", 0},
+		{"unknown year uses global centroid", 0, []float32{1, 1}, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, dist := centroids.Rank(tt.embedding, tt.year, global)
+			period := ""
+			if c != nil {
+				period = c.Period.String()
+			}
+			if period != tt.wantPeriod || math.Abs(dist-tt.wantDist) > 1e-6 {
+				t.Errorf("got period %q at %f, want %q at %f", period, dist, tt.wantPeriod, tt.wantDist)
+			}
+		})
+	}
+
+	c, dist := PeriodCentroids(nil).Rank([]float32{1, 0}, 1985, global)
+	if c != nil || math.Abs(dist-(1-math.Sqrt2/2)) > 1e-6 {
+		t.Errorf("expected global centroid, got %+v at %f", c, dist)
+	}
+}
+
+func TestBuildCentroid(t *testing.T) {
+	faces := []DatedFace{
+		{Embedding: []float32{1, 0}, Year: 1983, Weight: 0.75},
+		{Embedding: []float32{0, 1}, Weight: 0.25}, // unknown year counts too
+		{},
+	}
+
+	if c := BuildCentroid(faces); len(c) != 2 || c[0] != 0.75 || c[1] != 0.25 {
+		t.Errorf("unexpected centroid: %v", c)
+	}
+	if c := BuildCentroid(nil); c != nil {
+		t.Errorf("expected no centroid, got %v", c)
+	}
+}
@@ -0,0 +1,200 @@
+// Package facequality scores how useful a detected face is for recognition,
+// so blurry, tiny and profile faces do not pollute person centroids and
+// matches.
+//
+// The score combines up to four components, each between 0 and 1: the
+// sharpness of the face crop (variance of the Laplacian), the face width in
+// pixels, the detection score of the face detector and the left/right
+// symmetry of the facial landmarks, which drops for faces turned to profile.
+// Components that are not available (no image, no landmarks) are left out.
+// The components are combined by a weighted geometric mean, so a single
+// very poor component pulls the whole score down.
+package facequality
+
+import (
+	"bytes"
+	"image"
+	_ "image/jpeg" // register JPEG decoder
+	_ "image/png"  // register PNG decoder
+	"math"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"golang.org/x/image/draw"
+)
+
+// Component weights of the score.
+const (
+	weightSharpness = 0.35
+	weightSize      = 0.25
+	weightDetection = 0.2
+	weightSymmetry  = 0.2
+)
+
+const (
+	// goodFaceWidthPx is the face width in pixels from which a face scores
+	// full marks for its size. Faces narrower than database.MinFaceWidthPx
+	// score the minimum.
+	goodFaceWidthPx = 150
+
+	// sharpnessCropSize is the size of the square grayscale crop the
+	// sharpness is measured on, so faces of any size are comparable.
+	sharpnessCropSize = 64
+
+	// sharpnessHalf is the Laplacian variance of a crop that scores 0.5.
+	sharpnessHalf = 100.0
+
+	// minComponent is the lowest value of a component, keeping the
+	// geometric mean defined for components of 0.
+	minComponent = 0.01
+
+	// neutralQuality is used for faces nothing is known about.
+	neutralQuality = 0.5
+)
+
+// Face holds what the quality of a detected face is computed from.
+type Face struct {
+	BBox      []float64   // [x1, y1, x2, y2] in pixels of the image
+	DetScore  float64     // detection score of the face detector (0 = unknown)
+	Landmarks [][]float64 // optional five keypoints: eyes, nose, mouth corners
+}
+
+// DecodeImage decodes image data for Score. It returns nil when the image
+// cannot be decoded (e.g. HEIC), in which case Score leaves out sharpness.
+func DecodeImage(data []byte) image.Image {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	return img
+}
+
+// Score returns the quality of a face between 0 and 1, higher is better.
+// The image may be nil and the landmarks empty; the score then uses the
+// remaining components. It returns 0 when no component is available.
+func Score(img image.Image, f Face) float64 {
+	var logSum, weightSum float64
+	add := func(value, weight float64) {
+		logSum += weight * math.Log(max(minComponent, min(value, 1)))
+		weightSum += weight
+	}
+
+	if v, ok := Sharpness(img, f.BBox); ok {
+		add(v, weightSharpness)
+	}
+	if v, ok := Size(f.BBox); ok {
+		add(v, weightSize)
+	}
+	if f.DetScore > 0 {
+		add(f.DetScore, weightDetection)
+	}
+	if v, ok := Symmetry(f.Landmarks); ok {
+		add(v, weightSymmetry)
+	}
+
+	if weightSum == 0 {
+		return 0
+	}
+	return math.Exp(logSum / weightSum)
+}
+
+// ForFace returns the quality of a stored face: the stored score, or for
+// faces stored before scoring existed an estimate from the bounding box and
+// detection score. Faces nothing is known about get a neutral 0.5.
+func ForFace(f *database.StoredFace) float64 {
+	if f.Quality > 0 {
+		return f.Quality
+	}
+	if q := Score(nil, Face{BBox: f.BBox, DetScore: f.DetScore}); q > 0 {
+		return q
+	}
+	return neutralQuality
+}
+
+// Size scores the width of the face bounding box: 0 at the minimum face
+// width for matching, rising linearly to 1 at goodFaceWidthPx.
+func Size(bbox []float64) (float64, bool) {
+	if len(bbox) != 4 {
+		return 0, false
+	}
+	width := bbox[2] - bbox[0]
+	v := (width - database.MinFaceWidthPx) / (goodFaceWidthPx - database.MinFaceWidthPx)
+	return max(0, min(v, 1)), true
+}
+
+// Sharpness scores the sharpness of the face crop by the variance of its
+// Laplacian: blurry crops have few edges and a low variance. The crop is
+// scaled to a fixed size first. It reports false when there is no image or
+// the bounding box does not overlap it.
+func Sharpness(img image.Image, bbox []float64) (float64, bool) {
+	if img == nil || len(bbox) != 4 {
+		return 0, false
+	}
+	rect := image.Rect(
+		int(math.Floor(bbox[0])), int(math.Floor(bbox[1])), int(math.Ceil(bbox[2])), int(math.Ceil(bbox[3])),
+	).Intersect(img.Bounds())
+	if rect.Dx() < 3 || rect.Dy() < 3 {
+		return 0, false
+	}
+
+	crop := image.NewGray(image.Rect(0, 0, sharpnessCropSize, sharpnessCropSize))
+	draw.BiLinear.Scale(crop, crop.Bounds(), img, rect, draw.Src, nil)
+
+	variance := laplacianVariance(crop)
+	return variance / (variance + sharpnessHalf), true
+}
+
+// laplacianVariance returns the variance of the 4-neighbour Laplacian of a
+// grayscale image.
+func laplacianVariance(img *image.Gray) float64 {
+	b := img.Bounds()
+	at := func(x, y int) float64 { return float64(img.GrayAt(x, y).Y) }
+
+	var sum, sumSq float64
+	n := 0
+	for y := b.Min.Y + 1; y < b.Max.Y-1; y++ {
+		for x := b.Min.X + 1; x < b.Max.X-1; x++ {
+			l := 4*at(x, y) - at(x-1, y) - at(x+1, y) - at(x, y-1) - at(x, y+1)
+			sum += l
+			sumSq += l * l
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	mean := sum / float64(n)
+	return sumSq/float64(n) - mean*mean
+}
+
+// Symmetry scores how frontal a face is from its five landmarks (left eye,
+// right eye, nose, left and right mouth corner): the nose of a frontal face
+// lies half-way between the eyes and between the mouth corners, while in a
+// profile it moves towards one side. It reports false without landmarks.
+func Symmetry(landmarks [][]float64) (float64, bool) {
+	if len(landmarks) < 5 {
+		return 0, false
+	}
+	for _, p := range landmarks[:5] {
+		if len(p) < 2 {
+			return 0, false
+		}
+	}
+	leftEye, rightEye, nose, leftMouth, rightMouth := landmarks[0], landmarks[1], landmarks[2], landmarks[3], landmarks[4]
+	return (balance(nose, leftEye, rightEye) + balance(nose, leftMouth, rightMouth)) / 2, true
+}
+
+// balance returns how centred point p is between a and b along the line
+// through them: 1 half-way between them, falling to 0 at either end. Using
+// the line instead of the horizontal keeps tilted heads frontal.
+func balance(p, a, b []float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	length := dx*dx + dy*dy
+	if length == 0 {
+		return 0
+	}
+	t := ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / length
+	if t <= 0 || t >= 1 {
+		return 0
+	}
+	return min(t, 1-t) / max(t, 1-t)
+}
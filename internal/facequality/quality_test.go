@@ -0,0 +1,198 @@
+package facequality
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+// checkerboard returns an image with sharp 4px squares.
+func checkerboard(w, h int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			if (x/4+y/4)%2 == 0 {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+	return img
+}
+
+// gradient returns an image with a smooth horizontal gradient and no edges.
+func gradient(w, h int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.SetGray(x, y, color.Gray{Y: uint8(x * 255 / w)}) //nolint:gosec // x < w keeps it in range
+		}
+	}
+	return img
+}
+
+// frontal are the landmarks of a face looking straight at the camera.
+var frontal = [][]float64{{40, 40}, {80, 40}, {60, 60}, {45, 80}, {75, 80}}
+
+// profile are the landmarks of a face turned to the side.
+var profile = [][]float64{{70, 40}, {85, 40}, {82, 60}, {72, 80}, {86, 80}}
+
+func TestSharpness(t *testing.T) {
+	bbox := []float64{0, 0, 200, 200}
+
+	sharp, ok := Sharpness(checkerboard(200, 200), bbox)
+	if !ok {
+		t.Fatal("expected sharpness for the checkerboard")
+	}
+	blurry, ok := Sharpness(gradient(200, 200), bbox)
+	if !ok {
+		t.Fatal("expected sharpness for the gradient")
+	}
+	if sharp < 0.9 {
+		t.Errorf("checkerboard sharpness = %.3f, want >= 0.9", sharp)
+	}
+	if blurry > 0.1 {
+		t.Errorf("gradient sharpness = %.3f, want <= 0.1", blurry)
+	}
+}
+
+func TestSharpnessUnavailable(t *testing.T) {
+	img := checkerboard(100, 100)
+	tests := []struct {
+		name string
+		img  image.Image
+		bbox []float64
+	}{
+		{"no image", nil, []float64{0, 0, 50, 50}},
+		{"no bbox", img, nil},
+		{"bbox outside image", img, []float64{200, 200, 300, 300}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := Sharpness(tt.img, tt.bbox); ok {
+				t.Error("expected no sharpness")
+			}
+		})
+	}
+}
+
+func TestSize(t *testing.T) {
+	tests := []struct {
+		name  string
+		width float64
+		want  float64
+	}{
+		{"below minimum", 20, 0},
+		{"minimum", database.MinFaceWidthPx, 0},
+		{"halfway", (database.MinFaceWidthPx + goodFaceWidthPx) / 2.0, 0.5},
+		{"good", goodFaceWidthPx, 1},
+		{"large", 800, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Size([]float64{100, 100, 100 + tt.width, 100 + tt.width})
+			if !ok {
+				t.Fatal("expected a size score")
+			}
+			if diff := got - tt.want; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Size() = %.3f, want %.3f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSymmetry(t *testing.T) {
+	front, ok := Symmetry(frontal)
+	if !ok {
+		t.Fatal("expected symmetry for frontal landmarks")
+	}
+	side, ok := Symmetry(profile)
+	if !ok {
+		t.Fatal("expected symmetry for profile landmarks")
+	}
+	if front < 0.99 {
+		t.Errorf("frontal symmetry = %.3f, want ~1", front)
+	}
+	if side >= 0.5 {
+		t.Errorf("profile symmetry = %.3f, want < 0.5", side)
+	}
+
+	if _, ok := Symmetry(frontal[:3]); ok {
+		t.Error("expected no symmetry for fewer than five landmarks")
+	}
+	if _, ok := Symmetry([][]float64{{1}, {2}, {3}, {4}, {5}}); ok {
+		t.Error("expected no symmetry for landmarks without coordinates")
+	}
+}
+
+func TestScore(t *testing.T) {
+	sharp := checkerboard(400, 400)
+	blurry := gradient(400, 400)
+	bbox := []float64{100, 100, 300, 300}
+
+	good := Score(sharp, Face{BBox: bbox, DetScore: 0.9, Landmarks: frontal})
+	if good < 0.85 || good > 1 {
+		t.Errorf("good face score = %.3f, want in [0.85, 1]", good)
+	}
+
+	worse := []struct {
+		name string
+		img  image.Image
+		face Face
+	}{
+		{"blurry", blurry, Face{BBox: bbox, DetScore: 0.9, Landmarks: frontal}},
+		{"profile", sharp, Face{BBox: bbox, DetScore: 0.9, Landmarks: profile}},
+		{"low detection score", sharp, Face{BBox: bbox, DetScore: 0.3, Landmarks: frontal}},
+		{"small", sharp, Face{BBox: []float64{100, 100, 140, 140}, DetScore: 0.9, Landmarks: frontal}},
+	}
+	for _, tt := range worse {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Score(tt.img, tt.face); got >= good-0.1 {
+				t.Errorf("score = %.3f, want clearly below %.3f", got, good)
+			}
+		})
+	}
+}
+
+func TestScoreWithoutComponents(t *testing.T) {
+	if got := Score(nil, Face{}); got != 0 {
+		t.Errorf("Score() = %.3f, want 0", got)
+	}
+	// Missing components are left out instead of counting as 0.
+	if got := Score(nil, Face{DetScore: 0.8}); got < 0.79 || got > 0.81 {
+		t.Errorf("Score() = %.3f, want 0.8", got)
+	}
+}
+
+func TestForFace(t *testing.T) {
+	stored := &database.StoredFace{Quality: 0.42, BBox: []float64{0, 0, 200, 200}, DetScore: 0.9}
+	if got := ForFace(stored); got != 0.42 {
+		t.Errorf("ForFace(stored) = %.3f, want 0.42", got)
+	}
+
+	estimated := &database.StoredFace{BBox: []float64{0, 0, 200, 200}, DetScore: 0.9}
+	want := Score(nil, Face{BBox: estimated.BBox, DetScore: estimated.DetScore})
+	if got := ForFace(estimated); got != want || got <= 0 {
+		t.Errorf("ForFace(estimated) = %.3f, want %.3f", got, want)
+	}
+
+	if got := ForFace(&database.StoredFace{}); got != neutralQuality {
+		t.Errorf("ForFace(unknown) = %.3f, want %.3f", got, neutralQuality)
+	}
+}
+
+func TestDecodeImage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, checkerboard(16, 16)); err != nil {
+		t.Fatal(err)
+	}
+	if img := DecodeImage(buf.Bytes()); img == nil || img.Bounds().Dx() != 16 {
+		t.Error("expected the decoded PNG")
+	}
+	if img := DecodeImage([]byte("not an image")); img != nil {
+		t.Error("expected nil for invalid data")
+	}
+}
@@ -217,6 +217,9 @@ type FaceDetection struct {
 	Embedding []float32 `json:"embedding"`
 	BBox      []float64 `json:"bbox"` // [x1, y1, x2, y2]
 	DetScore  float64   `json:"det_score"`
+	// Landmarks are the optional five facial keypoints [x, y] (left eye,
+	// right eye, nose, left and right mouth corner), if the server sends them.
+	Landmarks [][]float64 `json:"landmarks,omitempty"`
 }
 
 // FaceResponse represents the response from the face embedding endpoint.
@@ -0,0 +1,181 @@
+// Package fake implements a deterministic stand-in for the embedding server.
+//
+// It serves the endpoints used by fingerprint.EmbeddingClient (/embed/image,
+// /embed/text and /embed/face) without loading any model. Image embeddings
+// are a fixed random projection of a downscaled copy of the image, so
+// similar images get similar vectors and the same image always gets the same
+// vector. Text embeddings are derived from the words of the text, and faces
+// are synthetic boxes placed from a hash of the image content. This lets the
+// processing pipeline, era computation and HNSW search run offline.
+package fake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Default embedding sizes, matching the CLIP and face models of the real server.
+const (
+	DefaultImageDim = 768
+	DefaultFaceDim  = 512
+	DefaultMaxFaces = 2
+)
+
+// maxImageSize bounds a single uploaded image.
+const maxImageSize = 64 << 20
+
+// Options configures a fake embedding server. Zero values use the defaults.
+type Options struct {
+	ImageDim int // Dimension of image and text embeddings
+	FaceDim  int // Dimension of face embeddings
+	MaxFaces int // Maximum number of synthetic faces per image; negative disables faces
+}
+
+// Server is a fake embedding server. It is stateless and safe for concurrent use.
+type Server struct {
+	maxFaces        int
+	imageProjection *projection
+	faceProjection  *projection
+	imageDim        int
+}
+
+// New creates a fake embedding server.
+func New(opts Options) *Server {
+	if opts.ImageDim <= 0 {
+		opts.ImageDim = DefaultImageDim
+	}
+	if opts.FaceDim <= 0 {
+		opts.FaceDim = DefaultFaceDim
+	}
+	if opts.MaxFaces == 0 {
+		opts.MaxFaces = DefaultMaxFaces
+	}
+	return &Server{
+		maxFaces:        max(opts.MaxFaces, 0),
+		imageProjection: newProjection("image", opts.ImageDim),
+		faceProjection:  newProjection("face", opts.FaceDim),
+		imageDim:        opts.ImageDim,
+	}
+}
+
+// Handler returns the HTTP handler serving the embedding endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /embed/image", s.handleImage)
+	mux.HandleFunc("POST /embed/text", s.handleText)
+	mux.HandleFunc("POST /embed/face", s.handleFace)
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	return mux
+}
+
+// embeddingResponse mirrors the image and text responses of the real server.
+type embeddingResponse struct {
+	Dim        int       `json:"dim"`
+	Embedding  []float32 `json:"embedding"`
+	Model      string    `json:"model"`
+	Pretrained string    `json:"pretrained"`
+}
+
+// Model names reported in responses.
+const (
+	clipModel      = "fake-clip"
+	clipPretrained = "fake"
+	faceModel      = "fake-face"
+)
+
+func (s *Server) handleImage(w http.ResponseWriter, r *http.Request) {
+	data, err := readImage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	embedding := s.ImageEmbedding(data)
+	writeJSON(w, http.StatusOK, embeddingResponse{
+		Dim: len(embedding), Embedding: embedding, Model: clipModel, Pretrained: clipPretrained,
+	})
+}
+
+func (s *Server) handleText(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Text) == "" {
+		writeError(w, http.StatusBadRequest, "text is required")
+		return
+	}
+	embedding := s.TextEmbedding(req.Text)
+	writeJSON(w, http.StatusOK, embeddingResponse{
+		Dim: len(embedding), Embedding: embedding, Model: clipModel, Pretrained: clipPretrained,
+	})
+}
+
+// faceDetection mirrors fingerprint.FaceDetection.
+type faceDetection struct {
+	FaceIndex int       `json:"face_index"`
+	Dim       int       `json:"dim"`
+	Embedding []float32 `json:"embedding"`
+	BBox      []float64 `json:"bbox"`
+	DetScore  float64   `json:"det_score"`
+}
+
+func (s *Server) handleFace(w http.ResponseWriter, r *http.Request) {
+	data, err := readImage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	faces, err := s.Faces(data)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	detections := make([]faceDetection, len(faces))
+	for i, f := range faces {
+		detections[i] = faceDetection{
+			FaceIndex: i, Dim: len(f.Embedding), Embedding: f.Embedding, BBox: f.BBox[:], DetScore: f.DetScore,
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"faces_count": len(detections), "faces": detections, "model": faceModel,
+	})
+}
+
+// readImage reads the "file" part of a multipart upload.
+func readImage(r *http.Request) ([]byte, error) {
+	r.Body = http.MaxBytesReader(nil, r.Body, maxImageSize)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		return nil, fmt.Errorf("missing file: %w", err)
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty file")
+	}
+	return data, nil
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error response in the style of the real server.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"detail": message})
+}
@@ -0,0 +1,165 @@
+package fake
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/kozaktomas/photo-sorter/internal/fingerprint"
+)
+
+// gradientJPEG returns a JPEG with a horizontal gradient, shifted by offset.
+func gradientJPEG(t *testing.T, width, height int, offset uint8, inverted bool) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := range width {
+		v := uint8(x*255/width) + offset
+		if inverted {
+			v = 255 - v
+		}
+		for y := range height {
+			img.Set(x, y, color.RGBA{R: v, G: uint8(y * 255 / height), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatalf("encoding test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func newTestClient(t *testing.T, opts Options) *fingerprint.EmbeddingClient {
+	t.Helper()
+	server := httptest.NewServer(New(opts).Handler())
+	t.Cleanup(server.Close)
+	client, err := fingerprint.NewEmbeddingClient(server.URL, "clip")
+	if err != nil {
+		t.Fatalf("NewEmbeddingClient failed: %v", err)
+	}
+	return client
+}
+
+func TestImageEmbedding(t *testing.T) {
+	client := newTestClient(t, Options{})
+	ctx := context.Background()
+
+	original := gradientJPEG(t, 64, 48, 0, false)
+	result, err := client.ComputeEmbeddingWithMetadata(ctx, original)
+	if err != nil {
+		t.Fatalf("ComputeEmbeddingWithMetadata failed: %v", err)
+	}
+	if result.Dim != DefaultImageDim || len(result.Embedding) != DefaultImageDim || result.Model != clipModel {
+		t.Fatalf("unexpected result: dim=%d len=%d model=%q", result.Dim, len(result.Embedding), result.Model)
+	}
+
+	again, err := client.ComputeEmbedding(ctx, original)
+	if err != nil {
+		t.Fatalf("ComputeEmbedding failed: %v", err)
+	}
+	if !slices.Equal(again, result.Embedding) {
+		t.Error("expected the same image to get the same embedding")
+	}
+
+	similar, _ := client.ComputeEmbedding(ctx, gradientJPEG(t, 128, 96, 10, false))
+	different, _ := client.ComputeEmbedding(ctx, gradientJPEG(t, 64, 48, 0, true))
+	simScore := fingerprint.CosineSimilarity(result.Embedding, similar)
+	diffScore := fingerprint.CosineSimilarity(result.Embedding, different)
+	if simScore < 0.9 || simScore <= diffScore {
+		t.Errorf("expected similar images to be closer: similar=%.3f different=%.3f", simScore, diffScore)
+	}
+}
+
+func TestImageEmbedding_NonImageData(t *testing.T) {
+	s := New(Options{ImageDim: 16})
+	a := s.ImageEmbedding([]byte("not an image"))
+	b := s.ImageEmbedding([]byte("not an image"))
+	if len(a) != 16 || !slices.Equal(a, b) {
+		t.Errorf("expected a stable 16-dim vector, got %v and %v", a, b)
+	}
+}
+
+func TestTextEmbedding(t *testing.T) {
+	client := newTestClient(t, Options{})
+	ctx := context.Background()
+
+	dog, err := client.ComputeTextEmbedding(ctx, "a photo of a dog")
+	if err != nil {
+		t.Fatalf("ComputeTextEmbedding failed: %v", err)
+	}
+	dogAgain, _ := client.ComputeTextEmbedding(ctx, "A photo of a DOG!")
+	dogBeach, _ := client.ComputeTextEmbedding(ctx, "a photo of a dog on the beach")
+	city, _ := client.ComputeTextEmbedding(ctx, "night city skyline")
+
+	if !slices.Equal(dog, dogAgain) {
+		t.Error("expected case and punctuation to be ignored")
+	}
+	if fingerprint.CosineSimilarity(dog, dogBeach) <= fingerprint.CosineSimilarity(dog, city) {
+		t.Error("expected texts sharing words to be more similar")
+	}
+
+	if _, err := client.ComputeTextEmbedding(ctx, "  "); err == nil {
+		t.Error("expected empty text to be rejected")
+	}
+}
+
+func TestFaceEmbeddings(t *testing.T) {
+	client := newTestClient(t, Options{MaxFaces: 3})
+	ctx := context.Background()
+
+	seen := make(map[int]bool)
+	for offset := range uint8(20) {
+		data := gradientJPEG(t, 200, 100, offset, false)
+		result, err := client.ComputeFaceEmbeddings(ctx, data)
+		if err != nil {
+			t.Fatalf("ComputeFaceEmbeddings failed: %v", err)
+		}
+		if result.FacesCount != len(result.Faces) || result.FacesCount > 3 {
+			t.Fatalf("unexpected face count %d (%d faces)", result.FacesCount, len(result.Faces))
+		}
+		seen[result.FacesCount] = true
+		for i, f := range result.Faces {
+			if f.FaceIndex != i || f.Dim != DefaultFaceDim || len(f.Embedding) != DefaultFaceDim {
+				t.Errorf("unexpected face %d: index=%d dim=%d", i, f.FaceIndex, f.Dim)
+			}
+			if f.BBox[0] < 0 || f.BBox[1] < 0 || f.BBox[2] > 200 || f.BBox[3] > 100 || f.BBox[0] >= f.BBox[2] {
+				t.Errorf("face box %v outside of 200x100 image", f.BBox)
+			}
+			if f.DetScore < 0.7 || f.DetScore > 1 {
+				t.Errorf("unexpected detection score %f", f.DetScore)
+			}
+		}
+
+		again, _ := client.ComputeFaceEmbeddings(ctx, data)
+		for i := range again.Faces {
+			if !slices.Equal(again.Faces[i].Embedding, result.Faces[i].Embedding) {
+				t.Error("expected the same image to get the same faces")
+			}
+		}
+	}
+	if len(seen) < 2 {
+		t.Errorf("expected varying face counts across images, got %v", seen)
+	}
+}
+
+func TestFaceEmbeddings_Disabled(t *testing.T) {
+	s := New(Options{MaxFaces: -1})
+	faces, err := s.Faces(gradientJPEG(t, 32, 32, 0, false))
+	if err != nil {
+		t.Fatalf("Faces failed: %v", err)
+	}
+	if len(faces) != 0 {
+		t.Errorf("expected no faces, got %d", len(faces))
+	}
+}
+
+func TestFaceEmbeddings_InvalidImage(t *testing.T) {
+	client := newTestClient(t, Options{})
+	if _, err := client.ComputeFaceEmbeddings(context.Background(), []byte("not an image")); err == nil {
+		t.Error("expected error for undecodable image")
+	}
+}
@@ -0,0 +1,197 @@
+package fake
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF decoder
+	_ "image/jpeg" // register JPEG decoder
+	_ "image/png"  // register PNG decoder
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/image/draw"
+)
+
+// gridSize is the side of the thumbnail images are reduced to before projection.
+const gridSize = 8
+
+// sampleLen is the length of a thumbnail sample: gridSize² RGB values.
+const sampleLen = gridSize * gridSize * 3
+
+// Face is a synthetic face detection.
+type Face struct {
+	BBox      [4]float64 // [x1, y1, x2, y2] in pixels
+	DetScore  float64
+	Embedding []float32
+}
+
+// stream is a deterministic pseudo-random sequence in [-1, 1] derived from a
+// seed with SHA-256 in counter mode.
+type stream struct {
+	seed    []byte
+	counter uint64
+	buf     []byte
+}
+
+func newStream(seed string) *stream {
+	return &stream{seed: []byte(seed)}
+}
+
+func (s *stream) next() float32 {
+	if len(s.buf) < 4 {
+		block := binary.LittleEndian.AppendUint64(bytes.Clone(s.seed), s.counter)
+		sum := sha256.Sum256(block)
+		s.buf = sum[:]
+		s.counter++
+	}
+	v := binary.LittleEndian.Uint32(s.buf)
+	s.buf = s.buf[4:]
+	return float32(float64(v)/math.MaxUint32*2 - 1)
+}
+
+// vector returns the next dim values of the stream as a unit vector.
+func (s *stream) vector(dim int) []float32 {
+	v := make([]float32, dim)
+	for i := range v {
+		v[i] = s.next()
+	}
+	normalize(v)
+	return v
+}
+
+// projection is a fixed random linear map from thumbnail samples to
+// embeddings. Random projections approximately preserve cosine similarity,
+// so similar images map to nearby vectors.
+type projection struct {
+	weights [][]float32 // [dim][sampleLen]
+}
+
+func newProjection(seed string, dim int) *projection {
+	s := newStream("projection:" + seed)
+	weights := make([][]float32, dim)
+	for i := range weights {
+		weights[i] = make([]float32, sampleLen)
+		for j := range weights[i] {
+			weights[i][j] = s.next()
+		}
+	}
+	return &projection{weights: weights}
+}
+
+// apply projects a sample to a unit vector. It returns false for samples
+// without any signal (e.g. a uniform mid-gray image).
+func (p *projection) apply(sample []float32) ([]float32, bool) {
+	out := make([]float32, len(p.weights))
+	for i, row := range p.weights {
+		var sum float32
+		for j, w := range row {
+			sum += w * sample[j]
+		}
+		out[i] = sum
+	}
+	return out, normalize(out)
+}
+
+// embed projects a region of an image, falling back to a vector seeded by
+// fallback when the region carries no signal.
+func (p *projection) embed(img image.Image, rect image.Rectangle, fallback []byte) []float32 {
+	if v, ok := p.apply(sample(img, rect)); ok {
+		return v
+	}
+	return newStream(fmt.Sprintf("fallback:%x", sha256.Sum256(fallback))).vector(len(p.weights))
+}
+
+// sample reduces a region of an image to gridSize² RGB values centered on zero.
+func sample(img image.Image, rect image.Rectangle) []float32 {
+	thumb := image.NewRGBA(image.Rect(0, 0, gridSize, gridSize))
+	draw.ApproxBiLinear.Scale(thumb, thumb.Bounds(), img, rect, draw.Src, nil)
+	values := make([]float32, 0, sampleLen)
+	for i := 0; i < len(thumb.Pix); i += 4 {
+		for _, c := range thumb.Pix[i : i+3] {
+			values = append(values, float32(c)/255-0.5)
+		}
+	}
+	return values
+}
+
+// normalize scales v to unit length. It returns false if v is zero.
+func normalize(v []float32) bool {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return false
+	}
+	norm := float32(math.Sqrt(sum))
+	for i := range v {
+		v[i] /= norm
+	}
+	return true
+}
+
+// ImageEmbedding returns the embedding of an image. Data that cannot be
+// decoded as an image still gets a stable vector derived from its bytes.
+func (s *Server) ImageEmbedding(data []byte) []float32 {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return newStream(fmt.Sprintf("bytes:%x", sha256.Sum256(data))).vector(s.imageDim)
+	}
+	return s.imageProjection.embed(img, img.Bounds(), data)
+}
+
+// TextEmbedding returns the embedding of a text: the normalized sum of one
+// vector per word, so texts sharing words are similar. Case and punctuation
+// are ignored.
+func (s *Server) TextEmbedding(text string) []float32 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	sum := make([]float32, s.imageDim)
+	for _, word := range words {
+		for i, x := range newStream("word:" + word).vector(s.imageDim) {
+			sum[i] += x
+		}
+	}
+	if !normalize(sum) {
+		return newStream("text:" + text).vector(s.imageDim)
+	}
+	return sum
+}
+
+// Faces returns synthetic faces for an image. The number of faces (up to
+// MaxFaces) and their placement are derived from a hash of the image, and
+// each face embedding is a projection of the pixels inside its box.
+func (s *Server) Faces(data []byte) ([]Face, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	hash := sha256.Sum256(data)
+	count := int(hash[0]) % (s.maxFaces + 1)
+	bounds := img.Bounds()
+	width, height := float64(bounds.Dx()), float64(bounds.Dy())
+	size := min(width/float64(count+1), height/2)
+
+	faces := make([]Face, 0, count)
+	for i := range count {
+		cx := width * float64(i+1) / float64(count+1)
+		cy := height * (0.3 + 0.2*float64(hash[(1+i)%len(hash)])/255)
+		box := [4]float64{
+			max(cx-size/2, 0), max(cy-size/2, 0),
+			min(cx+size/2, width), min(cy+size/2, height),
+		}
+		rect := image.Rect(int(box[0]), int(box[1]), int(box[2]), int(box[3])).Add(bounds.Min)
+		faces = append(faces, Face{
+			BBox:      box,
+			DetScore:  0.7 + 0.29*float64(hash[(16+i)%len(hash)])/255,
+			Embedding: s.faceProjection.embed(img, rect, slices.Concat(data, []byte{byte(i)})),
+		})
+	}
+	return faces, nil
+}
@@ -1,16 +1,77 @@
 package latex
 
-// Page dimensions in mm (A4 landscape).
+import "github.com/kozaktomas/photo-sorter/internal/database"
+
+// Page dimensions in mm of the default page (A4 landscape).
 const (
 	PageW = 297.0
 	PageH = 210.0
 )
 
-// BleedMM is the print bleed margin extending each side of the trimmed page.
-// Must stay in sync with the crop package config in templates/book.tex
-// (width=303truemm, height=216truemm, i.e. 297+2·3, 210+2·3).
+// BleedMM is the default print bleed margin extending each side of the
+// trimmed page. The crop package config in templates/book.tex is derived from
+// the book's page size and bleed (e.g. width=303truemm, height=216truemm for
+// A4 landscape, i.e. 297+2·3, 210+2·3).
 const BleedMM = 3.0
 
+// MaxBleedMM is the largest bleed a book can be configured with.
+const MaxBleedMM = 10.0
+
+// Page size identifiers.
+const (
+	PageSizeA4       = "a4"
+	PageSizeA5       = "a5"
+	PageSizeA3       = "a3"
+	PageSizeSquare20 = "square_20"
+	PageSizeSquare30 = "square_30"
+)
+
+// Page orientations.
+const (
+	OrientationLandscape = "landscape"
+	OrientationPortrait  = "portrait"
+)
+
+// DefaultPageSize and DefaultOrientation are used for books without a page setup.
+const (
+	DefaultPageSize    = PageSizeA4
+	DefaultOrientation = OrientationLandscape
+)
+
+// pageSizes maps page size identifiers to their trimmed dimensions in mm
+// (short side, long side). Square sizes ignore the orientation.
+var pageSizes = map[string][2]float64{
+	PageSizeA4:       {210, 297},
+	PageSizeA5:       {148, 210},
+	PageSizeA3:       {297, 420},
+	PageSizeSquare20: {200, 200},
+	PageSizeSquare30: {300, 300},
+}
+
+// ValidatePageSize reports whether id is a known page size.
+func ValidatePageSize(id string) bool {
+	_, ok := pageSizes[id]
+	return ok
+}
+
+// ValidateOrientation reports whether o is a known page orientation.
+func ValidateOrientation(o string) bool {
+	return o == OrientationLandscape || o == OrientationPortrait
+}
+
+// PageDimensions returns the trimmed page width and height in mm for a page
+// size and orientation. Unknown values fall back to the defaults.
+func PageDimensions(size, orientation string) (w, h float64) {
+	dims, ok := pageSizes[size]
+	if !ok {
+		dims = pageSizes[DefaultPageSize]
+	}
+	if orientation == OrientationPortrait {
+		return dims[0], dims[1]
+	}
+	return dims[1], dims[0]
+}
+
 // fullBleedRasterEpsilonMM expands the 1_fullbleed clip/slot slightly past the
 // PDF media box so rasterizer integer-pixel-grid rounding at the page bottom
 // doesn't leave a sub-mm white row. At 300 DPI the PDF page height (612.283 pt
@@ -31,8 +92,12 @@ const (
 	Format1P2L       = "1p_2l"
 )
 
-// LayoutConfig holds the 12-column grid and 3-zone page layout configuration.
+// LayoutConfig holds the page size and the 12-column grid and 3-zone page
+// layout configuration.
 type LayoutConfig struct {
+	PageWidthMM     float64 // trimmed page width (297mm)
+	PageHeightMM    float64 // trimmed page height (210mm)
+	BleedMM         float64 // print bleed on every side (3mm)
 	InsideMarginMM  float64 // binding side (20mm)
 	OutsideMarginMM float64 // away from binding (12mm)
 	TopMarginMM     float64 // 10mm
@@ -41,16 +106,20 @@ type LayoutConfig struct {
 	ColumnGutterMM  float64 // 4mm between columns
 	RowGapMM        float64 // 4mm between rows
 	HeaderHeightMM  float64 // 4mm running header zone
-	CanvasHeightMM  float64 // 172mm photo/text zone
+	CanvasHeightMM  float64 // 172mm photo/text zone (page height minus the other zones)
 	FooterHeightMM  float64 // 8mm captions + folio zone
 	ArchivalInsetMM float64 // 3mm mat inset for archival photos
 	GutterSafeMM    float64 // 8mm inset from inside content edge
 	BaselineUnitMM  float64 // 4mm vertical rhythm unit
 }
 
-// DefaultLayoutConfig returns the print-ready layout configuration.
+// DefaultLayoutConfig returns the print-ready layout configuration for the
+// default page (A4 landscape with 3mm bleed).
 func DefaultLayoutConfig() LayoutConfig {
 	return LayoutConfig{
+		PageWidthMM:     PageW,
+		PageHeightMM:    PageH,
+		BleedMM:         BleedMM,
 		InsideMarginMM:  20.0,
 		OutsideMarginMM: 12.0,
 		TopMarginMM:     10.0,
@@ -67,10 +136,37 @@ func DefaultLayoutConfig() LayoutConfig {
 	}
 }
 
+// LayoutConfigFor returns the layout configuration for a page size,
+// orientation and bleed. Margins and zone heights stay the same as on the
+// default page; the canvas takes up the remaining height, so the slots of
+// every format scale with the page.
+func LayoutConfigFor(size, orientation string, bleedMM float64) LayoutConfig {
+	c := DefaultLayoutConfig()
+	c.PageWidthMM, c.PageHeightMM = PageDimensions(size, orientation)
+	c.BleedMM = max(0, min(bleedMM, MaxBleedMM))
+	c.CanvasHeightMM = c.PageHeightMM - c.TopMarginMM - c.HeaderHeightMM - c.FooterHeightMM - c.BottomMarginMM
+	return c
+}
+
+// BookLayoutConfig returns the layout configuration for a book's page setup,
+// or the default configuration when book is nil.
+func BookLayoutConfig(book *database.PhotoBook) LayoutConfig {
+	if book == nil {
+		return DefaultLayoutConfig()
+	}
+	return LayoutConfigFor(book.PageSize, book.Orientation, book.BleedMM)
+}
+
 // ContentWidth returns the usable horizontal space (same for all pages).
 // 297 - 20 - 12 = 265mm.
 func (c LayoutConfig) ContentWidth() float64 {
-	return PageW - c.InsideMarginMM - c.OutsideMarginMM
+	return c.PageWidthMM - c.InsideMarginMM - c.OutsideMarginMM
+}
+
+// FullBleedSlot returns the slot of a 1_fullbleed page: the whole page
+// including the bleed on every side (303×216mm for A4 landscape).
+func (c LayoutConfig) FullBleedSlot() SlotRect {
+	return SlotRect{W: c.PageWidthMM + 2*c.BleedMM, H: c.PageHeightMM + 2*c.BleedMM}
 }
 
 // ColumnWidth returns the width of a single grid column.
@@ -121,7 +217,8 @@ func FormatSlotsGrid(format string, config LayoutConfig) []SlotRect {
 		// Fallback only: real full-bleed slot is built directly in
 		// buildFullBleedPage, which bypasses the canvas-relative grid and
 		// places a single slot covering the entire bleed area
-		// (-BleedMM, -BleedMM) → (PageW+BleedMM, PageH+BleedMM).
+		// (-BleedMM, -BleedMM) → (PageW+BleedMM, PageH+BleedMM), see
+		// LayoutConfig.FullBleedSlot.
 		// This case exists so callers that only need slot count / shape
 		// (e.g. preflight, the frontend grid logic) get a sensible value.
 		return []SlotRect{
@@ -175,6 +272,33 @@ func FormatSlotsGrid(format string, config LayoutConfig) []SlotRect {
 	}
 }
 
+// LayoutSlotsGrid returns slot rectangles for a user-defined page layout. Cells
+// span the columns of the 12-column grid and the rows the canvas height is
+// split into, with the column gutter and row gap between them.
+func LayoutSlotsGrid(layout *database.PageLayout, config LayoutConfig) []SlotRect {
+	rows := max(layout.Rows, 1)
+	rowH := (config.CanvasHeightMM - float64(rows-1)*config.RowGapMM) / float64(rows)
+	slots := make([]SlotRect, len(layout.Cells))
+	for i, c := range layout.Cells {
+		slots[i] = SlotRect{
+			X: config.ColOffset(c.Col),
+			Y: float64(c.Row) * (rowH + config.RowGapMM),
+			W: config.ColSpanWidth(c.ColSpan),
+			H: float64(c.RowSpan)*rowH + float64(c.RowSpan-1)*config.RowGapMM,
+		}
+	}
+	return slots
+}
+
+// PageSlotsGrid returns the slot rectangles of a page: those of its custom
+// layout, or of its built-in format with the page's split position.
+func PageSlotsGrid(p *database.BookPage, config LayoutConfig) []SlotRect {
+	if p.Layout != nil {
+		return LayoutSlotsGrid(p.Layout, config)
+	}
+	return FormatSlotsGridWithSplit(p.Format, config, p.SplitPosition)
+}
+
 // FormatSlotsGridWithSplit returns slot rectangles using a custom split position.
 // When splitPosition is nil or the format is 1_fullscreen, it delegates to FormatSlotsGrid.
 func FormatSlotsGridWithSplit(format string, config LayoutConfig, splitPosition *float64) []SlotRect {
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"math"
 	"testing"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
 )
 
 func TestDefaultLayoutConfig(t *testing.T) {
@@ -76,6 +78,111 @@ func TestZonesHeight(t *testing.T) {
 	}
 }
 
+func TestPageDimensions(t *testing.T) {
+	tests := []struct {
+		size, orientation string
+		w, h              float64
+	}{
+		{PageSizeA4, OrientationLandscape, 297, 210},
+		{PageSizeA4, OrientationPortrait, 210, 297},
+		{PageSizeA5, OrientationPortrait, 148, 210},
+		{PageSizeSquare30, OrientationPortrait, 300, 300},
+		{"unknown", "", 297, 210},
+	}
+	for _, tt := range tests {
+		w, h := PageDimensions(tt.size, tt.orientation)
+		if w != tt.w || h != tt.h {
+			t.Errorf("PageDimensions(%q, %q) = %.0fx%.0f, want %.0fx%.0f",
+				tt.size, tt.orientation, w, h, tt.w, tt.h)
+		}
+	}
+}
+
+func TestValidatePageSetup(t *testing.T) {
+	for _, size := range []string{PageSizeA4, PageSizeA5, PageSizeA3, PageSizeSquare20, PageSizeSquare30} {
+		if !ValidatePageSize(size) {
+			t.Errorf("ValidatePageSize(%q) = false, want true", size)
+		}
+	}
+	if ValidatePageSize("b5") || ValidatePageSize("") {
+		t.Error("expected unknown page sizes to be invalid")
+	}
+	if !ValidateOrientation(OrientationLandscape) || !ValidateOrientation(OrientationPortrait) {
+		t.Error("expected landscape and portrait to be valid")
+	}
+	if ValidateOrientation("upside-down") {
+		t.Error("expected unknown orientation to be invalid")
+	}
+}
+
+func TestLayoutConfigFor(t *testing.T) {
+	def := LayoutConfigFor(PageSizeA4, OrientationLandscape, BleedMM)
+	if def != DefaultLayoutConfig() {
+		t.Errorf("A4 landscape config = %+v, want the default %+v", def, DefaultLayoutConfig())
+	}
+
+	tests := []struct {
+		name              string
+		size, orientation string
+		bleed             float64
+		contentW, canvasH float64
+		wantBleed         float64
+	}{
+		{"A4 portrait", PageSizeA4, OrientationPortrait, 3, 178, 259, 3},
+		{"square 30", PageSizeSquare30, OrientationLandscape, 5, 268, 262, 5},
+		{"no bleed", PageSizeA5, OrientationLandscape, 0, 178, 110, 0},
+		{"bleed clamped", PageSizeA4, OrientationLandscape, 50, 265, 172, MaxBleedMM},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := LayoutConfigFor(tt.size, tt.orientation, tt.bleed)
+			if math.Abs(cfg.ContentWidth()-tt.contentW) > 0.01 {
+				t.Errorf("ContentWidth = %.2f, want %.2f", cfg.ContentWidth(), tt.contentW)
+			}
+			if math.Abs(cfg.CanvasHeightMM-tt.canvasH) > 0.01 {
+				t.Errorf("CanvasHeightMM = %.2f, want %.2f", cfg.CanvasHeightMM, tt.canvasH)
+			}
+			if cfg.BleedMM != tt.wantBleed {
+				t.Errorf("BleedMM = %.2f, want %.2f", cfg.BleedMM, tt.wantBleed)
+			}
+			total := cfg.TopMarginMM + cfg.HeaderHeightMM + cfg.CanvasHeightMM + cfg.FooterHeightMM + cfg.BottomMarginMM
+			if math.Abs(total-cfg.PageHeightMM) > 0.01 {
+				t.Errorf("zones sum to %.2f, want page height %.2f", total, cfg.PageHeightMM)
+			}
+			// Full-canvas slots follow the page.
+			full := FormatSlotsGrid(Format2Portrait, cfg)
+			if math.Abs(full[0].H-tt.canvasH) > 0.01 {
+				t.Errorf("2_portrait slot height = %.2f, want %.2f", full[0].H, tt.canvasH)
+			}
+			if math.Abs(full[1].X+full[1].W-tt.contentW) > 0.01 {
+				t.Errorf("2_portrait right edge = %.2f, want %.2f", full[1].X+full[1].W, tt.contentW)
+			}
+		})
+	}
+}
+
+func TestFullBleedSlot(t *testing.T) {
+	slot := DefaultLayoutConfig().FullBleedSlot()
+	if slot.W != 303 || slot.H != 216 {
+		t.Errorf("default full-bleed slot = %.0fx%.0f, want 303x216", slot.W, slot.H)
+	}
+	slot = LayoutConfigFor(PageSizeSquare20, OrientationLandscape, 0).FullBleedSlot()
+	if slot.W != 200 || slot.H != 200 {
+		t.Errorf("square full-bleed slot without bleed = %.0fx%.0f, want 200x200", slot.W, slot.H)
+	}
+}
+
+func TestBookLayoutConfig(t *testing.T) {
+	if BookLayoutConfig(nil) != DefaultLayoutConfig() {
+		t.Error("expected the default config for a nil book")
+	}
+	cfg := BookLayoutConfig(&database.PhotoBook{PageSize: PageSizeA4, Orientation: OrientationPortrait, BleedMM: 4})
+	if cfg.PageWidthMM != 210 || cfg.PageHeightMM != 297 || cfg.BleedMM != 4 {
+		t.Errorf("book config page = %.0fx%.0f bleed %.0f, want 210x297 bleed 4",
+			cfg.PageWidthMM, cfg.PageHeightMM, cfg.BleedMM)
+	}
+}
+
 func TestFormatSlotsGrid_SlotCounts(t *testing.T) {
 	cfg := DefaultLayoutConfig()
 	tests := []struct {
@@ -332,3 +439,79 @@ func TestFormatSlotsGridWithSplit_BoundsCheck(t *testing.T) {
 func formatFloat(f float64) string {
 	return fmt.Sprintf("%.1f", f)
 }
+
+// --- LayoutSlotsGrid ---
+
+func TestLayoutSlotsGrid_MatchesBuiltinFormat(t *testing.T) {
+	// A layout with the cells of 1p_2l must produce the slots of 1p_2l.
+	cfg := DefaultLayoutConfig()
+	layout := &database.PageLayout{
+		ID: "mirror", Name: "Mirror", Rows: 2,
+		Cells: []database.PageLayoutCell{
+			{Col: 0, ColSpan: 4, Row: 0, RowSpan: 2},
+			{Col: 4, ColSpan: 8, Row: 0, RowSpan: 1},
+			{Col: 4, ColSpan: 8, Row: 1, RowSpan: 1},
+		},
+	}
+	const eps = 0.01
+
+	want := FormatSlotsGrid(Format1P2L, cfg)
+	got := LayoutSlotsGrid(layout, cfg)
+	if len(got) != len(want) {
+		t.Fatalf("slot count = %d, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if math.Abs(got[i].X-want[i].X) > eps || math.Abs(got[i].Y-want[i].Y) > eps ||
+			math.Abs(got[i].W-want[i].W) > eps || math.Abs(got[i].H-want[i].H) > eps {
+			t.Errorf("slot %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLayoutSlotsGrid_Rows(t *testing.T) {
+	// Six slots in a 3x2 grid: rows split the canvas with the row gap between them.
+	cfg := DefaultLayoutConfig()
+	layout := &database.PageLayout{ID: "6_up", Name: "6-up", Rows: 2}
+	for row := range 2 {
+		for col := range 3 {
+			layout.Cells = append(layout.Cells, database.PageLayoutCell{Col: col * 4, ColSpan: 4, Row: row, RowSpan: 1})
+		}
+	}
+	const eps = 0.01
+
+	slots := LayoutSlotsGrid(layout, cfg)
+	if len(slots) != 6 {
+		t.Fatalf("slot count = %d, want 6", len(slots))
+	}
+	rowH := cfg.HalfCanvasHeight()
+	last := slots[5]
+	if math.Abs(last.X-cfg.ColOffset(8)) > eps || math.Abs(last.W-cfg.ColSpanWidth(4)) > eps {
+		t.Errorf("last slot x/w = %.2f/%.2f, want %.2f/%.2f", last.X, last.W, cfg.ColOffset(8), cfg.ColSpanWidth(4))
+	}
+	if math.Abs(last.Y-(rowH+cfg.RowGapMM)) > eps || math.Abs(last.H-rowH) > eps {
+		t.Errorf("last slot y/h = %.2f/%.2f, want %.2f/%.2f", last.Y, last.H, rowH+cfg.RowGapMM, rowH)
+	}
+	if math.Abs(last.X+last.W-cfg.ContentWidth()) > eps || math.Abs(last.Y+last.H-cfg.CanvasHeightMM) > eps {
+		t.Errorf("last slot does not reach the canvas corner: %+v", last)
+	}
+}
+
+func TestPageSlotsGrid(t *testing.T) {
+	cfg := DefaultLayoutConfig()
+	split := 0.3
+	builtin := &database.BookPage{Format: Format2Portrait, SplitPosition: &split}
+	got, want := PageSlotsGrid(builtin, cfg), FormatSlotsGridWithSplit(Format2Portrait, cfg, &split)
+	if got[0] != want[0] {
+		t.Errorf("built-in slot 0 = %+v, want %+v", got[0], want[0])
+	}
+
+	layout := &database.PageLayout{
+		ID: "wide", Name: "Wide", Rows: 1,
+		Cells: []database.PageLayoutCell{{Col: 0, ColSpan: 12, Row: 0, RowSpan: 1}},
+	}
+	custom := &database.BookPage{Format: "wide", Layout: layout, SplitPosition: &split}
+	got = PageSlotsGrid(custom, cfg)
+	if len(got) != 1 || got[0].W != cfg.ContentWidth() || got[0].H != cfg.CanvasHeightMM {
+		t.Errorf("layout slots = %+v, want one full-canvas slot", got)
+	}
+}
@@ -120,6 +120,12 @@ type ExportOptions struct {
 	Debug        bool
 	OnProgress   func(ProgressInfo)
 	PhotoQuality PhotoQuality
+	// Proof renders a small review copy for email: QualityLow photos
+	// (PhotoQuality is ignored), a diagonal watermark and the page ID on
+	// every page, and a review-comments page after each section.
+	Proof bool
+	// ProofWatermark is the watermark text of a proof. Empty = DefaultProofWatermark.
+	ProofWatermark string
 }
 
 // ExportReport contains metadata about a PDF export for quality analysis.
@@ -254,6 +260,7 @@ type TemplatePage struct {
 	IsRecto        bool   // true for odd pages (right-hand, recto)
 	Style          string // "modern" or "archival"
 	HidePageNumber bool   // suppress folio rendering on this page (numbering continues)
+	PageID         string // book page ID, printed by the proof overlay
 	// Content area bounds.
 	ContentLeftX  float64
 	ContentRightX float64
@@ -283,15 +290,21 @@ type TemplatePage struct {
 type TemplateSection struct {
 	Title string
 	Pages []TemplatePage
+	// ProofComments is the review-comments page rendered after Pages in
+	// proof mode; nil otherwise.
+	ProofComments *ProofCommentsPage
 }
 
 // TemplateData is the root data passed to the LaTeX template.
 type TemplateData struct {
 	Sections        []TemplateSection
-	PageW           float64
+	PageW           float64 // trimmed page size (mm)
 	PageH           float64
+	MediaW          float64 // page plus bleed on both sides (mm), the paper of the crop package
+	MediaH          float64
 	DebugOverlay    bool
-	DebugColOffsets []float64 // relative X offsets for column left edges
+	DebugColOffsets []float64     // relative X offsets for column left edges
+	Proof           *ProofOverlay // proof watermark and page labels; nil = regular export
 
 	// Typography settings (from per-book configuration).
 	// BodyFontDeclaration / HeadingFontDeclaration are full LaTeX commands
@@ -391,39 +404,17 @@ func GeneratePDFWithOptions(
 // GeneratePDFWithCallbacks renders a photo book to PDF, emitting progress via
 // opts.OnProgress if non-nil. Phases: downloading_photos, compiling_pass1,
 // compiling_pass2.
-//
-//nolint:cyclop // Orchestration function that fetches multiple resources.
 func GeneratePDFWithCallbacks(
 	ctx context.Context, pp *photoprism.PhotoPrism,
 	br database.BookReader, bookID string, opts ExportOptions,
 ) ([]byte, *ExportReport, error) {
-	book, err := br.GetBook(ctx, bookID)
-	if err != nil || book == nil {
-		return nil, nil, fmt.Errorf("book not found: %s", bookID)
-	}
-
-	sections, err := br.GetSections(ctx, bookID)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get sections: %w", err)
-	}
-
-	chapters, err := br.GetChapters(ctx, bookID)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get chapters: %w", err)
-	}
-
-	pages, err := br.GetPages(ctx, bookID)
+	m, err := loadBookModel(ctx, br, bookID)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get pages: %w", err)
-	}
-
-	if len(pages) == 0 {
-		return nil, nil, errors.New("book has no pages")
+		return nil, nil, err
 	}
 
-	SortPagesBySectionOrder(pages, sections)
-	captions := buildCaptionMap(ctx, br, sections)
-	uidSet := collectPhotoUIDs(pages)
+	captions := buildCaptionMap(ctx, br, m.sections)
+	uidSet := collectPhotoUIDs(m.pages)
 
 	tmpDir, err := os.MkdirTemp("", "book-pdf-*")
 	if err != nil {
@@ -431,14 +422,21 @@ func GeneratePDFWithCallbacks(
 	}
 	defer os.RemoveAll(tmpDir)
 
-	photos := downloadPhotosWithProgress(ctx, pp, uidSet, tmpDir, opts.OnProgress, normalizeQuality(opts.PhotoQuality))
-	groups := groupPagesBySection(pages, sections, chapters)
-	config := DefaultLayoutConfig()
-	data, report := buildTemplateData(groups, photos, captions, config, book)
+	quality := normalizeQuality(opts.PhotoQuality)
+	if opts.Proof {
+		quality = QualityLow
+	}
+	photos := downloadPhotosWithProgress(ctx, pp, uidSet, tmpDir, opts.OnProgress, quality)
+	groups := groupPagesBySection(m.pages, m.sections, m.chapters)
+	config := BookLayoutConfig(m.book)
+	data, report := buildTemplateData(groups, photos, captions, config, m.book)
 
 	if opts.Debug {
 		applyDebugOverlay(&data, config)
 	}
+	if opts.Proof {
+		applyProofMode(&data, config, opts.ProofWatermark)
+	}
 
 	// Layout validation.
 	validationWarnings := ValidatePages(data.Sections, config)
@@ -447,7 +445,10 @@ func GeneratePDFWithCallbacks(
 			fmt.Sprintf("Layout: page %d slot %d: %s", vw.PageNumber, vw.SlotIndex, vw.Message))
 	}
 
-	addDPIWarnings(report)
+	// Every photo of a proof is low resolution on purpose.
+	if !opts.Proof {
+		addDPIWarnings(report)
+	}
 
 	pdfData, err := compileLatexWithProgress(ctx, data, tmpDir, opts.OnProgress)
 	if err != nil {
@@ -456,6 +457,45 @@ func GeneratePDFWithCallbacks(
 	return pdfData, report, nil
 }
 
+// bookModel is a book with its chapters, sections, and pages in print order.
+type bookModel struct {
+	book     *database.PhotoBook
+	sections []database.BookSection
+	chapters []database.BookChapter
+	pages    []database.BookPage
+}
+
+// loadBookModel loads everything the book renderers need from the repository
+// and sorts the pages by section order. It fails when the book has no pages.
+func loadBookModel(ctx context.Context, br database.BookReader, bookID string) (*bookModel, error) {
+	book, err := br.GetBook(ctx, bookID)
+	if err != nil || book == nil {
+		return nil, fmt.Errorf("book not found: %s", bookID)
+	}
+
+	sections, err := br.GetSections(ctx, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sections: %w", err)
+	}
+
+	chapters, err := br.GetChapters(ctx, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chapters: %w", err)
+	}
+
+	pages, err := br.GetPages(ctx, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pages: %w", err)
+	}
+
+	if len(pages) == 0 {
+		return nil, errors.New("book has no pages")
+	}
+
+	SortPagesBySectionOrder(pages, sections)
+	return &bookModel{book: book, sections: sections, chapters: chapters, pages: pages}, nil
+}
+
 // collectPhotoUIDs extracts unique photo UIDs from all page slots.
 func collectPhotoUIDs(pages []database.BookPage) map[string]bool {
 	uidSet := make(map[string]bool)
@@ -618,28 +658,12 @@ func buildTemplateData(
 		bookTitle = book.Title
 	}
 
-	return TemplateData{
-			Sections:               tmplSections,
-			PageW:                  PageW,
-			PageH:                  PageH,
-			BodyFontDeclaration:    typo.bodyFontDeclaration,
-			HeadingFontDeclaration: typo.headingFontDeclaration,
-			BodyFontSize:           typo.bodyFontSize,
-			BodyLineHeight:         typo.bodyLineHeight,
-			H1FontSize:             typo.h1FontSize,
-			H1Leading:              typo.h1Leading,
-			H2FontSize:             typo.h2FontSize,
-			H2Leading:              typo.h2Leading,
-			CaptionOpacity:         typo.captionOpacity,
-			CaptionFontSize:        typo.captionFontSize,
-			CaptionLeading:         typo.captionLeading,
-			CaptionBadgeSize:       typo.captionBadgeSize,
-		}, &ExportReport{
-			BookTitle:  bookTitle,
-			PageCount:  pb.pageNumber,
-			PhotoCount: len(pb.photoSet),
-			Pages:      pb.reportPages,
-		}
+	return newTemplateData(tmplSections, typo, config), &ExportReport{
+		BookTitle:  bookTitle,
+		PageCount:  pb.pageNumber,
+		PhotoCount: len(pb.photoSet),
+		Pages:      pb.reportPages,
+	}
 }
 
 // resolvedTypography holds resolved font/size values with defaults applied.
@@ -774,7 +798,7 @@ func (pb *pageBuilder) computeZones(isRecto bool) (
 	contentRightX = contentLeftX + contentW
 
 	// Vertical zones (from top of page, converted to TikZ Y from bottom).
-	topEdge := PageH - cfg.TopMarginMM              // 200mm from bottom
+	topEdge := cfg.PageHeightMM - cfg.TopMarginMM   // 200mm from bottom
 	headerY = topEdge - 2.0                         // baseline in header zone
 	canvasTopY = topEdge - cfg.HeaderHeightMM       // 196mm
 	canvasBottomY = canvasTopY - cfg.CanvasHeightMM // 24mm
@@ -798,7 +822,7 @@ func (pb *pageBuilder) buildAndRouteSlots(
 	p database.BookPage, contentLeftX, canvasTopY float64,
 	style string, isRecto bool, chapterColor string,
 ) ([]TemplateSlot, []ReportPhoto, []FooterCaption) {
-	slots := FormatSlotsGridWithSplit(p.Format, pb.config, p.SplitPosition)
+	slots := PageSlotsGrid(&p, pb.config)
 	tmplSlots, reportPhotos, footerCaptions := pb.buildSlots(
 		p, slots, contentLeftX, canvasTopY, style, isRecto, chapterColor,
 	)
@@ -848,7 +872,7 @@ func (pb *pageBuilder) buildContentPage(p database.BookPage, chapterColor string
 	// descender) when the user sets heading_color_bleed = 0.
 	bleed := max(pb.headingColorBleed, clipSafetyMM)
 	clipLeftX := max(0, contentLeftX-bleed)
-	clipRightX := min(PageW, contentRightX+bleed)
+	clipRightX := min(pb.config.PageWidthMM, contentRightX+bleed)
 
 	return TemplatePage{
 		Slots:          tmplSlots,
@@ -857,6 +881,7 @@ func (pb *pageBuilder) buildContentPage(p database.BookPage, chapterColor string
 		IsRecto:        isRecto,
 		Style:          style,
 		HidePageNumber: p.HidePageNumber,
+		PageID:         p.ID,
 		ContentLeftX:   contentLeftX,
 		ContentRightX:  contentRightX,
 		ContentW:       contentW,
@@ -878,13 +903,14 @@ func (pb *pageBuilder) buildContentPage(p database.BookPage, chapterColor string
 }
 
 // buildFullBleedPage builds a 1_fullbleed page: a single photo covering the
-// entire bleed area (303×216mm = A4 + 3mm bleed on every side). Folio and
+// entire bleed area (the page plus the book's bleed on every side, 303×216mm
+// for A4 landscape with 3mm bleed). Folio and
 // footer captions are suppressed automatically; only the photo renders.
 //
 // This path is fully separate from buildContentPage's grid+canvas pipeline:
 // it places the photo via buildPhotoSlotNew with substituted contentLeftX
 // and canvasTopY so the resulting border/clip rectangle covers (-3,-3) to
-// (300,213) in TikZ page coordinates. The TemplatePage's clip bounds are
+// (300,213) in TikZ page coordinates on the default page. The TemplatePage's clip bounds are
 // expanded to the same area so the canvas-level clip in the template does
 // not crop the photo back to the safe area.
 func (pb *pageBuilder) buildFullBleedPage(p database.BookPage, chapterColor string) TemplatePage {
@@ -901,6 +927,7 @@ func (pb *pageBuilder) buildFullBleedPage(p database.BookPage, chapterColor stri
 		footerRuleY, folioX, folioY, folioAnchor := pb.computeZones(isRecto)
 
 	tmplSlots, reportPhotos := pb.buildFullBleedPhotoSlot(p, chapterColor)
+	cfg := pb.config
 
 	pb.reportPages = append(pb.reportPages, ReportPage{
 		PageNumber: pb.pageNumber,
@@ -916,14 +943,15 @@ func (pb *pageBuilder) buildFullBleedPage(p database.BookPage, chapterColor stri
 		IsRecto:        isRecto,
 		Style:          "modern",
 		HidePageNumber: true,
+		PageID:         p.ID,
 		ContentLeftX:   contentLeftX,
 		ContentRightX:  contentRightX,
 		ContentW:       pb.config.ContentWidth(),
-		ClipLeftX:      -BleedMM - fullBleedRasterEpsilonMM,
-		ClipRightX:     PageW + BleedMM + fullBleedRasterEpsilonMM,
+		ClipLeftX:      -cfg.BleedMM - fullBleedRasterEpsilonMM,
+		ClipRightX:     cfg.PageWidthMM + cfg.BleedMM + fullBleedRasterEpsilonMM,
 		HeaderY:        headerY,
-		CanvasTopY:     PageH + BleedMM + fullBleedRasterEpsilonMM,
-		CanvasBottomY:  -BleedMM - fullBleedRasterEpsilonMM,
+		CanvasTopY:     cfg.PageHeightMM + cfg.BleedMM + fullBleedRasterEpsilonMM,
+		CanvasBottomY:  -cfg.BleedMM - fullBleedRasterEpsilonMM,
 		FooterRuleY:    footerRuleY,
 		FolioX:         folioX,
 		FolioY:         folioY,
@@ -954,14 +982,13 @@ func (pb *pageBuilder) buildFullBleedPhotoSlot(
 	// eps expands the slot past the media box so rasterizers don't leave
 	// a sub-mm white row at the bottom (see formats.go).
 	eps := fullBleedRasterEpsilonMM
-	fullSlot := SlotRect{
-		X: 0, Y: 0,
-		W: PageW + 2*BleedMM + 2*eps,
-		H: PageH + 2*BleedMM + 2*eps,
-	}
+	bleed := pb.config.BleedMM
+	fullSlot := pb.config.FullBleedSlot()
+	fullSlot.W += 2 * eps
+	fullSlot.H += 2 * eps
 	ts := buildPhotoSlotNew(
 		fullSlot, img,
-		-BleedMM-eps, PageH+BleedMM+eps,
+		-bleed-eps, pb.config.PageHeightMM+bleed+eps,
 		false, 0,
 		ps.CropX, ps.CropY, cropScale,
 	)
@@ -1186,7 +1213,7 @@ func (pb *pageBuilder) buildSlots(
 		})
 	}
 
-	applyTextSlotPadding(tmplSlots, p.Format, pb.headingColorBleed, pb.bodyTextPadMM)
+	applySlotPadding(tmplSlots, pageSlotEdges(&p), pb.headingColorBleed, pb.bodyTextPadMM)
 
 	return tmplSlots, reportPhotos, buildFooterCaptions(ct, chapterColor)
 }
@@ -1271,6 +1298,32 @@ func rightNeighbors(format string, slotIndex int) []int {
 	return rightNeighborMap[neighborKey{format, slotIndex}]
 }
 
+// slotEdges tells which slots of a page touch the page margins and which
+// slots are their horizontal neighbours. It is implemented by the built-in
+// formats and by database.PageLayout for user-defined layouts.
+type slotEdges interface {
+	OnLeftEdge(slot int) bool
+	OnRightEdge(slot int) bool
+	LeftNeighbors(slot int) []int
+	RightNeighbors(slot int) []int
+}
+
+// builtinFormatEdges implements slotEdges for a built-in page format.
+type builtinFormatEdges string
+
+func (f builtinFormatEdges) OnLeftEdge(slot int) bool      { return isSlotOnLeftEdge(string(f), slot) }
+func (f builtinFormatEdges) OnRightEdge(slot int) bool     { return isSlotOnRightEdge(string(f), slot) }
+func (f builtinFormatEdges) LeftNeighbors(slot int) []int  { return leftNeighbors(string(f), slot) }
+func (f builtinFormatEdges) RightNeighbors(slot int) []int { return rightNeighbors(string(f), slot) }
+
+// pageSlotEdges returns the slot edges of a page's custom layout or built-in format.
+func pageSlotEdges(p *database.BookPage) slotEdges {
+	if p.Layout != nil {
+		return p.Layout
+	}
+	return builtinFormatEdges(p.Format)
+}
+
 // anyNeighborIsPhoto returns true if any slot at the given indices is a photo.
 func anyNeighborIsPhoto(slots []TemplateSlot, neighbors []int) bool {
 	for _, idx := range neighbors {
@@ -1294,19 +1347,25 @@ func anyNeighborIsPhoto(slots []TemplateSlot, neighbors []int) bool {
 // Interior edges adjacent to non-photo slots (text/captions/empty) get no
 // padding — only the photo-adjacent side breathes.
 func applyTextSlotPadding(slots []TemplateSlot, format string, headingBleed, bodyTextPad float64) {
+	applySlotPadding(slots, builtinFormatEdges(format), headingBleed, bodyTextPad)
+}
+
+// applySlotPadding is applyTextSlotPadding for the slot edges of any page
+// layout, built-in or user-defined.
+func applySlotPadding(slots []TemplateSlot, edges slotEdges, headingBleed, bodyTextPad float64) {
 	for i := range slots {
 		if !slots[i].HasText {
 			continue
 		}
-		if isSlotOnLeftEdge(format, i) {
+		if edges.OnLeftEdge(i) {
 			slots[i].BleedLeftMM = headingBleed
-		} else if anyNeighborIsPhoto(slots, leftNeighbors(format, i)) {
+		} else if anyNeighborIsPhoto(slots, edges.LeftNeighbors(i)) {
 			slots[i].TextPadLeft = bodyTextPad
 			slots[i].BleedLeftMM = bodyTextPad
 		}
-		if isSlotOnRightEdge(format, i) {
+		if edges.OnRightEdge(i) {
 			slots[i].BleedRightMM = headingBleed
-		} else if anyNeighborIsPhoto(slots, rightNeighbors(format, i)) {
+		} else if anyNeighborIsPhoto(slots, edges.RightNeighbors(i)) {
 			slots[i].TextPadRight = bodyTextPad
 			slots[i].BleedRightMM = bodyTextPad
 		}
@@ -1647,6 +1706,23 @@ func downloadPhotosWithProgress(
 	result := make(map[string]photoImage)
 	var mu sync.Mutex
 
+	workerCount := downloadConcurrency
+	if normalizeQuality(quality) == QualityOriginal {
+		workerCount = originalDownloadConcurrency
+	}
+	forEachPhoto(ctx, uids, workerCount, onProgress, func(uid string) {
+		downloadOnePhoto(pp, uid, tmpDir, result, &mu, quality)
+	})
+	return result
+}
+
+// forEachPhoto calls fetch for every UID on workerCount concurrent workers,
+// reporting downloading_photos progress via onProgress (which may be nil)
+// after each photo. Workers stop picking up photos once ctx is done.
+func forEachPhoto(
+	ctx context.Context, uids map[string]bool, workerCount int,
+	onProgress func(ProgressInfo), fetch func(uid string),
+) {
 	total := len(uids)
 	if onProgress != nil {
 		onProgress(ProgressInfo{Phase: "downloading_photos", Current: 0, Total: total})
@@ -1665,7 +1741,7 @@ func downloadPhotosWithProgress(
 			if ctx.Err() != nil {
 				return
 			}
-			downloadOnePhoto(pp, uid, tmpDir, result, &mu, quality)
+			fetch(uid)
 			if onProgress != nil {
 				onProgress(ProgressInfo{
 					Phase:    "downloading_photos",
@@ -1676,15 +1752,10 @@ func downloadPhotosWithProgress(
 			}
 		}
 	}
-	workerCount := downloadConcurrency
-	if normalizeQuality(quality) == QualityOriginal {
-		workerCount = originalDownloadConcurrency
-	}
 	for range workerCount {
 		wg.Go(worker)
 	}
 	wg.Wait()
-	return result
 }
 
 // downloadOnePhoto runs a single photo fetch under the shared result lock.
@@ -1710,13 +1781,9 @@ func downloadOnePhoto(
 func downloadPhoto(
 	pp *photoprism.PhotoPrism, uid string, tmpDir string, quality PhotoQuality,
 ) (*photoImage, error) {
-	photos, err := pp.GetPhotosWithQuery(1, 0, "uid:"+uid)
-	if err != nil || len(photos) == 0 {
-		return nil, fmt.Errorf("photo not found: %s", uid)
-	}
-	hash := photos[0].Hash
-	if hash == "" {
-		return nil, fmt.Errorf("photo has no hash: %s", uid)
+	hash, err := lookupPhotoHash(pp, uid)
+	if err != nil {
+		return nil, err
 	}
 
 	if normalizeQuality(quality) == QualityOriginal {
@@ -1730,6 +1797,19 @@ func downloadPhoto(
 	return downloadThumbnailPhoto(pp, uid, hash, tmpDir, size)
 }
 
+// lookupPhotoHash returns the file hash PhotoPrism serves a photo's
+// thumbnails under.
+func lookupPhotoHash(pp *photoprism.PhotoPrism, uid string) (string, error) {
+	photos, err := pp.GetPhotosWithQuery(1, 0, "uid:"+uid)
+	if err != nil || len(photos) == 0 {
+		return "", fmt.Errorf("photo not found: %s", uid)
+	}
+	if photos[0].Hash == "" {
+		return "", fmt.Errorf("photo has no hash: %s", uid)
+	}
+	return photos[0].Hash, nil
+}
+
 // downloadThumbnailPhoto fetches a single PhotoPrism thumbnail at the given
 // size and returns its path and dimensions.
 func downloadThumbnailPhoto(
@@ -2031,19 +2111,23 @@ func BuildBookTOC(ctx context.Context, br database.BookReader, bookID string) ([
 	}
 	SortPagesBySectionOrder(pages, sections)
 	groups := groupPagesBySection(pages, sections, chapters)
+	return buildTOCData(groups, computeSectionPageRanges(groups)), nil
+}
 
+// computeSectionPageRanges returns the printed [first, last] page numbers of
+// every section, numbering pages 1-based in group order as the full export
+// does.
+func computeSectionPageRanges(groups []sectionGroup) map[string][2]int {
 	ranges := make(map[string][2]int, len(groups))
 	pageNumber := 0
 	for _, g := range groups {
 		startPage := pageNumber + 1
-		for range g.pages {
-			pageNumber++
-		}
+		pageNumber += len(g.pages)
 		if len(g.pages) > 0 && g.sectionID != "" {
 			ranges[g.sectionID] = [2]int{startPage, pageNumber}
 		}
 	}
-	return buildTOCData(groups, ranges), nil
+	return ranges
 }
 
 // buildContentsSlotStub creates a TemplateSlot marker for a contents (table
@@ -2310,7 +2394,7 @@ func GenerateSinglePagePDF(
 	// book TOC so the preview matches what full book export would render.
 	injectContentsSlots(sections, input.TOC)
 
-	data := singlePageTemplateData(sections, typo)
+	data := newTemplateData(sections, typo, BookLayoutConfig(input.Book))
 
 	pdfData, err := compileLatex(ctx, data, tmpDir)
 	if err != nil {
@@ -2326,7 +2410,7 @@ func buildSinglePage(
 ) TemplatePage {
 	pageNum := max(input.PageNumber, 1)
 	pb := &pageBuilder{
-		config:            DefaultLayoutConfig(),
+		config:            BookLayoutConfig(input.Book),
 		photos:            photos,
 		captions:          input.Captions,
 		totalContentPages: pageNum,
@@ -2342,13 +2426,17 @@ func buildSinglePage(
 	return pb.buildContentPage(input.Page, input.ChapterColor)
 }
 
-// singlePageTemplateData assembles TemplateData for a single-page render,
-// applying the already-resolved book typography.
-func singlePageTemplateData(sections []TemplateSection, typo resolvedTypography) TemplateData {
+// newTemplateData assembles TemplateData for the given sections, applying
+// the already-resolved book typography and page setup.
+func newTemplateData(
+	sections []TemplateSection, typo resolvedTypography, config LayoutConfig,
+) TemplateData {
 	return TemplateData{
 		Sections:               sections,
-		PageW:                  PageW,
-		PageH:                  PageH,
+		PageW:                  config.PageWidthMM,
+		PageH:                  config.PageHeightMM,
+		MediaW:                 config.PageWidthMM + 2*config.BleedMM,
+		MediaH:                 config.PageHeightMM + 2*config.BleedMM,
 		BodyFontDeclaration:    typo.bodyFontDeclaration,
 		HeadingFontDeclaration: typo.headingFontDeclaration,
 		BodyFontSize:           typo.bodyFontSize,
@@ -824,6 +824,61 @@ func TestBuildTemplateData(t *testing.T) {
 	})
 }
 
+func TestBuildTemplateData_PageSetup(t *testing.T) {
+	book := &database.PhotoBook{PageSize: PageSizeA4, Orientation: OrientationPortrait, BleedMM: 5}
+	config := BookLayoutConfig(book)
+	groups := []sectionGroup{
+		{sectionID: "s1", title: "S1", pages: []database.BookPage{
+			{ID: "p1", SectionID: "s1", Format: "2_portrait",
+				Slots: []database.PageSlot{{SlotIndex: 0, PhotoUID: "photo1"}}},
+			{ID: "p2", SectionID: "s1", Format: "1_fullbleed",
+				Slots: []database.PageSlot{{SlotIndex: 0, PhotoUID: "photo1"}}},
+		}},
+	}
+	photos := map[string]photoImage{
+		"photo1": {path: "/tmp/photo1.jpg", width: 3000, height: 4000},
+	}
+	data, _ := buildTemplateData(groups, photos, nil, config, book)
+
+	if data.PageW != 210 || data.PageH != 297 {
+		t.Errorf("page = %.0fx%.0f, want 210x297", data.PageW, data.PageH)
+	}
+	if data.MediaW != 220 || data.MediaH != 307 {
+		t.Errorf("media = %.0fx%.0f, want 220x307", data.MediaW, data.MediaH)
+	}
+
+	page := data.Sections[0].Pages[0]
+	wantTop := 297 - config.TopMarginMM - config.HeaderHeightMM
+	if page.CanvasTopY != wantTop {
+		t.Errorf("CanvasTopY = %.2f, want %.2f", page.CanvasTopY, wantTop)
+	}
+	if page.CanvasBottomY != wantTop-config.CanvasHeightMM {
+		t.Errorf("CanvasBottomY = %.2f, want %.2f", page.CanvasBottomY, wantTop-config.CanvasHeightMM)
+	}
+	if page.ContentRightX-page.ContentLeftX != config.ContentWidth() {
+		t.Errorf("content width = %.2f, want %.2f", page.ContentRightX-page.ContentLeftX, config.ContentWidth())
+	}
+
+	fb := data.Sections[0].Pages[1].Slots[0]
+	eps := fullBleedRasterEpsilonMM
+	if fb.BorderX != -5-eps || fb.BorderW != 220+2*eps || fb.BorderH != 307+2*eps {
+		t.Errorf("full-bleed border = (%.2f, %.2f x %.2f), want (%.2f, %.2f x %.2f)",
+			fb.BorderX, fb.BorderW, fb.BorderH, -5-eps, 220+2*eps, 307+2*eps)
+	}
+	content := []TemplateSection{{Pages: data.Sections[0].Pages[:1]}}
+	if warnings := ValidatePages(content, config); len(warnings) > 0 {
+		t.Errorf("unexpected layout warnings: %+v", warnings)
+	}
+
+	out := renderBookTemplate(t, data)
+	if !strings.Contains(out, "paperwidth=210.00mm,paperheight=297.00mm") {
+		t.Error("expected the geometry paper size to be the book's page size")
+	}
+	if !strings.Contains(out, "width=220.00truemm,height=307.00truemm") {
+		t.Error("expected the crop media size to include the book's bleed")
+	}
+}
+
 // --- latexEscapeRaw ---
 
 func TestLatexEscapeRaw(t *testing.T) {
@@ -1906,6 +1961,28 @@ func TestApplyTextSlotPaddingSkipsPhotoSlots(t *testing.T) {
 	}
 }
 
+// TestApplySlotPaddingLayout verifies that user-defined layouts get heading
+// bleed on their page-edge slots and body-text padding next to photos.
+func TestApplySlotPaddingLayout(t *testing.T) {
+	layout := &database.PageLayout{
+		ID: "3_up", Name: "3-up", Rows: 2,
+		Cells: []database.PageLayoutCell{
+			{Col: 0, ColSpan: 6, Row: 0, RowSpan: 2},
+			{Col: 6, ColSpan: 6, Row: 0, RowSpan: 1},
+			{Col: 6, ColSpan: 6, Row: 1, RowSpan: 1},
+		},
+	}
+	slots := []TemplateSlot{{HasText: true}, {HasPhoto: true}, {HasText: true}}
+	applySlotPadding(slots, pageSlotEdges(&database.BookPage{Format: "3_up", Layout: layout}), 4.0, 3.0)
+
+	if got := slots[0]; got.BleedLeftMM != 4.0 || got.TextPadRight != 3.0 || got.BleedRightMM != 3.0 {
+		t.Errorf("slot 0 = %+v, want left heading bleed and right padding next to the photo", got)
+	}
+	if got := slots[2]; got.TextPadLeft != 0 || got.BleedLeftMM != 0 || got.BleedRightMM != 4.0 {
+		t.Errorf("slot 2 = %+v, want no left padding next to text and right heading bleed", got)
+	}
+}
+
 // TestNeighborMaps spot-checks the per-format neighbor lookup tables to
 // guard against accidental edits to the layout logic.
 func TestNeighborMaps(t *testing.T) {
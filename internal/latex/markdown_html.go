@@ -0,0 +1,208 @@
+package latex
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// htmlSmallCapsRe matches ^^text^^ small caps markers in HTML-escaped text.
+var htmlSmallCapsRe = regexp.MustCompile(`\^\^(.+?)\^\^`)
+
+// nbsp is the non-breaking space used by the HTML renderer for `~` and Czech
+// single-letter prepositions.
+const nbsp = "\u00a0"
+
+// MarkdownToHTML converts the same Markdown subset as MarkdownToLatex to HTML
+// for the web book export. User text is HTML-escaped before formatting, so
+// the result is safe to embed in a page.
+//
+// Supported syntax:.
+//   - # Heading / ## Subheading → <h3> / <h4> (the web book uses <h1> for
+//     chapters and <h2> for sections)
+//   - **bold**, *italic*, ^^small caps^^, ~ (non-breaking space), \n (line break)
+//   - - item / * item, 1. item → <ul> / <ol>
+//   - > quote         → <blockquote>
+//   - GFM pipe tables → <table>, with percentage widths from the separator row
+//   - ->text<- / ->text-> → centered / right-aligned paragraph
+//   - --- → <hr>
+//   - consecutive plain lines form one paragraph; blank lines separate them
+func MarkdownToHTML(md string) string {
+	lines := strings.Split(md, "\n")
+	var out, para []string
+	flush := func() {
+		if len(para) > 0 {
+			out = append(out, "<p>"+strings.Join(para, "\n")+"</p>")
+			para = nil
+		}
+	}
+
+	for i := 0; i < len(lines); {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" {
+			flush()
+			i++
+			continue
+		}
+		if block, next, ok := htmlBlock(lines, i); ok {
+			flush()
+			out = append(out, block)
+			i = next
+			continue
+		}
+		// Plain text continues the current paragraph.
+		para = append(para, htmlInline(trimmed))
+		i++
+	}
+	flush()
+
+	return strings.Join(out, "\n")
+}
+
+// htmlBlock renders the block-level construct starting at line i and returns
+// it with the index of the next unconsumed line. ok is false for plain text.
+func htmlBlock(lines []string, i int) (block string, next int, ok bool) {
+	trimmed := strings.TrimSpace(lines[i])
+	switch {
+	case trimmed == "---":
+		return "<hr>", i + 1, true
+	case strings.HasPrefix(trimmed, "## "):
+		return "<h4>" + htmlInline(trimmed[3:]) + "</h4>", i + 1, true
+	case strings.HasPrefix(trimmed, "# "):
+		return "<h3>" + htmlInline(trimmed[2:]) + "</h3>", i + 1, true
+	case isUnorderedListItem(trimmed):
+		items, next := collectHTMLListItems(lines, i, isUnorderedListItem, stripListMarker)
+		return "<ul>" + strings.Join(items, "") + "</ul>", next, true
+	case isOrderedListItem(trimmed):
+		items, next := collectHTMLListItems(lines, i, isOrderedListItem, stripOrderedListMarker)
+		return "<ol>" + strings.Join(items, "") + "</ol>", next, true
+	case isBlockquoteLine(trimmed):
+		quote, next := collectHTMLBlockquote(lines, i)
+		return "<blockquote><p>" + strings.Join(quote, "\n") + "</p></blockquote>", next, true
+	case isTableLine(trimmed) && i+1 < len(lines) && isTableSeparator(strings.TrimSpace(lines[i+1])):
+		table, next := collectHTMLTable(lines, i)
+		return table, next, true
+	}
+	if m := alignCenterRe.FindStringSubmatch(trimmed); m != nil {
+		return `<p class="center">` + htmlInline(m[1]) + "</p>", i + 1, true
+	}
+	if m := alignRightRe.FindStringSubmatch(trimmed); m != nil {
+		return `<p class="right">` + htmlInline(m[1]) + "</p>", i + 1, true
+	}
+	return "", i, false
+}
+
+// htmlInline escapes user text and applies bold, italic, small caps,
+// non-breaking space and line break formatting plus Czech typography — the
+// HTML counterpart of inlineFormat.
+func htmlInline(s string) string {
+	s = html.EscapeString(s)
+	s = boldRe.ReplaceAllString(s, `<strong>$1</strong>`)
+	s = italicRe.ReplaceAllString(s, `<em>$1</em>`)
+	s = htmlSmallCapsRe.ReplaceAllString(s, `<span class="sc">$1</span>`)
+
+	// Escaped tilde \~ stays a literal tilde; bare ~ is a non-breaking space.
+	const tildePlaceholder = "\x00TILDE\x00"
+	s = strings.ReplaceAll(s, `\~`, tildePlaceholder)
+	s = strings.ReplaceAll(s, "~", nbsp)
+	s = strings.ReplaceAll(s, tildePlaceholder, "~")
+
+	// Forced line break: literal \n in source text.
+	s = strings.ReplaceAll(s, `\n`, "<br>")
+
+	return czechTypographyRe.ReplaceAllString(s, "${1}${2}"+nbsp)
+}
+
+// htmlCaption formats a photo caption for the web book: **bold**, *italic*
+// and `~` as in the PDF, with embedded newlines as line breaks.
+func htmlCaption(s string) string {
+	s = html.EscapeString(s)
+	s = boldRe.ReplaceAllString(s, `<strong>$1</strong>`)
+	s = italicRe.ReplaceAllString(s, `<em>$1</em>`)
+	s = strings.ReplaceAll(s, "~", nbsp)
+	s = strings.ReplaceAll(s, "\n", "<br>")
+	return czechTypographyRe.ReplaceAllString(s, "${1}${2}"+nbsp)
+}
+
+// collectHTMLListItems consumes consecutive list items and returns <li> elements.
+func collectHTMLListItems(
+	lines []string, start int,
+	isItem func(string) bool, stripMarker func(string) string,
+) ([]string, int) {
+	var items []string
+	i := start
+	for i < len(lines) {
+		t := strings.TrimSpace(lines[i])
+		if !isItem(t) {
+			break
+		}
+		items = append(items, "<li>"+htmlInline(stripMarker(t))+"</li>")
+		i++
+	}
+	return items, i
+}
+
+// collectHTMLBlockquote consumes consecutive blockquote lines.
+func collectHTMLBlockquote(lines []string, start int) ([]string, int) {
+	var quote []string
+	i := start
+	for i < len(lines) {
+		t := strings.TrimSpace(lines[i])
+		if !isBlockquoteLine(t) {
+			break
+		}
+		quote = append(quote, htmlInline(stripBlockquoteMarker(t)))
+		i++
+	}
+	return quote, i
+}
+
+// collectHTMLTable consumes a GFM pipe table and returns it as an HTML table.
+func collectHTMLTable(lines []string, start int) (string, int) {
+	i := start
+	headerCells := parseTableCells(lines[i])
+	numCols := len(headerCells)
+	i++ // skip header row
+	widths := parseColumnWidths(lines[i])
+	i++ // skip separator row
+
+	var b strings.Builder
+	b.WriteString("<table>")
+	if widths != nil {
+		b.WriteString("<colgroup>")
+		for j := range numCols {
+			if j < len(widths) && widths[j] > 0 {
+				fmt.Fprintf(&b, `<col style="width: %d%%">`, widths[j])
+			} else {
+				b.WriteString("<col>")
+			}
+		}
+		b.WriteString("</colgroup>")
+	}
+	b.WriteString("<thead><tr>")
+	for _, cell := range headerCells {
+		b.WriteString("<th>" + htmlInline(cell) + "</th>")
+	}
+	b.WriteString("</tr></thead><tbody>")
+
+	for i < len(lines) {
+		trimmed := strings.TrimSpace(lines[i])
+		if !isTableLine(trimmed) {
+			break
+		}
+		cells := parseTableCells(trimmed)
+		for len(cells) < numCols {
+			cells = append(cells, "")
+		}
+		b.WriteString("<tr>")
+		for _, cell := range cells[:numCols] {
+			b.WriteString("<td>" + htmlInline(cell) + "</td>")
+		}
+		b.WriteString("</tr>")
+		i++
+	}
+
+	b.WriteString("</tbody></table>")
+	return b.String(), i
+}
@@ -0,0 +1,59 @@
+package latex
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdownToHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"heading", "# Title", "<h3>Title</h3>"},
+		{"subheading", "## Sub", "<h4>Sub</h4>"},
+		{"paragraph joins lines", "one\ntwo\n\nthree", "<p>one\ntwo</p>\n<p>three</p>"},
+		{"inline", "**b** *i* ^^sc^^", `<p><strong>b</strong> <em>i</em> <span class="sc">sc</span></p>`},
+		{"escapes html", "<script>&", "<p>&lt;script&gt;&amp;</p>"},
+		{"tilde", `a~b \~`, "<p>a\u00a0b ~</p>"},
+		{"line break", `a\nb`, "<p>a<br>b</p>"},
+		{"czech preposition", "jdu k domu", "<p>jdu k\u00a0domu</p>"},
+		{"unordered list", "- a\n- b", "<ul><li>a</li><li>b</li></ul>"},
+		{"ordered list", "1. a\n2. b", "<ol><li>a</li><li>b</li></ol>"},
+		{"blockquote", "> a\n> b", "<blockquote><p>a\nb</p></blockquote>"},
+		{"center", "->mid<-", `<p class="center">mid</p>`},
+		{"right", "->end->", `<p class="right">end</p>`},
+		{"rule", "---", "<hr>"},
+		{"list ends paragraph", "text\n- item", "<p>text</p>\n<ul><li>item</li></ul>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MarkdownToHTML(tt.input); got != tt.want {
+				t.Errorf("MarkdownToHTML(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownToHTML_Table(t *testing.T) {
+	got := MarkdownToHTML("| A | B |\n|--- 60%---|--- 40%---|\n| 1 | 2 | 3 |\n| x |")
+	for _, want := range []string{
+		`<col style="width: 60%"><col style="width: 40%">`,
+		"<thead><tr><th>A</th><th>B</th></tr></thead>",
+		"<tr><td>1</td><td>2</td></tr>",
+		"<tr><td>x</td><td></td></tr>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("MarkdownToHTML table = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestHTMLCaption(t *testing.T) {
+	got := htmlCaption("**Dům** <1920>\nfoto v zimě")
+	want := "<strong>Dům</strong> &lt;1920&gt;<br>foto v\u00a0zimě"
+	if got != want {
+		t.Errorf("htmlCaption() = %q, want %q", got, want)
+	}
+}
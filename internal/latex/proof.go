@@ -0,0 +1,151 @@
+package latex
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultProofWatermark is the diagonal watermark of proof exports when no
+// custom text is given.
+const DefaultProofWatermark = "NÁHLED"
+
+// ProofCommentsHeaderText is the headline of the review-comments page that
+// proof exports append to every section.
+const ProofCommentsHeaderText = "Poznámky"
+
+const (
+	// proofWatermarkMaxRunes caps the watermark length so the text still
+	// fits the page diagonal at a readable size.
+	proofWatermarkMaxRunes = 40
+	// proofWatermarkCoverage is the share of the page diagonal the
+	// watermark text spans.
+	proofWatermarkCoverage = 0.6
+	// proofGlyphWidthEm approximates the average advance of a bold capital
+	// in em, used to size the watermark without measuring it in TeX.
+	proofGlyphWidthEm = 0.65
+	// proofWatermarkMaxPt keeps short watermarks ("A") from filling the page.
+	proofWatermarkMaxPt = 160.0
+	// proofLabelOffsetMM is the distance of the page ID label from the top
+	// page edge.
+	proofLabelOffsetMM = 4.0
+	// proofLineSpacingMM is the distance between the ruled writing lines of
+	// a review-comments page.
+	proofLineSpacingMM = 9.0
+	// proofCommentsTitleGapMM separates the comments headline from the
+	// first writing line.
+	proofCommentsTitleGapMM = 14.0
+	mmPerPt                 = 25.4 / 72.0
+)
+
+// ProofOverlay holds the proof-mode marks drawn on top of every page: a
+// diagonal watermark through the page center and a label with the page
+// number and page ID at the top edge.
+type ProofOverlay struct {
+	Watermark         string  // LaTeX-escaped watermark text
+	WatermarkFontSize float64 // pt
+	WatermarkAngle    float64 // degrees, along the page diagonal
+	CenterX           float64
+	CenterY           float64
+	LabelY            float64
+}
+
+// ProofCommentsPage is a review-comments page appended after the pages of a
+// section in proof mode. It has no folio and does not advance the page
+// numbering, so page numbers in reviewers' notes match the printed book.
+// A blank back page follows it, so the comments leaf keeps every later book
+// page on its printed recto/verso side.
+type ProofCommentsPage struct {
+	Header       string // e.g. "Poznámky"
+	SectionTitle string
+	StartPage    int
+	EndPage      int
+	LeftX        float64
+	RightX       float64
+	TitleY       float64
+	LineYs       []float64 // Y of each ruled writing line, top to bottom
+	IsLast       bool
+}
+
+// applyProofMode turns the template data into a proof: every page gets the
+// watermark and page ID overlay, and every section with pages gets a
+// review-comments page after its last page. An empty watermark falls back to
+// DefaultProofWatermark.
+func applyProofMode(data *TemplateData, config LayoutConfig, watermark string) {
+	if watermark == "" {
+		watermark = DefaultProofWatermark
+	}
+	data.Proof = &ProofOverlay{
+		Watermark:         latexEscapeRaw(watermark),
+		WatermarkFontSize: proofWatermarkFontSize(watermark, config.PageWidthMM, config.PageHeightMM),
+		WatermarkAngle:    math.Atan2(config.PageHeightMM, config.PageWidthMM) * 180 / math.Pi,
+		CenterX:           config.PageWidthMM / 2,
+		CenterY:           config.PageHeightMM / 2,
+		LabelY:            config.PageHeightMM - proofLabelOffsetMM,
+	}
+
+	var last *ProofCommentsPage
+	for i := range data.Sections {
+		sec := &data.Sections[i]
+		if len(sec.Pages) == 0 {
+			continue
+		}
+		// The comments page follows the section, so no book page is the
+		// last one any more.
+		for j := range sec.Pages {
+			sec.Pages[j].IsLast = false
+		}
+		sec.ProofComments = buildProofCommentsPage(sec, config)
+		last = sec.ProofComments
+	}
+	if last != nil {
+		last.IsLast = true
+	}
+}
+
+// buildProofCommentsPage lays out the review-comments page of a section:
+// a headline in the header zone and ruled writing lines down the canvas.
+// The margins follow the side the page lands on, right after the section's
+// last page.
+func buildProofCommentsPage(sec *TemplateSection, config LayoutConfig) *ProofCommentsPage {
+	leftX := config.InsideMarginMM
+	if sec.Pages[len(sec.Pages)-1].IsRecto {
+		leftX = config.OutsideMarginMM
+	}
+	titleY := config.PageHeightMM - config.TopMarginMM - config.HeaderHeightMM
+	cp := &ProofCommentsPage{
+		Header:       ProofCommentsHeaderText,
+		SectionTitle: sec.Title,
+		StartPage:    sec.Pages[0].PageNumber,
+		EndPage:      sec.Pages[len(sec.Pages)-1].PageNumber,
+		LeftX:        leftX,
+		RightX:       leftX + config.ContentWidth(),
+		TitleY:       titleY,
+	}
+	for y := titleY - proofCommentsTitleGapMM; y >= config.BottomMarginMM; y -= proofLineSpacingMM {
+		cp.LineYs = append(cp.LineYs, y)
+	}
+	return cp
+}
+
+// proofWatermarkFontSize returns the watermark font size (pt) at which the
+// text spans proofWatermarkCoverage of the page diagonal.
+func proofWatermarkFontSize(text string, pageW, pageH float64) float64 {
+	runes := max(utf8.RuneCountInString(text), 1)
+	widthMM := math.Hypot(pageW, pageH) * proofWatermarkCoverage
+	return min(widthMM/(float64(runes)*proofGlyphWidthEm*mmPerPt), proofWatermarkMaxPt)
+}
+
+// ValidateProofWatermark checks a custom watermark text. Empty means the
+// default watermark.
+func ValidateProofWatermark(s string) error {
+	if strings.ContainsAny(s, "\r\n\t") {
+		return errors.New("watermark must be a single line")
+	}
+	if n := utf8.RuneCountInString(s); n > proofWatermarkMaxRunes {
+		return fmt.Errorf("watermark is too long: %d characters (max %d)", n, proofWatermarkMaxRunes)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package latex
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+func proofTestData(t *testing.T) TemplateData {
+	t.Helper()
+	groups := []sectionGroup{
+		{sectionID: "s1", title: "Zima & mráz", pages: []database.BookPage{
+			{ID: "page-a", SectionID: "s1", Format: "1_fullscreen", Slots: dummySlot()},
+			{ID: "page-b", SectionID: "s1", Format: "1_fullscreen", Slots: dummySlot()},
+		}},
+		{sectionID: "s2", title: "Jaro", pages: []database.BookPage{
+			{ID: "page-c", SectionID: "s2", Format: "1_fullscreen", Slots: dummySlot()},
+		}},
+	}
+	data, _ := buildTemplateData(groups, nil, nil, DefaultLayoutConfig(), nil)
+	return data
+}
+
+func TestApplyProofMode(t *testing.T) {
+	data := proofTestData(t)
+	config := DefaultLayoutConfig()
+	applyProofMode(&data, config, "")
+
+	if data.Proof == nil || data.Proof.Watermark != DefaultProofWatermark {
+		t.Fatalf("Proof = %+v, want the default watermark", data.Proof)
+	}
+	wantAngle := math.Atan2(config.PageHeightMM, config.PageWidthMM) * 180 / math.Pi
+	if math.Abs(data.Proof.WatermarkAngle-wantAngle) > 1e-9 {
+		t.Errorf("WatermarkAngle = %.2f, want %.2f", data.Proof.WatermarkAngle, wantAngle)
+	}
+
+	for _, sec := range data.Sections {
+		for _, p := range sec.Pages {
+			if p.IsLast {
+				t.Errorf("page %d is IsLast, want the last comments page to end the book", p.PageNumber)
+			}
+		}
+	}
+	first, second := data.Sections[0].ProofComments, data.Sections[1].ProofComments
+	if first == nil || second == nil {
+		t.Fatal("every section should get a comments page")
+	}
+	if first.StartPage != 1 || first.EndPage != 2 || second.StartPage != 3 || second.EndPage != 3 {
+		t.Errorf("comments page ranges = %d-%d, %d-%d, want 1-2, 3-3",
+			first.StartPage, first.EndPage, second.StartPage, second.EndPage)
+	}
+	if first.IsLast || !second.IsLast {
+		t.Error("only the final comments page should be IsLast")
+	}
+	// Page 2 is a verso, so the first comments page is a recto; page 3 is a
+	// recto, so the second one is a verso.
+	if first.LeftX != config.InsideMarginMM || second.LeftX != config.OutsideMarginMM {
+		t.Errorf("comments page LeftX = %.1f, %.1f, want %.1f (recto), %.1f (verso)",
+			first.LeftX, second.LeftX, config.InsideMarginMM, config.OutsideMarginMM)
+	}
+	if len(first.LineYs) == 0 || first.LineYs[len(first.LineYs)-1] < config.BottomMarginMM {
+		t.Errorf("writing lines = %v, want lines above the bottom margin", first.LineYs)
+	}
+	if data.Sections[0].Pages[1].PageID != "page-b" {
+		t.Errorf("PageID = %q, want page-b", data.Sections[0].Pages[1].PageID)
+	}
+}
+
+func TestApplyProofMode_CustomWatermarkEscaped(t *testing.T) {
+	data := proofTestData(t)
+	applyProofMode(&data, DefaultLayoutConfig(), "50% DRAFT")
+	if data.Proof.Watermark != `50\% DRAFT` {
+		t.Errorf("Watermark = %q, want LaTeX-escaped text", data.Proof.Watermark)
+	}
+}
+
+func TestProofWatermarkFontSize(t *testing.T) {
+	long := proofWatermarkFontSize("PRELIMINARY FAMILY REVIEW", PageW, PageH)
+	short := proofWatermarkFontSize("NÁHLED", PageW, PageH)
+	if long >= short {
+		t.Errorf("longer watermark should be smaller: %.1f >= %.1f", long, short)
+	}
+	if got := proofWatermarkFontSize("A", PageW, PageH); got != proofWatermarkMaxPt {
+		t.Errorf("single letter size = %.1f, want the %.0f pt cap", got, proofWatermarkMaxPt)
+	}
+}
+
+func TestValidateProofWatermark(t *testing.T) {
+	if err := ValidateProofWatermark(""); err != nil {
+		t.Errorf("empty watermark: %v", err)
+	}
+	if err := ValidateProofWatermark("KONCEPT"); err != nil {
+		t.Errorf("valid watermark: %v", err)
+	}
+	if err := ValidateProofWatermark("a\nb"); err == nil {
+		t.Error("expected error for multi-line watermark")
+	}
+	if err := ValidateProofWatermark(strings.Repeat("x", proofWatermarkMaxRunes+1)); err == nil {
+		t.Error("expected error for too long watermark")
+	}
+}
+
+func TestProofTemplate(t *testing.T) {
+	data := proofTestData(t)
+	applyProofMode(&data, DefaultLayoutConfig(), "")
+	out := renderBookTemplate(t, data)
+
+	// Three book pages plus two comments leaves (comments page and blank
+	// back), one \newpage between each.
+	if got := strings.Count(out, `\newpage`); got != 6 {
+		t.Errorf("got %d \\newpage, want 6", got)
+	}
+	if got := strings.Count(out, `{NÁHLED}`); got != 7 {
+		t.Errorf("got %d watermarks, want one per page (7)", got)
+	}
+	for _, want := range []string{
+		`{3\quad page-c}`,
+		`{Poznámky: Zima \& mráz\quad\mdseries1\,--\,2}`,
+		`{Poznámky: Jaro\quad\mdseries3}`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("rendered template does not contain %q", want)
+		}
+	}
+	if strings.Index(out, "Poznámky: Jaro") < strings.Index(out, "page-c") {
+		t.Error("comments page should follow the section's pages")
+	}
+
+	regular := renderBookTemplate(t, proofTestData(t))
+	if strings.Contains(regular, "NÁHLED") || strings.Contains(regular, "Poznámky") {
+		t.Error("regular export should not contain proof marks")
+	}
+}
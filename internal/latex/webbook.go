@@ -0,0 +1,448 @@
+package latex
+
+import (
+	"archive/zip"
+	"context"
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+)
+
+//go:embed templates/webbook.html templates/webbook.css
+var webBookFS embed.FS
+
+// Web book photo sizes. Every photo is exported as a small and a large
+// PhotoPrism thumbnail that browsers pick from via srcset.
+const (
+	webImageSmall      = "fit_720"
+	webImageLarge      = "fit_1920"
+	webImageSmallWidth = 720
+	webImageLargeWidth = 1920
+	webImageDir        = "images"
+
+	// webStackBreakpointPx is the viewport width below which the web book
+	// stacks the slots of a page instead of reproducing the printed layout.
+	// It must match the media query in templates/webbook.css.
+	webStackBreakpointPx = 700
+)
+
+// hexColorRe matches a chapter color (hex without #) that is safe to embed in CSS.
+var hexColorRe = regexp.MustCompile(`^[0-9A-Fa-f]{6}$`)
+
+// webImage is a photo exported to the web book, with paths relative to the
+// book root.
+type webImage struct {
+	small, large  string
+	width, height int // pixel size of the large image
+}
+
+// WebBookData is the root data passed to the web book template.
+type WebBookData struct {
+	Title          string
+	Description    string
+	ContentsHeader string
+	RootStyle      template.CSS // fonts and page aspect ratio custom properties
+	TOC            []TOCChapter
+	Sections       []WebSection
+}
+
+// WebSection is a book section with its pages. ChapterTitle is set on the
+// first section of each chapter so the chapter heading is rendered once.
+type WebSection struct {
+	ID           string
+	Title        string
+	ChapterTitle string
+	Style        template.CSS // chapter color custom properties
+	Pages        []WebPage
+}
+
+// WebPage is one book page. Page numbers match the PDF export so the table
+// of contents links to the same pages the printed book lists.
+type WebPage struct {
+	Number    int
+	Title     string
+	FullBleed bool
+	Slots     []WebSlot
+	Captions  []WebCaption
+}
+
+// WebSlot is a positioned slot of a page. At most one of Photo, Text,
+// Captions, and Contents is set; a slot with none of them is empty.
+type WebSlot struct {
+	Style    template.CSS // position as percentages of the page canvas
+	Photo    *WebPhoto
+	Text     template.HTML
+	TextType string
+	Captions []WebCaption
+	Contents bool
+}
+
+// WebPhoto is a responsive image in a photo slot.
+type WebPhoto struct {
+	Src    string
+	SrcSet string
+	Sizes  string
+	Width  int
+	Height int
+	Alt    string
+	Style  template.CSS // crop focal point and zoom
+	Marker int
+}
+
+// WebCaption is a page caption with the markers of the photos it belongs to.
+type WebCaption struct {
+	Markers []int
+	Text    template.HTML
+}
+
+// WriteWebBook renders a photo book as a self-contained static web book and
+// writes it to w as a ZIP archive with index.html, style.css, and responsive
+// images. It uses the same book model as the PDF export — page layouts,
+// captions, markdown text, and the table of contents — but needs no TeX
+// installation. Progress is reported via onProgress (may be nil) with the
+// phases downloading_photos and rendering_html.
+func WriteWebBook(
+	ctx context.Context, pp *photoprism.PhotoPrism,
+	br database.BookReader, bookID string, w io.Writer, onProgress func(ProgressInfo),
+) error {
+	m, err := loadBookModel(ctx, br, bookID)
+	if err != nil {
+		return err
+	}
+	captions := buildCaptionMap(ctx, br, m.sections)
+
+	tmpDir, err := os.MkdirTemp("", "book-web-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	images, err := downloadWebImages(ctx, pp, collectPhotoUIDs(m.pages), tmpDir, onProgress)
+	if err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("web book export cancelled: %w", ctx.Err())
+	}
+
+	if onProgress != nil {
+		onProgress(ProgressInfo{Phase: "rendering_html"})
+	}
+	groups := groupPagesBySection(m.pages, m.sections, m.chapters)
+	data := buildWebBookData(m.book, groups, images, captions, BookLayoutConfig(m.book))
+	return writeWebBookArchive(w, data, images, tmpDir)
+}
+
+// downloadWebImages fetches the small and large web image of every photo
+// into dir, keyed by photo UID. Photos that fail to download are logged and
+// left out; their slots render empty.
+func downloadWebImages(
+	ctx context.Context, pp *photoprism.PhotoPrism,
+	uids map[string]bool, dir string, onProgress func(ProgressInfo),
+) (map[string]webImage, error) {
+	if err := os.MkdirAll(filepath.Join(dir, webImageDir), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create image directory: %w", err)
+	}
+	result := make(map[string]webImage, len(uids))
+	var mu sync.Mutex
+	forEachPhoto(ctx, uids, downloadConcurrency, onProgress, func(uid string) {
+		img, err := downloadWebImage(pp, uid, dir)
+		if err != nil {
+			log.Printf("WARNING: failed to download web image %s: %v", uid, err)
+			return
+		}
+		mu.Lock()
+		result[uid] = *img
+		mu.Unlock()
+	})
+	return result, nil
+}
+
+// downloadWebImage fetches both web sizes of a photo into dir.
+func downloadWebImage(pp *photoprism.PhotoPrism, uid, dir string) (*webImage, error) {
+	hash, err := lookupPhotoHash(pp, uid)
+	if err != nil {
+		return nil, err
+	}
+	img := &webImage{
+		small: path.Join(webImageDir, fmt.Sprintf("%s-%d.jpg", uid, webImageSmallWidth)),
+		large: path.Join(webImageDir, fmt.Sprintf("%s-%d.jpg", uid, webImageLargeWidth)),
+	}
+	for size, rel := range map[string]string{webImageSmall: img.small, webImageLarge: img.large} {
+		data, _, err := pp.GetPhotoThumbnail(hash, size)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s thumbnail: %w", size, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, rel), data, 0o600); err != nil {
+			return nil, fmt.Errorf("failed to write photo: %w", err)
+		}
+	}
+	cfg, _, err := decodeConfigFile(filepath.Join(dir, img.large))
+	if err != nil {
+		return nil, err
+	}
+	img.width, img.height = cfg.Width, cfg.Height
+	return img, nil
+}
+
+// webPageBuilder tracks state while building web book pages across sections.
+type webPageBuilder struct {
+	config     LayoutConfig
+	images     map[string]webImage
+	captions   CaptionMap
+	pageNumber int
+}
+
+// buildWebBookData builds the web book template data. Pages are numbered
+// like the PDF export, so the table of contents uses the same page ranges.
+func buildWebBookData(
+	book *database.PhotoBook, groups []sectionGroup,
+	images map[string]webImage, captions CaptionMap, config LayoutConfig,
+) WebBookData {
+	wb := &webPageBuilder{config: config, images: images, captions: captions}
+	sections := make([]WebSection, 0, len(groups))
+	lastChapterID := ""
+	for _, g := range groups {
+		s := wb.buildSection(g)
+		if g.chapterID != "" && g.chapterID != lastChapterID {
+			s.ChapterTitle = g.chapterTitle
+		}
+		lastChapterID = g.chapterID
+		sections = append(sections, s)
+	}
+
+	data := WebBookData{
+		ContentsHeader: ContentsHeaderText,
+		RootStyle:      webRootStyle(book, config),
+		TOC:            buildTOCData(groups, computeSectionPageRanges(groups)),
+		Sections:       sections,
+	}
+	if book != nil {
+		data.Title = book.Title
+		data.Description = book.Description
+	}
+	return data
+}
+
+// webRootStyle returns the custom properties for the book fonts and the
+// page canvas aspect ratio.
+func webRootStyle(book *database.PhotoBook, config LayoutConfig) template.CSS {
+	bodyFont, headingFont := DefaultBodyFont, DefaultHeadingFont
+	if book != nil {
+		if ValidateFont(book.BodyFont) {
+			bodyFont = book.BodyFont
+		}
+		if ValidateFont(book.HeadingFont) {
+			headingFont = book.HeadingFont
+		}
+	}
+	body, _ := GetFont(bodyFont)
+	heading, _ := GetFont(headingFont)
+	//nolint:gosec // Font names come from the registry; the numbers are formatted.
+	return template.CSS(fmt.Sprintf(
+		"--body-font: %q, %s; --heading-font: %q, %s; --page-aspect: %.2f / %.2f",
+		body.DisplayName, body.Category, heading.DisplayName, heading.Category,
+		config.ContentWidth(), config.CanvasHeightMM,
+	))
+}
+
+// webChapterStyle returns the custom properties coloring a section's
+// headings with its chapter color, or "" without a (valid) color.
+func webChapterStyle(chapterColor string) template.CSS {
+	if !hexColorRe.MatchString(chapterColor) {
+		return ""
+	}
+	//nolint:gosec // The color is validated as six hex digits.
+	return template.CSS(fmt.Sprintf("--chapter-color: #%s; --chapter-text: %s",
+		chapterColor, contrastTextColorLatex(chapterColor)))
+}
+
+// buildSection builds a web section. Every page is emitted, including empty
+// ones, so page numbers stay in sync with the PDF export.
+func (wb *webPageBuilder) buildSection(g sectionGroup) WebSection {
+	pages := make([]WebPage, 0, len(g.pages))
+	for _, p := range g.pages {
+		wb.pageNumber++
+		pages = append(pages, wb.buildPage(p, g.chapterColor))
+	}
+	return WebSection{
+		ID:    g.sectionID,
+		Title: g.title,
+		Style: webChapterStyle(g.chapterColor),
+		Pages: pages,
+	}
+}
+
+// buildPage builds a web page with its slots placed as on the printed
+// canvas. Captions are listed below the page, or inside the captions slot
+// when the page has one.
+func (wb *webPageBuilder) buildPage(p database.BookPage, chapterColor string) WebPage {
+	page := WebPage{Number: wb.pageNumber, Title: p.Description}
+	if p.Format == FormatFullbleed {
+		page.FullBleed = true
+		full := SlotRect{W: wb.config.ContentWidth(), H: wb.config.CanvasHeightMM}
+		page.Slots = []WebSlot{wb.buildSlot(p, 0, full, 0)}
+		return page
+	}
+
+	rects := PageSlotsGrid(&p, wb.config)
+	ct := buildCaptionTracking(p, rects, wb.captions)
+	captions := buildWebCaptions(buildFooterCaptions(ct, chapterColor))
+
+	page.Slots = make([]WebSlot, len(rects))
+	for i, rect := range rects {
+		page.Slots[i] = wb.buildSlot(p, i, rect, ct.markerMap[i])
+	}
+	if idx := findCaptionsSlotIndex(p, rects); idx >= 0 {
+		page.Slots[idx].Captions = captions
+	} else {
+		page.Captions = captions
+	}
+	return page
+}
+
+// buildSlot builds the web slot at slotIndex of a page.
+func (wb *webPageBuilder) buildSlot(p database.BookPage, slotIndex int, rect SlotRect, marker int) WebSlot {
+	canvasW, canvasH := wb.config.ContentWidth(), wb.config.CanvasHeightMM
+	//nolint:gosec // Only formatted numbers.
+	slot := WebSlot{Style: template.CSS(fmt.Sprintf(
+		"left: %.3f%%; top: %.3f%%; width: %.3f%%; height: %.3f%%",
+		rect.X/canvasW*100, rect.Y/canvasH*100, rect.W/canvasW*100, rect.H/canvasH*100,
+	))}
+
+	ps := getPageSlot(p, slotIndex)
+	switch {
+	case ps.IsTextSlot():
+		//nolint:gosec // MarkdownToHTML escapes the user text.
+		slot.Text = template.HTML(MarkdownToHTML(ps.TextContent))
+		slot.TextType = DetectTextType(ps.TextContent)
+	case ps.IsContents():
+		slot.Contents = true
+	case ps.PhotoUID != "":
+		if img, ok := wb.images[ps.PhotoUID]; ok {
+			alt := lookupCaption(wb.captions, p.SectionID, ps.PhotoUID)
+			slot.Photo = buildWebPhoto(img, ps, rect.W/canvasW, alt, marker)
+		}
+	}
+	return slot
+}
+
+// buildWebPhoto builds a responsive photo. widthFraction is the slot width
+// relative to the page canvas, used to tell browsers which image to pick.
+// Like the PDF, the photo covers its slot around the crop focal point and
+// crop scales below 1 zoom in.
+func buildWebPhoto(img webImage, ps database.PageSlot, widthFraction float64, alt string, marker int) *WebPhoto {
+	cropX, cropY := ps.CropX*100, ps.CropY*100
+	style := fmt.Sprintf("object-position: %.1f%% %.1f%%", cropX, cropY)
+	if ps.CropScale > 0 && ps.CropScale < 1 {
+		style += fmt.Sprintf("; transform: scale(%.3f); transform-origin: %.1f%% %.1f%%",
+			1/ps.CropScale, cropX, cropY)
+	}
+	return &WebPhoto{
+		Src: img.large,
+		SrcSet: fmt.Sprintf("%s %dw, %s %dw",
+			img.small, webImageSmallWidth, img.large, webImageLargeWidth),
+		Sizes: fmt.Sprintf("(max-width: %dpx) 100vw, %dvw",
+			webStackBreakpointPx, int(math.Ceil(widthFraction*100))),
+		Width:  img.width,
+		Height: img.height,
+		Alt:    alt,
+		Style:  template.CSS(style), //nolint:gosec // Only formatted numbers.
+		Marker: marker,
+	}
+}
+
+// buildWebCaptions converts a page's merged footer captions to web captions.
+func buildWebCaptions(caps []FooterCaption) []WebCaption {
+	if len(caps) == 0 {
+		return nil
+	}
+	result := make([]WebCaption, len(caps))
+	for i, c := range caps {
+		result[i] = WebCaption{
+			Markers: c.Markers,
+			Text:    template.HTML(htmlCaption(c.Caption)), //nolint:gosec // htmlCaption escapes the caption.
+		}
+	}
+	return result
+}
+
+// renderWebBookHTML renders index.html of the web book.
+func renderWebBookHTML(w io.Writer, data WebBookData) error {
+	tmpl, err := template.ParseFS(webBookFS, "templates/webbook.html")
+	if err != nil {
+		return fmt.Errorf("failed to parse web book template: %w", err)
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render web book: %w", err)
+	}
+	return nil
+}
+
+// writeWebBookArchive writes index.html, style.css, and the images stored
+// under dir to w as a ZIP archive. Images are stored uncompressed as JPEGs
+// do not compress further.
+func writeWebBookArchive(w io.Writer, data WebBookData, images map[string]webImage, dir string) error {
+	zw := zip.NewWriter(w)
+
+	index, err := zw.Create("index.html")
+	if err != nil {
+		return fmt.Errorf("failed to add index.html: %w", err)
+	}
+	if err := renderWebBookHTML(index, data); err != nil {
+		return err
+	}
+
+	css, err := webBookFS.ReadFile("templates/webbook.css")
+	if err != nil {
+		return fmt.Errorf("failed to read web book stylesheet: %w", err)
+	}
+	style, err := zw.Create("style.css")
+	if err != nil {
+		return fmt.Errorf("failed to add style.css: %w", err)
+	}
+	if _, err := style.Write(css); err != nil {
+		return fmt.Errorf("failed to write style.css: %w", err)
+	}
+
+	for _, img := range images {
+		for _, rel := range []string{img.small, img.large} {
+			if err := addStoredFile(zw, rel, filepath.Join(dir, rel)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finish web book archive: %w", err)
+	}
+	return nil
+}
+
+// addStoredFile copies the file at src into the archive as name, uncompressed.
+func addStoredFile(zw *zip.Writer, name, src string) error {
+	f, err := os.Open(src) //nolint:gosec // src is a file this export wrote to its temp dir.
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer f.Close()
+	dst, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	if _, err := io.Copy(dst, f); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
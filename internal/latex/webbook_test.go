@@ -0,0 +1,195 @@
+package latex
+
+import (
+	"archive/zip"
+	"bytes"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+func webBookTestGroups() []sectionGroup {
+	return []sectionGroup{
+		{
+			sectionID: "s1", title: "Zima", chapterID: "c1", chapterTitle: "Rok 1950", chapterColor: "8B0000",
+			pages: []database.BookPage{{
+				ID: "p1", SectionID: "s1", Format: Format2Portrait,
+				Slots: []database.PageSlot{
+					{SlotIndex: 0, PhotoUID: "pa", CropX: 0.5, CropY: 0.5, CropScale: 0.5},
+					{SlotIndex: 1, PhotoUID: "pb", CropX: 0.5, CropY: 0.5, CropScale: 1},
+				},
+			}},
+		},
+		{
+			sectionID: "s2", title: "Jaro", chapterID: "c1", chapterTitle: "Rok 1950", chapterColor: "8B0000",
+			pages: []database.BookPage{
+				{ID: "p2", SectionID: "s2", Format: Format2Portrait, Slots: []database.PageSlot{
+					{SlotIndex: 0, TextContent: "# Jaro\n\n**Text**"},
+					{SlotIndex: 1, IsContentsSlot: true},
+				}},
+				{ID: "p3", SectionID: "s2", Format: FormatFullbleed, Slots: []database.PageSlot{
+					{SlotIndex: 0, PhotoUID: "pa", CropX: 0.2, CropY: 0.8, CropScale: 1},
+				}},
+			},
+		},
+	}
+}
+
+func webBookTestImages() map[string]webImage {
+	return map[string]webImage{
+		"pa": {small: "images/pa-720.jpg", large: "images/pa-1920.jpg", width: 1920, height: 1280},
+		"pb": {small: "images/pb-720.jpg", large: "images/pb-1920.jpg", width: 1280, height: 1920},
+	}
+}
+
+func TestBuildWebBookData(t *testing.T) {
+	captions := CaptionMap{"s1": {"pa": "Dům", "pb": "Zahrada"}}
+	book := &database.PhotoBook{Title: "Kniha", BodyFont: "pt-serif", HeadingFont: "unknown"}
+	data := buildWebBookData(book, webBookTestGroups(), webBookTestImages(), captions, DefaultLayoutConfig())
+
+	if data.Title != "Kniha" || len(data.Sections) != 2 {
+		t.Fatalf("unexpected data: %+v", data)
+	}
+	if !strings.Contains(string(data.RootStyle), `--body-font: "PT Serif", serif`) ||
+		!strings.Contains(string(data.RootStyle), "--page-aspect: 265.00 / 172.00") {
+		t.Errorf("RootStyle = %q", data.RootStyle)
+	}
+	if data.Sections[0].ChapterTitle != "Rok 1950" || data.Sections[1].ChapterTitle != "" {
+		t.Errorf("chapter titles = %q, %q, want only the first set",
+			data.Sections[0].ChapterTitle, data.Sections[1].ChapterTitle)
+	}
+	if !strings.Contains(string(data.Sections[0].Style), "--chapter-color: #8B0000; --chapter-text: white") {
+		t.Errorf("section style = %q", data.Sections[0].Style)
+	}
+
+	// Page numbers and TOC ranges follow the PDF numbering.
+	if len(data.TOC) != 1 || data.TOC[0].Sections[1].StartPage != 2 || data.TOC[0].Sections[1].EndPage != 3 {
+		t.Errorf("TOC = %+v", data.TOC)
+	}
+	if got := data.Sections[1].Pages[1].Number; got != 3 {
+		t.Errorf("last page number = %d, want 3", got)
+	}
+
+	page := data.Sections[0].Pages[0]
+	if len(page.Slots) != 2 || len(page.Captions) != 2 {
+		t.Fatalf("page 1 = %+v", page)
+	}
+	photo := page.Slots[0].Photo
+	if photo == nil || photo.Src != "images/pa-1920.jpg" || photo.Marker != 1 || photo.Alt != "Dům" {
+		t.Fatalf("photo = %+v", photo)
+	}
+	if photo.SrcSet != "images/pa-720.jpg 720w, images/pa-1920.jpg 1920w" {
+		t.Errorf("SrcSet = %q", photo.SrcSet)
+	}
+	if !strings.Contains(string(photo.Style), "transform: scale(2.000)") {
+		t.Errorf("zoomed photo style = %q", photo.Style)
+	}
+	if strings.Contains(string(page.Slots[1].Photo.Style), "transform") {
+		t.Errorf("unzoomed photo style = %q", page.Slots[1].Photo.Style)
+	}
+	if !strings.HasPrefix(string(page.Slots[0].Style), "left: 0.000%; top: 0.000%;") {
+		t.Errorf("slot style = %q", page.Slots[0].Style)
+	}
+
+	text := data.Sections[1].Pages[0]
+	if !strings.Contains(string(text.Slots[0].Text), "<strong>Text</strong>") || !text.Slots[1].Contents {
+		t.Errorf("text page slots = %+v", text.Slots)
+	}
+
+	bleed := data.Sections[1].Pages[1]
+	wantBleed := template.CSS("left: 0.000%; top: 0.000%; width: 100.000%; height: 100.000%")
+	if !bleed.FullBleed || len(bleed.Slots) != 1 || bleed.Slots[0].Style != wantBleed {
+		t.Errorf("full-bleed page = %+v", bleed)
+	}
+}
+
+func TestBuildWebBookData_CaptionsSlot(t *testing.T) {
+	groups := []sectionGroup{{
+		sectionID: "s1",
+		pages: []database.BookPage{{
+			SectionID: "s1", Format: Format2Portrait,
+			Slots: []database.PageSlot{{SlotIndex: 0, PhotoUID: "pa"}, {SlotIndex: 1, IsCaptionsSlot: true}},
+		}},
+	}}
+	captions := CaptionMap{"s1": {"pa": "Dům"}}
+	data := buildWebBookData(nil, groups, webBookTestImages(), captions, DefaultLayoutConfig())
+
+	page := data.Sections[0].Pages[0]
+	if page.Captions != nil || len(page.Slots[1].Captions) != 1 || page.Slots[1].Captions[0].Text != "Dům" {
+		t.Errorf("captions not routed into the captions slot: %+v", page)
+	}
+	if page.Slots[0].Photo.Marker != 0 {
+		t.Errorf("single photo got marker %d, want none", page.Slots[0].Photo.Marker)
+	}
+}
+
+func TestWebChapterStyle_RejectsInvalidColor(t *testing.T) {
+	for _, color := range []string{"", "red", "8B0000; background: url(x)"} {
+		if got := webChapterStyle(color); got != "" {
+			t.Errorf("webChapterStyle(%q) = %q, want empty", color, got)
+		}
+	}
+}
+
+func TestWriteWebBookArchive(t *testing.T) {
+	dir := t.TempDir()
+	images := webBookTestImages()
+	if err := os.MkdirAll(filepath.Join(dir, webImageDir), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	for _, img := range images {
+		for _, rel := range []string{img.small, img.large} {
+			if err := os.WriteFile(filepath.Join(dir, rel), []byte(rel), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	captions := CaptionMap{"s1": {"pa": "Dům <b>"}}
+	book := &database.PhotoBook{Title: "Kniha & spol."}
+	data := buildWebBookData(book, webBookTestGroups(), images, captions, DefaultLayoutConfig())
+
+	var buf bytes.Buffer
+	if err := writeWebBookArchive(&buf, data, images, dir); err != nil {
+		t.Fatalf("writeWebBookArchive: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader: %v", err)
+	}
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		files[f.Name] = string(body)
+	}
+
+	for _, name := range []string{"index.html", "style.css", "images/pa-720.jpg", "images/pb-1920.jpg"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("archive is missing %s", name)
+		}
+	}
+	index := files["index.html"]
+	for _, want := range []string{
+		"<title>Kniha &amp; spol.</title>",
+		`id="page-3"`,
+		`<a href="#page-2"><span class="toc-title">Jaro</span> <span class="toc-pages">2–3</span></a>`,
+		`srcset="images/pa-720.jpg 720w, images/pa-1920.jpg 1920w"`,
+		`style="--chapter-color: #8B0000; --chapter-text: white"`,
+		"Dům &lt;b&gt;",
+		"<h3>Jaro</h3>",
+	} {
+		if !strings.Contains(index, want) {
+			t.Errorf("index.html does not contain %q", want)
+		}
+	}
+}
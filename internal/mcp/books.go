@@ -96,6 +96,9 @@ type bookDetailResult struct {
 	ID          string              `json:"id"`
 	Title       string              `json:"title"`
 	Description string              `json:"description"`
+	PageSize    string              `json:"page_size"`
+	Orientation string              `json:"orientation"`
+	BleedMM     float64             `json:"bleed_mm"`
 	Chapters    []chapterDetailItem `json:"chapters"`
 	Sections    []sectionDetailItem `json:"sections"`
 	Pages       []pageDetailItem    `json:"pages"`
@@ -127,6 +130,7 @@ type pageDetailItem struct {
 	SplitPosition  *float64         `json:"split_position,omitempty"`
 	HidePageNumber bool             `json:"hide_page_number,omitempty"`
 	SortOrder      int              `json:"sort_order"`
+	SlotCount      int              `json:"slot_count"`
 	Slots          []slotDetailItem `json:"slots"`
 }
 
@@ -158,6 +162,7 @@ func convertPages(pages []database.BookPage) []pageDetailItem {
 			SplitPosition:  p.SplitPosition,
 			HidePageNumber: p.HidePageNumber,
 			SortOrder:      p.SortOrder,
+			SlotCount:      p.SlotCount(),
 			Slots:          slots,
 		}
 	}
@@ -206,6 +211,7 @@ func (s *Server) buildBookDetail(bookID string) (*bookDetailResult, error) {
 
 	return &bookDetailResult{
 		ID: book.ID, Title: book.Title, Description: book.Description,
+		PageSize: book.PageSize, Orientation: book.Orientation, BleedMM: book.BleedMM,
 		Chapters:  chapterItems,
 		Sections:  sectionItems,
 		Pages:     convertPages(pages),
@@ -306,6 +312,31 @@ func applyBookTypography(
 	return ""
 }
 
+// applyBookPageSetup applies optional page size, orientation and bleed
+// updates to a book. Returns an error message if validation fails, or empty
+// string on success. Mirrors the web handler's applyPageSetup.
+func applyBookPageSetup(book *database.PhotoBook, args map[string]any) string {
+	if size := optionalStr(args, "page_size"); size != "" {
+		if !latex.ValidatePageSize(size) {
+			return fmt.Sprintf("invalid page_size: %q", size)
+		}
+		book.PageSize = size
+	}
+	if o := optionalStr(args, "orientation"); o != "" {
+		if !latex.ValidateOrientation(o) {
+			return fmt.Sprintf("invalid orientation: %q", o)
+		}
+		book.Orientation = o
+	}
+	if v, ok := optionalFloat(args, "bleed_mm"); ok {
+		if v < 0 || v > latex.MaxBleedMM {
+			return fmt.Sprintf("bleed_mm must be between 0 and %g", latex.MaxBleedMM)
+		}
+		book.BleedMM = v
+	}
+	return ""
+}
+
 func (s *Server) handleUpdateBook(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := req.GetArguments()
 	bookID, err := requiredStr(args, "book_id")
@@ -330,6 +361,9 @@ func (s *Server) handleUpdateBook(ctx context.Context, req mcp.CallToolRequest)
 	if errMsg := applyBookTypography(book, args); errMsg != "" {
 		return mcp.NewToolResultError(errMsg), nil
 	}
+	if errMsg := applyBookPageSetup(book, args); errMsg != "" {
+		return mcp.NewToolResultError(errMsg), nil
+	}
 
 	if err := s.bookWriter.UpdateBook(s.ctx(), book); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to update book: %v", err)), nil
@@ -337,7 +371,7 @@ func (s *Server) handleUpdateBook(ctx context.Context, req mcp.CallToolRequest)
 	return jsonResult(buildUpdateBookResponse(book))
 }
 
-// updateBookResponse mirrors the typography payload accepted by `update_book`
+// updateBookResponse mirrors the typography and page setup payload accepted by `update_book`
 // so MCP clients see the resolved values after validation/clamping.
 type updateBookResponse struct {
 	ID                string  `json:"id"`
@@ -354,6 +388,9 @@ type updateBookResponse struct {
 	HeadingColorBleed float64 `json:"heading_color_bleed"`
 	CaptionBadgeSize  float64 `json:"caption_badge_size"`
 	BodyTextPadMM     float64 `json:"body_text_pad_mm"`
+	PageSize          string  `json:"page_size"`
+	Orientation       string  `json:"orientation"`
+	BleedMM           float64 `json:"bleed_mm"`
 	UpdatedAt         string  `json:"updated_at"`
 }
 
@@ -373,6 +410,9 @@ func buildUpdateBookResponse(book *database.PhotoBook) updateBookResponse {
 		HeadingColorBleed: book.HeadingColorBleed,
 		CaptionBadgeSize:  book.CaptionBadgeSize,
 		BodyTextPadMM:     book.BodyTextPadMM,
+		PageSize:          book.PageSize,
+		Orientation:       book.Orientation,
+		BleedMM:           book.BleedMM,
 		UpdatedAt:         book.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}
 }
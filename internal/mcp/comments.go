@@ -0,0 +1,160 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerBookCommentTools registers review comment tools.
+func (s *Server) registerBookCommentTools() {
+	s.mcpServer.AddTool(
+		mcp.NewTool("list_book_comments",
+			mcp.WithDescription("List review comments of a book, oldest first"),
+			mcp.WithString("book_id", mcp.Required(), mcp.Description("Book ID")),
+			mcp.WithBoolean("unresolved_only", mcp.Description("Only list unresolved comments (default false)")),
+		),
+		s.handleListBookComments,
+	)
+
+	s.mcpServer.AddTool(
+		mcp.NewTool("add_book_comment",
+			mcp.WithDescription("Add a review comment to a book, a page, a page slot or a photo in a section"),
+			mcp.WithString("book_id", mcp.Required(), mcp.Description("Book ID")),
+			mcp.WithString("target_type", mcp.Required(),
+				mcp.Description("What the comment is about: book, page, slot or section_photo")),
+			mcp.WithString("page_id", mcp.Description("Page ID (page and slot comments)")),
+			mcp.WithNumber("slot_index", mcp.Description("0-based slot index (slot comments)")),
+			mcp.WithString("section_id", mcp.Description("Section ID (section_photo comments)")),
+			mcp.WithString("photo_uid", mcp.Description("Photo UID in the section (section_photo comments)")),
+			mcp.WithString("body", mcp.Required(), mcp.Description("Comment text")),
+		),
+		s.handleAddBookComment,
+	)
+
+	s.mcpServer.AddTool(
+		mcp.NewTool("resolve_book_comment",
+			mcp.WithDescription("Mark a review comment resolved, or reopen it"),
+			mcp.WithString("comment_id", mcp.Required(), mcp.Description("Comment ID")),
+			mcp.WithBoolean("resolved", mcp.Description("false reopens the comment (default true)")),
+		),
+		s.handleResolveBookComment,
+	)
+}
+
+type commentItem struct {
+	ID         string `json:"id"`
+	TargetType string `json:"target_type"`
+	PageID     string `json:"page_id,omitempty"`
+	SlotIndex  *int   `json:"slot_index,omitempty"`
+	SectionID  string `json:"section_id,omitempty"`
+	PhotoUID   string `json:"photo_uid,omitempty"`
+	Body       string `json:"body"`
+	AuthorUID  string `json:"author_uid,omitempty"`
+	Resolved   bool   `json:"resolved"`
+	ResolvedBy string `json:"resolved_by,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
+func convertBookComment(c *database.BookComment) commentItem {
+	item := commentItem{
+		ID: c.ID, TargetType: c.TargetType, PageID: c.PageID,
+		SectionID: c.SectionID, PhotoUID: c.PhotoUID, Body: c.Body, AuthorUID: c.AuthorUID,
+		Resolved: c.IsResolved(), ResolvedBy: c.ResolvedBy,
+		CreatedAt: c.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if c.TargetType == database.CommentTargetSlot {
+		slotIndex := c.SlotIndex
+		item.SlotIndex = &slotIndex
+	}
+	return item
+}
+
+// --- Book comment handlers ---
+
+func (s *Server) handleListBookComments(
+	_ context.Context, req mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := req.GetArguments()
+	bookID, err := requiredStr(args, "book_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	unresolvedOnly, _ := optionalBool(args, "unresolved_only")
+	comments, err := s.bookWriter.ListBookComments(s.ctx(), bookID, unresolvedOnly)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list comments: %v", err)), nil
+	}
+	result := make([]commentItem, len(comments))
+	for i := range comments {
+		result[i] = convertBookComment(&comments[i])
+	}
+	return jsonResult(result)
+}
+
+// handleAddBookComment adds a comment without an author: MCP clients have
+// no PhotoPrism session.
+func (s *Server) handleAddBookComment(
+	_ context.Context, req mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := req.GetArguments()
+	bookID, err := requiredStr(args, "book_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	targetType, err := requiredStr(args, "target_type")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	body, err := requiredStr(args, "body")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	comment := &database.BookComment{
+		BookID: bookID, TargetType: targetType, PageID: optionalStr(args, "page_id"),
+		SlotIndex: optionalInt(args, "slot_index", 0), SectionID: optionalStr(args, "section_id"),
+		PhotoUID: optionalStr(args, "photo_uid"), Body: strings.TrimSpace(body),
+	}
+	if err := comment.Validate(); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid comment: %v", err)), nil
+	}
+	book, err := s.bookWriter.GetBook(s.ctx(), bookID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get book: %v", err)), nil
+	}
+	if book == nil {
+		return mcp.NewToolResultError(fmt.Sprintf("book %s not found", bookID)), nil
+	}
+	if err := database.CheckBookCommentTarget(s.ctx(), s.bookWriter, comment); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := s.bookWriter.CreateBookComment(s.ctx(), comment); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to create comment: %v", err)), nil
+	}
+	return jsonResult(convertBookComment(comment))
+}
+
+func (s *Server) handleResolveBookComment(
+	_ context.Context, req mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := req.GetArguments()
+	id, err := requiredStr(args, "comment_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	resolved, ok := optionalBool(args, "resolved")
+	if !ok {
+		resolved = true
+	}
+	if err := s.bookWriter.SetBookCommentResolved(s.ctx(), id, resolved, ""); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to update comment: %v", err)), nil
+	}
+	comment, err := s.bookWriter.GetBookComment(s.ctx(), id)
+	if err != nil || comment == nil {
+		return mcp.NewToolResultError("failed to get comment"), nil
+	}
+	return jsonResult(convertBookComment(comment))
+}
@@ -49,6 +49,16 @@ func (s *Server) registerLabelTools() {
 		s.handleDeleteLabels,
 	)
 
+	s.mcpServer.AddTool(
+		mcp.NewTool("merge_labels",
+			mcp.WithDescription("Move all photos from the source labels to the target label, then delete the source labels"),
+			mcp.WithArray("from_uids", mcp.Required(), mcp.Description("UIDs of the labels to merge")),
+			mcp.WithString("into_uid", mcp.Required(), mcp.Description("UID of the label to merge into")),
+			mcp.WithBoolean("dry_run", mcp.Description("Only list the affected photos (default false)")),
+		),
+		s.handleMergeLabels,
+	)
+
 	s.mcpServer.AddTool(
 		mcp.NewTool("add_photo_label",
 			mcp.WithDescription("Add a label to a photo"),
@@ -213,6 +223,40 @@ func (s *Server) handleDeleteLabels(
 	})
 }
 
+func (s *Server) handleMergeLabels(
+	_ context.Context, req mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := req.GetArguments()
+	fromUIDs, err := requiredStrArray(args, "from_uids")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	intoUID, err := requiredStr(args, "into_uid")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	dryRun, _ := optionalBool(args, "dry_run")
+
+	result, err := s.pp.MergeLabels(fromUIDs, intoUID, photoprism.MergeLabelsOptions{DryRun: dryRun})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to merge labels: %v", err)), nil
+	}
+
+	from := make([]string, len(result.From))
+	for i, l := range result.From {
+		from[i] = l.Name
+	}
+	return jsonResult(map[string]any{
+		"into":           result.Into.Name,
+		"from":           from,
+		"photo_count":    len(result.PhotoUIDs),
+		"updated":        result.UpdatedCount,
+		"labels_deleted": result.Deleted,
+		"dry_run":        dryRun,
+		"errors":         result.Errors,
+	})
+}
+
 func (s *Server) handleAddPhotoLabel(
 	_ context.Context, req mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
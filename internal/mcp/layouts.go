@@ -0,0 +1,199 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const layoutCellsDesc = "Slots in order, each an object {col, col_span, row, row_span}: " +
+	"col 0-11 and col_span on the 12-column grid, row and row_span on the rows; cells must not overlap"
+
+// registerPageLayoutTools registers page layout CRUD tools.
+func (s *Server) registerPageLayoutTools() {
+	s.mcpServer.AddTool(
+		mcp.NewTool("list_page_layouts",
+			mcp.WithDescription("List user-defined page layouts usable as page formats"),
+		),
+		s.handleListPageLayouts,
+	)
+
+	s.mcpServer.AddTool(
+		mcp.NewTool("create_page_layout",
+			mcp.WithDescription("Create a page layout: slots placed on the 12-column page grid. "+
+				"Pages use it by setting their format to the layout ID"),
+			mcp.WithString("id", mcp.Required(),
+				mcp.Description("Layout ID used as page format: 1-20 lowercase letters, digits or underscores")),
+			mcp.WithString("name", mcp.Required(), mcp.Description("Layout name")),
+			mcp.WithString("description", mcp.Description("Layout description")),
+			mcp.WithNumber("rows", mcp.Required(),
+				mcp.Description("Number of equal rows the canvas height is split into (1-6)")),
+			mcp.WithArray("cells", mcp.Required(), mcp.Description(layoutCellsDesc)),
+		),
+		s.handleCreatePageLayout,
+	)
+
+	s.mcpServer.AddTool(
+		mcp.NewTool("update_page_layout",
+			mcp.WithDescription("Replace the name, description and grid of a page layout. "+
+				"The number of slots cannot change while pages use the layout"),
+			mcp.WithString("id", mcp.Required(), mcp.Description("Layout ID")),
+			mcp.WithString("name", mcp.Required(), mcp.Description("Layout name")),
+			mcp.WithString("description", mcp.Description("Layout description")),
+			mcp.WithNumber("rows", mcp.Required(),
+				mcp.Description("Number of equal rows the canvas height is split into (1-6)")),
+			mcp.WithArray("cells", mcp.Required(), mcp.Description(layoutCellsDesc)),
+		),
+		s.handleUpdatePageLayout,
+	)
+
+	s.mcpServer.AddTool(
+		mcp.NewTool("delete_page_layout",
+			mcp.WithDescription("Delete a page layout that no page uses"),
+			mcp.WithString("id", mcp.Required(), mcp.Description("Layout ID")),
+		),
+		s.handleDeletePageLayout,
+	)
+}
+
+type layoutCellItem struct {
+	Col     int `json:"col"`
+	ColSpan int `json:"col_span"`
+	Row     int `json:"row"`
+	RowSpan int `json:"row_span"`
+}
+
+type layoutItem struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Rows        int              `json:"rows"`
+	SlotCount   int              `json:"slot_count"`
+	Cells       []layoutCellItem `json:"cells"`
+}
+
+func convertPageLayout(l *database.PageLayout) layoutItem {
+	cells := make([]layoutCellItem, len(l.Cells))
+	for i, c := range l.Cells {
+		cells[i] = layoutCellItem(c)
+	}
+	return layoutItem{
+		ID: l.ID, Name: l.Name, Description: l.Description,
+		Rows: l.Rows, SlotCount: l.SlotCount(), Cells: cells,
+	}
+}
+
+// parseLayoutCells parses the cells argument: an array of objects with
+// numeric col, col_span, row and row_span.
+func parseLayoutCells(args map[string]any) ([]database.PageLayoutCell, error) {
+	arr, ok := args["cells"].([]any)
+	if !ok {
+		return nil, errors.New("missing required parameter: cells")
+	}
+	cells := make([]database.PageLayoutCell, len(arr))
+	for i, v := range arr {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("cell %d must be an object", i)
+		}
+		var vals [4]int
+		for j, key := range []string{"col", "col_span", "row", "row_span"} {
+			n, err := requiredInt(obj, key)
+			if err != nil {
+				return nil, fmt.Errorf("cell %d: %w", i, err)
+			}
+			vals[j] = n
+		}
+		cells[i] = database.PageLayoutCell{Col: vals[0], ColSpan: vals[1], Row: vals[2], RowSpan: vals[3]}
+	}
+	return cells, nil
+}
+
+// parsePageLayout builds and validates a page layout from tool arguments.
+func parsePageLayout(args map[string]any) (*database.PageLayout, error) {
+	id, err := requiredStr(args, "id")
+	if err != nil {
+		return nil, err
+	}
+	name, err := requiredStr(args, "name")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := requiredInt(args, "rows")
+	if err != nil {
+		return nil, err
+	}
+	cells, err := parseLayoutCells(args)
+	if err != nil {
+		return nil, err
+	}
+	layout := &database.PageLayout{
+		ID: id, Name: name, Description: optionalStr(args, "description"),
+		Rows: rows, Cells: cells,
+	}
+	if err := layout.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid layout: %w", err)
+	}
+	return layout, nil
+}
+
+// --- Page layout handlers ---
+
+func (s *Server) handleListPageLayouts(
+	_ context.Context, _ mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	layouts, err := s.bookWriter.ListPageLayouts(s.ctx())
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list page layouts: %v", err)), nil
+	}
+	result := make([]layoutItem, len(layouts))
+	for i := range layouts {
+		result[i] = convertPageLayout(&layouts[i])
+	}
+	return jsonResult(result)
+}
+
+func (s *Server) handleCreatePageLayout(
+	_ context.Context, req mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	layout, err := parsePageLayout(req.GetArguments())
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := s.bookWriter.CreatePageLayout(s.ctx(), layout); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to create page layout: %v", err)), nil
+	}
+	return jsonResult(convertPageLayout(layout))
+}
+
+func (s *Server) handleUpdatePageLayout(
+	_ context.Context, req mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	layout, err := parsePageLayout(req.GetArguments())
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := s.bookWriter.UpdatePageLayout(s.ctx(), layout); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to update page layout: %v", err)), nil
+	}
+	return jsonResult(convertPageLayout(layout))
+}
+
+func (s *Server) handleDeletePageLayout(
+	_ context.Context, req mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	id, err := requiredStr(req.GetArguments(), "id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := s.bookWriter.DeletePageLayout(s.ctx(), id); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to delete page layout: %v", err)), nil
+	}
+	return jsonResult(map[string]any{
+		"success": true,
+		"id":      id,
+	})
+}
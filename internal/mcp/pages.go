@@ -70,16 +70,35 @@ const (
 	createPageDesc = "Create a page in a book. " +
 		"Format determines slot count: " +
 		"4_landscape=4, 2l_1p=3, 1p_2l=3, 2_portrait=2, " +
-		"1_fullscreen=1, 1_fullbleed=1"
+		"1_fullscreen=1, 1_fullbleed=1, " +
+		"or a page layout ID from list_page_layouts"
 	formatDesc = "Page format: 4_landscape (4 slots), " +
 		"2l_1p (3 slots), 1p_2l (3 slots), " +
 		"2_portrait (2 slots), 1_fullscreen (1 slot), " +
 		"1_fullbleed (1 slot, photo covers full page incl. 3mm bleed; " +
-		"folio and footer captions suppressed)"
+		"folio and footer captions suppressed), " +
+		"or a page layout ID from list_page_layouts"
 	invalidFormatMsg = "invalid format %q — valid: " +
-		"4_landscape, 2l_1p, 1p_2l, 2_portrait, 1_fullscreen, 1_fullbleed"
+		"4_landscape, 2l_1p, 1p_2l, 2_portrait, 1_fullscreen, 1_fullbleed, " +
+		"or a page layout ID from list_page_layouts"
 )
 
+// resolvePageFormat returns the custom layout of a page format, or nil for a
+// built-in format. It returns an error message when the format is unknown.
+func (s *Server) resolvePageFormat(format string) (*database.PageLayout, string) {
+	if database.IsBuiltinPageFormat(format) {
+		return nil, ""
+	}
+	layout, err := s.bookWriter.GetPageLayout(s.ctx(), format)
+	if err != nil {
+		return nil, fmt.Sprintf("failed to get page layout: %v", err)
+	}
+	if layout == nil {
+		return nil, fmt.Sprintf(invalidFormatMsg, format)
+	}
+	return layout, ""
+}
+
 // registerPageTools registers page CRUD + reorder tools.
 func (s *Server) registerPageTools() {
 	s.mcpServer.AddTool(
@@ -104,7 +123,8 @@ func (s *Server) registerPageTools() {
 				mcp.Description("Page ID (UUID)")),
 			mcp.WithString("format",
 				mcp.Description("New format: 4_landscape, 2l_1p, "+
-					"1p_2l, 2_portrait, 1_fullscreen, 1_fullbleed")),
+					"1p_2l, 2_portrait, 1_fullscreen, 1_fullbleed, "+
+					"or a page layout ID")),
 			mcp.WithString("section_id",
 				mcp.Description("New section ID (UUID)")),
 			mcp.WithString("description",
@@ -272,15 +292,16 @@ func (s *Server) handleCreatePage(
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
-	if database.PageFormatSlotCount(format) == 0 {
-		return mcp.NewToolResultError(
-			fmt.Sprintf(invalidFormatMsg, format)), nil
+	layout, errMsg := s.resolvePageFormat(format)
+	if errMsg != "" {
+		return mcp.NewToolResultError(errMsg), nil
 	}
 
 	page := &database.BookPage{
 		BookID:    bookID,
 		SectionID: sectionID,
 		Format:    format,
+		Layout:    layout,
 	}
 	if err := s.bookWriter.CreatePage(s.ctx(), page); err != nil {
 		return mcp.NewToolResultError(
@@ -317,15 +338,19 @@ func applySplitPosition(
 // applyPageUpdates applies optional update fields to a page.
 // Returns an error message if validation fails. The caller is responsible
 // for handling section_id changes separately via MovePageToSection so the
-// move stays atomic and reconciles section photo pools.
+// move stays atomic and reconciles section photo pools, and for setting
+// page.Layout to the layout of a new custom format.
 func applyPageUpdates(
 	page *database.BookPage, args map[string]any,
 ) string {
 	if f := optionalStr(args, "format"); f != "" {
-		if database.PageFormatSlotCount(f) == 0 {
+		if page.Layout == nil && database.PageFormatSlotCount(f) == 0 {
 			return fmt.Sprintf(invalidFormatMsg, f)
 		}
 		page.Format = f
+		if page.Layout != nil {
+			page.SplitPosition = nil
+		}
 	}
 	if d, ok := args["description"]; ok {
 		page.Description, _ = d.(string)
@@ -352,12 +377,18 @@ func (s *Server) handleUpdatePage(
 	if errResult != nil {
 		return errResult, nil
 	}
-	oldSlotCount := database.PageFormatSlotCount(page.Format)
+	oldSlotCount := page.SlotCount()
 
 	page, errResult = s.maybeMovePage(pageID, page, args)
 	if errResult != nil {
 		return errResult, nil
 	}
+	if f := optionalStr(args, "format"); f != "" {
+		var errMsg string
+		if page.Layout, errMsg = s.resolvePageFormat(f); errMsg != "" {
+			return mcp.NewToolResultError(errMsg), nil
+		}
+	}
 
 	if errMsg := applyPageUpdates(page, args); errMsg != "" {
 		return mcp.NewToolResultError(errMsg), nil
@@ -368,7 +399,7 @@ func (s *Server) handleUpdatePage(
 	}
 
 	// Clear excess slots if format changed to fewer slots.
-	newSlotCount := database.PageFormatSlotCount(page.Format)
+	newSlotCount := page.SlotCount()
 	for i := newSlotCount; i < oldSlotCount; i++ {
 		_ = s.bookWriter.ClearSlot(s.ctx(), pageID, i)
 	}
@@ -500,7 +531,7 @@ func (s *Server) validateSlotIndex(
 	if page == nil {
 		return nil, fmt.Errorf("page %s not found", pageID)
 	}
-	maxSlots := database.PageFormatSlotCount(page.Format)
+	maxSlots := page.SlotCount()
 	if slotIndex < 0 || slotIndex >= maxSlots {
 		return nil, fmt.Errorf(
 			"slot_index %d out of range — format %q has %d slots (0-%d)",
@@ -665,7 +696,7 @@ func (s *Server) handleSwapSlots(
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
-	maxSlots := database.PageFormatSlotCount(page.Format)
+	maxSlots := page.SlotCount()
 	if slotB < 0 || slotB >= maxSlots {
 		return mcp.NewToolResultError(fmt.Sprintf(
 			"slot_b %d out of range — format %q has %d slots (0-%d)",
@@ -20,6 +20,7 @@ type Server struct {
 	textVersionStore database.TextVersionStore
 	textCheckStore   database.TextCheckStore
 	embeddingReader  database.EmbeddingReader
+	labelTaxonomy    database.LabelTaxonomyStore
 	pp               *photoprism.PhotoPrism
 	config           *config.Config
 	apiToken         string
@@ -33,6 +34,7 @@ func NewServer(
 	textVersionStore database.TextVersionStore,
 	textCheckStore database.TextCheckStore,
 	embeddingReader database.EmbeddingReader,
+	labelTaxonomy database.LabelTaxonomyStore,
 	pp *photoprism.PhotoPrism,
 	cfg *config.Config,
 	apiToken string,
@@ -43,6 +45,7 @@ func NewServer(
 		textVersionStore: textVersionStore,
 		textCheckStore:   textCheckStore,
 		embeddingReader:  embeddingReader,
+		labelTaxonomy:    labelTaxonomy,
 		pp:               pp,
 		config:           cfg,
 		apiToken:         apiToken,
@@ -64,11 +67,14 @@ func NewServer(
 	s.registerSectionTools()
 	s.registerSectionPhotoTools()
 	s.registerPageTools()
+	s.registerPageLayoutTools()
+	s.registerBookCommentTools()
 	s.registerSlotTools()
 	s.registerTextTools()
 	s.registerPhotoTools()
 	s.registerAlbumTools()
 	s.registerLabelTools()
+	s.registerTaxonomyTools()
 
 	return s
 }
@@ -146,7 +152,7 @@ func (s *Server) registerUpdateBookTool() {
 	s.mcpServer.AddTool(
 		mcp.NewTool("update_book",
 			mcp.WithDescription(
-				"Update book title, description, or typography settings"),
+				"Update book title, description, typography or page setup"),
 			mcp.WithString("book_id", mcp.Required(),
 				mcp.Description("Book ID (UUID)")),
 			mcp.WithString("title", mcp.Description("New title")),
@@ -173,6 +179,12 @@ func (s *Server) registerUpdateBookTool() {
 				mcp.Description("Caption badge size in mm (2-12)")),
 			mcp.WithNumber("body_text_pad_mm",
 				mcp.Description("Inner padding (mm) added to body text on the side adjacent to a photo in mixed layouts (0-10)")),
+			mcp.WithString("page_size",
+				mcp.Description("Trimmed page size: a4, a5, a3, square_20 (20x20 cm) or square_30 (30x30 cm)")),
+			mcp.WithString("orientation",
+				mcp.Description("Page orientation: landscape or portrait (ignored for square sizes)")),
+			mcp.WithNumber("bleed_mm",
+				mcp.Description("Print bleed on every side of the page in mm (0-10)")),
 		),
 		s.handleUpdateBook,
 	)
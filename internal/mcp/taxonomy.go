@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kozaktomas/photo-sorter/internal/ai"
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/taxonomy"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerTaxonomyTools registers label taxonomy tools.
+func (s *Server) registerTaxonomyTools() {
+	s.mcpServer.AddTool(
+		mcp.NewTool("list_label_taxonomy",
+			mcp.WithDescription("List the label taxonomy: canonical label names with aliases and parents"),
+		),
+		s.handleListLabelTaxonomy,
+	)
+
+	s.mcpServer.AddTool(
+		mcp.NewTool("set_label_term",
+			mcp.WithDescription("Add or replace a canonical label in the taxonomy"),
+			mcp.WithString("name", mcp.Required(), mcp.Description("Canonical label name")),
+			mcp.WithString("parent", mcp.Description("Canonical name of the parent label")),
+			mcp.WithArray("aliases", mcp.Description("Synonyms and translations that map to this label")),
+			mcp.WithString("description", mcp.Description("Description of the label")),
+		),
+		s.handleSetLabelTerm,
+	)
+
+	s.mcpServer.AddTool(
+		mcp.NewTool("delete_label_term",
+			mcp.WithDescription("Remove a canonical label from the taxonomy; its children lose their parent"),
+			mcp.WithString("name", mcp.Required(), mcp.Description("Canonical label name")),
+		),
+		s.handleDeleteLabelTerm,
+	)
+
+	s.mcpServer.AddTool(
+		mcp.NewTool("normalize_labels",
+			mcp.WithDescription("Map label names through the taxonomy as the sorter does before writing to PhotoPrism"),
+			mcp.WithArray("labels", mcp.Required(), mcp.Description("Label names to normalize")),
+		),
+		s.handleNormalizeLabels,
+	)
+}
+
+func optionalStrArray(args map[string]any, key string) ([]string, error) {
+	if _, ok := args[key]; !ok {
+		return nil, nil
+	}
+	if arr, ok := args[key].([]any); ok && len(arr) == 0 {
+		return nil, nil
+	}
+	return requiredStrArray(args, key)
+}
+
+// --- Taxonomy handlers ---
+
+func (s *Server) handleListLabelTaxonomy(
+	_ context.Context, _ mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	terms, err := s.labelTaxonomy.ListLabelTerms(s.ctx())
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list label taxonomy: %v", err)), nil
+	}
+	type termItem struct {
+		Name        string   `json:"name"`
+		Parent      string   `json:"parent,omitempty"`
+		Aliases     []string `json:"aliases,omitempty"`
+		Description string   `json:"description,omitempty"`
+	}
+	result := make([]termItem, len(terms))
+	for i, t := range terms {
+		result[i] = termItem{Name: t.Name, Parent: t.Parent, Aliases: t.Aliases, Description: t.Description}
+	}
+	return jsonResult(result)
+}
+
+func (s *Server) handleSetLabelTerm(
+	_ context.Context, req mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := req.GetArguments()
+	name, err := requiredStr(args, "name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	aliases, err := optionalStrArray(args, "aliases")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	term := database.LabelTerm{
+		Name:        name,
+		Parent:      optionalStr(args, "parent"),
+		Aliases:     aliases,
+		Description: optionalStr(args, "description"),
+	}
+	if err := taxonomy.SaveTerms(s.ctx(), s.labelTaxonomy, term); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to save label term: %v", err)), nil
+	}
+	return jsonResult(map[string]any{
+		"success": true,
+		"name":    name,
+	})
+}
+
+func (s *Server) handleDeleteLabelTerm(
+	_ context.Context, req mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := req.GetArguments()
+	name, err := requiredStr(args, "name")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	deleted, err := s.labelTaxonomy.DeleteLabelTerm(s.ctx(), name)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to delete label term: %v", err)), nil
+	}
+	if !deleted {
+		return mcp.NewToolResultError(fmt.Sprintf("label %s is not in the taxonomy", name)), nil
+	}
+	return jsonResult(map[string]any{
+		"success": true,
+		"name":    name,
+	})
+}
+
+func (s *Server) handleNormalizeLabels(
+	_ context.Context, req mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	args := req.GetArguments()
+	names, err := requiredStrArray(args, "labels")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	tax, err := taxonomy.Load(s.ctx(), s.labelTaxonomy)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	labels := make([]ai.LabelWithConfidence, len(names))
+	for i, name := range names {
+		labels[i] = ai.LabelWithConfidence{Name: name, Confidence: 1}
+	}
+	normalized := tax.Normalize(labels)
+	result := make([]string, len(normalized))
+	for i, l := range normalized {
+		result[i] = l.Name
+	}
+	return jsonResult(map[string]any{
+		"labels": result,
+	})
+}
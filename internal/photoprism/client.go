@@ -0,0 +1,229 @@
+package photoprism
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Default retry settings for idempotent requests.
+const (
+	// DefaultMaxRetries is how often an idempotent request is retried after a
+	// transient failure.
+	DefaultMaxRetries = 3
+
+	// DefaultRetryDelay is the delay before the first retry; it doubles with
+	// each further attempt up to DefaultMaxRetryDelay.
+	DefaultRetryDelay = 250 * time.Millisecond
+
+	// DefaultMaxRetryDelay caps the exponential backoff.
+	DefaultMaxRetryDelay = 10 * time.Second
+)
+
+// errNoCredentials is returned when a token-based client would need to log in again.
+var errNoCredentials = errors.New("no credentials to log in with")
+
+// RetryPolicy configures how idempotent requests (GET, PUT, DELETE) are
+// retried after network errors and 429/502/503/504 responses.
+type RetryPolicy struct {
+	MaxRetries int           // Retries after the first attempt; 0 disables retrying
+	BaseDelay  time.Duration // Delay before the first retry, doubled per attempt
+	MaxDelay   time.Duration // Upper bound for the delay between attempts
+}
+
+// DefaultRetryPolicy returns the retry policy configured by the
+// PHOTOPRISM_MAX_RETRIES and PHOTOPRISM_RETRY_DELAY_MS env vars.
+func DefaultRetryPolicy() RetryPolicy {
+	policy := RetryPolicy{
+		MaxRetries: DefaultMaxRetries,
+		BaseDelay:  DefaultRetryDelay,
+		MaxDelay:   DefaultMaxRetryDelay,
+	}
+	if v := os.Getenv("PHOTOPRISM_MAX_RETRIES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			policy.MaxRetries = n
+		}
+	}
+	if v := os.Getenv("PHOTOPRISM_RETRY_DELAY_MS"); v != "" {
+		if ms, err := strconv.Atoi(v); err == nil && ms > 0 {
+			policy.BaseDelay = time.Duration(ms) * time.Millisecond
+		}
+	}
+	return policy
+}
+
+// backoff returns the delay before retry number n (starting at 0).
+func (p RetryPolicy) backoff(n int) time.Duration {
+	delay := p.BaseDelay
+	for range n {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return delay
+}
+
+// session holds the credentials and tokens of a client. It is shared by all
+// views created with WithContext, so a re-login by one worker is seen by all.
+type session struct {
+	mu            sync.RWMutex
+	token         string
+	downloadToken string
+	userUID       string
+
+	loginMu  sync.Mutex // serializes re-logins
+	username string
+	password string
+}
+
+// tokens returns the current access and download tokens.
+func (s *session) tokens() (string, string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.token, s.downloadToken
+}
+
+// accessToken returns the current access token.
+func (s *session) accessToken() string {
+	token, _ := s.tokens()
+	return token
+}
+
+// user returns the UID of the logged-in user.
+func (s *session) user() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.userUID
+}
+
+// set replaces the tokens and user UID.
+func (s *session) set(token, downloadToken, userUID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+	s.downloadToken = downloadToken
+	s.userUID = userUID
+}
+
+// WithContext returns a view of the client whose requests are bound to ctx:
+// cancelling ctx aborts in-flight requests, backoff waits and rate limiter
+// waits. The view shares the session, rate limiter and settings with pp, so
+// it is cheap to create per job or per request.
+//
+//	photos, err := pp.WithContext(ctx).GetAlbumPhotos(albumUID, 100, 0)
+func (pp *PhotoPrism) WithContext(ctx context.Context) *PhotoPrism {
+	view := *pp
+	view.ctx = ctx
+	return &view
+}
+
+// context returns the context requests of this client are bound to.
+func (pp *PhotoPrism) context() context.Context {
+	if pp.ctx == nil {
+		return context.Background()
+	}
+	return pp.ctx
+}
+
+// SetRetryPolicy replaces the retry policy of this client.
+func (pp *PhotoPrism) SetRetryPolicy(policy RetryPolicy) {
+	pp.retry = policy
+}
+
+// SetRateLimit limits all clients of this PhotoPrism instance to perSecond
+// requests per second with bursts of up to burst requests. A perSecond of 0
+// removes the limit.
+func (pp *PhotoPrism) SetRateLimit(perSecond float64, burst int) {
+	pp.limiter.configure(perSecond, burst)
+}
+
+// isRetryableStatus reports whether a response status indicates a transient failure.
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isIdempotent reports whether requests with method can safely be repeated.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// send performs the request built by newRequest. It waits for the rate
+// limiter, logs in again once when the server rejects an expired token, and
+// retries transient failures with exponential backoff if retry is set.
+// newRequest is called for every attempt so each one gets a fresh body and
+// the current tokens. The caller must close the response body.
+func (pp *PhotoPrism) send(
+	client *http.Client, retry bool, newRequest func(ctx context.Context) (*http.Request, error),
+) (*http.Response, error) {
+	ctx := pp.context()
+	reauthenticated := false
+	for retries := 0; ; {
+		if err := pp.limiter.wait(ctx); err != nil {
+			return nil, fmt.Errorf("request cancelled: %w", err)
+		}
+		token := pp.session.accessToken()
+		req, err := newRequest(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not create request: %w", err)
+		}
+
+		resp, err := client.Do(req)
+		canRetry := retry && retries < pp.retry.MaxRetries
+		switch {
+		case err != nil:
+			if ctx.Err() != nil || !canRetry {
+				return nil, fmt.Errorf("could not send request: %w", err)
+			}
+		case resp.StatusCode == http.StatusUnauthorized && !reauthenticated && pp.session.username != "":
+			resp.Body.Close()
+			reauthenticated = true
+			if err := pp.reauthenticate(ctx, token); err != nil {
+				return nil, fmt.Errorf("session expired and re-login failed: %w", err)
+			}
+			continue
+		case isRetryableStatus(resp.StatusCode) && canRetry:
+			resp.Body.Close()
+		default:
+			return resp, nil
+		}
+
+		timer := time.NewTimer(pp.retry.backoff(retries))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("request cancelled: %w", ctx.Err())
+		case <-timer.C:
+		}
+		retries++
+	}
+}
+
+// reauthenticate logs in again after the server rejected staleToken. When
+// several workers hit the expired token at once, only the first one logs in.
+func (pp *PhotoPrism) reauthenticate(ctx context.Context, staleToken string) error {
+	s := pp.session
+	s.loginMu.Lock()
+	defer s.loginMu.Unlock()
+	if s.accessToken() != staleToken {
+		return nil
+	}
+	if s.username == "" {
+		return errNoCredentials
+	}
+	return pp.login(ctx, s.username, s.password)
+}
@@ -0,0 +1,221 @@
+package photoprism
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newFastRetryClient(t *testing.T, serverURL string) *PhotoPrism {
+	t.Helper()
+	pp, err := NewPhotoPrism(serverURL, "test", "test")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	pp.SetRetryPolicy(RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond})
+	return pp
+}
+
+func TestSend_RetriesTransientFailures(t *testing.T) {
+	var requests atomic.Int32
+	server := setupMockServerWithHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/albums/{uid}": func(w http.ResponseWriter, r *http.Request) {
+			switch requests.Add(1) {
+			case 1:
+				http.Error(w, "bad gateway", http.StatusBadGateway)
+			case 2:
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			default:
+				w.Write([]byte(`{"UID":"a1","Title":"Album"}`))
+			}
+		},
+	})
+	defer server.Close()
+	pp := newFastRetryClient(t, server.URL)
+
+	album, err := pp.GetAlbum("a1")
+	if err != nil {
+		t.Fatalf("expected retries to recover, got %v", err)
+	}
+	if album.Title != "Album" || requests.Load() != 3 {
+		t.Errorf("expected album after 3 requests, got %+v after %d", album, requests.Load())
+	}
+}
+
+func TestSend_GivesUpAfterMaxRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := setupMockServerWithHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/albums/{uid}": func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		},
+	})
+	defer server.Close()
+	pp := newFastRetryClient(t, server.URL)
+
+	_, err := pp.GetAlbum("a1")
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("expected 503 error, got %v", err)
+	}
+	if requests.Load() != 4 {
+		t.Errorf("expected 4 attempts, got %d", requests.Load())
+	}
+}
+
+func TestSend_DoesNotRetryPost(t *testing.T) {
+	var requests atomic.Int32
+	server := setupMockServerWithHandlers(t, map[string]http.HandlerFunc{
+		"POST /api/v1/albums": func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		},
+	})
+	defer server.Close()
+	pp := newFastRetryClient(t, server.URL)
+
+	if _, err := pp.CreateAlbum("New"); err == nil {
+		t.Fatal("expected error")
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected a single attempt for POST, got %d", requests.Load())
+	}
+}
+
+func TestSend_ReauthenticatesExpiredToken(t *testing.T) {
+	var logins atomic.Int32
+	server := setupMockServerWithHandlers(t, map[string]http.HandlerFunc{
+		"POST /api/v1/sessions": func(w http.ResponseWriter, r *http.Request) {
+			n := logins.Add(1)
+			json.NewEncoder(w).Encode(map[string]any{"access_token": fmt.Sprintf("token-%d", n)})
+		},
+		"GET /api/v1/albums/{uid}": func(w http.ResponseWriter, r *http.Request) {
+			// The first token has expired.
+			if r.Header.Get("Authorization") != "Bearer token-2" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"UID":"a1"}`))
+		},
+	})
+	defer server.Close()
+	pp := newFastRetryClient(t, server.URL)
+
+	if _, err := pp.GetAlbum("a1"); err != nil {
+		t.Fatalf("expected re-login to recover, got %v", err)
+	}
+	if logins.Load() != 2 {
+		t.Errorf("expected 2 logins, got %d", logins.Load())
+	}
+	if pp.session.accessToken() != "token-2" {
+		t.Errorf("expected refreshed token, got %s", pp.session.accessToken())
+	}
+}
+
+func TestSend_TokenClientDoesNotReauthenticate(t *testing.T) {
+	var logins atomic.Int32
+	server := setupMockServerWithHandlers(t, map[string]http.HandlerFunc{
+		"POST /api/v1/sessions": func(w http.ResponseWriter, r *http.Request) {
+			logins.Add(1)
+		},
+		"GET /api/v1/albums/{uid}": func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		},
+	})
+	defer server.Close()
+
+	pp, err := NewPhotoPrismFromToken(server.URL, "expired", "dl", "user")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if _, err := pp.GetAlbum("a1"); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected 401 error, got %v", err)
+	}
+	if logins.Load() != 0 {
+		t.Errorf("expected no login attempts, got %d", logins.Load())
+	}
+}
+
+func TestWithContext_CancelAbortsRequest(t *testing.T) {
+	release := make(chan struct{})
+	server := setupMockServerWithHandlers(t, map[string]http.HandlerFunc{
+		"GET /api/v1/albums/{uid}": func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		},
+	})
+	defer server.Close()
+	defer close(release)
+	pp := newFastRetryClient(t, server.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := pp.WithContext(ctx).GetAlbum("a1")
+	if err == nil {
+		t.Fatal("expected error after cancellation")
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("request was not aborted by the context (took %v)", time.Since(start))
+	}
+
+	// The original client is not bound to the cancelled context.
+	if pp.context().Err() != nil {
+		t.Error("expected WithContext to leave the original client unchanged")
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	l := &rateLimiter{}
+	l.configure(100, 2)
+
+	start := time.Now()
+	for range 6 {
+		if err := l.wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// 2 requests are covered by the burst, the other 4 need 10ms each.
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("expected rate limiting to take about 40ms, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.wait(ctx); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
+
+func TestRateLimiter_SharedPerInstance(t *testing.T) {
+	server := setupMockServerWithHandlers(t, nil)
+	defer server.Close()
+
+	a, err := NewPhotoPrismFromToken(server.URL, "t", "", "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	b, err := NewPhotoPrismFromToken(server.URL, "t", "", "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if a.limiter != b.limiter || a.WithContext(context.Background()).limiter != a.limiter {
+		t.Error("expected clients of one instance to share the rate limiter")
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	for n, d := range want {
+		if got := p.backoff(n); got != d {
+			t.Errorf("backoff(%d) = %v, want %v", n, got, d)
+		}
+	}
+}
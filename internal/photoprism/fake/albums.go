@@ -0,0 +1,171 @@
+package fake
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+)
+
+// AddAlbum creates a manual album and returns its UID.
+func (s *Server) AddAlbum(title string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addAlbum(photoprism.Album{Title: title})
+}
+
+// addAlbum stores an album, assigning a UID if it has none. Existing albums
+// with the same UID are replaced but keep their photos. The caller must hold s.mu.
+func (s *Server) addAlbum(a photoprism.Album) string {
+	if a.UID == "" {
+		a.UID = s.newUID('a')
+	}
+	if a.Type == "" {
+		a.Type = "album"
+	}
+	if a.CreatedAt == "" {
+		a.CreatedAt = now()
+	}
+	if existing, ok := s.albums[a.UID]; ok {
+		existing.Album = a
+		return a.UID
+	}
+	s.albums[a.UID] = &album{Album: a}
+	return a.UID
+}
+
+// albumView returns the album with its current photo count. The caller must hold s.mu.
+func (s *Server) albumView(a *album) photoprism.Album {
+	view := a.Album
+	view.PhotoCount = 0
+	for _, uid := range a.photoUIDs {
+		if p, ok := s.photos[uid]; ok && p.DeletedAt == "" {
+			view.PhotoCount++
+		}
+	}
+	if view.Thumb == "" && len(a.photoUIDs) > 0 {
+		if p, ok := s.photos[a.photoUIDs[0]]; ok {
+			view.Thumb = p.Hash
+		}
+	}
+	return view
+}
+
+// add appends photos to the album, skipping photos already in it. The caller must hold s.mu.
+func (a *album) add(photoUIDs ...string) {
+	for _, uid := range photoUIDs {
+		if !slices.Contains(a.photoUIDs, uid) {
+			a.photoUIDs = append(a.photoUIDs, uid)
+		}
+	}
+}
+
+func (s *Server) handleListAlbums(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	albumType := q.Get("type")
+	search := strings.ToLower(q.Get("q"))
+
+	s.mu.Lock()
+	albums := make([]photoprism.Album, 0, len(s.albums))
+	for _, a := range s.albums {
+		if albumType != "" && a.Type != albumType {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(a.Title), search) {
+			continue
+		}
+		albums = append(albums, s.albumView(a))
+	}
+	s.mu.Unlock()
+
+	slices.SortFunc(albums, func(a, b photoprism.Album) int {
+		if q.Get("order") == "newest" {
+			return strings.Compare(b.CreatedAt, a.CreatedAt)
+		}
+		return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	})
+	writeJSON(w, http.StatusOK, paginate(albums, q.Get("count"), q.Get("offset")))
+}
+
+func (s *Server) handleCreateAlbum(w http.ResponseWriter, r *http.Request) {
+	var input photoprism.Album
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || strings.TrimSpace(input.Title) == "" {
+		writeError(w, http.StatusBadRequest, "album title required")
+		return
+	}
+	s.mu.Lock()
+	uid := s.addAlbum(photoprism.Album{Title: input.Title, Description: input.Description})
+	view := s.albumView(s.albums[uid])
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, view)
+}
+
+func (s *Server) handleGetAlbum(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.albums[r.PathValue("uid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Album not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.albumView(a))
+}
+
+func (s *Server) handleAddToAlbum(w http.ResponseWriter, r *http.Request) {
+	uids, err := decodeSelection(r, "photos")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.albums[r.PathValue("uid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Album not found")
+		return
+	}
+	for _, uid := range uids {
+		if _, ok := s.photos[uid]; ok {
+			a.add(uid)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"code": http.StatusOK, "album": s.albumView(a)})
+}
+
+func (s *Server) handleRemoveFromAlbum(w http.ResponseWriter, r *http.Request) {
+	uids, err := decodeSelection(r, "photos")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.albums[r.PathValue("uid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Album not found")
+		return
+	}
+	a.photoUIDs = slices.DeleteFunc(a.photoUIDs, func(uid string) bool {
+		return slices.Contains(uids, uid)
+	})
+	writeJSON(w, http.StatusOK, map[string]any{"code": http.StatusOK, "album": s.albumView(a)})
+}
+
+// paginate applies the count and offset query parameters. A missing count
+// returns everything after offset. The result is never nil, so it encodes
+// as [] like PhotoPrism's responses.
+func paginate[T any](items []T, countParam, offsetParam string) []T {
+	offset, _ := strconv.Atoi(offsetParam)
+	offset = min(max(offset, 0), len(items))
+	items = items[offset:]
+	if count, err := strconv.Atoi(countParam); err == nil && count >= 0 && count < len(items) {
+		items = items[:count]
+	}
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
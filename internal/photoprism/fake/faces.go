@@ -0,0 +1,213 @@
+package fake
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+)
+
+// subjectByName returns the subject with the given name, creating it if
+// needed. The caller must hold s.mu.
+func (s *Server) subjectByName(name string) *photoprism.Subject {
+	slug := slugify(name)
+	for _, subj := range s.subjects {
+		if subj.Slug == slug {
+			return subj
+		}
+	}
+	subj := &photoprism.Subject{UID: s.newUID('j'), Name: name, Slug: slug, CreatedAt: now()}
+	subj.UpdatedAt = subj.CreatedAt
+	s.subjects[subj.UID] = subj
+	return subj
+}
+
+// addMarker attaches a marker to a photo, linking it to a subject by
+// SubjUID or Name. The caller must hold s.mu.
+func (s *Server) addMarker(p *photo, m photoprism.Marker) *photoprism.Marker {
+	if m.UID == "" {
+		m.UID = s.newUID('m')
+	}
+	if m.Type == "" {
+		m.Type = "face"
+	}
+	m.FileUID = p.fileUID
+	if m.SubjUID != "" {
+		if _, ok := s.subjects[m.SubjUID]; !ok && m.Name != "" {
+			s.subjects[m.SubjUID] = &photoprism.Subject{UID: m.SubjUID, Name: m.Name, Slug: slugify(m.Name)}
+		}
+	} else if m.Name != "" {
+		m.SubjUID = s.subjectByName(m.Name).UID
+	}
+	stored := &m
+	p.markers = append(p.markers, stored)
+	s.markers[m.UID] = stored
+	return stored
+}
+
+// subjectView returns the subject with its current photo count. The caller must hold s.mu.
+func (s *Server) subjectView(subj *photoprism.Subject) photoprism.Subject {
+	view := *subj
+	view.PhotoCount = 0
+	for _, p := range s.photos {
+		if p.DeletedAt == "" && s.hasPerson(p, subj.UID) {
+			view.PhotoCount++
+			if view.Thumb == "" {
+				view.Thumb = p.Hash
+			}
+		}
+	}
+	return view
+}
+
+func (s *Server) handleListSubjects(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s.mu.Lock()
+	subjects := make([]photoprism.Subject, 0, len(s.subjects))
+	for _, subj := range s.subjects {
+		subjects = append(subjects, s.subjectView(subj))
+	}
+	s.mu.Unlock()
+
+	slices.SortFunc(subjects, func(a, b photoprism.Subject) int {
+		return strings.Compare(a.Slug, b.Slug)
+	})
+	writeJSON(w, http.StatusOK, paginate(subjects, q.Get("count"), q.Get("offset")))
+}
+
+func (s *Server) handleGetSubject(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subj, ok := s.subjects[r.PathValue("uid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Subject not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.subjectView(subj))
+}
+
+func (s *Server) handleUpdateSubject(w http.ResponseWriter, r *http.Request) {
+	var update photoprism.SubjectUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subj, ok := s.subjects[r.PathValue("uid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Subject not found")
+		return
+	}
+	if update.Name != nil && *update.Name != subj.Name {
+		subj.Name = *update.Name
+		subj.Slug = slugify(*update.Name)
+		for _, m := range s.markers {
+			if m.SubjUID == subj.UID {
+				m.Name = subj.Name
+			}
+		}
+	}
+	setIf(&subj.About, update.About)
+	setIf(&subj.Alias, update.Alias)
+	setIf(&subj.Bio, update.Bio)
+	setIf(&subj.Notes, update.Notes)
+	setIf(&subj.Favorite, update.Favorite)
+	setIf(&subj.Hidden, update.Hidden)
+	setIf(&subj.Private, update.Private)
+	setIf(&subj.Excluded, update.Excluded)
+	subj.UpdatedAt = now()
+	writeJSON(w, http.StatusOK, s.subjectView(subj))
+}
+
+// handleListFaces lists one face per valid face marker.
+func (s *Server) handleListFaces(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s.mu.Lock()
+	faces := make([]photoprism.Face, 0, len(s.markers))
+	for _, m := range s.markers {
+		if m.Invalid || m.Type != "face" {
+			continue
+		}
+		id := m.FaceID
+		if id == "" {
+			id = strings.ToUpper(m.UID)
+		}
+		faces = append(faces, photoprism.Face{
+			ID: id, MarkerUID: m.UID, FileUID: m.FileUID, SubjUID: m.SubjUID, Name: m.Name,
+			Src: m.Src, SubjSrc: m.SubjSrc, Size: m.Size, Score: m.Score, FaceDist: m.FaceDist, Samples: 1,
+		})
+	}
+	s.mu.Unlock()
+
+	slices.SortFunc(faces, func(a, b photoprism.Face) int { return strings.Compare(a.ID, b.ID) })
+	writeJSON(w, http.StatusOK, paginate(faces, q.Get("count"), q.Get("offset")))
+}
+
+func (s *Server) handleCreateMarker(w http.ResponseWriter, r *http.Request) {
+	var input photoprism.MarkerCreate
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var target *photo
+	for _, p := range s.photos {
+		if p.fileUID == input.FileUID {
+			target = p
+			break
+		}
+	}
+	if target == nil {
+		writeError(w, http.StatusNotFound, "File not found")
+		return
+	}
+	m := s.addMarker(target, photoprism.Marker{
+		Type: input.Type, Src: input.Src, Name: strings.TrimSpace(input.Name), SubjSrc: input.SubjSrc,
+		X: input.X, Y: input.Y, W: input.W, H: input.H, Score: 100,
+		Size: int(input.W * float64(max(target.Width, target.Height))),
+	})
+	writeJSON(w, http.StatusOK, m)
+}
+
+func (s *Server) handleUpdateMarker(w http.ResponseWriter, r *http.Request) {
+	var update photoprism.MarkerUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.markers[r.PathValue("uid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Marker not found")
+		return
+	}
+	if name := strings.TrimSpace(update.Name); name != "" {
+		subj := s.subjectByName(name)
+		m.Name = subj.Name
+		m.SubjUID = subj.UID
+	}
+	if update.SubjSrc != "" {
+		m.SubjSrc = update.SubjSrc
+	}
+	setIf(&m.Invalid, update.Invalid)
+	writeJSON(w, http.StatusOK, m)
+}
+
+func (s *Server) handleClearMarkerSubject(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.markers[r.PathValue("uid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Marker not found")
+		return
+	}
+	m.Name = ""
+	m.SubjUID = ""
+	m.SubjSrc = ""
+	writeJSON(w, http.StatusOK, m)
+}
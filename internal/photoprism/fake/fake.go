@@ -0,0 +1,258 @@
+// Package fake implements an in-memory stand-in for the PhotoPrism API.
+//
+// It serves the endpoints used by the photoprism client (albums, photos,
+// labels, markers, subjects, faces, thumbnails, downloads and uploads) from
+// a directory of images and JSON responses captured with --capture. All
+// changes made through the API are kept in memory, so the CLI, the web UI
+// and the MCP server can be exercised end-to-end without a live instance.
+package fake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kozaktomas/photo-sorter/internal/facematch"
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+)
+
+// DownloadToken is the download token handed out by every fake session.
+const DownloadToken = "fake-download-token"
+
+// Options configures a fake server.
+type Options struct {
+	Username string // Accepted login name; empty accepts any credentials
+	Password string // Accepted password
+}
+
+// Server is an in-memory PhotoPrism. It is safe for concurrent use.
+type Server struct {
+	opts Options
+
+	mu       sync.Mutex
+	seq      int
+	sessions map[string]bool
+	userUID  string
+	albums   map[string]*album
+	photos   map[string]*photo
+	labels   map[string]*label
+	subjects map[string]*photoprism.Subject
+	markers  map[string]*photoprism.Marker
+	files    map[string]*file   // by hash
+	uploads  map[string][]*file // staged files by upload token
+}
+
+// album is an album and its photos in album order.
+type album struct {
+	photoprism.Album
+	photoUIDs []string
+}
+
+// photo is a photo with its primary file, labels and captured details.
+type photo struct {
+	photoprism.Photo
+	fileUID string
+	added   int                  // insertion sequence, for "added" ordering
+	labels  []photoLabel         // in insertion order
+	details map[string]any       // captured detail fields not modeled here
+	markers []*photoprism.Marker // markers of the primary file
+}
+
+// photoLabel assigns a label to a photo.
+type photoLabel struct {
+	labelUID    string
+	src         string
+	uncertainty int
+}
+
+// label is a label with the numeric ID used by the photo label endpoints.
+type label struct {
+	photoprism.Label
+	id int
+}
+
+// file is the content behind a file hash, either on disk or in memory.
+type file struct {
+	name string
+	path string
+	data []byte
+}
+
+// New creates an empty fake server.
+func New(opts Options) *Server {
+	return &Server{
+		opts:     opts,
+		sessions: make(map[string]bool),
+		albums:   make(map[string]*album),
+		photos:   make(map[string]*photo),
+		labels:   make(map[string]*label),
+		subjects: make(map[string]*photoprism.Subject),
+		markers:  make(map[string]*photoprism.Marker),
+		files:    make(map[string]*file),
+		uploads:  make(map[string][]*file),
+	}
+}
+
+// ExpireSessions invalidates all session tokens, so clients have to log in again.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.sessions)
+}
+
+// Handler returns the HTTP handler serving the PhotoPrism API under /api/v1.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/sessions", s.handleLogin)
+	mux.HandleFunc("DELETE /api/v1/session", s.authorized(s.handleLogout))
+
+	mux.HandleFunc("GET /api/v1/albums", s.authorized(s.handleListAlbums))
+	mux.HandleFunc("POST /api/v1/albums", s.authorized(s.handleCreateAlbum))
+	mux.HandleFunc("GET /api/v1/albums/{uid}", s.authorized(s.handleGetAlbum))
+	mux.HandleFunc("POST /api/v1/albums/{uid}/photos", s.authorized(s.handleAddToAlbum))
+	mux.HandleFunc("DELETE /api/v1/albums/{uid}/photos", s.authorized(s.handleRemoveFromAlbum))
+
+	mux.HandleFunc("GET /api/v1/photos", s.authorized(s.handleSearchPhotos))
+	mux.HandleFunc("GET /api/v1/photos/{uid}", s.authorized(s.handleGetPhoto))
+	mux.HandleFunc("PUT /api/v1/photos/{uid}", s.authorized(s.handleUpdatePhoto))
+	mux.HandleFunc("POST /api/v1/photos/{uid}/approve", s.authorized(s.handleApprovePhoto))
+	mux.HandleFunc("POST /api/v1/photos/{uid}/label", s.authorized(s.handleAddPhotoLabel))
+	mux.HandleFunc("PUT /api/v1/photos/{uid}/label/{id}", s.authorized(s.handleUpdatePhotoLabel))
+	mux.HandleFunc("DELETE /api/v1/photos/{uid}/label/{id}", s.authorized(s.handleRemovePhotoLabel))
+	mux.HandleFunc("POST /api/v1/batch/photos/archive", s.authorized(s.handleArchivePhotos))
+
+	mux.HandleFunc("GET /api/v1/labels", s.authorized(s.handleListLabels))
+	mux.HandleFunc("PUT /api/v1/labels/{uid}", s.authorized(s.handleUpdateLabel))
+	mux.HandleFunc("POST /api/v1/batch/labels/delete", s.authorized(s.handleDeleteLabels))
+
+	mux.HandleFunc("GET /api/v1/subjects", s.authorized(s.handleListSubjects))
+	mux.HandleFunc("GET /api/v1/subjects/{uid}", s.authorized(s.handleGetSubject))
+	mux.HandleFunc("PUT /api/v1/subjects/{uid}", s.authorized(s.handleUpdateSubject))
+	mux.HandleFunc("GET /api/v1/faces", s.authorized(s.handleListFaces))
+	mux.HandleFunc("POST /api/v1/markers", s.authorized(s.handleCreateMarker))
+	mux.HandleFunc("PUT /api/v1/markers/{uid}", s.authorized(s.handleUpdateMarker))
+	mux.HandleFunc("DELETE /api/v1/markers/{uid}/subject", s.authorized(s.handleClearMarkerSubject))
+
+	mux.HandleFunc("GET /api/v1/t/{hash}/{token}/{size}", s.handleThumbnail)
+	mux.HandleFunc("GET /api/v1/dl/{hash}", s.handleDownload)
+	mux.HandleFunc("POST /api/v1/users/{user}/upload/{token}", s.authorized(s.handleUpload))
+	mux.HandleFunc("PUT /api/v1/users/{user}/upload/{token}", s.authorized(s.handleProcessUpload))
+	return mux
+}
+
+// authorized rejects requests without a valid session token.
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		s.mu.Lock()
+		ok := s.sessions[token]
+		s.mu.Unlock()
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var creds map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if s.opts.Username != "" && (creds["username"] != s.opts.Username || creds["password"] != s.opts.Password) {
+		writeError(w, http.StatusUnauthorized, "Invalid credentials")
+		return
+	}
+
+	s.mu.Lock()
+	token := s.newUID('s')
+	s.sessions[token] = true
+	if s.userUID == "" {
+		s.userUID = s.newUID('u')
+	}
+	userUID := s.userUID
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":           token,
+		"access_token": token,
+		"config":       map[string]string{"downloadToken": DownloadToken, "previewToken": DownloadToken},
+		"user":         map[string]string{"UID": userUID, "Name": creds["username"]},
+	})
+}
+
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	s.mu.Lock()
+	delete(s.sessions, token)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// newUID returns a new PhotoPrism-style UID: a type prefix and 15 characters.
+// The caller must hold s.mu.
+func (s *Server) newUID(prefix byte) string {
+	s.seq++
+	return fmt.Sprintf("%cfake%011d", prefix, s.seq)
+}
+
+// now returns the current time in PhotoPrism's timestamp format.
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// slugify converts a name to a PhotoPrism slug ("Jiří Novák" -> "jiri-novak").
+func slugify(name string) string {
+	name = strings.ToLower(facematch.RemoveDiacritics(strings.TrimSpace(name)))
+	var b strings.Builder
+	dash := false
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a PhotoPrism-style error response.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// decodeSelection reads a batch selection body such as {"photos": [...]}.
+func decodeSelection(r *http.Request, key string) ([]string, error) {
+	var body map[string][]string
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid selection: %w", err)
+	}
+	return body[key], nil
+}
+
+// decodeOptionalJSON decodes a JSON request body into v, accepting an empty body.
+func decodeOptionalJSON(r *http.Request, v any) error {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
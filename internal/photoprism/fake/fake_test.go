@@ -0,0 +1,434 @@
+package fake
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+)
+
+// testJPEG returns a small JPEG filled with the given gray level.
+func testJPEG(t *testing.T, width, height int, gray uint8) []byte {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = gray
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("encoding test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// startServer serves s over HTTP and returns a logged-in client.
+func startServer(t *testing.T, s *Server) *photoprism.PhotoPrism {
+	t.Helper()
+	server := httptest.NewServer(s.Handler())
+	t.Cleanup(server.Close)
+	pp, err := photoprism.NewPhotoPrism(server.URL, "admin", "secret")
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	pp.SetRetryPolicy(photoprism.RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	return pp
+}
+
+func TestLogin_RejectsWrongCredentials(t *testing.T) {
+	server := httptest.NewServer(New(Options{Username: "admin", Password: "secret"}).Handler())
+	defer server.Close()
+
+	// The client does not check the login status; the session stays empty.
+	pp, err := photoprism.NewPhotoPrism(server.URL, "admin", "wrong")
+	if err != nil {
+		t.Fatalf("NewPhotoPrism failed: %v", err)
+	}
+	if _, err := pp.GetAlbums(10, 0, "", "", ""); err == nil {
+		t.Error("expected requests with wrong credentials to fail")
+	}
+
+	pp, err = photoprism.NewPhotoPrism(server.URL, "admin", "secret")
+	if err != nil {
+		t.Fatalf("NewPhotoPrism failed: %v", err)
+	}
+	if _, err := pp.GetAlbums(10, 0, "", "", ""); err != nil {
+		t.Errorf("expected requests to succeed, got %v", err)
+	}
+}
+
+func TestAlbums(t *testing.T) {
+	s := New(Options{})
+	photoUID := s.AddPhoto(photoprism.Photo{Title: "Beach"}, testJPEG(t, 8, 8, 200))
+	pp := startServer(t, s)
+
+	album, err := pp.CreateAlbum("Holiday")
+	if err != nil {
+		t.Fatalf("CreateAlbum failed: %v", err)
+	}
+	if err := pp.AddPhotosToAlbum(album.UID, []string{photoUID, "unknown"}); err != nil {
+		t.Fatalf("AddPhotosToAlbum failed: %v", err)
+	}
+
+	albums, err := pp.GetAlbums(10, 0, "", "holi", "album")
+	if err != nil {
+		t.Fatalf("GetAlbums failed: %v", err)
+	}
+	if len(albums) != 1 || albums[0].PhotoCount != 1 {
+		t.Fatalf("expected one album with one photo, got %+v", albums)
+	}
+
+	photos, err := pp.GetAlbumPhotos(album.UID, 10, 0)
+	if err != nil {
+		t.Fatalf("GetAlbumPhotos failed: %v", err)
+	}
+	if len(photos) != 1 || photos[0].UID != photoUID || photos[0].Width != 8 {
+		t.Fatalf("unexpected album photos: %+v", photos)
+	}
+
+	if err := pp.RemovePhotosFromAlbum(album.UID, []string{photoUID}); err != nil {
+		t.Fatalf("RemovePhotosFromAlbum failed: %v", err)
+	}
+	if in, _ := pp.IsPhotoInAlbum(photoUID, album.UID); in {
+		t.Error("expected photo to be removed from album")
+	}
+}
+
+func TestSearchPhotos_PaginatesAndOrders(t *testing.T) {
+	s := New(Options{})
+	for i := range 5 {
+		s.AddPhoto(photoprism.Photo{
+			Title:   "photo " + strconv.Itoa(i),
+			TakenAt: time.Date(2020+i, 1, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339),
+		}, nil)
+	}
+	pp := startServer(t, s)
+
+	var titles []string
+	for offset := 0; ; offset += 2 {
+		page, err := pp.GetPhotosWithQueryAndOrder(2, offset, "", "oldest")
+		if err != nil {
+			t.Fatalf("GetPhotosWithQueryAndOrder failed: %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, p := range page {
+			titles = append(titles, p.Title)
+		}
+	}
+	if len(titles) != 5 || titles[0] != "photo 0" || titles[4] != "photo 4" {
+		t.Errorf("unexpected order: %v", titles)
+	}
+
+	newest, err := pp.GetPhotos(1, 0)
+	if err != nil {
+		t.Fatalf("GetPhotos failed: %v", err)
+	}
+	if len(newest) != 1 || newest[0].Title != "photo 4" {
+		t.Errorf("expected newest photo first, got %+v", newest)
+	}
+}
+
+func TestPhotoLabels(t *testing.T) {
+	s := New(Options{})
+	photoUID := s.AddPhoto(photoprism.Photo{Title: "Dog"}, nil)
+	pp := startServer(t, s)
+
+	if _, err := pp.AddPhotoLabel(photoUID, photoprism.PhotoLabel{Name: "Černý pes", LabelSrc: "manual"}); err != nil {
+		t.Fatalf("AddPhotoLabel failed: %v", err)
+	}
+	found, err := pp.GetPhotosWithQuery(10, 0, "label:cerny-pes")
+	if err != nil {
+		t.Fatalf("GetPhotosWithQuery failed: %v", err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("expected one photo with label, got %d", len(found))
+	}
+
+	details, err := pp.GetPhotoDetails(photoUID)
+	if err != nil {
+		t.Fatalf("GetPhotoDetails failed: %v", err)
+	}
+	labels, _ := details["Labels"].([]any)
+	if len(labels) != 1 {
+		t.Fatalf("expected one label in details, got %v", details["Labels"])
+	}
+	entry, _ := labels[0].(map[string]any)
+	labelID, _ := entry["LabelID"].(float64)
+
+	if _, err := pp.RemovePhotoLabel(photoUID, strconv.Itoa(int(labelID))); err != nil {
+		t.Fatalf("RemovePhotoLabel failed: %v", err)
+	}
+	all, err := pp.GetLabels(10, 0, true)
+	if err != nil {
+		t.Fatalf("GetLabels failed: %v", err)
+	}
+	if len(all) != 1 || all[0].PhotoCount != 0 {
+		t.Errorf("expected unused label, got %+v", all)
+	}
+	used, _ := pp.GetLabels(10, 0, false)
+	if len(used) != 0 {
+		t.Errorf("expected no labels with photos, got %+v", used)
+	}
+}
+
+func TestMarkersAndSubjects(t *testing.T) {
+	s := New(Options{})
+	photoUID := s.AddPhoto(photoprism.Photo{Title: "Portrait"}, testJPEG(t, 100, 50, 90))
+	pp := startServer(t, s)
+
+	fileUID, err := pp.GetPhotoFileUID(photoUID)
+	if err != nil {
+		t.Fatalf("GetPhotoFileUID failed: %v", err)
+	}
+	marker, err := pp.CreateMarker(photoprism.MarkerCreate{
+		FileUID: fileUID, Type: "face", Src: "manual", X: 0.1, Y: 0.1, W: 0.2, H: 0.4,
+	})
+	if err != nil {
+		t.Fatalf("CreateMarker failed: %v", err)
+	}
+	if marker.Size != 20 {
+		t.Errorf("expected marker size 20, got %d", marker.Size)
+	}
+
+	if _, err := pp.UpdateMarker(marker.UID, photoprism.MarkerUpdate{Name: "Jan Novák", SubjSrc: "manual"}); err != nil {
+		t.Fatalf("UpdateMarker failed: %v", err)
+	}
+	found, _ := pp.GetPhotosWithQuery(10, 0, "person:jan-novak")
+	if len(found) != 1 {
+		t.Errorf("expected photo to be found by person, got %d", len(found))
+	}
+	subjects, err := pp.GetSubjects(10, 0)
+	if err != nil {
+		t.Fatalf("GetSubjects failed: %v", err)
+	}
+	if len(subjects) != 1 || subjects[0].Name != "Jan Novák" || subjects[0].PhotoCount != 1 {
+		t.Fatalf("unexpected subjects: %+v", subjects)
+	}
+	faces, _ := pp.GetFaces(10, 0)
+	if len(faces) != 1 || faces[0].SubjUID != subjects[0].UID {
+		t.Errorf("unexpected faces: %+v", faces)
+	}
+
+	if _, err := pp.ClearMarkerSubject(marker.UID); err != nil {
+		t.Fatalf("ClearMarkerSubject failed: %v", err)
+	}
+	markers, _ := pp.GetPhotoMarkers(photoUID)
+	if len(markers) != 1 || markers[0].SubjUID != "" {
+		t.Errorf("expected unassigned marker, got %+v", markers)
+	}
+	if _, err := pp.DeleteMarker(marker.UID); err != nil {
+		t.Fatalf("DeleteMarker failed: %v", err)
+	}
+	if markers, _ := pp.GetPhotoMarkers(photoUID); len(markers) != 0 {
+		t.Errorf("expected invalid marker to be hidden, got %+v", markers)
+	}
+}
+
+func TestThumbnailsAndDownloads(t *testing.T) {
+	s := New(Options{})
+	data := testJPEG(t, 400, 200, 120)
+	photoUID := s.AddPhoto(photoprism.Photo{FileName: "a.jpg"}, data)
+	pp := startServer(t, s)
+
+	downloaded, _, err := pp.GetPhotoDownload(photoUID)
+	if err != nil {
+		t.Fatalf("GetPhotoDownload failed: %v", err)
+	}
+	if !bytes.Equal(downloaded, data) {
+		t.Error("downloaded file differs from original")
+	}
+
+	photos, err := pp.GetPhotosWithQuery(1, 0, "uid:"+photoUID)
+	if err != nil || len(photos) != 1 {
+		t.Fatalf("GetPhotosWithQuery failed: %v", err)
+	}
+	thumb, contentType, err := pp.GetPhotoThumbnail(photos[0].Hash, "fit_100")
+	if err != nil {
+		t.Fatalf("GetPhotoThumbnail failed: %v", err)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(thumb))
+	if err != nil {
+		t.Fatalf("thumbnail is not an image (%s): %v", contentType, err)
+	}
+	if cfg.Width != 100 || cfg.Height != 50 {
+		t.Errorf("expected 100x50 thumbnail, got %dx%d", cfg.Width, cfg.Height)
+	}
+
+	if _, _, err := pp.GetPhotoThumbnail("unknown", "tile_50"); err == nil {
+		t.Error("expected error for unknown hash")
+	}
+}
+
+func TestUploadAndProcess(t *testing.T) {
+	s := New(Options{})
+	albumUID := s.AddAlbum("Uploads")
+	pp := startServer(t, s)
+
+	path := filepath.Join(t.TempDir(), "upload.jpg")
+	if err := os.WriteFile(path, testJPEG(t, 16, 16, 60), 0600); err != nil {
+		t.Fatal(err)
+	}
+	token, err := pp.UploadFile(path)
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+	if err := pp.ProcessUpload(token, []string{albumUID}); err != nil {
+		t.Fatalf("ProcessUpload failed: %v", err)
+	}
+	if err := pp.ProcessUpload(token, nil); err == nil {
+		t.Error("expected processing the same upload twice to fail")
+	}
+
+	photos, err := pp.GetAlbumPhotos(albumUID, 10, 0)
+	if err != nil {
+		t.Fatalf("GetAlbumPhotos failed: %v", err)
+	}
+	if len(photos) != 1 || photos[0].OriginalName != "upload.jpg" || photos[0].Title != "upload" {
+		t.Errorf("unexpected uploaded photos: %+v", photos)
+	}
+}
+
+func TestExpireSessions_ClientLogsInAgain(t *testing.T) {
+	s := New(Options{Username: "admin", Password: "secret"})
+	s.AddAlbum("Kept")
+	pp := startServer(t, s)
+
+	s.ExpireSessions()
+	albums, err := pp.GetAlbums(10, 0, "", "", "")
+	if err != nil {
+		t.Fatalf("expected client to log in again, got %v", err)
+	}
+	if len(albums) != 1 {
+		t.Errorf("expected one album, got %d", len(albums))
+	}
+}
+
+func TestArchivePhotos(t *testing.T) {
+	s := New(Options{})
+	photoUID := s.AddPhoto(photoprism.Photo{}, nil)
+	pp := startServer(t, s)
+
+	if err := pp.ArchivePhotos([]string{photoUID}); err != nil {
+		t.Fatalf("ArchivePhotos failed: %v", err)
+	}
+	photos, _ := pp.GetPhotos(10, 0)
+	if len(photos) != 0 {
+		t.Errorf("expected archived photo to be hidden, got %d", len(photos))
+	}
+	details, err := pp.GetPhotoDetails(photoUID)
+	if err != nil {
+		t.Fatalf("GetPhotoDetails failed: %v", err)
+	}
+	if !photoprism.IsPhotoDeleted(details) {
+		t.Error("expected details to report the photo as deleted")
+	}
+}
+
+func TestLoadDir_Captures(t *testing.T) {
+	s := New(Options{})
+	if err := s.LoadDir("../testdata"); err != nil {
+		t.Fatalf("LoadDir failed: %v", err)
+	}
+	pp := startServer(t, s)
+
+	album, err := pp.GetAlbum("at8e94h6pa15hbk7")
+	if err != nil {
+		t.Fatalf("GetAlbum failed: %v", err)
+	}
+	if album.Title != "Sports Event 1985" || album.PhotoCount == 0 {
+		t.Errorf("unexpected album: %+v", album)
+	}
+
+	markers, err := pp.GetPhotoMarkers("pt8sur39icikrn19")
+	if err != nil {
+		t.Fatalf("GetPhotoMarkers failed: %v", err)
+	}
+	if len(markers) == 0 || markers[0].UID != "mt8sur3f4rl6r2ii" || markers[0].FileUID != "ft8sur3ptsof6hj0" {
+		t.Errorf("unexpected markers: %+v", markers)
+	}
+	details, _ := pp.GetPhotoDetails("pt8sur39icikrn19")
+	if details["DocumentID"] != "6ef82676-c5c8-4cd7-89cc-f37a7a37925e" {
+		t.Errorf("expected captured detail fields to be replayed, got %v", details["DocumentID"])
+	}
+	found, _ := pp.GetPhotosWithQuery(10, 0, "label:racing")
+	if len(found) != 1 || found[0].UID != "pt8sur39icikrn19" {
+		t.Errorf("expected captured photo label to be searchable, got %+v", found)
+	}
+
+	labels, err := pp.GetLabels(1000, 0, true)
+	if err != nil {
+		t.Fatalf("GetLabels failed: %v", err)
+	}
+	if len(labels) < 2 {
+		t.Errorf("expected captured labels, got %d", len(labels))
+	}
+}
+
+func TestLoadDir_Images(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "Trip"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		"root.jpg":       testJPEG(t, 10, 10, 10),
+		"Trip/one.jpg":   testJPEG(t, 20, 10, 20),
+		"Trip/notes.txt": []byte("ignored"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := New(Options{})
+	if err := s.LoadDir(dir); err != nil {
+		t.Fatalf("LoadDir failed: %v", err)
+	}
+	pp := startServer(t, s)
+
+	photos, _ := pp.GetPhotos(10, 0)
+	if len(photos) != 2 {
+		t.Fatalf("expected 2 photos, got %d", len(photos))
+	}
+	albums, _ := pp.GetAlbums(10, 0, "", "", "")
+	if len(albums) != 1 || albums[0].Title != "Trip" || albums[0].PhotoCount != 1 {
+		t.Fatalf("unexpected albums: %+v", albums)
+	}
+	tripPhotos, _ := pp.GetAlbumPhotos(albums[0].UID, 10, 0)
+	if len(tripPhotos) != 1 || tripPhotos[0].Width != 20 || tripPhotos[0].Title != "one" {
+		t.Errorf("unexpected album photos: %+v", tripPhotos)
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Jiří Novák":      "jiri-novak",
+		"  Hello, World ": "hello-world",
+		"2024 - Summer!":  "2024-summer",
+	}
+	for input, want := range tests {
+		if got := slugify(input); got != want {
+			t.Errorf("slugify(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+// The placeholder must stay a decodable image for clients resizing thumbnails.
+func TestPlaceholderIsImage(t *testing.T) {
+	img, err := jpeg.Decode(bytes.NewReader(placeholder))
+	if err != nil {
+		t.Fatalf("placeholder is not a JPEG: %v", err)
+	}
+	if c, _ := color.GrayModel.Convert(img.At(10, 10)).(color.Gray); c.Y < 0x70 || c.Y > 0x90 {
+		t.Errorf("unexpected placeholder color %v", c)
+	}
+}
@@ -0,0 +1,217 @@
+package fake
+
+import (
+	"bytes"
+	"crypto/sha1" //nolint:gosec // PhotoPrism identifies files by their SHA-1 hash
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif" // register GIF decoder
+	"image/jpeg"
+	_ "image/png" // register PNG decoder
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kozaktomas/photo-sorter/internal/fingerprint"
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+)
+
+// maxUploadSize bounds a single upload request.
+const maxUploadSize = 512 << 20
+
+// content returns the file bytes, reading them from disk if needed.
+func (f *file) content() ([]byte, error) {
+	if f.data != nil {
+		return f.data, nil
+	}
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", f.name, err)
+	}
+	return data, nil
+}
+
+// hash returns the SHA-1 hash of the file content, as PhotoPrism does.
+func (f *file) hash() string {
+	data, err := f.content()
+	if err != nil {
+		return ""
+	}
+	sum := sha1.Sum(data) //nolint:gosec // see import
+	return hex.EncodeToString(sum[:])
+}
+
+// dimensions returns the image size, or 0x0 if the file is not a decodable image.
+func (f *file) dimensions() (int, int) {
+	data, err := f.content()
+	if err != nil {
+		return 0, 0
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0
+	}
+	return cfg.Width, cfg.Height
+}
+
+// placeholder is served for photos whose image is not available, e.g. photos
+// that only exist in captured JSON.
+var placeholder = func() []byte {
+	img := image.NewGray(image.Rect(0, 0, 64, 64))
+	for i := range img.Pix {
+		img.Pix[i] = 0x80
+	}
+	var buf bytes.Buffer
+	_ = jpeg.Encode(&buf, img, nil)
+	return buf.Bytes()
+}()
+
+// fileContent returns the content of the file with the given hash, or the
+// placeholder image for hashes of known photos without a file.
+func (s *Server) fileContent(hash string) ([]byte, bool) {
+	s.mu.Lock()
+	f, ok := s.files[hash]
+	known := ok
+	if !ok {
+		for _, p := range s.photos {
+			if p.Hash == hash {
+				known = true
+				break
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if f == nil {
+		return placeholder, known
+	}
+	data, err := f.content()
+	if err != nil {
+		return placeholder, true
+	}
+	return data, true
+}
+
+// handleThumbnail serves /t/{hash}/{token}/{size}, scaled to fit the pixel
+// size in the size name (e.g. "fit_1280" or "tile_500").
+func (s *Server) handleThumbnail(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("token") != DownloadToken {
+		writeError(w, http.StatusForbidden, "Invalid download token")
+		return
+	}
+	data, ok := s.fileContent(r.PathValue("hash"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "File not found")
+		return
+	}
+	size := r.PathValue("size")
+	if px, err := strconv.Atoi(size[strings.LastIndex(size, "_")+1:]); err == nil && px > 0 {
+		if resized, err := fingerprint.ResizeImage(data, px); err == nil {
+			data = resized
+		}
+	}
+	w.Header().Set("Content-Type", http.DetectContentType(data))
+	w.Write(data)
+}
+
+// handleDownload serves the original file behind /dl/{hash}?t=<download token>.
+func (s *Server) handleDownload(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("t") != DownloadToken {
+		writeError(w, http.StatusForbidden, "Invalid download token")
+		return
+	}
+	data, ok := s.fileContent(r.PathValue("hash"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "File not found")
+		return
+	}
+	w.Header().Set("Content-Type", http.DetectContentType(data))
+	w.Write(data)
+}
+
+// handleUpload stages the files of a multipart upload under its upload token.
+func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid multipart form")
+		return
+	}
+	var staged []*file
+	for _, header := range r.MultipartForm.File["files"] {
+		part, err := header.Open()
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "could not read upload")
+			return
+		}
+		data, err := io.ReadAll(part)
+		part.Close()
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "could not read upload")
+			return
+		}
+		staged = append(staged, &file{name: filepath.Base(header.Filename), data: data})
+	}
+	if len(staged) == 0 {
+		writeError(w, http.StatusBadRequest, "no files uploaded")
+		return
+	}
+
+	s.mu.Lock()
+	token := r.PathValue("token")
+	s.uploads[token] = append(s.uploads[token], staged...)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"code": http.StatusOK, "message": "files uploaded"})
+}
+
+// handleProcessUpload imports the staged files of an upload token as photos
+// and adds them to the requested albums. Files already in the library
+// (same hash) are skipped, as PhotoPrism does.
+func (s *Server) handleProcessUpload(w http.ResponseWriter, r *http.Request) {
+	var options struct {
+		Albums []string `json:"albums"`
+	}
+	if err := decodeOptionalJSON(r, &options); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token := r.PathValue("token")
+	staged, ok := s.uploads[token]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Upload not found")
+		return
+	}
+	delete(s.uploads, token)
+	for _, f := range staged {
+		hash := f.hash()
+		if uid := s.photoUIDByHash(hash); uid != "" {
+			for _, albumUID := range options.Albums {
+				if a, ok := s.albums[albumUID]; ok {
+					a.add(uid)
+				}
+			}
+			continue
+		}
+		s.addPhoto(photoprism.Photo{
+			Hash: hash, FileName: f.name, OriginalName: f.name,
+			Title: strings.TrimSuffix(f.name, filepath.Ext(f.name)),
+		}, f, options.Albums...)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"code": http.StatusOK, "message": "upload processed"})
+}
+
+// photoUIDByHash returns the UID of the photo with the given file hash, or "".
+// The caller must hold s.mu.
+func (s *Server) photoUIDByHash(hash string) string {
+	for _, p := range s.photos {
+		if p.Hash == hash {
+			return p.UID
+		}
+	}
+	return ""
+}
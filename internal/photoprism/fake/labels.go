@@ -0,0 +1,207 @@
+package fake
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+)
+
+// addLabel stores a label, assigning a UID and slug if missing, and returns
+// it. A label with the same slug is reused. The caller must hold s.mu.
+func (s *Server) addLabel(l photoprism.Label) *label {
+	if l.Slug == "" {
+		l.Slug = slugify(l.Name)
+	}
+	if existing := s.labelBySlug(l.Slug); existing != nil {
+		return existing
+	}
+	if l.UID == "" {
+		l.UID = s.newUID('l')
+	}
+	if l.CreatedAt == "" {
+		l.CreatedAt = now()
+	}
+	s.seq++
+	stored := &label{Label: l, id: s.seq}
+	s.labels[l.UID] = stored
+	return stored
+}
+
+// labelBySlug returns the label with the given slug, or nil. The caller must hold s.mu.
+func (s *Server) labelBySlug(slug string) *label {
+	for _, l := range s.labels {
+		if l.Slug == slug {
+			return l
+		}
+	}
+	return nil
+}
+
+// labelByID returns the label with the given numeric ID, or nil. The caller must hold s.mu.
+func (s *Server) labelByID(id int) *label {
+	for _, l := range s.labels {
+		if l.id == id {
+			return l
+		}
+	}
+	return nil
+}
+
+// labelView returns the label with its current photo count. The caller must hold s.mu.
+func (s *Server) labelView(l *label) photoprism.Label {
+	view := l.Label
+	view.PhotoCount = 0
+	for _, p := range s.photos {
+		if p.DeletedAt != "" {
+			continue
+		}
+		if slices.ContainsFunc(p.labels, func(pl photoLabel) bool { return pl.labelUID == l.UID }) {
+			view.PhotoCount++
+		}
+	}
+	return view
+}
+
+func (s *Server) handleListLabels(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	all := q.Get("all") == "true"
+
+	s.mu.Lock()
+	labels := make([]photoprism.Label, 0, len(s.labels))
+	for _, l := range s.labels {
+		view := s.labelView(l)
+		if all || view.PhotoCount > 0 {
+			labels = append(labels, view)
+		}
+	}
+	s.mu.Unlock()
+
+	slices.SortFunc(labels, func(a, b photoprism.Label) int {
+		return strings.Compare(a.Slug, b.Slug)
+	})
+	writeJSON(w, http.StatusOK, paginate(labels, q.Get("count"), q.Get("offset")))
+}
+
+func (s *Server) handleUpdateLabel(w http.ResponseWriter, r *http.Request) {
+	var update photoprism.LabelUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.labels[r.PathValue("uid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Label not found")
+		return
+	}
+	if update.Name != nil {
+		l.Name = *update.Name
+		l.Slug = slugify(*update.Name)
+	}
+	setIf(&l.Description, update.Description)
+	setIf(&l.Notes, update.Notes)
+	setIf(&l.Priority, update.Priority)
+	setIf(&l.Favorite, update.Favorite)
+	writeJSON(w, http.StatusOK, s.labelView(l))
+}
+
+func (s *Server) handleDeleteLabels(w http.ResponseWriter, r *http.Request) {
+	uids, err := decodeSelection(r, "labels")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.mu.Lock()
+	for _, uid := range uids {
+		delete(s.labels, uid)
+	}
+	for _, p := range s.photos {
+		p.labels = slices.DeleteFunc(p.labels, func(pl photoLabel) bool {
+			return slices.Contains(uids, pl.labelUID)
+		})
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"code": http.StatusOK, "message": "Labels deleted"})
+}
+
+func (s *Server) handleAddPhotoLabel(w http.ResponseWriter, r *http.Request) {
+	var input photoprism.PhotoLabel
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || strings.TrimSpace(input.Name) == "" {
+		writeError(w, http.StatusBadRequest, "label name required")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.lookupPhoto(w, r)
+	if !ok {
+		return
+	}
+	l := s.addLabel(photoprism.Label{Name: strings.TrimSpace(input.Name), Priority: input.Priority})
+	src := input.LabelSrc
+	if src == "" {
+		src = "manual"
+	}
+	assignment := photoLabel{labelUID: l.UID, src: src, uncertainty: input.Uncertainty}
+	if i := p.labelIndex(l.UID); i >= 0 {
+		p.labels[i] = assignment
+	} else {
+		p.labels = append(p.labels, assignment)
+	}
+	writeJSON(w, http.StatusOK, s.details(p))
+}
+
+func (s *Server) handleUpdatePhotoLabel(w http.ResponseWriter, r *http.Request) {
+	var input photoprism.PhotoLabel
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, i, ok := s.lookupPhotoLabel(w, r)
+	if !ok {
+		return
+	}
+	p.labels[i].uncertainty = input.Uncertainty
+	if input.LabelSrc != "" {
+		p.labels[i].src = input.LabelSrc
+	}
+	writeJSON(w, http.StatusOK, s.details(p))
+}
+
+func (s *Server) handleRemovePhotoLabel(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, i, ok := s.lookupPhotoLabel(w, r)
+	if !ok {
+		return
+	}
+	p.labels = slices.Delete(p.labels, i, i+1)
+	writeJSON(w, http.StatusOK, s.details(p))
+}
+
+// lookupPhotoLabel resolves the photo and the index of the label named in the
+// request path, or writes a 404. The caller must hold s.mu.
+func (s *Server) lookupPhotoLabel(w http.ResponseWriter, r *http.Request) (*photo, int, bool) {
+	p, ok := s.lookupPhoto(w, r)
+	if !ok {
+		return nil, 0, false
+	}
+	id, _ := strconv.Atoi(r.PathValue("id"))
+	l := s.labelByID(id)
+	if l == nil || p.labelIndex(l.UID) < 0 {
+		writeError(w, http.StatusNotFound, "Label not found")
+		return nil, 0, false
+	}
+	return p, p.labelIndex(l.UID), true
+}
+
+// labelIndex returns the position of a label in the photo's labels, or -1.
+func (p *photo) labelIndex(labelUID string) int {
+	return slices.IndexFunc(p.labels, func(pl photoLabel) bool { return pl.labelUID == labelUID })
+}
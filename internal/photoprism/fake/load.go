@@ -0,0 +1,348 @@
+package fake
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+)
+
+// albumHintPattern extracts the album UID from captured album photo listings,
+// e.g. "photos_album_at8e94h6pa15hbk7_offset_0_<timestamp>.json" or
+// "photos?count=100&offset=0&s=at8e94h6pa15hbk7_<timestamp>.json".
+var albumHintPattern = regexp.MustCompile(`(?:album_|[?&]s=)([a-z0-9]{16})`)
+
+// imageExtensions are the file types loaded as photos.
+var imageExtensions = []string{".jpg", ".jpeg", ".png", ".gif"}
+
+// LoadDir loads a fixture directory into the server:
+//
+//   - JSON responses captured with --capture are merged by file name prefix:
+//     albums_*, labels_*, photos_* (search results or photo details) and
+//     subjects_*. Other captures are ignored.
+//   - Images in dir become photos; images in a subdirectory are also added to
+//     an album titled after the subdirectory. An image whose SHA-1 hash matches
+//     a captured photo provides that photo's file instead of a new photo.
+//
+// Captures are loaded before images, in file name order.
+func (s *Server) LoadDir(dir string) error {
+	var captures, images []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		switch {
+		case ext == ".json":
+			captures = append(captures, path)
+		case slices.Contains(imageExtensions, ext):
+			images = append(images, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("reading fixture directory: %w", err)
+	}
+
+	slices.SortFunc(captures, func(a, b string) int { return strings.Compare(filepath.Base(a), filepath.Base(b)) })
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, path := range captures {
+		if err := s.loadCapture(path); err != nil {
+			return err
+		}
+	}
+	for _, path := range images {
+		if err := s.loadImage(dir, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadCapture merges a captured JSON response. The caller must hold s.mu.
+func (s *Server) loadCapture(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	name := filepath.Base(path)
+	switch {
+	case strings.HasPrefix(name, "albums"):
+		err = s.loadAlbums(data)
+	case strings.HasPrefix(name, "labels"):
+		err = s.loadLabels(data)
+	case strings.HasPrefix(name, "photos"):
+		err = s.loadPhotos(data, name)
+	case strings.HasPrefix(name, "subjects"):
+		err = s.loadSubjects(data)
+	}
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", name, err)
+	}
+	return nil
+}
+
+// loadImage adds an image file as a photo, or attaches it to the captured
+// photo with the same hash. The caller must hold s.mu.
+func (s *Server) loadImage(root, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	name := filepath.Base(path)
+	f := &file{name: name, path: path}
+	hash := f.hash()
+
+	var albumUIDs []string
+	if rel, err := filepath.Rel(root, filepath.Dir(path)); err == nil && rel != "." {
+		albumUIDs = append(albumUIDs, s.albumByTitle(filepath.ToSlash(rel)))
+	}
+
+	if uid := s.photoUIDByHash(hash); uid != "" {
+		s.files[hash] = f
+		for _, albumUID := range albumUIDs {
+			s.albums[albumUID].add(uid)
+		}
+		return nil
+	}
+	s.addPhoto(photoprism.Photo{
+		Hash: hash, FileName: name, OriginalName: name,
+		Title:   strings.TrimSuffix(name, filepath.Ext(name)),
+		TakenAt: info.ModTime().UTC().Format(time.RFC3339),
+	}, f, albumUIDs...)
+	return nil
+}
+
+// albumByTitle returns the UID of the album with the given title, creating it
+// if needed. The caller must hold s.mu.
+func (s *Server) albumByTitle(title string) string {
+	for _, a := range s.albums {
+		if a.Title == title {
+			return a.UID
+		}
+	}
+	return s.addAlbum(photoprism.Album{Title: title})
+}
+
+// albumByUID returns the album with the given UID, creating a placeholder
+// if it was not captured. The caller must hold s.mu.
+func (s *Server) albumByUID(uid string) *album {
+	if _, ok := s.albums[uid]; !ok {
+		s.addAlbum(photoprism.Album{UID: uid, Title: uid})
+	}
+	return s.albums[uid]
+}
+
+func (s *Server) loadAlbums(data []byte) error {
+	albums, err := decodeOneOrMany[photoprism.Album](data)
+	if err != nil {
+		return err
+	}
+	for _, a := range albums {
+		if a.UID != "" {
+			s.addAlbum(a)
+		}
+	}
+	return nil
+}
+
+func (s *Server) loadLabels(data []byte) error {
+	labels, err := decodeOneOrMany[capturedLabel](data)
+	if err != nil {
+		return err
+	}
+	for _, l := range labels {
+		s.importLabel(l)
+	}
+	return nil
+}
+
+func (s *Server) loadSubjects(data []byte) error {
+	subjects, err := decodeOneOrMany[photoprism.Subject](data)
+	if err != nil {
+		return err
+	}
+	for _, subj := range subjects {
+		if subj.UID == "" {
+			continue
+		}
+		if subj.Slug == "" {
+			subj.Slug = slugify(subj.Name)
+		}
+		s.subjects[subj.UID] = &subj
+	}
+	return nil
+}
+
+// capturedLabel is a label as PhotoPrism returns it, with its numeric ID.
+type capturedLabel struct {
+	photoprism.Label
+	ID int `json:"ID"`
+}
+
+// importLabel stores a captured label, keeping its UID and numeric ID.
+// The caller must hold s.mu.
+func (s *Server) importLabel(c capturedLabel) *label {
+	if existing, ok := s.labels[c.UID]; ok {
+		return existing
+	}
+	l := s.addLabel(c.Label)
+	if c.ID != 0 && s.labelByID(c.ID) == nil {
+		l.id = c.ID
+	}
+	return l
+}
+
+// loadPhotos merges a photo search result (array) or photo details (object).
+func (s *Server) loadPhotos(data []byte, name string) error {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return s.loadPhotoDetails(data)
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("decoding photos: %w", err)
+	}
+	var albumUIDs []string
+	if m := albumHintPattern.FindStringSubmatch(name); m != nil {
+		albumUIDs = append(albumUIDs, s.albumByUID(m[1]).UID)
+	}
+	for _, item := range items {
+		p, err := s.mergePhoto(item)
+		if err != nil {
+			return err
+		}
+		s.addPhoto(p, nil, albumUIDs...)
+	}
+	return nil
+}
+
+// mergePhoto decodes a captured photo on top of the stored photo with the
+// same UID, so partial captures do not erase known fields. The caller must hold s.mu.
+func (s *Server) mergePhoto(data []byte) (photoprism.Photo, error) {
+	var probe struct {
+		UID string `json:"UID"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return photoprism.Photo{}, fmt.Errorf("decoding photo: %w", err)
+	}
+	var p photoprism.Photo
+	if existing, ok := s.photos[probe.UID]; ok {
+		p = existing.Photo
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return photoprism.Photo{}, fmt.Errorf("decoding photo %s: %w", probe.UID, err)
+	}
+	return p, nil
+}
+
+// capturedFile is a file entry of a photo details response.
+type capturedFile struct {
+	UID          string              `json:"UID"`
+	Hash         string              `json:"Hash"`
+	Name         string              `json:"Name"`
+	OriginalName string              `json:"OriginalName"`
+	Primary      bool                `json:"Primary"`
+	Width        int                 `json:"Width"`
+	Height       int                 `json:"Height"`
+	Markers      []photoprism.Marker `json:"Markers"`
+}
+
+// capturedDetails holds the parts of a photo details response that are
+// modeled as state rather than replayed as captured.
+type capturedDetails struct {
+	Files  []capturedFile `json:"Files"`
+	Labels []struct {
+		LabelSrc    string        `json:"LabelSrc"`
+		Uncertainty int           `json:"Uncertainty"`
+		Label       capturedLabel `json:"Label"`
+	} `json:"Labels"`
+	Albums []photoprism.Album `json:"Albums"`
+}
+
+// loadPhotoDetails imports a photo details response: the photo, its primary
+// file, markers, labels and albums. The caller must hold s.mu.
+func (s *Server) loadPhotoDetails(data []byte) error {
+	p, err := s.mergePhoto(data)
+	if err != nil {
+		return err
+	}
+	var captured capturedDetails
+	if err := json.Unmarshal(data, &captured); err != nil {
+		return fmt.Errorf("decoding photo details %s: %w", p.UID, err)
+	}
+	var extra map[string]any
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return fmt.Errorf("decoding photo details %s: %w", p.UID, err)
+	}
+	for _, key := range []string{"Files", "Labels", "Albums"} {
+		delete(extra, key)
+	}
+
+	var primary capturedFile
+	if i := slices.IndexFunc(captured.Files, func(f capturedFile) bool { return f.Primary }); i >= 0 {
+		primary = captured.Files[i]
+	} else if len(captured.Files) > 0 {
+		primary = captured.Files[0]
+	}
+	p.Hash = cmp.Or(primary.Hash, p.Hash)
+	p.FileName = cmp.Or(primary.Name, p.FileName)
+	p.OriginalName = cmp.Or(primary.OriginalName, p.OriginalName)
+	p.Width = cmp.Or(primary.Width, p.Width)
+	p.Height = cmp.Or(primary.Height, p.Height)
+
+	albumUIDs := make([]string, 0, len(captured.Albums))
+	for _, a := range captured.Albums {
+		if _, ok := s.albums[a.UID]; !ok && a.UID != "" {
+			s.addAlbum(a)
+		}
+		albumUIDs = append(albumUIDs, a.UID)
+	}
+	stored := s.photos[s.addPhoto(p, nil, albumUIDs...)]
+	stored.details = extra
+	if primary.UID != "" {
+		stored.fileUID = primary.UID
+	}
+
+	stored.labels = nil
+	for _, pl := range captured.Labels {
+		l := s.importLabel(pl.Label)
+		stored.labels = append(stored.labels, photoLabel{labelUID: l.UID, src: pl.LabelSrc, uncertainty: pl.Uncertainty})
+	}
+	for _, m := range stored.markers {
+		delete(s.markers, m.UID)
+	}
+	stored.markers = nil
+	for _, m := range primary.Markers {
+		s.addMarker(stored, m)
+	}
+	return nil
+}
+
+// decodeOneOrMany decodes a JSON object or an array of objects.
+func decodeOneOrMany[T any](data []byte) ([]T, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		var items []T
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, fmt.Errorf("decoding list: %w", err)
+		}
+		return items, nil
+	}
+	var item T
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, fmt.Errorf("decoding object: %w", err)
+	}
+	return []T{item}, nil
+}
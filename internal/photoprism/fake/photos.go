@@ -0,0 +1,323 @@
+package fake
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+)
+
+// AddPhoto stores a photo with an in-memory primary file and returns its UID.
+// Fields left empty (UID, Hash, TakenAt, ...) are filled in; the photo is
+// added to the given albums.
+func (s *Server) AddPhoto(p photoprism.Photo, data []byte, albumUIDs ...string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addPhoto(p, &file{name: p.FileName, data: data}, albumUIDs...)
+}
+
+// addPhoto stores a photo and its primary file. The caller must hold s.mu.
+func (s *Server) addPhoto(p photoprism.Photo, f *file, albumUIDs ...string) string {
+	if p.UID == "" {
+		p.UID = s.newUID('p')
+	}
+	if p.Type == "" {
+		p.Type = "image"
+	}
+	if p.Hash == "" && f != nil {
+		p.Hash = f.hash()
+	}
+	if p.TakenAt == "" {
+		p.TakenAt = now()
+	}
+	if p.TakenAtLocal == "" {
+		p.TakenAtLocal = p.TakenAt
+	}
+	if p.CreatedAt == "" {
+		p.CreatedAt = now()
+	}
+	if p.UpdatedAt == "" {
+		p.UpdatedAt = p.CreatedAt
+	}
+	if f != nil {
+		if p.Width == 0 || p.Height == 0 {
+			p.Width, p.Height = f.dimensions()
+		}
+		s.files[p.Hash] = f
+	}
+
+	existing, ok := s.photos[p.UID]
+	if !ok {
+		s.seq++
+		existing = &photo{added: s.seq, fileUID: s.newUID('f')}
+		s.photos[p.UID] = existing
+	}
+	existing.Photo = p
+	for _, albumUID := range albumUIDs {
+		if a, ok := s.albums[albumUID]; ok {
+			a.add(p.UID)
+		}
+	}
+	return p.UID
+}
+
+// photoFilter is a parsed PhotoPrism search query.
+type photoFilter func(s *Server, p *photo) bool
+
+// parseQuery supports the filters used by photo-sorter: uid:, label:,
+// person:/subject:, year:, country: and free text matched against title,
+// caption and file names.
+func parseQuery(query string) []photoFilter {
+	var filters []photoFilter
+	for term := range strings.FieldsSeq(query) {
+		key, value, ok := strings.Cut(term, ":")
+		if !ok {
+			text := strings.ToLower(term)
+			filters = append(filters, func(_ *Server, p *photo) bool {
+				return strings.Contains(strings.ToLower(p.Title+" "+p.Caption+" "+p.OriginalName+" "+p.FileName), text)
+			})
+			continue
+		}
+		value = strings.Trim(value, `"'`)
+		switch key {
+		case "uid":
+			filters = append(filters, func(_ *Server, p *photo) bool { return p.UID == value })
+		case "label":
+			filters = append(filters, func(s *Server, p *photo) bool { return s.hasLabel(p, value) })
+		case "person", "subject":
+			filters = append(filters, func(s *Server, p *photo) bool { return s.hasPerson(p, value) })
+		case "year":
+			year, _ := strconv.Atoi(value)
+			filters = append(filters, func(_ *Server, p *photo) bool { return p.Year == year })
+		case "country":
+			filters = append(filters, func(_ *Server, p *photo) bool { return strings.EqualFold(p.Country, value) })
+		default:
+			filters = append(filters, func(*Server, *photo) bool { return false })
+		}
+	}
+	return filters
+}
+
+// hasLabel reports whether a photo carries the label with the given slug.
+func (s *Server) hasLabel(p *photo, slug string) bool {
+	for _, pl := range p.labels {
+		if l, ok := s.labels[pl.labelUID]; ok && l.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPerson reports whether a valid marker of the photo is assigned to the
+// subject with the given slug or UID.
+func (s *Server) hasPerson(p *photo, slugOrUID string) bool {
+	for _, m := range p.markers {
+		if m.Invalid || m.SubjUID == "" {
+			continue
+		}
+		if subj, ok := s.subjects[m.SubjUID]; ok && (subj.Slug == slugOrUID || subj.UID == slugOrUID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) handleSearchPhotos(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filters := parseQuery(q.Get("q"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var candidates []*photo
+	if albumUID := q.Get("s"); albumUID != "" {
+		if a, ok := s.albums[albumUID]; ok {
+			for _, uid := range a.photoUIDs {
+				if p, ok := s.photos[uid]; ok {
+					candidates = append(candidates, p)
+				}
+			}
+		}
+		if order := q.Get("order"); order != "" {
+			sortPhotos(candidates, order)
+		}
+	} else {
+		candidates = slices.Collect(maps.Values(s.photos))
+		sortPhotos(candidates, q.Get("order"))
+	}
+
+	photos := make([]photoprism.Photo, 0, len(candidates))
+	for _, p := range candidates {
+		if p.DeletedAt == "" && s.matches(p, filters) {
+			photos = append(photos, p.Photo)
+		}
+	}
+	writeJSON(w, http.StatusOK, paginate(photos, q.Get("count"), q.Get("offset")))
+}
+
+// matches reports whether a photo passes all filters. The caller must hold s.mu.
+func (s *Server) matches(p *photo, filters []photoFilter) bool {
+	for _, f := range filters {
+		if !f(s, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortPhotos orders photos like PhotoPrism's search: "oldest", "added",
+// "edited" (never edited last) or (default) "newest".
+func sortPhotos(photos []*photo, order string) {
+	slices.SortStableFunc(photos, func(a, b *photo) int {
+		switch order {
+		case "oldest":
+			return cmp.Or(strings.Compare(a.TakenAt, b.TakenAt), cmp.Compare(a.added, b.added))
+		case "added":
+			return cmp.Compare(b.added, a.added)
+		case "edited":
+			// Empty EditedAt compares lowest, so never edited photos come last.
+			return cmp.Or(strings.Compare(b.EditedAt, a.EditedAt), cmp.Compare(b.added, a.added))
+		default:
+			return cmp.Or(strings.Compare(b.TakenAt, a.TakenAt), cmp.Compare(b.added, a.added))
+		}
+	})
+}
+
+// details renders the photo details response: the captured detail fields
+// overlaid with the current state. The caller must hold s.mu.
+func (s *Server) details(p *photo) map[string]any {
+	result := maps.Clone(p.details)
+	if result == nil {
+		result = make(map[string]any)
+	}
+	if data, err := json.Marshal(p.Photo); err == nil {
+		var fields map[string]any
+		if err := json.Unmarshal(data, &fields); err == nil {
+			maps.Copy(result, fields)
+		}
+	}
+
+	markers := make([]photoprism.Marker, 0, len(p.markers))
+	for _, m := range p.markers {
+		markers = append(markers, *m)
+	}
+	result["Files"] = []map[string]any{{
+		"UID": p.fileUID, "PhotoUID": p.UID, "Hash": p.Hash, "Primary": true,
+		"Name": p.FileName, "OriginalName": p.OriginalName,
+		"Width": p.Width, "Height": p.Height, "FileType": "jpg", "MediaType": "image",
+		"Markers": markers,
+	}}
+
+	labels := make([]map[string]any, 0, len(p.labels))
+	for _, pl := range p.labels {
+		l, ok := s.labels[pl.labelUID]
+		if !ok {
+			continue
+		}
+		labels = append(labels, map[string]any{
+			"LabelID": l.id, "LabelSrc": pl.src, "Uncertainty": pl.uncertainty,
+			"Label": map[string]any{"ID": l.id, "UID": l.UID, "Slug": l.Slug, "Name": l.Name},
+		})
+	}
+	result["Labels"] = labels
+
+	albums := []photoprism.Album{}
+	for _, a := range s.albums {
+		if slices.Contains(a.photoUIDs, p.UID) {
+			albums = append(albums, a.Album)
+		}
+	}
+	result["Albums"] = albums
+	return result
+}
+
+// lookupPhoto returns the photo named in the request path or writes a 404.
+// The caller must hold s.mu.
+func (s *Server) lookupPhoto(w http.ResponseWriter, r *http.Request) (*photo, bool) {
+	p, ok := s.photos[r.PathValue("uid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Photo not found")
+	}
+	return p, ok
+}
+
+func (s *Server) handleGetPhoto(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.lookupPhoto(w, r); ok {
+		writeJSON(w, http.StatusOK, s.details(p))
+	}
+}
+
+func (s *Server) handleApprovePhoto(w http.ResponseWriter, r *http.Request) {
+	s.handleGetPhoto(w, r)
+}
+
+func (s *Server) handleUpdatePhoto(w http.ResponseWriter, r *http.Request) {
+	var update photoprism.PhotoUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.lookupPhoto(w, r)
+	if !ok {
+		return
+	}
+	applyPhotoUpdate(p, update)
+	p.EditedAt = now()
+	p.UpdatedAt = p.EditedAt
+	writeJSON(w, http.StatusOK, s.details(p))
+}
+
+// applyPhotoUpdate copies the set fields of update onto the photo.
+func applyPhotoUpdate(p *photo, u photoprism.PhotoUpdate) {
+	setIf(&p.Title, u.Title)
+	setIf(&p.Description, u.Description)
+	setIf(&p.TakenAt, u.TakenAt)
+	setIf(&p.TakenAtLocal, u.TakenAtLocal)
+	setIf(&p.Favorite, u.Favorite)
+	setIf(&p.Private, u.Private)
+	setIf(&p.Lat, u.Lat)
+	setIf(&p.Lng, u.Lng)
+	setIf(&p.Caption, u.Caption)
+	setIf(&p.Year, u.Year)
+	setIf(&p.Month, u.Month)
+	setIf(&p.Day, u.Day)
+	setIf(&p.Country, u.Country)
+	if u.Details != nil && u.Details.Notes != nil {
+		if p.details == nil {
+			p.details = make(map[string]any)
+		}
+		p.details["Details"] = map[string]any{"Notes": *u.Details.Notes}
+	}
+}
+
+// setIf assigns *src to *dst when src is set.
+func setIf[T any](dst *T, src *T) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+func (s *Server) handleArchivePhotos(w http.ResponseWriter, r *http.Request) {
+	uids, err := decodeSelection(r, "photos")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.mu.Lock()
+	for _, uid := range uids {
+		if p, ok := s.photos[uid]; ok {
+			p.DeletedAt = now()
+		}
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"code": http.StatusOK, "message": "Selection archived"})
+}
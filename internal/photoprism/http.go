@@ -11,41 +11,49 @@ import (
 	"strings"
 )
 
-// doGetJSON performs a GET request and unmarshals the JSON response into the result type.
-// The endpoint should be the path after the base API URL (e.g., "albums/123").
-func doGetJSON[T any](pp *PhotoPrism, endpoint string) (*T, error) {
-	url := pp.resolveURL(endpoint)
-
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+// newRequest creates a request authorized with the current session token.
+// A non-nil body is sent with the given content type.
+func (pp *PhotoPrism) newRequest(
+	ctx context.Context, method, url string, body []byte, contentType string,
+) (*http.Request, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("could not create request: %w", err)
 	}
-
-	req.Header.Set("Authorization", "Bearer "+pp.token)
-
-	resp, err := pp.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("could not send request: %w", err)
+	req.Header.Set("Authorization", "Bearer "+pp.session.accessToken())
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
 	}
-	defer resp.Body.Close()
+	return req, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, readErrorBody(resp.Body))
-	}
+// sendJSON marshals requestBody (if any) and sends it to endpoint, retrying
+// idempotent methods. The caller must close the response body.
+func (pp *PhotoPrism) sendJSON(method, endpoint string, requestBody any) (*http.Response, error) {
+	url := pp.resolveURL(endpoint)
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("could not read response body: %w", err)
+	var body []byte
+	if requestBody != nil {
+		jsonBody, err := json.Marshal(requestBody)
+		if err != nil {
+			return nil, fmt.Errorf("could not marshal request body: %w", err)
+		}
+		body = jsonBody
 	}
 
-	pp.captureResponse(endpoint, body)
-
-	var result T
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("could not unmarshal response: %w", err)
-	}
+	return pp.send(pp.httpClient, isIdempotent(method), func(ctx context.Context) (*http.Request, error) {
+		return pp.newRequest(ctx, method, url, body, "application/json")
+	})
+}
 
-	return &result, nil
+// doGetJSON performs a GET request and unmarshals the JSON response into the result type.
+// The endpoint should be the path after the base API URL (e.g., "albums/123").
+func doGetJSON[T any](pp *PhotoPrism, endpoint string) (*T, error) {
+	return doRequestJSON[T](pp, http.MethodGet, endpoint, nil, http.StatusOK)
 }
 
 // doPostJSON performs a POST request with a JSON body and unmarshals the JSON response.
@@ -75,30 +83,9 @@ func doRequestJSON[T any](
 	pp *PhotoPrism, method, endpoint string,
 	requestBody any, expectedStatuses ...int,
 ) (*T, error) {
-	url := pp.resolveURL(endpoint)
-
-	var bodyReader io.Reader
-	if requestBody != nil {
-		jsonBody, err := json.Marshal(requestBody)
-		if err != nil {
-			return nil, fmt.Errorf("could not marshal request body: %w", err)
-		}
-		bodyReader = bytes.NewReader(jsonBody)
-	}
-
-	req, err := http.NewRequestWithContext(context.Background(), method, url, bodyReader)
-	if err != nil {
-		return nil, fmt.Errorf("could not create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+pp.token)
-	if requestBody != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
-
-	resp, err := pp.httpClient.Do(req)
+	resp, err := pp.sendJSON(method, endpoint, requestBody)
 	if err != nil {
-		return nil, fmt.Errorf("could not send request: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -123,35 +110,9 @@ func doRequestJSON[T any](
 
 // doRequestRaw performs an HTTP request without JSON unmarshaling the response.
 func doRequestRaw(pp *PhotoPrism, method, endpoint string, requestBody any) error {
-	return doRequestRawWithClient(pp, pp.httpClient, method, endpoint, requestBody)
-}
-
-// doRequestRawWithClient performs an HTTP request using a specific HTTP client.
-func doRequestRawWithClient(pp *PhotoPrism, client *http.Client, method, endpoint string, requestBody any) error {
-	url := pp.resolveURL(endpoint)
-
-	var bodyReader io.Reader
-	if requestBody != nil {
-		jsonBody, err := json.Marshal(requestBody)
-		if err != nil {
-			return fmt.Errorf("could not marshal request body: %w", err)
-		}
-		bodyReader = bytes.NewReader(jsonBody)
-	}
-
-	req, err := http.NewRequestWithContext(context.Background(), method, url, bodyReader)
-	if err != nil {
-		return fmt.Errorf("could not create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+pp.token)
-	if requestBody != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
-
-	resp, err := client.Do(req)
+	resp, err := pp.sendJSON(method, endpoint, requestBody)
 	if err != nil {
-		return fmt.Errorf("could not send request: %w", err)
+		return err
 	}
 	defer resp.Body.Close()
 
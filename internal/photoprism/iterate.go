@@ -0,0 +1,123 @@
+package photoprism
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+)
+
+// DefaultIteratePageSize is the number of photos requested per page when
+// IterateOptions.PageSize is not set.
+const DefaultIteratePageSize = 1000
+
+// iterateRetries is how often a failed page request is retried before the
+// iteration gives up. Transient failures are already retried by the client;
+// this also covers other errors, e.g. a 500 while PhotoPrism is indexing.
+const iterateRetries = 3
+
+// iterateRetryDelay is the delay before the first retry; it doubles with
+// each further attempt.
+var iterateRetryDelay = 500 * time.Millisecond
+
+// IterateOptions configures photo iteration.
+type IterateOptions struct {
+	PageSize int    // Photos per request (default DefaultIteratePageSize)
+	Quality  int    // Minimum quality score 1-7; 0 uses the PhotoPrism default
+	Order    string // Sort order, e.g. "newest" or "added"; empty uses the PhotoPrism default
+}
+
+// IteratePhotos returns an iterator over the pages of photos matching query
+// (e.g. "person:jan-novak", "label:cat", "" for all photos). The next page is
+// only requested once the caller is done with the previous one, so memory use
+// is bounded by the page size. Failed requests are retried with backoff. The
+// iteration ends after the last page, on the first error that persists after
+// retries, or when ctx is cancelled; errors are yielded with a nil page.
+func (pp *PhotoPrism) IteratePhotos(
+	ctx context.Context, query string, opts IterateOptions,
+) iter.Seq2[[]Photo, error] {
+	client := pp.WithContext(ctx)
+	return iteratePages(ctx, opts, func(count, offset int) ([]Photo, error) {
+		return client.GetPhotosWithQueryAndOrder(count, offset, query, opts.Order, opts.Quality)
+	})
+}
+
+// IterateAlbumPhotos returns an iterator over the pages of photos in an album.
+// It behaves like IteratePhotos; opts.Order is ignored.
+func (pp *PhotoPrism) IterateAlbumPhotos(
+	ctx context.Context, albumUID string, opts IterateOptions,
+) iter.Seq2[[]Photo, error] {
+	client := pp.WithContext(ctx)
+	return iteratePages(ctx, opts, func(count, offset int) ([]Photo, error) {
+		return client.GetAlbumPhotos(albumUID, count, offset, opts.Quality)
+	})
+}
+
+// CollectPhotoUIDs drains a photo iterator and returns the UIDs of all photos.
+func CollectPhotoUIDs(pages iter.Seq2[[]Photo, error]) ([]string, error) {
+	var uids []string
+	for page, err := range pages {
+		if err != nil {
+			return nil, err
+		}
+		for i := range page {
+			uids = append(uids, page[i].UID)
+		}
+	}
+	return uids, nil
+}
+
+// iteratePages pages through fetch until it returns a page shorter than
+// requested.
+func iteratePages(
+	ctx context.Context, opts IterateOptions, fetch func(count, offset int) ([]Photo, error),
+) iter.Seq2[[]Photo, error] {
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultIteratePageSize
+	}
+	return func(yield func([]Photo, error) bool) {
+		offset := 0
+		for {
+			photos, err := fetchPageWithRetry(ctx, pageSize, offset, fetch)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if len(photos) == 0 {
+				return
+			}
+			if !yield(photos, nil) || len(photos) < pageSize {
+				return
+			}
+			offset += len(photos)
+		}
+	}
+}
+
+// fetchPageWithRetry requests one page, retrying failures with exponential backoff.
+func fetchPageWithRetry(
+	ctx context.Context, count, offset int, fetch func(count, offset int) ([]Photo, error),
+) ([]Photo, error) {
+	delay := iterateRetryDelay
+	var lastErr error
+	for attempt := 0; attempt <= iterateRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("fetching photos cancelled: %w", ctx.Err())
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("fetching photos cancelled: %w", err)
+		}
+		photos, err := fetch(count, offset)
+		if err == nil {
+			return photos, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("failed to get photos at offset %d: %w", offset, lastErr)
+}
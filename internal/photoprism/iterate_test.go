@@ -0,0 +1,168 @@
+package photoprism
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// pagedPhotosHandler serves total photos in pages, failing the first
+// failures requests with a 500.
+func pagedPhotosHandler(t *testing.T, total int, failures int32, requests *atomic.Int32) http.HandlerFunc {
+	t.Helper()
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		if n <= failures {
+			http.Error(w, "temporary failure", http.StatusInternalServerError)
+			return
+		}
+		count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("["))
+		for i := offset; i < min(offset+count, total); i++ {
+			if i > offset {
+				w.Write([]byte(","))
+			}
+			fmt.Fprintf(w, `{"UID":"p%d"}`, i)
+		}
+		w.Write([]byte("]"))
+	}
+}
+
+func withFastRetries(t *testing.T) {
+	t.Helper()
+	old := iterateRetryDelay
+	iterateRetryDelay = time.Millisecond
+	t.Cleanup(func() { iterateRetryDelay = old })
+}
+
+func TestIteratePhotos_Pages(t *testing.T) {
+	var requests atomic.Int32
+	server := setupMockServerWithHandlers(t, map[string]http.HandlerFunc{
+		"/api/v1/photos": pagedPhotosHandler(t, 25, 0, &requests),
+	})
+	defer server.Close()
+
+	pp, err := NewPhotoPrism(server.URL, "test", "test")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	var sizes []int
+	seen := 0
+	for page, err := range pp.IteratePhotos(context.Background(), "", IterateOptions{PageSize: 10}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sizes = append(sizes, len(page))
+		if page[0].UID != fmt.Sprintf("p%d", seen) {
+			t.Errorf("expected page to start at p%d, got %s", seen, page[0].UID)
+		}
+		seen += len(page)
+	}
+	if fmt.Sprint(sizes) != "[10 10 5]" {
+		t.Errorf("expected pages [10 10 5], got %v", sizes)
+	}
+	// The short last page ends the iteration without another request.
+	if requests.Load() != 3 {
+		t.Errorf("expected 3 requests, got %d", requests.Load())
+	}
+}
+
+func TestIteratePhotos_StopsWhenCallerBreaks(t *testing.T) {
+	var requests atomic.Int32
+	server := setupMockServerWithHandlers(t, map[string]http.HandlerFunc{
+		"/api/v1/photos": pagedPhotosHandler(t, 100, 0, &requests),
+	})
+	defer server.Close()
+
+	pp, err := NewPhotoPrism(server.URL, "test", "test")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	for range pp.IteratePhotos(context.Background(), "", IterateOptions{PageSize: 10}) {
+		break
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected only the first page to be requested, got %d requests", requests.Load())
+	}
+}
+
+func TestIteratePhotos_RetriesFailedPages(t *testing.T) {
+	withFastRetries(t)
+	var requests atomic.Int32
+	server := setupMockServerWithHandlers(t, map[string]http.HandlerFunc{
+		"/api/v1/photos": pagedPhotosHandler(t, 5, 2, &requests),
+	})
+	defer server.Close()
+
+	pp, err := NewPhotoPrism(server.URL, "test", "test")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	uids, err := CollectPhotoUIDs(pp.IteratePhotos(context.Background(), "", IterateOptions{PageSize: 10}))
+	if err != nil {
+		t.Fatalf("expected retries to recover, got %v", err)
+	}
+	if len(uids) != 5 {
+		t.Errorf("expected 5 photos, got %d", len(uids))
+	}
+}
+
+func TestIteratePhotos_GivesUpAfterRetries(t *testing.T) {
+	withFastRetries(t)
+	var requests atomic.Int32
+	server := setupMockServerWithHandlers(t, map[string]http.HandlerFunc{
+		"/api/v1/photos": pagedPhotosHandler(t, 5, 100, &requests),
+	})
+	defer server.Close()
+
+	pp, err := NewPhotoPrism(server.URL, "test", "test")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if _, err := CollectPhotoUIDs(pp.IteratePhotos(context.Background(), "", IterateOptions{})); err == nil {
+		t.Fatal("expected error")
+	}
+	if requests.Load() != iterateRetries+1 {
+		t.Errorf("expected %d attempts, got %d", iterateRetries+1, requests.Load())
+	}
+}
+
+func TestIterateAlbumPhotos_Cancelled(t *testing.T) {
+	var requests atomic.Int32
+	server := setupMockServerWithHandlers(t, map[string]http.HandlerFunc{
+		"/api/v1/photos": pagedPhotosHandler(t, 100, 0, &requests),
+	})
+	defer server.Close()
+
+	pp, err := NewPhotoPrism(server.URL, "test", "test")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var gotErr error
+	for _, err := range pp.IterateAlbumPhotos(ctx, "album1", IterateOptions{PageSize: 10}) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		cancel()
+	}
+	if gotErr == nil {
+		t.Error("expected cancellation error")
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected no requests after cancel, got %d", requests.Load())
+	}
+}
@@ -0,0 +1,168 @@
+package photoprism
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// ErrLabelNotFound is returned by MergeLabels for an unknown label UID.
+var ErrLabelNotFound = errors.New("label not found")
+
+// MergeLabelsOptions configures MergeLabels.
+type MergeLabelsOptions struct {
+	DryRun bool // Report the affected photos without changing anything
+
+	// OnProgress, if set, is called after each photo is processed.
+	OnProgress func(done, total int, photoUID string)
+}
+
+// MergeLabelsResult holds the outcome of a label merge.
+type MergeLabelsResult struct {
+	Into         Label    `json:"into"`
+	From         []Label  `json:"from"`
+	PhotoUIDs    []string `json:"photo_uids"`     // photos carrying at least one source label
+	UpdatedCount int      `json:"updated_count"`  // photos rewritten (0 in dry-run mode)
+	Deleted      bool     `json:"labels_deleted"` // source labels were deleted
+	Errors       []string `json:"errors,omitempty"`
+}
+
+// photoLabelRef is a label assignment in the photo details response.
+type photoLabelRef struct {
+	LabelID     int `json:"LabelID"`
+	Uncertainty int `json:"Uncertainty"`
+	Label       struct {
+		UID string `json:"UID"`
+	} `json:"Label"`
+}
+
+// MergeLabels moves every photo from the source labels to the target label
+// and then deletes the source labels. Each affected photo gets the target
+// label with the highest uncertainty of the source labels it carried, after
+// which the source labels are removed from it. The source labels are only
+// deleted if every photo was rewritten, so a failed merge can be re-run.
+func (pp *PhotoPrism) MergeLabels(
+	fromUIDs []string, intoUID string, opts MergeLabelsOptions,
+) (*MergeLabelsResult, error) {
+	into, from, err := pp.resolveMergeLabels(fromUIDs, intoUID)
+	if err != nil {
+		return nil, err
+	}
+	result := &MergeLabelsResult{Into: into, From: from}
+
+	seen := make(map[string]bool)
+	for _, label := range from {
+		uids, err := CollectPhotoUIDs(pp.IteratePhotos(pp.context(), "label:"+label.Slug, IterateOptions{}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list photos of label %s: %w", label.Name, err)
+		}
+		for _, uid := range uids {
+			if !seen[uid] {
+				seen[uid] = true
+				result.PhotoUIDs = append(result.PhotoUIDs, uid)
+			}
+		}
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	sources := make(map[string]bool, len(from))
+	for _, label := range from {
+		sources[label.UID] = true
+	}
+	for i, photoUID := range result.PhotoUIDs {
+		if err := pp.relabelPhoto(photoUID, sources, into.Name); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", photoUID, err))
+		} else {
+			result.UpdatedCount++
+		}
+		if opts.OnProgress != nil {
+			opts.OnProgress(i+1, len(result.PhotoUIDs), photoUID)
+		}
+	}
+	if len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	if err := pp.DeleteLabels(fromUIDs); err != nil {
+		return result, fmt.Errorf("photos were relabeled but deleting the labels failed: %w", err)
+	}
+	result.Deleted = true
+	return result, nil
+}
+
+// resolveMergeLabels looks up the target and source labels by UID.
+func (pp *PhotoPrism) resolveMergeLabels(fromUIDs []string, intoUID string) (Label, []Label, error) {
+	if len(fromUIDs) == 0 {
+		return Label{}, nil, errors.New("no labels to merge")
+	}
+	if slices.Contains(fromUIDs, intoUID) {
+		return Label{}, nil, fmt.Errorf("label %s cannot be merged into itself", intoUID)
+	}
+	labels, err := pp.GetLabels(10000, 0, true)
+	if err != nil {
+		return Label{}, nil, fmt.Errorf("failed to get labels: %w", err)
+	}
+	byUID := make(map[string]Label, len(labels))
+	for _, l := range labels {
+		byUID[l.UID] = l
+	}
+
+	into, ok := byUID[intoUID]
+	if !ok {
+		return Label{}, nil, fmt.Errorf("%w: %s", ErrLabelNotFound, intoUID)
+	}
+	from := make([]Label, 0, len(fromUIDs))
+	for _, uid := range fromUIDs {
+		l, ok := byUID[uid]
+		if !ok {
+			return Label{}, nil, fmt.Errorf("%w: %s", ErrLabelNotFound, uid)
+		}
+		from = append(from, l)
+	}
+	return into, from, nil
+}
+
+// relabelPhoto adds the target label to a photo and removes the source labels.
+func (pp *PhotoPrism) relabelPhoto(photoUID string, sources map[string]bool, intoName string) error {
+	details, err := pp.GetPhotoDetails(photoUID)
+	if err != nil {
+		return fmt.Errorf("could not get photo details: %w", err)
+	}
+	data, err := json.Marshal(details["Labels"])
+	if err != nil {
+		return fmt.Errorf("could not read photo labels: %w", err)
+	}
+	var labels []photoLabelRef
+	if err := json.Unmarshal(data, &labels); err != nil {
+		return fmt.Errorf("could not read photo labels: %w", err)
+	}
+
+	var remove []int
+	uncertainty := 0
+	for _, l := range labels {
+		if sources[l.Label.UID] {
+			remove = append(remove, l.LabelID)
+			uncertainty = max(uncertainty, l.Uncertainty)
+		}
+	}
+	// The search can match related labels; nothing to do if the photo does
+	// not carry a source label itself.
+	if len(remove) == 0 {
+		return nil
+	}
+
+	_, err = pp.AddPhotoLabel(photoUID, PhotoLabel{Name: intoName, LabelSrc: "manual", Uncertainty: uncertainty})
+	if err != nil {
+		return fmt.Errorf("could not add label %s: %w", intoName, err)
+	}
+	for _, id := range remove {
+		if _, err := pp.RemovePhotoLabel(photoUID, strconv.Itoa(id)); err != nil {
+			return fmt.Errorf("could not remove label %d: %w", id, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package photoprism
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// labelMergeServer fakes the PhotoPrism endpoints used by MergeLabels.
+type labelMergeServer struct {
+	mu      sync.Mutex
+	added   map[string]PhotoLabel // photo UID -> added label
+	removed []string              // "photoUID/labelID"
+	deleted []string              // deleted label UIDs
+}
+
+func (s *labelMergeServer) handlers(t *testing.T) map[string]http.HandlerFunc {
+	t.Helper()
+	details := map[string]string{
+		"p1": `{"UID":"p1","Labels":[` +
+			`{"LabelID":1,"Uncertainty":10,"Label":{"UID":"lpes"}},` +
+			`{"LabelID":2,"Uncertainty":30,"Label":{"UID":"lpsi"}},` +
+			`{"LabelID":9,"Uncertainty":0,"Label":{"UID":"lother"}}]}`,
+		"p2": `{"UID":"p2","Labels":[{"LabelID":2,"Uncertainty":5,"Label":{"UID":"lpsi"}}]}`,
+		"p3": `{"UID":"p3","Labels":[{"LabelID":9,"Uncertainty":0,"Label":{"UID":"lother"}}]}`,
+	}
+	return map[string]http.HandlerFunc{
+		"GET /api/v1/labels": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`[{"UID":"ldog","Name":"Dog","Slug":"dog"},` +
+				`{"UID":"lpes","Name":"Pes","Slug":"pes"},{"UID":"lpsi","Name":"Psi","Slug":"psi"}]`))
+		},
+		"GET /api/v1/photos": func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Query().Get("q") {
+			case "label:pes":
+				w.Write([]byte(`[{"UID":"p1"}]`))
+			case "label:psi":
+				w.Write([]byte(`[{"UID":"p1"},{"UID":"p2"},{"UID":"p3"}]`))
+			default:
+				w.Write([]byte(`[]`))
+			}
+		},
+		"GET /api/v1/photos/{uid}": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(details[r.PathValue("uid")]))
+		},
+		"POST /api/v1/photos/{uid}/label": func(w http.ResponseWriter, r *http.Request) {
+			var label PhotoLabel
+			json.NewDecoder(r.Body).Decode(&label)
+			s.mu.Lock()
+			s.added[r.PathValue("uid")] = label
+			s.mu.Unlock()
+			w.Write([]byte(`{"UID":"` + r.PathValue("uid") + `"}`))
+		},
+		"DELETE /api/v1/photos/{uid}/label/{id}": func(w http.ResponseWriter, r *http.Request) {
+			s.mu.Lock()
+			s.removed = append(s.removed, r.PathValue("uid")+"/"+r.PathValue("id"))
+			s.mu.Unlock()
+			w.Write([]byte(`{"UID":"` + r.PathValue("uid") + `"}`))
+		},
+		"POST /api/v1/batch/labels/delete": func(w http.ResponseWriter, r *http.Request) {
+			var sel struct {
+				Labels []string `json:"labels"`
+			}
+			json.NewDecoder(r.Body).Decode(&sel)
+			s.mu.Lock()
+			s.deleted = sel.Labels
+			s.mu.Unlock()
+			w.Write([]byte(`{}`))
+		},
+	}
+}
+
+func TestMergeLabels(t *testing.T) {
+	fake := &labelMergeServer{added: make(map[string]PhotoLabel)}
+	server := setupMockServerWithHandlers(t, fake.handlers(t))
+	defer server.Close()
+
+	pp, err := NewPhotoPrism(server.URL, "test", "test")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	var progress []string
+	result, err := pp.MergeLabels([]string{"lpes", "lpsi"}, "ldog", MergeLabelsOptions{
+		OnProgress: func(done, total int, photoUID string) { progress = append(progress, photoUID) },
+	})
+	if err != nil {
+		t.Fatalf("MergeLabels failed: %v", err)
+	}
+
+	if !slices.Equal(result.PhotoUIDs, []string{"p1", "p2", "p3"}) {
+		t.Errorf("unexpected photos: %v", result.PhotoUIDs)
+	}
+	if result.UpdatedCount != 3 || !result.Deleted || len(result.Errors) != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(progress) != 3 {
+		t.Errorf("expected 3 progress callbacks, got %d", len(progress))
+	}
+	if got := fake.added["p1"]; got.Name != "Dog" || got.Uncertainty != 30 {
+		t.Errorf("expected Dog with max source uncertainty 30 on p1, got %+v", got)
+	}
+	if got := fake.added["p2"]; got.Uncertainty != 5 {
+		t.Errorf("expected uncertainty 5 on p2, got %+v", got)
+	}
+	if _, ok := fake.added["p3"]; ok {
+		t.Error("expected photo without a source label to be left alone")
+	}
+	slices.Sort(fake.removed)
+	if strings.Join(fake.removed, ",") != "p1/1,p1/2,p2/2" {
+		t.Errorf("unexpected removed labels: %v", fake.removed)
+	}
+	if !slices.Equal(fake.deleted, []string{"lpes", "lpsi"}) {
+		t.Errorf("expected source labels to be deleted, got %v", fake.deleted)
+	}
+}
+
+func TestMergeLabels_DryRun(t *testing.T) {
+	fake := &labelMergeServer{added: make(map[string]PhotoLabel)}
+	server := setupMockServerWithHandlers(t, fake.handlers(t))
+	defer server.Close()
+
+	pp, err := NewPhotoPrism(server.URL, "test", "test")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	result, err := pp.MergeLabels([]string{"lpes"}, "ldog", MergeLabelsOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("MergeLabels failed: %v", err)
+	}
+	if len(result.PhotoUIDs) != 1 || result.UpdatedCount != 0 || result.Deleted {
+		t.Errorf("unexpected dry-run result: %+v", result)
+	}
+	if len(fake.added) != 0 || len(fake.removed) != 0 || fake.deleted != nil {
+		t.Error("expected dry run not to change anything")
+	}
+}
+
+func TestMergeLabels_InvalidLabels(t *testing.T) {
+	fake := &labelMergeServer{added: make(map[string]PhotoLabel)}
+	server := setupMockServerWithHandlers(t, fake.handlers(t))
+	defer server.Close()
+
+	pp, err := NewPhotoPrism(server.URL, "test", "test")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	for name, tc := range map[string]struct {
+		from []string
+		into string
+	}{
+		"no sources":     {nil, "ldog"},
+		"into itself":    {[]string{"ldog"}, "ldog"},
+		"unknown target": {[]string{"lpes"}, "lmissing"},
+		"unknown source": {[]string{"lmissing"}, "ldog"},
+	} {
+		if _, err := pp.MergeLabels(tc.from, tc.into, MergeLabelsOptions{}); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
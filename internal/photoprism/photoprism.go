@@ -26,15 +26,21 @@ const (
 )
 
 // PhotoPrism represents a client for the PhotoPrism API.
+//
+// Requests are rate limited per PhotoPrism instance, idempotent requests are
+// retried after transient failures (see RetryPolicy), and clients created with
+// credentials log in again when the session token expires. Use WithContext to
+// bind requests to a context.
 type PhotoPrism struct {
 	Url           string
 	parsedURL     *url.URL
-	token         string
-	downloadToken string
-	userUID       string
+	session       *session
 	captureDir    string
 	httpClient    *http.Client
 	processClient *http.Client
+	ctx           context.Context //nolint:containedctx // set per view by WithContext
+	retry         RetryPolicy
+	limiter       *rateLimiter
 }
 
 // resolveURL builds a full URL from the base API URL and the given path segments.
@@ -160,6 +166,21 @@ func newHTTPClients() (*http.Client, *http.Client) {
 		&http.Client{Timeout: getProcessTimeout()}
 }
 
+// newClient creates an unauthenticated client for the PhotoPrism instance at rawURL.
+func newClient(rawURL string) (*PhotoPrism, error) {
+	apiURL := rawURL + "/api/v1"
+	parsed, err := url.Parse(apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PhotoPrism URL: %w", err)
+	}
+	httpClient, processClient := newHTTPClients()
+	return &PhotoPrism{
+		Url: apiURL, parsedURL: parsed, session: &session{},
+		httpClient: httpClient, processClient: processClient,
+		retry: DefaultRetryPolicy(), limiter: sharedLimiter(apiURL),
+	}, nil
+}
+
 // NewPhotoPrism creates a new PhotoPrism client.
 func NewPhotoPrism(url, username, password string) (*PhotoPrism, error) {
 	return NewPhotoPrismWithCapture(url, username, password, "")
@@ -168,19 +189,18 @@ func NewPhotoPrism(url, username, password string) (*PhotoPrism, error) {
 // NewPhotoPrismWithCapture creates a new PhotoPrism client with optional response capturing.
 // Pass an empty captureDir to disable capturing.
 func NewPhotoPrismWithCapture(rawURL, username, password, captureDir string) (*PhotoPrism, error) {
-	apiURL := rawURL + "/api/v1"
-	parsed, err := url.Parse(apiURL)
+	pp, err := newClient(rawURL)
 	if err != nil {
-		return nil, fmt.Errorf("invalid PhotoPrism URL: %w", err)
+		return nil, err
 	}
-	httpClient, processClient := newHTTPClients()
-	pp := &PhotoPrism{Url: apiURL, parsedURL: parsed, httpClient: httpClient, processClient: processClient}
 	if captureDir != "" {
 		if err := pp.SetCaptureDir(captureDir); err != nil {
 			return nil, err
 		}
 	}
-	if err := pp.auth(username, password); err != nil {
+	pp.session.username = username
+	pp.session.password = password
+	if err := pp.login(context.Background(), username, password); err != nil {
 		return nil, fmt.Errorf("could not authenticate: %w", err)
 	}
 
@@ -188,21 +208,18 @@ func NewPhotoPrismWithCapture(rawURL, username, password, captureDir string) (*P
 }
 
 // NewPhotoPrismFromToken creates a new PhotoPrism client from existing tokens.
+// Such a client cannot log in again once the token expires.
 func NewPhotoPrismFromToken(rawURL, token, downloadToken, userUID string) (*PhotoPrism, error) {
-	apiURL := rawURL + "/api/v1"
-	parsed, err := url.Parse(apiURL)
+	pp, err := newClient(rawURL)
 	if err != nil {
-		return nil, fmt.Errorf("invalid PhotoPrism URL: %w", err)
+		return nil, err
 	}
-	httpClient, processClient := newHTTPClients()
-	return &PhotoPrism{
-		Url: apiURL, parsedURL: parsed,
-		token: token, downloadToken: downloadToken, userUID: userUID,
-		httpClient: httpClient, processClient: processClient,
-	}, nil
+	pp.session.set(token, downloadToken, userUID)
+	return pp, nil
 }
 
-func (pp *PhotoPrism) auth(username, password string) error {
+// login creates a new session and stores its tokens.
+func (pp *PhotoPrism) login(ctx context.Context, username, password string) error {
 	inputBody, err := json.Marshal(map[string]string{
 		"username": username,
 		"password": password,
@@ -210,9 +227,12 @@ func (pp *PhotoPrism) auth(username, password string) error {
 	if err != nil {
 		return fmt.Errorf("could not marshal input: %w", err)
 	}
+	if err := pp.limiter.wait(ctx); err != nil {
+		return fmt.Errorf("request cancelled: %w", err)
+	}
 
 	req, err := http.NewRequestWithContext(
-		context.Background(), http.MethodPost,
+		ctx, http.MethodPost,
 		pp.resolveURL("sessions"), bytes.NewReader(inputBody),
 	)
 	if err != nil {
@@ -240,29 +260,22 @@ func (pp *PhotoPrism) auth(username, password string) error {
 		return fmt.Errorf("could not unmarshal response: %w", err)
 	}
 
-	pp.token = result.token
-	pp.downloadToken = result.config.downloadToken
-	pp.userUID = result.user.uid
+	pp.session.set(result.token, result.config.downloadToken, result.user.uid)
 
 	return nil
 }
 
 // Logout deletes the current session (logout).
 func (pp *PhotoPrism) Logout() error {
-	if pp.token == "" {
+	if pp.session.accessToken() == "" {
 		return nil // Already logged out
 	}
 
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodDelete, pp.resolveURL("session"), nil)
-	if err != nil {
-		return fmt.Errorf("could not create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+pp.token)
-
-	resp, err := pp.httpClient.Do(req)
+	resp, err := pp.send(pp.httpClient, true, func(ctx context.Context) (*http.Request, error) {
+		return pp.newRequest(ctx, http.MethodDelete, pp.resolveURL("session"), nil, "")
+	})
 	if err != nil {
-		return fmt.Errorf("could not send request: %w", err)
+		return err
 	}
 	defer resp.Body.Close()
 
@@ -270,8 +283,7 @@ func (pp *PhotoPrism) Logout() error {
 		return fmt.Errorf("logout failed with status %d: %s", resp.StatusCode, readErrorBody(resp.Body))
 	}
 
-	pp.token = ""
-	pp.downloadToken = ""
+	pp.session.set("", "", pp.session.user())
 
 	return nil
 }
@@ -78,16 +78,16 @@ func TestAuth(t *testing.T) {
 	}
 
 	// Verify tokens were parsed from session response.
-	if pp.token == "" {
+	if pp.session.token == "" {
 		t.Error("expected access token to be set")
 	}
 
-	if pp.downloadToken == "" {
+	if pp.session.downloadToken == "" {
 		t.Error("expected download token to be set")
 	}
 
-	if pp.downloadToken != "downloadtoken123" {
-		t.Errorf("expected downloadToken 'downloadtoken123', got '%s'", pp.downloadToken)
+	if pp.session.downloadToken != "downloadtoken123" {
+		t.Errorf("expected downloadToken 'downloadtoken123', got '%s'", pp.session.downloadToken)
 	}
 }
 
@@ -101,7 +101,7 @@ func TestLogout(t *testing.T) {
 	}
 
 	// Verify we have tokens.
-	if pp.token == "" {
+	if pp.session.token == "" {
 		t.Fatal("expected token to be set before logout")
 	}
 
@@ -112,12 +112,12 @@ func TestLogout(t *testing.T) {
 	}
 
 	// Verify tokens are cleared.
-	if pp.token != "" {
-		t.Errorf("expected token to be empty after logout, got '%s'", pp.token)
+	if pp.session.token != "" {
+		t.Errorf("expected token to be empty after logout, got '%s'", pp.session.token)
 	}
 
-	if pp.downloadToken != "" {
-		t.Errorf("expected downloadToken to be empty after logout, got '%s'", pp.downloadToken)
+	if pp.session.downloadToken != "" {
+		t.Errorf("expected downloadToken to be empty after logout, got '%s'", pp.session.downloadToken)
 	}
 
 	// Logout again should be no-op.
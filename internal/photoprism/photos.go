@@ -154,15 +154,13 @@ func (pp *PhotoPrism) GetPhotoDownload(photoUID string) ([]byte, string, error)
 //	// Save thumbnail to file
 //	err = os.WriteFile("thumbnail.jpg", data, 0644)
 func (pp *PhotoPrism) GetPhotoThumbnail(thumbHash string, size string) ([]byte, string, error) {
-	url := pp.resolveURL("t", thumbHash, pp.downloadToken, size)
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	resp, err := pp.send(pp.httpClient, true, func(ctx context.Context) (*http.Request, error) {
+		_, downloadToken := pp.session.tokens()
+		url := pp.resolveURL("t", thumbHash, downloadToken, size)
+		return http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	})
 	if err != nil {
-		return nil, "", fmt.Errorf("could not create request: %w", err)
-	}
-
-	resp, err := pp.httpClient.Do(req)
-	if err != nil {
-		return nil, "", fmt.Errorf("could not send request: %w", err)
+		return nil, "", err
 	}
 	defer resp.Body.Close()
 
@@ -182,20 +180,10 @@ func (pp *PhotoPrism) GetPhotoThumbnail(thumbHash string, size string) ([]byte,
 // GetFileDownload downloads a file using its hash via the /api/v1/dl/{hash} endpoint.
 // This endpoint may work differently than the photo download endpoint.
 func (pp *PhotoPrism) GetFileDownload(fileHash string) ([]byte, string, error) {
-	dlURL := pp.parsedURL.JoinPath("dl", fileHash)
-	q := dlURL.Query()
-	q.Set("t", pp.downloadToken)
-	dlURL.RawQuery = q.Encode()
-	url := dlURL.String()
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
-	if err != nil {
-		return nil, "", fmt.Errorf("could not create request: %w", err)
-	}
-
-	// Try without Authorization header first, as this endpoint might use token in URL.
-	resp, err := pp.httpClient.Do(req)
+	// The download endpoint uses the download token in the URL instead of an Authorization header.
+	resp, err := pp.sendFileDownload(fileHash)
 	if err != nil {
-		return nil, "", fmt.Errorf("could not send request: %w", err)
+		return nil, "", err
 	}
 	defer resp.Body.Close()
 
@@ -217,19 +205,9 @@ func (pp *PhotoPrism) GetFileDownload(fileHash string) ([]byte, string, error) {
 // HTTP response body). Useful for piping a large primary file directly to disk
 // via io.Copy without buffering the whole payload in memory.
 func (pp *PhotoPrism) GetFileDownloadStream(fileHash string) (io.ReadCloser, string, error) {
-	dlURL := pp.parsedURL.JoinPath("dl", fileHash)
-	q := dlURL.Query()
-	q.Set("t", pp.downloadToken)
-	dlURL.RawQuery = q.Encode()
-	url := dlURL.String()
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	resp, err := pp.sendFileDownload(fileHash)
 	if err != nil {
-		return nil, "", fmt.Errorf("could not create request: %w", err)
-	}
-
-	resp, err := pp.httpClient.Do(req)
-	if err != nil {
-		return nil, "", fmt.Errorf("could not send request: %w", err)
+		return nil, "", err
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -241,6 +219,19 @@ func (pp *PhotoPrism) GetFileDownloadStream(fileHash string) (io.ReadCloser, str
 	return resp.Body, resp.Header.Get("Content-Type"), nil
 }
 
+// sendFileDownload requests a file from the /api/v1/dl/{hash} endpoint.
+// The caller must close the response body.
+func (pp *PhotoPrism) sendFileDownload(fileHash string) (*http.Response, error) {
+	return pp.send(pp.httpClient, true, func(ctx context.Context) (*http.Request, error) {
+		_, downloadToken := pp.session.tokens()
+		dlURL := pp.parsedURL.JoinPath("dl", fileHash)
+		q := dlURL.Query()
+		q.Set("t", downloadToken)
+		dlURL.RawQuery = q.Encode()
+		return http.NewRequestWithContext(ctx, http.MethodGet, dlURL.String(), nil)
+	})
+}
+
 // GetPhotoDownloadStream is the streaming variant of GetPhotoDownload. The
 // caller owns the returned ReadCloser and MUST Close it. The same primary-file
 // resolution as GetPhotoDownload is performed.
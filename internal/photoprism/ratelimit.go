@@ -0,0 +1,107 @@
+package photoprism
+
+import (
+	"context"
+	"math"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// limiters holds one rate limiter per PhotoPrism API URL, so every client of
+// the same instance (CLI workers, web sessions, MCP) draws from one budget.
+var (
+	limitersMu sync.Mutex
+	limiters   = make(map[string]*rateLimiter)
+)
+
+// sharedLimiter returns the rate limiter for apiURL, creating it from the
+// PHOTOPRISM_RATE_LIMIT and PHOTOPRISM_RATE_BURST env vars on first use.
+func sharedLimiter(apiURL string) *rateLimiter {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	l, ok := limiters[apiURL]
+	if !ok {
+		l = &rateLimiter{}
+		l.configure(getRateLimit())
+		limiters[apiURL] = l
+	}
+	return l
+}
+
+// getRateLimit returns the requests per second from PHOTOPRISM_RATE_LIMIT
+// (0 = unlimited) and the burst size from PHOTOPRISM_RATE_BURST.
+func getRateLimit() (float64, int) {
+	var perSecond float64
+	if v := os.Getenv("PHOTOPRISM_RATE_LIMIT"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 {
+			perSecond = f
+		}
+	}
+	burst := 0
+	if v := os.Getenv("PHOTOPRISM_RATE_BURST"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			burst = n
+		}
+	}
+	return perSecond, burst
+}
+
+// rateLimiter is a token bucket. Waiting callers reserve a token up front, so
+// they are served in the order they arrived.
+type rateLimiter struct {
+	mu        sync.Mutex
+	perSecond float64
+	burst     float64
+	tokens    float64 // negative when callers are waiting for reserved tokens
+	last      time.Time
+}
+
+// configure sets the rate and burst size. A burst below 1 defaults to one
+// second worth of requests.
+func (l *rateLimiter) configure(perSecond float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.perSecond = perSecond
+	l.burst = float64(burst)
+	if l.burst < 1 {
+		l.burst = math.Max(1, math.Ceil(perSecond))
+	}
+	l.tokens = l.burst
+	l.last = time.Now()
+}
+
+// wait blocks until a request may be sent or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+	l.mu.Lock()
+	if l.perSecond <= 0 {
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.perSecond)
+	l.last = now
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.perSecond * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		// Hand the reserved token back to the callers behind us.
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
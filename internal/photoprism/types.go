@@ -53,6 +53,9 @@ type Photo struct {
 	CameraModel  string  `json:"CameraModel"`  // Camera model name
 	Scan         bool    `json:"Scan"`         // True if photo was scanned
 	DeletedAt    string  `json:"DeletedAt"`    // Non-empty if soft-deleted/archived
+	CreatedAt    string  `json:"CreatedAt"`    // When the photo was added to PhotoPrism
+	UpdatedAt    string  `json:"UpdatedAt"`    // Last change of the photo record
+	EditedAt     string  `json:"EditedAt"`     // Last manual edit; empty if never edited
 }
 
 // PhotoDetails represents additional photo details like notes.
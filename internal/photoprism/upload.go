@@ -3,6 +3,7 @@ package photoprism
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -17,7 +18,8 @@ import (
 // UploadFile uploads a single file to the user's upload folder.
 // Returns the upload token used for processing.
 func (pp *PhotoPrism) UploadFile(filePath string) (string, error) {
-	if pp.userUID == "" {
+	userUID := pp.session.user()
+	if userUID == "" {
 		return "", errors.New("user UID not available")
 	}
 
@@ -51,18 +53,12 @@ func (pp *PhotoPrism) UploadFile(filePath string) (string, error) {
 	}
 
 	// Send request.
-	url := pp.resolveURL("users", pp.userUID, "upload", uploadToken)
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, &body)
+	url := pp.resolveURL("users", userUID, "upload", uploadToken)
+	resp, err := pp.send(pp.httpClient, false, func(ctx context.Context) (*http.Request, error) {
+		return pp.newRequest(ctx, http.MethodPost, url, body.Bytes(), writer.FormDataContentType())
+	})
 	if err != nil {
-		return "", fmt.Errorf("could not create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+pp.token)
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	resp, err := pp.httpClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("could not send request: %w", err)
+		return "", err
 	}
 	defer resp.Body.Close()
 
@@ -97,7 +93,8 @@ func addFileToMultipart(writer *multipart.Writer, filePath string) error {
 // UploadFiles uploads multiple files to the user's upload folder.
 // Returns the upload token used for processing.
 func (pp *PhotoPrism) UploadFiles(filePaths []string) (string, error) {
-	if pp.userUID == "" {
+	userUID := pp.session.user()
+	if userUID == "" {
 		return "", errors.New("user UID not available")
 	}
 
@@ -124,18 +121,12 @@ func (pp *PhotoPrism) UploadFiles(filePaths []string) (string, error) {
 	}
 
 	// Send request.
-	url := pp.resolveURL("users", pp.userUID, "upload", uploadToken)
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, &body)
-	if err != nil {
-		return "", fmt.Errorf("could not create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+pp.token)
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	resp, err := pp.httpClient.Do(req)
+	url := pp.resolveURL("users", userUID, "upload", uploadToken)
+	resp, err := pp.send(pp.httpClient, false, func(ctx context.Context) (*http.Request, error) {
+		return pp.newRequest(ctx, http.MethodPost, url, body.Bytes(), writer.FormDataContentType())
+	})
 	if err != nil {
-		return "", fmt.Errorf("could not send request: %w", err)
+		return "", err
 	}
 	defer resp.Body.Close()
 
@@ -150,7 +141,8 @@ func (pp *PhotoPrism) UploadFiles(filePaths []string) (string, error) {
 // ProcessUpload processes previously uploaded files and optionally adds them to albums.
 // Uses a longer HTTP timeout since PhotoPrism's import+indexing pipeline can be slow.
 func (pp *PhotoPrism) ProcessUpload(uploadToken string, albumUIDs []string) error {
-	if pp.userUID == "" {
+	userUID := pp.session.user()
+	if userUID == "" {
 		return errors.New("user UID not available")
 	}
 
@@ -160,6 +152,25 @@ func (pp *PhotoPrism) ProcessUpload(uploadToken string, albumUIDs []string) erro
 		Albums: albumUIDs,
 	}
 
-	endpoint := fmt.Sprintf("users/%s/upload/%s", pp.userUID, uploadToken)
-	return doRequestRawWithClient(pp, pp.processClient, "PUT", endpoint, options)
+	body, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("could not marshal request body: %w", err)
+	}
+
+	// Processing is not retried: a gateway timeout does not mean PhotoPrism
+	// stopped importing, and a second run would import the files again.
+	url := pp.resolveURL("users", userUID, "upload", uploadToken)
+	resp, err := pp.send(pp.processClient, false, func(ctx context.Context) (*http.Request, error) {
+		return pp.newRequest(ctx, http.MethodPut, url, body, "application/json")
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, readErrorBody(resp.Body))
+	}
+
+	return nil
 }
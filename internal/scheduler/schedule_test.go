@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@yearly",
+		"@every",
+		"@every soon",
+		"@every 10s",
+	}
+	for _, spec := range specs {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q): expected error", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	base := time.Date(2024, time.March, 15, 10, 17, 42, 0, time.Local) // a Friday
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.March, 15, 10, 18, 0, 0, time.Local)},
+		{"*/15 * * * *", time.Date(2024, time.March, 15, 10, 30, 0, 0, time.Local)},
+		{"0 3 * * *", time.Date(2024, time.March, 16, 3, 0, 0, 0, time.Local)},
+		{"30 9-17 * * 1-5", time.Date(2024, time.March, 15, 10, 30, 0, 0, time.Local)},
+		{"0 0 * * 0", time.Date(2024, time.March, 17, 0, 0, 0, 0, time.Local)},
+		{"0 0 * * 7", time.Date(2024, time.March, 17, 0, 0, 0, 0, time.Local)},
+		{"0 12 1,20 * *", time.Date(2024, time.March, 20, 12, 0, 0, 0, time.Local)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.Local)},
+		{"5/20 * * * *", time.Date(2024, time.March, 15, 10, 25, 0, 0, time.Local)},
+		// Restricted day-of-month and day-of-week match either.
+		{"0 0 1 * 6", time.Date(2024, time.March, 16, 0, 0, 0, 0, time.Local)},
+		{"@hourly", time.Date(2024, time.March, 15, 11, 0, 0, 0, time.Local)},
+		{"@daily", time.Date(2024, time.March, 16, 0, 0, 0, 0, time.Local)},
+		{"@monthly", time.Date(2024, time.April, 1, 0, 0, 0, 0, time.Local)},
+		{"@every 90m", time.Date(2024, time.March, 15, 11, 47, 42, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		s, err := Parse(tt.spec)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tt.spec, err)
+		}
+		if got := s.Next(base); !got.Equal(tt.want) {
+			t.Errorf("%q: Next = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestNext_Impossible(t *testing.T) {
+	s, err := Parse("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got := s.Next(time.Now()); !got.IsZero() {
+		t.Errorf("expected zero time for impossible schedule, got %v", got)
+	}
+}
@@ -0,0 +1,198 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Task is the work run by a Scheduler. The returned result is exposed in the
+// status of the run. The context is cancelled when the scheduler stops.
+type Task func(ctx context.Context) (any, error)
+
+// RunStatus is the outcome of a task run.
+type RunStatus string
+
+// RunStatus constants define the states of a task run.
+const (
+	RunStatusRunning   RunStatus = "running"
+	RunStatusCompleted RunStatus = "completed"
+	RunStatusFailed    RunStatus = "failed"
+)
+
+// Run describes a single execution of the task.
+type Run struct {
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Status      RunStatus  `json:"status"`
+	Trigger     string     `json:"trigger"` // "schedule" or "manual"
+	Error       string     `json:"error,omitempty"`
+	Result      any        `json:"result,omitempty"`
+}
+
+// Status is a snapshot of the scheduler state.
+type Status struct {
+	Schedule string     `json:"schedule"`
+	Running  bool       `json:"running"`
+	NextRun  *time.Time `json:"next_run,omitempty"`
+	LastRun  *Run       `json:"last_run,omitempty"`
+}
+
+// Scheduler runs a task on a schedule, one run at a time. Activations that
+// fall while the task is still running are skipped.
+type Scheduler struct {
+	spec     string
+	schedule Schedule
+	task     Task
+
+	mu      sync.Mutex
+	running bool
+	nextRun time.Time
+	lastRun *Run
+
+	trigger chan struct{}
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// New creates a scheduler running task according to spec (see Parse).
+func New(spec string, task Task) (*Scheduler, error) {
+	schedule, err := Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &Scheduler{
+		spec:     spec,
+		schedule: schedule,
+		task:     task,
+		trigger:  make(chan struct{}, 1),
+	}, nil
+}
+
+// Start starts the scheduling loop in the background. It returns
+// immediately; call Stop to end the loop.
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	s.mu.Lock()
+	s.cancel = cancel
+	s.mu.Unlock()
+	s.wg.Go(func() { s.loop(ctx) })
+}
+
+// Stop cancels a running task and waits for the scheduling loop to exit.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	s.wg.Wait()
+}
+
+// Trigger requests an immediate run. It returns false if a run is already
+// in progress or pending.
+func (s *Scheduler) Trigger() bool {
+	s.mu.Lock()
+	running := s.running
+	s.mu.Unlock()
+	if running {
+		return false
+	}
+	select {
+	case s.trigger <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Status returns a snapshot of the scheduler state.
+func (s *Scheduler) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := Status{Schedule: s.spec, Running: s.running}
+	if !s.nextRun.IsZero() {
+		next := s.nextRun
+		status.NextRun = &next
+	}
+	if s.lastRun != nil {
+		run := *s.lastRun
+		status.LastRun = &run
+	}
+	return status
+}
+
+func (s *Scheduler) loop(ctx context.Context) {
+	for {
+		next := s.schedule.Next(time.Now())
+		s.mu.Lock()
+		s.nextRun = next
+		s.mu.Unlock()
+
+		// A schedule that never fires again leaves only manual triggers.
+		var timer *time.Timer
+		var timerC <-chan time.Time
+		if !next.IsZero() {
+			timer = time.NewTimer(time.Until(next))
+			timerC = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			stopTimer(timer)
+			return
+		case <-timerC:
+			s.run(ctx, "schedule")
+		case <-s.trigger:
+			stopTimer(timer)
+			s.run(ctx, "manual")
+		}
+	}
+}
+
+func stopTimer(t *time.Timer) {
+	if t != nil {
+		t.Stop()
+	}
+}
+
+// run executes the task once and records the outcome.
+func (s *Scheduler) run(ctx context.Context, trigger string) {
+	run := &Run{StartedAt: time.Now(), Status: RunStatusRunning, Trigger: trigger}
+	s.mu.Lock()
+	s.running = true
+	s.lastRun = run
+	s.mu.Unlock()
+
+	log.Printf("Scheduler: starting %s run", trigger)
+	result, err := s.safeRun(ctx)
+
+	completed := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = false
+	run.CompletedAt = &completed
+	run.Result = result
+	if err != nil {
+		run.Status = RunStatusFailed
+		run.Error = err.Error()
+		log.Printf("Scheduler: run failed after %s: %v", completed.Sub(run.StartedAt).Round(time.Second), err)
+		return
+	}
+	run.Status = RunStatusCompleted
+	log.Printf("Scheduler: run completed in %s", completed.Sub(run.StartedAt).Round(time.Second))
+}
+
+// safeRun calls the task, turning a panic into an error so a failing run
+// does not take down the server.
+func (s *Scheduler) safeRun(ctx context.Context) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task panicked: %v", r)
+		}
+	}()
+	return s.task(ctx)
+}
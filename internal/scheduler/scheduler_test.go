@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitFor polls the scheduler until cond holds or the test times out.
+func waitFor(t *testing.T, s *Scheduler, cond func(Status) bool) Status {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if status := s.Status(); cond(status) {
+			return status
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for scheduler, status: %+v", s.Status())
+	return Status{}
+}
+
+func TestScheduler_Trigger(t *testing.T) {
+	release := make(chan struct{})
+	s, err := New("@daily", func(context.Context) (any, error) {
+		<-release
+		return map[string]int{"processed": 3}, nil
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	s.Start(context.Background())
+	defer s.Stop()
+
+	status := waitFor(t, s, func(st Status) bool { return st.NextRun != nil })
+	if status.Schedule != "@daily" || status.LastRun != nil {
+		t.Fatalf("unexpected initial status: %+v", status)
+	}
+
+	if !s.Trigger() {
+		t.Fatal("expected trigger to be accepted")
+	}
+	waitFor(t, s, func(st Status) bool { return st.Running })
+	if s.Trigger() {
+		t.Error("expected trigger to be rejected while running")
+	}
+
+	close(release)
+	status = waitFor(t, s, func(st Status) bool { return !st.Running })
+	run := status.LastRun
+	if run == nil || run.Status != RunStatusCompleted || run.Trigger != "manual" || run.CompletedAt == nil {
+		t.Fatalf("unexpected last run: %+v", run)
+	}
+	if result, ok := run.Result.(map[string]int); !ok || result["processed"] != 3 {
+		t.Errorf("unexpected result: %v", run.Result)
+	}
+}
+
+func TestScheduler_FailureAndPanic(t *testing.T) {
+	calls := make(chan int, 2)
+	n := 0
+	s, err := New("@every 1h", func(context.Context) (any, error) {
+		n++
+		calls <- n
+		if n == 1 {
+			return nil, errors.New("photoprism unreachable")
+		}
+		panic("boom")
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	s.Start(context.Background())
+	defer s.Stop()
+
+	s.Trigger()
+	<-calls
+	status := waitFor(t, s, func(st Status) bool { return st.LastRun != nil && !st.Running })
+	if status.LastRun.Status != RunStatusFailed || status.LastRun.Error != "photoprism unreachable" {
+		t.Errorf("unexpected run after error: %+v", status.LastRun)
+	}
+
+	s.Trigger()
+	<-calls
+	status = waitFor(t, s, func(st Status) bool { return st.LastRun.Error != "photoprism unreachable" && !st.Running })
+	if status.LastRun.Status != RunStatusFailed || status.LastRun.Error != "task panicked: boom" {
+		t.Errorf("unexpected run after panic: %+v", status.LastRun)
+	}
+}
+
+func TestScheduler_StopCancelsRun(t *testing.T) {
+	started := make(chan struct{})
+	s, err := New("@hourly", func(ctx context.Context) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	s.Start(context.Background())
+	s.Trigger()
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not cancel the running task")
+	}
+	if run := s.Status().LastRun; run == nil || run.Status != RunStatusFailed {
+		t.Errorf("expected cancelled run to be recorded as failed, got %+v", run)
+	}
+}
@@ -0,0 +1,105 @@
+package sorter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kozaktomas/photo-sorter/internal/ai"
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+// cacheScope holds the parts of the analysis cache key that are fixed for a
+// whole sort run, so only the image hash has to be computed per photo.
+// A nil *cacheScope means caching is disabled; all its methods are no-ops.
+type cacheScope struct {
+	cache         database.AnalysisCache
+	provider      string
+	model         string
+	promptVersion string
+	labelsHash    string
+}
+
+// newCacheScope returns the cache scope for a run, or nil if opts.Cache is not set.
+func (s *Sorter) newCacheScope(availableLabels []string, opts SortOptions) *cacheScope {
+	if opts.Cache == nil {
+		return nil
+	}
+	promptVersion := ai.PhotoAnalysisPromptVersion(opts.IndividualDates)
+	if opts.ForceDate {
+		// ForceDate hides the photo's current date from the model, which
+		// changes the request even though the prompt template is the same.
+		promptVersion += "-nodate"
+	}
+	return &cacheScope{
+		cache:         opts.Cache,
+		provider:      strings.TrimPrefix(fmt.Sprintf("%T", s.aiProvider), "*"),
+		model:         s.aiProvider.Name(),
+		promptVersion: promptVersion,
+		labelsHash:    hashLabels(availableLabels),
+	}
+}
+
+// hashLabels returns a hash of the label set that does not depend on order.
+func hashLabels(labels []string) string {
+	sorted := slices.Clone(labels)
+	slices.Sort(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// entry builds the cache entry (without analysis) for an image.
+func (c *cacheScope) entry(imageData []byte) *database.CachedAnalysis {
+	if c == nil {
+		return nil
+	}
+	imageSum := sha256.Sum256(imageData)
+	imageHash := hex.EncodeToString(imageSum[:])
+	keySum := sha256.Sum256([]byte(strings.Join(
+		[]string{imageHash, c.provider, c.model, c.promptVersion, c.labelsHash}, "\x00",
+	)))
+	return &database.CachedAnalysis{
+		Key:           hex.EncodeToString(keySum[:]),
+		ImageHash:     imageHash,
+		Provider:      c.provider,
+		Model:         c.model,
+		PromptVersion: c.promptVersion,
+		LabelsHash:    c.labelsHash,
+	}
+}
+
+// lookup returns the cached analysis for entry, or nil on a miss. The cache
+// is best-effort: lookup errors are treated as misses.
+func (c *cacheScope) lookup(ctx context.Context, entry *database.CachedAnalysis) *ai.PhotoAnalysis {
+	if c == nil || entry == nil {
+		return nil
+	}
+	cached, err := c.cache.GetCachedAnalysis(ctx, entry.Key)
+	if err != nil || cached == nil {
+		return nil
+	}
+	var analysis ai.PhotoAnalysis
+	if err := json.Unmarshal(cached.Analysis, &analysis); err != nil {
+		return nil
+	}
+	return &analysis
+}
+
+// store saves an analysis under entry's key. Failures are ignored; the
+// analysis will simply be requested again next time.
+func (c *cacheScope) store(ctx context.Context, entry *database.CachedAnalysis, analysis *ai.PhotoAnalysis) {
+	if c == nil || entry == nil || analysis == nil {
+		return
+	}
+	data, err := json.Marshal(analysis)
+	if err != nil {
+		return
+	}
+	stored := *entry
+	stored.Analysis = data
+	_ = c.cache.SaveCachedAnalysis(ctx, &stored)
+}
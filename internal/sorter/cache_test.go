@@ -0,0 +1,107 @@
+package sorter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kozaktomas/photo-sorter/internal/ai"
+	"github.com/kozaktomas/photo-sorter/internal/database/mock"
+)
+
+func newTestCacheScope(cache *mock.MockAnalysisCache, model string, labels []string) *cacheScope {
+	return &cacheScope{
+		cache:         cache,
+		provider:      "ai.OpenAIProvider",
+		model:         model,
+		promptVersion: ai.PhotoAnalysisPromptVersion(false),
+		labelsHash:    hashLabels(labels),
+	}
+}
+
+func TestCacheScope_KeyDerivation(t *testing.T) {
+	image := []byte("image bytes")
+	base := newTestCacheScope(nil, "gpt-4.1-mini", []string{"beach", "mountain"}).entry(image)
+
+	reordered := newTestCacheScope(nil, "gpt-4.1-mini", []string{"mountain", "beach"}).entry(image)
+	if reordered.Key != base.Key {
+		t.Error("expected label order not to affect the key")
+	}
+
+	otherModel := newTestCacheScope(nil, "gpt-4o", []string{"beach", "mountain"}).entry(image)
+	otherLabels := newTestCacheScope(nil, "gpt-4.1-mini", []string{"beach"}).entry(image)
+	otherImage := newTestCacheScope(nil, "gpt-4.1-mini", []string{"beach", "mountain"}).entry([]byte("other"))
+	otherPrompt := newTestCacheScope(nil, "gpt-4.1-mini", []string{"beach", "mountain"})
+	otherPrompt.promptVersion = ai.PhotoAnalysisPromptVersion(true)
+
+	for name, key := range map[string]string{
+		"model":  otherModel.Key,
+		"labels": otherLabels.Key,
+		"image":  otherImage.Key,
+		"prompt": otherPrompt.entry(image).Key,
+	} {
+		if key == base.Key {
+			t.Errorf("expected a different %s to change the key", name)
+		}
+	}
+	if len(base.Key) != 64 || len(base.ImageHash) != 64 {
+		t.Errorf("expected hex sha256 key and image hash, got %q / %q", base.Key, base.ImageHash)
+	}
+}
+
+func TestCacheScope_LookupAndStore(t *testing.T) {
+	ctx := context.Background()
+	cache := mock.NewMockAnalysisCache()
+	scope := newTestCacheScope(cache, "gpt-4.1-mini", []string{"beach"})
+	entry := scope.entry([]byte("image"))
+
+	if got := scope.lookup(ctx, entry); got != nil {
+		t.Fatalf("expected miss on empty cache, got %+v", got)
+	}
+
+	scope.store(ctx, entry, &ai.PhotoAnalysis{
+		Labels:      []ai.LabelWithConfidence{{Name: "beach", Confidence: 0.9}},
+		Description: "A beach",
+	})
+	got := scope.lookup(ctx, entry)
+	if got == nil {
+		t.Fatal("expected hit after store")
+	}
+	if got.Description != "A beach" || len(got.Labels) != 1 || got.Labels[0].Name != "beach" {
+		t.Errorf("unexpected cached analysis: %+v", got)
+	}
+	if entry.Analysis != nil {
+		t.Error("expected store not to modify the entry")
+	}
+}
+
+func TestCacheScope_ErrorsAreMisses(t *testing.T) {
+	ctx := context.Background()
+	cache := mock.NewMockAnalysisCache()
+	cache.SaveCachedAnalysisError = errors.New("db down")
+	cache.GetCachedAnalysisError = errors.New("db down")
+	scope := newTestCacheScope(cache, "gpt-4.1-mini", nil)
+	entry := scope.entry([]byte("image"))
+
+	scope.store(ctx, entry, &ai.PhotoAnalysis{Description: "ignored"})
+	if got := scope.lookup(ctx, entry); got != nil {
+		t.Errorf("expected cache errors to be treated as misses, got %+v", got)
+	}
+}
+
+func TestCacheScope_NilDisablesCache(t *testing.T) {
+	var scope *cacheScope
+	entry := scope.entry([]byte("image"))
+	if entry != nil {
+		t.Errorf("expected nil entry, got %+v", entry)
+	}
+	scope.store(context.Background(), entry, &ai.PhotoAnalysis{})
+	if got := scope.lookup(context.Background(), entry); got != nil {
+		t.Errorf("expected nil analysis, got %+v", got)
+	}
+
+	s := New(nil, nil)
+	if s.newCacheScope([]string{"beach"}, SortOptions{}) != nil {
+		t.Error("expected no cache scope without opts.Cache")
+	}
+}
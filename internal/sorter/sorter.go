@@ -9,7 +9,9 @@ import (
 	"time"
 
 	"github.com/kozaktomas/photo-sorter/internal/ai"
+	"github.com/kozaktomas/photo-sorter/internal/database"
 	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+	"github.com/kozaktomas/photo-sorter/internal/taxonomy"
 	"github.com/schollz/progressbar/v3"
 )
 
@@ -37,12 +39,36 @@ type SortOptions struct {
 	ForceDate       bool               // Overwrite existing dates with AI estimates
 	Concurrency     int                // Number of parallel requests in standard mode
 	OnProgress      func(ProgressInfo) // Optional progress callback for web UI
+
+	// OnBeforeApply, if set, receives each photo's state right before the
+	// sorter changes it, e.g. to record an undo log. Returning an error skips
+	// the photo so no change is ever applied without its snapshot.
+	OnBeforeApply func(PhotoSnapshot) error
+
+	// ResumeBatchID, in batch mode, waits for an already submitted batch
+	// instead of creating a new one, e.g. after the client was disconnected.
+	ResumeBatchID string
+
+	// KeepBatchOnCancel, in batch mode, leaves the batch running when ctx is
+	// cancelled so the caller can resume it with ResumeBatchID later. By
+	// default a cancelled sort cancels its batch.
+	KeepBatchOnCancel bool
+
+	// Cache, if set, is consulted before each AI analysis and receives every
+	// new analysis. Nil disables caching.
+	Cache database.AnalysisCache
+
+	// Taxonomy, if set, maps the existing labels offered to the AI and the
+	// suggested labels onto canonical names and adds parent labels before
+	// anything is written to PhotoPrism.
+	Taxonomy *taxonomy.Taxonomy
 }
 
 // SortResult holds the outcome of a sort operation.
 type SortResult struct {
 	ProcessedCount int
 	SortedCount    int
+	CacheHits      int // photos whose analysis came from the cache
 	AlbumDate      string
 	DateReasoning  string
 	Errors         []error
@@ -97,11 +123,13 @@ func (s *Sorter) Sort(
 type photoResult struct {
 	index      int
 	suggestion *ai.SortSuggestion
+	cached     bool // analysis came from the cache
 	err        error
 }
 
 // fetchLabelsAndPhotos fetches available labels and album photos for sorting.
-func (s *Sorter) fetchLabelsAndPhotos(albumUID string, limit int) ([]string, []photoprism.Photo, error) {
+// With a taxonomy the labels are replaced by its vocabulary.
+func (s *Sorter) fetchLabelsAndPhotos(albumUID string, opts SortOptions) ([]string, []photoprism.Photo, error) {
 	labels, err := s.photoprism.GetLabels(10000, 0, true)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to fetch labels: %w", err)
@@ -110,6 +138,10 @@ func (s *Sorter) fetchLabelsAndPhotos(albumUID string, limit int) ([]string, []p
 	for i, label := range labels {
 		availableLabels[i] = label.Name
 	}
+	if opts.Taxonomy != nil {
+		availableLabels = opts.Taxonomy.Vocabulary(availableLabels)
+	}
+	limit := opts.Limit
 	if limit == 0 {
 		limit = 10000
 	}
@@ -141,31 +173,38 @@ func newAnalysisProgressBar(total, concurrency int) *progressbar.ProgressBar {
 
 func (s *Sorter) analyzeOnePhoto(
 	ctx context.Context, idx int, p photoprism.Photo,
-	availableLabels []string, opts SortOptions,
+	availableLabels []string, opts SortOptions, cache *cacheScope,
 ) photoResult {
 	imageData, _, err := s.photoprism.GetPhotoDownload(p.UID)
 	if err != nil {
 		return photoResult{index: idx, err: fmt.Errorf("failed to download photo %s: %w", p.UID, err)}
 	}
 
-	metadata := photoToMetadata(p, opts.ForceDate)
-	analysis, err := s.aiProvider.AnalyzePhoto(ctx, imageData, metadata, availableLabels, opts.IndividualDates)
-	if err != nil {
-		return photoResult{index: idx, err: fmt.Errorf("failed to analyze photo %s: %w", p.UID, err)}
+	entry := cache.entry(imageData)
+	analysis := cache.lookup(ctx, entry)
+	cached := analysis != nil
+	if !cached {
+		metadata := photoToMetadata(p, opts.ForceDate)
+		analysis, err = s.aiProvider.AnalyzePhoto(ctx, imageData, metadata, availableLabels, opts.IndividualDates)
+		if err != nil {
+			return photoResult{index: idx, err: fmt.Errorf("failed to analyze photo %s: %w", p.UID, err)}
+		}
+		cache.store(ctx, entry, analysis)
 	}
 
-	return photoResult{index: idx, suggestion: &ai.SortSuggestion{
+	return photoResult{index: idx, cached: cached, suggestion: &ai.SortSuggestion{
 		PhotoUID:      p.UID,
 		Labels:        analysis.Labels,
 		Description:   analysis.Description,
 		EstimatedDate: analysis.EstimatedDate,
+		Consensus:     analysis.Consensus,
 	}}
 }
 
 // analyzePhotosParallel downloads and analyzes photos concurrently, returning ordered results.
 func (s *Sorter) analyzePhotosParallel(
 	ctx context.Context, photos []photoprism.Photo,
-	availableLabels []string, opts SortOptions,
+	availableLabels []string, opts SortOptions, cache *cacheScope,
 ) []*photoResult {
 	concurrency := opts.Concurrency
 	if concurrency <= 0 {
@@ -206,7 +245,7 @@ func (s *Sorter) analyzePhotosParallel(
 			if ctx.Err() != nil {
 				resultsChan <- photoResult{index: idx, err: ctx.Err()}
 			} else {
-				resultsChan <- s.analyzeOnePhoto(ctx, idx, p, availableLabels, opts)
+				resultsChan <- s.analyzeOnePhoto(ctx, idx, p, availableLabels, opts, cache)
 			}
 			reportProgress(p.UID)
 		}(i, photos[i])
@@ -238,6 +277,9 @@ func collectSuggestions(results []*photoResult, sortResult *SortResult) []string
 			sortResult.Errors = append(sortResult.Errors, r.err)
 			continue
 		}
+		if r.cached {
+			sortResult.CacheHits++
+		}
 		if r.suggestion != nil {
 			photoDescriptions = append(photoDescriptions, r.suggestion.Description)
 			sortResult.Suggestions = append(sortResult.Suggestions, *r.suggestion)
@@ -246,6 +288,16 @@ func collectSuggestions(results []*photoResult, sortResult *SortResult) []string
 	return photoDescriptions
 }
 
+// normalizeSuggestions maps the suggested labels through the taxonomy.
+func normalizeSuggestions(result *SortResult, tax *taxonomy.Taxonomy) {
+	if tax == nil {
+		return
+	}
+	for i := range result.Suggestions {
+		result.Suggestions[i].Labels = tax.Normalize(result.Suggestions[i].Labels)
+	}
+}
+
 // estimateAndApplyAlbumDate estimates album date and applies it to all suggestions.
 func (s *Sorter) estimateAndApplyAlbumDate(
 	ctx context.Context, result *SortResult,
@@ -264,14 +316,14 @@ func (s *Sorter) estimateAndApplyAlbumDate(
 }
 
 // applySuggestions applies sorting suggestions to photos if not dry run.
-func (s *Sorter) applySuggestions(result *SortResult, photoMap map[string]photoprism.Photo, forceDate bool) {
+func (s *Sorter) applySuggestions(result *SortResult, photoMap map[string]photoprism.Photo, opts SortOptions) {
 	for _, suggestion := range result.Suggestions {
 		photo, ok := photoMap[suggestion.PhotoUID]
 		if !ok {
 			result.Errors = append(result.Errors, fmt.Errorf("photo not found: %s", suggestion.PhotoUID))
 			continue
 		}
-		if err := s.applySorting(photo, suggestion, forceDate); err != nil {
+		if err := s.applyWithSnapshot(photo, suggestion, opts); err != nil {
 			result.Errors = append(result.Errors, fmt.Errorf("failed to apply sorting for %s: %w", photo.UID, err))
 			continue
 		}
@@ -285,13 +337,15 @@ func (s *Sorter) sortImmediate(
 ) (*SortResult, error) {
 	result := &SortResult{}
 
-	availableLabels, photos, err := s.fetchLabelsAndPhotos(albumUID, opts.Limit)
+	availableLabels, photos, err := s.fetchLabelsAndPhotos(albumUID, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	results := s.analyzePhotosParallel(ctx, photos, availableLabels, opts)
+	cache := s.newCacheScope(availableLabels, opts)
+	results := s.analyzePhotosParallel(ctx, photos, availableLabels, opts, cache)
 	photoDescriptions := collectSuggestions(results, result)
+	normalizeSuggestions(result, opts.Taxonomy)
 
 	if !opts.IndividualDates && len(photoDescriptions) > 0 {
 		s.estimateAndApplyAlbumDate(ctx, result, albumTitle, albumDescription, photoDescriptions)
@@ -302,7 +356,7 @@ func (s *Sorter) sortImmediate(
 		for i := range photos {
 			photoMap[photos[i].UID] = photos[i]
 		}
-		s.applySuggestions(result, photoMap, opts.ForceDate)
+		s.applySuggestions(result, photoMap, opts)
 	} else {
 		result.SortedCount = len(result.Suggestions)
 	}
@@ -310,11 +364,20 @@ func (s *Sorter) sortImmediate(
 	return result, nil
 }
 
-// downloadBatchPhotos downloads photos and prepares batch requests.
+// batchInput is the outcome of downloading photos for a batch run.
+type batchInput struct {
+	requests []ai.BatchPhotoRequest
+	photoMap map[string]photoprism.Photo
+	cached   []ai.BatchPhotoResult               // photos answered from the cache
+	entries  map[string]*database.CachedAnalysis // cache entries by photo UID
+}
+
+// downloadBatchPhotos downloads photos and prepares batch requests. Photos
+// with a cached analysis are not added to the batch.
 func (s *Sorter) downloadBatchPhotos(
-	photos []photoprism.Photo, availableLabels []string,
-	opts SortOptions, result *SortResult,
-) ([]ai.BatchPhotoRequest, map[string]photoprism.Photo) {
+	ctx context.Context, photos []photoprism.Photo, availableLabels []string,
+	opts SortOptions, cache *cacheScope, result *SortResult,
+) *batchInput {
 	bar := progressbar.NewOptions(len(photos),
 		progressbar.OptionSetDescription("Downloading photos"),
 		progressbar.OptionShowCount(),
@@ -330,8 +393,10 @@ func (s *Sorter) downloadBatchPhotos(
 		}),
 	)
 
-	var batchRequests []ai.BatchPhotoRequest
-	photoMap := make(map[string]photoprism.Photo)
+	input := &batchInput{
+		photoMap: make(map[string]photoprism.Photo),
+		entries:  make(map[string]*database.CachedAnalysis),
+	}
 
 	for i := range photos {
 		imageData, _, err := s.photoprism.GetPhotoDownload(photos[i].UID)
@@ -340,21 +405,31 @@ func (s *Sorter) downloadBatchPhotos(
 			bar.Add(1)
 			continue
 		}
-		batchRequests = append(batchRequests, ai.BatchPhotoRequest{
+		input.photoMap[photos[i].UID] = photos[i]
+		entry := cache.entry(imageData)
+		input.entries[photos[i].UID] = entry
+		if analysis := cache.lookup(ctx, entry); analysis != nil {
+			input.cached = append(input.cached, ai.BatchPhotoResult{PhotoUID: photos[i].UID, Analysis: analysis})
+			bar.Add(1)
+			continue
+		}
+		input.requests = append(input.requests, ai.BatchPhotoRequest{
 			PhotoUID:        photos[i].UID,
 			ImageData:       imageData,
 			Metadata:        photoToMetadata(photos[i], opts.ForceDate),
 			AvailableLabels: availableLabels,
 			EstimateDate:    opts.IndividualDates,
 		})
-		photoMap[photos[i].UID] = photos[i]
 		bar.Add(1)
 	}
 	fmt.Println()
-	return batchRequests, photoMap
+	return input
 }
 
-// cancelBatch attempts to cancel a batch job and returns the context error.
+// batchPollInterval is how long pollBatchCompletion waits between status checks.
+var batchPollInterval = 5 * time.Second
+
+// cancelBatch attempts to cancel a batch job.
 func (s *Sorter) cancelBatch(batchID string) {
 	fmt.Println("\n\nCancelling batch job...")
 	if err := s.aiProvider.CancelBatch(context.Background(), batchID); err != nil {
@@ -364,8 +439,30 @@ func (s *Sorter) cancelBatch(batchID string) {
 	}
 }
 
+// stopBatch stops waiting for a batch without cancelling it, so it can be
+// resumed later. Local batch workers are stopped as well.
+func (s *Sorter) stopBatch(batchID string) {
+	if lb, ok := s.aiProvider.(ai.LocalBatcher); ok {
+		lb.StopLocalBatch(batchID)
+	}
+	fmt.Printf("\n\nStopped waiting for batch %s. Resume it with --resume-batch %s\n", batchID, batchID)
+}
+
+// abandonBatch is called when the sort is cancelled while waiting for a
+// batch: it keeps the batch resumable if the caller asked for it, and
+// cancels it otherwise.
+func (s *Sorter) abandonBatch(batchID string, keep bool) {
+	if keep {
+		s.stopBatch(batchID)
+		return
+	}
+	s.cancelBatch(batchID)
+}
+
 // pollBatchCompletion polls a batch job until completion or cancellation.
-func (s *Sorter) pollBatchCompletion(ctx context.Context, batchID string) error {
+// On cancellation the batch is kept for resuming if keep is set, and
+// cancelled otherwise.
+func (s *Sorter) pollBatchCompletion(ctx context.Context, batchID string, keep bool) error {
 	pollBar := progressbar.NewOptions(-1,
 		progressbar.OptionSetDescription("Processing"),
 		progressbar.OptionSpinnerType(14),
@@ -375,7 +472,7 @@ func (s *Sorter) pollBatchCompletion(ctx context.Context, batchID string) error
 	for {
 		select {
 		case <-ctx.Done():
-			s.cancelBatch(batchID)
+			s.abandonBatch(batchID, keep)
 			return fmt.Errorf("sort cancelled: %w", ctx.Err())
 		default:
 		}
@@ -383,7 +480,7 @@ func (s *Sorter) pollBatchCompletion(ctx context.Context, batchID string) error
 		status, err := s.aiProvider.GetBatchStatus(ctx, batchID)
 		if err != nil {
 			if ctx.Err() != nil {
-				s.cancelBatch(batchID)
+				s.abandonBatch(batchID, keep)
 				return fmt.Errorf("sort cancelled: %w", ctx.Err())
 			}
 			return fmt.Errorf("failed to get batch status: %w", err)
@@ -403,7 +500,7 @@ func (s *Sorter) pollBatchCompletion(ctx context.Context, batchID string) error
 			return fmt.Errorf("batch failed with status: %s", status.Status)
 		}
 
-		time.Sleep(5 * time.Second)
+		time.Sleep(batchPollInterval)
 	}
 }
 
@@ -428,6 +525,7 @@ func collectBatchResults(batchResults []ai.BatchPhotoResult, result *SortResult)
 			Labels:        batchResult.Analysis.Labels,
 			Description:   batchResult.Analysis.Description,
 			EstimatedDate: batchResult.Analysis.EstimatedDate,
+			Consensus:     batchResult.Analysis.Consensus,
 		})
 	}
 	return photoDescriptions
@@ -435,7 +533,7 @@ func collectBatchResults(batchResults []ai.BatchPhotoResult, result *SortResult)
 
 // applySuggestionsWithProgress applies suggestions with a progress bar.
 func (s *Sorter) applySuggestionsWithProgress(
-	result *SortResult, photoMap map[string]photoprism.Photo, forceDate bool,
+	result *SortResult, photoMap map[string]photoprism.Photo, opts SortOptions,
 ) {
 	fmt.Println("Applying changes to PhotoPrism...")
 	applyBar := progressbar.NewOptions(len(result.Suggestions),
@@ -457,7 +555,7 @@ func (s *Sorter) applySuggestionsWithProgress(
 			applyBar.Add(1)
 			continue
 		}
-		if err := s.applySorting(photo, suggestion, forceDate); err != nil {
+		if err := s.applyWithSnapshot(photo, suggestion, opts); err != nil {
 			result.Errors = append(result.Errors, fmt.Errorf("failed to apply sorting for %s: %w", photo.UID, err))
 			applyBar.Add(1)
 			continue
@@ -468,43 +566,135 @@ func (s *Sorter) applySuggestionsWithProgress(
 	fmt.Println()
 }
 
+// runBatch submits batch requests, waits for the batch to finish and returns its results.
+func (s *Sorter) runBatch(
+	ctx context.Context, requests []ai.BatchPhotoRequest, keep bool,
+) ([]ai.BatchPhotoResult, error) {
+	fmt.Println("Creating batch job...")
+	batchID, err := s.aiProvider.CreatePhotoBatch(ctx, requests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create batch: %w", err)
+	}
+	fmt.Printf("Batch created: %s\n", batchID)
+	return s.awaitBatch(ctx, batchID, keep)
+}
+
+// awaitBatch waits for a batch to finish and returns its results. keep
+// leaves the batch running if ctx is cancelled (see KeepBatchOnCancel).
+func (s *Sorter) awaitBatch(ctx context.Context, batchID string, keep bool) ([]ai.BatchPhotoResult, error) {
+	fmt.Println("Waiting for batch to complete (this may take a few minutes)...")
+	if keep {
+		fmt.Println("Press Ctrl+C to stop waiting; the batch can be resumed later...")
+	} else {
+		fmt.Println("Press Ctrl+C to cancel the batch job...")
+	}
+	if err := s.pollBatchCompletion(ctx, batchID, keep); err != nil {
+		return nil, err
+	}
+
+	fmt.Println("Downloading results...")
+	batchResults, err := s.aiProvider.GetBatchResults(ctx, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch results: %w", err)
+	}
+	return batchResults, nil
+}
+
 func (s *Sorter) sortBatch(
 	ctx context.Context, albumUID string, albumTitle string,
 	albumDescription string, opts SortOptions,
 ) (*SortResult, error) {
 	result := &SortResult{}
 
-	availableLabels, photos, err := s.fetchLabelsAndPhotos(albumUID, opts.Limit)
+	availableLabels, photos, err := s.fetchLabelsAndPhotos(albumUID, opts)
 	if err != nil {
 		return nil, err
 	}
 
+	if opts.ResumeBatchID != "" {
+		return s.resumeBatch(ctx, photos, availableLabels, albumTitle, albumDescription, opts)
+	}
+
+	cache := s.newCacheScope(availableLabels, opts)
 	fmt.Printf("Downloading %d photos for batch processing...\n", len(photos))
-	batchRequests, photoMap := s.downloadBatchPhotos(photos, availableLabels, opts, result)
-	if len(batchRequests) == 0 {
+	input := s.downloadBatchPhotos(ctx, photos, availableLabels, opts, cache, result)
+	if len(input.requests) == 0 && len(input.cached) == 0 {
 		return nil, errors.New("no photos to process")
 	}
 
-	fmt.Println("Creating batch job...")
-	batchID, err := s.aiProvider.CreatePhotoBatch(ctx, batchRequests)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create batch: %w", err)
+	var batchResults []ai.BatchPhotoResult
+	if len(input.requests) > 0 {
+		batchResults, err = s.runBatch(ctx, input.requests, opts.KeepBatchOnCancel)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		fmt.Println("All photos found in analysis cache, skipping batch job.")
 	}
-	fmt.Printf("Batch created: %s\n", batchID)
 
-	fmt.Println("Waiting for batch to complete (this may take a few minutes)...")
-	fmt.Println("Press Ctrl+C to cancel the batch job...")
-	if err := s.pollBatchCompletion(ctx, batchID); err != nil {
-		return nil, err
+	batchResults = storeBatchResults(ctx, cache, input, batchResults, result)
+	s.finishBatch(ctx, result, batchResults, input.photoMap, albumTitle, albumDescription, opts)
+	return result, nil
+}
+
+// storeBatchResults saves the analyses of batch results in the cache and adds
+// the cached analyses of photos the batch did not cover, counting them as
+// cache hits.
+func storeBatchResults(
+	ctx context.Context, cache *cacheScope, input *batchInput,
+	batchResults []ai.BatchPhotoResult, result *SortResult,
+) []ai.BatchPhotoResult {
+	inBatch := make(map[string]bool, len(batchResults))
+	for _, r := range batchResults {
+		cache.store(ctx, input.entries[r.PhotoUID], r.Analysis)
+		inBatch[r.PhotoUID] = true
+	}
+	for _, r := range input.cached {
+		if !inBatch[r.PhotoUID] {
+			batchResults = append(batchResults, r)
+			result.CacheHits++
+		}
 	}
+	return batchResults
+}
 
-	fmt.Println("Downloading results...")
-	batchResults, err := s.aiProvider.GetBatchResults(ctx, batchID)
+// resumeBatch finishes a sort whose batch was submitted by an earlier run.
+// With the cache enabled, the photos are downloaded again to cache the
+// resumed results and to use the cached analyses of photos the earlier run
+// left out of the batch.
+func (s *Sorter) resumeBatch(
+	ctx context.Context, photos []photoprism.Photo, availableLabels []string,
+	albumTitle, albumDescription string, opts SortOptions,
+) (*SortResult, error) {
+	fmt.Printf("Resuming batch %s...\n", opts.ResumeBatchID)
+	batchResults, err := s.awaitBatch(ctx, opts.ResumeBatchID, opts.KeepBatchOnCancel)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get batch results: %w", err)
+		return nil, err
 	}
 
+	result := &SortResult{}
+	input := &batchInput{photoMap: make(map[string]photoprism.Photo, len(photos))}
+	if cache := s.newCacheScope(availableLabels, opts); cache != nil {
+		fmt.Printf("Downloading %d photos for the analysis cache...\n", len(photos))
+		input = s.downloadBatchPhotos(ctx, photos, availableLabels, opts, cache, result)
+		batchResults = storeBatchResults(ctx, cache, input, batchResults, result)
+	}
+	for i := range photos {
+		// Photos that failed to download were still analyzed by the batch.
+		input.photoMap[photos[i].UID] = photos[i]
+	}
+	s.finishBatch(ctx, result, batchResults, input.photoMap, albumTitle, albumDescription, opts)
+	return result, nil
+}
+
+// finishBatch turns batch results into suggestions, estimates the album date
+// and applies the suggestions unless this is a dry run.
+func (s *Sorter) finishBatch(
+	ctx context.Context, result *SortResult, batchResults []ai.BatchPhotoResult,
+	photoMap map[string]photoprism.Photo, albumTitle, albumDescription string, opts SortOptions,
+) {
 	photoDescriptions := collectBatchResults(batchResults, result)
+	normalizeSuggestions(result, opts.Taxonomy)
 
 	if !opts.IndividualDates && len(photoDescriptions) > 0 {
 		fmt.Println("Estimating album date...")
@@ -512,12 +702,10 @@ func (s *Sorter) sortBatch(
 	}
 
 	if !opts.DryRun {
-		s.applySuggestionsWithProgress(result, photoMap, opts.ForceDate)
+		s.applySuggestionsWithProgress(result, photoMap, opts)
 	} else {
 		result.SortedCount = len(result.Suggestions)
 	}
-
-	return result, nil
 }
 
 // applyLabels replaces photo labels with AI-suggested ones (confidence > 80%).
@@ -578,6 +766,21 @@ func buildDateUpdate(
 	return nil
 }
 
+// applyWithSnapshot hands the photo's current state to opts.OnBeforeApply
+// (if set) and then applies the suggestion.
+func (s *Sorter) applyWithSnapshot(photo photoprism.Photo, suggestion ai.SortSuggestion, opts SortOptions) error {
+	if opts.OnBeforeApply != nil {
+		snap, err := SnapshotPhoto(s.photoprism, photo.UID)
+		if err != nil {
+			return fmt.Errorf("failed to snapshot photo: %w", err)
+		}
+		if err := opts.OnBeforeApply(*snap); err != nil {
+			return fmt.Errorf("failed to record undo snapshot: %w", err)
+		}
+	}
+	return s.applySorting(photo, suggestion, opts.ForceDate)
+}
+
 func (s *Sorter) applySorting(photo photoprism.Photo, suggestion ai.SortSuggestion, forceDate bool) error {
 	if err := s.applyLabels(photo.UID, suggestion.Labels); err != nil {
 		return err
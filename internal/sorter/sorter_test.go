@@ -1,9 +1,22 @@
 package sorter
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/kozaktomas/photo-sorter/internal/ai"
+	"github.com/kozaktomas/photo-sorter/internal/database/mock"
 	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+	"github.com/kozaktomas/photo-sorter/internal/photoprism/fake"
 )
 
 func TestPhotoToMetadata_BasicFields(t *testing.T) {
@@ -409,3 +422,164 @@ func TestProgressInfo_Fields(t *testing.T) {
 		t.Errorf("expected Message 'Processing photo', got '%s'", info.Message)
 	}
 }
+
+// newStalledLocalBatch submits a two-photo local batch to an Ollama server
+// whose first analysis hangs until the test ends. The returned channel is
+// closed once that analysis has started.
+func newStalledLocalBatch(t *testing.T) (*ai.OllamaProvider, string, <-chan struct{}) {
+	t.Helper()
+	prev := batchPollInterval
+	batchPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { batchPollInterval = prev })
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			close(started)
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"message":{"role":"assistant","content":"{\"labels\":[],\"description\":\"ok\"}"},"done":true}`)
+	}))
+	// Cleanups run last-in first-out: release the hanging handler first.
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	provider, err := ai.NewOllamaProvider(server.URL, "llava")
+	if err != nil {
+		t.Fatalf("NewOllamaProvider: %v", err)
+	}
+	if err := provider.EnableLocalBatch(t.TempDir(), 1); err != nil {
+		t.Fatalf("EnableLocalBatch: %v", err)
+	}
+	var img bytes.Buffer
+	if err := jpeg.Encode(&img, image.NewRGBA(image.Rect(0, 0, 10, 10)), nil); err != nil {
+		t.Fatalf("jpeg.Encode: %v", err)
+	}
+	batchID, err := provider.CreatePhotoBatch(context.Background(), []ai.BatchPhotoRequest{
+		{PhotoUID: "p1", ImageData: img.Bytes()},
+		{PhotoUID: "p2", ImageData: img.Bytes()},
+	})
+	if err != nil {
+		t.Fatalf("CreatePhotoBatch: %v", err)
+	}
+	return provider, batchID, started
+}
+
+// cancelWhenStarted returns a context that is cancelled once started is closed.
+func cancelWhenStarted(started <-chan struct{}) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	return ctx
+}
+
+func TestAwaitBatch_ResumesAfterCancel(t *testing.T) {
+	provider, batchID, started := newStalledLocalBatch(t)
+
+	s := New(nil, provider)
+	if _, err := s.awaitBatch(cancelWhenStarted(started), batchID, true); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancelled wait, got %v", err)
+	}
+
+	results, err := s.awaitBatch(context.Background(), batchID, true)
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if len(results) != 2 || results[0].Analysis == nil || results[1].Analysis == nil {
+		t.Errorf("expected both photos analyzed after resume, got %+v", results)
+	}
+}
+
+func TestAwaitBatch_CancelsBatchByDefault(t *testing.T) {
+	provider, batchID, started := newStalledLocalBatch(t)
+
+	s := New(nil, provider)
+	if _, err := s.awaitBatch(cancelWhenStarted(started), batchID, false); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancelled wait, got %v", err)
+	}
+
+	status, err := provider.GetBatchStatus(context.Background(), batchID)
+	if err != nil {
+		t.Fatalf("GetBatchStatus: %v", err)
+	}
+	if status.Status != "cancelled" {
+		t.Errorf("status = %q, want the batch cancelled", status.Status)
+	}
+}
+
+func TestResumeBatch_UsesAnalysisCache(t *testing.T) {
+	prev := batchPollInterval
+	batchPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { batchPollInterval = prev })
+
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"message":{"role":"assistant","content":"{\"labels\":[],\"description\":\"ok\"}"},"done":true}`)
+	}))
+	defer ollama.Close()
+	provider, err := ai.NewOllamaProvider(ollama.URL, "llava")
+	if err != nil {
+		t.Fatalf("NewOllamaProvider: %v", err)
+	}
+	if err := provider.EnableLocalBatch(t.TempDir(), 1); err != nil {
+		t.Fatalf("EnableLocalBatch: %v", err)
+	}
+
+	// Three distinct images; the third was answered from the cache when the
+	// batch was submitted, so only the first two are in it.
+	s := fake.New(fake.Options{})
+	images := make([][]byte, 3)
+	photos := make([]photoprism.Photo, 3)
+	var requests []ai.BatchPhotoRequest
+	for i := range images {
+		var img bytes.Buffer
+		if err := jpeg.Encode(&img, image.NewRGBA(image.Rect(0, 0, 10+i, 10)), nil); err != nil {
+			t.Fatalf("jpeg.Encode: %v", err)
+		}
+		images[i] = img.Bytes()
+		photos[i] = photoprism.Photo{UID: s.AddPhoto(photoprism.Photo{}, images[i])}
+		if i < 2 {
+			requests = append(requests, ai.BatchPhotoRequest{PhotoUID: photos[i].UID, ImageData: images[i]})
+		}
+	}
+	batchID, err := provider.CreatePhotoBatch(context.Background(), requests)
+	if err != nil {
+		t.Fatalf("CreatePhotoBatch: %v", err)
+	}
+
+	server := httptest.NewServer(s.Handler())
+	defer server.Close()
+	pp, err := photoprism.NewPhotoPrism(server.URL, "admin", "secret")
+	if err != nil {
+		t.Fatalf("NewPhotoPrism: %v", err)
+	}
+
+	ctx := context.Background()
+	labels := []string{"beach"}
+	opts := SortOptions{DryRun: true, IndividualDates: true, ResumeBatchID: batchID, Cache: mock.NewMockAnalysisCache()}
+	sorter := New(pp, provider)
+	cache := sorter.newCacheScope(labels, opts)
+	cache.store(ctx, cache.entry(images[2]), &ai.PhotoAnalysis{Description: "cached"})
+
+	result, err := sorter.resumeBatch(ctx, photos, labels, "", "", opts)
+	if err != nil {
+		t.Fatalf("resumeBatch: %v", err)
+	}
+	if result.CacheHits != 1 || len(result.Suggestions) != 3 {
+		t.Errorf("expected 3 suggestions with 1 cache hit, got %d with %d", len(result.Suggestions), result.CacheHits)
+	}
+	for i := range 2 {
+		if cache.lookup(ctx, cache.entry(images[i])) == nil {
+			t.Errorf("expected the resumed analysis of photo %d in the cache", i)
+		}
+	}
+}
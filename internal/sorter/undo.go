@@ -0,0 +1,232 @@
+package sorter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+)
+
+// PhotoSnapshot is the state of the photo fields a sort run may change,
+// captured before the run touches the photo so it can be restored later.
+type PhotoSnapshot struct {
+	PhotoUID       string          `json:"photo_uid"`
+	Title          string          `json:"title"`
+	Description    string          `json:"description"`
+	DescriptionSrc string          `json:"description_src"`
+	Notes          string          `json:"notes"`
+	TakenAt        string          `json:"taken_at"`
+	TakenAtLocal   string          `json:"taken_at_local"`
+	TimeZone       string          `json:"time_zone"`
+	Year           int             `json:"year"`
+	Month          int             `json:"month"`
+	Day            int             `json:"day"`
+	Labels         []SnapshotLabel `json:"labels"`
+}
+
+// SnapshotLabel is a label assigned to a photo at snapshot time.
+type SnapshotLabel struct {
+	Name        string `json:"name"`
+	LabelSrc    string `json:"label_src"`
+	Uncertainty int    `json:"uncertainty"`
+}
+
+// photoDetails is the subset of the PhotoPrism photo details response that
+// makes up a PhotoSnapshot.
+type photoDetails struct {
+	Title          string `json:"Title"`
+	Description    string `json:"Description"`
+	DescriptionSrc string `json:"DescriptionSrc"`
+	TakenAt        string `json:"TakenAt"`
+	TakenAtLocal   string `json:"TakenAtLocal"`
+	TimeZone       string `json:"TimeZone"`
+	Year           int    `json:"Year"`
+	Month          int    `json:"Month"`
+	Day            int    `json:"Day"`
+	Details        struct {
+		Notes string `json:"Notes"`
+	} `json:"Details"`
+	Labels []struct {
+		LabelSrc    string `json:"LabelSrc"`
+		Uncertainty int    `json:"Uncertainty"`
+		Label       struct {
+			Name string `json:"Name"`
+		} `json:"Label"`
+	} `json:"Labels"`
+}
+
+// SnapshotPhoto fetches the current state of a photo from PhotoPrism.
+func SnapshotPhoto(pp *photoprism.PhotoPrism, photoUID string) (*PhotoSnapshot, error) {
+	details, err := pp.GetPhotoDetails(photoUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get photo details: %w", err)
+	}
+	return snapshotFromDetails(photoUID, details)
+}
+
+// snapshotFromDetails converts a photo details response into a snapshot.
+func snapshotFromDetails(photoUID string, details map[string]any) (*PhotoSnapshot, error) {
+	data, err := json.Marshal(details)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode photo details: %w", err)
+	}
+	var d photoDetails
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("failed to decode photo details: %w", err)
+	}
+
+	snap := &PhotoSnapshot{
+		PhotoUID:       photoUID,
+		Title:          d.Title,
+		Description:    d.Description,
+		DescriptionSrc: d.DescriptionSrc,
+		Notes:          d.Details.Notes,
+		TakenAt:        d.TakenAt,
+		TakenAtLocal:   d.TakenAtLocal,
+		TimeZone:       d.TimeZone,
+		Year:           d.Year,
+		Month:          d.Month,
+		Day:            d.Day,
+		Labels:         make([]SnapshotLabel, 0, len(d.Labels)),
+	}
+	for _, l := range d.Labels {
+		if l.Label.Name == "" {
+			continue
+		}
+		snap.Labels = append(snap.Labels, SnapshotLabel{
+			Name:        l.Label.Name,
+			LabelSrc:    l.LabelSrc,
+			Uncertainty: l.Uncertainty,
+		})
+	}
+	return snap, nil
+}
+
+// restoreUpdate builds the photo update that puts back the snapshot's fields.
+func (snap *PhotoSnapshot) restoreUpdate() photoprism.PhotoUpdate {
+	update := photoprism.PhotoUpdate{
+		Title:          &snap.Title,
+		Description:    &snap.Description,
+		DescriptionSrc: &snap.DescriptionSrc,
+		Details:        &photoprism.PhotoDetails{Notes: &snap.Notes},
+	}
+	// Only restore the date if the photo had one; PhotoPrism rejects an
+	// empty TakenAt.
+	if snap.TakenAt != "" {
+		update.TakenAt = &snap.TakenAt
+		update.TakenAtLocal = &snap.TakenAtLocal
+		update.TimeZone = &snap.TimeZone
+		update.Year = &snap.Year
+		update.Month = &snap.Month
+		update.Day = &snap.Day
+	}
+	return update
+}
+
+// RestorePhoto puts a photo back into the state captured by the snapshot:
+// labels are replaced by the snapshot's labels and title, description,
+// notes and date are overwritten.
+func RestorePhoto(pp *photoprism.PhotoPrism, snap *PhotoSnapshot) error {
+	if err := pp.RemoveAllPhotoLabels(snap.PhotoUID); err != nil {
+		return fmt.Errorf("failed to remove labels: %w", err)
+	}
+	for _, label := range snap.Labels {
+		_, err := pp.AddPhotoLabel(snap.PhotoUID, photoprism.PhotoLabel{
+			Name:        label.Name,
+			LabelSrc:    label.LabelSrc,
+			Uncertainty: label.Uncertainty,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to restore label %s: %w", label.Name, err)
+		}
+	}
+	if _, err := pp.EditPhoto(snap.PhotoUID, snap.restoreUpdate()); err != nil {
+		return fmt.Errorf("failed to restore photo: %w", err)
+	}
+	return nil
+}
+
+// UndoRecorder returns an OnBeforeApply callback that stores each snapshot
+// in the undo log under runID.
+func UndoRecorder(ctx context.Context, store database.SortUndoStore, runID string) func(PhotoSnapshot) error {
+	return func(snap PhotoSnapshot) error {
+		data, err := json.Marshal(snap)
+		if err != nil {
+			return fmt.Errorf("failed to encode snapshot: %w", err)
+		}
+		if err := store.SaveSortChange(ctx, &database.SortChange{
+			RunID:    runID,
+			PhotoUID: snap.PhotoUID,
+			Before:   data,
+		}); err != nil {
+			return fmt.Errorf("failed to save snapshot: %w", err)
+		}
+		return nil
+	}
+}
+
+// RevertResult holds the outcome of reverting a sort run.
+type RevertResult struct {
+	RunID         string
+	RevertedCount int
+	AlreadyCount  int // changes reverted by an earlier revert
+	Errors        []error
+}
+
+// RevertRun restores every photo changed by a sort run to its recorded prior
+// state. Photos are reverted one by one; a failure on one photo is recorded
+// and the rest are still attempted. Already reverted photos are skipped, so a
+// partially failed revert can simply be re-run.
+func RevertRun(
+	ctx context.Context, pp *photoprism.PhotoPrism,
+	store database.SortUndoStore, runID string,
+) (*RevertResult, error) {
+	changes, err := store.ListSortChanges(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load undo log: %w", err)
+	}
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("no changes recorded for sort run %s", runID)
+	}
+
+	result := &RevertResult{RunID: runID}
+	for _, change := range changes {
+		if ctx.Err() != nil {
+			return result, fmt.Errorf("revert cancelled: %w", ctx.Err())
+		}
+		if change.RevertedAt != nil {
+			result.AlreadyCount++
+			continue
+		}
+		if err := revertChange(ctx, pp, store, change); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("photo %s: %w", change.PhotoUID, err))
+			continue
+		}
+		result.RevertedCount++
+	}
+	return result, nil
+}
+
+// revertChange restores a single photo and marks its change as reverted.
+func revertChange(
+	ctx context.Context, pp *photoprism.PhotoPrism,
+	store database.SortUndoStore, change database.SortChange,
+) error {
+	var snap PhotoSnapshot
+	if err := json.Unmarshal(change.Before, &snap); err != nil {
+		return fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	if snap.PhotoUID == "" {
+		return errors.New("snapshot has no photo UID")
+	}
+	if err := RestorePhoto(pp, &snap); err != nil {
+		return err
+	}
+	if err := store.MarkSortChangeReverted(ctx, change.ID); err != nil {
+		return fmt.Errorf("photo restored but not marked reverted: %w", err)
+	}
+	return nil
+}
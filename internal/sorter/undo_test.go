@@ -0,0 +1,86 @@
+package sorter
+
+import (
+	"testing"
+)
+
+func TestSnapshotFromDetails(t *testing.T) {
+	details := map[string]any{
+		"UID":            "pt8sur39icikrn19",
+		"Title":          "John Doe / Sample City",
+		"Description":    "Old description",
+		"DescriptionSrc": "manual",
+		"TakenAt":        "2016-05-21T09:24:36Z",
+		"TakenAtLocal":   "2016-05-21T11:24:36Z",
+		"TimeZone":       "Europe/Prague",
+		"Year":           float64(2016),
+		"Month":          float64(5),
+		"Day":            float64(21),
+		"Details":        map[string]any{"Notes": "scanned by grandpa"},
+		"Labels": []any{
+			map[string]any{
+				"LabelID": float64(248), "LabelSrc": "batch", "Uncertainty": float64(10),
+				"Label": map[string]any{"ID": float64(248), "Name": "Racing"},
+			},
+			map[string]any{"LabelID": float64(249), "LabelSrc": "image"},
+		},
+	}
+
+	snap, err := snapshotFromDetails("pt8sur39icikrn19", details)
+	if err != nil {
+		t.Fatalf("snapshotFromDetails: %v", err)
+	}
+	if snap.PhotoUID != "pt8sur39icikrn19" || snap.Title != "John Doe / Sample City" {
+		t.Errorf("unexpected identity fields: %+v", snap)
+	}
+	if snap.Description != "Old description" || snap.DescriptionSrc != "manual" {
+		t.Errorf("unexpected description: %q (%q)", snap.Description, snap.DescriptionSrc)
+	}
+	if snap.Notes != "scanned by grandpa" {
+		t.Errorf("expected notes to be captured, got %q", snap.Notes)
+	}
+	if snap.Year != 2016 || snap.Month != 5 || snap.Day != 21 || snap.TimeZone != "Europe/Prague" {
+		t.Errorf("unexpected date fields: %+v", snap)
+	}
+	if len(snap.Labels) != 1 {
+		t.Fatalf("expected 1 label (nameless label skipped), got %d", len(snap.Labels))
+	}
+	if l := snap.Labels[0]; l.Name != "Racing" || l.LabelSrc != "batch" || l.Uncertainty != 10 {
+		t.Errorf("unexpected label: %+v", l)
+	}
+}
+
+func TestPhotoSnapshot_RestoreUpdate(t *testing.T) {
+	snap := &PhotoSnapshot{
+		PhotoUID:     "p1",
+		Title:        "Title",
+		Description:  "",
+		TakenAt:      "2020-01-02T03:04:05Z",
+		TakenAtLocal: "2020-01-02T04:04:05Z",
+		TimeZone:     "Europe/Prague",
+		Year:         2020, Month: 1, Day: 2,
+	}
+
+	update := snap.restoreUpdate()
+	if update.Description == nil || *update.Description != "" {
+		t.Error("expected empty description to be restored explicitly")
+	}
+	if update.Details == nil || update.Details.Notes == nil {
+		t.Error("expected notes to be restored")
+	}
+	if update.TakenAt == nil || *update.TakenAt != snap.TakenAt || update.Year == nil || *update.Year != 2020 {
+		t.Errorf("expected date to be restored, got %+v", update)
+	}
+}
+
+func TestPhotoSnapshot_RestoreUpdateWithoutDate(t *testing.T) {
+	snap := &PhotoSnapshot{PhotoUID: "p1", Title: "Title"}
+
+	update := snap.restoreUpdate()
+	if update.TakenAt != nil || update.Year != nil || update.TimeZone != nil {
+		t.Errorf("expected date fields to be left alone, got %+v", update)
+	}
+	if update.Title == nil || *update.Title != "Title" {
+		t.Error("expected title to be restored")
+	}
+}
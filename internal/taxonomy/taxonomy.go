@@ -0,0 +1,245 @@
+// Package taxonomy maps free-form label names (as produced by AI providers)
+// onto the managed label vocabulary: aliases are resolved to canonical names,
+// duplicates are merged and parent labels are added.
+package taxonomy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kozaktomas/photo-sorter/internal/ai"
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+// Taxonomy is a validated, read-only view of the label vocabulary. A nil
+// *Taxonomy is valid and leaves labels unchanged apart from merging
+// case-insensitive duplicates.
+type Taxonomy struct {
+	terms  map[string]database.LabelTerm // by canonical name
+	lookup map[string]string             // key of a name or alias -> canonical name
+}
+
+// Key returns the form in which label names are compared: lower case with
+// surrounding and repeated whitespace removed.
+func Key(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// New validates terms and builds a taxonomy. Names and aliases must be unique
+// (case-insensitively) across all terms, parents must exist and the parent
+// relation must not contain cycles.
+func New(terms []database.LabelTerm) (*Taxonomy, error) {
+	t := &Taxonomy{
+		terms:  make(map[string]database.LabelTerm, len(terms)),
+		lookup: make(map[string]string),
+	}
+	for _, term := range terms {
+		if Key(term.Name) == "" {
+			return nil, errors.New("label term name is required")
+		}
+		if err := t.claim(term.Name, term.Name); err != nil {
+			return nil, err
+		}
+		t.terms[term.Name] = term
+	}
+	for _, term := range terms {
+		for _, alias := range term.Aliases {
+			if Key(alias) == Key(term.Name) {
+				continue
+			}
+			if err := t.claim(alias, term.Name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, term := range terms {
+		if term.Parent == "" {
+			continue
+		}
+		if _, ok := t.terms[term.Parent]; !ok {
+			return nil, fmt.Errorf("label term %s: unknown parent %s", term.Name, term.Parent)
+		}
+		if err := t.checkCycle(term.Name); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// claim registers name as resolving to canonical, failing if it is taken.
+func (t *Taxonomy) claim(name, canonical string) error {
+	key := Key(name)
+	if key == "" {
+		return nil
+	}
+	if owner, ok := t.lookup[key]; ok && owner != canonical {
+		return fmt.Errorf("label name %q is used by both %s and %s", name, owner, canonical)
+	}
+	t.lookup[key] = canonical
+	return nil
+}
+
+// checkCycle fails if following the parents of name leads back to name.
+func (t *Taxonomy) checkCycle(name string) error {
+	seen := map[string]bool{name: true}
+	for parent := t.terms[name].Parent; parent != ""; parent = t.terms[parent].Parent {
+		if seen[parent] {
+			return fmt.Errorf("label term %s: parent cycle through %s", name, parent)
+		}
+		seen[parent] = true
+	}
+	return nil
+}
+
+// Load reads and validates the taxonomy stored in store.
+func Load(ctx context.Context, store database.LabelTaxonomyStore) (*Taxonomy, error) {
+	terms, err := store.ListLabelTerms(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load label taxonomy: %w", err)
+	}
+	return New(terms)
+}
+
+// SaveTerms cleans up the given terms, checks that the taxonomy stays valid
+// with them added or replaced, and stores them.
+func SaveTerms(ctx context.Context, store database.LabelTaxonomyStore, terms ...database.LabelTerm) error {
+	existing, err := store.ListLabelTerms(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load label taxonomy: %w", err)
+	}
+	merged := make([]database.LabelTerm, 0, len(existing)+len(terms))
+	replaced := make(map[string]bool, len(terms))
+	for i := range terms {
+		terms[i] = cleanTerm(terms[i])
+		replaced[terms[i].Name] = true
+	}
+	for _, term := range existing {
+		if !replaced[term.Name] {
+			merged = append(merged, term)
+		}
+	}
+	merged = append(merged, terms...)
+	if _, err := New(merged); err != nil {
+		return err
+	}
+	if err := store.SaveLabelTerms(ctx, terms); err != nil {
+		return fmt.Errorf("failed to save label taxonomy: %w", err)
+	}
+	return nil
+}
+
+// cleanTerm trims names and drops empty, duplicate and self-referencing aliases.
+func cleanTerm(term database.LabelTerm) database.LabelTerm {
+	term.Name = strings.Join(strings.Fields(term.Name), " ")
+	term.Parent = strings.Join(strings.Fields(term.Parent), " ")
+	term.Description = strings.TrimSpace(term.Description)
+	seen := map[string]bool{Key(term.Name): true}
+	aliases := make([]string, 0, len(term.Aliases))
+	for _, alias := range term.Aliases {
+		alias = strings.Join(strings.Fields(alias), " ")
+		if key := Key(alias); key != "" && !seen[key] {
+			seen[key] = true
+			aliases = append(aliases, alias)
+		}
+	}
+	term.Aliases = aliases
+	return term
+}
+
+// Terms returns all terms ordered by name.
+func (t *Taxonomy) Terms() []database.LabelTerm {
+	if t == nil {
+		return nil
+	}
+	terms := make([]database.LabelTerm, 0, len(t.terms))
+	for _, term := range t.terms {
+		terms = append(terms, term)
+	}
+	slices.SortFunc(terms, func(a, b database.LabelTerm) int { return strings.Compare(a.Name, b.Name) })
+	return terms
+}
+
+// Canonical returns the canonical name for a label name or alias.
+func (t *Taxonomy) Canonical(name string) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	canonical, ok := t.lookup[Key(name)]
+	return canonical, ok
+}
+
+// Ancestors returns the parents of a canonical name, nearest first.
+func (t *Taxonomy) Ancestors(name string) []string {
+	if t == nil {
+		return nil
+	}
+	var ancestors []string
+	for parent := t.terms[name].Parent; parent != ""; parent = t.terms[parent].Parent {
+		ancestors = append(ancestors, parent)
+	}
+	return ancestors
+}
+
+// Vocabulary returns the label names offered to the AI: all canonical names
+// plus the given existing labels that are not in the taxonomy, with aliases
+// and case-insensitive duplicates collapsed.
+func (t *Taxonomy) Vocabulary(existing []string) []string {
+	seen := make(map[string]bool)
+	var vocabulary []string
+	add := func(name string) {
+		if key := Key(name); key != "" && !seen[key] {
+			seen[key] = true
+			vocabulary = append(vocabulary, name)
+		}
+	}
+	for _, term := range t.Terms() {
+		add(term.Name)
+	}
+	for _, name := range existing {
+		if canonical, ok := t.Canonical(name); ok {
+			name = canonical
+		}
+		add(name)
+	}
+	slices.SortFunc(vocabulary, func(a, b string) int { return strings.Compare(Key(a), Key(b)) })
+	return vocabulary
+}
+
+// Normalize maps labels onto canonical names, merges duplicates (keeping the
+// highest confidence) and adds the parents of every known label with the
+// confidence of the child. Labels not in the taxonomy are kept as they are.
+func (t *Taxonomy) Normalize(labels []ai.LabelWithConfidence) []ai.LabelWithConfidence {
+	var result []ai.LabelWithConfidence
+	index := make(map[string]int)
+	add := func(name string, confidence float64) {
+		key := Key(name)
+		if key == "" {
+			return
+		}
+		if i, ok := index[key]; ok {
+			result[i].Confidence = max(result[i].Confidence, confidence)
+			return
+		}
+		index[key] = len(result)
+		result = append(result, ai.LabelWithConfidence{Name: name, Confidence: confidence})
+	}
+
+	var known []ai.LabelWithConfidence
+	for _, label := range labels {
+		name := strings.Join(strings.Fields(label.Name), " ")
+		if canonical, ok := t.Canonical(name); ok {
+			name = canonical
+			known = append(known, ai.LabelWithConfidence{Name: canonical, Confidence: label.Confidence})
+		}
+		add(name, label.Confidence)
+	}
+	for _, label := range known {
+		for _, parent := range t.Ancestors(label.Name) {
+			add(parent, label.Confidence)
+		}
+	}
+	return result
+}
@@ -0,0 +1,189 @@
+package taxonomy
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/kozaktomas/photo-sorter/internal/ai"
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/database/mock"
+)
+
+func testTerms() []database.LabelTerm {
+	return []database.LabelTerm{
+		{Name: "Animal", Aliases: []string{"Animals", "Zvíře"}},
+		{Name: "Dog", Parent: "Animal", Aliases: []string{"Dogs", "Pes", "puppy"}},
+		{Name: "Beach", Aliases: []string{"Pláž", "seaside"}},
+	}
+}
+
+func mustNew(t *testing.T, terms []database.LabelTerm) *Taxonomy {
+	t.Helper()
+	tax, err := New(terms)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return tax
+}
+
+func TestNew_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		terms []database.LabelTerm
+	}{
+		{"empty name", []database.LabelTerm{{Name: "  "}}},
+		{"duplicate name", []database.LabelTerm{{Name: "Dog"}, {Name: "dog"}}},
+		{"alias of other term", []database.LabelTerm{{Name: "Dog"}, {Name: "Cat", Aliases: []string{"DOG"}}}},
+		{"shared alias", []database.LabelTerm{
+			{Name: "Dog", Aliases: []string{"pet"}},
+			{Name: "Cat", Aliases: []string{"Pet"}},
+		}},
+		{"unknown parent", []database.LabelTerm{{Name: "Dog", Parent: "Animal"}}},
+		{"cycle", []database.LabelTerm{{Name: "A", Parent: "B"}, {Name: "B", Parent: "C"}, {Name: "C", Parent: "A"}}},
+		{"self parent", []database.LabelTerm{{Name: "A", Parent: "A"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.terms); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestTaxonomy_Canonical(t *testing.T) {
+	tax := mustNew(t, testTerms())
+	for input, want := range map[string]string{
+		"dog":        "Dog",
+		"  PES ":     "Dog",
+		"Puppy":      "Dog",
+		"zvíře":      "Animal",
+		"SEASIDE":    "Beach",
+		"Dogs":       "Dog",
+		"pláž":       "Beach",
+		"Animals":    "Animal",
+		"Dog":        "Dog",
+		"beach\t":    "Beach",
+		"  Animal  ": "Animal",
+	} {
+		got, ok := tax.Canonical(input)
+		if !ok || got != want {
+			t.Errorf("Canonical(%q) = %q, %v; want %q", input, got, ok, want)
+		}
+	}
+	if _, ok := tax.Canonical("mountain"); ok {
+		t.Error("expected unknown label not to resolve")
+	}
+}
+
+func TestTaxonomy_Normalize(t *testing.T) {
+	tax := mustNew(t, testTerms())
+	got := tax.Normalize([]ai.LabelWithConfidence{
+		{Name: "pes", Confidence: 0.85},
+		{Name: "Dogs", Confidence: 0.95},
+		{Name: "mountain", Confidence: 0.9},
+		{Name: "Mountain", Confidence: 0.7},
+		{Name: "animal", Confidence: 0.5},
+	})
+	want := []ai.LabelWithConfidence{
+		{Name: "Dog", Confidence: 0.95},
+		{Name: "mountain", Confidence: 0.9},
+		{Name: "Animal", Confidence: 0.95},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Normalize = %+v; want %+v", got, want)
+	}
+}
+
+func TestTaxonomy_NilNormalizeMergesDuplicates(t *testing.T) {
+	var tax *Taxonomy
+	got := tax.Normalize([]ai.LabelWithConfidence{{Name: "Dog", Confidence: 0.6}, {Name: "dog ", Confidence: 0.9}})
+	if len(got) != 1 || got[0].Name != "Dog" || got[0].Confidence != 0.9 {
+		t.Errorf("unexpected result: %+v", got)
+	}
+}
+
+func TestTaxonomy_Vocabulary(t *testing.T) {
+	tax := mustNew(t, testTerms())
+	got := tax.Vocabulary([]string{"dogs", "Mountain", "pes", "mountain", "Sunset"})
+	want := []string{"Animal", "Beach", "Dog", "Mountain", "Sunset"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Vocabulary = %v; want %v", got, want)
+	}
+}
+
+func TestTaxonomy_Ancestors(t *testing.T) {
+	tax := mustNew(t, []database.LabelTerm{
+		{Name: "Animal"},
+		{Name: "Dog", Parent: "Animal"},
+		{Name: "Beagle", Parent: "Dog"},
+	})
+	if got := tax.Ancestors("Beagle"); !slices.Equal(got, []string{"Dog", "Animal"}) {
+		t.Errorf("Ancestors = %v", got)
+	}
+	if got := tax.Ancestors("Animal"); len(got) != 0 {
+		t.Errorf("expected no ancestors, got %v", got)
+	}
+}
+
+func TestSaveTerms(t *testing.T) {
+	ctx := context.Background()
+	store := mock.NewMockLabelTaxonomyStore()
+	if err := store.SaveLabelTerms(ctx, testTerms()); err != nil {
+		t.Fatalf("SaveLabelTerms: %v", err)
+	}
+
+	err := SaveTerms(ctx, store, database.LabelTerm{
+		Name:    " Cat ",
+		Parent:  "Animal",
+		Aliases: []string{"Kočka", "kočka", "", "cat"},
+	})
+	if err != nil {
+		t.Fatalf("SaveTerms: %v", err)
+	}
+	tax, err := Load(ctx, store)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got, _ := tax.Canonical("KOČKA"); got != "Cat" {
+		t.Errorf("expected alias to resolve to Cat, got %q", got)
+	}
+	for _, term := range tax.Terms() {
+		if term.Name == "Cat" && !slices.Equal(term.Aliases, []string{"Kočka"}) {
+			t.Errorf("expected cleaned aliases, got %v", term.Aliases)
+		}
+	}
+
+	// Conflicts with an existing alias are rejected and nothing is stored.
+	if err := SaveTerms(ctx, store, database.LabelTerm{Name: "Puppy"}); err == nil {
+		t.Error("expected conflict with alias of Dog")
+	}
+	if tax, _ := Load(ctx, store); len(tax.Terms()) != 4 {
+		t.Errorf("expected 4 terms, got %d", len(tax.Terms()))
+	}
+
+	store.ListLabelTermsError = errors.New("db down")
+	if err := SaveTerms(ctx, store, database.LabelTerm{Name: "Cat"}); err == nil {
+		t.Error("expected store error")
+	}
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	data, err := MarshalYAML(testTerms())
+	if err != nil {
+		t.Fatalf("MarshalYAML: %v", err)
+	}
+	terms, err := ParseYAML(data)
+	if err != nil {
+		t.Fatalf("ParseYAML: %v", err)
+	}
+	if len(terms) != 3 || terms[1].Name != "Dog" || terms[1].Parent != "Animal" || len(terms[1].Aliases) != 3 {
+		t.Errorf("unexpected terms: %+v", terms)
+	}
+
+	if _, err := ParseYAML([]byte("labels:\n  - name: Dog\n    parent: Animal\n")); err == nil {
+		t.Error("expected unknown parent to be rejected")
+	}
+}
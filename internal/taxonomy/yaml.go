@@ -0,0 +1,60 @@
+package taxonomy
+
+import (
+	"fmt"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"gopkg.in/yaml.v3"
+)
+
+// File is the YAML representation of a taxonomy used by import and export.
+type File struct {
+	Labels []FileTerm `yaml:"labels"`
+}
+
+// FileTerm is a single label term in a taxonomy file.
+type FileTerm struct {
+	Name        string   `yaml:"name"`
+	Parent      string   `yaml:"parent,omitempty"`
+	Aliases     []string `yaml:"aliases,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+}
+
+// ParseYAML parses and validates a taxonomy file.
+func ParseYAML(data []byte) ([]database.LabelTerm, error) {
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse taxonomy file: %w", err)
+	}
+	terms := make([]database.LabelTerm, len(file.Labels))
+	for i, t := range file.Labels {
+		terms[i] = cleanTerm(database.LabelTerm{
+			Name:        t.Name,
+			Parent:      t.Parent,
+			Aliases:     t.Aliases,
+			Description: t.Description,
+		})
+	}
+	if _, err := New(terms); err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
+
+// MarshalYAML encodes terms as a taxonomy file.
+func MarshalYAML(terms []database.LabelTerm) ([]byte, error) {
+	file := File{Labels: make([]FileTerm, len(terms))}
+	for i, t := range terms {
+		file.Labels[i] = FileTerm{
+			Name:        t.Name,
+			Parent:      t.Parent,
+			Aliases:     t.Aliases,
+			Description: t.Description,
+		}
+	}
+	data, err := yaml.Marshal(&file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode taxonomy file: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/web/middleware"
+)
+
+// --- Book comments ---
+
+type bookCommentResponse struct {
+	ID         string `json:"id"`
+	BookID     string `json:"book_id"`
+	TargetType string `json:"target_type"`
+	PageID     string `json:"page_id,omitempty"`
+	SlotIndex  *int   `json:"slot_index,omitempty"`
+	SectionID  string `json:"section_id,omitempty"`
+	PhotoUID   string `json:"photo_uid,omitempty"`
+	Body       string `json:"body"`
+	AuthorUID  string `json:"author_uid"`
+	Resolved   bool   `json:"resolved"`
+	ResolvedAt string `json:"resolved_at,omitempty"`
+	ResolvedBy string `json:"resolved_by,omitempty"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// bookCommentRequest is the body of the create comment request. Which target
+// fields are required depends on target_type.
+type bookCommentRequest struct {
+	TargetType string `json:"target_type"`
+	PageID     string `json:"page_id"`
+	SlotIndex  int    `json:"slot_index"`
+	SectionID  string `json:"section_id"`
+	PhotoUID   string `json:"photo_uid"`
+	Body       string `json:"body"`
+}
+
+func buildBookCommentResponse(c *database.BookComment) bookCommentResponse {
+	resp := bookCommentResponse{
+		ID:         c.ID,
+		BookID:     c.BookID,
+		TargetType: c.TargetType,
+		PageID:     c.PageID,
+		SectionID:  c.SectionID,
+		PhotoUID:   c.PhotoUID,
+		Body:       c.Body,
+		AuthorUID:  c.AuthorUID,
+		Resolved:   c.IsResolved(),
+		ResolvedBy: c.ResolvedBy,
+		CreatedAt:  c.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:  c.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if c.TargetType == database.CommentTargetSlot {
+		slotIndex := c.SlotIndex
+		resp.SlotIndex = &slotIndex
+	}
+	if c.ResolvedAt != nil {
+		resp.ResolvedAt = c.ResolvedAt.Format("2006-01-02T15:04:05Z")
+	}
+	return resp
+}
+
+// sessionUserUID returns the PhotoPrism user UID of the request's session,
+// or an empty string without a session.
+func sessionUserUID(r *http.Request) string {
+	if session := middleware.GetSessionFromContext(r.Context()); session != nil {
+		return session.UserUID
+	}
+	return ""
+}
+
+// ListBookComments handles GET /api/v1/books/:id/comments and returns the
+// book's review comments, oldest first. With ?unresolved=true only the
+// unresolved comments are returned.
+func (h *BooksHandler) ListBookComments(w http.ResponseWriter, r *http.Request) {
+	bw := getBookWriter(r, w)
+	if bw == nil {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	book, err := bw.GetBook(r.Context(), id)
+	if err != nil || book == nil {
+		respondError(w, http.StatusNotFound, "book not found")
+		return
+	}
+	comments, err := bw.ListBookComments(r.Context(), id, r.URL.Query().Get("unresolved") == "true")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to list comments")
+		return
+	}
+	result := make([]bookCommentResponse, len(comments))
+	for i := range comments {
+		result[i] = buildBookCommentResponse(&comments[i])
+	}
+	respondJSON(w, http.StatusOK, result)
+}
+
+// CreateBookComment handles POST /api/v1/books/:id/comments and adds a review
+// comment to the book, one of its pages or slots, or a photo in one of its
+// sections. The author is the user of the current session.
+func (h *BooksHandler) CreateBookComment(w http.ResponseWriter, r *http.Request) {
+	bw := getBookWriter(r, w)
+	if bw == nil {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	book, err := bw.GetBook(r.Context(), id)
+	if err != nil || book == nil {
+		respondError(w, http.StatusNotFound, "book not found")
+		return
+	}
+	var req bookCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, errInvalidRequestBody)
+		return
+	}
+	comment := &database.BookComment{
+		BookID:     id,
+		TargetType: req.TargetType,
+		PageID:     req.PageID,
+		SlotIndex:  req.SlotIndex,
+		SectionID:  req.SectionID,
+		PhotoUID:   req.PhotoUID,
+		Body:       strings.TrimSpace(req.Body),
+		AuthorUID:  sessionUserUID(r),
+	}
+	if err := comment.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := database.CheckBookCommentTarget(r.Context(), bw, comment); err != nil {
+		if errors.Is(err, database.ErrCommentTargetNotFound) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to check comment target")
+		return
+	}
+	if err := bw.CreateBookComment(r.Context(), comment); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to create comment")
+		return
+	}
+	respondJSON(w, http.StatusCreated, buildBookCommentResponse(comment))
+}
+
+// ResolveBookComment handles POST /api/v1/comments/:id/resolve and marks a
+// comment resolved by the user of the current session.
+func (h *BooksHandler) ResolveBookComment(w http.ResponseWriter, r *http.Request) {
+	setBookCommentResolved(w, r, true)
+}
+
+// UnresolveBookComment handles POST /api/v1/comments/:id/unresolve and
+// reopens a resolved comment.
+func (h *BooksHandler) UnresolveBookComment(w http.ResponseWriter, r *http.Request) {
+	setBookCommentResolved(w, r, false)
+}
+
+// setBookCommentResolved resolves or reopens the comment of the request and
+// responds with the updated comment.
+func setBookCommentResolved(w http.ResponseWriter, r *http.Request, resolved bool) {
+	bw := getBookWriter(r, w)
+	if bw == nil {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	if err := bw.SetBookCommentResolved(r.Context(), id, resolved, sessionUserUID(r)); err != nil {
+		if errors.Is(err, database.ErrBookCommentNotFound) {
+			respondError(w, http.StatusNotFound, "comment not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to update comment")
+		return
+	}
+	comment, err := bw.GetBookComment(r.Context(), id)
+	if err != nil || comment == nil {
+		respondError(w, http.StatusInternalServerError, "failed to get comment")
+		return
+	}
+	respondJSON(w, http.StatusOK, buildBookCommentResponse(comment))
+}
+
+// DeleteBookComment handles DELETE /api/v1/comments/:id. Only the author can
+// delete a comment; comments without a known author can be deleted by anyone.
+func (h *BooksHandler) DeleteBookComment(w http.ResponseWriter, r *http.Request) {
+	bw := getBookWriter(r, w)
+	if bw == nil {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	comment, err := bw.GetBookComment(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to get comment")
+		return
+	}
+	if comment == nil {
+		respondError(w, http.StatusNotFound, "comment not found")
+		return
+	}
+	if comment.AuthorUID != "" && comment.AuthorUID != sessionUserUID(r) {
+		respondError(w, http.StatusForbidden, "only the author can delete a comment")
+		return
+	}
+	if err := bw.DeleteBookComment(r.Context(), id); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to delete comment")
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]bool{"deleted": true})
+}
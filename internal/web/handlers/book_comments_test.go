@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/database/mock"
+	"github.com/kozaktomas/photo-sorter/internal/web/middleware"
+)
+
+// setupCommentTest creates a book with one section holding photo ph1 and one
+// 2-slot page in that section.
+func setupCommentTest(t *testing.T) (*mock.MockBookWriter, *BooksHandler) {
+	t.Helper()
+	mockBW, handler := setupBookTest(t)
+	mockBW.AddBook(database.PhotoBook{ID: "b1", Title: "Kniha"})
+	mockBW.AddSection(database.BookSection{ID: "s1", BookID: "b1", Title: "Zima"})
+	mockBW.SetSectionPhotos("s1", []database.SectionPhoto{{SectionID: "s1", PhotoUID: "ph1"}})
+	mockBW.AddPage(database.BookPage{ID: "pg1", BookID: "b1", SectionID: "s1", Format: "2_portrait"})
+	return mockBW, handler
+}
+
+// commentRequest builds a request with chi params and a session of userUID.
+func commentRequest(method, path, body, userUID string, params map[string]string) *http.Request {
+	req := httptest.NewRequestWithContext(context.Background(), method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := middleware.SetSessionInContext(req.Context(), &middleware.Session{ID: "sess", UserUID: userUID})
+	return requestWithChiParams(req.WithContext(ctx), params)
+}
+
+func TestBooksHandler_CreateBookComment_Success(t *testing.T) {
+	mockBW, handler := setupCommentTest(t)
+
+	req := commentRequest("POST", "/api/v1/books/b1/comments",
+		`{"target_type":"slot","page_id":"pg1","slot_index":1,"body":"  Oříznout hlavu  "}`,
+		"user-1", map[string]string{"id": "b1"})
+	recorder := httptest.NewRecorder()
+	handler.CreateBookComment(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusCreated)
+	var resp bookCommentResponse
+	parseJSONResponse(t, recorder, &resp)
+	if resp.Body != "Oříznout hlavu" || resp.AuthorUID != "user-1" || resp.SlotIndex == nil || *resp.SlotIndex != 1 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	stored, _ := mockBW.ListBookComments(context.Background(), "b1", true)
+	if len(stored) != 1 || stored[0].PageID != "pg1" {
+		t.Errorf("unexpected stored comments: %+v", stored)
+	}
+}
+
+func TestBooksHandler_CreateBookComment_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		want   string
+	}{
+		{"empty body", `{"target_type":"book","body":" "}`, http.StatusBadRequest, "body is required"},
+		{"unknown target", `{"target_type":"chapter","body":"x"}`, http.StatusBadRequest,
+			`invalid target_type "chapter"`},
+		{"missing page", `{"target_type":"page","page_id":"nope","body":"x"}`, http.StatusBadRequest,
+			"comment target not found: page nope is not in the book"},
+		{"slot out of range", `{"target_type":"slot","page_id":"pg1","slot_index":2,"body":"x"}`,
+			http.StatusBadRequest, "comment target not found: page has no slot 2"},
+		{"photo not in section", `{"target_type":"section_photo","section_id":"s1","photo_uid":"ph9","body":"x"}`,
+			http.StatusBadRequest, "comment target not found: photo ph9 is not in the section"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, handler := setupCommentTest(t)
+
+			req := commentRequest("POST", "/api/v1/books/b1/comments", tt.body, "user-1",
+				map[string]string{"id": "b1"})
+			recorder := httptest.NewRecorder()
+			handler.CreateBookComment(recorder, req)
+
+			assertStatusCode(t, recorder, tt.status)
+			assertJSONError(t, recorder, tt.want)
+		})
+	}
+}
+
+func TestBooksHandler_ListBookComments_Unresolved(t *testing.T) {
+	mockBW, handler := setupCommentTest(t)
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	mockBW.AddBookComment(database.BookComment{
+		ID: "c1", BookID: "b1", TargetType: database.CommentTargetBook, Body: "a", CreatedAt: now,
+	})
+	mockBW.AddBookComment(database.BookComment{
+		ID: "c2", BookID: "b1", TargetType: database.CommentTargetBook, Body: "b", CreatedAt: now.Add(time.Hour),
+		ResolvedAt: &now, ResolvedBy: "user-2",
+	})
+
+	for query, want := range map[string]int{"": 2, "?unresolved=true": 1} {
+		req := commentRequest("GET", "/api/v1/books/b1/comments"+query, "", "user-1",
+			map[string]string{"id": "b1"})
+		recorder := httptest.NewRecorder()
+		handler.ListBookComments(recorder, req)
+
+		assertStatusCode(t, recorder, http.StatusOK)
+		var resp []bookCommentResponse
+		parseJSONResponse(t, recorder, &resp)
+		if len(resp) != want || resp[0].ID != "c1" {
+			t.Errorf("%q: got %+v, want %d comments starting with c1", query, resp, want)
+		}
+	}
+}
+
+func TestBooksHandler_ResolveBookComment(t *testing.T) {
+	mockBW, handler := setupCommentTest(t)
+	mockBW.AddBookComment(database.BookComment{ID: "c1", BookID: "b1", TargetType: database.CommentTargetBook})
+
+	recorder := httptest.NewRecorder()
+	handler.ResolveBookComment(recorder,
+		commentRequest("POST", "/api/v1/comments/c1/resolve", "", "user-2", map[string]string{"id": "c1"}))
+	assertStatusCode(t, recorder, http.StatusOK)
+	var resp bookCommentResponse
+	parseJSONResponse(t, recorder, &resp)
+	if !resp.Resolved || resp.ResolvedBy != "user-2" || resp.ResolvedAt == "" {
+		t.Errorf("unexpected resolved comment: %+v", resp)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.UnresolveBookComment(recorder,
+		commentRequest("POST", "/api/v1/comments/c1/unresolve", "", "user-2", map[string]string{"id": "c1"}))
+	assertStatusCode(t, recorder, http.StatusOK)
+	resp = bookCommentResponse{}
+	parseJSONResponse(t, recorder, &resp)
+	if resp.Resolved || resp.ResolvedBy != "" {
+		t.Errorf("unexpected reopened comment: %+v", resp)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ResolveBookComment(recorder,
+		commentRequest("POST", "/api/v1/comments/nope/resolve", "", "user-2", map[string]string{"id": "nope"}))
+	assertStatusCode(t, recorder, http.StatusNotFound)
+}
+
+func TestBooksHandler_DeleteBookComment_OnlyAuthor(t *testing.T) {
+	mockBW, handler := setupCommentTest(t)
+	mockBW.AddBookComment(database.BookComment{
+		ID: "c1", BookID: "b1", TargetType: database.CommentTargetBook, AuthorUID: "user-1",
+	})
+
+	recorder := httptest.NewRecorder()
+	handler.DeleteBookComment(recorder,
+		commentRequest("DELETE", "/api/v1/comments/c1", "", "user-2", map[string]string{"id": "c1"}))
+	assertStatusCode(t, recorder, http.StatusForbidden)
+
+	recorder = httptest.NewRecorder()
+	handler.DeleteBookComment(recorder,
+		commentRequest("DELETE", "/api/v1/comments/c1", "", "user-1", map[string]string{"id": "c1"}))
+	assertStatusCode(t, recorder, http.StatusOK)
+	if c, _ := mockBW.GetBookComment(context.Background(), "c1"); c != nil {
+		t.Error("comment still exists after delete")
+	}
+}
+
+func TestCheckUnresolvedComments(t *testing.T) {
+	mockBW, _ := setupCommentTest(t)
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	mockBW.AddBookComment(database.BookComment{
+		ID: "c1", BookID: "b1", TargetType: database.CommentTargetSlot, PageID: "pg2", SlotIndex: 1,
+		Body: "Oříznout", CreatedAt: now,
+	})
+	mockBW.AddBookComment(database.BookComment{
+		ID: "c2", BookID: "b1", TargetType: database.CommentTargetSectionPhoto, SectionID: "s1",
+		PhotoUID: "ph1", Body: "Kdo to je?", CreatedAt: now.Add(time.Minute),
+	})
+	mockBW.AddBookComment(database.BookComment{
+		ID: "c3", BookID: "b1", TargetType: database.CommentTargetBook, Body: "Hotovo",
+		CreatedAt: now.Add(time.Hour), ResolvedAt: &now,
+	})
+	data := &preflightData{
+		pages:       []database.BookPage{{ID: "pg1", SectionID: "s1"}, {ID: "pg2", SectionID: "s2"}},
+		sectionByID: map[string]string{"s1": "Zima", "s2": "Jaro"},
+	}
+	result := &preflightResult{}
+
+	req := httptest.NewRequestWithContext(context.Background(), "GET", "/api/v1/books/b1/preflight", nil)
+	checkUnresolvedComments(req, mockBW, "b1", data, result)
+
+	want := []preflightIssue{
+		{Type: "unresolved_comment", PageNumber: 2, Section: "Jaro", SlotIndex: 1,
+			CommentID: "c1", Target: "slot", Comment: "Oříznout"},
+		{Type: "unresolved_comment", Section: "Zima", PhotoUID: "ph1",
+			CommentID: "c2", Target: "section_photo", Comment: "Kdo to je?"},
+	}
+	if len(result.warnings) != len(want) {
+		t.Fatalf("warnings = %+v, want %+v", result.warnings, want)
+	}
+	for i := range want {
+		if result.warnings[i] != want[i] {
+			t.Errorf("warning %d = %+v, want %+v", i, result.warnings[i], want[i])
+		}
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -15,6 +16,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/kozaktomas/photo-sorter/internal/database"
 	"github.com/kozaktomas/photo-sorter/internal/latex"
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
 	"github.com/kozaktomas/photo-sorter/internal/web/middleware"
 )
 
@@ -28,9 +30,17 @@ const (
 	bookExportSweepInterval = time.Minute
 )
 
-// BookExportJob represents an async PDF export job for a photo book.
-// Progress is reported via SSE events; the finished PDF is served from a
-// temp file (see pdfPath) to avoid keeping 700 MB in memory.
+// Output formats of a book export job, selected by the "output" query
+// parameter. The HTML web book is delivered as a ZIP archive.
+const (
+	exportOutputPDF  = "pdf"
+	exportOutputHTML = "html"
+)
+
+// BookExportJob represents an async export job for a photo book, producing
+// either a PDF or a zipped HTML web book. Progress is reported via SSE
+// events; the finished file is served from a temp file (see filePath) to
+// avoid keeping 700 MB in memory.
 type BookExportJob struct {
 	EventBroadcaster
 
@@ -49,8 +59,11 @@ type BookExportJob struct {
 	Consumed     bool               `json:"consumed"`
 	Debug        bool               `json:"debug,omitempty"`
 	PhotoQuality latex.PhotoQuality `json:"photo_quality,omitempty"`
+	Output       string             `json:"output"`
+	Proof        bool               `json:"proof,omitempty"`
+	Watermark    string             `json:"watermark,omitempty"`
 
-	pdfPath   string
+	filePath  string
 	expiresAt time.Time
 }
 
@@ -73,6 +86,54 @@ func (j *BookExportJob) Cancel() {
 	j.mu.Unlock()
 }
 
+// markRunning transitions the job to running (implements persistentJob).
+func (j *BookExportJob) markRunning() {
+	j.mu.Lock()
+	j.Status = JobStatusRunning
+	j.mu.Unlock()
+}
+
+// bookExportJobParams is the persisted input of a book export job.
+type bookExportJobParams struct {
+	BookID       string             `json:"book_id"`
+	BookTitle    string             `json:"book_title"`
+	Debug        bool               `json:"debug,omitempty"`
+	PhotoQuality latex.PhotoQuality `json:"photo_quality,omitempty"`
+	Output       string             `json:"output,omitempty"`
+	Proof        bool               `json:"proof,omitempty"`
+	Watermark    string             `json:"watermark,omitempty"`
+}
+
+// bookExportJobResult is the persisted outcome of a completed export. The
+// exported file itself is a temp file and is not persisted.
+type bookExportJobResult struct {
+	Filename string `json:"filename"`
+	FileSize int64  `json:"file_size"`
+}
+
+// snapshot returns the job's persisted representation (implements persistentJob).
+func (j *BookExportJob) snapshot() database.StoredJob {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	var result *bookExportJobResult
+	if j.Status == JobStatusCompleted {
+		result = &bookExportJobResult{Filename: j.Filename, FileSize: j.FileSize}
+	}
+	params := bookExportJobParams{
+		BookID: j.BookID, BookTitle: j.BookTitle, Debug: j.Debug, PhotoQuality: j.PhotoQuality, Output: j.Output,
+		Proof: j.Proof, Watermark: j.Watermark,
+	}
+	stored := newStoredJob(j.ID, database.JobTypeBookExport, j.Status, j.Error, j.StartedAt, j.CompletedAt,
+		params, result)
+	stored.Progress = percentOf(j.Current, j.Total)
+	stored.Total = j.Total
+	stored.Processed = j.Current
+	if j.Status == JobStatusCompleted {
+		stored.Progress = 100
+	}
+	return stored
+}
+
 // isExpired reports whether the job is past its TTL window.
 func (j *BookExportJob) isExpired(now time.Time) bool {
 	j.mu.RLock()
@@ -80,11 +141,11 @@ func (j *BookExportJob) isExpired(now time.Time) bool {
 	return !j.expiresAt.IsZero() && now.After(j.expiresAt)
 }
 
-// removeTempFile deletes the backing PDF file if present. Idempotent.
+// removeTempFile deletes the backing export file if present. Idempotent.
 func (j *BookExportJob) removeTempFile() {
 	j.mu.Lock()
-	path := j.pdfPath
-	j.pdfPath = ""
+	path := j.filePath
+	j.filePath = ""
 	j.mu.Unlock()
 	if path == "" {
 		return
@@ -116,10 +177,16 @@ func NewBookExportJobManager() *BookExportJobManager {
 
 // CreateJob atomically creates a new job for a book. Returns the new job on
 // success, or the existing active job (with err != nil) if one is already
-// running for the same book.
+// running for the same book. An empty output defaults to PDF.
 func (m *BookExportJobManager) CreateJob(
-	id, bookID, bookTitle string, debug bool, quality latex.PhotoQuality,
+	id string, params bookExportJobParams,
 ) (*BookExportJob, *BookExportJob, error) {
+	bookID := params.BookID
+	output := params.Output
+	if output == "" {
+		output = exportOutputPDF
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -138,11 +205,14 @@ func (m *BookExportJobManager) CreateJob(
 	job := &BookExportJob{
 		ID:           id,
 		BookID:       bookID,
-		BookTitle:    bookTitle,
+		BookTitle:    params.BookTitle,
 		Status:       JobStatusPending,
 		StartedAt:    time.Now(),
-		Debug:        debug,
-		PhotoQuality: quality,
+		Debug:        params.Debug,
+		PhotoQuality: params.PhotoQuality,
+		Output:       output,
+		Proof:        params.Proof,
+		Watermark:    params.Watermark,
 	}
 	m.jobs[id] = job
 	return job, nil, nil
@@ -227,6 +297,8 @@ func (m *BookExportJobManager) sweep() {
 // StartExportJob handles POST /api/v1/books/{id}/export-pdf/job.
 // It validates the request, creates a background job, and returns 202 with
 // the job ID. The job runs in a goroutine and reports progress via SSE.
+// ?output=html exports a zipped HTML web book instead of the PDF;
+// ?format=proof exports a watermarked low-resolution review PDF.
 func (h *BooksHandler) StartExportJob(w http.ResponseWriter, r *http.Request) {
 	pp := middleware.MustGetPhotoPrism(r.Context(), w)
 	if pp == nil {
@@ -244,22 +316,24 @@ func (h *BooksHandler) StartExportJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if _, err := exec.LookPath("lualatex"); err != nil {
-		respondError(w, http.StatusServiceUnavailable, "lualatex is not installed on the server")
+	params, errMsg := parseExportJobParams(r)
+	if errMsg != "" {
+		respondError(w, http.StatusBadRequest, errMsg)
 		return
 	}
-
-	debug := r.URL.Query().Get("format") == exportFormatDebug
-	quality, err := latex.ValidatePhotoQuality(r.URL.Query().Get("photo_quality"))
-	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
-		return
+	params.BookID = bookID
+	params.BookTitle = book.Title
+	if params.Output == exportOutputPDF {
+		if _, err := exec.LookPath("lualatex"); err != nil {
+			respondError(w, http.StatusServiceUnavailable, "lualatex is not installed on the server")
+			return
+		}
 	}
 
 	session := middleware.GetSessionFromContext(r.Context())
 	jobID := uuid.New().String()
 
-	job, existing, err := h.exportJobs.CreateJob(jobID, bookID, book.Title, debug, quality)
+	job, existing, err := h.exportJobs.CreateJob(jobID, params)
 	if err != nil {
 		respondJSON(w, http.StatusConflict, map[string]any{
 			"error":  "export already in progress for this book",
@@ -276,10 +350,46 @@ func (h *BooksHandler) StartExportJob(w http.ResponseWriter, r *http.Request) {
 		"book_id":       bookID,
 		"book_title":    book.Title,
 		"status":        string(JobStatusPending),
-		"photo_quality": string(quality),
+		"photo_quality": string(params.PhotoQuality),
+		"output":        params.Output,
+		"proof":         params.Proof,
 	})
 }
 
+// parseExportJobParams reads the export options of a job from the query
+// string: format (debug|proof), photo_quality, output (pdf|html), and the
+// proof watermark. Returns a non-empty error message on invalid input.
+func parseExportJobParams(r *http.Request) (bookExportJobParams, string) {
+	q := r.URL.Query()
+	quality, err := latex.ValidatePhotoQuality(q.Get("photo_quality"))
+	if err != nil {
+		return bookExportJobParams{}, err.Error()
+	}
+	params := bookExportJobParams{
+		Debug:        q.Get("format") == exportFormatDebug,
+		Proof:        q.Get("format") == exportFormatProof,
+		PhotoQuality: quality,
+		Output:       q.Get("output"),
+	}
+	switch params.Output {
+	case "":
+		params.Output = exportOutputPDF
+	case exportOutputPDF, exportOutputHTML:
+	default:
+		return bookExportJobParams{}, "invalid output: must be pdf or html"
+	}
+	if params.Proof {
+		if params.Output != exportOutputPDF {
+			return bookExportJobParams{}, "invalid output: proofs are exported as pdf only"
+		}
+		params.Watermark = q.Get("watermark")
+		if err := latex.ValidateProofWatermark(params.Watermark); err != nil {
+			return bookExportJobParams{}, err.Error()
+		}
+	}
+	return params, ""
+}
+
 // GetExportJob handles GET /api/v1/book-export/{jobId}.
 func (h *BooksHandler) GetExportJob(w http.ResponseWriter, r *http.Request) {
 	jobID := chi.URLParam(r, "jobId")
@@ -308,7 +418,7 @@ func (h *BooksHandler) StreamExportJobEvents(w http.ResponseWriter, r *http.Requ
 }
 
 // DownloadExport handles GET /api/v1/book-export/{jobId}/download. Streams
-// the compiled PDF temp file via http.ServeContent (which handles
+// the exported temp file via http.ServeContent (which handles
 // Content-Length and range requests automatically). On successful serve,
 // the job's TTL is shortened to the "consumed" window.
 func (h *BooksHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
@@ -321,8 +431,9 @@ func (h *BooksHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
 
 	job.mu.RLock()
 	status := job.Status
-	path := job.pdfPath
+	path := job.filePath
 	filename := job.Filename
+	output := job.Output
 	completedAt := job.CompletedAt
 	expired := !job.expiresAt.IsZero() && time.Now().After(job.expiresAt)
 	job.mu.RUnlock()
@@ -343,15 +454,19 @@ func (h *BooksHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
 	}
 	defer file.Close()
 
+	contentType := "application/pdf"
+	if output == exportOutputHTML {
+		contentType = "application/zip"
+	}
 	if filename == "" {
-		filename = "book.pdf"
+		filename = sanitizeExportFilename("", output)
 	}
 	modTime := time.Now()
 	if completedAt != nil {
 		modTime = *completedAt
 	}
 
-	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Type", contentType)
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
 	// Disable nginx/proxy buffering so the browser sees chunks as they
 	// arrive over the wire (required for download progress to be meaningful).
@@ -387,29 +502,48 @@ func (h *BooksHandler) CancelExportJob(w http.ResponseWriter, r *http.Request) {
 // runBookExportJob is the background goroutine that actually runs the
 // export. It instantiates its own PhotoPrism client from the session (the
 // request context is gone by the time this runs), calls latex with a
-// progress translator, writes the PDF to a temp file, and emits the
-// terminal SSE event.
+// progress translator, writes the PDF or web book to a temp file, and
+// emits the terminal SSE event.
 func (h *BooksHandler) runBookExportJob(job *BookExportJob, session *middleware.Session) {
-	ctx, cancel := context.WithCancel(context.Background())
-	job.cancel = cancel
-	defer cancel()
+	runPersistentJob(job, "Book export started", func(ctx context.Context) {
+		if job.Output == exportOutputHTML {
+			tmpPath, size, ok := h.generateWebBook(ctx, job, session)
+			if ok {
+				h.finalizeBookExport(job, tmpPath, size)
+			}
+			return
+		}
 
-	job.mu.Lock()
-	job.Status = JobStatusRunning
-	job.mu.Unlock()
-	job.SendEvent(JobEvent{Type: "started", Message: "Book export started"})
+		pdfData, ok := h.generateBookPDF(ctx, job, session)
+		if !ok {
+			return
+		}
 
-	pdfData, ok := h.generateBookPDF(ctx, job, session)
-	if !ok {
-		return
-	}
+		tmpPath, ok := h.materializeExportFile(job, pdfData)
+		if !ok {
+			return
+		}
 
-	tmpPath, ok := h.materializeExportFile(job, pdfData)
-	if !ok {
-		return
+		h.finalizeBookExport(job, tmpPath, int64(len(pdfData)))
+	})
+}
+
+// ResumeJob restarts a book export interrupted by a server restart
+// (implements JobResumer). The export is regenerated from scratch under the
+// original job ID so a client polling that ID picks up the new run.
+func (h *BooksHandler) ResumeJob(stored database.StoredJob) error {
+	var params bookExportJobParams
+	if err := json.Unmarshal(stored.Options, &params); err != nil {
+		return fmt.Errorf("decoding book export options: %w", err)
 	}
+	job, _, err := h.exportJobs.CreateJob(stored.ID, params)
+	if err != nil {
+		return err
+	}
+	job.StartedAt = stored.StartedAt
 
-	h.finalizeBookExport(job, tmpPath, pdfData)
+	go h.runBookExportJob(job, nil)
+	return nil
 }
 
 // generateBookPDF runs the latex pipeline with progress translation. Returns
@@ -418,30 +552,17 @@ func (h *BooksHandler) runBookExportJob(job *BookExportJob, session *middleware.
 func (h *BooksHandler) generateBookPDF(
 	ctx context.Context, job *BookExportJob, session *middleware.Session,
 ) ([]byte, bool) {
-	pp, err := getPhotoPrismClient(h.config, session)
-	if err != nil {
-		h.failBookExportJob(job, "failed to connect to PhotoPrism: "+err.Error())
-		return nil, false
-	}
-
-	bw, err := database.GetBookWriter(ctx)
-	if err != nil {
-		h.failBookExportJob(job, "book storage not available: "+err.Error())
+	pp, bw, ok := h.openExportSources(ctx, job, session)
+	if !ok {
 		return nil, false
 	}
 
-	job.mu.Lock()
-	job.Phase = "fetching_metadata"
-	job.mu.Unlock()
-	job.SendEvent(JobEvent{
-		Type: "progress",
-		Data: map[string]any{"phase": "fetching_metadata"},
-	})
-
 	opts := latex.ExportOptions{
-		Debug:        job.Debug,
-		OnProgress:   h.exportProgressTranslator(job),
-		PhotoQuality: job.PhotoQuality,
+		Debug:          job.Debug,
+		OnProgress:     h.exportProgressTranslator(job),
+		PhotoQuality:   job.PhotoQuality,
+		Proof:          job.Proof,
+		ProofWatermark: job.Watermark,
 	}
 	pdfData, _, err := latex.GeneratePDFWithCallbacks(ctx, pp, bw, job.BookID, opts)
 	if err != nil {
@@ -461,6 +582,75 @@ func (h *BooksHandler) generateBookPDF(
 	return pdfData, true
 }
 
+// generateWebBook renders the HTML web book straight into a temp ZIP file.
+// Returns the path and size on success, or ("", 0, false) after emitting
+// the appropriate terminal event (fail/cancel) on failure.
+func (h *BooksHandler) generateWebBook(
+	ctx context.Context, job *BookExportJob, session *middleware.Session,
+) (string, int64, bool) {
+	pp, bw, ok := h.openExportSources(ctx, job, session)
+	if !ok {
+		return "", 0, false
+	}
+
+	tmpFile, err := os.CreateTemp("", "book-export-*.zip")
+	if err != nil {
+		h.failBookExportJob(job, "failed to create temp file: "+err.Error())
+		return "", 0, false
+	}
+	tmpPath := tmpFile.Name()
+	err = latex.WriteWebBook(ctx, pp, bw, job.BookID, tmpFile, h.exportProgressTranslator(job))
+	if closeErr := tmpFile.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close temp file: %w", closeErr)
+	}
+	if err != nil || ctx.Err() != nil {
+		_ = os.Remove(tmpPath)
+		if ctx.Err() != nil {
+			h.cancelBookExportJob(job)
+		} else {
+			h.failBookExportJob(job, fmt.Sprintf("web book generation failed: %v", err))
+		}
+		return "", 0, false
+	}
+
+	info, err := os.Stat(tmpPath)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		h.failBookExportJob(job, "failed to stat temp file: "+err.Error())
+		return "", 0, false
+	}
+	return tmpPath, info.Size(), true
+}
+
+// openExportSources connects to PhotoPrism and the book storage for a
+// background export and emits the initial "fetching_metadata" progress
+// event. Returns ok=false after emitting a failed event.
+func (h *BooksHandler) openExportSources(
+	ctx context.Context, job *BookExportJob, session *middleware.Session,
+) (*photoprism.PhotoPrism, database.BookWriter, bool) {
+	pp, err := getPhotoPrismClient(h.config, session)
+	if err != nil {
+		h.failBookExportJob(job, "failed to connect to PhotoPrism: "+err.Error())
+		return nil, nil, false
+	}
+	pp = pp.WithContext(ctx)
+
+	bw, err := database.GetBookWriter(ctx)
+	if err != nil {
+		h.failBookExportJob(job, "book storage not available: "+err.Error())
+		return nil, nil, false
+	}
+
+	job.mu.Lock()
+	job.Phase = "fetching_metadata"
+	job.mu.Unlock()
+	job.SendEvent(JobEvent{
+		Type: "progress",
+		Data: map[string]any{"phase": "fetching_metadata"},
+	})
+	return pp, bw, true
+}
+
 // materializeExportFile writes the PDF bytes to a temp file on disk. Returns
 // the path on success, or ("", false) after emitting a failed event.
 func (h *BooksHandler) materializeExportFile(job *BookExportJob, pdfData []byte) (string, bool) {
@@ -487,8 +677,12 @@ func (h *BooksHandler) materializeExportFile(job *BookExportJob, pdfData []byte)
 // finalizeBookExport atomically transitions the job to completed and emits
 // the "completed" SSE event. If the job has been cancelled while we were
 // writing, the temp file is discarded instead of overwriting the status.
-func (h *BooksHandler) finalizeBookExport(job *BookExportJob, tmpPath string, pdfData []byte) {
-	filename := sanitizePDFFilename(job.BookTitle)
+func (h *BooksHandler) finalizeBookExport(job *BookExportJob, tmpPath string, size int64) {
+	title := job.BookTitle
+	if job.Proof && job.Output == exportOutputPDF {
+		title += "-proof"
+	}
+	filename := sanitizeExportFilename(title, job.Output)
 	now := time.Now()
 
 	job.mu.Lock()
@@ -500,8 +694,8 @@ func (h *BooksHandler) finalizeBookExport(job *BookExportJob, tmpPath string, pd
 	job.Status = JobStatusCompleted
 	job.CompletedAt = &now
 	job.Filename = filename
-	job.FileSize = int64(len(pdfData))
-	job.pdfPath = tmpPath
+	job.FileSize = size
+	job.filePath = tmpPath
 	job.Phase = "done"
 	job.expiresAt = now.Add(bookExportUnconsumedTTL)
 	job.mu.Unlock()
@@ -511,7 +705,7 @@ func (h *BooksHandler) finalizeBookExport(job *BookExportJob, tmpPath string, pd
 		Data: map[string]any{
 			"job_id":       job.ID,
 			"filename":     filename,
-			"file_size":    len(pdfData),
+			"file_size":    size,
 			"download_url": "/api/v1/book-export/" + job.ID + "/download",
 		},
 	})
@@ -564,10 +758,15 @@ func (h *BooksHandler) cancelBookExportJob(job *BookExportJob) {
 // Helpers.
 // ============================================================================
 
-// sanitizePDFFilename produces a safe Content-Disposition filename from a
+// sanitizeExportFilename produces a safe Content-Disposition filename from a
 // book title. Replaces characters that confuse HTTP parsers (quotes,
-// newlines, backslashes) with underscores and appends ".pdf".
-func sanitizePDFFilename(title string) string {
+// newlines, backslashes) with underscores and appends ".zip" for web book
+// exports or ".pdf" otherwise.
+func sanitizeExportFilename(title, output string) string {
+	ext := ".pdf"
+	if output == exportOutputHTML {
+		ext = ".zip"
+	}
 	if title == "" {
 		title = "book"
 	}
@@ -581,5 +780,5 @@ func sanitizePDFFilename(title string) string {
 			b = append(b, c)
 		}
 	}
-	return string(b) + ".pdf"
+	return string(b) + ext
 }
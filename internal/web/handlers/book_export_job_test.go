@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kozaktomas/photo-sorter/internal/latex"
+)
+
+func TestParseExportJobParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    bookExportJobParams
+		wantErr bool
+	}{
+		{"defaults", "", bookExportJobParams{PhotoQuality: latex.QualityMedium, Output: exportOutputPDF}, false},
+		{"debug", "?format=debug&photo_quality=low",
+			bookExportJobParams{Debug: true, PhotoQuality: latex.QualityLow, Output: exportOutputPDF}, false},
+		{"proof with watermark", "?format=proof&watermark=KONCEPT",
+			bookExportJobParams{Proof: true, Watermark: "KONCEPT", PhotoQuality: latex.QualityMedium, Output: exportOutputPDF},
+			false},
+		{"watermark ignored without proof", "?watermark=KONCEPT",
+			bookExportJobParams{PhotoQuality: latex.QualityMedium, Output: exportOutputPDF}, false},
+		{"html", "?output=html", bookExportJobParams{PhotoQuality: latex.QualityMedium, Output: exportOutputHTML}, false},
+		{"invalid output", "?output=epub", bookExportJobParams{}, true},
+		{"invalid quality", "?photo_quality=ultra", bookExportJobParams{}, true},
+		{"multi-line watermark", "?format=proof&watermark=a%0Ab", bookExportJobParams{}, true},
+		{"proof html", "?format=proof&output=html", bookExportJobParams{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/books/b1/export-pdf/job"+tt.query, nil)
+			got, errMsg := parseExportJobParams(r)
+			if (errMsg != "") != tt.wantErr {
+				t.Fatalf("errMsg = %q, wantErr %v", errMsg, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("params = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeExportFilename(t *testing.T) {
+	if got := sanitizeExportFilename(`My "Book"`, exportOutputPDF); got != "My _Book_.pdf" {
+		t.Errorf("pdf filename = %q", got)
+	}
+	if got := sanitizeExportFilename("", exportOutputHTML); got != "book.zip" {
+		t.Errorf("html filename = %q", got)
+	}
+}
@@ -34,6 +34,7 @@ const (
 	exportFormatTest   = "test"
 	exportFormatDebug  = "debug"
 	exportFormatReport = "report"
+	exportFormatProof  = "proof"
 )
 
 // Book page style values (for goconst — referenced from several places).
@@ -95,6 +96,11 @@ type bookDetailResponse struct {
 	HeadingColorBleed float64           `json:"heading_color_bleed"`
 	CaptionBadgeSize  float64           `json:"caption_badge_size"`
 	BodyTextPadMM     float64           `json:"body_text_pad_mm"`
+	PageSize          string            `json:"page_size"`
+	Orientation       string            `json:"orientation"`
+	BleedMM           float64           `json:"bleed_mm"`
+	PageWidthMM       float64           `json:"page_width_mm"`
+	PageHeightMM      float64           `json:"page_height_mm"`
 	Chapters          []chapterResponse `json:"chapters"`
 	Sections          []sectionResponse `json:"sections"`
 	Pages             []pageResponse    `json:"pages"`
@@ -128,15 +134,16 @@ type sectionPhotoResponse struct {
 }
 
 type pageResponse struct {
-	ID             string         `json:"id"`
-	SectionID      string         `json:"section_id"`
-	Format         string         `json:"format"`
-	Style          string         `json:"style"`
-	Description    string         `json:"description"`
-	SplitPosition  *float64       `json:"split_position"`
-	HidePageNumber bool           `json:"hide_page_number"`
-	SortOrder      int            `json:"sort_order"`
-	Slots          []slotResponse `json:"slots"`
+	ID             string              `json:"id"`
+	SectionID      string              `json:"section_id"`
+	Format         string              `json:"format"`
+	Style          string              `json:"style"`
+	Description    string              `json:"description"`
+	SplitPosition  *float64            `json:"split_position"`
+	HidePageNumber bool                `json:"hide_page_number"`
+	SortOrder      int                 `json:"sort_order"`
+	Slots          []slotResponse      `json:"slots"`
+	Layout         *pageLayoutResponse `json:"layout,omitempty"`
 }
 
 type slotResponse struct {
@@ -326,6 +333,7 @@ func buildPageResponses(pages []database.BookPage) []pageResponse {
 			HidePageNumber: p.HidePageNumber,
 			SortOrder:      p.SortOrder,
 			Slots:          slots,
+			Layout:         buildPageLayoutResponse(p.Layout),
 		}
 	}
 	return pageResps
@@ -357,6 +365,7 @@ func buildBookDetailResponse(
 		}
 	}
 
+	layout := latex.BookLayoutConfig(book)
 	return bookDetailResponse{
 		ID:                book.ID,
 		Title:             book.Title,
@@ -372,6 +381,11 @@ func buildBookDetailResponse(
 		HeadingColorBleed: book.HeadingColorBleed,
 		CaptionBadgeSize:  book.CaptionBadgeSize,
 		BodyTextPadMM:     book.BodyTextPadMM,
+		PageSize:          book.PageSize,
+		Orientation:       book.Orientation,
+		BleedMM:           book.BleedMM,
+		PageWidthMM:       layout.PageWidthMM,
+		PageHeightMM:      layout.PageHeightMM,
 		Chapters:          chapterResps,
 		Sections:          sectionResps,
 		Pages:             buildPageResponses(pages),
@@ -407,6 +421,9 @@ type bookUpdateRequest struct {
 	HeadingColorBleed *float64 `json:"heading_color_bleed"`
 	CaptionBadgeSize  *float64 `json:"caption_badge_size"`
 	BodyTextPadMM     *float64 `json:"body_text_pad_mm"`
+	PageSize          *string  `json:"page_size"`
+	Orientation       *string  `json:"orientation"`
+	BleedMM           *float64 `json:"bleed_mm"`
 }
 
 // applyTo validates and applies the update request fields to a book.
@@ -421,6 +438,9 @@ func (req *bookUpdateRequest) applyTo(book *database.PhotoBook) string {
 	if msg := req.applyFonts(book); msg != "" {
 		return msg
 	}
+	if msg := req.applyPageSetup(book); msg != "" {
+		return msg
+	}
 	return req.applySizes(book)
 }
 
@@ -440,6 +460,22 @@ func (req *bookUpdateRequest) applyFonts(book *database.PhotoBook) string {
 	return ""
 }
 
+func (req *bookUpdateRequest) applyPageSetup(book *database.PhotoBook) string {
+	if req.PageSize != nil {
+		if !latex.ValidatePageSize(*req.PageSize) {
+			return "invalid page_size"
+		}
+		book.PageSize = *req.PageSize
+	}
+	if req.Orientation != nil {
+		if !latex.ValidateOrientation(*req.Orientation) {
+			return "invalid orientation"
+		}
+		book.Orientation = *req.Orientation
+	}
+	return ""
+}
+
 func (req *bookUpdateRequest) applySizes(book *database.PhotoBook) string {
 	ranges := []struct {
 		val    *float64
@@ -456,6 +492,7 @@ func (req *bookUpdateRequest) applySizes(book *database.PhotoBook) string {
 		{req.HeadingColorBleed, 0, 20, "heading_color_bleed", &book.HeadingColorBleed},
 		{req.CaptionBadgeSize, 2, 12, "caption_badge_size", &book.CaptionBadgeSize},
 		{req.BodyTextPadMM, 0, 10, "body_text_pad_mm", &book.BodyTextPadMM},
+		{req.BleedMM, 0, latex.MaxBleedMM, "bleed_mm", &book.BleedMM},
 	}
 	for _, r := range ranges {
 		if msg := validateRange(r.val, r.lo, r.hi, r.name); msg != "" {
@@ -479,7 +516,8 @@ func validateRange(v *float64, lo, hi float64, name string) string {
 	return ""
 }
 
-// UpdateBook handles PUT /api/v1/books/:id and updates a book's title, description, and typography.
+// UpdateBook handles PUT /api/v1/books/:id and updates a book's title, description, typography
+// and page setup.
 func (h *BooksHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 	bw := getBookWriter(r, w)
 	if bw == nil {
@@ -870,8 +908,9 @@ func (h *BooksHandler) CreatePage(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusBadRequest, errInvalidRequestBody)
 		return
 	}
-	if database.PageFormatSlotCount(req.Format) == 0 {
-		respondError(w, http.StatusBadRequest, "invalid format")
+	layout, err := resolvePageFormat(r.Context(), bw, req.Format)
+	if err != nil {
+		respondPageFormatError(w, err)
 		return
 	}
 	if req.SectionID == "" {
@@ -882,7 +921,9 @@ func (h *BooksHandler) CreatePage(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusBadRequest, "style must be 'modern' or 'archival'")
 		return
 	}
-	page := &database.BookPage{BookID: bookID, SectionID: req.SectionID, Format: req.Format, Style: req.Style}
+	page := &database.BookPage{
+		BookID: bookID, SectionID: req.SectionID, Format: req.Format, Style: req.Style, Layout: layout,
+	}
 	if err := bw.CreatePage(r.Context(), page); err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to create page")
 		return
@@ -897,6 +938,7 @@ func (h *BooksHandler) CreatePage(w http.ResponseWriter, r *http.Request) {
 		HidePageNumber: page.HidePageNumber,
 		SortOrder:      page.SortOrder,
 		Slots:          []slotResponse{},
+		Layout:         buildPageLayoutResponse(page.Layout),
 	})
 }
 
@@ -953,12 +995,15 @@ func applySectionIDUpdate(page *database.BookPage, sectionID *string) string {
 	return ""
 }
 
+// applyFormatUpdate applies a format change. For a custom layout the caller
+// sets page.Layout to the resolved layout first; split positions only apply
+// to built-in formats.
 func applyFormatUpdate(page *database.BookPage, format string) string {
-	if database.PageFormatSlotCount(format) == 0 {
+	if page.Layout == nil && database.PageFormatSlotCount(format) == 0 {
 		return "invalid format"
 	}
 	page.Format = format
-	if format == "1_fullscreen" {
+	if format == "1_fullscreen" || page.Layout != nil {
 		page.SplitPosition = nil
 	}
 	return ""
@@ -984,6 +1029,9 @@ func applySplitPosition(page *database.BookPage, raw json.RawMessage) string {
 	if sp < 0.2 || sp > 0.8 {
 		return "split_position must be between 0.2 and 0.8"
 	}
+	if page.Layout != nil {
+		return "split_position does not apply to custom layouts"
+	}
 	page.SplitPosition = &sp
 	return ""
 }
@@ -1010,12 +1058,18 @@ func (h *BooksHandler) UpdatePage(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusNotFound, "page not found")
 		return
 	}
-	oldSlotCount := database.PageFormatSlotCount(page.Format)
+	oldSlotCount := page.SlotCount()
 
 	page, ok := maybeCrossSectionMove(w, r, bw, id, page, &req)
 	if !ok {
 		return
 	}
+	if req.Format != nil {
+		if page.Layout, err = resolvePageFormat(r.Context(), bw, *req.Format); err != nil {
+			respondPageFormatError(w, err)
+			return
+		}
+	}
 
 	if errMsg := applyPageUpdates(page, req); errMsg != "" {
 		respondError(w, http.StatusBadRequest, errMsg)
@@ -1026,7 +1080,7 @@ func (h *BooksHandler) UpdatePage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	clearExcessSlotsIfShrunk(r, bw, id, req, oldSlotCount)
+	clearExcessSlotsIfShrunk(r, bw, id, req, oldSlotCount, page.SlotCount())
 	respondJSON(w, http.StatusOK, map[string]string{"id": id})
 }
 
@@ -1058,12 +1112,11 @@ func maybeCrossSectionMove(
 // slot count when the update shrinks the format.
 func clearExcessSlotsIfShrunk(
 	r *http.Request, bw database.BookWriter,
-	pageID string, req updatePageRequest, oldSlotCount int,
+	pageID string, req updatePageRequest, oldSlotCount, newSlotCount int,
 ) {
 	if req.Format == nil {
 		return
 	}
-	newSlotCount := database.PageFormatSlotCount(*req.Format)
 	for i := newSlotCount; i < oldSlotCount; i++ {
 		if err := bw.ClearSlot(r.Context(), pageID, i); err != nil {
 			log.Printf("warning: failed to clear excess slot %d on page %s: %v", i, sanitizeForLog(pageID), err)
@@ -1476,6 +1529,8 @@ func pickMixedFormat(prefer2l1p bool, allowed map[string]bool) string {
 }
 
 // parseAutoLayoutFormats validates and returns the allowed formats set.
+// Auto-layout only places photos into built-in formats, so custom page
+// layout IDs are rejected.
 func parseAutoLayoutFormats(preferFormats []string) (map[string]bool, string) {
 	if len(preferFormats) == 0 {
 		return map[string]bool{
@@ -1485,8 +1540,8 @@ func parseAutoLayoutFormats(preferFormats []string) (map[string]bool, string) {
 	}
 	allowed := make(map[string]bool, len(preferFormats))
 	for _, f := range preferFormats {
-		if database.PageFormatSlotCount(f) == 0 {
-			return nil, "invalid format: " + f
+		if !database.IsBuiltinPageFormat(f) {
+			return nil, "invalid format: " + f + " (auto-layout uses built-in formats only)"
 		}
 		allowed[f] = true
 	}
@@ -1646,6 +1701,11 @@ type preflightIssue struct {
 	// LongestPx is populated for original_downgrade warnings — it is the
 	// longest-side dimension (px) of the photo's original file.
 	LongestPx int `json:"longest_px,omitempty"`
+	// CommentID, Target and Comment are populated for unresolved_comment
+	// warnings: the comment, its target type and its body.
+	CommentID string `json:"comment_id,omitempty"`
+	Target    string `json:"target,omitempty"`
+	Comment   string `json:"comment,omitempty"`
 }
 
 type preflightSummary struct {
@@ -1711,9 +1771,10 @@ func (h *BooksHandler) Preflight(w http.ResponseWriter, r *http.Request) {
 	}
 
 	result := &preflightResult{uniquePhotos: make(map[string]bool)}
-	checkPageSlots(data, result)
+	checkPageSlots(data, latex.BookLayoutConfig(book), result)
 	checkSections(r, bw, data, result)
 	checkMissingCaptions(r, bw, data, result)
+	checkUnresolvedComments(r, bw, id, data, result)
 	if quality == latex.QualityOriginal {
 		checkOriginalQualityDowngrade(data, result)
 	}
@@ -1790,9 +1851,9 @@ func collectSlotPhotoUIDs(pages []database.BookPage) []string {
 	return uids
 }
 
-// checkPageSlots checks all pages for empty slots and low DPI photos.
-func checkPageSlots(data *preflightData, result *preflightResult) {
-	layoutConfig := latex.DefaultLayoutConfig()
+// checkPageSlots checks all pages for empty slots and low DPI photos at the
+// slot sizes of the book's page setup.
+func checkPageSlots(data *preflightData, layoutConfig latex.LayoutConfig, result *preflightResult) {
 	for pageIdx, page := range data.pages {
 		checkSinglePage(page, pageIdx+1, data, layoutConfig, result)
 	}
@@ -1804,8 +1865,12 @@ func checkSinglePage(
 	layoutConfig latex.LayoutConfig, result *preflightResult,
 ) {
 	sectionTitle := data.sectionByID[page.SectionID]
-	slotRects := latex.FormatSlotsGridWithSplit(page.Format, layoutConfig, page.SplitPosition)
-	expectedSlots := database.PageFormatSlotCount(page.Format)
+	slotRects := latex.PageSlotsGrid(&page, layoutConfig)
+	if page.Format == latex.FormatFullbleed {
+		// The photo covers the whole page including the bleed, not the canvas.
+		slotRects = []latex.SlotRect{layoutConfig.FullBleedSlot()}
+	}
+	expectedSlots := page.SlotCount()
 	result.totalSlots += expectedSlots
 
 	filledByIndex := indexFilledSlots(page.Slots, result)
@@ -1962,6 +2027,33 @@ func checkMissingCaptions(
 	}
 }
 
+// checkUnresolvedComments adds a warning for every unresolved review comment,
+// located by the page number or section of its target.
+func checkUnresolvedComments(
+	r *http.Request, bw database.BookWriter, bookID string, data *preflightData, result *preflightResult,
+) {
+	comments, err := bw.ListBookComments(r.Context(), bookID, true)
+	if err != nil {
+		log.Printf("preflight: failed to list comments: %v", err)
+		return
+	}
+	pageIndex := make(map[string]int, len(data.pages))
+	for i, p := range data.pages {
+		pageIndex[p.ID] = i
+	}
+	for _, c := range comments {
+		issue := preflightIssue{
+			Type: "unresolved_comment", CommentID: c.ID, Target: c.TargetType, Comment: c.Body,
+			SlotIndex: c.SlotIndex, PhotoUID: c.PhotoUID, Section: data.sectionByID[c.SectionID],
+		}
+		if i, ok := pageIndex[c.PageID]; ok {
+			issue.PageNumber = i + 1
+			issue.Section = data.sectionByID[data.pages[i].SectionID]
+		}
+		result.warnings = append(result.warnings, issue)
+	}
+}
+
 // fetchPhotoDimensions batch-fetches photo dimensions from PhotoPrism.
 func fetchPhotoDimensions(pp *photoprism.PhotoPrism, uids []string) map[string][2]int {
 	dims := make(map[string][2]int, len(uids))
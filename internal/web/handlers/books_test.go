@@ -217,6 +217,63 @@ func TestBooksHandler_UpdateBook_Success(t *testing.T) {
 	}
 }
 
+func TestBooksHandler_UpdateBook_PageSetup(t *testing.T) {
+	mockBW, handler := setupBookTest(t)
+	mockBW.AddBook(database.PhotoBook{ID: "b1", Title: "Book", PageSize: "a4", Orientation: "landscape", BleedMM: 3})
+
+	body := bytes.NewBufferString(`{"page_size":"square_30","orientation":"portrait","bleed_mm":5}`)
+	req := httptest.NewRequestWithContext(context.Background(), "PUT", "/api/v1/books/b1", body)
+	req.Header.Set("Content-Type", "application/json")
+	req = requestWithChiParams(req, map[string]string{"id": "b1"})
+	recorder := httptest.NewRecorder()
+	handler.UpdateBook(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusOK)
+	book, _ := mockBW.GetBook(context.Background(), "b1")
+	if book.PageSize != "square_30" || book.Orientation != "portrait" || book.BleedMM != 5 {
+		t.Errorf("page setup = %s/%s/%.1f, want square_30/portrait/5.0", book.PageSize, book.Orientation, book.BleedMM)
+	}
+
+	req = httptest.NewRequestWithContext(context.Background(), "GET", "/api/v1/books/b1", nil)
+	req = requestWithChiParams(req, map[string]string{"id": "b1"})
+	recorder = httptest.NewRecorder()
+	handler.GetBook(recorder, req)
+
+	var resp bookDetailResponse
+	parseJSONResponse(t, recorder, &resp)
+	if resp.PageWidthMM != 300 || resp.PageHeightMM != 300 || resp.BleedMM != 5 {
+		t.Errorf("page = %.0fx%.0f bleed %.0f, want 300x300 bleed 5", resp.PageWidthMM, resp.PageHeightMM, resp.BleedMM)
+	}
+}
+
+func TestBooksHandler_UpdateBook_InvalidPageSetup(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"page size", `{"page_size":"b5"}`, "invalid page_size"},
+		{"orientation", `{"orientation":"diagonal"}`, "invalid orientation"},
+		{"bleed", `{"bleed_mm":12}`, "bleed_mm must be between 0.0 and 10.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBW, handler := setupBookTest(t)
+			mockBW.AddBook(database.PhotoBook{ID: "b1", Title: "Book"})
+
+			req := httptest.NewRequestWithContext(context.Background(), "PUT", "/api/v1/books/b1",
+				bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req = requestWithChiParams(req, map[string]string{"id": "b1"})
+			recorder := httptest.NewRecorder()
+			handler.UpdateBook(recorder, req)
+
+			assertStatusCode(t, recorder, http.StatusBadRequest)
+			assertJSONError(t, recorder, tt.want)
+		})
+	}
+}
+
 func TestBooksHandler_UpdateBook_NotFound(t *testing.T) {
 	_, handler := setupBookTest(t)
 
@@ -1937,3 +1994,15 @@ func TestComputeAutoLayout_EmptyInput(t *testing.T) {
 		t.Fatalf("expected 0 pages, got %d", len(specs))
 	}
 }
+
+func TestParseAutoLayoutFormats_BuiltinOnly(t *testing.T) {
+	allowed, errMsg := parseAutoLayoutFormats([]string{"2_portrait", "1_fullscreen"})
+	if errMsg != "" || len(allowed) != 2 || !allowed["2_portrait"] {
+		t.Errorf("got %v, %q; want both built-in formats allowed", allowed, errMsg)
+	}
+
+	// Custom page layouts are not used by auto-layout.
+	if _, errMsg := parseAutoLayoutFormats([]string{"2_portrait", "3_up"}); errMsg == "" {
+		t.Error("expected custom layout ID to be rejected")
+	}
+}
@@ -65,6 +65,10 @@ func (h *ConfigHandler) Get(w http.ResponseWriter, r *http.Request) {
 			Name:      "llamacpp",
 			Available: true, // Always available (local)
 		},
+		{
+			Name:      "openai-compatible",
+			Available: h.config.OpenAICompatible.URL != "" && h.config.OpenAICompatible.Model != "",
+		},
 	}
 
 	// Check if PostgreSQL database is configured (always writable).
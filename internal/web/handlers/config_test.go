@@ -69,8 +69,8 @@ func TestConfigHandler_Get_ReturnsProviders(t *testing.T) {
 		t.Fatalf("failed to unmarshal response: %v", err)
 	}
 
-	if len(result.Providers) != 4 {
-		t.Errorf("expected 4 providers, got %d", len(result.Providers))
+	if len(result.Providers) != 5 {
+		t.Errorf("expected 5 providers, got %d", len(result.Providers))
 	}
 }
 
@@ -308,7 +308,8 @@ func TestConfigHandler_Get_AllProvidersConfigured(t *testing.T) {
 		OpenAI: config.OpenAIConfig{
 			Token: "openai-token",
 		},
-		Gemini: config.NewGeminiConfig("gemini-key"),
+		Gemini:           config.NewGeminiConfig("gemini-key"),
+		OpenAICompatible: config.NewOpenAICompatibleConfig("http://vllm:8000/v1", "qwen2-vl", ""),
 	}
 	handler := NewConfigHandler(cfg)
 
@@ -347,7 +348,7 @@ func TestConfigHandler_Get_ProviderOrder(t *testing.T) {
 	}
 
 	// Verify expected order.
-	expectedOrder := []string{"openai", "gemini", "ollama", "llamacpp"}
+	expectedOrder := []string{"openai", "gemini", "ollama", "llamacpp", "openai-compatible"}
 	for i, expected := range expectedOrder {
 		if i >= len(result.Providers) {
 			t.Errorf("missing provider at index %d", i)
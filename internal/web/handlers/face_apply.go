@@ -4,11 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"image"
 	"log"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/kozaktomas/photo-sorter/internal/constants"
 	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/facematch"
+	"github.com/kozaktomas/photo-sorter/internal/facequality"
 	"github.com/kozaktomas/photo-sorter/internal/fingerprint"
 	"github.com/kozaktomas/photo-sorter/internal/photoprism"
 	"github.com/kozaktomas/photo-sorter/internal/web/middleware"
@@ -130,6 +134,67 @@ func (h *FacesHandler) Apply(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// maxBatchApplyFaces is the maximum number of faces of a single batch apply request.
+const maxBatchApplyFaces = 2000
+
+// BatchApplyRequest represents a request to assign a person to many faces at once,
+// e.g. all faces of a cluster.
+type BatchApplyRequest struct {
+	PersonName  string              `json:"person_name"`
+	Faces       []facematch.FaceRef `json:"faces"`
+	Concurrency int                 `json:"concurrency"` // 0 = default
+}
+
+// BatchApplyResponse represents the per-face results of a batch apply.
+type BatchApplyResponse struct {
+	Person  string                  `json:"person"`
+	Results []facematch.ApplyResult `json:"results"`
+	Summary facematch.ApplySummary  `json:"summary"`
+}
+
+// BatchApply assigns a person to every given face, creating markers where
+// needed. Faces are applied in parallel; failures are reported per face.
+func (h *FacesHandler) BatchApply(w http.ResponseWriter, r *http.Request) {
+	var req BatchApplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, errInvalidRequestBody)
+		return
+	}
+	if req.PersonName == "" || len(req.Faces) == 0 {
+		respondError(w, http.StatusBadRequest, "person_name and faces are required")
+		return
+	}
+	if len(req.Faces) > maxBatchApplyFaces {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("at most %d faces per request", maxBatchApplyFaces))
+		return
+	}
+	if req.Concurrency < 0 || req.Concurrency > constants.WorkerPoolSize {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("concurrency must be between 0 and %d", constants.WorkerPoolSize))
+		return
+	}
+
+	h.writerMu.Lock()
+	faceWriter := h.faceWriter
+	h.writerMu.Unlock()
+	if faceWriter == nil {
+		respondError(w, http.StatusServiceUnavailable, "face data not available")
+		return
+	}
+
+	pp := middleware.MustGetPhotoPrism(r.Context(), w)
+	if pp == nil {
+		return
+	}
+
+	ctx := r.Context()
+	results := facematch.ApplyPerson(ctx, pp.WithContext(ctx), faceWriter, req.Faces, req.PersonName, req.Concurrency)
+	respondJSON(w, http.StatusOK, BatchApplyResponse{
+		Person:  req.PersonName,
+		Results: results,
+		Summary: facematch.Summarize(results),
+	})
+}
+
 // syncFaceCache updates the face cache with new marker/subject data.
 func (h *FacesHandler) syncFaceCache(photoUID string, faceIndex int, markerUID, subjectUID, subjectName string) {
 	h.writerMu.Lock()
@@ -191,19 +256,32 @@ func computeFaceEmbeddings(
 	if err != nil {
 		return nil, fmt.Errorf("computing face embeddings: %w", err)
 	}
-	faces := make([]database.StoredFace, len(faceResult.Faces))
-	for i, f := range faceResult.Faces {
+	return buildStoredFaces(faceResult, imageData, photoUID), nil
+}
+
+// buildStoredFaces converts detected faces to StoredFace structs with their
+// quality score, measuring the sharpness of each face on the decoded image.
+func buildStoredFaces(result *fingerprint.FaceResponse, imageData []byte, photoUID string) []database.StoredFace {
+	var img image.Image
+	if len(result.Faces) > 0 {
+		img = facequality.DecodeImage(imageData)
+	}
+	faces := make([]database.StoredFace, len(result.Faces))
+	for i, f := range result.Faces {
 		faces[i] = database.StoredFace{
 			PhotoUID:  photoUID,
 			FaceIndex: f.FaceIndex,
 			Embedding: f.Embedding,
 			BBox:      f.BBox,
 			DetScore:  f.DetScore,
-			Model:     faceResult.Model,
+			Model:     result.Model,
 			Dim:       f.Dim,
+			Quality: facequality.Score(img, facequality.Face{
+				BBox: f.BBox, DetScore: f.DetScore, Landmarks: f.Landmarks,
+			}),
 		}
 	}
-	return faces, nil
+	return faces
 }
 
 func (h *FacesHandler) saveFacesAndEnrich(
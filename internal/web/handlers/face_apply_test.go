@@ -4,10 +4,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/database/mock"
+	"github.com/kozaktomas/photo-sorter/internal/facematch"
+	"github.com/kozaktomas/photo-sorter/internal/fingerprint"
 	"github.com/kozaktomas/photo-sorter/internal/web/middleware"
 )
 
@@ -444,3 +452,134 @@ func TestFacesHandler_ComputeFaces_DatabaseNotConfigured(t *testing.T) {
 		t.Errorf("expected error 'database not configured', got '%s'", response.Error)
 	}
 }
+
+func TestFacesHandler_BatchApply(t *testing.T) {
+	server := setupMockPhotoPrismServer(t, map[string]http.HandlerFunc{
+		"/api/v1/markers": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]any{"UID": "marker-new", "SubjUID": "subj123"})
+		},
+		"/api/v1/markers/marker1": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{"UID": "marker1", "SubjUID": "subj123"})
+		},
+	})
+	defer server.Close()
+
+	pp := createPhotoPrismClient(t, server)
+	writer := mock.NewMockFaceWriter()
+	writer.AddFaces("photo1", []database.StoredFace{
+		{PhotoUID: "photo1", FaceIndex: 0, BBox: []float64{100, 100, 200, 200},
+			PhotoWidth: 1000, PhotoHeight: 1000, Orientation: 1, FileUID: "file1"},
+		{PhotoUID: "photo1", FaceIndex: 1, MarkerUID: "marker1"},
+	})
+	handler := &FacesHandler{config: testConfig(), faceReader: writer, faceWriter: writer}
+
+	body := bytes.NewBufferString(`{
+		"person_name": "John Doe",
+		"faces": [
+			{"photo_uid": "photo1", "face_index": 0},
+			{"photo_uid": "photo1", "face_index": 1},
+			{"photo_uid": "photo2", "face_index": 0}
+		]
+	}`)
+	req := requestWithPhotoPrism(t, "POST", "/api/v1/faces/apply/batch", pp)
+	req.Body = io.NopCloser(body)
+	recorder := httptest.NewRecorder()
+
+	handler.BatchApply(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusOK)
+	var response BatchApplyResponse
+	parseJSONResponse(t, recorder, &response)
+
+	if len(response.Results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(response.Results))
+	}
+	if !response.Results[0].Success || response.Results[0].MarkerUID != "marker-new" {
+		t.Errorf("expected created marker, got %+v", response.Results[0])
+	}
+	if !response.Results[1].Success || response.Results[1].Action != ActionAssignPerson {
+		t.Errorf("expected assigned person, got %+v", response.Results[1])
+	}
+	if response.Results[2].Success || response.Results[2].Error == "" {
+		t.Errorf("expected failure for unknown face, got %+v", response.Results[2])
+	}
+	want := facematch.ApplySummary{CreatedMarkers: 1, AssignedPeople: 1, Failed: 1}
+	if response.Summary != want {
+		t.Errorf("expected summary %+v, got %+v", want, response.Summary)
+	}
+	if len(writer.UpdateMarkerCalls) != 2 {
+		t.Errorf("expected 2 cache updates, got %d", len(writer.UpdateMarkerCalls))
+	}
+}
+
+func TestFacesHandler_BatchApply_Validation(t *testing.T) {
+	writer := mock.NewMockFaceWriter()
+	handler := &FacesHandler{config: testConfig(), faceReader: writer, faceWriter: writer}
+
+	for _, body := range []string{
+		`not json`,
+		`{"person_name": "John Doe", "faces": []}`,
+		`{"faces": [{"photo_uid": "photo1", "face_index": 0}]}`,
+		`{"person_name": "John Doe", "faces": [{"photo_uid": "photo1"}], "concurrency": 100}`,
+	} {
+		req := httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/faces/apply/batch",
+			bytes.NewBufferString(body))
+		recorder := httptest.NewRecorder()
+
+		handler.BatchApply(recorder, req)
+
+		assertStatusCode(t, recorder, http.StatusBadRequest)
+	}
+}
+
+func TestFacesHandler_BatchApply_NoFaceWriter(t *testing.T) {
+	handler := &FacesHandler{config: testConfig()}
+
+	body := bytes.NewBufferString(`{"person_name": "John Doe", "faces": [{"photo_uid": "photo1"}]}`)
+	req := httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/faces/apply/batch", body)
+	recorder := httptest.NewRecorder()
+
+	handler.BatchApply(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusServiceUnavailable)
+}
+
+func TestBuildStoredFaces_Quality(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 400, 400))
+	for y := range 400 {
+		for x := range 400 {
+			if (x/4+y/4)%2 == 0 {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	result := &fingerprint.FaceResponse{
+		Model: "buffalo_l",
+		Faces: []fingerprint.FaceDetection{
+			{FaceIndex: 0, Dim: 2, Embedding: []float32{1, 0}, BBox: []float64{100, 100, 300, 300}, DetScore: 0.9},
+			{FaceIndex: 1, Dim: 2, Embedding: []float32{0, 1}, BBox: []float64{10, 10, 45, 45}, DetScore: 0.9},
+		},
+	}
+
+	faces := buildStoredFaces(result, buf.Bytes(), "photo1")
+
+	if len(faces) != 2 || faces[0].PhotoUID != "photo1" || faces[0].Model != "buffalo_l" {
+		t.Fatalf("unexpected faces: %+v", faces)
+	}
+	if faces[0].Quality <= faces[1].Quality {
+		t.Errorf("expected the large face to score higher, got %f and %f", faces[0].Quality, faces[1].Quality)
+	}
+
+	// Undecodable images still get a score from size and detection score.
+	faces = buildStoredFaces(result, []byte("heic"), "photo1")
+	if faces[0].Quality <= 0 {
+		t.Errorf("expected a quality without the image, got %f", faces[0].Quality)
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/facecluster"
+)
+
+// defaultClusterRepresentatives is the number of representative faces
+// returned per cluster when the request does not specify it.
+const defaultClusterRepresentatives = 6
+
+// ClusterRequest represents a request to cluster unassigned faces.
+type ClusterRequest struct {
+	Threshold       float64 `json:"threshold"`       // max cosine distance between neighbours (0 = default)
+	MinSize         int     `json:"min_size"`        // min faces per cluster (0 = default)
+	Limit           int     `json:"limit"`           // max clusters returned, largest first (0 = all)
+	Representatives int     `json:"representatives"` // faces per cluster shown as crops (0 = default)
+}
+
+// ClusterFace represents a single face in a cluster.
+type ClusterFace struct {
+	PhotoUID  string      `json:"photo_uid"`
+	FaceIndex int         `json:"face_index"`
+	Distance  float64     `json:"distance"` // cosine distance to the cluster centroid
+	BBox      []float64   `json:"bbox"`
+	BBoxRel   []float64   `json:"bbox_rel,omitempty"`
+	FileUID   string      `json:"file_uid,omitempty"`
+	MarkerUID string      `json:"marker_uid,omitempty"`
+	Action    MatchAction `json:"action"` // create_marker or assign_person
+}
+
+// FaceCluster represents a group of unassigned faces that likely show the same person.
+type FaceCluster struct {
+	ID              int           `json:"id"`
+	Size            int           `json:"size"`
+	PhotoCount      int           `json:"photo_count"`
+	Representatives []ClusterFace `json:"representatives"` // faces closest to the centroid
+	Faces           []ClusterFace `json:"faces"`           // all faces, closest to the centroid first
+}
+
+// ClusterResponse represents the response for face clustering.
+type ClusterResponse struct {
+	TotalFaces     int           `json:"total_faces"`
+	ClusteredFaces int           `json:"clustered_faces"`
+	NoiseFaces     int           `json:"noise_faces"`
+	Threshold      float64       `json:"threshold"`
+	MinSize        int           `json:"min_size"`
+	Clusters       []FaceCluster `json:"clusters"`
+}
+
+// toClusterFace converts a stored face to a cluster face.
+func toClusterFace(face *database.StoredFace, distance float64) ClusterFace {
+	var bboxRel []float64
+	if face.PhotoWidth > 0 && face.PhotoHeight > 0 && len(face.BBox) == 4 {
+		bboxRel = convertPixelBBoxToDisplayRelative(face.BBox, face.PhotoWidth, face.PhotoHeight, face.Orientation)
+	}
+	action := ActionAssignPerson
+	if face.MarkerUID == "" {
+		action = ActionCreateMarker
+	}
+	return ClusterFace{
+		PhotoUID: face.PhotoUID, FaceIndex: face.FaceIndex, Distance: distance,
+		BBox: face.BBox, BBoxRel: bboxRel, FileUID: face.FileUID,
+		MarkerUID: face.MarkerUID, Action: action,
+	}
+}
+
+// buildFaceClusters converts clustering results to response clusters, numbered from 1.
+func buildFaceClusters(clusters []facecluster.Cluster, representatives int) []FaceCluster {
+	result := make([]FaceCluster, 0, len(clusters))
+	for i := range clusters {
+		c := &clusters[i]
+		faces := make([]ClusterFace, len(c.Faces))
+		photos := make(map[string]struct{})
+		for j := range c.Faces {
+			faces[j] = toClusterFace(&c.Faces[j], c.Distances[j])
+			photos[c.Faces[j].PhotoUID] = struct{}{}
+		}
+		result = append(result, FaceCluster{
+			ID: i + 1, Size: len(faces), PhotoCount: len(photos),
+			Representatives: faces[:min(representatives, len(faces))],
+			Faces:           faces,
+		})
+	}
+	return result
+}
+
+// ClusterFaces groups faces without an assigned person into clusters of
+// likely the same person, so a whole cluster can be named at once.
+func (h *FacesHandler) ClusterFaces(w http.ResponseWriter, r *http.Request) {
+	if h.faceReader == nil {
+		respondError(w, http.StatusServiceUnavailable, "face data not available")
+		return
+	}
+
+	var req ClusterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, errInvalidRequestBody)
+		return
+	}
+	if req.Threshold < 0 || req.MinSize < 0 || req.Limit < 0 || req.Representatives < 0 {
+		respondError(w, http.StatusBadRequest, "threshold, min_size, limit and representatives must not be negative")
+		return
+	}
+	opts := facecluster.Options{Threshold: req.Threshold, MinSize: req.MinSize}.WithDefaults()
+	if req.Representatives == 0 {
+		req.Representatives = defaultClusterRepresentatives
+	}
+
+	ctx := r.Context()
+	unassigned, err := h.faceReader.GetUnassignedFaces(ctx)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to get unassigned faces")
+		return
+	}
+	faces := facecluster.FilterFaces(unassigned)
+
+	result, err := facecluster.Run(ctx, faces, opts)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to cluster faces")
+		return
+	}
+
+	clusters := result.Clusters
+	if req.Limit > 0 && len(clusters) > req.Limit {
+		clusters = clusters[:req.Limit]
+	}
+	respondJSON(w, http.StatusOK, ClusterResponse{
+		TotalFaces:     len(faces),
+		ClusteredFaces: len(faces) - result.Noise,
+		NoiseFaces:     result.Noise,
+		Threshold:      opts.Threshold,
+		MinSize:        opts.MinSize,
+		Clusters:       buildFaceClusters(clusters, req.Representatives),
+	})
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/database/mock"
+)
+
+// clusterEmbedding returns a 512-dim embedding pointing mostly along axis,
+// slightly varied by seed.
+func clusterEmbedding(axis, seed int) []float32 {
+	emb := make([]float32, 512)
+	emb[axis] = 1
+	emb[(axis+seed+1)%512] = 0.1
+	return emb
+}
+
+func newClusterTestHandler() *FacesHandler {
+	reader := mock.NewMockFaceReader()
+	for i := range 4 {
+		uid := fmt.Sprintf("photoA%d", i)
+		face := database.StoredFace{
+			ID: int64(i + 1), PhotoUID: uid, Embedding: clusterEmbedding(0, i),
+			BBox: []float64{100, 100, 300, 300}, PhotoWidth: 2000, PhotoHeight: 1000, Orientation: 1,
+			FileUID: "file" + uid,
+		}
+		if i == 0 {
+			face.MarkerUID = "marker" + uid // marker without a person
+		}
+		reader.AddFaces(uid, []database.StoredFace{face})
+	}
+	for i := range 3 {
+		uid := fmt.Sprintf("photoB%d", i)
+		reader.AddFaces(uid, []database.StoredFace{{
+			ID: int64(i + 10), PhotoUID: uid, Embedding: clusterEmbedding(100, i),
+			BBox: []float64{100, 100, 300, 300}, PhotoWidth: 2000, PhotoHeight: 1000, Orientation: 1,
+		}})
+	}
+	// Assigned faces are never clustered.
+	reader.AddFaces("assigned", []database.StoredFace{{
+		ID: 20, PhotoUID: "assigned", Embedding: clusterEmbedding(0, 9),
+		BBox: []float64{100, 100, 300, 300}, SubjectName: "John Doe", SubjectUID: "subj1",
+	}})
+	// Too small to be clustered.
+	reader.AddFaces("tiny", []database.StoredFace{{
+		ID: 21, PhotoUID: "tiny", Embedding: clusterEmbedding(0, 10), BBox: []float64{0, 0, 10, 10},
+	}})
+	return &FacesHandler{config: testConfig(), faceReader: reader}
+}
+
+func TestFacesHandler_ClusterFaces_Success(t *testing.T) {
+	handler := newClusterTestHandler()
+
+	body := bytes.NewBufferString(`{"representatives": 2}`)
+	req := httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/faces/clusters", body)
+	recorder := httptest.NewRecorder()
+
+	handler.ClusterFaces(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusOK)
+	var response ClusterResponse
+	parseJSONResponse(t, recorder, &response)
+
+	if response.TotalFaces != 7 || response.ClusteredFaces != 7 || response.NoiseFaces != 0 {
+		t.Errorf("expected 7 clustered faces, got %+v", response)
+	}
+	if len(response.Clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %d", len(response.Clusters))
+	}
+
+	first := response.Clusters[0]
+	if first.ID != 1 || first.Size != 4 || first.PhotoCount != 4 || len(first.Faces) != 4 {
+		t.Errorf("unexpected first cluster: %+v", first)
+	}
+	if len(first.Representatives) != 2 {
+		t.Errorf("expected 2 representatives, got %d", len(first.Representatives))
+	}
+	for _, f := range first.Faces {
+		if len(f.BBoxRel) != 4 {
+			t.Errorf("expected bbox_rel for %s", f.PhotoUID)
+		}
+		want := ActionCreateMarker
+		if f.PhotoUID == "photoA0" {
+			want = ActionAssignPerson
+		}
+		if f.Action != want {
+			t.Errorf("%s: expected action %s, got %s", f.PhotoUID, want, f.Action)
+		}
+	}
+	if response.Clusters[1].Size != 3 {
+		t.Errorf("expected second cluster of 3 faces, got %d", response.Clusters[1].Size)
+	}
+}
+
+func TestFacesHandler_ClusterFaces_MinSizeAndLimit(t *testing.T) {
+	handler := newClusterTestHandler()
+
+	body := bytes.NewBufferString(`{"min_size": 4, "limit": 1}`)
+	req := httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/faces/clusters", body)
+	recorder := httptest.NewRecorder()
+
+	handler.ClusterFaces(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusOK)
+	var response ClusterResponse
+	parseJSONResponse(t, recorder, &response)
+
+	if len(response.Clusters) != 1 || response.Clusters[0].Size != 4 {
+		t.Fatalf("expected the cluster of 4 faces only, got %+v", response.Clusters)
+	}
+	if response.NoiseFaces != 3 || response.MinSize != 4 {
+		t.Errorf("expected 3 noise faces with min_size 4, got %d (min_size %d)", response.NoiseFaces, response.MinSize)
+	}
+	if len(response.Clusters[0].Representatives) != 4 {
+		t.Errorf("expected all 4 faces as representatives, got %d", len(response.Clusters[0].Representatives))
+	}
+}
+
+func TestFacesHandler_ClusterFaces_EmptyBody(t *testing.T) {
+	handler := newClusterTestHandler()
+
+	req := httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/faces/clusters", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	handler.ClusterFaces(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusOK)
+	var response ClusterResponse
+	parseJSONResponse(t, recorder, &response)
+	if response.Threshold == 0 || response.MinSize == 0 {
+		t.Errorf("expected default parameters in response, got %+v", response)
+	}
+}
+
+func TestFacesHandler_ClusterFaces_InvalidRequest(t *testing.T) {
+	handler := newClusterTestHandler()
+
+	body := bytes.NewBufferString(`{"threshold": -1}`)
+	req := httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/faces/clusters", body)
+	recorder := httptest.NewRecorder()
+
+	handler.ClusterFaces(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusBadRequest)
+}
+
+func TestFacesHandler_ClusterFaces_NoFaceReader(t *testing.T) {
+	handler := &FacesHandler{config: testConfig()}
+
+	req := httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/faces/clusters", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	handler.ClusterFaces(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusServiceUnavailable)
+}
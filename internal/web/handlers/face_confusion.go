@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/kozaktomas/photo-sorter/internal/faceconfusion"
+)
+
+// ConfusionRequest represents a request for the person-to-person confusion report.
+type ConfusionRequest struct {
+	MinFaces int     `json:"min_faces"` // min faces per person (0 = default)
+	Margin   float64 `json:"margin"`    // how much closer the other centroid must be (0 = any closer)
+	Limit    int     `json:"limit"`     // max pairs returned, most confused first (0 = all)
+	Examples int     `json:"examples"`  // example faces per pair (0 = default)
+}
+
+// ConfusionExample represents a face closer to another person's centroid than to its own.
+type ConfusionExample struct {
+	PhotoUID      string    `json:"photo_uid"`
+	FaceIndex     int       `json:"face_index"`
+	AssignedTo    string    `json:"assigned_to"`
+	ConfusedWith  string    `json:"confused_with"`
+	OwnDistance   float64   `json:"own_distance"`
+	OtherDistance float64   `json:"other_distance"`
+	BBoxRel       []float64 `json:"bbox_rel,omitempty"`
+	FileUID       string    `json:"file_uid,omitempty"`
+	MarkerUID     string    `json:"marker_uid,omitempty"`
+}
+
+// ConfusionPair represents a pair of people the face model confuses.
+type ConfusionPair struct {
+	PersonA          string             `json:"person_a"`
+	PersonB          string             `json:"person_b"`
+	FacesA           int                `json:"faces_a"`
+	FacesB           int                `json:"faces_b"`
+	AConfusedAsB     int                `json:"a_confused_as_b"`
+	BConfusedAsA     int                `json:"b_confused_as_a"`
+	CentroidDistance float64            `json:"centroid_distance"`
+	Examples         []ConfusionExample `json:"examples"`
+}
+
+// ConfusionResponse represents the person-to-person confusion report.
+type ConfusionResponse struct {
+	People        int             `json:"people"`
+	Faces         int             `json:"faces"`
+	ConfusedFaces int             `json:"confused_faces"`
+	Pairs         []ConfusionPair `json:"pairs"`
+}
+
+// buildConfusionPairs converts report pairs to response pairs.
+func buildConfusionPairs(pairs []faceconfusion.Pair) []ConfusionPair {
+	result := make([]ConfusionPair, 0, len(pairs))
+	for i := range pairs {
+		p := &pairs[i]
+		examples := make([]ConfusionExample, len(p.Examples))
+		for j := range p.Examples {
+			ex := &p.Examples[j]
+			var bboxRel []float64
+			if ex.Face.PhotoWidth > 0 && ex.Face.PhotoHeight > 0 && len(ex.Face.BBox) == 4 {
+				bboxRel = convertPixelBBoxToDisplayRelative(
+					ex.Face.BBox, ex.Face.PhotoWidth, ex.Face.PhotoHeight, ex.Face.Orientation,
+				)
+			}
+			examples[j] = ConfusionExample{
+				PhotoUID: ex.Face.PhotoUID, FaceIndex: ex.Face.FaceIndex,
+				AssignedTo: ex.AssignedTo, ConfusedWith: ex.ConfusedWith,
+				OwnDistance: ex.OwnDistance, OtherDistance: ex.OtherDistance,
+				BBoxRel: bboxRel, FileUID: ex.Face.FileUID, MarkerUID: ex.Face.MarkerUID,
+			}
+		}
+		result = append(result, ConfusionPair{
+			PersonA: p.PersonA, PersonB: p.PersonB, FacesA: p.FacesA, FacesB: p.FacesB,
+			AConfusedAsB: p.AConfusedAsB, BConfusedAsA: p.BConfusedAsA,
+			CentroidDistance: p.CentroidDistance, Examples: examples,
+		})
+	}
+	return result
+}
+
+// FindConfusion reports pairs of people whose faces are closer to the other
+// person's centroid than to their own, pointing at mis-assigned faces.
+func (h *FacesHandler) FindConfusion(w http.ResponseWriter, r *http.Request) {
+	if h.faceReader == nil {
+		respondError(w, http.StatusServiceUnavailable, "face data not available")
+		return
+	}
+
+	var req ConfusionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, errInvalidRequestBody)
+		return
+	}
+	if req.MinFaces < 0 || req.Margin < 0 || req.Limit < 0 || req.Examples < 0 {
+		respondError(w, http.StatusBadRequest, "min_faces, margin, limit and examples must not be negative")
+		return
+	}
+
+	ctx := r.Context()
+	faces, err := h.faceReader.GetAssignedFaces(ctx)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to get assigned faces")
+		return
+	}
+
+	report, err := faceconfusion.Analyze(ctx, faces, faceconfusion.Options{
+		MinFaces: req.MinFaces, Margin: req.Margin, Examples: req.Examples,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to compute confusion report")
+		return
+	}
+
+	pairs := report.Pairs
+	if req.Limit > 0 && len(pairs) > req.Limit {
+		pairs = pairs[:req.Limit]
+	}
+	respondJSON(w, http.StatusOK, ConfusionResponse{
+		People:        report.People,
+		Faces:         report.Faces,
+		ConfusedFaces: report.ConfusedFaces,
+		Pairs:         buildConfusionPairs(pairs),
+	})
+}
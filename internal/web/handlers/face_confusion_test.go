@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/database/mock"
+)
+
+func newConfusionTestHandler() *FacesHandler {
+	reader := mock.NewMockFaceReader()
+	add := func(uid, name string, axis, seed int) {
+		reader.AddFaces(uid, []database.StoredFace{{
+			PhotoUID: uid, SubjectName: name, Embedding: clusterEmbedding(axis, seed),
+			BBox: []float64{100, 100, 300, 300}, PhotoWidth: 2000, PhotoHeight: 1000, Orientation: 1,
+			FileUID: "file" + uid, MarkerUID: "marker" + uid,
+		}})
+	}
+	for i := range 4 {
+		add(fmt.Sprintf("alice%d", i), "Alice", 0, i)
+		add(fmt.Sprintf("bob%d", i), "Bob", 100, i)
+		add(fmt.Sprintf("carol%d", i), "Carol", 200, i)
+	}
+	add("wrong", "Alice", 100, 9) // a face of Bob assigned to Alice
+	add("unassigned", "", 100, 10)
+	return &FacesHandler{config: testConfig(), faceReader: reader}
+}
+
+func TestFacesHandler_FindConfusion_Success(t *testing.T) {
+	handler := newConfusionTestHandler()
+
+	req := httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/faces/confusion", nil)
+	recorder := httptest.NewRecorder()
+
+	handler.FindConfusion(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusOK)
+	var response ConfusionResponse
+	parseJSONResponse(t, recorder, &response)
+
+	if response.People != 3 || response.Faces != 13 || response.ConfusedFaces != 1 {
+		t.Errorf("unexpected totals: %+v", response)
+	}
+	if len(response.Pairs) != 1 {
+		t.Fatalf("expected 1 pair, got %d", len(response.Pairs))
+	}
+	pair := response.Pairs[0]
+	if pair.PersonA != "Alice" || pair.PersonB != "Bob" || pair.AConfusedAsB != 1 || pair.FacesA != 5 {
+		t.Errorf("unexpected pair: %+v", pair)
+	}
+	if len(pair.Examples) != 1 || pair.Examples[0].PhotoUID != "wrong" || pair.Examples[0].MarkerUID != "markerwrong" {
+		t.Fatalf("unexpected examples: %+v", pair.Examples)
+	}
+	if len(pair.Examples[0].BBoxRel) != 4 {
+		t.Errorf("expected bbox_rel, got %v", pair.Examples[0].BBoxRel)
+	}
+}
+
+func TestFacesHandler_FindConfusion_InvalidRequest(t *testing.T) {
+	handler := newConfusionTestHandler()
+
+	body := bytes.NewBufferString(`{"limit": -1}`)
+	req := httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/faces/confusion", body)
+	recorder := httptest.NewRecorder()
+
+	handler.FindConfusion(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusBadRequest)
+}
+
+func TestFacesHandler_FindConfusion_NoFaceReader(t *testing.T) {
+	handler := &FacesHandler{config: testConfig()}
+
+	req := httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/faces/confusion", nil)
+	recorder := httptest.NewRecorder()
+
+	handler.FindConfusion(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusServiceUnavailable)
+}
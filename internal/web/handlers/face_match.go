@@ -10,6 +10,7 @@ import (
 	"github.com/kozaktomas/photo-sorter/internal/constants"
 	"github.com/kozaktomas/photo-sorter/internal/database"
 	"github.com/kozaktomas/photo-sorter/internal/facematch"
+	"github.com/kozaktomas/photo-sorter/internal/facequality"
 	"github.com/kozaktomas/photo-sorter/internal/photoprism"
 	"github.com/kozaktomas/photo-sorter/internal/web/middleware"
 )
@@ -19,6 +20,7 @@ type MatchRequest struct {
 	PersonName string  `json:"person_name"`
 	Threshold  float64 `json:"threshold"`
 	Limit      int     `json:"limit"`
+	MinQuality float64 `json:"min_quality"` // skip source and candidate faces of lower quality (0 = all)
 }
 
 // FaceMatchResult represents a single matched photo.
@@ -33,6 +35,7 @@ type FaceMatchResult struct {
 	MarkerUID  string      `json:"marker_uid,omitempty"`
 	MarkerName string      `json:"marker_name,omitempty"`
 	IoU        float64     `json:"iou,omitempty"`
+	Quality    float64     `json:"quality,omitempty"`
 }
 
 // MatchSummary provides counts by action type.
@@ -65,6 +68,7 @@ type matchCandidate struct {
 	MarkerUID   string
 	SubjectName string
 	SubjectUID  string
+	Quality     float64
 }
 
 // matchSourceData holds a source face's embedding and photo info.
@@ -75,8 +79,11 @@ type matchSourceData struct {
 
 // buildMatchSourceData extracts unique per-photo source faces from the person's face list.
 // First pass: add ALL person face photo UIDs to sourcePhotoSet (regardless of embedding).
-// Second pass: extract one embedding per unique photo (skip faces without embeddings).
-func buildMatchSourceData(allPersonFaces []database.StoredFace) ([]matchSourceData, map[string]bool) {
+// Second pass: extract one embedding per unique photo (skip faces without embeddings
+// and faces below minQuality).
+func buildMatchSourceData(
+	allPersonFaces []database.StoredFace, minQuality float64,
+) ([]matchSourceData, map[string]bool) {
 	sourcePhotoSet := make(map[string]bool)
 	for i := range allPersonFaces {
 		sourcePhotoSet[allPersonFaces[i].PhotoUID] = true
@@ -89,6 +96,9 @@ func buildMatchSourceData(allPersonFaces []database.StoredFace) ([]matchSourceDa
 		if len(face.Embedding) == 0 || embeddingPhotoSet[face.PhotoUID] {
 			continue
 		}
+		if minQuality > 0 && facequality.ForFace(face) < minQuality {
+			continue
+		}
 		embeddingPhotoSet[face.PhotoUID] = true
 		sourceFaces = append(sourceFaces, matchSourceData{
 			PhotoUID:  face.PhotoUID,
@@ -117,7 +127,7 @@ func computeMinMatchCount(sourceCount int, threshold float64) int {
 func searchSimilarFaces(
 	ctx context.Context, faceRepo database.FaceReader,
 	sourceEmbeddings [][]float32, sourcePhotoSet map[string]bool,
-	searchLimit int, threshold float64, personName string,
+	searchLimit int, threshold float64, personName string, minQuality float64,
 ) map[string]*matchCandidate {
 	normalizedPersonName := facematch.NormalizePersonName(personName)
 	type searchResult struct {
@@ -154,6 +164,9 @@ func searchSimilarFaces(
 			if sourcePhotoSet[face.PhotoUID] {
 				continue
 			}
+			if minQuality > 0 && facequality.ForFace(face) < minQuality {
+				continue
+			}
 			// Skip faces assigned to a different person.
 			if face.SubjectName != "" && face.SubjectUID != "" {
 				if facematch.NormalizePersonName(face.SubjectName) != normalizedPersonName {
@@ -181,6 +194,7 @@ func mergeMatchCandidate(matchMap map[string]*matchCandidate, face *database.Sto
 			existing.MarkerUID = face.MarkerUID
 			existing.SubjectName = face.SubjectName
 			existing.SubjectUID = face.SubjectUID
+			existing.Quality = facequality.ForFace(face)
 		}
 	} else {
 		matchMap[face.PhotoUID] = &matchCandidate{
@@ -196,6 +210,7 @@ func mergeMatchCandidate(matchMap map[string]*matchCandidate, face *database.Sto
 			MarkerUID:   face.MarkerUID,
 			SubjectName: face.SubjectName,
 			SubjectUID:  face.SubjectUID,
+			Quality:     facequality.ForFace(face),
 		}
 	}
 }
@@ -279,7 +294,7 @@ func candidateToMatchResult(c *matchCandidate, pp interface {
 	return &FaceMatchResult{
 		PhotoUID: c.PhotoUID, Distance: c.Distance, FaceIndex: c.FaceIndex,
 		BBox: c.BBox, BBoxRel: bboxRel, FileUID: fileUID,
-		Action: action, MarkerUID: markerUID, MarkerName: markerName,
+		Action: action, MarkerUID: markerUID, MarkerName: markerName, Quality: c.Quality,
 	}
 }
 
@@ -367,6 +382,9 @@ func parseMatchRequest(r *http.Request) (MatchRequest, string) {
 	if req.Threshold <= 0 {
 		req.Threshold = 0.5
 	}
+	if req.MinQuality < 0 || req.MinQuality > 1 {
+		return req, "min_quality must be between 0 and 1"
+	}
 	return req, ""
 }
 
@@ -439,7 +457,7 @@ func (h *FacesHandler) Match(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sourceFaces, sourcePhotoSet := buildMatchSourceData(allPersonFaces)
+	sourceFaces, sourcePhotoSet := buildMatchSourceData(allPersonFaces, req.MinQuality)
 	augmentSourcePhotoSet(pp, allPersonFaces[0].SubjectUID, sourcePhotoSet)
 	sourceEmbeddings := extractSourceEmbeddings(sourceFaces)
 
@@ -455,7 +473,7 @@ func (h *FacesHandler) Match(w http.ResponseWriter, r *http.Request) {
 
 	matchMap := searchSimilarFaces(
 		ctx, h.faceReader, sourceEmbeddings,
-		sourcePhotoSet, searchLimit, req.Threshold, req.PersonName,
+		sourcePhotoSet, searchLimit, req.Threshold, req.PersonName, req.MinQuality,
 	)
 	markAlreadyAssignedPhotos(ctx, h.faceReader, matchMap, req.PersonName)
 	candidates := filterAndSortCandidates(matchMap, computeMinMatchCount(len(sourceEmbeddings), req.Threshold), req.Limit)
@@ -285,6 +285,23 @@ func TestMatchRequest_Validation(t *testing.T) {
 			body:         `{"person_name": "john-doe", "limit": 0}`,
 			expectStatus: http.StatusOK,
 		},
+		{
+			name:         "valid min_quality",
+			body:         `{"person_name": "john-doe", "min_quality": 0.4}`,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "negative min_quality",
+			body:         `{"person_name": "john-doe", "min_quality": -0.1}`,
+			expectStatus: http.StatusBadRequest,
+			expectError:  "min_quality must be between 0 and 1",
+		},
+		{
+			name:         "min_quality above 1",
+			body:         `{"person_name": "john-doe", "min_quality": 1.5}`,
+			expectStatus: http.StatusBadRequest,
+			expectError:  "min_quality must be between 0 and 1",
+		},
 	}
 
 	for _, tc := range tests {
@@ -333,7 +350,7 @@ func TestBuildMatchSourceData_FacesWithoutEmbedding(t *testing.T) {
 		},
 	}
 
-	sourceFaces, sourcePhotoSet := buildMatchSourceData(allFaces)
+	sourceFaces, sourcePhotoSet := buildMatchSourceData(allFaces, 0)
 
 	// Both photos must be in sourcePhotoSet.
 	if !sourcePhotoSet["photo1"] {
@@ -352,6 +369,50 @@ func TestBuildMatchSourceData_FacesWithoutEmbedding(t *testing.T) {
 	}
 }
 
+func TestBuildMatchSourceData_MinQuality(t *testing.T) {
+	allFaces := []database.StoredFace{
+		{PhotoUID: "blurry", Embedding: make([]float32, 512), Quality: 0.2, SubjectName: "John"},
+		{PhotoUID: "sharp", Embedding: make([]float32, 512), Quality: 0.8, SubjectName: "John"},
+	}
+
+	sourceFaces, sourcePhotoSet := buildMatchSourceData(allFaces, 0.5)
+
+	// Low quality photos are still source photos, so they are not matched again.
+	if !sourcePhotoSet["blurry"] || !sourcePhotoSet["sharp"] {
+		t.Errorf("expected both photos in sourcePhotoSet, got %v", sourcePhotoSet)
+	}
+	if len(sourceFaces) != 1 || sourceFaces[0].PhotoUID != "sharp" {
+		t.Errorf("expected only the sharp source face, got %+v", sourceFaces)
+	}
+}
+
+func TestSearchSimilarFaces_MinQuality(t *testing.T) {
+	mockReader := mock.NewMockFaceReader()
+	embedding := make([]float32, 512)
+	embedding[0] = 1
+	mockReader.AddFaces("blurry", []database.StoredFace{
+		{PhotoUID: "blurry", Embedding: embedding, BBox: []float64{10, 10, 200, 200}, Quality: 0.2},
+	})
+	mockReader.AddFaces("sharp", []database.StoredFace{
+		{PhotoUID: "sharp", Embedding: embedding, BBox: []float64{10, 10, 200, 200}, Quality: 0.9},
+	})
+
+	matchMap := searchSimilarFaces(
+		context.Background(), mockReader, [][]float32{embedding}, map[string]bool{}, 10, 0.5, "John", 0.5,
+	)
+
+	if _, ok := matchMap["blurry"]; ok {
+		t.Error("expected the low quality face to be skipped")
+	}
+	c, ok := matchMap["sharp"]
+	if !ok {
+		t.Fatal("expected the sharp face to match")
+	}
+	if c.Quality != 0.9 {
+		t.Errorf("expected candidate quality 0.9, got %f", c.Quality)
+	}
+}
+
 func TestMarkAlreadyAssignedPhotos(t *testing.T) {
 	mockReader := mock.NewMockFaceReader()
 	// Add a face on "candidate-photo" that is assigned to "John".
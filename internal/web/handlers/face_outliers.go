@@ -6,6 +6,7 @@ import (
 	"sort"
 
 	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/facequality"
 	"github.com/kozaktomas/photo-sorter/internal/web/middleware"
 )
 
@@ -21,6 +22,7 @@ type OutlierResult struct {
 	PhotoUID         string    `json:"photo_uid"`
 	DistFromCentroid float64   `json:"dist_from_centroid"`
 	FaceIndex        int       `json:"face_index"`
+	Quality          float64   `json:"quality,omitempty"`
 	BBoxRel          []float64 `json:"bbox_rel,omitempty"`
 	FileUID          string    `json:"file_uid,omitempty"`
 	MarkerUID        string    `json:"marker_uid,omitempty"`
@@ -43,6 +45,7 @@ type outlierFaceData struct {
 	BBoxRel   []float64
 	FileUID   string
 	MarkerUID string
+	Quality   float64
 }
 
 // classifyOutlierFaces splits person faces into faces with embeddings and those missing embeddings.
@@ -68,24 +71,28 @@ func classifyOutlierFaces(
 			PhotoUID: face.PhotoUID, Embedding: face.Embedding,
 			FaceIndex: face.FaceIndex, BBoxRel: bboxRel,
 			FileUID: face.FileUID, MarkerUID: face.MarkerUID,
+			Quality: facequality.ForFace(face),
 		})
 	}
 	return faces, missingEmbeddings
 }
 
-// computeFaceCentroid computes the element-wise mean of face embeddings.
+// computeFaceCentroid computes the mean of face embeddings weighted by face
+// quality, so blurry or profile faces pull the centroid less.
 func computeFaceCentroid(faces []outlierFaceData) []float32 {
 	embDim := len(faces[0].Embedding)
 	centroid := make([]float32, embDim)
+	totalWeight := 0.0
 	for _, f := range faces {
 		for i := range centroid {
 			if i < len(f.Embedding) {
-				centroid[i] += f.Embedding[i]
+				centroid[i] += float32(f.Quality) * f.Embedding[i]
 			}
 		}
+		totalWeight += f.Quality
 	}
 	for i := range centroid {
-		centroid[i] /= float32(len(faces))
+		centroid[i] /= float32(totalWeight)
 	}
 	return centroid
 }
@@ -131,6 +138,7 @@ func rankFacesByDistance(
 			PhotoUID: f.data.PhotoUID, DistFromCentroid: f.dist,
 			FaceIndex: f.data.FaceIndex, BBoxRel: f.data.BBoxRel,
 			FileUID: f.data.FileUID, MarkerUID: f.data.MarkerUID,
+			Quality: f.data.Quality,
 		})
 	}
 	return outliers, avgDistance
@@ -455,6 +455,19 @@ func TestFacesHandler_FindOutliers_MissingEmbeddings(t *testing.T) {
 	}
 }
 
+func TestComputeFaceCentroid_WeightedByQuality(t *testing.T) {
+	faces := []outlierFaceData{
+		{PhotoUID: "sharp", Embedding: []float32{1, 0}, Quality: 0.75},
+		{PhotoUID: "blurry", Embedding: []float32{0, 1}, Quality: 0.25},
+	}
+
+	centroid := computeFaceCentroid(faces)
+
+	if centroid[0] != 0.75 || centroid[1] != 0.25 {
+		t.Errorf("expected centroid [0.75 0.25], got %v", centroid)
+	}
+}
+
 func TestOutlierRequest_JSON(t *testing.T) {
 	jsonData := `{"person_name": "jane-doe", "threshold": 0.2, "limit": 50}`
 
@@ -3,8 +3,10 @@
 // Handler methods are organized in separate files:.
 //   - subjects.go: Subject CRUD operations (ListSubjects, GetSubject, UpdateSubject)
 //   - face_match.go: Face matching and similarity search (Match)
-//   - face_apply.go: Applying face matches (Apply, ComputeFaces)
+//   - face_apply.go: Applying face matches (Apply, BatchApply, ComputeFaces)
 //   - face_outliers.go: Outlier detection (FindOutliers)
+//   - face_clusters.go: Clustering of unassigned faces (ClusterFaces)
+//   - face_confusion.go: Person-to-person confusion report (FindConfusion)
 //   - face_photos.go: Photo face retrieval and suggestions (GetPhotoFaces)
 //   - face_helpers.go: Shared helper functions
 package handlers
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/kozaktomas/photo-sorter/internal/changes"
+	"github.com/kozaktomas/photo-sorter/internal/config"
+	"github.com/kozaktomas/photo-sorter/internal/constants"
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/database/mariadb"
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+)
+
+// photoPrismDB lazily opens the PhotoPrism MariaDB pool used for change
+// detection. It stays nil if PHOTOPRISM_DATABASE_URL is not set or the
+// database cannot be reached, in which case the API is used instead.
+type photoPrismDB struct {
+	once sync.Once
+	pool *mariadb.Pool
+}
+
+func (d *photoPrismDB) get(cfg *config.Config) *mariadb.Pool {
+	d.once.Do(func() {
+		if cfg.PhotoPrism.DatabaseURL == "" {
+			return
+		}
+		pool, err := mariadb.NewPool(cfg.PhotoPrism.DatabaseURL)
+		if err != nil {
+			log.Printf("Warning: PhotoPrism database unavailable, detecting changes via the API: %v", err)
+			return
+		}
+		d.pool = pool
+	})
+	return d.pool
+}
+
+// changeSource returns the change detection source: PhotoPrism's database
+// if configured, its API otherwise.
+func (h *ProcessHandler) changeSource(pp *photoprism.PhotoPrism) changes.Source {
+	if pool := h.photoPrismDB.get(h.config); pool != nil {
+		return changes.NewMariaDBSource(pool)
+	}
+	return changes.NewAPISource(pp, constants.DefaultPageSize)
+}
+
+// detectChanges loads the named cursor and returns the photos changed since.
+func (h *ProcessHandler) detectChanges(
+	ctx context.Context, pp *photoprism.PhotoPrism, cursorName string,
+) (*changes.Set, error) {
+	store, err := database.GetSyncCursorStore(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync cursor store: %w", err)
+	}
+	since, err := changes.Load(ctx, store, cursorName)
+	if err != nil {
+		return nil, fmt.Errorf("incremental change detection: %w", err)
+	}
+	source := h.changeSource(pp)
+	set, err := source.Changes(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("detecting changes via %s: %w", source.Name(), err)
+	}
+	log.Printf("Detected %d changed and %d archived photos via %s since %s",
+		len(set.Changed), len(set.Archived), source.Name(), since.PhotosAt.Format("2006-01-02 15:04:05"))
+	return set, nil
+}
+
+// saveSyncCursor stores the cursor after a successful incremental run.
+// Failures are logged; the next run then simply looks at more photos.
+func saveSyncCursor(ctx context.Context, name string, cursor changes.Cursor) {
+	store, err := database.GetSyncCursorStore(ctx)
+	if err == nil {
+		err = changes.Save(ctx, store, name, cursor)
+	}
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// fetchChangedPhotos returns the photos changed since the process cursor
+// that still need embedding or face processing, and the number of changed
+// photos.
+func (h *ProcessHandler) fetchChangedPhotos(
+	ctx context.Context, pp *photoprism.PhotoPrism, repos *processJobRepos, job *ProcessJob,
+) ([]photoprism.Photo, int, error) {
+	set, err := h.detectChanges(ctx, pp, changes.CursorProcess)
+	if err != nil {
+		return nil, 0, err
+	}
+	photos := make([]photoprism.Photo, len(set.Changed))
+	for i, uid := range set.Changed {
+		photos[i] = photoprism.Photo{UID: uid}
+	}
+	photosToProcess := filterUnprocessedPhotos(ctx, photos, repos)
+	if limit := job.Options.Limit; limit > 0 && len(photosToProcess) > limit {
+		// Photos beyond the limit are left for the next run, which must see
+		// them again, so the cursor is not advanced.
+		photosToProcess = photosToProcess[:limit]
+	} else {
+		job.mu.Lock()
+		job.nextCursor = &set.Next
+		job.mu.Unlock()
+	}
+	return photosToProcess, len(set.Changed), nil
+}
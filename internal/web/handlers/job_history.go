@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kozaktomas/photo-sorter/internal/ai"
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+const (
+	defaultJobHistoryLimit = 100
+	maxJobHistoryLimit     = 1000
+)
+
+var (
+	validJobTypes = []string{
+		database.JobTypeSort, database.JobTypeProcess, database.JobTypeUpload, database.JobTypeBookExport,
+	}
+	validJobStatuses = []string{
+		string(JobStatusPending), string(JobStatusRunning), string(JobStatusCompleted),
+		string(JobStatusFailed), string(JobStatusCancelled),
+	}
+)
+
+// JobsHandler serves the history of persisted background jobs.
+type JobsHandler struct{}
+
+// NewJobsHandler creates a new job history handler.
+func NewJobsHandler() *JobsHandler {
+	return &JobsHandler{}
+}
+
+// JobSummary is a single entry of the job history list.
+type JobSummary struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Status      string          `json:"status"`
+	Progress    int             `json:"progress"`
+	Total       int             `json:"total"`
+	Processed   int             `json:"processed"`
+	Error       string          `json:"error,omitempty"`
+	Options     json.RawMessage `json:"options,omitempty"`
+	TotalCost   *float64        `json:"total_cost,omitempty"`
+	StartedAt   time.Time       `json:"started_at"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// JobDetail is a job history entry with its full result. Suggestions, usage
+// and errors are lifted out of sort job results for convenience.
+type JobDetail struct {
+	JobSummary
+	Result      json.RawMessage     `json:"result,omitempty"`
+	Suggestions []ai.SortSuggestion `json:"suggestions,omitempty"`
+	Usage       *UsageInfo          `json:"usage,omitempty"`
+	Errors      []string            `json:"errors,omitempty"`
+}
+
+func getJobStore(w http.ResponseWriter, r *http.Request) database.JobStore {
+	store, err := database.GetJobStore(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "job storage not available")
+		return nil
+	}
+	return store
+}
+
+// List handles GET /api/v1/jobs and returns past and running jobs, newest first.
+func (h *JobsHandler) List(w http.ResponseWriter, r *http.Request) {
+	store := getJobStore(w, r)
+	if store == nil {
+		return
+	}
+
+	filter, errMsg := parseJobFilter(r)
+	if errMsg != "" {
+		respondError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	jobs, err := store.ListJobs(r.Context(), filter)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to list jobs")
+		return
+	}
+
+	result := make([]JobSummary, 0, len(jobs))
+	for i := range jobs {
+		result = append(result, newJobSummary(&jobs[i], decodeSortResult(&jobs[i])))
+	}
+	respondJSON(w, http.StatusOK, result)
+}
+
+// Get handles GET /api/v1/jobs/{id} and returns a single job with its result.
+func (h *JobsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	store := getJobStore(w, r)
+	if store == nil {
+		return
+	}
+
+	job, err := store.GetJob(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to get job")
+		return
+	}
+	if job == nil {
+		respondError(w, http.StatusNotFound, "job not found")
+		return
+	}
+
+	sortResult := decodeSortResult(job)
+	detail := JobDetail{
+		JobSummary: newJobSummary(job, sortResult),
+		Result:     job.Result,
+	}
+	if sortResult != nil {
+		detail.Suggestions = sortResult.Suggestions
+		detail.Usage = sortResult.Usage
+		detail.Errors = sortResult.Errors
+	}
+	respondJSON(w, http.StatusOK, detail)
+}
+
+func newJobSummary(job *database.StoredJob, sortResult *SortJobResult) JobSummary {
+	summary := JobSummary{
+		ID:          job.ID,
+		Type:        job.Type,
+		Status:      job.Status,
+		Progress:    job.Progress,
+		Total:       job.Total,
+		Processed:   job.Processed,
+		Error:       job.Error,
+		Options:     job.Options,
+		StartedAt:   job.StartedAt,
+		CompletedAt: job.CompletedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+	if sortResult != nil && sortResult.Usage != nil {
+		cost := sortResult.Usage.TotalCost
+		summary.TotalCost = &cost
+	}
+	return summary
+}
+
+// decodeSortResult returns the stored result of a sort job, or nil for other
+// job types and jobs without a (valid) result.
+func decodeSortResult(job *database.StoredJob) *SortJobResult {
+	if job.Type != database.JobTypeSort || len(job.Result) == 0 {
+		return nil
+	}
+	var result SortJobResult
+	if err := json.Unmarshal(job.Result, &result); err != nil {
+		return nil
+	}
+	return &result
+}
+
+// parseJobFilter builds a job filter from the query string, returning an error message if invalid.
+func parseJobFilter(r *http.Request) (database.JobFilter, string) {
+	q := r.URL.Query()
+	filter := database.JobFilter{Limit: defaultJobHistoryLimit}
+
+	var errMsg string
+	if filter.Types, errMsg = parseListParam(q.Get("type"), validJobTypes, "type"); errMsg != "" {
+		return filter, errMsg
+	}
+	if filter.Statuses, errMsg = parseListParam(q.Get("status"), validJobStatuses, "status"); errMsg != "" {
+		return filter, errMsg
+	}
+	if filter.StartedAfter, errMsg = parseJobTime(q.Get("from"), "from", false); errMsg != "" {
+		return filter, errMsg
+	}
+	if filter.StartedBefore, errMsg = parseJobTime(q.Get("to"), "to", true); errMsg != "" {
+		return filter, errMsg
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return filter, "invalid limit"
+		}
+		filter.Limit = min(limit, maxJobHistoryLimit)
+	}
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return filter, "invalid offset"
+		}
+		filter.Offset = offset
+	}
+	return filter, ""
+}
+
+// parseListParam splits a comma-separated query value and checks every item against allowed.
+func parseListParam(value string, allowed []string, name string) ([]string, string) {
+	if value == "" {
+		return nil, ""
+	}
+	var items []string
+	for item := range strings.SplitSeq(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !slices.Contains(allowed, item) {
+			return nil, "invalid " + name + ": " + item
+		}
+		items = append(items, item)
+	}
+	return items, ""
+}
+
+// parseJobTime accepts RFC 3339 timestamps or YYYY-MM-DD dates (UTC). A date
+// used as an upper bound covers the whole day.
+func parseJobTime(value, name string, endOfDay bool) (time.Time, string) {
+	if value == "" {
+		return time.Time{}, ""
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, ""
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, "invalid " + name + ": expected RFC 3339 timestamp or YYYY-MM-DD date"
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, ""
+}
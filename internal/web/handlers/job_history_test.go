@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kozaktomas/photo-sorter/internal/ai"
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/database/mock"
+)
+
+func seedJobHistory(t *testing.T) *mock.MockJobStore {
+	t.Helper()
+	store := setupMockJobStore(t)
+	ctx := context.Background()
+
+	sortResult, _ := json.Marshal(SortJobResult{
+		ProcessedCount: 2,
+		SortedCount:    1,
+		Errors:         []string{"photo p2: timeout"},
+		Suggestions:    []ai.SortSuggestion{{PhotoUID: "p1", Description: "beach"}},
+		Usage:          &UsageInfo{InputTokens: 100, OutputTokens: 20, TotalCost: 0.25},
+	})
+	for _, j := range []database.StoredJob{
+		{
+			ID: "sort-1", Type: database.JobTypeSort, Status: "completed",
+			StartedAt: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), Result: sortResult,
+		},
+		{
+			ID: "process-1", Type: database.JobTypeProcess, Status: "failed", Error: "boom",
+			StartedAt: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			ID: "sort-2", Type: database.JobTypeSort, Status: "running",
+			StartedAt: time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC),
+		},
+	} {
+		if err := store.SaveJob(ctx, &j); err != nil {
+			t.Fatalf("SaveJob: %v", err)
+		}
+	}
+	return store
+}
+
+func listJobs(t *testing.T, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs"+query, nil)
+	recorder := httptest.NewRecorder()
+	NewJobsHandler().List(recorder, req)
+	return recorder
+}
+
+func TestJobsHandler_List(t *testing.T) {
+	seedJobHistory(t)
+
+	recorder := listJobs(t, "")
+	assertStatusCode(t, recorder, http.StatusOK)
+
+	var jobs []JobSummary
+	parseJSONResponse(t, recorder, &jobs)
+	if len(jobs) != 3 {
+		t.Fatalf("expected 3 jobs, got %d", len(jobs))
+	}
+	if jobs[0].ID != "sort-2" || jobs[2].ID != "sort-1" {
+		t.Errorf("expected newest first, got %s..%s", jobs[0].ID, jobs[2].ID)
+	}
+	if jobs[2].TotalCost == nil || *jobs[2].TotalCost != 0.25 {
+		t.Errorf("expected total_cost 0.25 on sort-1, got %v", jobs[2].TotalCost)
+	}
+	if jobs[1].TotalCost != nil {
+		t.Errorf("expected no total_cost on process job, got %v", *jobs[1].TotalCost)
+	}
+}
+
+func TestJobsHandler_ListFilters(t *testing.T) {
+	seedJobHistory(t)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"by type", "?type=sort", []string{"sort-2", "sort-1"}},
+		{"by status", "?status=failed,completed", []string{"process-1", "sort-1"}},
+		{"from date", "?from=2026-03-02", []string{"sort-2", "process-1"}},
+		{"to date covers whole day", "?to=2026-03-02", []string{"process-1", "sort-1"}},
+		{"rfc3339 range", "?from=2026-03-02T00:00:00Z&to=2026-03-03T00:00:00Z", []string{"process-1"}},
+		{"limit and offset", "?limit=1&offset=1", []string{"process-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := listJobs(t, tt.query)
+			assertStatusCode(t, recorder, http.StatusOK)
+
+			var jobs []JobSummary
+			parseJSONResponse(t, recorder, &jobs)
+			if len(jobs) != len(tt.want) {
+				t.Fatalf("expected %v, got %d jobs", tt.want, len(jobs))
+			}
+			for i, id := range tt.want {
+				if jobs[i].ID != id {
+					t.Errorf("job %d: expected %s, got %s", i, id, jobs[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestJobsHandler_ListInvalidParams(t *testing.T) {
+	seedJobHistory(t)
+
+	for _, query := range []string{"?type=bogus", "?status=done", "?from=yesterday", "?limit=0", "?offset=-1"} {
+		recorder := listJobs(t, query)
+		assertStatusCode(t, recorder, http.StatusBadRequest)
+	}
+}
+
+func TestJobsHandler_ListStoreError(t *testing.T) {
+	store := seedJobHistory(t)
+	store.ListJobsError = errors.New("db down")
+
+	recorder := listJobs(t, "")
+	assertStatusCode(t, recorder, http.StatusInternalServerError)
+}
+
+func TestJobsHandler_NoStore(t *testing.T) {
+	database.ResetForTesting()
+
+	recorder := listJobs(t, "")
+	assertStatusCode(t, recorder, http.StatusInternalServerError)
+}
+
+func TestJobsHandler_Get(t *testing.T) {
+	seedJobHistory(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs/sort-1", nil)
+	req = requestWithChiParams(req, map[string]string{"id": "sort-1"})
+	recorder := httptest.NewRecorder()
+	NewJobsHandler().Get(recorder, req)
+	assertStatusCode(t, recorder, http.StatusOK)
+
+	var detail JobDetail
+	parseJSONResponse(t, recorder, &detail)
+	if len(detail.Suggestions) != 1 || detail.Suggestions[0].PhotoUID != "p1" {
+		t.Errorf("unexpected suggestions: %+v", detail.Suggestions)
+	}
+	if detail.Usage == nil || detail.Usage.InputTokens != 100 {
+		t.Errorf("unexpected usage: %+v", detail.Usage)
+	}
+	if len(detail.Errors) != 1 {
+		t.Errorf("expected 1 error, got %v", detail.Errors)
+	}
+	if len(detail.Result) == 0 {
+		t.Error("expected raw result to be included")
+	}
+}
+
+func TestJobsHandler_GetNotFound(t *testing.T) {
+	seedJobHistory(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs/missing", nil)
+	req = requestWithChiParams(req, map[string]string{"id": "missing"})
+	recorder := httptest.NewRecorder()
+	NewJobsHandler().Get(recorder, req)
+	assertStatusCode(t, recorder, http.StatusNotFound)
+}
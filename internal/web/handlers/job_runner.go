@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+// jobPersistInterval throttles how often a running job's events are written
+// to the job store. The final state is always written when the job returns.
+//
+// Exposed as a var so tests can shrink it.
+var jobPersistInterval = 2 * time.Second
+
+// errJobNotResumable is returned by a JobResumer for jobs whose inputs did
+// not survive the restart (e.g. the uploaded files of an upload job).
+var errJobNotResumable = errors.New("job cannot be resumed")
+
+// persistentJob is implemented by every async job kind (sort, process,
+// upload, book export) so runPersistentJob can drive its lifecycle and
+// snapshot it into the job store.
+type persistentJob interface {
+	SSEJob
+	SendEvent(event JobEvent)
+	setCancel(cancel context.CancelFunc)
+	markRunning()
+	snapshot() database.StoredJob
+}
+
+// JobResumer restarts a job that was left pending or running by a previous
+// server process.
+type JobResumer interface {
+	ResumeJob(stored database.StoredJob) error
+}
+
+// jobPersistMu serializes snapshot+save so an older snapshot can never
+// overwrite a newer one for the same job.
+var jobPersistMu sync.Mutex
+
+// runPersistentJob is the lifecycle shared by all background jobs: it
+// installs a cancellable context, marks the job running, runs fn, and keeps
+// the job store in sync with the events the job emits. fn is responsible for
+// moving the job into a terminal state. Persistence is best-effort; without
+// a configured job store jobs simply live in memory.
+func runPersistentJob(job persistentJob, startMessage string, fn func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	job.setCancel(cancel)
+	defer cancel()
+
+	job.markRunning()
+	stopWatching := watchJobEvents(job)
+	job.SendEvent(JobEvent{Type: "started", Message: startMessage})
+
+	fn(ctx)
+
+	stopWatching()
+	persistJob(job)
+}
+
+// watchJobEvents subscribes to the job's events and persists a snapshot at
+// most once per jobPersistInterval. The returned func unsubscribes and waits
+// for the watcher goroutine to exit.
+func watchJobEvents(job persistentJob) func() {
+	ch := job.AddListener()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var lastSave time.Time
+		for range ch {
+			if time.Since(lastSave) < jobPersistInterval {
+				continue
+			}
+			persistJob(job)
+			lastSave = time.Now()
+		}
+	}()
+	return func() {
+		job.RemoveListener(ch)
+		<-done
+	}
+}
+
+// persistJob writes the job's current snapshot to the job store, if any.
+func persistJob(job persistentJob) {
+	ctx := context.Background()
+	store, err := database.GetJobStore(ctx)
+	if err != nil {
+		return
+	}
+	jobPersistMu.Lock()
+	defer jobPersistMu.Unlock()
+	snap := job.snapshot()
+	if err := store.SaveJob(ctx, &snap); err != nil {
+		log.Printf("Warning: failed to persist job %s: %v", snap.ID, err)
+	}
+}
+
+// newStoredJob builds a StoredJob from the fields common to all job kinds.
+// options and result are marshalled to JSON; a nil result stays nil.
+func newStoredJob(
+	id, jobType string, status JobStatus, errMsg string,
+	startedAt time.Time, completedAt *time.Time, options, result any,
+) database.StoredJob {
+	stored := database.StoredJob{
+		ID:          id,
+		Type:        jobType,
+		Status:      string(status),
+		Error:       errMsg,
+		StartedAt:   startedAt,
+		CompletedAt: completedAt,
+	}
+	if data, err := json.Marshal(options); err == nil {
+		stored.Options = data
+	}
+	if data, err := json.Marshal(result); err == nil && string(data) != "null" {
+		stored.Result = data
+	}
+	return stored
+}
+
+// percentOf returns current/total as a 0-100 integer percentage.
+func percentOf(current, total int) int {
+	if total <= 0 {
+		return 0
+	}
+	return min(current*100/total, 100)
+}
+
+// ResumeInterruptedJobs loads jobs left pending or running by a previous
+// server process and hands each to the resumer registered for its type.
+// Jobs that cannot be resumed are marked failed so they do not linger as
+// "running" forever. Returns the number of resumed jobs.
+func ResumeInterruptedJobs(ctx context.Context, resumers map[string]JobResumer) (int, error) {
+	store, err := database.GetJobStore(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("getting job store: %w", err)
+	}
+	jobs, err := store.ListJobs(ctx, database.JobFilter{
+		Statuses: []string{string(JobStatusPending), string(JobStatusRunning)},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("listing interrupted jobs: %w", err)
+	}
+
+	resumed := 0
+	for _, stored := range jobs {
+		resumer, ok := resumers[stored.Type]
+		if !ok {
+			markJobInterrupted(ctx, store, stored, errJobNotResumable)
+			continue
+		}
+		if err := resumer.ResumeJob(stored); err != nil {
+			markJobInterrupted(ctx, store, stored, err)
+			continue
+		}
+		resumed++
+	}
+	return resumed, nil
+}
+
+// markJobInterrupted records a job that could not be resumed as failed.
+func markJobInterrupted(ctx context.Context, store database.JobStore, stored database.StoredJob, reason error) {
+	now := time.Now()
+	stored.Status = string(JobStatusFailed)
+	stored.Error = "interrupted by server restart: " + reason.Error()
+	stored.CompletedAt = &now
+	if err := store.SaveJob(ctx, &stored); err != nil {
+		log.Printf("Warning: failed to mark job %s as interrupted: %v", stored.ID, err)
+	}
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/database/mock"
+)
+
+func setupMockJobStore(t *testing.T) *mock.MockJobStore {
+	t.Helper()
+	store := mock.NewMockJobStore()
+	database.RegisterPostgresBackend(nil, nil, nil)
+	database.RegisterJobStore(func() database.JobStore { return store })
+	t.Cleanup(database.ResetForTesting)
+	return store
+}
+
+func TestRunPersistentJob_PersistsFinalState(t *testing.T) {
+	store := setupMockJobStore(t)
+
+	jm := NewJobManager()
+	job := jm.CreateJob("job-1", "album1", "Album", SortJobOptions{Provider: "openai", Limit: 5})
+
+	runPersistentJob(job, "Sort job started", func(ctx context.Context) {
+		if job.GetStatus() != JobStatusRunning {
+			t.Errorf("expected running status inside fn, got %s", job.GetStatus())
+		}
+		now := time.Now()
+		job.mu.Lock()
+		job.Status = JobStatusCompleted
+		job.CompletedAt = &now
+		job.TotalPhotos = 10
+		job.ProcessedPhotos = 10
+		job.Progress = 100
+		job.Result = &SortJobResult{ProcessedCount: 10, SortedCount: 7}
+		job.mu.Unlock()
+	})
+
+	stored, err := store.GetJob(context.Background(), "job-1")
+	if err != nil {
+		t.Fatalf("GetJob: %v", err)
+	}
+	if stored == nil {
+		t.Fatal("expected job to be persisted")
+	}
+	if stored.Type != database.JobTypeSort {
+		t.Errorf("expected type sort, got %s", stored.Type)
+	}
+	if stored.Status != string(JobStatusCompleted) {
+		t.Errorf("expected status completed, got %s", stored.Status)
+	}
+	if stored.Total != 10 || stored.Processed != 10 || stored.Progress != 100 {
+		t.Errorf("unexpected counters: total=%d processed=%d progress=%d",
+			stored.Total, stored.Processed, stored.Progress)
+	}
+
+	var params sortJobParams
+	if err := json.Unmarshal(stored.Options, &params); err != nil {
+		t.Fatalf("unmarshal options: %v", err)
+	}
+	if params.AlbumUID != "album1" || params.Limit != 5 || params.Provider != "openai" {
+		t.Errorf("unexpected options: %+v", params)
+	}
+
+	var result SortJobResult
+	if err := json.Unmarshal(stored.Result, &result); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if result.SortedCount != 7 {
+		t.Errorf("expected sorted_count 7, got %d", result.SortedCount)
+	}
+}
+
+func TestRunPersistentJob_NoStoreConfigured(t *testing.T) {
+	database.ResetForTesting()
+
+	job := &ProcessJob{ID: "job-2", Status: JobStatusPending}
+	ran := false
+	runPersistentJob(job, "Process job started", func(ctx context.Context) {
+		ran = true
+	})
+	if !ran {
+		t.Error("expected fn to run without a job store")
+	}
+}
+
+func TestRunPersistentJob_CancelStopsContext(t *testing.T) {
+	setupMockJobStore(t)
+
+	job := &ProcessJob{ID: "job-3", Status: JobStatusPending}
+	runPersistentJob(job, "Process job started", func(ctx context.Context) {
+		job.Cancel()
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+			t.Error("expected context to be cancelled")
+		}
+	})
+	if job.GetStatus() != JobStatusCancelled {
+		t.Errorf("expected cancelled status, got %s", job.GetStatus())
+	}
+}
+
+func TestProcessJob_SnapshotProgress(t *testing.T) {
+	job := &ProcessJob{
+		ID: "job-4", Status: JobStatusRunning,
+		TotalPhotos: 200, ProcessedPhotos: 50,
+		Options: ProcessJobOptions{Concurrency: 3, NoFaces: true},
+	}
+	snap := job.snapshot()
+	if snap.Progress != 25 {
+		t.Errorf("expected progress 25, got %d", snap.Progress)
+	}
+	if snap.Result != nil {
+		t.Errorf("expected nil result for running job, got %s", snap.Result)
+	}
+	var opts ProcessJobOptions
+	if err := json.Unmarshal(snap.Options, &opts); err != nil {
+		t.Fatalf("unmarshal options: %v", err)
+	}
+	if opts.Concurrency != 3 || !opts.NoFaces {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+}
+
+type fakeResumer struct {
+	resumed []string
+	err     error
+}
+
+func (f *fakeResumer) ResumeJob(stored database.StoredJob) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.resumed = append(f.resumed, stored.ID)
+	return nil
+}
+
+func TestResumeInterruptedJobs(t *testing.T) {
+	store := setupMockJobStore(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, j := range []database.StoredJob{
+		{ID: "sort-running", Type: database.JobTypeSort, Status: "running", StartedAt: now},
+		{ID: "process-pending", Type: database.JobTypeProcess, Status: "pending", StartedAt: now},
+		{ID: "upload-running", Type: database.JobTypeUpload, Status: "running", StartedAt: now},
+		{ID: "sort-done", Type: database.JobTypeSort, Status: "completed", StartedAt: now},
+	} {
+		store.SaveJob(ctx, &j)
+	}
+
+	sortResumer := &fakeResumer{}
+	processResumer := &fakeResumer{}
+	uploadResumer := &fakeResumer{err: errJobNotResumable}
+
+	resumed, err := ResumeInterruptedJobs(ctx, map[string]JobResumer{
+		database.JobTypeSort:    sortResumer,
+		database.JobTypeProcess: processResumer,
+		database.JobTypeUpload:  uploadResumer,
+	})
+	if err != nil {
+		t.Fatalf("ResumeInterruptedJobs: %v", err)
+	}
+	if resumed != 2 {
+		t.Errorf("expected 2 resumed jobs, got %d", resumed)
+	}
+	if len(sortResumer.resumed) != 1 || sortResumer.resumed[0] != "sort-running" {
+		t.Errorf("unexpected sort resumes: %v", sortResumer.resumed)
+	}
+	if len(processResumer.resumed) != 1 || processResumer.resumed[0] != "process-pending" {
+		t.Errorf("unexpected process resumes: %v", processResumer.resumed)
+	}
+
+	upload, _ := store.GetJob(ctx, "upload-running")
+	if upload.Status != string(JobStatusFailed) {
+		t.Errorf("expected upload job to be marked failed, got %s", upload.Status)
+	}
+	if !strings.Contains(upload.Error, "interrupted by server restart") {
+		t.Errorf("unexpected error message: %q", upload.Error)
+	}
+	if upload.CompletedAt == nil {
+		t.Error("expected completed_at to be set on interrupted job")
+	}
+}
+
+func TestResumeInterruptedJobs_NoStore(t *testing.T) {
+	database.ResetForTesting()
+	if _, err := ResumeInterruptedJobs(context.Background(), nil); err == nil {
+		t.Error("expected error without job store")
+	}
+}
+
+func TestResumeInterruptedJobs_ListError(t *testing.T) {
+	store := setupMockJobStore(t)
+	store.ListJobsError = errors.New("boom")
+	if _, err := ResumeInterruptedJobs(context.Background(), nil); err == nil {
+		t.Error("expected list error to be returned")
+	}
+}
+
+func TestUploadHandler_ResumeJobNotResumable(t *testing.T) {
+	h := NewUploadHandler(testConfig(), nil, nil)
+	err := h.ResumeJob(database.StoredJob{ID: "u1", Type: database.JobTypeUpload})
+	if !errors.Is(err, errJobNotResumable) {
+		t.Errorf("expected errJobNotResumable, got %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/kozaktomas/photo-sorter/internal/ai"
 	"github.com/kozaktomas/photo-sorter/internal/constants"
+	"github.com/kozaktomas/photo-sorter/internal/database"
 )
 
 // JobStatus represents the status of an async job.
@@ -56,21 +57,50 @@ func (j *SortJob) Cancel() {
 	j.mu.Unlock()
 }
 
+// markRunning transitions the job to running (implements persistentJob).
+func (j *SortJob) markRunning() {
+	j.mu.Lock()
+	j.Status = JobStatusRunning
+	j.mu.Unlock()
+}
+
+// sortJobParams is the persisted input of a sort job, used to resume it.
+type sortJobParams struct {
+	AlbumUID   string `json:"album_uid"`
+	AlbumTitle string `json:"album_title"`
+	SortJobOptions
+}
+
+// snapshot returns the job's persisted representation (implements persistentJob).
+func (j *SortJob) snapshot() database.StoredJob {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	stored := newStoredJob(j.ID, database.JobTypeSort, j.Status, j.Error, j.StartedAt, j.CompletedAt,
+		sortJobParams{AlbumUID: j.AlbumUID, AlbumTitle: j.AlbumTitle, SortJobOptions: j.Options}, j.Result)
+	stored.Progress = j.Progress
+	stored.Total = j.TotalPhotos
+	stored.Processed = j.ProcessedPhotos
+	return stored
+}
+
 // SortJobOptions represents sort job options.
 type SortJobOptions struct {
-	DryRun          bool   `json:"dry_run"`
-	Limit           int    `json:"limit"`
-	IndividualDates bool   `json:"individual_dates"`
-	BatchMode       bool   `json:"batch_mode"`
-	Provider        string `json:"provider"`
-	ForceDate       bool   `json:"force_date"`
-	Concurrency     int    `json:"concurrency"`
+	DryRun          bool     `json:"dry_run"`
+	Limit           int      `json:"limit"`
+	IndividualDates bool     `json:"individual_dates"`
+	BatchMode       bool     `json:"batch_mode"`
+	Provider        string   `json:"provider"`
+	ForceDate       bool     `json:"force_date"`
+	Concurrency     int      `json:"concurrency"`
+	NoCache         bool     `json:"no_cache"`
+	Consensus       []string `json:"consensus,omitempty"`
 }
 
 // SortJobResult represents the result of a sort job.
 type SortJobResult struct {
 	ProcessedCount int                 `json:"processed_count"`
 	SortedCount    int                 `json:"sorted_count"`
+	CacheHits      int                 `json:"cache_hits"`
 	AlbumDate      string              `json:"album_date,omitempty"`
 	DateReasoning  string              `json:"date_reasoning,omitempty"`
 	Errors         []string            `json:"errors,omitempty"`
@@ -135,10 +165,20 @@ func (b *EventBroadcaster) SendEvent(event JobEvent) {
 	}
 }
 
+// setCancel installs the cancel func of the job's context.
+func (b *EventBroadcaster) setCancel(cancel context.CancelFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cancel = cancel
+}
+
 // Cancel cancels the job via context and sends a cancelled event.
 func (b *EventBroadcaster) Cancel() {
-	if b.cancel != nil {
-		b.cancel()
+	b.mu.RLock()
+	cancel := b.cancel
+	b.mu.RUnlock()
+	if cancel != nil {
+		cancel()
 	}
 	b.SendEvent(JobEvent{Type: "cancelled", Message: "Job cancelled by user"})
 }
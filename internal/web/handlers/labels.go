@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"slices"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
@@ -187,3 +189,76 @@ func (h *LabelsHandler) BatchDelete(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, http.StatusOK, map[string]int{"deleted": len(req.UIDs)})
 }
+
+// LabelMergeRequest represents a request to merge labels into another label.
+type LabelMergeRequest struct {
+	From   []string `json:"from"`
+	Into   string   `json:"into"`
+	DryRun bool     `json:"dry_run"`
+}
+
+// LabelMergeResponse represents the outcome of a label merge.
+type LabelMergeResponse struct {
+	Into          LabelResponse   `json:"into"`
+	From          []LabelResponse `json:"from"`
+	PhotoUIDs     []string        `json:"photo_uids"`
+	Updated       int             `json:"updated"`
+	LabelsDeleted bool            `json:"labels_deleted"`
+	DryRun        bool            `json:"dry_run"`
+	Errors        []string        `json:"errors,omitempty"`
+}
+
+// Merge moves all photos from the source labels to the target label and
+// deletes the source labels.
+func (h *LabelsHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	var req LabelMergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, errInvalidRequestBody)
+		return
+	}
+
+	if len(req.From) == 0 {
+		respondError(w, http.StatusBadRequest, "no labels specified")
+		return
+	}
+	if req.Into == "" {
+		respondError(w, http.StatusBadRequest, "into is required")
+		return
+	}
+	if slices.Contains(req.From, req.Into) {
+		respondError(w, http.StatusBadRequest, "cannot merge a label into itself")
+		return
+	}
+
+	pp := middleware.MustGetPhotoPrism(r.Context(), w)
+	if pp == nil {
+		return
+	}
+
+	result, err := pp.MergeLabels(req.From, req.Into, photoprism.MergeLabelsOptions{DryRun: req.DryRun})
+	if errors.Is(err, photoprism.ErrLabelNotFound) {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil && result == nil {
+		respondError(w, http.StatusInternalServerError, "failed to merge labels")
+		return
+	}
+
+	response := LabelMergeResponse{
+		Into:          labelToResponse(result.Into),
+		From:          make([]LabelResponse, len(result.From)),
+		PhotoUIDs:     result.PhotoUIDs,
+		Updated:       result.UpdatedCount,
+		LabelsDeleted: result.Deleted,
+		DryRun:        req.DryRun,
+		Errors:        result.Errors,
+	}
+	for i, l := range result.From {
+		response.From[i] = labelToResponse(l)
+	}
+	if err != nil {
+		response.Errors = append(response.Errors, err.Error())
+	}
+	respondJSON(w, http.StatusOK, response)
+}
@@ -425,3 +425,92 @@ func TestLabelsHandler_BatchDelete_NoClient(t *testing.T) {
 
 	assertStatusCode(t, recorder, http.StatusInternalServerError)
 }
+
+func TestLabelsHandler_Merge_DryRun(t *testing.T) {
+	server := setupMockPhotoPrismServer(t, map[string]http.HandlerFunc{
+		"/api/v1/labels": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"UID": "ldog", "Name": "Dog", "Slug": "dog"}, {"UID": "lpes", "Name": "Pes", "Slug": "pes"}]`))
+		},
+		"/api/v1/photos": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("q") != "label:pes" {
+				t.Errorf("expected q=label:pes, got %s", r.URL.Query().Get("q"))
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"UID": "photo1"}, {"UID": "photo2"}]`))
+		},
+		"/api/v1/batch/labels/delete": func(w http.ResponseWriter, r *http.Request) {
+			t.Error("expected dry run not to delete labels")
+		},
+	})
+	defer server.Close()
+
+	pp := createPhotoPrismClient(t, server)
+	handler := NewLabelsHandler(testConfig(), nil)
+
+	body := bytes.NewBufferString(`{"from": ["lpes"], "into": "ldog", "dry_run": true}`)
+	req := httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/labels/merge", body)
+	req = req.WithContext(middleware.SetPhotoPrismInContext(req.Context(), pp))
+	recorder := httptest.NewRecorder()
+
+	handler.Merge(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusOK)
+
+	var result LabelMergeResponse
+	parseJSONResponse(t, recorder, &result)
+
+	if result.Into.Name != "Dog" || len(result.From) != 1 || result.From[0].Name != "Pes" {
+		t.Errorf("unexpected labels: %+v", result)
+	}
+	if len(result.PhotoUIDs) != 2 || !result.DryRun || result.LabelsDeleted || result.Updated != 0 {
+		t.Errorf("unexpected dry-run result: %+v", result)
+	}
+}
+
+func TestLabelsHandler_Merge_UnknownLabel(t *testing.T) {
+	server := setupMockPhotoPrismServer(t, map[string]http.HandlerFunc{
+		"/api/v1/labels": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"UID": "ldog", "Name": "Dog", "Slug": "dog"}]`))
+		},
+	})
+	defer server.Close()
+
+	pp := createPhotoPrismClient(t, server)
+	handler := NewLabelsHandler(testConfig(), nil)
+
+	body := bytes.NewBufferString(`{"from": ["lmissing"], "into": "ldog"}`)
+	req := httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/labels/merge", body)
+	req = req.WithContext(middleware.SetPhotoPrismInContext(req.Context(), pp))
+	recorder := httptest.NewRecorder()
+
+	handler.Merge(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusNotFound)
+}
+
+func TestLabelsHandler_Merge_Validation(t *testing.T) {
+	handler := NewLabelsHandler(testConfig(), nil)
+
+	tests := []struct {
+		body    string
+		message string
+	}{
+		{`{"from": [], "into": "ldog"}`, "no labels specified"},
+		{`{"from": ["lpes"]}`, "into is required"},
+		{`{"from": ["ldog"], "into": "ldog"}`, "cannot merge a label into itself"},
+		{`not json`, errInvalidRequestBody},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequestWithContext(
+			context.Background(), "POST", "/api/v1/labels/merge", bytes.NewBufferString(tt.body),
+		)
+		recorder := httptest.NewRecorder()
+
+		handler.Merge(recorder, req)
+
+		assertStatusCode(t, recorder, http.StatusBadRequest)
+		assertJSONError(t, recorder, tt.message)
+	}
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+// --- Page layouts ---
+
+type pageLayoutCellResponse struct {
+	Col     int `json:"col"`
+	ColSpan int `json:"col_span"`
+	Row     int `json:"row"`
+	RowSpan int `json:"row_span"`
+}
+
+type pageLayoutResponse struct {
+	ID          string                   `json:"id"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Rows        int                      `json:"rows"`
+	Cells       []pageLayoutCellResponse `json:"cells"`
+	SlotCount   int                      `json:"slot_count"`
+}
+
+// pageLayoutRequest is the body of the create and update page layout requests.
+type pageLayoutRequest struct {
+	ID          string                   `json:"id"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Rows        int                      `json:"rows"`
+	Cells       []pageLayoutCellResponse `json:"cells"`
+}
+
+func buildPageLayoutResponse(l *database.PageLayout) *pageLayoutResponse {
+	if l == nil {
+		return nil
+	}
+	cells := make([]pageLayoutCellResponse, len(l.Cells))
+	for i, c := range l.Cells {
+		cells[i] = pageLayoutCellResponse(c)
+	}
+	return &pageLayoutResponse{
+		ID:          l.ID,
+		Name:        l.Name,
+		Description: l.Description,
+		Rows:        l.Rows,
+		Cells:       cells,
+		SlotCount:   l.SlotCount(),
+	}
+}
+
+// toPageLayout converts the request to a page layout with the given ID.
+func (req *pageLayoutRequest) toPageLayout(id string) *database.PageLayout {
+	cells := make([]database.PageLayoutCell, len(req.Cells))
+	for i, c := range req.Cells {
+		cells[i] = database.PageLayoutCell(c)
+	}
+	return &database.PageLayout{
+		ID:          id,
+		Name:        req.Name,
+		Description: req.Description,
+		Rows:        req.Rows,
+		Cells:       cells,
+	}
+}
+
+// resolvePageFormat returns the custom layout of a page format, or nil for a
+// built-in format. It returns database.ErrPageLayoutNotFound when the format
+// is neither.
+func resolvePageFormat(ctx context.Context, br database.BookReader, format string) (*database.PageLayout, error) {
+	if database.IsBuiltinPageFormat(format) {
+		return nil, nil
+	}
+	layout, err := br.GetPageLayout(ctx, format)
+	if err != nil {
+		return nil, fmt.Errorf("get page layout: %w", err)
+	}
+	if layout == nil {
+		return nil, database.ErrPageLayoutNotFound
+	}
+	return layout, nil
+}
+
+// respondPageFormatError writes the response for a failed resolvePageFormat.
+func respondPageFormatError(w http.ResponseWriter, err error) {
+	if errors.Is(err, database.ErrPageLayoutNotFound) {
+		respondError(w, http.StatusBadRequest, "invalid format")
+		return
+	}
+	respondError(w, http.StatusInternalServerError, "failed to load page layout")
+}
+
+// ListPageLayouts handles GET /api/v1/page-layouts and returns the
+// user-defined page layouts.
+func (h *BooksHandler) ListPageLayouts(w http.ResponseWriter, r *http.Request) {
+	bw := getBookWriter(r, w)
+	if bw == nil {
+		return
+	}
+	layouts, err := bw.ListPageLayouts(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to list page layouts")
+		return
+	}
+	result := make([]*pageLayoutResponse, len(layouts))
+	for i := range layouts {
+		result[i] = buildPageLayoutResponse(&layouts[i])
+	}
+	respondJSON(w, http.StatusOK, result)
+}
+
+// CreatePageLayout handles POST /api/v1/page-layouts and creates a
+// user-defined page layout.
+func (h *BooksHandler) CreatePageLayout(w http.ResponseWriter, r *http.Request) {
+	bw := getBookWriter(r, w)
+	if bw == nil {
+		return
+	}
+	var req pageLayoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, errInvalidRequestBody)
+		return
+	}
+	layout := req.toPageLayout(req.ID)
+	if err := layout.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := bw.CreatePageLayout(r.Context(), layout); err != nil {
+		if errors.Is(err, database.ErrPageLayoutExists) {
+			respondError(w, http.StatusConflict, "page layout already exists")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to create page layout")
+		return
+	}
+	respondJSON(w, http.StatusCreated, buildPageLayoutResponse(layout))
+}
+
+// UpdatePageLayout handles PUT /api/v1/page-layouts/:id and replaces the
+// name, description and grid of a page layout. The number of slots of a
+// layout used by pages cannot change.
+func (h *BooksHandler) UpdatePageLayout(w http.ResponseWriter, r *http.Request) {
+	bw := getBookWriter(r, w)
+	if bw == nil {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	var req pageLayoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, errInvalidRequestBody)
+		return
+	}
+	layout := req.toPageLayout(id)
+	if err := layout.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := bw.UpdatePageLayout(r.Context(), layout); err != nil {
+		switch {
+		case errors.Is(err, database.ErrPageLayoutNotFound):
+			respondError(w, http.StatusNotFound, "page layout not found")
+		case errors.Is(err, database.ErrPageLayoutInUse):
+			respondError(w, http.StatusConflict, "cannot change the number of slots of a layout used by pages")
+		default:
+			respondError(w, http.StatusInternalServerError, "failed to update page layout")
+		}
+		return
+	}
+	respondJSON(w, http.StatusOK, buildPageLayoutResponse(layout))
+}
+
+// DeletePageLayout handles DELETE /api/v1/page-layouts/:id and deletes a
+// page layout that no page uses.
+func (h *BooksHandler) DeletePageLayout(w http.ResponseWriter, r *http.Request) {
+	bw := getBookWriter(r, w)
+	if bw == nil {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	layout, err := bw.GetPageLayout(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to get page layout")
+		return
+	}
+	if layout == nil {
+		respondError(w, http.StatusNotFound, "page layout not found")
+		return
+	}
+	if err := bw.DeletePageLayout(r.Context(), id); err != nil {
+		if errors.Is(err, database.ErrPageLayoutInUse) {
+			respondError(w, http.StatusConflict, "page layout is used by pages")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to delete page layout")
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]bool{"deleted": true})
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+)
+
+const threeUpLayoutJSON = `{"id":"3_up","name":"3-up","rows":2,"cells":[` +
+	`{"col":0,"col_span":6,"row":0,"row_span":2},` +
+	`{"col":6,"col_span":6,"row":0,"row_span":1},` +
+	`{"col":6,"col_span":6,"row":1,"row_span":1}]}`
+
+func threeUpLayout() database.PageLayout {
+	return database.PageLayout{
+		ID: "3_up", Name: "3-up", Rows: 2,
+		Cells: []database.PageLayoutCell{
+			{Col: 0, ColSpan: 6, Row: 0, RowSpan: 2},
+			{Col: 6, ColSpan: 6, Row: 0, RowSpan: 1},
+			{Col: 6, ColSpan: 6, Row: 1, RowSpan: 1},
+		},
+	}
+}
+
+func TestBooksHandler_CreatePageLayout_Success(t *testing.T) {
+	mockBW, handler := setupBookTest(t)
+
+	req := httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/page-layouts",
+		bytes.NewBufferString(threeUpLayoutJSON))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	handler.CreatePageLayout(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusCreated)
+	var resp pageLayoutResponse
+	parseJSONResponse(t, recorder, &resp)
+	if resp.ID != "3_up" || resp.SlotCount != 3 || len(resp.Cells) != 3 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	stored, _ := mockBW.GetPageLayout(context.Background(), "3_up")
+	if stored == nil || stored.Rows != 2 || stored.Cells[0].RowSpan != 2 {
+		t.Errorf("unexpected stored layout: %+v", stored)
+	}
+}
+
+func TestBooksHandler_CreatePageLayout_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"built-in id", `{"id":"2_portrait","name":"x","rows":1,"cells":[{"col":0,"col_span":12,"row":0,"row_span":1}]}`,
+			`id "2_portrait" is a built-in format`},
+		{"overlap", `{"id":"bad","name":"x","rows":1,"cells":[` +
+			`{"col":0,"col_span":8,"row":0,"row_span":1},{"col":6,"col_span":6,"row":0,"row_span":1}]}`,
+			"cell 1 overlaps another cell"},
+		{"outside grid", `{"id":"bad","name":"x","rows":1,"cells":[{"col":6,"col_span":8,"row":0,"row_span":1}]}`,
+			"cell 0 is outside the 12x1 grid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, handler := setupBookTest(t)
+
+			req := httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/page-layouts",
+				bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+			handler.CreatePageLayout(recorder, req)
+
+			assertStatusCode(t, recorder, http.StatusBadRequest)
+			assertJSONError(t, recorder, tt.want)
+		})
+	}
+}
+
+func TestBooksHandler_CreatePageLayout_Duplicate(t *testing.T) {
+	mockBW, handler := setupBookTest(t)
+	mockBW.AddPageLayout(threeUpLayout())
+
+	req := httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/page-layouts",
+		bytes.NewBufferString(threeUpLayoutJSON))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	handler.CreatePageLayout(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusConflict)
+}
+
+func TestBooksHandler_ListPageLayouts(t *testing.T) {
+	mockBW, handler := setupBookTest(t)
+	mockBW.AddPageLayout(threeUpLayout())
+
+	req := httptest.NewRequestWithContext(context.Background(), "GET", "/api/v1/page-layouts", nil)
+	recorder := httptest.NewRecorder()
+	handler.ListPageLayouts(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusOK)
+	var resp []pageLayoutResponse
+	parseJSONResponse(t, recorder, &resp)
+	if len(resp) != 1 || resp[0].ID != "3_up" || resp[0].SlotCount != 3 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestBooksHandler_UpdatePageLayout_InUse(t *testing.T) {
+	mockBW, handler := setupBookTest(t)
+	mockBW.AddPageLayout(threeUpLayout())
+	mockBW.AddPage(database.BookPage{ID: "p1", BookID: "b1", Format: "3_up"})
+
+	// Dropping a slot of a layout used by a page is refused.
+	body := `{"name":"2-up","rows":1,"cells":[` +
+		`{"col":0,"col_span":6,"row":0,"row_span":1},{"col":6,"col_span":6,"row":0,"row_span":1}]}`
+	req := httptest.NewRequestWithContext(context.Background(), "PUT", "/api/v1/page-layouts/3_up",
+		bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = requestWithChiParams(req, map[string]string{"id": "3_up"})
+	recorder := httptest.NewRecorder()
+	handler.UpdatePageLayout(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusConflict)
+}
+
+func TestBooksHandler_DeletePageLayout(t *testing.T) {
+	mockBW, handler := setupBookTest(t)
+	mockBW.AddPageLayout(threeUpLayout())
+	mockBW.AddPage(database.BookPage{ID: "p1", BookID: "b1", Format: "3_up"})
+
+	del := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequestWithContext(context.Background(), "DELETE", "/api/v1/page-layouts/3_up", nil)
+		req = requestWithChiParams(req, map[string]string{"id": "3_up"})
+		recorder := httptest.NewRecorder()
+		handler.DeletePageLayout(recorder, req)
+		return recorder
+	}
+
+	assertStatusCode(t, del(), http.StatusConflict)
+
+	_ = mockBW.DeletePage(context.Background(), "p1")
+	assertStatusCode(t, del(), http.StatusOK)
+	assertStatusCode(t, del(), http.StatusNotFound)
+}
+
+func TestBooksHandler_CreatePage_CustomLayout(t *testing.T) {
+	mockBW, handler := setupBookTest(t)
+	mockBW.AddPageLayout(threeUpLayout())
+
+	body := bytes.NewBufferString(`{"format":"3_up","section_id":"s1"}`)
+	req := httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/books/b1/pages", body)
+	req.Header.Set("Content-Type", "application/json")
+	req = requestWithChiParams(req, map[string]string{"id": "b1"})
+	recorder := httptest.NewRecorder()
+	handler.CreatePage(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusCreated)
+	var resp pageResponse
+	parseJSONResponse(t, recorder, &resp)
+	if resp.Format != "3_up" || resp.Layout == nil || resp.Layout.SlotCount != 3 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestBooksHandler_UpdatePage_CustomLayoutShrinks(t *testing.T) {
+	mockBW, handler := setupBookTest(t)
+	mockBW.AddPageLayout(threeUpLayout())
+	mockBW.AddPage(database.BookPage{ID: "p1", BookID: "b1", SectionID: "s1", Format: "3_up"})
+	mockBW.SetPageSlots("p1", []database.PageSlot{
+		{SlotIndex: 0, PhotoUID: "a"}, {SlotIndex: 1, PhotoUID: "b"}, {SlotIndex: 2, PhotoUID: "c"},
+	})
+
+	body := bytes.NewBufferString(`{"format":"2_portrait"}`)
+	req := httptest.NewRequestWithContext(context.Background(), "PUT", "/api/v1/pages/p1", body)
+	req.Header.Set("Content-Type", "application/json")
+	req = requestWithChiParams(req, map[string]string{"id": "p1"})
+	recorder := httptest.NewRecorder()
+	handler.UpdatePage(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusOK)
+	page, _ := mockBW.GetPage(context.Background(), "p1")
+	if page.Format != "2_portrait" || page.Layout != nil {
+		t.Errorf("unexpected page: %+v", page)
+	}
+	for _, s := range page.Slots {
+		if s.SlotIndex == 2 && s.PhotoUID != "" {
+			t.Error("expected slot 2 to be cleared")
+		}
+	}
+}
+
+func TestBooksHandler_UpdatePage_UnknownLayout(t *testing.T) {
+	mockBW, handler := setupBookTest(t)
+	mockBW.AddPage(database.BookPage{ID: "p1", BookID: "b1", SectionID: "s1", Format: "4_landscape"})
+
+	body := bytes.NewBufferString(`{"format":"9_up"}`)
+	req := httptest.NewRequestWithContext(context.Background(), "PUT", "/api/v1/pages/p1", body)
+	req.Header.Set("Content-Type", "application/json")
+	req = requestWithChiParams(req, map[string]string{"id": "p1"})
+	recorder := httptest.NewRecorder()
+	handler.UpdatePage(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusBadRequest)
+	assertJSONError(t, recorder, "invalid format")
+}
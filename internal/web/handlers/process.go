@@ -3,7 +3,9 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"sync"
 	"sync/atomic"
@@ -11,6 +13,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/kozaktomas/photo-sorter/internal/changes"
 	"github.com/kozaktomas/photo-sorter/internal/config"
 	"github.com/kozaktomas/photo-sorter/internal/constants"
 	"github.com/kozaktomas/photo-sorter/internal/database"
@@ -34,6 +37,8 @@ type ProcessJob struct {
 	CompletedAt     *time.Time        `json:"completed_at,omitempty"`
 	Options         ProcessJobOptions `json:"options"`
 	Result          *ProcessJobResult `json:"result,omitempty"`
+
+	nextCursor *changes.Cursor // incremental runs: cursor to store on success
 }
 
 // ProcessJobOptions represents options for a process job.
@@ -42,6 +47,7 @@ type ProcessJobOptions struct {
 	Limit        int  `json:"limit"`
 	NoFaces      bool `json:"no_faces"`
 	NoEmbeddings bool `json:"no_embeddings"`
+	Incremental  bool `json:"incremental"` // only consider photos changed since the last incremental run
 }
 
 // ProcessJobResult represents the result of a process job.
@@ -71,6 +77,28 @@ func (j *ProcessJob) Cancel() {
 	j.mu.Unlock()
 }
 
+// markRunning transitions the job to running (implements persistentJob).
+func (j *ProcessJob) markRunning() {
+	j.mu.Lock()
+	j.Status = JobStatusRunning
+	j.mu.Unlock()
+}
+
+// snapshot returns the job's persisted representation (implements persistentJob).
+func (j *ProcessJob) snapshot() database.StoredJob {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	stored := newStoredJob(j.ID, database.JobTypeProcess, j.Status, j.Error, j.StartedAt, j.CompletedAt,
+		j.Options, j.Result)
+	stored.Progress = percentOf(j.ProcessedPhotos, j.TotalPhotos)
+	stored.Total = j.TotalPhotos
+	stored.Processed = j.ProcessedPhotos
+	if j.Status == JobStatusCompleted {
+		stored.Progress = 100
+	}
+	return stored
+}
+
 // ProcessJobManager manages process jobs (only one at a time).
 type ProcessJobManager struct {
 	activeJob *ProcessJob
@@ -106,6 +134,21 @@ func (m *ProcessJobManager) SetActiveJob(job *ProcessJob) {
 	m.activeJob = job
 }
 
+// TrySetActiveJob sets the active job unless a pending or running job is
+// already active, and reports whether it did. The check and the set happen
+// under one lock, so two concurrent starts cannot both succeed.
+func (m *ProcessJobManager) TrySetActiveJob(job *ProcessJob) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.activeJob != nil {
+		if status := m.activeJob.GetStatus(); status == JobStatusRunning || status == JobStatusPending {
+			return false
+		}
+	}
+	m.activeJob = job
+	return true
+}
+
 // ClearActiveJob clears the active job.
 func (m *ProcessJobManager) ClearActiveJob() {
 	m.mu.Lock()
@@ -121,6 +164,7 @@ type ProcessHandler struct {
 	facesHandler   *FacesHandler
 	photosHandler  *PhotosHandler
 	statsHandler   *StatsHandler
+	photoPrismDB   photoPrismDB
 }
 
 // NewProcessHandler creates a new process handler.
@@ -144,6 +188,7 @@ type ProcessStartRequest struct {
 	Limit        int  `json:"limit"`
 	NoFaces      bool `json:"no_faces"`
 	NoEmbeddings bool `json:"no_embeddings"`
+	Incremental  bool `json:"incremental"`
 }
 
 // Start starts a new processing job.
@@ -154,14 +199,6 @@ func (h *ProcessHandler) Start(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check no job already running.
-	if active := h.jobManager.GetActiveJob(); active != nil {
-		if active.Status == JobStatusRunning || active.Status == JobStatusPending {
-			respondError(w, http.StatusConflict, "a process job is already running")
-			return
-		}
-	}
-
 	var req ProcessStartRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondError(w, http.StatusBadRequest, errInvalidRequestBody)
@@ -188,7 +225,10 @@ func (h *ProcessHandler) Start(w http.ResponseWriter, r *http.Request) {
 		Options:   ProcessJobOptions(req),
 	}
 
-	h.jobManager.SetActiveJob(job)
+	if !h.jobManager.TrySetActiveJob(job) {
+		respondError(w, http.StatusConflict, "a process job is already running")
+		return
+	}
 
 	// Launch processing goroutine (intentionally outlives request).
 	go h.runProcessJob(job, session) //nolint:gosec // G118 - background job outlives HTTP request
@@ -269,36 +309,35 @@ func initProcessJobRepos(ctx context.Context, options ProcessJobOptions) (*proce
 	return repos, nil
 }
 
-// fetchAllPhotos fetches all photos from PhotoPrism with pagination, respecting context cancellation.
-func fetchAllPhotos(ctx context.Context, pp *photoprism.PhotoPrism) ([]photoprism.Photo, error) {
-	var allPhotos []photoprism.Photo
-	pageSize := constants.DefaultPageSize
-	offset := 0
-
-	for {
-		if ctx.Err() != nil {
-			return nil, fmt.Errorf("fetching photos cancelled: %w", ctx.Err())
-		}
-		photos, err := pp.GetPhotos(pageSize, offset)
+// fetchUnprocessedPhotos pages through all photos in PhotoPrism, respecting
+// context cancellation, and keeps only those that need embedding or face
+// processing, so the full library is never held in memory. It returns the
+// photos to process (at most limit, if set) and the total number of photos.
+func fetchUnprocessedPhotos(
+	ctx context.Context, pp *photoprism.PhotoPrism, repos *processJobRepos, limit int,
+) ([]photoprism.Photo, int, error) {
+	var photosToProcess []photoprism.Photo
+	total := 0
+	opts := photoprism.IterateOptions{PageSize: constants.DefaultPageSize}
+	for photos, err := range pp.IteratePhotos(ctx, "", opts) {
 		if err != nil {
-			return nil, fmt.Errorf("fetching photos: %w", err)
+			return nil, 0, fmt.Errorf("fetching photos: %w", err)
 		}
-		if len(photos) == 0 {
-			break
-		}
-		allPhotos = append(allPhotos, photos...)
-		offset += len(photos)
+		total += len(photos)
+		photosToProcess = append(photosToProcess, filterUnprocessedPhotos(ctx, photos, repos)...)
 	}
-	return allPhotos, nil
+	if limit > 0 && len(photosToProcess) > limit {
+		photosToProcess = photosToProcess[:limit]
+	}
+	return photosToProcess, total, nil
 }
 
 // filterUnprocessedPhotos filters photos that need embedding or face processing.
 func filterUnprocessedPhotos(
-	ctx context.Context, allPhotos []photoprism.Photo,
-	repos *processJobRepos, limit int,
-) ([]photoprism.Photo, int) {
+	ctx context.Context, photos []photoprism.Photo, repos *processJobRepos,
+) []photoprism.Photo {
 	var photosToProcess []photoprism.Photo
-	for _, photo := range allPhotos {
+	for _, photo := range photos {
 		needsEmbed := false
 		needsFaces := false
 		if repos.embRepo != nil {
@@ -313,13 +352,7 @@ func filterUnprocessedPhotos(
 			photosToProcess = append(photosToProcess, photo)
 		}
 	}
-
-	if limit > 0 && len(photosToProcess) > limit {
-		photosToProcess = photosToProcess[:limit]
-	}
-
-	skipped := len(allPhotos) - len(photosToProcess)
-	return photosToProcess, skipped
+	return photosToProcess
 }
 
 // processOnePhoto processes a single photo for embeddings and faces.
@@ -393,13 +426,7 @@ func processPhotoFaces(
 		atomic.AddInt64(&counters.faceError, 1)
 		return
 	}
-	faces := make([]database.StoredFace, len(result.Faces))
-	for i, f := range result.Faces {
-		faces[i] = database.StoredFace{
-			PhotoUID: photoUID, FaceIndex: f.FaceIndex, Embedding: f.Embedding,
-			BBox: f.BBox, DetScore: f.DetScore, Model: result.Model, Dim: f.Dim,
-		}
-	}
+	faces := buildStoredFaces(result, imageData, photoUID)
 	if err := faceWriter.SaveFaces(ctx, photoUID, faces); err != nil {
 		atomic.AddInt64(&counters.faceError, 1)
 		return
@@ -484,17 +511,24 @@ func (h *ProcessHandler) fetchAndFilterPhotos(
 	ctx context.Context, clients *processJobClients, repos *processJobRepos,
 	job *ProcessJob,
 ) ([]photoprism.Photo, error) {
-	allPhotos, err := fetchAllPhotos(ctx, clients.pp)
+	var photosToProcess []photoprism.Photo
+	var total int
+	var err error
+	if job.Options.Incremental {
+		photosToProcess, total, err = h.fetchChangedPhotos(ctx, clients.pp, repos, job)
+	} else {
+		photosToProcess, total, err = fetchUnprocessedPhotos(ctx, clients.pp, repos, job.Options.Limit)
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	job.mu.Lock()
-	job.TotalPhotos = len(allPhotos)
+	job.TotalPhotos = total
 	job.mu.Unlock()
-	job.SendEvent(JobEvent{Type: "photos_counted", Data: map[string]int{"total": len(allPhotos)}})
+	job.SendEvent(JobEvent{Type: "photos_counted", Data: map[string]int{"total": total}})
 
-	photosToProcess, skipped := filterUnprocessedPhotos(ctx, allPhotos, repos, job.Options.Limit)
+	skipped := total - len(photosToProcess)
 
 	job.mu.Lock()
 	job.SkippedPhotos = skipped
@@ -507,17 +541,41 @@ func (h *ProcessHandler) fetchAndFilterPhotos(
 	return photosToProcess, nil
 }
 
+// ResumeJob restarts a process job interrupted by a server restart
+// (implements JobResumer). Photos that already have embeddings or are in
+// faces_processed are skipped by the normal filtering, so the job continues
+// where the previous run stopped.
+func (h *ProcessHandler) ResumeJob(stored database.StoredJob) error {
+	var options ProcessJobOptions
+	if err := json.Unmarshal(stored.Options, &options); err != nil {
+		return fmt.Errorf("decoding process job options: %w", err)
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = constants.DefaultConcurrency
+	}
+	job := &ProcessJob{
+		ID:        stored.ID,
+		Status:    JobStatusPending,
+		StartedAt: stored.StartedAt,
+		Options:   options,
+	}
+	if !h.jobManager.TrySetActiveJob(job) {
+		return errors.New("another process job is already running")
+	}
+
+	go h.runProcessJob(job, nil)
+	return nil
+}
+
 // runProcessJob executes the process job in the background.
 func (h *ProcessHandler) runProcessJob(job *ProcessJob, session *middleware.Session) {
-	ctx, cancel := context.WithCancel(context.Background())
-	job.cancel = cancel
-	defer cancel()
-
-	job.mu.Lock()
-	job.Status = JobStatusRunning
-	job.mu.Unlock()
-	job.SendEvent(JobEvent{Type: "started", Message: "Process job started"})
+	runPersistentJob(job, "Process job started", func(ctx context.Context) {
+		h.executeProcessJob(ctx, job, session)
+	})
+}
 
+// executeProcessJob fetches unprocessed photos and runs the worker pool.
+func (h *ProcessHandler) executeProcessJob(ctx context.Context, job *ProcessJob, session *middleware.Session) {
 	repos, err := initProcessJobRepos(ctx, job.Options)
 	if err != nil {
 		h.failJob(job, err.Error())
@@ -529,6 +587,7 @@ func (h *ProcessHandler) runProcessJob(job *ProcessJob, session *middleware.Sess
 		h.failJob(job, err.Error())
 		return
 	}
+	clients.pp = clients.pp.WithContext(ctx)
 
 	photosToProcess, err := h.fetchAndFilterPhotos(ctx, clients, repos, job)
 	if err != nil {
@@ -541,6 +600,7 @@ func (h *ProcessHandler) runProcessJob(job *ProcessJob, session *middleware.Sess
 	}
 
 	if len(photosToProcess) == 0 {
+		h.saveNextCursor(ctx, job)
 		h.completeJob(job, repos.embRepo, repos.faceWriter, 0, 0, 0, 0, 0)
 		return
 	}
@@ -555,11 +615,26 @@ func (h *ProcessHandler) runProcessJob(job *ProcessJob, session *middleware.Sess
 		return
 	}
 
+	// Photos that failed stay unprocessed; keep the cursor so the next
+	// incremental run retries them.
+	if counters.embedError == 0 && counters.faceError == 0 {
+		h.saveNextCursor(ctx, job)
+	}
 	h.completeJob(job, repos.embRepo, repos.faceWriter,
 		counters.embedSuccess, counters.embedError,
 		counters.faceSuccess, counters.faceError, counters.totalNewFaces)
 }
 
+// saveNextCursor stores the cursor of an incremental job, if any.
+func (h *ProcessHandler) saveNextCursor(ctx context.Context, job *ProcessJob) {
+	job.mu.RLock()
+	next := job.nextCursor
+	job.mu.RUnlock()
+	if next != nil {
+		saveSyncCursor(ctx, changes.CursorProcess, *next)
+	}
+}
+
 func (h *ProcessHandler) sendProgress(job *ProcessJob, processed int) {
 	job.mu.Lock()
 	job.ProcessedPhotos = processed
@@ -726,26 +801,41 @@ type SyncCacheResponse struct {
 	PhotosScanned int    `json:"photos_scanned"`
 	FacesUpdated  int    `json:"faces_updated"`
 	PhotosDeleted int    `json:"photos_deleted"`
+	Errors        int    `json:"errors"` // photos that failed to sync
 	DurationMs    int64  `json:"duration_ms"`
+	Incremental   bool   `json:"incremental,omitempty"`
 	Error         string `json:"error,omitempty"`
 }
 
+// SyncCacheRequest represents the optional options of a cache sync.
+type SyncCacheRequest struct {
+	Incremental bool `json:"incremental"` // only sync photos changed since the last incremental sync
+}
+
 // RebuildIndex rebuilds the HNSW indexes and reloads them in memory.
 func (h *ProcessHandler) RebuildIndex(w http.ResponseWriter, _ *http.Request) {
-	ctx := context.Background()
+	resp, err := rebuildIndexes(context.Background())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// rebuildIndexes rebuilds the in-memory HNSW indexes from PostgreSQL and
+// saves them to disk.
+func rebuildIndexes(ctx context.Context) (*RebuildIndexResponse, error) {
 	startTime := time.Now()
 
 	// Rebuild face HNSW index.
 	faceRebuilder := database.GetFaceHNSWRebuilder()
 	if faceRebuilder == nil {
-		respondError(w, http.StatusInternalServerError, "Face HNSW rebuilder not registered")
-		return
+		return nil, errors.New("face HNSW rebuilder not registered")
 	}
 
 	// Rebuild in-memory face HNSW index from PostgreSQL.
 	if err := faceRebuilder.RebuildHNSW(ctx); err != nil {
-		respondError(w, http.StatusInternalServerError, fmt.Sprintf("failed to rebuild face HNSW index: %v", err))
-		return
+		return nil, fmt.Errorf("failed to rebuild face HNSW index: %w", err)
 	}
 
 	// Save face index to disk if path is configured.
@@ -780,16 +870,14 @@ func (h *ProcessHandler) RebuildIndex(w http.ResponseWriter, _ *http.Request) {
 		}
 	}
 
-	durationMs := time.Since(startTime).Milliseconds()
-
-	respondJSON(w, http.StatusOK, RebuildIndexResponse{
+	return &RebuildIndexResponse{
 		Success:            true,
 		FaceCount:          faceCount,
 		EmbeddingCount:     embCount,
 		FaceIndexPath:      "(persisted)",
 		EmbeddingIndexPath: "(persisted)",
-		DurationMs:         durationMs,
-	})
+		DurationMs:         time.Since(startTime).Milliseconds(),
+	}, nil
 }
 
 // collectSyncPhotoUIDs collects all unique photo UIDs from faces and embeddings tables.
@@ -829,29 +917,78 @@ func collectSyncPhotoUIDs(
 // been deleted or archived in PhotoPrism.
 func (h *ProcessHandler) SyncCache(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	startTime := time.Now()
 
 	pp := middleware.MustGetPhotoPrism(ctx, w)
 	if pp == nil {
 		return
 	}
 
-	faceWriter, err := database.GetFaceWriter(ctx)
+	var req SyncCacheRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, errInvalidRequestBody)
+		return
+	}
+
+	resp, err := h.syncCache(ctx, pp, req.Incremental)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, fmt.Sprintf("failed to get face writer: %v", err))
+		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// syncCache refreshes the cached marker data of every photo with faces or
+// embeddings and removes photos deleted or archived in PhotoPrism. An
+// incremental sync only looks at the cached photos changed since the last
+// incremental sync. The cursor is only advanced if every photo synced, so
+// failed photos are retried by the next incremental sync.
+func (h *ProcessHandler) syncCache(
+	ctx context.Context, pp *photoprism.PhotoPrism, incremental bool,
+) (*SyncCacheResponse, error) {
+	startTime := time.Now()
+
+	faceWriter, err := database.GetFaceWriter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get face writer: %w", err)
+	}
 
 	embWriter, _ := database.GetEmbeddingWriter(ctx)
 
 	photoUIDs, err := collectSyncPhotoUIDs(ctx, faceWriter, embWriter)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, fmt.Sprintf("failed to get face photo UIDs: %v", err))
-		return
+		return nil, fmt.Errorf("failed to get face photo UIDs: %w", err)
 	}
 
-	var facesUpdated int64
-	var photosDeleted int64
+	var set *changes.Set
+	if incremental {
+		if set, err = h.detectChanges(ctx, pp, changes.CursorCacheSync); err != nil {
+			return nil, err
+		}
+		photoUIDs = set.Among(photoUIDs)
+	}
+
+	facesUpdated, photosDeleted, errorCount := h.syncPhotos(ctx, pp, faceWriter, embWriter, photoUIDs)
+
+	if h.statsHandler != nil {
+		h.statsHandler.InvalidateCache()
+	}
+	if set != nil && ctx.Err() == nil && errorCount == 0 {
+		saveSyncCursor(ctx, changes.CursorCacheSync, set.Next)
+	}
+
+	return &SyncCacheResponse{
+		Success: true, PhotosScanned: len(photoUIDs),
+		FacesUpdated: int(facesUpdated), PhotosDeleted: int(photosDeleted), Errors: int(errorCount),
+		DurationMs: time.Since(startTime).Milliseconds(), Incremental: incremental,
+	}, nil
+}
+
+// syncPhotos syncs the cache of the given photos in parallel and returns the
+// number of faces updated, photos deleted and photos that failed to sync.
+func (h *ProcessHandler) syncPhotos(
+	ctx context.Context, pp *photoprism.PhotoPrism, faceWriter database.FaceWriter,
+	embWriter database.EmbeddingWriter, photoUIDs []string,
+) (facesUpdated, photosDeleted, errorCount int64) {
 	var wg sync.WaitGroup
 	sem := make(chan struct{}, constants.WorkerPoolSize)
 
@@ -861,7 +998,12 @@ func (h *ProcessHandler) SyncCache(w http.ResponseWriter, r *http.Request) {
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
-			updated, deleted := h.syncPhotoCache(ctx, pp, faceWriter, embWriter, uid)
+			updated, deleted, err := h.syncPhotoCache(ctx, pp, faceWriter, embWriter, uid)
+			if err != nil {
+				fmt.Printf("Warning: cache sync of photo %s failed: %v\n", uid, err)
+				atomic.AddInt64(&errorCount, 1)
+				return
+			}
 			if updated > 0 {
 				atomic.AddInt64(&facesUpdated, int64(updated))
 			}
@@ -872,16 +1014,7 @@ func (h *ProcessHandler) SyncCache(w http.ResponseWriter, r *http.Request) {
 	}
 
 	wg.Wait()
-
-	if h.statsHandler != nil {
-		h.statsHandler.InvalidateCache()
-	}
-
-	respondJSON(w, http.StatusOK, SyncCacheResponse{
-		Success: true, PhotosScanned: len(photoUIDs),
-		FacesUpdated: int(facesUpdated), PhotosDeleted: int(photosDeleted),
-		DurationMs: time.Since(startTime).Milliseconds(),
-	})
+	return facesUpdated, photosDeleted, errorCount
 }
 
 // cleanupDeletedPhoto removes all cached data for a deleted/archived photo.
@@ -931,47 +1064,47 @@ func syncFaceMarkers(
 }
 
 // fetchPhotoDetailsForSync fetches photo details and handles deleted/archived photos.
-// Returns details and fileInfo on success, or (nil, nil, true) if the photo.
-// was deleted, or (nil, nil, false) on other errors.
+// Returns fileInfo on success, (nil, true, nil) if the photo was deleted,
+// (nil, false, nil) if it has no usable primary file, and an error if the
+// details could not be fetched.
 func fetchPhotoDetailsForSync(
 	ctx context.Context, pp *photoprism.PhotoPrism,
 	faceWriter database.FaceWriter, embWriter database.EmbeddingWriter,
 	photoUID string,
-) (details map[string]any, fileInfo *facematch.PrimaryFileInfo, deleted bool) {
-	var err error
-	details, err = pp.GetPhotoDetails(photoUID)
+) (fileInfo *facematch.PrimaryFileInfo, deleted bool, err error) {
+	details, err := pp.GetPhotoDetails(photoUID)
 	if err != nil {
 		if photoprism.IsNotFoundError(err) {
 			cleanupDeletedPhoto(ctx, faceWriter, embWriter, photoUID)
-			return nil, nil, true
+			return nil, true, nil
 		}
-		return nil, nil, false
+		return nil, false, fmt.Errorf("failed to get photo details: %w", err)
 	}
 
 	if photoprism.IsPhotoDeleted(details) {
 		cleanupDeletedPhoto(ctx, faceWriter, embWriter, photoUID)
-		return nil, nil, true
+		return nil, true, nil
 	}
 
 	fileInfo = facematch.ExtractPrimaryFileInfo(details)
 	if fileInfo == nil || fileInfo.Width == 0 || fileInfo.Height == 0 {
-		return nil, nil, false
+		return nil, false, nil
 	}
-	return details, fileInfo, false
+	return fileInfo, false, nil
 }
 
-// syncPhotoCache syncs the cache for a single photo and returns the number of faces updated.
-// and whether the photo was deleted/archived in PhotoPrism (404 or DeletedAt set).
+// syncPhotoCache syncs the cache for a single photo and returns the number of faces updated,
+// whether the photo was deleted/archived in PhotoPrism (404 or DeletedAt set), and any error.
 func (h *ProcessHandler) syncPhotoCache(
 	ctx context.Context, pp *photoprism.PhotoPrism,
 	faceWriter database.FaceWriter, embWriter database.EmbeddingWriter,
 	photoUID string,
-) (int, bool) {
-	_, fileInfo, deleted := fetchPhotoDetailsForSync(
+) (int, bool, error) {
+	fileInfo, deleted, err := fetchPhotoDetailsForSync(
 		ctx, pp, faceWriter, embWriter, photoUID,
 	)
-	if fileInfo == nil {
-		return 0, deleted
+	if err != nil || fileInfo == nil {
+		return 0, deleted, err
 	}
 
 	faceWriter.UpdateFacePhotoInfo(
@@ -981,15 +1114,21 @@ func (h *ProcessHandler) syncPhotoCache(
 	)
 
 	markers, err := pp.GetPhotoMarkers(photoUID)
-	if err != nil || len(markers) == 0 {
-		return 0, false
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get markers: %w", err)
+	}
+	if len(markers) == 0 {
+		return 0, false, nil
 	}
 
 	faces, err := faceWriter.GetFaces(ctx, photoUID)
-	if err != nil || len(faces) == 0 {
-		return 0, false
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get faces: %w", err)
+	}
+	if len(faces) == 0 {
+		return 0, false, nil
 	}
 
 	markerInfos := convertMarkersToInfos(markers)
-	return syncFaceMarkers(ctx, faceWriter, photoUID, faces, markerInfos, fileInfo), false
+	return syncFaceMarkers(ctx, faceWriter, photoUID, faces, markerInfos, fileInfo), false, nil
 }
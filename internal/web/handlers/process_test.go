@@ -4,10 +4,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/kozaktomas/photo-sorter/internal/changes"
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/database/mock"
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+	"github.com/kozaktomas/photo-sorter/internal/photoprism/fake"
 	"github.com/kozaktomas/photo-sorter/internal/web/middleware"
 )
 
@@ -368,3 +376,112 @@ func TestProcessJobResult_JSON(t *testing.T) {
 		t.Errorf("expected total_new_faces 120, got %d", parsed.TotalNewFaces)
 	}
 }
+
+func TestProcessJobManager_TrySetActiveJob(t *testing.T) {
+	manager := NewProcessJobManager()
+
+	// Concurrent starts: exactly one job becomes active.
+	var started atomic.Int32
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Go(func() {
+			job := &ProcessJob{ID: fmt.Sprintf("job-%d", i), Status: JobStatusPending}
+			if manager.TrySetActiveJob(job) {
+				started.Add(1)
+			}
+		})
+	}
+	wg.Wait()
+	if started.Load() != 1 {
+		t.Fatalf("expected exactly one job to start, got %d", started.Load())
+	}
+
+	active := manager.GetActiveJob()
+	active.mu.Lock()
+	active.Status = JobStatusCompleted
+	active.mu.Unlock()
+	next := &ProcessJob{ID: "next", Status: JobStatusPending}
+	if !manager.TrySetActiveJob(next) || manager.GetActiveJob() != next {
+		t.Error("expected a new job to replace a finished one")
+	}
+}
+
+// memoryCursorStore is an in-memory database.SyncCursorStore.
+type memoryCursorStore struct {
+	mu      sync.Mutex
+	cursors map[string]database.SyncCursor
+}
+
+//nolint:nilnil // nil means no cursor, like the PostgreSQL store
+func (m *memoryCursorStore) GetSyncCursor(_ context.Context, name string) (*database.SyncCursor, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.cursors[name]
+	if !ok {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+func (m *memoryCursorStore) SaveSyncCursor(_ context.Context, c *database.SyncCursor) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cursors[c.Name] = *c
+	return nil
+}
+
+func TestProcessHandler_SyncCache_FailedPhotoKeepsCursor(t *testing.T) {
+	s := fake.New(fake.Options{})
+	ok := s.AddPhoto(photoprism.Photo{}, []byte("ok"))
+	failing := s.AddPhoto(photoprism.Photo{}, []byte("failing"))
+
+	// The details of one photo fail until fail is cleared.
+	var fail atomic.Bool
+	fail.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() && r.URL.Path == "/api/v1/photos/"+failing {
+			http.Error(w, "database unavailable", http.StatusInternalServerError)
+			return
+		}
+		s.Handler().ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	pp, err := photoprism.NewPhotoPrism(server.URL, "admin", "secret")
+	if err != nil {
+		t.Fatalf("NewPhotoPrism failed: %v", err)
+	}
+
+	faceWriter := mock.NewMockFaceWriter()
+	faceWriter.AddFaces(ok, []database.StoredFace{{PhotoUID: ok}})
+	faceWriter.AddFaces(failing, []database.StoredFace{{PhotoUID: failing}})
+	store := &memoryCursorStore{cursors: make(map[string]database.SyncCursor)}
+	database.RegisterPostgresBackend(nil, nil, func() database.FaceWriter { return faceWriter })
+	database.RegisterSyncCursorStore(func() database.SyncCursorStore { return store })
+	t.Cleanup(database.ResetForTesting)
+
+	handler := NewProcessHandler(testConfig(), middleware.NewSessionManager("test-secret", nil), nil, nil, nil)
+	ctx := context.Background()
+
+	resp, err := handler.syncCache(ctx, pp, true)
+	if err != nil {
+		t.Fatalf("syncCache failed: %v", err)
+	}
+	if resp.PhotosScanned != 2 || resp.Errors != 1 {
+		t.Errorf("expected 2 photos scanned and 1 error, got %+v", resp)
+	}
+	if c, _ := store.GetSyncCursor(ctx, changes.CursorCacheSync); c != nil {
+		t.Fatalf("expected the cursor not to advance after a failed photo, got %+v", c)
+	}
+
+	fail.Store(false)
+	resp, err = handler.syncCache(ctx, pp, true)
+	if err != nil {
+		t.Fatalf("syncCache failed: %v", err)
+	}
+	if resp.PhotosScanned != 2 || resp.Errors != 0 {
+		t.Errorf("expected the failed photo to be retried, got %+v", resp)
+	}
+	if c, _ := store.GetSyncCursor(ctx, changes.CursorCacheSync); c == nil {
+		t.Error("expected the cursor to be saved after a clean sync")
+	}
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kozaktomas/photo-sorter/internal/constants"
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/scheduler"
+)
+
+// ScheduledSyncResult summarizes a scheduled sync run.
+type ScheduledSyncResult struct {
+	ProcessJobID   string                `json:"process_job_id,omitempty"`
+	Process        *ProcessJobResult     `json:"process,omitempty"`
+	ProcessSkipped string                `json:"process_skipped,omitempty"`
+	Sync           *SyncCacheResponse    `json:"sync,omitempty"`
+	Index          *RebuildIndexResponse `json:"index,omitempty"`
+}
+
+// RunScheduledSync is the task run by the serve scheduler. It processes new
+// photos (embeddings and faces) as a regular process job, syncs PhotoPrism
+// markers into the faces cache and, if anything changed, rebuilds and saves
+// the HNSW indexes. Both steps are incremental: only photos changed since the
+// previous run are looked at (all photos on the first run). PhotoPrism is
+// accessed with the configured credentials.
+func (h *ProcessHandler) RunScheduledSync(ctx context.Context) (any, error) {
+	if !database.IsInitialized() {
+		return nil, errors.New("DATABASE_URL is not configured")
+	}
+	result := &ScheduledSyncResult{}
+
+	if err := h.runScheduledProcessJob(ctx, result); err != nil {
+		return result, err
+	}
+
+	pp, err := getPhotoPrismClient(h.config, nil)
+	if err != nil {
+		return result, err
+	}
+	synced, err := h.syncCache(ctx, pp.WithContext(ctx), true)
+	if err != nil {
+		return result, err
+	}
+	result.Sync = synced
+	if ctx.Err() != nil {
+		return result, fmt.Errorf("sync cancelled: %w", ctx.Err())
+	}
+
+	changed := synced.FacesUpdated > 0 || synced.PhotosDeleted > 0
+	if p := result.Process; p != nil && p.EmbedSuccess+p.FaceSuccess > 0 {
+		changed = true
+	}
+	if !changed {
+		return result, nil
+	}
+	if h.facesHandler != nil {
+		h.facesHandler.RefreshReader()
+	}
+	index, err := rebuildIndexes(ctx)
+	if err != nil {
+		return result, err
+	}
+	result.Index = index
+	return result, nil
+}
+
+// runScheduledProcessJob runs an incremental process job with default
+// options and waits for it to finish. The job is registered as the active process job, so it
+// shows up in the process UI and can be cancelled there. It is skipped if
+// another process job is already running.
+func (h *ProcessHandler) runScheduledProcessJob(ctx context.Context, result *ScheduledSyncResult) error {
+	job := &ProcessJob{
+		ID:        uuid.New().String(),
+		Status:    JobStatusPending,
+		StartedAt: time.Now(),
+		Options:   ProcessJobOptions{Concurrency: constants.DefaultConcurrency, Incremental: true},
+	}
+	if !h.jobManager.TrySetActiveJob(job) {
+		result.ProcessSkipped = "another process job is running"
+		return nil
+	}
+	result.ProcessJobID = job.ID
+
+	stop := context.AfterFunc(ctx, job.Cancel)
+	h.runProcessJob(job, nil)
+	stop()
+
+	job.mu.RLock()
+	defer job.mu.RUnlock()
+	switch job.Status {
+	case JobStatusCompleted:
+		result.Process = job.Result
+		return nil
+	case JobStatusFailed:
+		return fmt.Errorf("process job failed: %s", job.Error)
+	default:
+		return fmt.Errorf("process job %s", job.Status)
+	}
+}
+
+// ScheduleHandler exposes the status of the serve scheduler.
+type ScheduleHandler struct {
+	scheduler *scheduler.Scheduler // nil if no schedule is configured
+}
+
+// NewScheduleHandler creates a new schedule handler. s may be nil.
+func NewScheduleHandler(s *scheduler.Scheduler) *ScheduleHandler {
+	return &ScheduleHandler{scheduler: s}
+}
+
+// ScheduleStatusResponse represents the scheduler status.
+type ScheduleStatusResponse struct {
+	Enabled bool `json:"enabled"`
+	scheduler.Status
+}
+
+// Status returns the schedule, the next run and the outcome of the last run.
+func (h *ScheduleHandler) Status(w http.ResponseWriter, _ *http.Request) {
+	if h.scheduler == nil {
+		respondJSON(w, http.StatusOK, ScheduleStatusResponse{})
+		return
+	}
+	respondJSON(w, http.StatusOK, ScheduleStatusResponse{Enabled: true, Status: h.scheduler.Status()})
+}
+
+// Run triggers an immediate scheduled run.
+func (h *ScheduleHandler) Run(w http.ResponseWriter, _ *http.Request) {
+	if h.scheduler == nil {
+		respondError(w, http.StatusBadRequest, "scheduler is not enabled (set SYNC_SCHEDULE)")
+		return
+	}
+	if !h.scheduler.Trigger() {
+		respondError(w, http.StatusConflict, "a scheduled run is already in progress")
+		return
+	}
+	log.Printf("Scheduler: run triggered via API")
+	respondJSON(w, http.StatusAccepted, map[string]string{"status": "triggered"})
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kozaktomas/photo-sorter/internal/scheduler"
+)
+
+func TestScheduleHandler_Disabled(t *testing.T) {
+	handler := NewScheduleHandler(nil)
+
+	req := httptest.NewRequestWithContext(context.Background(), "GET", "/api/v1/process/schedule", nil)
+	recorder := httptest.NewRecorder()
+	handler.Status(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusOK)
+	var status ScheduleStatusResponse
+	parseJSONResponse(t, recorder, &status)
+	if status.Enabled || status.LastRun != nil {
+		t.Errorf("expected disabled scheduler, got %+v", status)
+	}
+
+	req = httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/process/schedule/run", nil)
+	recorder = httptest.NewRecorder()
+	handler.Run(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusBadRequest)
+	assertJSONError(t, recorder, "scheduler is not enabled (set SYNC_SCHEDULE)")
+}
+
+func TestScheduleHandler_RunAndStatus(t *testing.T) {
+	release := make(chan struct{})
+	sched, err := scheduler.New("0 3 * * *", func(context.Context) (any, error) {
+		<-release
+		return ScheduledSyncResult{ProcessSkipped: "another process job is running"}, nil
+	})
+	if err != nil {
+		t.Fatalf("scheduler.New failed: %v", err)
+	}
+	sched.Start(context.Background())
+	defer sched.Stop()
+	handler := NewScheduleHandler(sched)
+
+	req := httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/process/schedule/run", nil)
+	recorder := httptest.NewRecorder()
+	handler.Run(recorder, req)
+	assertStatusCode(t, recorder, http.StatusAccepted)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !sched.Status().Running && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	recorder = httptest.NewRecorder()
+	handler.Run(recorder, req)
+	assertStatusCode(t, recorder, http.StatusConflict)
+
+	close(release)
+	for sched.Status().Running && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	req = httptest.NewRequestWithContext(context.Background(), "GET", "/api/v1/process/schedule", nil)
+	recorder = httptest.NewRecorder()
+	handler.Status(recorder, req)
+
+	assertStatusCode(t, recorder, http.StatusOK)
+	var status struct {
+		Enabled  bool       `json:"enabled"`
+		Schedule string     `json:"schedule"`
+		NextRun  *time.Time `json:"next_run"`
+		LastRun  *struct {
+			Status  string              `json:"status"`
+			Trigger string              `json:"trigger"`
+			Result  ScheduledSyncResult `json:"result"`
+		} `json:"last_run"`
+	}
+	parseJSONResponse(t, recorder, &status)
+	if !status.Enabled || status.Schedule != "0 3 * * *" || status.NextRun == nil {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+	if status.LastRun == nil || status.LastRun.Status != "completed" || status.LastRun.Trigger != "manual" {
+		t.Fatalf("unexpected last run: %+v", status.LastRun)
+	}
+	if status.LastRun.Result.ProcessSkipped != "another process job is running" {
+		t.Errorf("unexpected result: %+v", status.LastRun.Result)
+	}
+}
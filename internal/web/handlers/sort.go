@@ -7,7 +7,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -15,8 +18,10 @@ import (
 	"github.com/kozaktomas/photo-sorter/internal/ai"
 	"github.com/kozaktomas/photo-sorter/internal/config"
 	"github.com/kozaktomas/photo-sorter/internal/constants"
+	"github.com/kozaktomas/photo-sorter/internal/database"
 	"github.com/kozaktomas/photo-sorter/internal/photoprism"
 	"github.com/kozaktomas/photo-sorter/internal/sorter"
+	"github.com/kozaktomas/photo-sorter/internal/taxonomy"
 
 	"github.com/kozaktomas/photo-sorter/internal/web/middleware"
 )
@@ -39,14 +44,39 @@ func NewSortHandler(cfg *config.Config, sm *middleware.SessionManager, jm *JobMa
 
 // StartRequest represents a sort start request.
 type StartRequest struct {
-	AlbumUID        string `json:"album_uid"`
-	DryRun          bool   `json:"dry_run"`
-	Limit           int    `json:"limit"`
-	IndividualDates bool   `json:"individual_dates"`
-	BatchMode       bool   `json:"batch_mode"`
-	Provider        string `json:"provider"`
-	ForceDate       bool   `json:"force_date"`
-	Concurrency     int    `json:"concurrency"`
+	AlbumUID        string   `json:"album_uid"`
+	DryRun          bool     `json:"dry_run"`
+	Limit           int      `json:"limit"`
+	IndividualDates bool     `json:"individual_dates"`
+	BatchMode       bool     `json:"batch_mode"`
+	Provider        string   `json:"provider"`
+	ForceDate       bool     `json:"force_date"`
+	Concurrency     int      `json:"concurrency"`
+	NoCache         bool     `json:"no_cache"`
+	Consensus       []string `json:"consensus,omitempty"`
+}
+
+// normalize fills in defaults and validates the request, returning an error message if invalid.
+func (req *StartRequest) normalize() string {
+	if req.AlbumUID == "" {
+		return "album_uid is required"
+	}
+	if req.Provider == "" {
+		req.Provider = constants.ProviderOpenAI
+	}
+	if req.Concurrency <= 0 {
+		req.Concurrency = constants.DefaultConcurrency
+	}
+	if len(req.Consensus) > 0 {
+		req.Consensus = uniqueProviders(req.Consensus)
+		if len(req.Consensus) < 2 {
+			return "consensus needs at least two providers"
+		}
+		if req.BatchMode {
+			return "consensus cannot be combined with batch mode"
+		}
+	}
+	return ""
 }
 
 // Start starts a new sort job.
@@ -57,19 +87,11 @@ func (h *SortHandler) Start(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.AlbumUID == "" {
-		respondError(w, http.StatusBadRequest, "album_uid is required")
+	if errMsg := req.normalize(); errMsg != "" {
+		respondError(w, http.StatusBadRequest, errMsg)
 		return
 	}
 
-	if req.Provider == "" {
-		req.Provider = constants.ProviderOpenAI
-	}
-
-	if req.Concurrency <= 0 {
-		req.Concurrency = constants.DefaultConcurrency
-	}
-
 	pp := middleware.MustGetPhotoPrism(r.Context(), w)
 	if pp == nil {
 		return
@@ -97,6 +119,8 @@ func (h *SortHandler) Start(w http.ResponseWriter, r *http.Request) {
 		Provider:        req.Provider,
 		ForceDate:       req.ForceDate,
 		Concurrency:     req.Concurrency,
+		NoCache:         req.NoCache,
+		Consensus:       req.Consensus,
 	}
 	job := h.jobManager.CreateJob(jobID, req.AlbumUID, album.Title, options)
 
@@ -162,6 +186,67 @@ func (h *SortHandler) Cancel(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, map[string]bool{"cancelled": true})
 }
 
+// RevertResponse is the result of reverting a sort run.
+type RevertResponse struct {
+	RunID           string   `json:"run_id"`
+	RevertedCount   int      `json:"reverted_count"`
+	AlreadyReverted int      `json:"already_reverted"`
+	Errors          []string `json:"errors,omitempty"`
+}
+
+// Revert restores every photo changed by a sort job to its state before the
+// job ran, using the undo log recorded while the job applied its changes.
+func (h *SortHandler) Revert(w http.ResponseWriter, r *http.Request) {
+	jobID := chi.URLParam(r, "jobId")
+	if jobID == "" {
+		respondError(w, http.StatusBadRequest, "missing job ID")
+		return
+	}
+
+	if job := h.jobManager.GetJob(jobID); job != nil {
+		if status := job.GetStatus(); status == JobStatusPending || status == JobStatusRunning {
+			respondError(w, http.StatusConflict, "job is still running")
+			return
+		}
+	}
+
+	store, err := database.GetSortUndoStore(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "undo log not available")
+		return
+	}
+	changes, err := store.ListSortChanges(r.Context(), jobID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to load undo log")
+		return
+	}
+	if len(changes) == 0 {
+		respondError(w, http.StatusNotFound, "no changes recorded for this job")
+		return
+	}
+
+	pp := middleware.MustGetPhotoPrism(r.Context(), w)
+	if pp == nil {
+		return
+	}
+
+	result, err := sorter.RevertRun(r.Context(), pp, store, jobID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("revert failed: %v", err))
+		return
+	}
+
+	resp := RevertResponse{
+		RunID:           result.RunID,
+		RevertedCount:   result.RevertedCount,
+		AlreadyReverted: result.AlreadyCount,
+	}
+	for _, e := range result.Errors {
+		resp.Errors = append(resp.Errors, e.Error())
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
 func (h *SortHandler) initSortJobDeps(
 	job *SortJob, session *middleware.Session,
 ) (*photoprism.PhotoPrism, ai.Provider, error) {
@@ -170,13 +255,23 @@ func (h *SortHandler) initSortJobDeps(
 		return nil, nil, fmt.Errorf("failed to connect to PhotoPrism: %w", err)
 	}
 
-	aiProvider, err := h.createAIProvider(job.Options.Provider)
+	var aiProvider ai.Provider
+	if len(job.Options.Consensus) > 0 {
+		aiProvider, err = h.createConsensusProvider(job.Options.Consensus)
+	} else {
+		aiProvider, err = h.createAIProvider(job.Options.Provider)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
 
 	if job.Options.BatchMode {
 		aiProvider.SetBatchMode(true)
+		if lb, ok := aiProvider.(ai.LocalBatcher); ok {
+			if err := lb.EnableLocalBatch(h.config.LocalBatch.Dir, job.Options.Concurrency); err != nil {
+				return nil, nil, fmt.Errorf("failed to enable local batch queue: %w", err)
+			}
+		}
 	}
 
 	return pp, aiProvider, nil
@@ -210,22 +305,39 @@ func (job *SortJob) buildSortOptions() sorter.SortOptions {
 	}
 }
 
+// ResumeJob restarts a sort job interrupted by a server restart (implements
+// JobResumer). The album is sorted again from the start with the original
+// options; the job keeps its ID so clients polling it see the new run.
+func (h *SortHandler) ResumeJob(stored database.StoredJob) error {
+	var params sortJobParams
+	if err := json.Unmarshal(stored.Options, &params); err != nil {
+		return fmt.Errorf("decoding sort job options: %w", err)
+	}
+	if params.AlbumUID == "" {
+		return errors.New("sort job has no album")
+	}
+	job := h.jobManager.CreateJob(stored.ID, params.AlbumUID, params.AlbumTitle, params.SortJobOptions)
+	job.StartedAt = stored.StartedAt
+
+	go h.runSortJob(job, nil)
+	return nil
+}
+
 // runSortJob runs the sort job in the background.
 func (h *SortHandler) runSortJob(job *SortJob, session *middleware.Session) {
-	ctx, cancel := context.WithCancel(context.Background())
-	job.cancel = cancel
-	defer cancel()
-
-	job.mu.Lock()
-	job.Status = JobStatusRunning
-	job.mu.Unlock()
-	job.SendEvent(JobEvent{Type: "started", Message: "Sort job started"})
+	runPersistentJob(job, "Sort job started", func(ctx context.Context) {
+		h.executeSortJob(ctx, job, session)
+	})
+}
 
+// executeSortJob fetches the album photos and runs the sorter.
+func (h *SortHandler) executeSortJob(ctx context.Context, job *SortJob, session *middleware.Session) {
 	pp, aiProvider, err := h.initSortJobDeps(job, session)
 	if err != nil {
 		h.failJob(job, err.Error())
 		return
 	}
+	pp = pp.WithContext(ctx)
 
 	limit := job.Options.Limit
 	if limit == 0 {
@@ -242,8 +354,11 @@ func (h *SortHandler) runSortJob(job *SortJob, session *middleware.Session) {
 	job.mu.Unlock()
 	job.SendEvent(JobEvent{Type: "photos_counted", Data: map[string]int{"total": len(photos)}})
 
+	opts := job.buildSortOptions()
+	attachSortDatabase(ctx, job, &opts)
+
 	s := sorter.New(pp, aiProvider)
-	result, err := s.Sort(ctx, job.AlbumUID, job.AlbumTitle, "", job.buildSortOptions())
+	result, err := s.Sort(ctx, job.AlbumUID, job.AlbumTitle, "", opts)
 
 	if err != nil {
 		if ctx.Err() != nil {
@@ -260,6 +375,32 @@ func (h *SortHandler) runSortJob(job *SortJob, session *middleware.Session) {
 	h.completeSortJob(job, result, aiProvider)
 }
 
+// attachSortDatabase adds the database-backed sort options. All of them are
+// optional, so the sort runs without a database too.
+func attachSortDatabase(ctx context.Context, job *SortJob, opts *sorter.SortOptions) {
+	if !job.Options.NoCache {
+		// The analysis cache is optional; without a database every photo is analyzed.
+		if cache, err := database.GetAnalysisCache(ctx); err == nil {
+			opts.Cache = cache
+		}
+	}
+	if store, err := database.GetLabelTaxonomyStore(ctx); err == nil {
+		tax, err := taxonomy.Load(ctx, store)
+		if err != nil {
+			log.Printf("Sort job %s: label taxonomy not applied: %v", job.ID, err)
+		} else {
+			opts.Taxonomy = tax
+		}
+	}
+	if !opts.DryRun {
+		// The job ID doubles as the undo log run ID. Without a database the
+		// sort still runs, it just cannot be reverted.
+		if store, err := database.GetSortUndoStore(ctx); err == nil {
+			opts.OnBeforeApply = sorter.UndoRecorder(ctx, store, job.ID)
+		}
+	}
+}
+
 func (h *SortHandler) completeSortJob(job *SortJob, result *sorter.SortResult, aiProvider ai.Provider) {
 	usage := aiProvider.GetUsage()
 
@@ -271,6 +412,7 @@ func (h *SortHandler) completeSortJob(job *SortJob, result *sorter.SortResult, a
 	jobResult := &SortJobResult{
 		ProcessedCount: result.ProcessedCount,
 		SortedCount:    result.SortedCount,
+		CacheHits:      result.CacheHits,
 		AlbumDate:      result.AlbumDate,
 		DateReasoning:  result.DateReasoning,
 		Errors:         errors,
@@ -304,6 +446,35 @@ func (h *SortHandler) failJob(job *SortJob, message string) {
 	job.SendEvent(JobEvent{Type: "job_error", Message: message})
 }
 
+// uniqueProviders trims provider names and drops empty and repeated ones.
+func uniqueProviders(names []string) []string {
+	var result []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name != "" && !slices.Contains(result, name) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// createConsensusProvider creates a provider combining the given providers.
+func (h *SortHandler) createConsensusProvider(names []string) (ai.Provider, error) {
+	providers := make([]ai.Provider, 0, len(names))
+	for _, name := range names {
+		p, err := h.createAIProvider(name)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, p)
+	}
+	consensus, err := ai.NewConsensusProvider(providers)
+	if err != nil {
+		return nil, fmt.Errorf("creating consensus provider: %w", err)
+	}
+	return consensus, nil
+}
+
 func (h *SortHandler) createAIProvider(providerName string) (ai.Provider, error) {
 	switch providerName {
 	case constants.ProviderOpenAI:
@@ -322,6 +493,16 @@ func (h *SortHandler) createAIProvider(providerName string) (ai.Provider, error)
 			return nil, fmt.Errorf("creating llama.cpp provider: %w", err)
 		}
 		return p, nil
+	case constants.ProviderOpenAICompatible:
+		compat := h.config.OpenAICompatible
+		pricing := h.config.GetOpenAICompatiblePricing()
+		p, err := ai.NewOpenAICompatibleProvider(compat.URL, compat.GetAPIKey(), compat.Model,
+			ai.RequestPricing{Input: pricing.Input, Output: pricing.Output},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("creating OpenAI-compatible provider: %w", err)
+		}
+		return p, nil
 	default:
 		return nil, fmt.Errorf("unknown provider: %s", providerName)
 	}
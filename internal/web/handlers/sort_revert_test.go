@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/database/mock"
+	"github.com/kozaktomas/photo-sorter/internal/photoprism"
+	"github.com/kozaktomas/photo-sorter/internal/sorter"
+	"github.com/kozaktomas/photo-sorter/internal/web/middleware"
+)
+
+func setupMockSortUndoStore(t *testing.T) *mock.MockSortUndoStore {
+	t.Helper()
+	store := mock.NewMockSortUndoStore()
+	database.RegisterPostgresBackend(nil, nil, nil)
+	database.RegisterSortUndoStore(func() database.SortUndoStore { return store })
+	t.Cleanup(database.ResetForTesting)
+	return store
+}
+
+func recordSnapshot(t *testing.T, store *mock.MockSortUndoStore, runID string, snap sorter.PhotoSnapshot) {
+	t.Helper()
+	if err := sorter.UndoRecorder(context.Background(), store, runID)(snap); err != nil {
+		t.Fatalf("record snapshot: %v", err)
+	}
+}
+
+func revertRequest(
+	t *testing.T, handler *SortHandler, jobID string, pp *photoprism.PhotoPrism,
+) *httptest.ResponseRecorder {
+	t.Helper()
+	ctx := context.Background()
+	if pp != nil {
+		ctx = middleware.SetPhotoPrismInContext(ctx, pp)
+	}
+	req := httptest.NewRequestWithContext(ctx, "POST", "/api/v1/sort/"+jobID+"/revert", nil)
+	req = requestWithChiParams(req, map[string]string{"jobId": jobID})
+	recorder := httptest.NewRecorder()
+	handler.Revert(recorder, req)
+	return recorder
+}
+
+func TestSortHandler_Revert_Success(t *testing.T) {
+	store := setupMockSortUndoStore(t)
+	recordSnapshot(t, store, "job1", sorter.PhotoSnapshot{
+		PhotoUID:    "photo1",
+		Title:       "Old title",
+		Description: "Old description",
+		Labels:      []sorter.SnapshotLabel{{Name: "Beach", LabelSrc: "image", Uncertainty: 20}},
+	})
+
+	var mu sync.Mutex
+	var addedLabels []string
+	var edited map[string]any
+	server := setupMockPhotoPrismServer(t, map[string]http.HandlerFunc{
+		"/api/v1/photos/photo1": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.Method == "PUT" {
+				mu.Lock()
+				json.NewDecoder(r.Body).Decode(&edited)
+				mu.Unlock()
+			}
+			w.Write([]byte(`{"UID": "photo1", "Labels": [{"LabelID": 7, "Label": {"Name": "Dog"}}]}`))
+		},
+		"/api/v1/photos/photo1/label": func(w http.ResponseWriter, r *http.Request) {
+			var label map[string]any
+			json.NewDecoder(r.Body).Decode(&label)
+			mu.Lock()
+			addedLabels = append(addedLabels, label["Name"].(string))
+			mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"UID": "photo1"}`))
+		},
+		"/api/v1/photos/photo1/label/7": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"UID": "photo1"}`))
+		},
+	})
+	defer server.Close()
+
+	pp := createPhotoPrismClient(t, server)
+	handler := createSortHandlerForTest(testConfig())
+
+	recorder := revertRequest(t, handler, "job1", pp)
+	assertStatusCode(t, recorder, http.StatusOK)
+
+	var resp RevertResponse
+	parseJSONResponse(t, recorder, &resp)
+	if resp.RevertedCount != 1 || len(resp.Errors) != 0 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if len(addedLabels) != 1 || addedLabels[0] != "Beach" {
+		t.Errorf("expected Beach label to be restored, got %v", addedLabels)
+	}
+	if edited["Title"] != "Old title" || edited["Description"] != "Old description" {
+		t.Errorf("unexpected photo update: %v", edited)
+	}
+
+	// A second revert finds the change already reverted.
+	recorder = revertRequest(t, handler, "job1", pp)
+	assertStatusCode(t, recorder, http.StatusOK)
+	parseJSONResponse(t, recorder, &resp)
+	if resp.RevertedCount != 0 || resp.AlreadyReverted != 1 {
+		t.Errorf("expected already reverted change, got %+v", resp)
+	}
+}
+
+func TestSortHandler_Revert_NoChanges(t *testing.T) {
+	setupMockSortUndoStore(t)
+	handler := createSortHandlerForTest(testConfig())
+
+	recorder := revertRequest(t, handler, "unknown", nil)
+	assertStatusCode(t, recorder, http.StatusNotFound)
+}
+
+func TestSortHandler_Revert_JobRunning(t *testing.T) {
+	setupMockSortUndoStore(t)
+	handler := createSortHandlerForTest(testConfig())
+	job := handler.jobManager.CreateJob("job1", "album1", "Album", SortJobOptions{})
+	job.mu.Lock()
+	job.Status = JobStatusRunning
+	job.mu.Unlock()
+
+	recorder := revertRequest(t, handler, "job1", nil)
+	assertStatusCode(t, recorder, http.StatusConflict)
+}
+
+func TestSortHandler_Revert_NoStore(t *testing.T) {
+	database.ResetForTesting()
+	handler := createSortHandlerForTest(testConfig())
+
+	recorder := revertRequest(t, handler, "job1", nil)
+	assertStatusCode(t, recorder, http.StatusInternalServerError)
+}
@@ -93,6 +93,39 @@ func TestSortHandler_Start_MissingAlbumUID(t *testing.T) {
 	assertJSONError(t, recorder, "album_uid is required")
 }
 
+func TestSortHandler_Start_InvalidConsensus(t *testing.T) {
+	handler := createSortHandlerForTest(testConfig())
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{
+			"single provider",
+			`{"album_uid": "album123", "consensus": ["openai", " openai "]}`,
+			"consensus needs at least two providers",
+		},
+		{
+			"batch mode",
+			`{"album_uid": "album123", "consensus": ["openai", "gemini"], "batch_mode": true}`,
+			"consensus cannot be combined with batch mode",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequestWithContext(context.Background(), "POST", "/api/v1/sort",
+				bytes.NewBufferString(tt.body))
+			recorder := httptest.NewRecorder()
+
+			handler.Start(recorder, req)
+
+			assertStatusCode(t, recorder, http.StatusBadRequest)
+			assertJSONError(t, recorder, tt.wantErr)
+		})
+	}
+}
+
 func TestSortHandler_Start_InvalidJSON(t *testing.T) {
 	handler := createSortHandlerForTest(testConfig())
 
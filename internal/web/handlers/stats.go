@@ -75,24 +75,17 @@ type StatsResponse struct {
 }
 
 // fetchAllPhotoUIDs fetches all photo UIDs from PhotoPrism with pagination.
-func fetchAllPhotoUIDs(pp *photoprism.PhotoPrism) []string {
-	var allPhotos []photoprism.Photo
-	pageSize := constants.DefaultPageSize
-	offset := 0
-	for {
-		photos, err := pp.GetPhotos(pageSize, offset, constants.DefaultPhotoQuality)
+// On error the UIDs fetched so far are returned.
+func fetchAllPhotoUIDs(ctx context.Context, pp *photoprism.PhotoPrism) []string {
+	var photoUIDs []string
+	opts := photoprism.IterateOptions{PageSize: constants.DefaultPageSize, Quality: constants.DefaultPhotoQuality}
+	for photos, err := range pp.IteratePhotos(ctx, "", opts) {
 		if err != nil {
 			break
 		}
-		allPhotos = append(allPhotos, photos...)
-		if len(photos) < pageSize {
-			break
+		for _, p := range photos {
+			photoUIDs = append(photoUIDs, p.UID)
 		}
-		offset += pageSize
-	}
-	photoUIDs := make([]string, len(allPhotos))
-	for i, p := range allPhotos {
-		photoUIDs[i] = p.UID
 	}
 	return photoUIDs
 }
@@ -130,7 +123,7 @@ func (h *StatsHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	photoUIDs := fetchAllPhotoUIDs(pp)
+	photoUIDs := fetchAllPhotoUIDs(r.Context(), pp)
 	photosWithEmbed, totalEmbeddings, photosWithFaces, totalFaces := fetchDBStats(context.Background(), photoUIDs)
 
 	photosProcessed := max(photosWithEmbed, photosWithFaces)
@@ -64,6 +64,27 @@ func (j *UploadJob) Cancel() {
 	j.mu.Unlock()
 }
 
+// markRunning transitions the job to running (implements persistentJob).
+func (j *UploadJob) markRunning() {
+	j.mu.Lock()
+	j.Status = JobStatusRunning
+	j.mu.Unlock()
+}
+
+// snapshot returns the job's persisted representation (implements persistentJob).
+func (j *UploadJob) snapshot() database.StoredJob {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	stored := newStoredJob(j.ID, database.JobTypeUpload, j.Status, j.Error, j.StartedAt, j.CompletedAt,
+		j.Options, j.Result)
+	stored.Total = j.Options.FileCount
+	if j.Result != nil {
+		stored.Processed = j.Result.Uploaded
+		stored.Progress = 100
+	}
+	return stored
+}
+
 // UploadJobManager manages upload jobs (one at a time).
 type UploadJobManager struct {
 	activeJob *UploadJob
@@ -107,25 +128,32 @@ func needsBeforeSnapshot(opts UploadJobOptions) bool {
 		opts.AutoProcess
 }
 
+// ResumeJob implements JobResumer. Uploaded files live in a temp directory
+// owned by the interrupted process, so upload jobs are never resumed.
+func (h *UploadHandler) ResumeJob(_ database.StoredJob) error {
+	return fmt.Errorf("upload files were not persisted: %w", errJobNotResumable)
+}
+
 // runUploadJob executes the upload job in the background.
 func (h *UploadHandler) runUploadJob(
 	job *UploadJob, session *middleware.Session, tempDir string,
 ) {
-	ctx, cancel := context.WithCancel(context.Background())
-	job.cancel = cancel
-	defer cancel()
 	defer os.RemoveAll(tempDir)
+	runPersistentJob(job, "Upload job started", func(ctx context.Context) {
+		h.executeUploadJob(ctx, job, session, tempDir)
+	})
+}
 
-	job.mu.Lock()
-	job.Status = JobStatusRunning
-	job.mu.Unlock()
-	job.SendEvent(JobEvent{Type: "started", Message: "Upload job started"})
-
+// executeUploadJob uploads the files and applies the post-upload actions.
+func (h *UploadHandler) executeUploadJob(
+	ctx context.Context, job *UploadJob, session *middleware.Session, tempDir string,
+) {
 	pp, err := getPhotoPrismClient(h.config, session)
 	if err != nil {
 		h.failUploadJob(job, "failed to connect to PhotoPrism: "+err.Error())
 		return
 	}
+	pp = pp.WithContext(ctx)
 
 	primaryAlbumUID := job.Options.AlbumUIDs[0]
 	beforeUIDs := h.snapshotBefore(ctx, job, pp, primaryAlbumUID)
@@ -371,29 +399,15 @@ func getAlbumPhotoUIDSet(
 	ctx context.Context,
 	pp *photoprism.PhotoPrism, albumUID string,
 ) (map[string]struct{}, error) {
-	var allPhotos []photoprism.Photo
-	pageSize := constants.DefaultPageSize
-	offset := 0
-	for {
-		if ctx.Err() != nil {
-			return nil, fmt.Errorf("cancelled: %w", ctx.Err())
-		}
-		photos, err := pp.GetAlbumPhotos(albumUID, pageSize, offset)
+	uids := make(map[string]struct{})
+	opts := photoprism.IterateOptions{PageSize: constants.DefaultPageSize}
+	for photos, err := range pp.IterateAlbumPhotos(ctx, albumUID, opts) {
 		if err != nil {
 			return nil, fmt.Errorf("fetching album photos: %w", err)
 		}
-		if len(photos) == 0 {
-			break
-		}
-		allPhotos = append(allPhotos, photos...)
-		if len(photos) < pageSize {
-			break
+		for _, p := range photos {
+			uids[p.UID] = struct{}{}
 		}
-		offset += len(photos)
-	}
-	uids := make(map[string]struct{}, len(allPhotos))
-	for _, p := range allPhotos {
-		uids[p.UID] = struct{}{}
 	}
 	return uids, nil
 }
@@ -30,7 +30,8 @@ func WithPhotoPrismClient(cfg *config.Config) func(http.Handler) http.Handler {
 				return
 			}
 
-			ctx := context.WithValue(r.Context(), photoPrismContextKey, pp)
+			// Bind the client to the request so a closed connection aborts its PhotoPrism calls.
+			ctx := context.WithValue(r.Context(), photoPrismContextKey, pp.WithContext(r.Context()))
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -28,8 +28,13 @@ func (s *Server) setupRoutes(sessionManager *middleware.SessionManager) {
 	uploadHandler := handlers.NewUploadHandler(s.config, sessionManager, processHandler)
 	booksHandler := handlers.NewBooksHandler(s.config, sessionManager)
 	s.booksHandler = booksHandler
+	s.sortHandler = sortHandler
+	s.processHandler = processHandler
+	s.uploadHandler = uploadHandler
 	textHandler := handlers.NewTextHandler(s.config)
 	textVersionsHandler := handlers.NewTextVersionsHandler()
+	jobsHandler := handlers.NewJobsHandler()
+	scheduleHandler := handlers.NewScheduleHandler(s.newScheduler(processHandler))
 
 	// Health check (no auth required).
 	s.router.Get("/api/v1/health", handlers.HealthCheck)
@@ -67,6 +72,7 @@ func (s *Server) setupRoutes(sessionManager *middleware.SessionManager) {
 				r.Get("/labels/{uid}", labelsHandler.Get)
 				r.Put("/labels/{uid}", labelsHandler.Update)
 				r.Delete("/labels", labelsHandler.BatchDelete)
+				r.Post("/labels/merge", labelsHandler.Merge)
 
 				// Photos.
 				r.Get("/photos", photosHandler.List)
@@ -107,13 +113,18 @@ func (s *Server) setupRoutes(sessionManager *middleware.SessionManager) {
 				r.Put("/subjects/{uid}", facesHandler.UpdateSubject)
 				r.Post("/faces/match", facesHandler.Match)
 				r.Post("/faces/apply", facesHandler.Apply)
+				r.Post("/faces/apply/batch", facesHandler.BatchApply)
 				r.Post("/faces/outliers", facesHandler.FindOutliers)
+				r.Post("/faces/clusters", facesHandler.ClusterFaces)
+				r.Post("/faces/confusion", facesHandler.FindConfusion)
 
-				// Process (start/cancel/rebuild/sync; progress stream is in the long group).
+				// Process (start/cancel/rebuild/sync/schedule; progress stream is in the long group).
 				r.Post("/process", processHandler.Start)
 				r.Delete("/process/{jobId}", processHandler.Cancel)
 				r.Post("/process/rebuild-index", processHandler.RebuildIndex)
 				r.Post("/process/sync-cache", processHandler.SyncCache)
+				r.Get("/process/schedule", scheduleHandler.Status)
+				r.Post("/process/schedule/run", scheduleHandler.Run)
 
 				// Fonts.
 				r.Get("/fonts", booksHandler.ListFonts)
@@ -146,6 +157,15 @@ func (s *Server) setupRoutes(sessionManager *middleware.SessionManager) {
 				r.Delete("/pages/{id}/slots/{index}", booksHandler.ClearSlot)
 				r.Post("/books/{id}/sections/{sectionId}/auto-layout", booksHandler.AutoLayout)
 				r.Get("/books/{id}/preflight", booksHandler.Preflight)
+				r.Get("/books/{id}/comments", booksHandler.ListBookComments)
+				r.Post("/books/{id}/comments", booksHandler.CreateBookComment)
+				r.Post("/comments/{id}/resolve", booksHandler.ResolveBookComment)
+				r.Post("/comments/{id}/unresolve", booksHandler.UnresolveBookComment)
+				r.Delete("/comments/{id}", booksHandler.DeleteBookComment)
+				r.Get("/page-layouts", booksHandler.ListPageLayouts)
+				r.Post("/page-layouts", booksHandler.CreatePageLayout)
+				r.Put("/page-layouts/{id}", booksHandler.UpdatePageLayout)
+				r.Delete("/page-layouts/{id}", booksHandler.DeletePageLayout)
 				r.Post("/books/{id}/export-pdf/job", booksHandler.StartExportJob)
 				r.Get("/book-export/{jobId}", booksHandler.GetExportJob)
 				r.Delete("/book-export/{jobId}", booksHandler.CancelExportJob)
@@ -160,6 +180,10 @@ func (s *Server) setupRoutes(sessionManager *middleware.SessionManager) {
 				// Text version history.
 				r.Get("/text-versions", textVersionsHandler.List)
 				r.Post("/text-versions/{id}/restore", textVersionsHandler.Restore)
+
+				// Job history.
+				r.Get("/jobs", jobsHandler.List)
+				r.Get("/jobs/{id}", jobsHandler.Get)
 			})
 
 			// --- Long-running / streaming endpoints ---
@@ -185,6 +209,10 @@ func (s *Server) setupRoutes(sessionManager *middleware.SessionManager) {
 				r.Get("/books/{id}/export-pdf", booksHandler.ExportPDF)
 				r.Get("/pages/{id}/export-pdf", booksHandler.ExportPagePDF)
 				r.Get("/book-export/{jobId}/download", booksHandler.DownloadExport)
+
+				// Sort revert restores photos one by one and can outlast
+				// the short-lived timeout on large albums.
+				r.Post("/sort/{jobId}/revert", sortHandler.Revert)
 			})
 		})
 	})
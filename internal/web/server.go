@@ -10,6 +10,8 @@ import (
 	"github.com/go-chi/chi/v5"
 	chiMiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/kozaktomas/photo-sorter/internal/config"
+	"github.com/kozaktomas/photo-sorter/internal/database"
+	"github.com/kozaktomas/photo-sorter/internal/scheduler"
 	"github.com/kozaktomas/photo-sorter/internal/web/handlers"
 	"github.com/kozaktomas/photo-sorter/internal/web/middleware"
 )
@@ -23,6 +25,10 @@ type Server struct {
 	sessionManager *middleware.SessionManager
 	mcpHandler     http.Handler // nil if MCP not enabled
 	booksHandler   *handlers.BooksHandler
+	sortHandler    *handlers.SortHandler
+	processHandler *handlers.ProcessHandler
+	uploadHandler  *handlers.UploadHandler
+	scheduler      *scheduler.Scheduler // nil if SYNC_SCHEDULE is not set
 }
 
 // NewServer creates a new web server.
@@ -80,8 +86,28 @@ func NewServer(
 	return s
 }
 
-// Start starts the HTTP server.
+// newScheduler creates the background sync scheduler, or returns nil if no
+// schedule is configured.
+func (s *Server) newScheduler(processHandler *handlers.ProcessHandler) *scheduler.Scheduler {
+	spec := s.config.Scheduler.Schedule
+	if spec == "" {
+		return nil
+	}
+	sched, err := scheduler.New(spec, processHandler.RunScheduledSync)
+	if err != nil {
+		log.Printf("Warning: invalid SYNC_SCHEDULE, scheduler disabled: %v", err)
+		return nil
+	}
+	s.scheduler = sched
+	return sched
+}
+
+// Start starts the background scheduler, if configured, and the HTTP server.
 func (s *Server) Start() error {
+	if s.scheduler != nil {
+		s.scheduler.Start(context.Background())
+		log.Printf("Scheduler enabled with schedule %q", s.config.Scheduler.Schedule)
+	}
 	log.Printf("Starting web server on %s", s.httpServer.Addr)
 	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		return fmt.Errorf("failed to start server: %w", err)
@@ -98,6 +124,11 @@ func (s *Server) Shutdown(ctx context.Context) error {
 		s.sessionManager.Stop()
 	}
 
+	// Cancel a running scheduled sync and wait for it to stop.
+	if s.scheduler != nil {
+		s.scheduler.Stop()
+	}
+
 	if err := s.httpServer.Shutdown(ctx); err != nil {
 		return fmt.Errorf("shutting down server: %w", err)
 	}
@@ -110,6 +141,21 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// ResumeJobs restarts async jobs that a previous server process left
+// pending or running. Returns the number of resumed jobs.
+func (s *Server) ResumeJobs(ctx context.Context) (int, error) {
+	n, err := handlers.ResumeInterruptedJobs(ctx, map[string]handlers.JobResumer{
+		database.JobTypeSort:       s.sortHandler,
+		database.JobTypeProcess:    s.processHandler,
+		database.JobTypeUpload:     s.uploadHandler,
+		database.JobTypeBookExport: s.booksHandler,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("resuming jobs: %w", err)
+	}
+	return n, nil
+}
+
 // Router returns the chi router for testing.
 func (s *Server) Router() *chi.Mux {
 	return s.router
@@ -2,6 +2,7 @@ import type {
   Album,
   Photo,
   Label,
+  LabelMergeResult,
   SortJob,
   Config,
   AuthStatus,
@@ -16,19 +17,30 @@ import type {
   ComputeFacesResponse,
   StatsResponse,
   OutlierResponse,
+  ClusterResponse,
+  ConfusionResponse,
+  FaceRef,
+  BatchApplyResponse,
   TextSearchResponse,
   RebuildIndexResponse,
   SyncCacheResponse,
+  ScheduleStatus,
   EraEstimateResponse,
   DuplicatesResponse,
   SuggestAlbumsResponse,
   PhotoBook,
   BookDetail,
+  BookOrientation,
+  BookPageSize,
   BookChapter,
   BookSection,
   SectionPhoto,
   BookPage,
   PageFormat,
+  PageLayout,
+  PageLayoutCell,
+  BookComment,
+  NewBookComment,
   PhotoBookMembership,
   PhotoAlbumMembership,
   PreflightResponse,
@@ -201,6 +213,17 @@ export async function deleteLabels(
   });
 }
 
+export async function mergeLabels(
+  from: string[],
+  into: string,
+  dryRun = false
+): Promise<LabelMergeResult> {
+  return request<LabelMergeResult>('/labels/merge', {
+    method: 'POST',
+    body: JSON.stringify({ from, into, dry_run: dryRun }),
+  });
+}
+
 // Photos
 export async function getPhotos(params?: {
   count?: number;
@@ -261,6 +284,8 @@ export async function startSort(params: {
   provider?: string;
   force_date?: boolean;
   concurrency?: number;
+  no_cache?: boolean;
+  consensus?: string[];
 }): Promise<{ job_id: string; album_uid: string; album_title: string }> {
   return request('/sort', {
     method: 'POST',
@@ -349,6 +374,7 @@ export async function matchFaces(params: {
   person_name: string;
   threshold?: number;
   limit?: number;
+  min_quality?: number;
 }): Promise<FaceMatchResult> {
   return request<FaceMatchResult>('/faces/match', {
     method: 'POST',
@@ -444,12 +470,51 @@ export async function findFaceOutliers(params: {
   });
 }
 
+// Cluster unassigned faces
+export async function clusterFaces(params: {
+  threshold?: number;
+  min_size?: number;
+  limit?: number;
+  representatives?: number;
+} = {}): Promise<ClusterResponse> {
+  return request<ClusterResponse>('/faces/clusters', {
+    method: 'POST',
+    body: JSON.stringify(params),
+  });
+}
+
+// Person-to-person confusion report
+export async function getFaceConfusion(params: {
+  min_faces?: number;
+  margin?: number;
+  limit?: number;
+  examples?: number;
+} = {}): Promise<ConfusionResponse> {
+  return request<ConfusionResponse>('/faces/confusion', {
+    method: 'POST',
+    body: JSON.stringify(params),
+  });
+}
+
+// Assign a person to many faces at once
+export async function batchApplyFaces(
+  personName: string,
+  faces: FaceRef[],
+  concurrency?: number
+): Promise<BatchApplyResponse> {
+  return request<BatchApplyResponse>('/faces/apply/batch', {
+    method: 'POST',
+    body: JSON.stringify({ person_name: personName, faces, concurrency }),
+  });
+}
+
 // Process (embeddings & face detection)
 export async function startProcess(params: {
   concurrency?: number;
   limit?: number;
   no_faces?: boolean;
   no_embeddings?: boolean;
+  incremental?: boolean;
 }): Promise<{ job_id: string }> {
   return request('/process', {
     method: 'POST',
@@ -473,9 +538,22 @@ export async function rebuildIndex(): Promise<RebuildIndexResponse> {
 }
 
 // Sync face cache from PhotoPrism
-export async function syncCache(): Promise<SyncCacheResponse> {
+export async function syncCache(incremental = false): Promise<SyncCacheResponse> {
   return request<SyncCacheResponse>('/process/sync-cache', {
     method: 'POST',
+    body: JSON.stringify({ incremental }),
+  });
+}
+
+// Scheduled sync status
+export async function getScheduleStatus(): Promise<ScheduleStatus> {
+  return request<ScheduleStatus>('/process/schedule');
+}
+
+// Start a scheduled sync run now
+export async function runSchedule(): Promise<{ status: string }> {
+  return request<{ status: string }>('/process/schedule/run', {
+    method: 'POST',
   });
 }
 
@@ -579,6 +657,9 @@ export async function updateBook(id: string, updates: {
   heading_color_bleed?: number;
   caption_badge_size?: number;
   body_text_pad_mm?: number;
+  page_size?: BookPageSize;
+  orientation?: BookOrientation;
+  bleed_mm?: number;
 }): Promise<void> {
   await request(`/books/${id}`, {
     method: 'PUT',
@@ -700,6 +781,52 @@ export async function reorderPages(bookId: string, pageIds: string[]): Promise<v
   });
 }
 
+export async function listPageLayouts(): Promise<PageLayout[]> {
+  return request<PageLayout[]>('/page-layouts');
+}
+
+export async function createPageLayout(layout: { id: string; name: string; description?: string; rows: number; cells: PageLayoutCell[] }): Promise<PageLayout> {
+  return request<PageLayout>('/page-layouts', {
+    method: 'POST',
+    body: JSON.stringify(layout),
+  });
+}
+
+export async function updatePageLayout(id: string, layout: { name: string; description?: string; rows: number; cells: PageLayoutCell[] }): Promise<PageLayout> {
+  return request<PageLayout>(`/page-layouts/${id}`, {
+    method: 'PUT',
+    body: JSON.stringify(layout),
+  });
+}
+
+export async function deletePageLayout(id: string): Promise<void> {
+  await request(`/page-layouts/${id}`, { method: 'DELETE' });
+}
+
+export async function listBookComments(bookId: string, unresolvedOnly = false): Promise<BookComment[]> {
+  const q = unresolvedOnly ? '?unresolved=true' : '';
+  return request<BookComment[]>(`/books/${bookId}/comments${q}`);
+}
+
+export async function createBookComment(bookId: string, comment: NewBookComment): Promise<BookComment> {
+  return request<BookComment>(`/books/${bookId}/comments`, {
+    method: 'POST',
+    body: JSON.stringify(comment),
+  });
+}
+
+export async function resolveBookComment(id: string): Promise<BookComment> {
+  return request<BookComment>(`/comments/${id}/resolve`, { method: 'POST' });
+}
+
+export async function unresolveBookComment(id: string): Promise<BookComment> {
+  return request<BookComment>(`/comments/${id}/unresolve`, { method: 'POST' });
+}
+
+export async function deleteBookComment(id: string): Promise<void> {
+  await request(`/comments/${id}`, { method: 'DELETE' });
+}
+
 export async function assignSlot(pageId: string, slotIndex: number, photoUid: string): Promise<void> {
   await request(`/pages/${pageId}/slots/${slotIndex}`, {
     method: 'PUT',
@@ -825,6 +952,18 @@ export async function preflightBook(
 // side at 8000 px.
 export type PhotoQuality = 'low' | 'medium' | 'original';
 
+// Book export output: the print PDF, or a zipped static HTML web book.
+export type BookExportOutput = 'pdf' | 'html';
+
+export interface BookExportOptions {
+  photoQuality?: PhotoQuality;
+  output?: BookExportOutput;
+  // Low-resolution review PDF with a diagonal watermark, page IDs and a
+  // comments page per section. Empty watermark = server default.
+  proof?: boolean;
+  watermark?: string;
+}
+
 // Text AI operations
 export interface TextSuggestion {
   severity: 'major' | 'minor';
@@ -970,10 +1109,15 @@ export interface BookExportJobConflict {
  */
 export async function startBookExportJob(
   bookId: string,
-  photoQuality?: PhotoQuality,
+  options: BookExportOptions = {},
 ): Promise<{ jobId: string; reattached: boolean }> {
-  const q = photoQuality ? `?photo_quality=${encodeURIComponent(photoQuality)}` : '';
-  const response = await fetch(`${API_BASE}/books/${bookId}/export-pdf/job${q}`, {
+  const searchParams = new URLSearchParams();
+  if (options.photoQuality) searchParams.set('photo_quality', options.photoQuality);
+  if (options.output) searchParams.set('output', options.output);
+  if (options.proof) searchParams.set('format', 'proof');
+  if (options.proof && options.watermark) searchParams.set('watermark', options.watermark);
+  const query = searchParams.toString();
+  const response = await fetch(`${API_BASE}/books/${bookId}/export-pdf/job${query ? `?${query}` : ''}`, {
     method: 'POST',
     credentials: 'include',
   });
@@ -983,7 +1127,7 @@ export async function startBookExportJob(
   }
   if (!response.ok) {
     const errorData = (await response.json().catch(() => ({}))) as Record<string, unknown>;
-    const errorMessage = typeof errorData.error === 'string' ? errorData.error : 'Failed to start PDF export';
+    const errorMessage = typeof errorData.error === 'string' ? errorData.error : 'Failed to start book export';
     throw new Error(errorMessage);
   }
   const started = (await response.json()) as BookExportJobStartResponse;
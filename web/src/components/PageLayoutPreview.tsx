@@ -2,13 +2,11 @@ import { useRef, useEffect, useState, useMemo, type ReactNode } from 'react';
 import { useTranslation } from 'react-i18next';
 import { MarkdownContent } from '../utils/markdown';
 import { getThumbnailUrl } from '../api/client';
-import { PAGE_DIMENSIONS } from '../constants/bookTypography';
+import { PAGE_DIMENSIONS, getPageGeometry } from '../constants/bookTypography';
 import { getSlotRects } from '../utils/pageFormats';
-import type { PageFormat, PageSlot } from '../types';
+import type { PageFormat, PageLayout, PageSlot } from '../types';
 
 const {
-  pageWidth: PAGE_WIDTH_MM,
-  pageHeight: PAGE_HEIGHT_MM,
   marginInside: MARGIN_INSIDE_MM,
   headerHeight: HEADER_MM,
   footerHeight: FOOTER_MM,
@@ -19,6 +17,7 @@ interface PageLayoutPreviewProps {
   activeSlotIndex: number;
   slots: PageSlot[];
   splitPosition: number | null;
+  layout?: PageLayout;
   liveText: string;
   previewWidth?: number;
 }
@@ -28,6 +27,7 @@ export function PageLayoutPreview({
   activeSlotIndex,
   slots,
   splitPosition,
+  layout,
   liveText,
   previewWidth = 300,
 }: PageLayoutPreviewProps) {
@@ -35,12 +35,13 @@ export function PageLayoutPreview({
   const textSlotRef = useRef<HTMLDivElement>(null);
   const [overflow, setOverflow] = useState(false);
 
-  const scale = previewWidth / PAGE_WIDTH_MM;
-  const pageHeight = PAGE_HEIGHT_MM * scale;
+  const page = getPageGeometry();
+  const scale = previewWidth / page.pageWidth;
+  const pageHeight = page.pageHeight * scale;
   const canvasLeft = MARGIN_INSIDE_MM * scale;
   const canvasTop = HEADER_MM * scale;
 
-  const slotRects = useMemo(() => getSlotRects(format, splitPosition), [format, splitPosition]);
+  const slotRects = useMemo(() => getSlotRects(format, splitPosition, layout), [format, splitPosition, layout]);
 
   // Detect overflow
   useEffect(() => {
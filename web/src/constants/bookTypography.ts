@@ -107,13 +107,15 @@ export const BOOK_TYPOGRAPHY = {
   },
 } as const;
 
-// Physical page dimensions in mm (shared with PageLayoutPreview)
+// Physical page dimensions in mm of the default page (shared with PageLayoutPreview)
 export const PAGE_DIMENSIONS = {
   pageWidth: 297,           // A4 landscape
   pageHeight: 210,
   bleed: 3,                 // mirrors BleedMM in internal/latex/formats.go
   marginInside: 20,
   marginOutside: 12,
+  marginTop: 10,
+  marginBottom: 16,
   headerHeight: 4,
   footerHeight: 8,
   canvasHeight: 172,
@@ -122,3 +124,43 @@ export const PAGE_DIMENSIONS = {
   rowGap: 4,
   halfCanvas: 84,           // (172 - 4) / 2
 } as const;
+
+// Page geometry of the book open in the editor. Margins and zone heights are
+// the same on every page size; the content width and canvas height take up
+// the rest of the page (mirrors LayoutConfigFor in internal/latex/formats.go).
+export interface PageGeometry {
+  pageWidth: number;
+  pageHeight: number;
+  bleed: number;
+  contentWidth: number;
+  canvasHeight: number;
+}
+
+function computePageGeometry(pageWidth: number, pageHeight: number, bleed: number): PageGeometry {
+  const d = PAGE_DIMENSIONS;
+  return {
+    pageWidth,
+    pageHeight,
+    bleed,
+    contentWidth: pageWidth - d.marginInside - d.marginOutside,
+    canvasHeight: pageHeight - d.marginTop - d.headerHeight - d.footerHeight - d.marginBottom,
+  };
+}
+
+let pageGeometryCache: PageGeometry = computePageGeometry(
+  PAGE_DIMENSIONS.pageWidth, PAGE_DIMENSIONS.pageHeight, PAGE_DIMENSIONS.bleed,
+);
+
+// setPageGeometry switches slot geometry, aspect ratios and DPI estimates to
+// the page setup of a book. Called by the BookEditor when the book loads.
+export function setPageGeometry(book: Pick<BookDetail, 'page_width_mm' | 'page_height_mm' | 'bleed_mm'>): void {
+  pageGeometryCache = computePageGeometry(
+    book.page_width_mm || PAGE_DIMENSIONS.pageWidth,
+    book.page_height_mm || PAGE_DIMENSIONS.pageHeight,
+    book.bleed_mm ?? PAGE_DIMENSIONS.bleed,
+  );
+}
+
+export function getPageGeometry(): PageGeometry {
+  return pageGeometryCache;
+}
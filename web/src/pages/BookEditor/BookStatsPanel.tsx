@@ -1,7 +1,7 @@
 import { useMemo } from 'react';
 import { useTranslation } from 'react-i18next';
 import type { BookDetail, SectionPhoto } from '../../types';
-import { pageFormatSlotCount } from '../../utils/pageFormats';
+import { pageSlotCount } from '../../utils/pageFormats';
 
 interface BookStatsPanelProps {
   book: BookDetail;
@@ -31,7 +31,7 @@ export function BookStatsPanel({ book, sectionPhotos }: BookStatsPanelProps) {
     const formatCounts: Record<string, number> = {};
 
     for (const page of pages) {
-      const slotCount = pageFormatSlotCount(page.format);
+      const slotCount = pageSlotCount(page);
       totalSlots += slotCount;
       formatCounts[page.format] = (formatCounts[page.format] || 0) + 1;
 
@@ -0,0 +1,255 @@
+import { useState, useEffect, useMemo, useCallback } from 'react';
+import { useTranslation } from 'react-i18next';
+import { Check, RotateCcw, Trash2, Loader2 } from 'lucide-react';
+import {
+  listBookComments,
+  createBookComment,
+  resolveBookComment,
+  unresolveBookComment,
+  deleteBookComment,
+} from '../../api/client';
+import type { BookComment, BookDetail } from '../../types';
+import { pageSlotCount } from '../../types';
+
+interface CommentsTabProps {
+  book: BookDetail;
+  onNavigateToPage: (pageId: string) => void;
+}
+
+export function CommentsTab({ book, onNavigateToPage }: CommentsTabProps) {
+  const { t } = useTranslation('pages');
+  const [comments, setComments] = useState<BookComment[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [showResolved, setShowResolved] = useState(false);
+  const [busyId, setBusyId] = useState<string | null>(null);
+  const [error, setError] = useState<string | null>(null);
+
+  // New comment form: '' targets the whole book.
+  const [body, setBody] = useState('');
+  const [pageId, setPageId] = useState('');
+  const [slot, setSlot] = useState('');
+  const [saving, setSaving] = useState(false);
+
+  const load = useCallback(async () => {
+    try {
+      setComments(await listBookComments(book.id));
+    } catch (e) {
+      console.error('Failed to load comments:', e);
+    } finally {
+      setLoading(false);
+    }
+  }, [book.id]);
+
+  useEffect(() => {
+    void load();
+  }, [load]);
+
+  const pageNumbers = useMemo(() => {
+    const map: Record<string, number> = {};
+    book.pages.forEach((p, i) => { map[p.id] = i + 1; });
+    return map;
+  }, [book.pages]);
+
+  const sectionNames = useMemo(() => {
+    const map: Record<string, string> = {};
+    for (const s of book.sections) {
+      map[s.id] = s.title;
+    }
+    return map;
+  }, [book.sections]);
+
+  const selectedPage = book.pages.find(p => p.id === pageId);
+  const slotCount = selectedPage ? pageSlotCount(selectedPage) : 0;
+
+  const visible = showResolved ? comments : comments.filter(c => !c.resolved);
+  const unresolvedCount = comments.filter(c => !c.resolved).length;
+
+  const describeTarget = (c: BookComment): string => {
+    const page = c.page_id ? pageNumbers[c.page_id] : undefined;
+    switch (c.target_type) {
+      case 'page':
+        return t('books.editor.comments.targetPage', { page });
+      case 'slot':
+        return t('books.editor.comments.targetSlot', { page, slot: (c.slot_index ?? 0) + 1 });
+      case 'section_photo':
+        return t('books.editor.comments.targetSectionPhoto', {
+          section: sectionNames[c.section_id ?? ''] ?? c.section_id,
+          photo: c.photo_uid,
+        });
+      default:
+        return t('books.editor.comments.targetBook');
+    }
+  };
+
+  const handleAdd = async () => {
+    if (!body.trim()) return;
+    setSaving(true);
+    setError(null);
+    try {
+      const comment = await createBookComment(book.id, {
+        target_type: !pageId ? 'book' : slot ? 'slot' : 'page',
+        page_id: pageId || undefined,
+        slot_index: slot ? Number(slot) : undefined,
+        body,
+      });
+      setComments(prev => [...prev, comment]);
+      setBody('');
+    } catch (e) {
+      setError(e instanceof Error ? e.message : String(e));
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const handleToggleResolved = async (c: BookComment) => {
+    setBusyId(c.id);
+    setError(null);
+    try {
+      const updated = c.resolved ? await unresolveBookComment(c.id) : await resolveBookComment(c.id);
+      setComments(prev => prev.map(x => (x.id === updated.id ? updated : x)));
+    } catch (e) {
+      setError(e instanceof Error ? e.message : String(e));
+    } finally {
+      setBusyId(null);
+    }
+  };
+
+  const handleDelete = async (c: BookComment) => {
+    setBusyId(c.id);
+    setError(null);
+    try {
+      await deleteBookComment(c.id);
+      setComments(prev => prev.filter(x => x.id !== c.id));
+    } catch (e) {
+      setError(e instanceof Error ? e.message : String(e));
+    } finally {
+      setBusyId(null);
+    }
+  };
+
+  if (loading) {
+    return (
+      <div className="flex items-center justify-center py-16 text-slate-400">
+        <Loader2 className="h-5 w-5 animate-spin mr-2" />
+        {t('books.editor.comments.loading')}
+      </div>
+    );
+  }
+
+  return (
+    <div className="max-w-3xl space-y-6">
+      <div className="bg-slate-800 border border-slate-700 rounded-lg p-4 space-y-3">
+        <textarea
+          value={body}
+          onChange={(e) => setBody(e.target.value)}
+          placeholder={t('books.editor.comments.placeholder')}
+          className="w-full px-3 py-2 bg-slate-900 border border-slate-600 rounded text-sm text-white resize-none focus:outline-none focus-visible:ring-1 focus-visible:ring-rose-500"
+          rows={3}
+        />
+        <div className="flex flex-wrap items-center gap-2">
+          <select
+            value={pageId}
+            onChange={(e) => { setPageId(e.target.value); setSlot(''); }}
+            className="px-3 py-1.5 bg-slate-900 border border-slate-600 rounded text-white text-sm focus:outline-none focus-visible:ring-1 focus-visible:ring-rose-500"
+          >
+            <option value="">{t('books.editor.comments.targetBook')}</option>
+            {book.pages.map((p, i) => (
+              <option key={p.id} value={p.id}>
+                {t('books.editor.comments.targetPage', { page: i + 1 })}
+              </option>
+            ))}
+          </select>
+          {slotCount > 1 && (
+            <select
+              value={slot}
+              onChange={(e) => setSlot(e.target.value)}
+              className="px-3 py-1.5 bg-slate-900 border border-slate-600 rounded text-white text-sm focus:outline-none focus-visible:ring-1 focus-visible:ring-rose-500"
+            >
+              <option value="">{t('books.editor.comments.wholePage')}</option>
+              {Array.from({ length: slotCount }, (_, i) => (
+                <option key={i} value={i}>
+                  {t('books.editor.comments.slot', { slot: i + 1 })}
+                </option>
+              ))}
+            </select>
+          )}
+          <button
+            onClick={() => void handleAdd()}
+            disabled={saving || !body.trim()}
+            className="ml-auto px-3 py-1.5 bg-rose-600 hover:bg-rose-700 text-white text-sm rounded disabled:opacity-50"
+          >
+            {saving ? <Loader2 className="h-4 w-4 animate-spin" /> : t('books.editor.comments.add')}
+          </button>
+        </div>
+        {error && <p className="text-sm text-red-400">{error}</p>}
+      </div>
+
+      <div className="flex items-center justify-between">
+        <p className="text-sm text-slate-400">
+          {t('books.editor.comments.unresolvedCount', { count: unresolvedCount })}
+        </p>
+        <label className="flex items-center gap-2 text-sm text-slate-400">
+          <input
+            type="checkbox"
+            checked={showResolved}
+            onChange={(e) => setShowResolved(e.target.checked)}
+            className="rounded border-slate-600 bg-slate-900"
+          />
+          {t('books.editor.comments.showResolved')}
+        </label>
+      </div>
+
+      {visible.length === 0 ? (
+        <div className="text-center py-12 text-slate-400">
+          {t('books.editor.comments.empty')}
+        </div>
+      ) : (
+        <div className="space-y-3">
+          {visible.map(c => (
+            <div
+              key={c.id}
+              className={`bg-slate-800 border border-slate-700 rounded-lg p-3 ${c.resolved ? 'opacity-60' : ''}`}
+            >
+              <div className="flex items-center gap-2 text-xs text-slate-400 mb-1.5">
+                {c.page_id ? (
+                  <button
+                    onClick={() => onNavigateToPage(c.page_id ?? '')}
+                    className="text-rose-400 hover:text-rose-300"
+                  >
+                    {describeTarget(c)}
+                  </button>
+                ) : (
+                  <span>{describeTarget(c)}</span>
+                )}
+                <span>·</span>
+                <span>{new Date(c.created_at).toLocaleString()}</span>
+                {c.resolved && (
+                  <span className="text-emerald-400">{t('books.editor.comments.resolved')}</span>
+                )}
+                <div className="ml-auto flex items-center gap-1">
+                  <button
+                    onClick={() => void handleToggleResolved(c)}
+                    disabled={busyId === c.id}
+                    className="p-1 text-slate-500 hover:text-emerald-400 disabled:opacity-40"
+                    title={c.resolved ? t('books.editor.comments.unresolve') : t('books.editor.comments.resolve')}
+                  >
+                    {c.resolved ? <RotateCcw className="h-3.5 w-3.5" /> : <Check className="h-3.5 w-3.5" />}
+                  </button>
+                  <button
+                    onClick={() => void handleDelete(c)}
+                    disabled={busyId === c.id}
+                    className="p-1 text-slate-500 hover:text-red-400 disabled:opacity-40"
+                    title={t('books.editor.comments.delete')}
+                  >
+                    <Trash2 className="h-3.5 w-3.5" />
+                  </button>
+                </div>
+              </div>
+              <p className="text-sm text-slate-200 whitespace-pre-wrap">{c.body}</p>
+            </div>
+          ))}
+        </div>
+      )}
+    </div>
+  );
+}
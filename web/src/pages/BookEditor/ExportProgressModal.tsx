@@ -35,6 +35,7 @@ export function ExportProgressModal({ state, onCancel, onDismiss }: Props) {
     'downloading_photos',
     'compiling_pass1',
     'compiling_pass2',
+    'rendering_html',
   ];
   const inGeneration = generationPhases.includes(state.phase);
 
@@ -44,6 +45,7 @@ export function ExportProgressModal({ state, onCancel, onDismiss }: Props) {
     }
     if (state.phase === 'compiling_pass1') return 50;
     if (state.phase === 'compiling_pass2') return 75;
+    if (state.phase === 'rendering_html') return 90;
     if (state.phase === 'starting' || state.phase === 'fetching_metadata') return 5;
     if (!inGeneration) return 100;
     return 0;
@@ -73,6 +75,8 @@ export function ExportProgressModal({ state, onCancel, onDismiss }: Props) {
         return t('books.editor.exportModal.compilingPass1');
       case 'compiling_pass2':
         return t('books.editor.exportModal.compilingPass2');
+      case 'rendering_html':
+        return t('books.editor.exportModal.renderingHtml');
       case 'downloading_file': {
         const dp = state.downloadProgress;
         if (!dp) return t('books.editor.exportModal.waiting');
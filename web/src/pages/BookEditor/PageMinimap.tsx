@@ -1,7 +1,8 @@
 import { useMemo } from 'react';
 import { useTranslation } from 'react-i18next';
 import { getThumbnailUrl } from '../../api/client';
-import { pageFormatSlotCount } from '../../types';
+import { pageSlotCount } from '../../types';
+import { getGridColumnStyle, getSlotGridStyle } from '../../utils/pageFormats';
 import type { BookDetail, BookPage } from '../../types';
 import { Type } from 'lucide-react';
 
@@ -37,6 +38,18 @@ function MiniLayout({ page }: { page: BookPage }) {
   const format = page.format;
   const gap = 'gap-[1px]';
 
+  if (page.layout) {
+    return (
+      <div className={`grid w-full h-full ${gap}`} style={getGridColumnStyle(format, null, page.layout)}>
+        {page.layout.cells.map((_, i) => (
+          <div key={i} className="overflow-hidden rounded-[1px]" style={getSlotGridStyle(page.layout, i)}>
+            <SlotMini {...getSlotData(page, i)} />
+          </div>
+        ))}
+      </div>
+    );
+  }
+
   switch (format) {
     case '4_landscape':
       return (
@@ -84,7 +97,7 @@ function MiniLayout({ page }: { page: BookPage }) {
 }
 
 function isPageComplete(page: BookPage): boolean {
-  const total = pageFormatSlotCount(page.format);
+  const total = pageSlotCount(page);
   const filled = page.slots.filter(s => s.photo_uid || s.text_content).length;
   return filled >= total;
 }
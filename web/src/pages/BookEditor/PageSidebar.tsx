@@ -11,7 +11,7 @@ import { CSS } from '@dnd-kit/utilities';
 import { createPage, deletePage, getThumbnailUrl, getPageExportPdfUrl } from '../../api/client';
 import { ConfirmDialog } from '../../components/ConfirmDialog';
 import type { BookChapter, BookPage, BookSection, PageFormat } from '../../types';
-import { pageFormatLabelKey, pageFormatSlotCount } from '../../types';
+import { pageFormatLabelKey, pageSlotCount } from '../../types';
 
 interface Props {
   bookId: string;
@@ -69,7 +69,7 @@ function SortablePageItem({ page, globalIndex, isSelected, onSelect, onDelete, i
   const isOver = over?.id === page.id;
   const style = { transform: CSS.Transform.toString(transform), transition };
   const filledSlots = page.slots.filter(s => s.photo_uid || s.text_content).length;
-  const totalSlots = pageFormatSlotCount(page.format);
+  const totalSlots = pageSlotCount(page);
   const isComplete = filledSlots === totalSlots && totalSlots > 0;
   const hasEmptySlots = filledSlots < totalSlots;
 
@@ -102,7 +102,7 @@ function SortablePageItem({ page, globalIndex, isSelected, onSelect, onDelete, i
       style={style}
       className={`flex items-center gap-1.5 p-1.5 rounded-md cursor-pointer transition-colors ${boxClass}`}
       onClick={onSelect}
-      title={`${t(pageFormatLabelKey(page.format))} · ${filledSlots}/${totalSlots}`}
+      title={`${page.layout?.name ?? t(pageFormatLabelKey(page.format))} · ${filledSlots}/${totalSlots}`}
     >
       <button {...attributes} {...listeners} className="text-slate-500 hover:text-slate-300 cursor-grab shrink-0">
         <GripVertical className="h-4 w-4" />
@@ -1,4 +1,5 @@
 import { useState, useEffect, useCallback } from 'react';
+import type { CSSProperties } from 'react';
 import { useDroppable, useDraggable } from '@dnd-kit/core';
 import { useTranslation } from 'react-i18next';
 import { X, Pencil, Type, Crop, MessageSquareText, ListTree } from 'lucide-react';
@@ -7,7 +8,7 @@ import { PhotoActionOverlay } from './PhotoActionOverlay';
 import { PhotoInfoOverlay } from './PhotoInfoOverlay';
 import { MarkdownContent } from '../../utils/markdown';
 import { computeEffectiveDpi } from '../../utils/pageFormats';
-import type { PageFormat } from '../../types';
+import type { PageFormat, PageLayout } from '../../types';
 
 interface Props {
   pageId: string;
@@ -21,6 +22,7 @@ interface Props {
   cropScale?: number;
   format?: PageFormat;
   splitPosition?: number | null;
+  layout?: PageLayout;
   onClear: () => void;
   onEditCrop?: () => void;
   description?: string;
@@ -36,9 +38,10 @@ interface Props {
   bleedRight?: boolean;
   textPaddingClass?: string;
   className?: string;
+  style?: CSSProperties;
 }
 
-export function PageSlotComponent({ pageId, slotIndex, photoUid, textContent, isCaptionsSlot, isContentsSlot, cropX, cropY, cropScale, format, splitPosition, onClear, onEditCrop, description, note, fileName, onEditDescription, onEditText, onAddText, onAddCaptions, onAddContents, chapterColor, bleedLeft, bleedRight, textPaddingClass, className }: Props) {
+export function PageSlotComponent({ pageId, slotIndex, photoUid, textContent, isCaptionsSlot, isContentsSlot, cropX, cropY, cropScale, format, splitPosition, layout, onClear, onEditCrop, description, note, fileName, onEditDescription, onEditText, onAddText, onAddCaptions, onAddContents, chapterColor, bleedLeft, bleedRight, textPaddingClass, className, style }: Props) {
   const { t } = useTranslation('pages');
   const [orientation, setOrientation] = useState<'L' | 'P' | null>(null);
   const [dpi, setDpi] = useState<number | null>(null);
@@ -70,11 +73,11 @@ export function PageSlotComponent({ pageId, slotIndex, photoUid, textContent, is
     let cancelled = false;
     getPhoto(photoUid).then(photo => {
       if (!cancelled && photo.width && photo.height) {
-        setDpi(computeEffectiveDpi(photo.width, photo.height, format, slotIndex, splitPosition));
+        setDpi(computeEffectiveDpi(photo.width, photo.height, format, slotIndex, splitPosition, layout));
       }
     }).catch(() => { /* ignore */ });
     return () => { cancelled = true; };
-  }, [photoUid, format, slotIndex, splitPosition]);
+  }, [photoUid, format, slotIndex, splitPosition, layout]);
 
   return (
     <div
@@ -85,6 +88,7 @@ export function PageSlotComponent({ pageId, slotIndex, photoUid, textContent, is
       } ${hasContent ? 'cursor-grab active:cursor-grabbing' : ''} ${
         isDragging ? 'opacity-30' : ''
       } ${className ?? ''}`}
+      style={style}
     >
       {photoUid ? (
         <div className="group relative w-full h-full">
@@ -2,7 +2,7 @@ import { useState, useCallback, useMemo } from 'react';
 import { useTranslation } from 'react-i18next';
 import { PageSlotComponent } from './PageSlot';
 import type { BookPage, SectionPhoto, PageFormat, PageStyle } from '../../types';
-import { pageFormatSlotCount, getGridClasses, getGridColumnStyle, getSlotClasses, getSlotPhotoUid, getSlotTextContent, getSlotCrop, getTextSlotPaddingClass, getSlotH1Bleed, isMultiColumn, defaultSplitPosition } from '../../utils/pageFormats';
+import { pageSlotCount, getGridClasses, getGridColumnStyle, getSlotClasses, getSlotGridStyle, getSlotPhotoUid, getSlotTextContent, getSlotCrop, getTextSlotPaddingClass, getSlotH1Bleed, isMultiColumn, defaultSplitPosition, pageAspectRatio } from '../../utils/pageFormats';
 
 const ALL_PAGE_FORMATS: PageFormat[] = ['4_landscape', '2l_1p', '1p_2l', '2_portrait', '1_fullscreen', '1_fullbleed'];
 
@@ -35,8 +35,8 @@ interface Props {
 
 export function PageTemplate({ page, onClearSlot, sectionPhotos, onEditDescription, onUpdatePageDescription, onChangeFormat, onChangeStyle, onEditText, onAddText, onAddCaptions, onAddContents, onEditCrop, onChangeSplitPosition, onChangeHidePageNumber, chapterColor }: Props) {
   const { t } = useTranslation('pages');
-  const slotCount = pageFormatSlotCount(page.format);
-  const gridClasses = getGridClasses(page.format);
+  const slotCount = pageSlotCount(page);
+  const gridClasses = getGridClasses(page.format, page.layout);
 
   // Build lookup from photo uid to section photo
   const photoLookup = useMemo(() => {
@@ -100,6 +100,7 @@ export function PageTemplate({ page, onClearSlot, sectionPhotos, onEditDescripti
                 onChange={(e) => onChangeFormat(e.target.value as PageFormat)}
                 className="px-2 py-1 bg-slate-900 border border-slate-600 rounded text-sm text-white focus:outline-none focus-visible:ring-1 focus-visible:ring-rose-500"
               >
+                {page.layout && <option value={page.format}>{page.layout.name}</option>}
                 {ALL_PAGE_FORMATS.map(f => (
                   <option key={f} value={f}>{t(`books.editor.format${formatKeyMap[f]}`)}</option>
                 ))}
@@ -119,7 +120,7 @@ export function PageTemplate({ page, onClearSlot, sectionPhotos, onEditDescripti
               </select>
             </div>
           )}
-          {onChangeSplitPosition && isMultiColumn(page.format) && (
+          {onChangeSplitPosition && !page.layout && isMultiColumn(page.format) && (
             <div className="flex items-center gap-2">
               <label className="text-xs text-slate-400">{t('books.editor.splitPosition')}</label>
               <input
@@ -159,7 +160,7 @@ export function PageTemplate({ page, onClearSlot, sectionPhotos, onEditDescripti
 
       <div
         className={`${gridClasses} gap-2 bg-slate-950 border border-slate-700 rounded-lg ${page.format === '1_fullbleed' ? 'p-0 overflow-hidden' : 'p-3'}`}
-        style={{ aspectRatio: '297/210', ...getGridColumnStyle(page.format, page.split_position) }}
+        style={{ aspectRatio: pageAspectRatio(), ...getGridColumnStyle(page.format, page.split_position, page.layout) }}
       >
         {Array.from({ length: slotCount }, (_, i) => {
           const uid = getSlotPhotoUid(page, i);
@@ -170,7 +171,7 @@ export function PageTemplate({ page, onClearSlot, sectionPhotos, onEditDescripti
           const { cropX, cropY, cropScale } = getSlotCrop(page, i);
           const sp = uid ? photoLookup.get(uid) : undefined;
           const slotFileName = slot?.file_name || sp?.file_name || '';
-          const bleed = getSlotH1Bleed(page.format, i);
+          const bleed = getSlotH1Bleed(page.format, i, page.layout);
           const isEmpty = !uid && !textContent && !isCaptions && !isContents;
           // Only offer "captions slot" / "contents slot" when no other slot on
           // this page is already marked as such (one of each kind per page).
@@ -190,6 +191,7 @@ export function PageTemplate({ page, onClearSlot, sectionPhotos, onEditDescripti
               cropScale={cropScale}
               format={page.format}
               splitPosition={page.split_position}
+              layout={page.layout}
               onClear={() => onClearSlot(i)}
               onEditCrop={uid && onEditCrop ? () => onEditCrop(i) : undefined}
               description={sp?.description ?? ''}
@@ -205,6 +207,7 @@ export function PageTemplate({ page, onClearSlot, sectionPhotos, onEditDescripti
               bleedRight={bleed.right}
               textPaddingClass={getTextSlotPaddingClass(page, i)}
               className={getSlotClasses(page.format, i)}
+              style={getSlotGridStyle(page.layout, i)}
             />
           );
         })}
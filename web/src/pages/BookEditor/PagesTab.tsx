@@ -15,8 +15,8 @@ import { PageMinimap } from './PageMinimap';
 import { PageTemplate } from './PageTemplate';
 import { UnassignedPool } from './UnassignedPool';
 import { PhotoDescriptionDialog } from './PhotoDescriptionDialog';
-import type { BookDetail, SectionPhoto, PageFormat, PageStyle, PageSlot, TextVersion } from '../../types';
-import { pageFormatSlotCount } from '../../types';
+import type { BookDetail, SectionPhoto, PageFormat, PageLayout, PageStyle, PageSlot, TextVersion } from '../../types';
+import { pageSlotCount } from '../../types';
 import { getSlotAspectRatio, getSlotRects } from '../../utils/pageFormats';
 import { PageLayoutPreview } from '../../components/PageLayoutPreview';
 import { BOOK_TYPOGRAPHY } from '../../constants/bookTypography';
@@ -81,9 +81,9 @@ function insertMarkdown(textarea: HTMLTextAreaElement, prefix: string, suffix: s
 // Inline text slot editing dialog with markdown toolbar and preview
 type TargetLength = 'much_shorter' | 'shorter' | 'longer' | 'much_longer';
 
-function TextSlotDialog({ text, pageId, slotIndex, pageFormat, pageSlots, splitPosition, chapterColor, onSave, onClose }: {
+function TextSlotDialog({ text, pageId, slotIndex, pageFormat, pageLayout, pageSlots, splitPosition, chapterColor, onSave, onClose }: {
   text: string; pageId: string; slotIndex: number;
-  pageFormat: PageFormat; pageSlots: PageSlot[];
+  pageFormat: PageFormat; pageLayout?: PageLayout; pageSlots: PageSlot[];
   splitPosition: number | null;
   chapterColor?: string;
   onSave: (text: string) => void; onClose: () => void;
@@ -117,9 +117,9 @@ function TextSlotDialog({ text, pageId, slotIndex, pageFormat, pageSlots, splitP
 
   // Compute slot dimensions in mm for WYSIWYG preview
   const slotRect = useMemo(() => {
-    const rects = getSlotRects(pageFormat, splitPosition);
+    const rects = getSlotRects(pageFormat, splitPosition, pageLayout);
     return rects[slotIndex] ?? rects[0];
-  }, [pageFormat, splitPosition, slotIndex]);
+  }, [pageFormat, splitPosition, pageLayout, slotIndex]);
 
   // Measure fill percentage from rendered WYSIWYG content
   useEffect(() => {
@@ -409,6 +409,7 @@ function TextSlotDialog({ text, pageId, slotIndex, pageFormat, pageSlots, splitP
                 activeSlotIndex={slotIndex}
                 slots={pageSlots}
                 splitPosition={splitPosition}
+                layout={pageLayout}
                 liveText={value}
                 previewWidth={200}
               />
@@ -566,9 +567,9 @@ function TextSlotDialog({ text, pageId, slotIndex, pageFormat, pageSlots, splitP
 }
 
 // Crop adjustment dialog with visual crop box overlay
-function CropDialog({ photoUid, cropX, cropY, cropScale: initialScale, format, slotIndex, splitPosition, onSave, onClose }: {
+function CropDialog({ photoUid, cropX, cropY, cropScale: initialScale, format, slotIndex, splitPosition, layout, onSave, onClose }: {
   photoUid: string; cropX: number; cropY: number; cropScale: number;
-  format: PageFormat; slotIndex: number; splitPosition?: number | null;
+  format: PageFormat; slotIndex: number; splitPosition?: number | null; layout?: PageLayout;
   onSave: (x: number, y: number, scale: number) => void; onClose: () => void;
 }) {
   const { t } = useTranslation('pages');
@@ -586,7 +587,7 @@ function CropDialog({ photoUid, cropX, cropY, cropScale: initialScale, format, s
     anchorTopLeftX: number; anchorTopY: number;
   } | null>(null);
 
-  const slotAR = getSlotAspectRatio(format, slotIndex, splitPosition);
+  const slotAR = getSlotAspectRatio(format, slotIndex, splitPosition, layout);
 
   // Measure container with ResizeObserver
   useEffect(() => {
@@ -921,7 +922,7 @@ export function PagesTab({ book, setBook, sectionPhotos, loadSectionPhotos, onRe
   const [isPhotoDrag, setIsPhotoDrag] = useState(false);
   const [editingPhoto, setEditingPhoto] = useState<{ sectionId: string; photoUid: string } | null>(null);
   const [editingTextSlot, setEditingTextSlot] = useState<{ slotIndex: number; text: string; pageId: string } | null>(null);
-  const [editingCrop, setEditingCrop] = useState<{ slotIndex: number; photoUid: string; cropX: number; cropY: number; cropScale: number; format: PageFormat; splitPosition?: number | null } | null>(null);
+  const [editingCrop, setEditingCrop] = useState<{ slotIndex: number; photoUid: string; cropX: number; cropY: number; cropScale: number; format: PageFormat; splitPosition?: number | null; layout?: PageLayout } | null>(null);
   const [minimapOpen, setMinimapOpen] = useState(() => {
     try { return localStorage.getItem(`book-minimap-${book.id}`) === 'true'; } catch { return false; }
   });
@@ -1136,7 +1137,7 @@ export function PagesTab({ book, setBook, sectionPhotos, loadSectionPhotos, onRe
       const targetPage = book.pages.find(p => p.id === targetPageId);
       if (!targetPage) return;
 
-      const totalSlots = pageFormatSlotCount(targetPage.format);
+      const totalSlots = pageSlotCount(targetPage);
       // Check if photo already on target page
       if (targetPage.slots.some(s => s.photo_uid === photoUid)) return;
       // Find first empty slot
@@ -1402,7 +1403,7 @@ export function PagesTab({ book, setBook, sectionPhotos, loadSectionPhotos, onRe
     if (!selectedPage) return;
     const slot = selectedPage.slots.find(s => s.slot_index === slotIndex);
     if (!slot?.photo_uid) return;
-    setEditingCrop({ slotIndex, photoUid: slot.photo_uid, cropX: slot.crop_x ?? 0.5, cropY: slot.crop_y ?? 0.5, cropScale: slot.crop_scale ?? 1.0, format: selectedPage.format, splitPosition: selectedPage.split_position });
+    setEditingCrop({ slotIndex, photoUid: slot.photo_uid, cropX: slot.crop_x ?? 0.5, cropY: slot.crop_y ?? 0.5, cropScale: slot.crop_scale ?? 1.0, format: selectedPage.format, splitPosition: selectedPage.split_position, layout: selectedPage.layout });
   }, [selectedPage]);
 
   const handleSaveCrop = useCallback(async (cropX: number, cropY: number, cropScale: number) => {
@@ -1564,6 +1565,7 @@ export function PagesTab({ book, setBook, sectionPhotos, loadSectionPhotos, onRe
               pageId={editingTextSlot.pageId}
               slotIndex={editingTextSlot.slotIndex}
               pageFormat={editPage?.format ?? '1_fullscreen'}
+              pageLayout={editPage?.layout}
               pageSlots={editPage?.slots ?? []}
               splitPosition={editPage?.split_position ?? null}
               chapterColor={editChapter?.color || undefined}
@@ -1582,6 +1584,7 @@ export function PagesTab({ book, setBook, sectionPhotos, loadSectionPhotos, onRe
             format={editingCrop.format}
             slotIndex={editingCrop.slotIndex}
             splitPosition={editingCrop.splitPosition}
+            layout={editingCrop.layout}
             onSave={handleSaveCrop}
             onClose={() => setEditingCrop(null)}
           />
@@ -72,6 +72,16 @@ function formatIssue(issue: PreflightIssue, t: (key: string, opts?: Record<strin
         photo: issue.photo_uid,
         longest: issue.longest_px,
       });
+    case 'unresolved_comment': {
+      const key = issue.page_number ? 'unresolvedPageComment'
+        : issue.section ? 'unresolvedSectionComment' : 'unresolvedComment';
+      const comment = issue.comment ?? '';
+      return t(`books.editor.preflight.${key}`, {
+        page: issue.page_number,
+        section: issue.section,
+        comment: comment.length > 80 ? `${comment.slice(0, 80)}…` : comment,
+      });
+    }
     default:
       return issue.type;
   }
@@ -1,11 +1,12 @@
 import { useState, useEffect, useMemo, useCallback } from 'react';
+import type { CSSProperties } from 'react';
 import { useTranslation } from 'react-i18next';
 import { BookOpen, FileText } from 'lucide-react';
 import { getThumbnailUrl } from '../../api/client';
 import { PhotoInfoOverlay } from './PhotoInfoOverlay';
 import { MarkdownContent } from '../../utils/markdown';
 import type { BookDetail, BookPage, SectionPhoto } from '../../types';
-import { pageFormatLabelKey, pageFormatSlotCount, getGridClasses, getGridColumnStyle, getSlotClasses, getSlotPhotoUid, getSlotTextContent, getSlotCrop, getTextSlotPaddingClass, getSlotH1Bleed } from '../../utils/pageFormats';
+import { pageFormatLabelKey, pageSlotCount, getGridClasses, getGridColumnStyle, getSlotClasses, getSlotGridStyle, getSlotPhotoUid, getSlotTextContent, getSlotCrop, getTextSlotPaddingClass, getSlotH1Bleed, pageAspectRatio } from '../../utils/pageFormats';
 
 interface Props {
   book: BookDetail;
@@ -14,7 +15,7 @@ interface Props {
   initialPageId?: string | null;
 }
 
-function PreviewPageSlot({ photoUid, textContent, description, note, cropX, cropY, cropScale, chapterColor, bleedLeft, bleedRight, textPaddingClass, className }: {
+function PreviewPageSlot({ photoUid, textContent, description, note, cropX, cropY, cropScale, chapterColor, bleedLeft, bleedRight, textPaddingClass, className, style }: {
   photoUid: string;
   textContent: string;
   description: string;
@@ -27,11 +28,12 @@ function PreviewPageSlot({ photoUid, textContent, description, note, cropX, crop
   bleedRight?: boolean;
   textPaddingClass?: string;
   className?: string;
+  style?: CSSProperties;
 }) {
   const [orientation, setOrientation] = useState<'L' | 'P' | null>(null);
 
   return (
-    <div className={`relative ${className ?? ''}`}>
+    <div className={`relative ${className ?? ''}`} style={style}>
       {photoUid ? (
         <div className="relative w-full h-full">
           <img
@@ -72,20 +74,20 @@ function PreviewPageContent({ page, photoInfo, chapterColor, className }: {
   chapterColor?: string;
   className?: string;
 }) {
-  const slotCount = pageFormatSlotCount(page.format);
-  const gridClasses = getGridClasses(page.format);
+  const slotCount = pageSlotCount(page);
+  const gridClasses = getGridClasses(page.format, page.layout);
 
   return (
     <div
       className={`${gridClasses} gap-2 bg-slate-950 border border-slate-700 rounded-lg p-3 ${className ?? ''}`}
-      style={{ aspectRatio: '297/210', ...getGridColumnStyle(page.format, page.split_position) }}
+      style={{ aspectRatio: pageAspectRatio(), ...getGridColumnStyle(page.format, page.split_position, page.layout) }}
     >
       {Array.from({ length: slotCount }, (_, i) => {
         const uid = getSlotPhotoUid(page, i);
         const textContent = getSlotTextContent(page, i);
         const { cropX, cropY, cropScale } = getSlotCrop(page, i);
         const info = uid ? photoInfo[uid] : undefined;
-        const bleed = getSlotH1Bleed(page.format, i);
+        const bleed = getSlotH1Bleed(page.format, i, page.layout);
         return (
           <PreviewPageSlot
             key={i}
@@ -101,6 +103,7 @@ function PreviewPageContent({ page, photoInfo, chapterColor, className }: {
             bleedRight={bleed.right}
             textPaddingClass={getTextSlotPaddingClass(page, i)}
             className={getSlotClasses(page.format, i)}
+            style={getSlotGridStyle(page.layout, i)}
           />
         );
       })}
@@ -123,7 +126,7 @@ function PreviewPage({ page, pageNumber, sectionTitle, photoInfo, chapterColor }
         <span className="text-sm font-medium text-slate-400">
           Page {pageNumber}
         </span>
-        <span className="text-xs text-slate-600">{t(pageFormatLabelKey(page.format))}</span>
+        <span className="text-xs text-slate-600">{page.layout?.name ?? t(pageFormatLabelKey(page.format))}</span>
         {sectionTitle && (
           <span className="text-xs text-rose-400/60">{sectionTitle}</span>
         )}
@@ -219,7 +222,7 @@ function SpreadView({ orderedPages, sectionMap, sectionColorMap, photoInfo }: {
                   <span className="text-sm font-medium text-slate-400">
                     Page {spread.left.number}
                   </span>
-                  <span className="text-xs text-slate-600">{t(pageFormatLabelKey(spread.left.page.format))}</span>
+                  <span className="text-xs text-slate-600">{spread.left.page.layout?.name ?? t(pageFormatLabelKey(spread.left.page.format))}</span>
                 </>
               )}
               {spread.left && spread.right && (
@@ -230,13 +233,13 @@ function SpreadView({ orderedPages, sectionMap, sectionColorMap, photoInfo }: {
                   <span className="text-sm font-medium text-slate-400">
                     Page {spread.right.number}
                   </span>
-                  <span className="text-xs text-slate-600">{t(pageFormatLabelKey(spread.right.page.format))}</span>
+                  <span className="text-xs text-slate-600">{spread.right.page.layout?.name ?? t(pageFormatLabelKey(spread.right.page.format))}</span>
                 </>
               )}
             </div>
             <div
               className="flex bg-slate-950 border border-slate-700 rounded-lg overflow-hidden"
-              style={{ aspectRatio: `${297 * 2}/210` }}
+              style={{ aspectRatio: pageAspectRatio(2) }}
             >
               {/* Left page (verso) */}
               {spread.left ? (
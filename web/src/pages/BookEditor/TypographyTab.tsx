@@ -1,8 +1,8 @@
 import { useState, useEffect, useRef, useCallback } from 'react';
 import { useTranslation } from 'react-i18next';
 import { getFonts, updateBook, updateChapter } from '../../api/client';
-import { BOOK_TYPOGRAPHY, PAGE_DIMENSIONS, setFontRegistry, getBookTypographyCSSVars } from '../../constants/bookTypography';
-import type { BookDetail, FontInfo } from '../../types';
+import { BOOK_TYPOGRAPHY, PAGE_DIMENSIONS, getPageGeometry, setFontRegistry, getBookTypographyCSSVars } from '../../constants/bookTypography';
+import type { BookDetail, BookOrientation, BookPageSize, FontInfo } from '../../types';
 import { loadFontByInfo } from '../../utils/fontLoader';
 import { MarkdownContent } from '../../utils/markdown';
 
@@ -453,34 +453,89 @@ function TextStylesSection() {
 
 // ── Page Dimensions Section ────────────────────────────────────
 
-function PageDimensionsSection() {
+const PAGE_SIZES: BookPageSize[] = ['a4', 'a5', 'a3', 'square_20', 'square_30'];
+
+function PageDimensionsSection({ book, onRefresh }: { book: BookDetail; onRefresh: () => void }) {
   const { t } = useTranslation('pages');
+  const debouncedSave = useDebouncedSave();
   const dim = PAGE_DIMENSIONS;
+  const page = getPageGeometry();
+  const isSquare = book.page_size.startsWith('square_');
+
+  const save = async (updates: { page_size?: BookPageSize; orientation?: BookOrientation; bleed_mm?: number }) => {
+    try {
+      await updateBook(book.id, updates);
+      onRefresh();
+    } catch { /* silent */ }
+  };
 
   const rows = [
-    { label: t('books.editor.typography.pageSize'), value: t('books.editor.typography.pageSizeValue') },
+    {
+      label: t('books.editor.typography.pageSize'),
+      value: t('books.editor.typography.pageSizeValue', { width: page.pageWidth, height: page.pageHeight }),
+    },
     {
       label: t('books.editor.typography.margins'),
       value: t('books.editor.typography.marginsValue', { inside: dim.marginInside, outside: dim.marginOutside }),
     },
     {
       label: t('books.editor.typography.topBottom'),
-      // Layout: margin_top(10) + header(4) + canvas(172) + footer(8) + margin_bottom(16) = 210
-      value: t('books.editor.typography.topBottomValue', { top: 10, bottom: 16 }),
+      // Layout: margin_top(10) + header(4) + canvas + footer(8) + margin_bottom(16) = page height
+      value: t('books.editor.typography.topBottomValue', { top: dim.marginTop, bottom: dim.marginBottom }),
     },
     {
       label: t('books.editor.typography.contentArea'),
-      value: t('books.editor.typography.contentAreaValue', { width: dim.contentWidth, height: dim.canvasHeight }),
+      value: t('books.editor.typography.contentAreaValue', { width: page.contentWidth, height: page.canvasHeight }),
     },
     {
       label: t('books.editor.typography.canvasZone'),
-      value: t('books.editor.typography.canvasZoneValue', { height: dim.canvasHeight }),
+      value: t('books.editor.typography.canvasZoneValue', { height: page.canvasHeight }),
+    },
+    {
+      label: t('books.editor.typography.bleed'),
+      value: t('books.editor.typography.bleedValue', {
+        bleed: page.bleed, width: page.pageWidth + 2 * page.bleed, height: page.pageHeight + 2 * page.bleed,
+      }),
     },
   ];
 
   return (
     <section>
       <h2 className="text-lg font-semibold text-white mb-4">{t('books.editor.typography.pageDimensionsTitle')}</h2>
+      <div className="grid grid-cols-3 gap-4 mb-4">
+        <div>
+          <label className="text-xs text-slate-400 mb-1 block">{t('books.editor.typography.pageSize')}</label>
+          <select
+            value={book.page_size}
+            onChange={(e) => void save({ page_size: e.target.value as BookPageSize })}
+            className="w-full px-2 py-1.5 bg-slate-900 border border-slate-600 rounded text-white text-sm focus:outline-none focus-visible:ring-1 focus-visible:ring-rose-500"
+          >
+            {PAGE_SIZES.map(size => (
+              <option key={size} value={size}>{t(`books.editor.typography.pageSizes.${size}`)}</option>
+            ))}
+          </select>
+        </div>
+        <div>
+          <label className="text-xs text-slate-400 mb-1 block">{t('books.editor.typography.orientation')}</label>
+          <select
+            value={book.orientation}
+            disabled={isSquare}
+            onChange={(e) => void save({ orientation: e.target.value as BookOrientation })}
+            className="w-full px-2 py-1.5 bg-slate-900 border border-slate-600 rounded text-white text-sm focus:outline-none focus-visible:ring-1 focus-visible:ring-rose-500 disabled:opacity-50"
+          >
+            <option value="landscape">{t('books.editor.typography.orientationLandscape')}</option>
+            <option value="portrait">{t('books.editor.typography.orientationPortrait')}</option>
+          </select>
+        </div>
+        <NumberInput
+          label={t('books.editor.typography.bleed')}
+          value={book.bleed_mm}
+          min={0} max={10} step={0.5}
+          suffix="mm"
+          onChange={(v) => debouncedSave(() => save({ bleed_mm: v }), 500)}
+        />
+      </div>
+      <p className="text-xs text-slate-500 mb-4">{t('books.editor.typography.pageSetupHint')}</p>
       <div className="bg-slate-800/50 rounded-lg border border-slate-700 overflow-hidden">
         <table className="w-full text-sm">
           <tbody>
@@ -505,7 +560,7 @@ export function TypographyTab({ book, onRefresh }: TypographyTabProps) {
       <TypographySettingsSection book={book} onRefresh={onRefresh} />
       <ChapterColorsSection book={book} onRefresh={onRefresh} />
       <TextStylesSection />
-      <PageDimensionsSection />
+      <PageDimensionsSection book={book} onRefresh={onRefresh} />
     </div>
   );
 }
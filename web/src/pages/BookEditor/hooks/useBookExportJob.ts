@@ -4,7 +4,7 @@ import {
   cancelBookExportJob,
   getBookExportJobEventsUrl,
   getBookExportJobDownloadUrl,
-  type PhotoQuality,
+  type BookExportOptions,
 } from '../../../api/client';
 import { useSSE } from '../../../hooks/useSSE';
 
@@ -15,6 +15,7 @@ export type ExportPhase =
   | 'downloading_photos'
   | 'compiling_pass1'
   | 'compiling_pass2'
+  | 'rendering_html'
   | 'downloading_file'
   | 'done'
   | 'error'
@@ -58,6 +59,7 @@ const KNOWN_PHASES: readonly ExportPhase[] = [
   'downloading_photos',
   'compiling_pass1',
   'compiling_pass2',
+  'rendering_html',
 ];
 
 function isKnownPhase(p: string): p is ExportPhase {
@@ -170,7 +172,8 @@ export function useBookExportJob() {
           downloadProgress: { bytesLoaded: loaded, bytesTotal: total ?? loaded },
         }));
 
-        const blob = new Blob(chunks as BlobPart[], { type: 'application/pdf' });
+        const type = res.headers.get('Content-Type') ?? 'application/pdf';
+        const blob = new Blob(chunks as BlobPart[], { type });
         triggerBlobDownload(blob, filename);
 
         setState(prev => ({ ...prev, phase: 'done' }));
@@ -250,11 +253,11 @@ export function useBookExportJob() {
 
   useSSE(sseUrl, { onMessage: handleSSEMessage });
 
-  const start = useCallback(async (bookId: string, photoQuality?: PhotoQuality) => {
+  const start = useCallback(async (bookId: string, options: BookExportOptions = {}) => {
     startedAtRef.current = Date.now();
     setState({ ...INITIAL_STATE, phase: 'starting', elapsedMs: 0 });
     try {
-      const { jobId } = await startBookExportJob(bookId, photoQuality);
+      const { jobId } = await startBookExportJob(bookId, options);
       setState(prev => ({ ...prev, jobId, phase: 'fetching_metadata' }));
     } catch (err) {
       setState(prev => ({
@@ -1,11 +1,11 @@
 import { useState, useCallback, useEffect, useMemo } from 'react';
 import { useParams, useNavigate, useSearchParams } from 'react-router-dom';
 import { useTranslation } from 'react-i18next';
-import { BookOpen, ArrowLeft, Pencil, Trash2, Check, X, Download, BarChart3 } from 'lucide-react';
+import { BookOpen, ArrowLeft, Pencil, Trash2, Check, X, Download, Globe, Stamp, BarChart3 } from 'lucide-react';
 import { updateBook, deleteBook, preflightBook, getFonts, type PhotoQuality } from '../../api/client';
 import type { PreflightResponse } from '../../types';
 import { LoadingState } from '../../components/LoadingState';
-import { setFontRegistry, getBookTypographyCSSVars } from '../../constants/bookTypography';
+import { setFontRegistry, setPageGeometry, getBookTypographyCSSVars } from '../../constants/bookTypography';
 import { loadFontByInfo } from '../../utils/fontLoader';
 import { ConfirmDialog } from '../../components/ConfirmDialog';
 import { useBookData } from './hooks/useBookData';
@@ -17,13 +17,14 @@ import { PreviewTab } from './PreviewTab';
 import { DuplicatesTab } from './DuplicatesTab';
 import { TextsTab } from './TextsTab';
 import { TypographyTab } from './TypographyTab';
+import { CommentsTab } from './CommentsTab';
 import { PreflightModal } from './PreflightModal';
 import { KeyboardShortcutsHelp } from './KeyboardShortcutsHelp';
 import { ExportProgressModal } from './ExportProgressModal';
 
-type Tab = 'sections' | 'pages' | 'preview' | 'duplicates' | 'texts' | 'typography';
+type Tab = 'sections' | 'pages' | 'preview' | 'duplicates' | 'texts' | 'typography' | 'comments';
 
-const VALID_TABS: Tab[] = ['sections', 'pages', 'preview', 'texts', 'typography', 'duplicates'];
+const VALID_TABS: Tab[] = ['sections', 'pages', 'preview', 'texts', 'typography', 'duplicates', 'comments'];
 
 function isValidTab(value: string | null): value is Tab {
   return value !== null && VALID_TABS.includes(value as Tab);
@@ -104,6 +105,11 @@ export function BookEditorPage() {
     }).catch(() => { /* ignore font loading errors */ });
   }, [book?.body_font, book?.heading_font]);
 
+  // Page setup for slot geometry, aspect ratios and DPI estimates. Set during
+  // render (not in an effect) so child components already lay out against
+  // the book's page size on the first render.
+  if (book) setPageGeometry(book);
+
   // CSS variables for typography inheritance by all child components
   const typographyVars = useMemo(
     () => (book ? getBookTypographyCSSVars(book) : {}),
@@ -205,7 +211,21 @@ export function BookEditorPage() {
     if (!book || exporting) return;
     setShowPreflight(false);
     setPreflightData(null);
-    await exportJob.start(book.id, quality);
+    await exportJob.start(book.id, { photoQuality: quality });
+  };
+
+  // The web book uses fixed web-sized images, so it skips the print
+  // preflight and the photo quality setting.
+  const handleExportWebBook = async () => {
+    if (!book || exporting) return;
+    await exportJob.start(book.id, { output: 'html' });
+  };
+
+  // Proofs always use low-resolution photos and the default watermark, so
+  // they skip the preflight too.
+  const handleExportProof = async () => {
+    if (!book || exporting) return;
+    await exportJob.start(book.id, { proof: true });
   };
 
   const handleGoToPage = (pageNumber: number) => {
@@ -246,6 +266,7 @@ export function BookEditorPage() {
     { key: 'texts', label: t('books.editor.textsTab') },
     { key: 'typography', label: t('books.editor.typographyTab') },
     { key: 'duplicates', label: t('books.editor.duplicatesTab') },
+    { key: 'comments', label: t('books.editor.commentsTab') },
   ];
 
   return (
@@ -303,6 +324,22 @@ export function BookEditorPage() {
                     >
                       <Download className={`h-4 w-4 ${exporting ? 'animate-pulse' : ''}`} />
                     </button>
+                    <button
+                      onClick={() => void handleExportWebBook()}
+                      disabled={exporting || !book.pages?.length}
+                      className="text-slate-400 hover:text-white p-1 transition-colors disabled:opacity-40 disabled:cursor-not-allowed"
+                      title={exporting ? t('books.editor.exporting') : t('books.editor.exportWebBook')}
+                    >
+                      <Globe className="h-4 w-4" />
+                    </button>
+                    <button
+                      onClick={() => void handleExportProof()}
+                      disabled={exporting || !book.pages?.length}
+                      className="text-slate-400 hover:text-white p-1 transition-colors disabled:opacity-40 disabled:cursor-not-allowed"
+                      title={exporting ? t('books.editor.exporting') : t('books.editor.exportProof')}
+                    >
+                      <Stamp className="h-4 w-4" />
+                    </button>
                     <button onClick={handleStartEdit} className="text-slate-400 hover:text-white p-1 transition-colors">
                       <Pencil className="h-4 w-4" />
                     </button>
@@ -385,6 +422,9 @@ export function BookEditorPage() {
                 onRefresh={refresh}
               />
             )}
+            {activeTab === 'comments' && (
+              <CommentsTab book={book} onNavigateToPage={handleNavigateToPage} />
+            )}
             </div>
           </>
         )}
@@ -482,6 +482,9 @@ export function ProcessPage() {
                 {syncResult.photos_deleted > 0 && (
                   <div>{t('pages:process.syncCache.photosDeleted', { count: syncResult.photos_deleted })}</div>
                 )}
+                {syncResult.errors > 0 && (
+                  <div className="text-amber-400">{t('pages:process.syncCache.errors', { count: syncResult.errors })}</div>
+                )}
                 <div>{t('pages:process.syncCache.duration', { ms: syncResult.duration_ms })}</div>
               </div>
             </Alert>
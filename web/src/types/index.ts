@@ -49,6 +49,16 @@ export interface Label {
   created_at: string;
 }
 
+export interface LabelMergeResult {
+  into: Label;
+  from: Label[];
+  photo_uids: string[];
+  updated: number;
+  labels_deleted: boolean;
+  dry_run: boolean;
+  errors?: string[];
+}
+
 export interface SortJob {
   id: string;
   album_uid: string;
@@ -72,11 +82,14 @@ export interface SortJobOptions {
   provider: string;
   force_date: boolean;
   concurrency: number;
+  no_cache?: boolean;
+  consensus?: string[];
 }
 
 export interface SortJobResult {
   processed_count: number;
   sorted_count: number;
+  cache_hits?: number;
   album_date?: string;
   date_reasoning?: string;
   errors?: string[];
@@ -89,6 +102,7 @@ export interface SortSuggestion {
   Labels: LabelSuggestion[];
   Description: string;
   EstimatedDate: string;
+  Consensus?: Consensus;
 }
 
 export interface LabelSuggestion {
@@ -96,6 +110,19 @@ export interface LabelSuggestion {
   confidence: number;
 }
 
+export interface Consensus {
+  votes: ProviderVote[];
+  disputed?: string[];
+  agreement: number;
+}
+
+export interface ProviderVote {
+  provider: string;
+  labels?: LabelSuggestion[];
+  description?: string;
+  error?: string;
+}
+
 export interface UsageInfo {
   input_tokens: number;
   output_tokens: number;
@@ -165,6 +192,7 @@ export interface FaceMatch {
   marker_uid?: string;
   marker_name?: string;
   iou?: number;
+  quality?: number;
 }
 
 export interface MatchSummary {
@@ -289,6 +317,7 @@ export interface OutlierResult {
   bbox_rel?: number[];
   file_uid?: string;
   marker_uid?: string;
+  quality?: number;
 }
 
 export interface OutlierResponse {
@@ -299,6 +328,91 @@ export interface OutlierResponse {
   missing_embeddings: OutlierResult[];
 }
 
+// Clustering of unassigned faces
+export interface ClusterFace {
+  photo_uid: string;
+  face_index: number;
+  distance: number;
+  bbox: number[];
+  bbox_rel?: number[];
+  file_uid?: string;
+  marker_uid?: string;
+  action: 'create_marker' | 'assign_person';
+}
+
+export interface FaceCluster {
+  id: number;
+  size: number;
+  photo_count: number;
+  representatives: ClusterFace[];
+  faces: ClusterFace[];
+}
+
+export interface ClusterResponse {
+  total_faces: number;
+  clustered_faces: number;
+  noise_faces: number;
+  threshold: number;
+  min_size: number;
+  clusters: FaceCluster[];
+}
+
+export interface ConfusionExample {
+  photo_uid: string;
+  face_index: number;
+  assigned_to: string;
+  confused_with: string;
+  own_distance: number;
+  other_distance: number;
+  bbox_rel?: number[];
+  file_uid?: string;
+  marker_uid?: string;
+}
+
+export interface ConfusionPair {
+  person_a: string;
+  person_b: string;
+  faces_a: number;
+  faces_b: number;
+  a_confused_as_b: number;
+  b_confused_as_a: number;
+  centroid_distance: number;
+  examples: ConfusionExample[];
+}
+
+export interface ConfusionResponse {
+  people: number;
+  faces: number;
+  confused_faces: number;
+  pairs: ConfusionPair[];
+}
+
+export interface FaceRef {
+  photo_uid: string;
+  face_index: number;
+}
+
+export interface BatchApplyResult {
+  photo_uid: string;
+  face_index: number;
+  action?: MatchAction;
+  marker_uid?: string;
+  success: boolean;
+  error?: string;
+  cache_error?: string;
+}
+
+export interface BatchApplyResponse {
+  person: string;
+  results: BatchApplyResult[];
+  summary: {
+    created_markers: number;
+    assigned_people: number;
+    already_done: number;
+    failed: number;
+  };
+}
+
 // Process job types
 export interface ProcessJob {
   id: string;
@@ -318,6 +432,7 @@ export interface ProcessJobOptions {
   limit: number;
   no_faces: boolean;
   no_embeddings: boolean;
+  incremental: boolean;
 }
 
 export interface ProcessJobResult {
@@ -400,10 +515,38 @@ export interface SyncCacheResponse {
   photos_scanned: number;
   faces_updated: number;
   photos_deleted: number;
+  errors: number;
+  incremental?: boolean;
   duration_ms: number;
   error?: string;
 }
 
+// Scheduled sync (SYNC_SCHEDULE) status
+export interface ScheduledSyncResult {
+  process_job_id?: string;
+  process?: ProcessJobResult;
+  process_skipped?: string;
+  sync?: SyncCacheResponse;
+  index?: RebuildIndexResponse;
+}
+
+export interface ScheduledRun {
+  started_at: string;
+  completed_at?: string;
+  status: 'running' | 'completed' | 'failed';
+  trigger: 'schedule' | 'manual';
+  error?: string;
+  result?: ScheduledSyncResult;
+}
+
+export interface ScheduleStatus {
+  enabled: boolean;
+  schedule: string;
+  running: boolean;
+  next_run?: string;
+  last_run?: ScheduledRun;
+}
+
 // Photo Book types
 export interface PhotoBook {
   id: string;
@@ -453,6 +596,23 @@ export interface BookPage {
   hide_page_number: boolean;
   sort_order: number;
   slots: PageSlot[];
+  layout?: PageLayout;
+}
+
+export interface PageLayoutCell {
+  col: number;
+  col_span: number;
+  row: number;
+  row_span: number;
+}
+
+export interface PageLayout {
+  id: string;
+  name: string;
+  description: string;
+  rows: number;
+  cells: PageLayoutCell[];
+  slot_count: number;
 }
 
 export interface PageSlot {
@@ -468,6 +628,10 @@ export interface PageSlot {
   file_name: string;
 }
 
+export type BookPageSize = 'a4' | 'a5' | 'a3' | 'square_20' | 'square_30';
+
+export type BookOrientation = 'landscape' | 'portrait';
+
 export interface BookDetail {
   id: string;
   title: string;
@@ -483,6 +647,11 @@ export interface BookDetail {
   heading_color_bleed: number;
   caption_badge_size: number;
   body_text_pad_mm: number;
+  page_size: BookPageSize;
+  orientation: BookOrientation;
+  bleed_mm: number;
+  page_width_mm: number;
+  page_height_mm: number;
   chapters: BookChapter[];
   sections: BookSection[];
   pages: BookPage[];
@@ -520,6 +689,35 @@ export interface TextVersion {
 
 export type PageFormat = '4_landscape' | '2l_1p' | '1p_2l' | '2_portrait' | '1_fullscreen' | '1_fullbleed';
 
+// Book review comments
+export type BookCommentTarget = 'book' | 'page' | 'slot' | 'section_photo';
+
+export interface BookComment {
+  id: string;
+  book_id: string;
+  target_type: BookCommentTarget;
+  page_id?: string;
+  slot_index?: number;
+  section_id?: string;
+  photo_uid?: string;
+  body: string;
+  author_uid: string;
+  resolved: boolean;
+  resolved_at?: string;
+  resolved_by?: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface NewBookComment {
+  target_type: BookCommentTarget;
+  page_id?: string;
+  slot_index?: number;
+  section_id?: string;
+  photo_uid?: string;
+  body: string;
+}
+
 // Upload job types
 export interface UploadJobResult {
   uploaded: number;
@@ -542,6 +740,10 @@ export interface PreflightIssue {
   // Longest-side (px) of the photo's original file, populated for
   // original_downgrade warnings.
   longest_px?: number;
+  // Populated for unresolved_comment warnings.
+  comment_id?: string;
+  target?: BookCommentTarget;
+  comment?: string;
 }
 
 export interface PreflightSummary {
@@ -559,4 +761,4 @@ export interface PreflightResponse {
   summary: PreflightSummary;
 }
 
-export { pageFormatSlotCount, pageFormatLabelKey } from '../utils/pageFormats';
+export { pageFormatSlotCount, pageSlotCount, pageFormatLabelKey } from '../utils/pageFormats';
@@ -1,6 +1,6 @@
 import type { CSSProperties } from 'react';
-import type { BookPage, PageFormat } from '../types';
-import { PAGE_DIMENSIONS } from '../constants/bookTypography';
+import type { BookPage, PageFormat, PageLayout, PageLayoutCell } from '../types';
+import { getPageGeometry } from '../constants/bookTypography';
 
 export function pageFormatSlotCount(format: PageFormat): number {
   switch (format) {
@@ -13,6 +13,11 @@ export function pageFormatSlotCount(format: PageFormat): number {
   }
 }
 
+/** Returns the number of slots of a page: those of its custom layout, or of its built-in format. */
+export function pageSlotCount(page: BookPage): number {
+  return page.layout?.slot_count ?? pageFormatSlotCount(page.format);
+}
+
 export function pageFormatLabelKey(format: PageFormat): string {
   switch (format) {
     case '4_landscape': return 'books.editor.formatShort4Landscape';
@@ -36,13 +41,21 @@ export function isMultiColumn(format: PageFormat): boolean {
   return format !== '1_fullscreen' && format !== '1_fullbleed';
 }
 
-export function getGridColumnStyle(format: PageFormat, splitPosition: number | null): CSSProperties {
+export function getGridColumnStyle(format: PageFormat, splitPosition: number | null, layout?: PageLayout): CSSProperties {
+  // Custom layouts place their cells on the 12 layout columns and equal rows.
+  if (layout) {
+    return {
+      gridTemplateColumns: `repeat(${GRID_COLUMNS}, minmax(0, 1fr))`,
+      gridTemplateRows: `repeat(${layout.rows}, minmax(0, 1fr))`,
+    };
+  }
   if (format === '1_fullscreen' || format === '1_fullbleed') return {};
   const split = splitPosition ?? defaultSplitPosition(format);
   return { gridTemplateColumns: `${split}fr ${1 - split}fr` };
 }
 
-export function getGridClasses(format: PageFormat): string {
+export function getGridClasses(format: PageFormat, layout?: PageLayout): string {
+  if (layout) return 'grid';
   switch (format) {
     case '4_landscape':
       return 'grid grid-rows-2';
@@ -71,6 +84,16 @@ export function getSlotClasses(format: PageFormat, slotIndex: number): string {
   return '';
 }
 
+// Places a slot of a custom layout on the grid set up by getGridColumnStyle.
+export function getSlotGridStyle(layout: PageLayout | undefined, slotIndex: number): CSSProperties | undefined {
+  const cell = layout?.cells[slotIndex];
+  if (!cell) return undefined;
+  return {
+    gridColumn: `${cell.col + 1} / span ${cell.col_span}`,
+    gridRow: `${cell.row + 1} / span ${cell.row_span}`,
+  };
+}
+
 export function getSlotPhotoUid(page: BookPage, slotIndex: number): string {
   const slot = page.slots.find(s => s.slot_index === slotIndex);
   return slot?.photo_uid || '';
@@ -89,6 +112,7 @@ export function getSlotCrop(page: BookPage, slotIndex: number): { cropX: number;
 // Returns extra padding class for text slots adjacent to photos in mixed layouts.
 // Mirrors applyTextSlotPadding in internal/latex/latex.go.
 export function getTextSlotPaddingClass(page: BookPage, slotIndex: number): string {
+  if (page.layout) return getLayoutTextSlotPaddingClass(page, page.layout, slotIndex);
   const format = page.format;
   if (format !== '1p_2l' && format !== '2l_1p') return '';
 
@@ -107,8 +131,31 @@ export function getTextSlotPaddingClass(page: BookPage, slotIndex: number): stri
   return slotIndex <= 1 ? 'pr-4' : 'pl-4';
 }
 
-// Returns H1 bleed direction for a text slot. Mirrors isSlotOnLeftEdge/isSlotOnRightEdge in latex.go.
-export function getSlotH1Bleed(format: string, slotIndex: number): { left: boolean; right: boolean } {
+// Custom layout variant of getTextSlotPaddingClass: pads the sides that face a
+// photo in a neighbouring cell. Mirrors PageLayout.LeftNeighbors/RightNeighbors.
+function getLayoutTextSlotPaddingClass(page: BookPage, layout: PageLayout, slotIndex: number): string {
+  const cell = layout.cells[slotIndex];
+  const slot = page.slots.find(s => s.slot_index === slotIndex);
+  if (!cell || !slot?.text_content) return '';
+
+  const photoNeighbor = (isNeighbor: (o: PageLayoutCell) => boolean) => layout.cells.some((o, i) =>
+    isNeighbor(o) && o.row < cell.row + cell.row_span && cell.row < o.row + o.row_span &&
+    page.slots.some(s => s.slot_index === i && s.photo_uid));
+  const classes: string[] = [];
+  if (cell.col > 0 && photoNeighbor(o => o.col + o.col_span === cell.col)) classes.push('pl-4');
+  if (cell.col + cell.col_span < GRID_COLUMNS && photoNeighbor(o => o.col === cell.col + cell.col_span)) {
+    classes.push('pr-4');
+  }
+  return classes.join(' ');
+}
+
+// Returns H1 bleed direction for a text slot. Mirrors isSlotOnLeftEdge/isSlotOnRightEdge in latex.go
+// and PageLayout.OnLeftEdge/OnRightEdge for custom layouts.
+export function getSlotH1Bleed(format: string, slotIndex: number, layout?: PageLayout): { left: boolean; right: boolean } {
+  if (layout) {
+    const cell = layout.cells[slotIndex];
+    return { left: cell?.col === 0, right: !!cell && cell.col + cell.col_span === GRID_COLUMNS };
+  }
   switch (format) {
     case '1_fullscreen':
       return { left: true, right: true };
@@ -128,27 +175,49 @@ export function getSlotH1Bleed(format: string, slotIndex: number): { left: boole
 }
 
 // Layout constants mirroring internal/latex/formats.go
-const CONTENT_WIDTH = 265; // 297 - 20 - 12
-const CANVAS_HEIGHT = 172;
 const COLUMN_GUTTER = 4;
 const ROW_GAP = 4;
 const GRID_COLUMNS = 12;
 
-// For 1_fullbleed, the photo covers the full A4 + 3mm bleed on every side,
-// not the safe canvas area. These two constants give the real print geometry
-// used by getSlotAspectRatio / getSlotDimensionsMm — callers that need the
-// preview safe-area geometry should use getSlotRects (which deliberately
-// stays on CONTENT_WIDTH × CANVAS_HEIGHT).
-const FULLBLEED_WIDTH = PAGE_DIMENSIONS.pageWidth + 2 * PAGE_DIMENSIONS.bleed;   // 303
-const FULLBLEED_HEIGHT = PAGE_DIMENSIONS.pageHeight + 2 * PAGE_DIMENSIONS.bleed; // 216
+interface GridGeometry {
+  contentWidth: number;
+  canvasHeight: number;
+  halfCanvasHeight: number;
+  colWidth: number;
+  // For 1_fullbleed, the photo covers the full page + bleed on every side,
+  // not the safe canvas area. These two give the real print geometry used by
+  // getSlotAspectRatio / getSlotDimensionsMm — callers that need the preview
+  // safe-area geometry should use getSlotRects (which deliberately stays on
+  // contentWidth × canvasHeight).
+  fullbleedWidth: number;
+  fullbleedHeight: number;
+}
 
-const COL_WIDTH = (CONTENT_WIDTH - (GRID_COLUMNS - 1) * COLUMN_GUTTER) / GRID_COLUMNS;
+// Grid geometry of the page of the book open in the editor (see
+// getPageGeometry). 265 × 172 mm canvas on the default A4 landscape page.
+function gridGeometry(): GridGeometry {
+  const page = getPageGeometry();
+  const contentWidth = page.contentWidth;
+  const canvasHeight = page.canvasHeight;
+  return {
+    contentWidth,
+    canvasHeight,
+    halfCanvasHeight: (canvasHeight - ROW_GAP) / 2,
+    colWidth: (contentWidth - (GRID_COLUMNS - 1) * COLUMN_GUTTER) / GRID_COLUMNS,
+    fullbleedWidth: page.pageWidth + 2 * page.bleed,   // 303 on A4 landscape
+    fullbleedHeight: page.pageHeight + 2 * page.bleed, // 216 on A4 landscape
+  };
+}
 
-function colSpanWidth(n: number): number {
-  return n * COL_WIDTH + (n - 1) * COLUMN_GUTTER;
+/** Returns the CSS aspect ratio of the trimmed page, or of a spread of `pages` pages side by side. */
+export function pageAspectRatio(pages = 1): string {
+  const page = getPageGeometry();
+  return `${page.pageWidth * pages}/${page.pageHeight}`;
 }
 
-const HALF_CANVAS_HEIGHT = (CANVAS_HEIGHT - ROW_GAP) / 2;
+function colSpanWidth(g: GridGeometry, n: number): number {
+  return n * g.colWidth + (n - 1) * COLUMN_GUTTER;
+}
 
 export interface SlotRect {
   x: number; // mm from content left
@@ -157,9 +226,23 @@ export interface SlotRect {
   h: number; // mm
 }
 
-/** Returns slot rectangles (position + size in mm) for all slots in a page format. */
-export function getSlotRects(format: PageFormat, splitPosition: number | null): SlotRect[] {
-  const availW = CONTENT_WIDTH - COLUMN_GUTTER;
+// Slot rectangles of a custom layout. Mirrors LayoutSlotsGrid in internal/latex/formats.go.
+function getLayoutSlotRects(g: GridGeometry, layout: PageLayout): SlotRect[] {
+  const rows = Math.max(layout.rows, 1);
+  const rowH = (g.canvasHeight - (rows - 1) * ROW_GAP) / rows;
+  return layout.cells.map(c => ({
+    x: c.col * (g.colWidth + COLUMN_GUTTER),
+    y: c.row * (rowH + ROW_GAP),
+    w: colSpanWidth(g, c.col_span),
+    h: c.row_span * rowH + (c.row_span - 1) * ROW_GAP,
+  }));
+}
+
+/** Returns slot rectangles (position + size in mm) for all slots in a page format or custom layout. */
+export function getSlotRects(format: PageFormat, splitPosition: number | null, layout?: PageLayout): SlotRect[] {
+  const g = gridGeometry();
+  if (layout) return getLayoutSlotRects(g, layout);
+  const availW = g.contentWidth - COLUMN_GUTTER;
   const sp = splitPosition ?? (format === '2l_1p' ? 2 / 3 : format === '1p_2l' ? 1 / 3 : 0.5);
   const leftW = availW * sp;
   const rightW = availW * (1 - sp);
@@ -167,82 +250,89 @@ export function getSlotRects(format: PageFormat, splitPosition: number | null):
 
   switch (format) {
     case '1_fullscreen':
-      return [{ x: 0, y: 0, w: CONTENT_WIDTH, h: CANVAS_HEIGHT }];
+      return [{ x: 0, y: 0, w: g.contentWidth, h: g.canvasHeight }];
     case '1_fullbleed':
       // Preview-only safe-area rect; the actual print geometry covers the
-      // full 303×216 bleed area — see FULLBLEED_WIDTH/HEIGHT used by
+      // full bleed area (303×216 on A4 landscape) — see fullbleedWidth/Height used by
       // getSlotAspectRatio and getSlotDimensionsMm. Keep the safe-area rect
       // here so PageLayoutPreview / text-slot editor don't overflow their
       // safe-area containers.
-      return [{ x: 0, y: 0, w: CONTENT_WIDTH, h: CANVAS_HEIGHT }];
+      return [{ x: 0, y: 0, w: g.contentWidth, h: g.canvasHeight }];
     case '2_portrait':
       return [
-        { x: 0, y: 0, w: leftW, h: CANVAS_HEIGHT },
-        { x: rightX, y: 0, w: rightW, h: CANVAS_HEIGHT },
+        { x: 0, y: 0, w: leftW, h: g.canvasHeight },
+        { x: rightX, y: 0, w: rightW, h: g.canvasHeight },
       ];
     case '4_landscape':
       return [
-        { x: 0, y: 0, w: leftW, h: HALF_CANVAS_HEIGHT },
-        { x: rightX, y: 0, w: rightW, h: HALF_CANVAS_HEIGHT },
-        { x: 0, y: HALF_CANVAS_HEIGHT + ROW_GAP, w: leftW, h: HALF_CANVAS_HEIGHT },
-        { x: rightX, y: HALF_CANVAS_HEIGHT + ROW_GAP, w: rightW, h: HALF_CANVAS_HEIGHT },
+        { x: 0, y: 0, w: leftW, h: g.halfCanvasHeight },
+        { x: rightX, y: 0, w: rightW, h: g.halfCanvasHeight },
+        { x: 0, y: g.halfCanvasHeight + ROW_GAP, w: leftW, h: g.halfCanvasHeight },
+        { x: rightX, y: g.halfCanvasHeight + ROW_GAP, w: rightW, h: g.halfCanvasHeight },
       ];
     case '2l_1p':
       return [
-        { x: 0, y: 0, w: leftW, h: HALF_CANVAS_HEIGHT },
-        { x: 0, y: HALF_CANVAS_HEIGHT + ROW_GAP, w: leftW, h: HALF_CANVAS_HEIGHT },
-        { x: rightX, y: 0, w: rightW, h: CANVAS_HEIGHT },
+        { x: 0, y: 0, w: leftW, h: g.halfCanvasHeight },
+        { x: 0, y: g.halfCanvasHeight + ROW_GAP, w: leftW, h: g.halfCanvasHeight },
+        { x: rightX, y: 0, w: rightW, h: g.canvasHeight },
       ];
     case '1p_2l':
       return [
-        { x: 0, y: 0, w: leftW, h: CANVAS_HEIGHT },
-        { x: rightX, y: 0, w: rightW, h: HALF_CANVAS_HEIGHT },
-        { x: rightX, y: HALF_CANVAS_HEIGHT + ROW_GAP, w: rightW, h: HALF_CANVAS_HEIGHT },
+        { x: 0, y: 0, w: leftW, h: g.canvasHeight },
+        { x: rightX, y: 0, w: rightW, h: g.halfCanvasHeight },
+        { x: rightX, y: g.halfCanvasHeight + ROW_GAP, w: rightW, h: g.halfCanvasHeight },
       ];
   }
 }
 
 /** Returns the physical slot dimensions [widthMm, heightMm] for a given slot in a page format. */
-function getSlotDimensionsMm(format: PageFormat, slotIndex: number, splitPosition?: number | null): [number, number] {
+function getSlotDimensionsMm(
+  format: PageFormat, slotIndex: number, splitPosition?: number | null, layout?: PageLayout,
+): [number, number] {
+  const g = gridGeometry();
+  if (layout) {
+    const rect = getLayoutSlotRects(g, layout)[slotIndex] ?? { w: g.contentWidth, h: g.canvasHeight };
+    return [rect.w, rect.h];
+  }
   if (splitPosition != null && format !== '1_fullscreen' && format !== '1_fullbleed') {
-    const availW = CONTENT_WIDTH - COLUMN_GUTTER;
+    const availW = g.contentWidth - COLUMN_GUTTER;
     const leftW = availW * splitPosition;
     const rightW = availW * (1 - splitPosition);
 
     switch (format) {
       case '2_portrait':
-        return [slotIndex === 0 ? leftW : rightW, CANVAS_HEIGHT];
+        return [slotIndex === 0 ? leftW : rightW, g.canvasHeight];
       case '4_landscape':
-        return [slotIndex % 2 === 0 ? leftW : rightW, HALF_CANVAS_HEIGHT];
+        return [slotIndex % 2 === 0 ? leftW : rightW, g.halfCanvasHeight];
       case '2l_1p':
         return slotIndex < 2
-          ? [leftW, HALF_CANVAS_HEIGHT]
-          : [rightW, CANVAS_HEIGHT];
+          ? [leftW, g.halfCanvasHeight]
+          : [rightW, g.canvasHeight];
       case '1p_2l':
         return slotIndex === 0
-          ? [leftW, CANVAS_HEIGHT]
-          : [rightW, HALF_CANVAS_HEIGHT];
+          ? [leftW, g.canvasHeight]
+          : [rightW, g.halfCanvasHeight];
     }
   }
 
-  const halfW = colSpanWidth(6);
+  const halfW = colSpanWidth(g, 6);
   switch (format) {
     case '1_fullscreen':
-      return [CONTENT_WIDTH, CANVAS_HEIGHT];
+      return [g.contentWidth, g.canvasHeight];
     case '1_fullbleed':
-      return [FULLBLEED_WIDTH, FULLBLEED_HEIGHT];
+      return [g.fullbleedWidth, g.fullbleedHeight];
     case '2_portrait':
-      return [halfW, CANVAS_HEIGHT];
+      return [halfW, g.canvasHeight];
     case '4_landscape':
-      return [halfW, HALF_CANVAS_HEIGHT];
+      return [halfW, g.halfCanvasHeight];
     case '2l_1p':
       return slotIndex < 2
-        ? [colSpanWidth(8), HALF_CANVAS_HEIGHT]
-        : [colSpanWidth(4), CANVAS_HEIGHT];
+        ? [colSpanWidth(g, 8), g.halfCanvasHeight]
+        : [colSpanWidth(g, 4), g.canvasHeight];
     case '1p_2l':
       return slotIndex === 0
-        ? [colSpanWidth(4), CANVAS_HEIGHT]
-        : [colSpanWidth(8), HALF_CANVAS_HEIGHT];
+        ? [colSpanWidth(g, 4), g.canvasHeight]
+        : [colSpanWidth(g, 8), g.halfCanvasHeight];
   }
 }
 
@@ -251,56 +341,64 @@ export function computeEffectiveDpi(
   naturalW: number, naturalH: number,
   format: PageFormat, slotIndex: number,
   splitPosition?: number | null,
+  layout?: PageLayout,
 ): number {
-  const [slotW, slotH] = getSlotDimensionsMm(format, slotIndex, splitPosition);
+  const [slotW, slotH] = getSlotDimensionsMm(format, slotIndex, splitPosition, layout);
   const dpiW = (naturalW / slotW) * 25.4;
   const dpiH = (naturalH / slotH) * 25.4;
   return Math.round(Math.min(dpiW, dpiH));
 }
 
-/** Returns the W/H aspect ratio for a given slot in a page format. */
-export function getSlotAspectRatio(format: PageFormat, slotIndex: number, splitPosition?: number | null): number {
+/** Returns the W/H aspect ratio for a given slot in a page format or custom layout. */
+export function getSlotAspectRatio(
+  format: PageFormat, slotIndex: number, splitPosition?: number | null, layout?: PageLayout,
+): number {
+  const g = gridGeometry();
+  if (layout) {
+    const [w, h] = getSlotDimensionsMm(format, slotIndex, null, layout);
+    return w / h;
+  }
   // With custom split position
   if (splitPosition != null && format !== '1_fullscreen' && format !== '1_fullbleed') {
-    const availW = CONTENT_WIDTH - COLUMN_GUTTER;
+    const availW = g.contentWidth - COLUMN_GUTTER;
     const leftW = availW * splitPosition;
     const rightW = availW * (1 - splitPosition);
 
     switch (format) {
       case '2_portrait':
-        return (slotIndex === 0 ? leftW : rightW) / CANVAS_HEIGHT;
+        return (slotIndex === 0 ? leftW : rightW) / g.canvasHeight;
       case '4_landscape':
-        return (slotIndex % 2 === 0 ? leftW : rightW) / HALF_CANVAS_HEIGHT;
+        return (slotIndex % 2 === 0 ? leftW : rightW) / g.halfCanvasHeight;
       case '2l_1p':
         return slotIndex < 2
-          ? leftW / HALF_CANVAS_HEIGHT
-          : rightW / CANVAS_HEIGHT;
+          ? leftW / g.halfCanvasHeight
+          : rightW / g.canvasHeight;
       case '1p_2l':
         return slotIndex === 0
-          ? leftW / CANVAS_HEIGHT
-          : rightW / HALF_CANVAS_HEIGHT;
+          ? leftW / g.canvasHeight
+          : rightW / g.halfCanvasHeight;
     }
   }
 
   // Default grid-based dimensions (no custom split)
-  const halfW = colSpanWidth(6);
+  const halfW = colSpanWidth(g, 6);
 
   switch (format) {
     case '1_fullscreen':
-      return CONTENT_WIDTH / CANVAS_HEIGHT;
+      return g.contentWidth / g.canvasHeight;
     case '1_fullbleed':
-      return FULLBLEED_WIDTH / FULLBLEED_HEIGHT;
+      return g.fullbleedWidth / g.fullbleedHeight;
     case '2_portrait':
-      return halfW / CANVAS_HEIGHT;
+      return halfW / g.canvasHeight;
     case '4_landscape':
-      return halfW / HALF_CANVAS_HEIGHT;
+      return halfW / g.halfCanvasHeight;
     case '2l_1p':
       return slotIndex < 2
-        ? colSpanWidth(8) / HALF_CANVAS_HEIGHT
-        : colSpanWidth(4) / CANVAS_HEIGHT;
+        ? colSpanWidth(g, 8) / g.halfCanvasHeight
+        : colSpanWidth(g, 4) / g.canvasHeight;
     case '1p_2l':
       return slotIndex === 0
-        ? colSpanWidth(4) / CANVAS_HEIGHT
-        : colSpanWidth(8) / HALF_CANVAS_HEIGHT;
+        ? colSpanWidth(g, 4) / g.canvasHeight
+        : colSpanWidth(g, 8) / g.halfCanvasHeight;
   }
 }